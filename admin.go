@@ -0,0 +1,114 @@
+package otelagent
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+)
+
+// adminTimeout bounds a single admin operation.
+const adminTimeout = 30 * time.Second
+
+// AdminHandler returns operator endpoints for a wedged or draining pod:
+//
+//	POST /flush      export all buffered telemetry now (ForceFlush)
+//	POST /reconnect  replace the exporters with fresh connections (Reconnect)
+//
+// Every request must send "Authorization: Bearer <token>". With an empty
+// token all requests are rejected, so the endpoints are never open by
+// accident. Mount it on an internal admin listener, not the public router:
+//
+//	go http.ListenAndServe(":9464", http.StripPrefix("/otel", agent.AdminHandler(token)))
+//
+// The read-only probes enabled in Features are served without a token, so
+// kubelet can reach them on the same listener:
+//
+//	GET /health  HealthCheck, 503 when unhealthy (Features.HealthChecks)
+//	GET /ready   ReadinessCheck (Features.ReadinessProbes)
+//	GET /live    LivenessCheck (Features.LivenessProbes)
+func (a *Agent) AdminHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /flush", a.adminAction("flushed", a.ForceFlush))
+	mux.HandleFunc("POST /reconnect", a.adminAction("reconnected", a.Reconnect))
+
+	probes := http.NewServeMux()
+	if a.config.Features.HealthChecks {
+		probes.HandleFunc("GET /health", a.healthProbe)
+	}
+	if a.config.Features.ReadinessProbes {
+		probes.HandleFunc("GET /ready", adminProbe("ready", a.ReadinessCheck))
+	}
+	if a.config.Features.LivenessProbes {
+		probes.HandleFunc("GET /live", adminProbe("live", a.LivenessCheck))
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h, pattern := probes.Handler(r); pattern != "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if !validAdminToken(r, token) {
+			writeAdminJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid or missing admin token"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (a *Agent) adminAction(done string, action func(context.Context) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), adminTimeout)
+		defer cancel()
+
+		if err := action(ctx); err != nil {
+			a.logger.Error(ctx, "Admin operation failed", logger.Fields{
+				"path": r.URL.Path, "error": err.Error(),
+			})
+			writeAdminJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		a.logger.Info(ctx, "Admin operation completed", logger.Fields{"path": r.URL.Path})
+		writeAdminJSON(w, http.StatusOK, map[string]string{"status": done})
+	}
+}
+
+// healthProbe answers HealthCheck, with 503 when unhealthy.
+func (a *Agent) healthProbe(w http.ResponseWriter, _ *http.Request) {
+	status := a.HealthCheck()
+	code := http.StatusOK
+	if status.Status == "unhealthy" {
+		code = http.StatusServiceUnavailable
+	}
+	writeAdminJSON(w, code, status)
+}
+
+// adminProbe answers {"<name>": true} with 200, or false with 503.
+func adminProbe(name string, check func() bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ok := check()
+		code := http.StatusOK
+		if !ok {
+			code = http.StatusServiceUnavailable
+		}
+		writeAdminJSON(w, code, map[string]bool{name: ok})
+	}
+}
+
+func validAdminToken(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+func writeAdminJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
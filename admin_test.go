@@ -0,0 +1,105 @@
+package otelagent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestAdminHandler(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+	agent := NewAgent(
+		WithServiceName("admin-test"),
+		WithStdoutExporter(),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+	)
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	do := func(h http.Handler, method, path, auth string) int {
+		req := httptest.NewRequest(method, path, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	h := agent.AdminHandler("s3cret")
+	for _, tc := range []struct {
+		method, path, auth string
+		want               int
+	}{
+		{"POST", "/flush", "", http.StatusUnauthorized},
+		{"POST", "/flush", "Bearer wrong", http.StatusUnauthorized},
+		{"GET", "/flush", "Bearer s3cret", http.StatusMethodNotAllowed},
+		{"POST", "/flush", "Bearer s3cret", http.StatusOK},
+		{"POST", "/reconnect", "Bearer s3cret", http.StatusOK},
+	} {
+		if got := do(h, tc.method, tc.path, tc.auth); got != tc.want {
+			t.Errorf("%s %s (%q) = %d, want %d", tc.method, tc.path, tc.auth, got, tc.want)
+		}
+	}
+	if got := do(agent.AdminHandler(""), "POST", "/flush", "Bearer "); got != http.StatusUnauthorized {
+		t.Errorf("empty token = %d, want every request rejected", got)
+	}
+
+	// Spans keep flowing through the new exporter after a reconnect.
+	_, span := agent.GetTracer("test").Start(context.Background(), "after-reconnect")
+	span.End()
+	if err := agent.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if got := agent.Diagnostics().Exports["traces"].TotalExported; got != 1 {
+		t.Errorf("exported %d spans after reconnect, want 1", got)
+	}
+}
+
+func TestAdminHandler_Probes(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+	agent := NewAgent(
+		WithServiceName("admin-probes"),
+		WithStdoutExporter(),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+		WithHealthProbes(false, true, true),
+	)
+	h := agent.AdminHandler("s3cret")
+	probe := func(path string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	if got := probe("/live"); got != http.StatusServiceUnavailable {
+		t.Errorf("/live before Init = %d, want 503", got)
+	}
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	// Probes need no token; the disabled /health falls through to the
+	// authenticated mux.
+	for path, want := range map[string]int{
+		"/ready":  http.StatusOK,
+		"/live":   http.StatusOK,
+		"/health": http.StatusUnauthorized,
+	} {
+		if got := probe(path); got != want {
+			t.Errorf("GET %s = %d, want %d", path, got, want)
+		}
+	}
+
+	probes := agent.Diagnostics().HealthProbes
+	if probes.ExporterHealth || !probes.Readiness || !probes.Liveness {
+		t.Errorf("Diagnostics().HealthProbes = %+v", probes)
+	}
+	if status := agent.HealthCheck(); status.Status != "ok" || status.Signals != nil {
+		t.Errorf("HealthCheck with exporter health off = %+v", status)
+	}
+}
@@ -2,33 +2,40 @@ package otelagent
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"sync"
-	"time"
 
 	"github.com/RodolfoBonis/go-otel-agent/collector"
+	"github.com/RodolfoBonis/go-otel-agent/config"
 	"github.com/RodolfoBonis/go-otel-agent/helper"
 	"github.com/RodolfoBonis/go-otel-agent/instrumentor"
 	"github.com/RodolfoBonis/go-otel-agent/internal/matcher"
+	"github.com/RodolfoBonis/go-otel-agent/internal/workerpool"
 	"github.com/RodolfoBonis/go-otel-agent/logger"
 	"github.com/RodolfoBonis/go-otel-agent/provider"
+	"github.com/RodolfoBonis/go-otel-agent/scrub"
 	"go.opentelemetry.io/otel"
 	otellog "go.opentelemetry.io/otel/log"
 	logglobal "go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/metric"
-	"go.opentelemetry.io/otel/propagation"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 	nooptrace "go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
 )
 
+// agentScopeName is the instrumentation scope of the agent's self-metrics.
+const agentScopeName = "github.com/RodolfoBonis/go-otel-agent"
+
 // Signal represents a telemetry signal type.
 type Signal int
 
 const (
-	SignalTraces  Signal = iota
+	SignalTraces Signal = iota
 	SignalMetrics
 	SignalLogs
 )
@@ -38,14 +45,57 @@ const (
 //
 // Create with NewAgent(opts...), then call Init(ctx) to start.
 type Agent struct {
-	config *Config
-	logger logger.Logger
+	config       *Config
+	logger       logger.Logger
+	errorHandler otel.ErrorHandler
+	// restoreErrorHandling puts back the global error handler and SDK
+	// logger replaced by Init
+	restoreErrorHandling func()
+
+	// Extra dial options for the gRPC exporters, set by WithGRPCDialOptions
+	grpcDialOptions []grpc.DialOption
+
+	// Resource additions set by WithResourceAttributes / WithResourceDetectors
+	resourceOptions []provider.ResourceOption
+
+	// Metric exporter selectors set by WithMetricTemporality / WithMetricAggregation
+	metricTemporality sdkmetric.TemporalitySelector
+	metricAggregation sdkmetric.AggregationSelector
+
+	// Config file set by WithConfigFile and the error loading it, if any
+	configFile string
+	configErr  error
+
+	// provenance records where each configuration value came from
+	provenance config.Provenance
+
+	// Endpoint selection: set by WithEndpoint / WithRegion / WithEndpointRegistry
+	endpointExplicit bool
+	resolveEndpoint  bool
 
 	// Providers (SDK types, unexported)
 	tracerProvider *sdktrace.TracerProvider
 	meterProvider  *sdkmetric.MeterProvider
 	loggerProvider *sdklog.LoggerProvider
 
+	// tracing hands out tracers: tracerProvider, wrapped to anchor
+	// timestamps to the monotonic clock when Traces.MonotonicTimestamps is
+	// on, to sample repeated span events when Traces.RepeatedEventsEvery is
+	// set and to fingerprint errors when Features.ErrorTracking is on
+	tracing trace.TracerProvider
+
+	// Root sampler when Performance.AdaptiveSampling is enabled
+	adaptiveSampler *provider.AdaptiveSampler
+
+	// Scrub.SensitivePatterns that failed to compile, reported by Diagnostics
+	scrubPatternErrors []error
+
+	// Drops telemetry of blocked subjects when Blocklist.Enabled
+	blocklist *provider.Blocklist
+
+	// Last finished spans when Features.DebugMode is on
+	recentSpans *provider.RecentSpans
+
 	// Cached tracers/meters
 	tracers sync.Map // name -> trace.Tracer
 	meters  sync.Map // name -> metric.Meter
@@ -53,13 +103,30 @@ type Agent struct {
 	// Components
 	instrumentor *instrumentor.Instrumentor
 	collector    *collector.MetricCollector
+	system       *collector.SystemCollector
 	routeMatcher *matcher.RouteMatcher
+	metricRoutes *matcher.RouteMatcher
 	health       *provider.ExporterHealth
+	exports      *provider.ExportStats
+	self         *provider.SelfTelemetry // nil unless Features.SelfTelemetry
+	workers      *workerpool.Pool
+	budget       *provider.ResourceBudget // nil unless Features.ResourceBudget
+	budgetStop   chan struct{}
+
+	// WithShutdownOnSignal and WithFlushOnSignal install a signal hook at
+	// Init; signalStop removes it
+	signalHook  signalHookMode
+	signalStop  func()
+	reconnector *provider.Reconnector
+
+	// Connection pools registered via RegisterDBStats, possibly before Init
+	dbStats map[string]func() sql.DBStats
 
 	// State
 	mu          sync.RWMutex
 	initialized bool
 	running     bool
+	shutDown    bool // Shutdown has started; later calls return at once
 }
 
 // NewAgent creates a new Agent with the given options.
@@ -68,24 +135,71 @@ func NewAgent(opts ...Option) *Agent {
 	cfg := LoadConfigFromEnv()
 
 	a := &Agent{
-		config: cfg,
-		health: provider.NewExporterHealth(),
+		config:  cfg,
+		health:  provider.NewExporterHealth(),
+		exports: provider.NewExportStats(),
+
+		reconnector: provider.NewReconnector(),
 	}
 
+	// Provenance is tracked by diffing the config around each step, so
+	// options that compute values need no bookkeeping of their own.
+	a.provenance = config.EnvProvenance()
+
+	// The file sits between env and options, so find it first (on a scratch
+	// agent) and merge it; the options then run once, on top of both,
+	// regardless of where WithConfigFile was passed.
+	scratch := &Agent{config: config.Defaults()}
+	for _, opt := range opts {
+		opt(scratch)
+	}
+	if a.configFile = scratch.configFile; a.configFile != "" {
+		snapshot := config.TakeSnapshot(a.config)
+		var keys []string
+		if keys, a.configErr = config.MergeFile(a.config, a.configFile); a.configErr == nil {
+			a.provenance.Set(config.SourceFile, snapshot.Changed(config.TakeSnapshot(a.config))...)
+			a.provenance.Set(config.SourceFile, keys...)
+		}
+	}
+
+	snapshot := config.TakeSnapshot(a.config)
 	for _, opt := range opts {
 		opt(a)
 	}
+	a.provenance.Set(config.SourceOption, snapshot.Changed(config.TakeSnapshot(a.config))...)
+
+	// Region/registry options pick the endpoint unless one was set
+	// explicitly: by WithEndpoint, or to anything but the default by env, the
+	// file or WithConfig
+	if a.resolveEndpoint && !a.endpointExplicit && a.provenance["endpoint"] == config.SourceDefault {
+		if endpoint, ok := a.config.RegistryEndpoint(); ok {
+			a.config.Endpoint = endpoint
+			a.provenance.Set(config.SourceOption, "endpoint")
+		}
+	}
 
 	// Create logger if not provided
 	if a.logger == nil {
 		a.logger = logger.NewLogger(cfg.Environment)
 	}
+	if len(a.config.Logs.CustomFields) > 0 {
+		fields := make(logger.Fields, len(a.config.Logs.CustomFields))
+		for k, v := range a.config.Logs.CustomFields {
+			fields[k] = v
+		}
+		a.logger = a.logger.With(fields)
+	}
 
 	// Build route matcher from config + options
 	a.routeMatcher = matcher.NewRouteMatcher(matcher.RouteExclusionConfig{
-		ExactPaths:  cfg.RouteExclusion.ExactPaths,
-		PrefixPaths: cfg.RouteExclusion.PrefixPaths,
-		Patterns:    cfg.RouteExclusion.Patterns,
+		ExactPaths:  a.config.RouteExclusion.ExactPaths,
+		PrefixPaths: a.config.RouteExclusion.PrefixPaths,
+		Patterns:    a.config.RouteExclusion.Patterns,
+	})
+	a.metricRoutes = matcher.NewRouteMatcher(matcher.RouteExclusionConfig{
+		ExactPaths:  a.config.MetricRouteExclusion.ExactPaths,
+		PrefixPaths: a.config.MetricRouteExclusion.PrefixPaths,
+		Patterns:    a.config.MetricRouteExclusion.Patterns,
 	})
 
 	return a
@@ -100,8 +214,17 @@ func (a *Agent) Init(ctx context.Context) error {
 		return ErrAlreadyInitialized
 	}
 
+	if a.configErr != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, a.configErr)
+	}
+
+	// Size queues and intervals before anything is built from them
+	a.autoTune(ctx)
+
 	if !a.config.Enabled {
 		a.logger.Info(ctx, "Observability disabled by configuration")
+		// Async work submitted by the application still runs
+		a.workers = workerpool.New(a.config.Performance.WorkerPoolSize, a.config.Performance.QueueBufferSize)
 		a.initialized = true
 		return nil
 	}
@@ -110,37 +233,153 @@ func (a *Agent) Init(ctx context.Context) error {
 		return ErrMissingServiceName
 	}
 
+	// Fail fast on compression the exporters cannot honor (e.g. "snappy")
+	if a.config.ExporterProtocol != provider.ProtocolStdout {
+		if _, err := provider.NormalizeCompression(a.config.ExporterProtocol, a.config.Compression); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+	}
+
+	// Route internal SDK errors and log messages through the agent logger
+	// instead of stderr
+	if a.errorHandler == nil {
+		a.errorHandler = provider.NewLoggerErrorHandler(a.logger, 0)
+	}
+	a.restoreErrorHandling = provider.SetGlobalErrorHandling(a.errorHandler, provider.NewSDKLogger(a.logger, a.config.Logs.SDKVerbosity))
+
+	// Invalid scrub patterns are skipped by every scrubber, leaving the data
+	// they were meant to cover unredacted; make that loud.
+	scrubber := scrub.New(a.config.Scrub)
+	a.scrubPatternErrors = scrubber.InvalidPatterns()
+	for _, err := range a.scrubPatternErrors {
+		a.logger.Warning(ctx, "PII scrub pattern skipped; matching data will not be redacted", logger.Fields{"error": err.Error()})
+	}
+
+	if a.config.Blocklist.Enabled {
+		a.blocklist = provider.NewBlocklist(a.config.Blocklist)
+	}
+
+	if a.config.Features.SelfTelemetry {
+		// The global meter forwards to the agent's MeterProvider once it is
+		// set below.
+		self, err := provider.NewSelfTelemetry(otel.Meter(agentScopeName))
+		if err != nil {
+			return fmt.Errorf("failed to create self-telemetry: %w", err)
+		}
+		a.self = self
+		a.self.TrackBlocklist(a.blocklist)
+	}
+
+	if a.config.Features.ResourceBudget {
+		a.budget = provider.NewResourceBudget(a.config.Performance, a.logger)
+	}
+
 	// Build resource
-	res, err := provider.BuildResource(a.config)
+	res, err := provider.BuildResource(a.config, a.resourceOptions...)
 	if err != nil {
-		return fmt.Errorf("failed to build resource: %w", err)
+		if res == nil {
+			return fmt.Errorf("failed to build resource: %w", err)
+		}
+		a.logger.Warning(ctx, "Resource detection incomplete", logger.Fields{"error": err.Error()})
 	}
 
 	// Initialize trace provider
 	if a.config.Traces.Enabled {
-		a.tracerProvider, err = provider.NewTraceProvider(a.config, res, a.logger)
+		traceOpts := []provider.TraceProviderOption{
+			provider.WithTraceExportStats(a.exports),
+			provider.WithTraceSelfTelemetry(a.self),
+			provider.WithTraceBudget(a.budget),
+			provider.WithTraceReconnector(a.reconnector),
+			provider.WithTraceGRPCDialOptions(a.grpcDialOptions...),
+		}
+		if a.config.Features.HealthChecks {
+			traceOpts = append(traceOpts, provider.WithTraceExporterHealth(a.health))
+		}
+		if a.blocklist != nil {
+			traceOpts = append(traceOpts, provider.WithBlocklist(a.blocklist))
+		}
+		if a.config.Features.DebugMode && a.config.Traces.RecentSpans > 0 {
+			a.recentSpans = provider.NewRecentSpans(a.config.Traces.RecentSpans)
+			traceOpts = append(traceOpts, provider.WithRecentSpans(a.recentSpans))
+		}
+		if a.config.Traces.MinimalSpans {
+			// The global meter forwards to the agent's MeterProvider once it
+			// is set below.
+			traceOpts = append(traceOpts, provider.WithMinimalSpans(otel.Meter(agentScopeName)))
+		}
+		if a.config.Performance.AdaptiveSampling && a.config.Traces.Sampling.RatioBased() {
+			a.adaptiveSampler = provider.NewAdaptiveSampler(a.config.Traces.Sampling, a.config.Performance)
+			traceOpts = append(traceOpts, provider.WithAdaptiveSampler(a.adaptiveSampler))
+		}
+
+		a.tracerProvider, err = provider.NewTraceProvider(a.config, res, a.logger, traceOpts...)
 		if err != nil {
 			return fmt.Errorf("failed to create trace provider: %w", err)
 		}
-		otel.SetTracerProvider(a.tracerProvider)
-		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-			propagation.TraceContext{},
-			propagation.Baggage{},
-		))
+		a.tracing = a.tracerProvider
+		if a.config.Traces.MonotonicTimestamps {
+			a.tracing = provider.NewMonotonicTracerProvider(a.tracing, a.config.Traces.ClockSkewTolerance)
+		}
+		a.tracing = provider.NewEventSamplingTracerProvider(a.tracing, a.config.Traces.RepeatedEventsEvery)
+		if a.config.Features.ErrorTracking {
+			// The global meter forwards to the agent's MeterProvider once it
+			// is set below.
+			a.tracing = provider.NewErrorTrackingTracerProvider(a.tracing, otel.Meter(agentScopeName))
+		}
+		otel.SetTracerProvider(a.tracing)
+		propagator, err := provider.NewPropagator(a.config.Propagators)
+		if err != nil {
+			return fmt.Errorf("failed to create propagator: %w", err)
+		}
+		otel.SetTextMapPropagator(propagator)
 	}
 
 	// Initialize metric provider
 	if a.config.Metrics.Enabled {
-		a.meterProvider, err = provider.NewMetricProvider(a.config, res, a.logger)
+		metricOpts := []provider.MetricProviderOption{
+			provider.WithMetricExportStats(a.exports),
+			provider.WithMetricSelfTelemetry(a.self),
+			provider.WithMetricReconnector(a.reconnector),
+			provider.WithMetricGRPCDialOptions(a.grpcDialOptions...),
+			provider.WithMetricTemporality(a.metricTemporality),
+			provider.WithMetricAggregation(a.metricAggregation),
+		}
+		if a.config.Features.HealthChecks {
+			metricOpts = append(metricOpts, provider.WithMetricExporterHealth(a.health))
+		}
+		a.meterProvider, err = provider.NewMetricProvider(a.config, res, a.logger, metricOpts...)
 		if err != nil {
 			return fmt.Errorf("failed to create metric provider: %w", err)
 		}
 		otel.SetMeterProvider(a.meterProvider)
+
+		if a.config.Features.HealthChecks {
+			failures, err := a.meterProvider.Meter(agentScopeName).Int64Counter(
+				"otel_agent_export_failures_total",
+				metric.WithDescription("Failed telemetry export calls by signal"),
+			)
+			if err == nil {
+				a.health.SetFailureCounter(failures)
+			}
+		}
 	}
 
 	// Initialize log provider
 	if a.config.Logs.Enabled {
-		a.loggerProvider, err = provider.NewLogProvider(a.config, res, a.logger)
+		logOpts := []provider.LogProviderOption{
+			provider.WithLogExportStats(a.exports),
+			provider.WithLogSelfTelemetry(a.self),
+			provider.WithLogReconnector(a.reconnector),
+			provider.WithLogDropMeter(otel.Meter(agentScopeName)),
+			provider.WithLogGRPCDialOptions(a.grpcDialOptions...),
+		}
+		if a.config.Features.HealthChecks {
+			logOpts = append(logOpts, provider.WithLogExporterHealth(a.health))
+		}
+		if a.blocklist != nil {
+			logOpts = append(logOpts, provider.WithLogBlocklist(a.blocklist))
+		}
+		a.loggerProvider, err = provider.NewLogProvider(a.config, res, a.logger, logOpts...)
 		if err != nil {
 			return fmt.Errorf("failed to create log provider: %w", err)
 		}
@@ -148,9 +387,14 @@ func (a *Agent) Init(ctx context.Context) error {
 
 		// Bridge zap logger to OTel LoggerProvider so log entries
 		// are exported via OTLP alongside traces and metrics.
-		if bridgeable, ok := a.logger.(interface {
+		switch bridgeable := a.logger.(type) {
+		case interface {
+			EnableOTelBridge(otellog.LoggerProvider, ...logger.BridgeOption)
+		}:
+			bridgeable.EnableOTelBridge(a.loggerProvider, logger.WithExportLevels(a.config.Logs.ExportLevels...))
+		case interface {
 			EnableOTelBridge(otellog.LoggerProvider)
-		}); ok {
+		}:
 			bridgeable.EnableOTelBridge(a.loggerProvider)
 		}
 	}
@@ -158,19 +402,38 @@ func (a *Agent) Init(ctx context.Context) error {
 	// Initialize instrumentor
 	a.instrumentor = instrumentor.New(a)
 
+	a.workers = workerpool.New(a.config.Performance.WorkerPoolSize, a.config.Performance.QueueBufferSize)
+	a.self.TrackWorkerQueue(a.workers.QueueDepth)
+
 	// Initialize collectors
 	if a.config.Metrics.Enabled {
 		if err := a.initCollectors(); err != nil {
+			_ = a.workers.Close(ctx)
+			a.workers = nil
 			return fmt.Errorf("failed to initialize collectors: %w", err)
 		}
 	}
 
 	// Set global helper provider
 	helper.SetGlobalProvider(a)
+	if a.config.Traces.ErrorStackTraces {
+		helper.SetErrorStackTraces(true,
+			helper.WithMaxStackFrames(a.config.Traces.ErrorStackFrames),
+			helper.WithMaxErrorChain(a.config.Traces.ErrorChainDepth),
+		)
+	}
+	helper.SetEventPayloadLimit(a.config.Traces.EventPayloadMaxSize)
+
+	// Share scrub rules with application code (scrub.Map, scrub.Struct)
+	scrub.SetDefault(scrubber)
 
 	a.initialized = true
 	a.running = true
 
+	if a.signalHook != signalHookNone {
+		a.installSignalHook(a.signalHook)
+	}
+
 	// Start collectors
 	if a.collector != nil {
 		if err := a.collector.Start(ctx); err != nil {
@@ -178,6 +441,12 @@ func (a *Agent) Init(ctx context.Context) error {
 		}
 	}
 
+	if a.budget != nil {
+		a.budget.OnChange(a.applyBudget)
+		a.budgetStop = make(chan struct{})
+		go a.watchBudget(a.budgetStop)
+	}
+
 	a.logger.Info(ctx, "Observability agent initialized", logger.Fields{
 		"service":  a.config.ServiceName,
 		"version":  a.config.Version,
@@ -185,6 +454,7 @@ func (a *Agent) Init(ctx context.Context) error {
 		"traces":   a.config.Traces.Enabled,
 		"metrics":  a.config.Metrics.Enabled,
 		"logs":     a.config.Logs.Enabled,
+		"dry_run":  a.config.Features.DryRun,
 	})
 
 	return nil
@@ -223,30 +493,114 @@ func (a *Agent) initCollectors() error {
 		return fmt.Errorf("performance collector: %w", err)
 	}
 
-	systemC, err = collector.NewSystemCollector(systemMeter, a.config.Metrics.DefaultInterval)
+	var systemOpts []collector.SystemCollectorOption
+	if a.config.Metrics.CPU {
+		systemOpts = append(systemOpts, collector.WithCPUMetrics())
+	}
+	if a.config.Metrics.Memory {
+		systemOpts = append(systemOpts, collector.WithMemoryMetrics())
+	}
+	if a.config.Metrics.Disk {
+		systemOpts = append(systemOpts, collector.WithDiskMetrics(a.config.Metrics.DiskPaths...))
+	}
+	systemC, err = collector.NewSystemCollector(systemMeter, a.config.Metrics.DefaultInterval, systemOpts...)
 	if err != nil {
 		return fmt.Errorf("system collector: %w", err)
 	}
 
+	for name, stats := range a.dbStats {
+		systemC.RegisterDBStats(name, stats)
+	}
+
+	a.system = systemC
 	a.collector = collector.New(a.logger, runtimeC, businessC, performanceC, systemC)
+	// Collectors submit without the agent lock, which Init still holds
+	workers := a.workers
+	a.collector.UseWorkers(func(task func(ctx context.Context)) error {
+		return a.submit(workers, task)
+	})
 	return nil
 }
 
-// Shutdown gracefully shuts down all providers with a 10s timeout enforcement.
+// RegisterDBStats reports the pool statistics returned by stats as
+// database connection gauges, labelled with name. Pools may be registered
+// before Init; they are handed to the system collector once it starts.
+func (a *Agent) RegisterDBStats(name string, stats func() sql.DBStats) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.dbStats == nil {
+		a.dbStats = make(map[string]func() sql.DBStats)
+	}
+	a.dbStats[name] = stats
+	if a.system != nil {
+		a.system.RegisterDBStats(name, stats)
+	}
+}
+
+// Submit runs task on the agent's worker pool, a bounded set of
+// Performance.WorkerPoolSize goroutines with a queue of
+// Performance.QueueBufferSize tasks, so async telemetry work (e.g. enriching
+// and emitting events off the request path) cannot pile up goroutines under
+// load. Submit never blocks: it returns ErrQueueFull when the queue is full,
+// ErrNotInitialized before Init and ErrWorkerPoolClosed after Shutdown.
+// Shutdown waits for queued tasks; those still running when its timeout
+// expires see their context cancelled. A panicking task is recorded with
+// helper.RecordPanic and does not stop its worker.
+func (a *Agent) Submit(task func(ctx context.Context)) error {
+	a.mu.RLock()
+	workers := a.workers
+	a.mu.RUnlock()
+
+	if workers == nil {
+		return ErrNotInitialized
+	}
+	return a.submit(workers, task)
+}
+
+func (a *Agent) submit(workers *workerpool.Pool, task func(ctx context.Context)) error {
+	err := workers.Submit(task)
+	if errors.Is(err, workerpool.ErrQueueFull) {
+		a.self.WorkerTaskRejected()
+	}
+	return err
+}
+
+// Shutdown stops the collectors, drains the worker pool and shuts down the
+// providers in drain order (traces, logs, then metrics), all within
+// Performance.ShutdownTimeout (see WithShutdownTimeout). What was exported
+// and dropped is logged. With WithFlushOnlyShutdown it only flushes and
+// keeps everything running, like Drain.
 func (a *Agent) Shutdown(ctx context.Context) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	if !a.initialized {
+	if !a.initialized || a.shutDown {
 		return nil
 	}
 
-	// Enforce 10s timeout
-	shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	// Everything below shares Performance.ShutdownTimeout
+	shutdownCtx, cancel := a.shutdownContext(ctx)
 	defer cancel()
 
+	if a.config.Performance.FlushOnlyShutdown {
+		report, err := a.drain(shutdownCtx, false)
+		a.logDrain(ctx, report, err)
+		return nil
+	}
+
 	a.logger.Info(ctx, "Shutting down observability agent...")
 
+	if a.signalStop != nil {
+		a.signalStop()
+		a.signalStop = nil
+	}
+
+	if a.budgetStop != nil {
+		close(a.budgetStop)
+		a.budgetStop = nil
+	}
+
 	// Stop collectors
 	if a.collector != nil {
 		if err := a.collector.Stop(shutdownCtx); err != nil {
@@ -254,31 +608,36 @@ func (a *Agent) Shutdown(ctx context.Context) error {
 		}
 	}
 
-	// Shutdown providers
-	if a.tracerProvider != nil {
-		if err := a.tracerProvider.Shutdown(shutdownCtx); err != nil {
-			a.logger.Error(ctx, "Failed to shutdown trace provider", logger.Fields{"error": err.Error()})
-		}
-	}
-
-	if a.meterProvider != nil {
-		if err := a.meterProvider.Shutdown(shutdownCtx); err != nil {
-			a.logger.Error(ctx, "Failed to shutdown metric provider", logger.Fields{"error": err.Error()})
+	// Let queued async work finish while its telemetry can still be
+	// exported. The pool drains without the lock: tasks may call back into
+	// the agent, and Submit fails once the pool is closed.
+	if workers := a.workers; workers != nil {
+		a.shutDown = true
+		a.mu.Unlock()
+		err := workers.Close(shutdownCtx)
+		a.mu.Lock()
+		if err != nil {
+			a.logger.Error(ctx, "Worker pool did not drain before shutdown", logger.Fields{"error": err.Error()})
 		}
 	}
+	a.shutDown = true
 
-	if a.loggerProvider != nil {
-		if err := a.loggerProvider.Shutdown(shutdownCtx); err != nil {
-			a.logger.Error(ctx, "Failed to shutdown log provider", logger.Fields{"error": err.Error()})
-		}
-	}
+	// Shutdown providers: traces, logs, then metrics
+	report, err := a.drain(shutdownCtx, true)
+	a.logDrain(ctx, report, err)
 
 	a.running = false
 	a.logger.Info(ctx, "Observability agent shut down")
+	// SDK errors after this point must not go to a logger that is shut down
+	if a.restoreErrorHandling != nil {
+		a.restoreErrorHandling()
+		a.restoreErrorHandling = nil
+	}
 	return nil
 }
 
-// ForceFlush flushes all pending telemetry without shutting down.
+// ForceFlush flushes all pending telemetry without shutting down, traces
+// first and metrics last.
 func (a *Agent) ForceFlush(ctx context.Context) error {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -293,21 +652,35 @@ func (a *Agent) ForceFlush(ctx context.Context) error {
 		}
 	}
 
-	if a.meterProvider != nil {
-		if err := a.meterProvider.ForceFlush(ctx); err != nil {
-			return fmt.Errorf("metric flush: %w", err)
-		}
-	}
-
 	if a.loggerProvider != nil {
 		if err := a.loggerProvider.ForceFlush(ctx); err != nil {
 			return fmt.Errorf("log flush: %w", err)
 		}
 	}
 
+	if a.meterProvider != nil {
+		if err := a.meterProvider.ForceFlush(ctx); err != nil {
+			return fmt.Errorf("metric flush: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// Reconnect replaces every exporter with a new one, opening fresh collector
+// connections, and shuts the old exporters down. Use it to recover an
+// exporter stuck on a dead connection without restarting the process;
+// buffered data is kept and goes out through the new exporters.
+func (a *Agent) Reconnect(ctx context.Context) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if !a.initialized {
+		return nil
+	}
+	return a.reconnector.Reconnect(ctx)
+}
+
 // --- TracerMeterProvider interface implementation ---
 
 // GetTracer returns a tracer for the given name. Never returns nil.
@@ -320,11 +693,30 @@ func (a *Agent) GetTracer(name string) trace.Tracer {
 		return cached.(trace.Tracer)
 	}
 
-	tracer := a.tracerProvider.Tracer(name)
+	tracer := a.tracing.Tracer(name)
 	a.tracers.Store(name, tracer)
 	return tracer
 }
 
+// Tracer returns a tracer whose instrumentation scope is the calling
+// package's import path, for ad-hoc instrumentation. Libraries should pass
+// an explicit name, which takes precedence. Never returns nil.
+func (a *Agent) Tracer(name ...string) trace.Tracer {
+	if len(name) > 0 && name[0] != "" {
+		return a.GetTracer(name[0])
+	}
+	return a.GetTracer(callerScope(2))
+}
+
+// Meter returns a meter whose instrumentation scope is the calling package's
+// import path. An explicit name takes precedence. Never returns nil.
+func (a *Agent) Meter(name ...string) metric.Meter {
+	if len(name) > 0 && name[0] != "" {
+		return a.GetMeter(name[0])
+	}
+	return a.GetMeter(callerScope(2))
+}
+
 // GetMeter returns a meter for the given name. Never returns nil.
 // Fix: original returned nil when disabled, causing panics in consumers.
 func (a *Agent) GetMeter(name string) metric.Meter {
@@ -336,7 +728,7 @@ func (a *Agent) GetMeter(name string) metric.Meter {
 		return cached.(metric.Meter)
 	}
 
-	meter := a.meterProvider.Meter(name)
+	meter := helper.NewBaggageMeter(a.meterProvider.Meter(name), a.config.Metrics.BaggageKeys...)
 	a.meters.Store(name, meter)
 	return meter
 }
@@ -368,7 +760,42 @@ func (a *Agent) RouteMatcher() *matcher.RouteMatcher {
 	return a.routeMatcher
 }
 
-// ExporterHealth returns the exporter health tracker.
+// MetricRouteMatcher returns the metric-only route exclusion matcher.
+func (a *Agent) MetricRouteMatcher() *matcher.RouteMatcher {
+	return a.metricRoutes
+}
+
+// ShouldTraceRoute reports whether requests to path should produce spans.
+func (a *Agent) ShouldTraceRoute(path string) bool {
+	if a.routeMatcher.ShouldExclude(path) {
+		a.self.RouteExcluded(provider.SignalTraces)
+		return false
+	}
+	return true
+}
+
+// ShouldRecordRouteMetrics reports whether requests to path should be
+// counted in request metrics. Trace-excluded routes are only counted when
+// RouteExclusion.KeepMetrics is set; MetricRouteExclusion always applies.
+func (a *Agent) ShouldRecordRouteMetrics(path string) bool {
+	if (a.routeMatcher.ShouldExclude(path) && !a.config.RouteExclusion.KeepMetrics) ||
+		a.metricRoutes.ShouldExclude(path) {
+		a.self.RouteExcluded(provider.SignalMetrics)
+		return false
+	}
+	return true
+}
+
+// Blocklist returns the runtime-updatable list of blocked subjects, or nil
+// unless Blocklist.Enabled was set when Init ran.
+//
+//	agent.Blocklist().Add(userID) // stop exporting telemetry about userID
+func (a *Agent) Blocklist() *provider.Blocklist {
+	return a.blocklist
+}
+
+// ExporterHealth returns the exporter health tracker. It records nothing
+// when Features.HealthChecks is off.
 func (a *Agent) ExporterHealth() *provider.ExporterHealth {
 	return a.health
 }
@@ -379,7 +806,7 @@ func (a *Agent) TracerProvider() trace.TracerProvider {
 	if a.tracerProvider == nil {
 		return nooptrace.NewTracerProvider()
 	}
-	return a.tracerProvider
+	return a.tracing
 }
 
 // LoggerProvider returns the underlying sdklog.LoggerProvider.
@@ -2,8 +2,24 @@ package otelagent
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/collector"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/RodolfoBonis/go-otel-agent/provider"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // newTestAgent creates an agent configured for fast test execution.
@@ -197,6 +213,20 @@ func TestInit_FailsWithoutServiceName(t *testing.T) {
 	}
 }
 
+func TestInit_FailsFastOnUnsupportedCompression(t *testing.T) {
+	agent := newTestAgent("test-compression")
+	agent.Config().Compression = "snappy"
+
+	err := agent.Init(context.Background())
+	if err == nil {
+		defer func() { _ = agent.Shutdown(context.Background()) }()
+		t.Fatal("expected error for unsupported compression")
+	}
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig, got: %v", err)
+	}
+}
+
 func TestInit_SucceedsWithValidConfig(t *testing.T) {
 	t.Setenv("OTEL_SERVICE_NAME", "test-service")
 
@@ -482,3 +512,658 @@ func TestShutdown_AfterDisabledInit(t *testing.T) {
 		t.Fatalf("expected no error on shutdown of disabled agent, got: %v", err)
 	}
 }
+
+func TestNewAgent_WithErrorHandler(t *testing.T) {
+	var handled error
+	h := otel.ErrorHandlerFunc(func(err error) { handled = err })
+
+	agent := NewAgent(WithErrorHandler(h))
+	if agent.errorHandler == nil {
+		t.Fatal("expected custom error handler to be set")
+	}
+
+	agent.errorHandler.Handle(errors.New("boom"))
+	if handled == nil || handled.Error() != "boom" {
+		t.Errorf("expected custom handler to receive error, got %v", handled)
+	}
+}
+
+func TestAgent_RegisterDBStats_BeforeInit(t *testing.T) {
+	agent := newTestAgent("test")
+
+	// Must not panic before collectors exist; the pool is kept for Init.
+	agent.RegisterDBStats("primary", func() sql.DBStats { return sql.DBStats{InUse: 1} })
+
+	if _, ok := agent.dbStats["primary"]; !ok {
+		t.Error("expected pool to be retained until collectors start")
+	}
+}
+
+func TestAgent_RouteMetricsIndependentOfTraces(t *testing.T) {
+	agent := NewAgent(
+		WithServiceName("test"),
+		WithRouteExclusions(RouteExclusionConfig{ExactPaths: []string{"/health"}, KeepMetrics: true}),
+		WithMetricRouteExclusions(RouteExclusionConfig{PrefixPaths: []string{"/debug/"}}),
+	)
+
+	tests := []struct {
+		path            string
+		traced, metered bool
+	}{
+		{"/health", false, true},
+		{"/debug/pprof", true, false},
+		{"/api/users", true, true},
+	}
+	for _, tt := range tests {
+		if got := agent.ShouldTraceRoute(tt.path); got != tt.traced {
+			t.Errorf("ShouldTraceRoute(%q) = %v, want %v", tt.path, got, tt.traced)
+		}
+		if got := agent.ShouldRecordRouteMetrics(tt.path); got != tt.metered {
+			t.Errorf("ShouldRecordRouteMetrics(%q) = %v, want %v", tt.path, got, tt.metered)
+		}
+	}
+
+	agent.Config().RouteExclusion.KeepMetrics = false
+	if agent.ShouldRecordRouteMetrics("/health") {
+		t.Error("trace-excluded route should not be metered without KeepMetrics")
+	}
+}
+
+func TestAgent_RouteExclusionsFromWithConfig(t *testing.T) {
+	cfg := LoadConfigFromEnv()
+	cfg.RouteExclusion = RouteExclusionConfig{ExactPaths: []string{"/health"}}
+	cfg.MetricRouteExclusion = RouteExclusionConfig{PrefixPaths: []string{"/debug/"}}
+	agent := NewAgent(WithServiceName("test"), WithConfig(cfg))
+
+	if agent.ShouldTraceRoute("/health") {
+		t.Error("ShouldTraceRoute(/health) = true, want the WithConfig exclusion applied")
+	}
+	if agent.ShouldRecordRouteMetrics("/debug/pprof") {
+		t.Error("ShouldRecordRouteMetrics(/debug/pprof) = true, want the WithConfig metric exclusion applied")
+	}
+}
+
+func TestNewAgent_WithRegion_SelectsRegistryEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	registry := map[string]string{
+		"eu-west-1": "collector.eu:4317",
+		"us-east-1": "collector.us:4317",
+	}
+
+	agent := NewAgent(WithServiceName("test"), WithEndpointRegistry(registry), WithRegion("us-east-1"))
+	if agent.Config().Endpoint != "collector.us:4317" {
+		t.Errorf("expected us-east-1 endpoint, got %q", agent.Config().Endpoint)
+	}
+
+	agent = NewAgent(WithServiceName("test"), WithEndpointRegistry(registry), WithRegion("eu-west-1"), WithEndpoint("explicit:4317"))
+	if agent.Config().Endpoint != "explicit:4317" {
+		t.Errorf("expected explicit endpoint to win, got %q", agent.Config().Endpoint)
+	}
+
+	cfg := LoadConfigFromEnv()
+	cfg.Endpoint = "from-config:4317"
+	agent = NewAgent(WithConfig(cfg), WithEndpointRegistry(registry), WithRegion("eu-west-1"))
+	if agent.Config().Endpoint != "from-config:4317" {
+		t.Errorf("expected the WithConfig endpoint to win, got %q", agent.Config().Endpoint)
+	}
+
+	path := writeConfigFile(t, "otel.yaml", "endpoint: from-file:4317\n")
+	agent = NewAgent(WithConfigFile(path), WithEndpointRegistry(registry), WithRegion("eu-west-1"))
+	if agent.Config().Endpoint != "from-file:4317" {
+		t.Errorf("expected the file endpoint to win, got %q", agent.Config().Endpoint)
+	}
+}
+
+func TestNewAgent_WithConfigFile_OptionsWinRegardlessOfOrder(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	t.Setenv("OTEL_SERVICE_VERSION", "")
+	t.Setenv("VERSION", "")
+	t.Setenv("OTEL_TRACES_SAMPLER_ARG", "")
+
+	path := writeConfigFile(t, "otel.yaml", `
+service_name: from-file
+version: 1.2.3
+traces:
+  sampling:
+    rate: 0.1
+`)
+
+	agent := NewAgent(WithSamplingRate(1.0), WithConfigFile(path))
+	cfg := agent.Config()
+	if cfg.Traces.Sampling.Rate != 1.0 {
+		t.Errorf("expected option to win over file, got sampling rate %v", cfg.Traces.Sampling.Rate)
+	}
+	if cfg.ServiceName != "from-file" || cfg.Version != "1.2.3" {
+		t.Errorf("expected file values, got service %q version %q", cfg.ServiceName, cfg.Version)
+	}
+}
+
+type countingDetector struct{ calls atomic.Int32 }
+
+func (d *countingDetector) Detect(context.Context) (*resource.Resource, error) {
+	d.calls.Add(1)
+	return resource.Empty(), nil
+}
+
+func TestNewAgent_WithConfigFile_RunsResourceDetectorsOnce(t *testing.T) {
+	path := writeConfigFile(t, "otel.yaml", "version: 1.2.3\n")
+
+	detector := &countingDetector{}
+	agent := NewAgent(
+		WithConfigFile(path),
+		WithResourceDetectors(detector),
+		WithServiceName("test-detectors"),
+		WithInsecure(true),
+		WithEndpoint("localhost:4317"),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+	)
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	if n := detector.calls.Load(); n != 1 {
+		t.Errorf("detector ran %d times, want 1", n)
+	}
+}
+
+func TestDiagnostics_ReportsConfigSources(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	t.Setenv("OTEL_SERVICE_VERSION", "")
+	t.Setenv("VERSION", "")
+	t.Setenv("OTEL_TRACES_SAMPLER_ARG", "")
+	t.Setenv("OTEL_SERVICE_NAMESPACE", "from-env")
+
+	path := writeConfigFile(t, "otel.yaml", `
+version: 1.2.3
+traces:
+  sampling:
+    rate: 0.1
+`)
+
+	agent := NewAgent(WithConfigFile(path), WithServiceName("from-option"), WithSamplingRate(1.0))
+	sources := agent.Diagnostics().ConfigSources
+	for key, want := range map[string]string{
+		"service_name":         "option",
+		"traces.sampling.rate": "option",
+		"version":              "file",
+		"namespace":            "env",
+		"traces.batch_size":    "default",
+	} {
+		if got := sources[key]; got != want {
+			t.Errorf("ConfigSources[%q] = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestInit_FailsOnInvalidConfigFile(t *testing.T) {
+	path := writeConfigFile(t, "otel.toml", "")
+
+	agent := NewAgent(WithServiceName("test"), WithConfigFile(path))
+	err := agent.Init(context.Background())
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+}
+
+func TestDiagnostics_ReportsAdaptiveSamplingRate(t *testing.T) {
+	agent := newTestAgent("test-adaptive")
+	agent.Config().Traces.Sampling.Rate = 0.5
+	agent.Config().Performance.AdaptiveSampling = true
+
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	diag := agent.Diagnostics()
+	if !diag.AdaptiveSampling {
+		t.Error("expected AdaptiveSampling in diagnostics")
+	}
+	if diag.EffectiveSamplingRate != 0.5 {
+		t.Errorf("EffectiveSamplingRate = %v, want the configured ceiling 0.5 before any traffic", diag.EffectiveSamplingRate)
+	}
+}
+
+func TestDiagnostics_ReportsRateLimitedBudgetAsSpansPerSecond(t *testing.T) {
+	agent := newTestAgent("test-rate-limited")
+	agent.Config().Traces.Sampling.Type = "rate_limited"
+	agent.Config().Traces.Sampling.Rate = 250
+
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	diag := agent.Diagnostics()
+	if diag.SamplerType != "rate_limited" || diag.SpansPerSecond != 250 {
+		t.Errorf("SamplerType, SpansPerSecond = %q, %v, want rate_limited, 250", diag.SamplerType, diag.SpansPerSecond)
+	}
+	if diag.SamplingRate != 0 || diag.EffectiveSamplingRate != 0 {
+		t.Errorf("SamplingRate, EffectiveSamplingRate = %v, %v, want 0: the budget is not a ratio", diag.SamplingRate, diag.EffectiveSamplingRate)
+	}
+}
+
+func TestDiagnostics_ReportsInvalidScrubPatterns(t *testing.T) {
+	agent := newTestAgent("test-scrub-patterns")
+	agent.Config().Scrub.SensitivePatterns = []string{".*token.*", "(unclosed"}
+
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	diag := agent.Diagnostics()
+	if diag.InvalidScrubPatterns != 1 {
+		t.Errorf("InvalidScrubPatterns = %d, want 1", diag.InvalidScrubPatterns)
+	}
+	if len(diag.ScrubPatternErrors) != 1 || !strings.Contains(diag.ScrubPatternErrors[0], "(unclosed") {
+		t.Errorf("ScrubPatternErrors = %v, want the invalid pattern", diag.ScrubPatternErrors)
+	}
+}
+
+func TestInit_WithStdoutExporterNeedsNoCollector(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.json")
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", path)
+
+	agent := NewAgent(
+		WithServiceName("stdout-test"),
+		WithStdoutExporter(),
+		WithEndpoint(""),
+	)
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	_, span := agent.GetTracer("test").Start(context.Background(), "local-span")
+	span.End()
+	if err := agent.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !strings.Contains(string(got), "local-span") {
+		t.Errorf("expected span in %s", path)
+	}
+}
+
+func TestDiagnostics_ReportsLastExportPerSignal(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+
+	agent := NewAgent(
+		WithServiceName("export-stats-test"),
+		WithStdoutExporter(),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+	)
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	if got := agent.Diagnostics().Exports; got["traces"].SinceLastExport != "never" || len(got) != 1 {
+		t.Fatalf("Exports before any span = %+v, want only traces, never exported", got)
+	}
+
+	for range 3 {
+		_, span := agent.GetTracer("test").Start(context.Background(), "op")
+		span.End()
+	}
+	if err := agent.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	traces := agent.Diagnostics().Exports["traces"]
+	if traces.LastExport == nil || traces.LastBatchSize != 3 || traces.TotalExported != 3 {
+		t.Errorf("traces export = %+v, want one batch of 3 spans", traces)
+	}
+}
+
+func TestInit_DryRunNeedsNoCollector(t *testing.T) {
+	agent := NewAgent(
+		WithServiceName("dry-run-test"),
+		WithEndpoint("127.0.0.1:1"),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+	)
+	agent.Config().Features.DryRun = true
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	for range 2 {
+		_, span := agent.GetTracer("test").Start(context.Background(), "op")
+		span.End()
+	}
+	if err := agent.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	diag := agent.Diagnostics()
+	if !diag.DryRun {
+		t.Error("Diagnostics().DryRun = false, want true")
+	}
+	if got := diag.Exports["traces"].TotalExported; got != 2 {
+		t.Errorf("would-be exported spans = %d, want 2", got)
+	}
+}
+
+func TestInit_BlocklistDropsBlockedSubjects(t *testing.T) {
+	if newTestAgent("test-no-blocklist").Blocklist() != nil {
+		t.Error("expected nil Blocklist when disabled")
+	}
+
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+	agent := NewAgent(
+		WithServiceName("blocklist-test"),
+		WithStdoutExporter(),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+	)
+	agent.Config().Blocklist.Enabled = true
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	agent.Blocklist().Add("u-42")
+	for _, user := range []string{"u-42", "u-1"} {
+		_, span := agent.GetTracer("test").Start(context.Background(), "op",
+			trace.WithAttributes(attribute.String("user.id", user)))
+		span.End()
+	}
+	if err := agent.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	if got := agent.Diagnostics().Exports["traces"].TotalExported; got != 1 {
+		t.Errorf("exported %d spans, want only the unblocked one", got)
+	}
+}
+
+func TestReadinessCheck_FailsWhenExportsKeepFailing(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer collector.Close()
+
+	agent := NewAgent(
+		WithServiceName("health-test"),
+		WithInsecure(true),
+		WithEndpoint(strings.TrimPrefix(collector.URL, "http://")),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+	)
+	agent.Config().ExporterProtocol = "http"
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	if !agent.ReadinessCheck() {
+		t.Fatal("expected agent to be ready before any export")
+	}
+	for range 10 {
+		_, span := agent.GetTracer("test").Start(context.Background(), "op")
+		span.End()
+		_ = agent.ForceFlush(context.Background())
+	}
+
+	if got := agent.HealthCheck(); got.Status != "unhealthy" {
+		t.Errorf("HealthCheck().Status = %q, want unhealthy", got.Status)
+	}
+	if agent.ReadinessCheck() {
+		t.Error("expected agent not to be ready once the collector keeps rejecting exports")
+	}
+}
+
+func TestInit_WithSelfTelemetry(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+
+	agent := NewAgent(
+		WithServiceName("self-telemetry-test"),
+		WithStdoutExporter(),
+		WithSelfTelemetry(true),
+		WithDisabledSignals(SignalLogs),
+	)
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	if agent.self == nil {
+		t.Fatal("expected self-telemetry to be created")
+	}
+	if agent.ShouldTraceRoute("/health") {
+		t.Error("expected /health to stay excluded")
+	}
+}
+
+func TestNewAgent_AppliesLogCustomFields(t *testing.T) {
+	t.Setenv("OTEL_LOGS_CUSTOM_FIELDS", "team=payments,region=eu")
+	rec := &fieldsLogger{}
+	a := NewAgent(WithServiceName("svc"), WithLogger(rec))
+
+	if a.Logger() == logger.Logger(rec) {
+		t.Fatal("expected the logger to be wrapped with the custom fields")
+	}
+	if rec.with["team"] != "payments" || rec.with["region"] != "eu" {
+		t.Errorf("With fields = %v, want team and region", rec.with)
+	}
+}
+
+// fieldsLogger records the fields passed to With.
+type fieldsLogger struct {
+	logger.NoopLogger
+	with logger.Fields
+}
+
+func (l *fieldsLogger) With(fields logger.Fields) logger.Logger {
+	l.with = fields
+	return &logger.NoopLogger{}
+}
+
+func TestSubmit_RunsOnBoundedPoolAndDrainsOnShutdown(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+	t.Setenv("OTEL_WORKER_POOL_SIZE", "1")
+	t.Setenv("OTEL_QUEUE_BUFFER_SIZE", "1")
+
+	agent := NewAgent(
+		WithServiceName("submit-test"),
+		WithStdoutExporter(),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+	)
+	if err := agent.Submit(func(context.Context) {}); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Submit before Init = %v, want ErrNotInitialized", err)
+	}
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	if err := agent.Submit(func(context.Context) { close(started); <-release }); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	<-started
+	var queuedRan atomic.Bool
+	if err := agent.Submit(func(context.Context) { queuedRan.Store(true) }); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if err := agent.Submit(func(context.Context) {}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Submit with a busy worker and a full queue = %v, want ErrQueueFull", err)
+	}
+
+	close(release)
+	if err := agent.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if !queuedRan.Load() {
+		t.Error("queued task did not run before Shutdown returned")
+	}
+	if err := agent.Submit(func(context.Context) {}); !errors.Is(err, ErrWorkerPoolClosed) {
+		t.Errorf("Submit after Shutdown = %v, want ErrWorkerPoolClosed", err)
+	}
+}
+
+func TestShutdown_DrainsTasksThatCallBackIntoTheAgent(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+
+	agent := NewAgent(
+		WithServiceName("submit-test"),
+		WithStdoutExporter(),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+	)
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	release := make(chan struct{})
+	if err := agent.Submit(func(ctx context.Context) {
+		<-release
+		_ = agent.ForceFlush(ctx)
+		_ = agent.Diagnostics()
+	}); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- agent.Shutdown(context.Background()) }()
+	time.Sleep(50 * time.Millisecond) // let Shutdown reach the pool drain
+	close(release)
+
+	select {
+	case err := <-shutdown:
+		if err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown deadlocked on a task calling back into the agent")
+	}
+}
+
+func TestResourceBudget_DegradesHealthCheck(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+	t.Setenv("OTEL_RESOURCE_BUDGET", "true")
+	t.Setenv("OTEL_MAX_MEMORY_USAGE", "1") // any heap exceeds it
+
+	agent := NewAgent(
+		WithServiceName("budget-test"),
+		WithStdoutExporter(),
+		WithDisabledSignals(SignalLogs),
+	)
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	if status := agent.HealthCheck(); status.Status != "ok" || status.Budget == nil || status.Budget.State != provider.BudgetNormal {
+		t.Fatalf("HealthCheck() before a check = %+v, want ok with a normal budget", status)
+	}
+
+	agent.budget.Check(context.Background())
+	if !agent.Degraded() {
+		t.Fatal("Degraded() = false with a 1-byte memory budget")
+	}
+	status := agent.HealthCheck()
+	if status.Status != "degraded" || status.Budget.State != provider.BudgetDegraded || len(status.Budget.Reasons) == 0 {
+		t.Errorf("HealthCheck() = %+v, want degraded with a reason", status)
+	}
+	if got := agent.collector.IntervalFactor(); got != degradedIntervalFactor {
+		t.Errorf("collector interval factor = %d, want %d", got, degradedIntervalFactor)
+	}
+}
+
+func TestInit_AutoTunesDefaultsForSmallContainers(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+	t.Setenv("OTEL_BLRP_MAX_QUEUE_SIZE", "4096")
+
+	orig := readLimits
+	readLimits = func() collector.Limits {
+		return collector.Limits{MemoryBytes: 128 << 20, CPUs: 0.5, ContainerMemory: true, ContainerCPU: true}
+	}
+	defer func() { readLimits = orig }()
+
+	agent := NewAgent(
+		WithServiceName("autotune-test"),
+		WithStdoutExporter(),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+	)
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	cfg := agent.Config()
+	if cfg.Traces.QueueSize != 512 || cfg.Performance.WorkerPoolSize != 1 {
+		t.Errorf("traces.queue_size = %d, worker_pool_size = %d, want 512 and 1", cfg.Traces.QueueSize, cfg.Performance.WorkerPoolSize)
+	}
+	if cfg.Logs.QueueSize != 4096 {
+		t.Errorf("logs.queue_size = %d, want the env value 4096", cfg.Logs.QueueSize)
+	}
+	sources := agent.Diagnostics().ConfigSources
+	if sources["traces.queue_size"] != "auto" || sources["logs.queue_size"] != "env" {
+		t.Errorf("ConfigSources = %v, want traces.queue_size auto and logs.queue_size env", sources)
+	}
+}
+
+func TestShutdown_FlushOnlyKeepsProvidersForTheNextInvocation(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+
+	agent := NewAgent(
+		WithServiceName("flush-only-test"),
+		WithStdoutExporter(),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+		WithShutdownTimeout(time.Second),
+		WithFlushOnlyShutdown(true),
+	)
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() {
+		agent.Config().Performance.FlushOnlyShutdown = false
+		_ = agent.Shutdown(context.Background())
+	}()
+
+	for invocation := 1; invocation <= 2; invocation++ {
+		_, span := agent.GetTracer("test").Start(context.Background(), "handler")
+		span.End()
+		if err := agent.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+		if got := agent.Diagnostics().Exports["traces"].TotalExported; got != int64(invocation) {
+			t.Errorf("after invocation %d, TotalExported = %d, want %d", invocation, got, invocation)
+		}
+	}
+
+	report, err := agent.Drain(context.Background())
+	if err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if len(report.Signals) != 1 || report.Signals[0].Signal != "traces" || report.Shutdown || report.Dropped() != 0 {
+		t.Errorf("Drain() = %+v, want a flush of traces with nothing dropped", report)
+	}
+}
+
+func TestShutdown_StopsRoutingSDKErrorsToTheAgent(t *testing.T) {
+	var handled atomic.Int32
+	agent := NewAgent(
+		WithServiceName("test-error-handler"),
+		WithInsecure(true),
+		WithEndpoint("localhost:4317"),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+		WithErrorHandler(otel.ErrorHandlerFunc(func(error) { handled.Add(1) })),
+	)
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	otel.Handle(errors.New("export failed"))
+	if err := agent.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	otel.Handle(errors.New("after shutdown"))
+
+	if got := handled.Load(); got != 1 {
+		t.Errorf("agent handled %d SDK errors, want only the one before Shutdown", got)
+	}
+}
@@ -0,0 +1,52 @@
+package otelagent
+
+import (
+	"context"
+	"os"
+	"runtime/debug"
+
+	"github.com/RodolfoBonis/go-otel-agent/collector"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+)
+
+// memoryLimitRatio is the share of the container memory given to the Go
+// runtime by Performance.SetMemoryLimit; the rest is headroom for memory the
+// runtime does not manage.
+const memoryLimitRatio = 0.9
+
+// readLimits is replaced in tests.
+var readLimits = collector.ReadLimits
+
+// autoTune fits the configuration defaults to the memory and CPUs available
+// to the process (Performance.AutoTune) and optionally sets the Go memory
+// limit from the container's (Performance.SetMemoryLimit).
+func (a *Agent) autoTune(ctx context.Context) {
+	perf := a.config.Performance
+	if !perf.AutoTune && !perf.SetMemoryLimit {
+		return
+	}
+	limits := readLimits()
+
+	if perf.AutoTune {
+		if changed := a.config.AutoTune(limits.MemoryBytes, limits.CPUs, a.provenance); len(changed) > 0 {
+			a.logger.Info(ctx, "Auto-tuned configuration", logger.Fields{
+				"memory_bytes":   limits.MemoryBytes,
+				"cpus":           limits.CPUs,
+				"container":      limits.ContainerMemory,
+				"tuned":          changed,
+				"trace_queue":    a.config.Traces.QueueSize,
+				"runtime_period": a.config.Metrics.RuntimeInterval.String(),
+			})
+		}
+	}
+
+	// An explicit GOMEMLIMIT always wins
+	if perf.SetMemoryLimit && limits.ContainerMemory && os.Getenv("GOMEMLIMIT") == "" {
+		limit := int64(float64(limits.MemoryBytes) * memoryLimitRatio)
+		debug.SetMemoryLimit(limit)
+		a.logger.Info(ctx, "Go memory limit set from container limit", logger.Fields{
+			"container_bytes": limits.MemoryBytes,
+			"limit_bytes":     limit,
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package otelagent
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// budgetCheckInterval is how often the resource budget is checked.
+	budgetCheckInterval = 15 * time.Second
+	// degradedIntervalFactor stretches the collector intervals while the
+	// agent is over its resource budget.
+	degradedIntervalFactor = 4
+)
+
+// Degraded reports whether the agent is over its resource budget
+// (Features.ResourceBudget). Integrations skip optional, costly capture such
+// as request and response bodies while it is.
+func (a *Agent) Degraded() bool {
+	return a.budget.Degraded()
+}
+
+func (a *Agent) watchBudget(stop <-chan struct{}) {
+	ticker := time.NewTicker(budgetCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			a.budget.Check(context.Background())
+		}
+	}
+}
+
+// applyBudget slows the collectors down while the agent is degraded. Fewer
+// sampled traces and no body capture follow from the budget itself.
+func (a *Agent) applyBudget(degraded bool) {
+	if a.collector == nil {
+		return
+	}
+	if degraded {
+		a.collector.SetIntervalFactor(degradedIntervalFactor)
+	} else {
+		a.collector.SetIntervalFactor(1)
+	}
+}
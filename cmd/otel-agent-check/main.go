@@ -0,0 +1,183 @@
+// Command otel-agent-check loads go-otel-agent configuration exactly like the
+// agent does (defaults, optional file, environment), validates it, probes the
+// collector endpoint and prints the effective configuration with secrets
+// redacted. It exits non-zero on any problem, so it can gate a rollout as an
+// initContainer or CI step:
+//
+//	otel-agent-check -config /etc/otel/otel.yaml
+//
+// Exit codes: 0 ok, 1 invalid configuration or unreachable endpoint,
+// 2 usage or load error.
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/provider"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("otel-agent-check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configFile := fs.String("config", "", "YAML or JSON config file layered under the environment")
+	probe := fs.Bool("probe", true, "dial the collector endpoint")
+	timeout := fs.Duration("timeout", 5*time.Second, "endpoint probe timeout")
+	quiet := fs.Bool("quiet", false, "do not print the effective configuration")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := otelagent.LoadConfigFromEnv()
+	if *configFile != "" {
+		var err error
+		if cfg, err = otelagent.LoadConfigFromFile(*configFile); err != nil {
+			fmt.Fprintf(stderr, "load: %v\n", err)
+			return 2
+		}
+	}
+
+	if !*quiet {
+		out, err := json.MarshalIndent(effectiveConfig(cfg), "", "  ")
+		if err != nil {
+			fmt.Fprintf(stderr, "print: %v\n", err)
+			return 2
+		}
+		fmt.Fprintln(stdout, string(out))
+	}
+
+	ok := true
+	if err := cfg.Validate(); err != nil {
+		ok = false
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(stderr, "invalid: %s\n", line)
+		}
+	} else {
+		fmt.Fprintln(stderr, "config: ok")
+	}
+
+	if *probe && cfg.Enabled {
+		for _, target := range probeTargets(cfg) {
+			if err := probeEndpoint(cfg, target, *timeout); err != nil {
+				ok = false
+				fmt.Fprintf(stderr, "probe: %v\n", err)
+			} else {
+				fmt.Fprintf(stderr, "probe: %s reachable\n", target.Endpoint)
+			}
+		}
+	}
+
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+// probeTargets returns the distinct collector endpoints of the enabled
+// signals, skipping signals exported to stdout.
+func probeTargets(cfg *otelagent.Config) []otelagent.SignalExporterConfig {
+	var targets []otelagent.SignalExporterConfig
+	seen := make(map[string]bool)
+	for signal, enabled := range map[string]bool{
+		"traces":  cfg.Traces.Enabled,
+		"metrics": cfg.Metrics.Enabled,
+		"logs":    cfg.Logs.Enabled,
+	} {
+		target := cfg.SignalExporter(signal)
+		key := target.Protocol + " " + target.Endpoint
+		if !enabled || target.Protocol == otelagent.ProtocolStdout || seen[key] {
+			continue
+		}
+		seen[key] = true
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Endpoint < targets[j].Endpoint })
+	return targets
+}
+
+// probeEndpoint opens a TCP (or unix socket) connection to the collector,
+// completing a TLS handshake when the exporter is configured for TLS.
+func probeEndpoint(cfg *otelagent.Config, target otelagent.SignalExporterConfig, timeout time.Duration) error {
+	network, addr := "tcp", target.Endpoint
+	if path, ok := strings.CutPrefix(addr, "unix://"); ok {
+		network, addr = "unix", path
+	} else if u, err := url.Parse(addr); err == nil && u.Host != "" {
+		addr = u.Host
+	}
+	if _, _, err := net.SplitHostPort(addr); network == "tcp" && err != nil {
+		port := "4317"
+		if strings.HasPrefix(target.Protocol, "http") {
+			port = "4318"
+		}
+		addr = net.JoinHostPort(addr, port)
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	if cfg.Insecure {
+		conn, err := dialer.Dial(network, addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	tlsCfg, err := provider.BuildTLSConfig(cfg.TLS)
+	if err != nil {
+		return err
+	}
+	conn, err := tls.DialWithDialer(dialer, network, addr, tlsCfg)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// effectiveConfig converts cfg into a map keyed by the json tags (the same
+// keys config files use), with durations as strings and secrets redacted by
+// Config.Redacted, the same way the agent's GET /config serves them.
+func effectiveConfig(cfg *otelagent.Config) map[string]any {
+	return toValue(reflect.ValueOf(*cfg.Redacted())).(map[string]any)
+}
+
+func toValue(v reflect.Value) any {
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Kind() == reflect.Struct:
+		m := make(map[string]any, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			m[name] = toValue(v.Field(i))
+		}
+		return m
+	case v.Kind() == reflect.Map:
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = toValue(iter.Value())
+		}
+		return m
+	default:
+		return v.Interface()
+	}
+}
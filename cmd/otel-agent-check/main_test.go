@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_PrintsRedactedConfigAndProbes(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	t.Setenv("OTEL_SERVICE_NAME", "checked")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", ln.Addr().String())
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "true")
+	t.Setenv("SIGNOZ_ACCESS_TOKEN", "super-secret")
+
+	var stdout, stderr bytes.Buffer
+	if code := run(nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, stderr:\n%s", code, stderr.String())
+	}
+
+	if strings.Contains(stdout.String(), "super-secret") {
+		t.Error("access token leaked into the printed config")
+	}
+	var printed map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &printed); err != nil {
+		t.Fatalf("stdout is not JSON: %v", err)
+	}
+	if printed["service_name"] != "checked" || printed["timeout"] != "10s" {
+		t.Errorf("unexpected printed config: service_name=%v timeout=%v", printed["service_name"], printed["timeout"])
+	}
+	if !strings.Contains(stderr.String(), "reachable") {
+		t.Errorf("expected probe success, stderr:\n%s", stderr.String())
+	}
+}
+
+func TestRun_FailsOnInvalidConfig(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	t.Setenv("OTEL_TRACES_SAMPLER_ARG", "2")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-probe=false", "-quiet"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code = %d, want 1", code)
+	}
+	for _, want := range []string{"service_name is required", "traces.sampling.rate"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("expected %q in stderr:\n%s", want, stderr.String())
+		}
+	}
+	if stdout.Len() != 0 {
+		t.Error("expected no config output with -quiet")
+	}
+}
+
+func TestRun_LoadErrorExitsWithUsageCode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "otel.yaml")
+	if err := os.WriteFile(path, []byte("not_a_key: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-config", path}, &stdout, &stderr); code != 2 {
+		t.Fatalf("exit code = %d, want 2", code)
+	}
+}
+
+func TestRun_ProbesEachSignalEndpointAndRedactsSecrets(t *testing.T) {
+	var addrs []string
+	for range 2 {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ln.Close()
+		addrs = append(addrs, ln.Addr().String())
+	}
+
+	t.Setenv("OTEL_SERVICE_NAME", "checked")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", addrs[0])
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "true")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", "http://"+addrs[1]+"/otlp/v1/metrics")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_PROTOCOL", "http")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_HEADERS", "Authorization=Bearer mimir-secret")
+	t.Setenv("OTEL_BLOCKLIST_VALUES", "user-42")
+
+	var stdout, stderr bytes.Buffer
+	if code := run(nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, stderr:\n%s", code, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "mimir-secret") {
+		t.Error("per-signal header leaked into the printed config")
+	}
+	if strings.Contains(stdout.String(), "user-42") {
+		t.Error("blocklist value leaked into the printed config")
+	}
+	if n := strings.Count(stderr.String(), "reachable"); n != 2 {
+		t.Errorf("expected 2 probed endpoints, stderr:\n%s", stderr.String())
+	}
+}
+
+func TestRun_ProbesUnixSocketEndpoint(t *testing.T) {
+	dir, err := os.MkdirTemp("", "otlp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ln, err := net.Listen("unix", filepath.Join(dir, "collector.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	t.Setenv("OTEL_SERVICE_NAME", "checked")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "unix://"+ln.Addr().String())
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "true")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-quiet"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, stderr:\n%s", code, stderr.String())
+	}
+}
@@ -0,0 +1,402 @@
+// Command otel-wrap generates a wrapper for an interface that traces and
+// measures every method call with instrumentor.MethodTracer, so repositories
+// and services are instrumented without a hand-written wrapper per method.
+// Run it through go generate next to the interface:
+//
+//	//go:generate go run github.com/RodolfoBonis/go-otel-agent/cmd/otel-wrap -type OrderRepository
+//
+// It writes orderrepository_traced.go, declaring
+//
+//	func NewTracedOrderRepository(next OrderRepository, inst *instrumentor.Instrumentor) OrderRepository
+//
+// whose calls run in spans named "OrderRepository.<Method>". A
+// context.Context first parameter carries the span on to next, and an error
+// last result sets the span status and the success attribute of
+// instrumentor.MethodDurationMetric.
+//
+// Interfaces embedded from the same package are expanded; interfaces
+// embedded from other packages and generic interfaces are not supported.
+//
+// Exit codes: 0 ok, 1 generation error, 2 usage error.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const instrumentorPath = "github.com/RodolfoBonis/go-otel-agent/instrumentor"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+func run(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("otel-wrap", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	typeName := fs.String("type", "", "interface to wrap (required)")
+	dir := fs.String("dir", ".", "directory of the package declaring the interface")
+	output := fs.String("output", "", "output file (default <dir>/<type>_traced.go)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *typeName == "" {
+		fmt.Fprintln(stderr, "otel-wrap: -type is required")
+		return 2
+	}
+
+	src, err := generate(*dir, *typeName)
+	if err != nil {
+		fmt.Fprintf(stderr, "otel-wrap: %v\n", err)
+		return 1
+	}
+	out := *output
+	if out == "" {
+		out = filepath.Join(*dir, strings.ToLower(*typeName)+"_traced.go")
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		fmt.Fprintf(stderr, "otel-wrap: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// method is one method of the wrapped interface.
+type method struct {
+	name string
+	typ  *ast.FuncType
+	file *ast.File // declares the method, for its imports
+}
+
+// pkg is the parsed, non-generated source of a package.
+type pkg struct {
+	name       string
+	fset       *token.FileSet
+	interfaces map[string]*ast.TypeSpec
+	files      map[*ast.TypeSpec]*ast.File
+}
+
+func parsePackage(dir string) (*pkg, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	p := &pkg{
+		fset:       token.NewFileSet(),
+		interfaces: make(map[string]*ast.TypeSpec),
+		files:      make(map[*ast.TypeSpec]*ast.File),
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(p.fset, filepath.Join(dir, name), nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		if ast.IsGenerated(f) {
+			continue
+		}
+		p.name = f.Name.Name
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if _, ok := ts.Type.(*ast.InterfaceType); ok {
+					p.interfaces[ts.Name.Name] = ts
+					p.files[ts] = f
+				}
+			}
+		}
+	}
+	if p.name == "" {
+		return nil, fmt.Errorf("no Go source files in %s", dir)
+	}
+	return p, nil
+}
+
+// methods returns the methods of the interface typeName, expanding the
+// interfaces it embeds from the same package.
+func (p *pkg) methods(typeName string, seen map[string]bool) ([]method, error) {
+	ts, ok := p.interfaces[typeName]
+	if !ok {
+		return nil, fmt.Errorf("interface %s not found in package %s", typeName, p.name)
+	}
+	if ts.TypeParams != nil {
+		return nil, fmt.Errorf("interface %s is generic, which is not supported", typeName)
+	}
+	if seen[typeName] {
+		return nil, nil
+	}
+	seen[typeName] = true
+
+	var methods []method
+	for _, field := range ts.Type.(*ast.InterfaceType).Methods.List {
+		switch t := field.Type.(type) {
+		case *ast.FuncType:
+			for _, name := range field.Names {
+				methods = append(methods, method{name: name.Name, typ: t, file: p.files[ts]})
+			}
+		case *ast.Ident:
+			embedded, err := p.methods(t.Name, seen)
+			if err != nil {
+				return nil, fmt.Errorf("%s embeds %s: %w", typeName, t.Name, err)
+			}
+			methods = append(methods, embedded...)
+		default:
+			return nil, fmt.Errorf("%s embeds %s, which is not supported: list its methods instead", typeName, p.expr(field.Type))
+		}
+	}
+	return methods, nil
+}
+
+func (p *pkg) expr(e ast.Expr) string {
+	var b bytes.Buffer
+	_ = printer.Fprint(&b, p.fset, e)
+	return b.String()
+}
+
+// generate returns the formatted source of the wrapper of typeName, declared
+// in the package in dir.
+func generate(dir, typeName string) ([]byte, error) {
+	p, err := parsePackage(dir)
+	if err != nil {
+		return nil, err
+	}
+	methods, err := p.methods(typeName, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(methods, func(i, j int) bool { return methods[i].name < methods[j].name })
+
+	imports := map[string]string{instrumentorPath: ""} // path -> explicit name
+	wrapper := "traced" + upperFirst(typeName)
+	constructor := "NewTraced" + upperFirst(typeName)
+	if !ast.IsExported(typeName) {
+		constructor = "newTraced" + upperFirst(typeName)
+	}
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "// %s is a %s whose method calls are traced and measured.\n", wrapper, typeName)
+	fmt.Fprintf(&body, "type %s struct {\n\tnext %s\n\ttracer *instrumentor.MethodTracer\n}\n\n", wrapper, typeName)
+	fmt.Fprintf(&body, "// %s returns next with every method call traced as a\n// \"%s.<Method>\" span and recorded in instrumentor.MethodDurationMetric.\n", constructor, typeName)
+	fmt.Fprintf(&body, "func %s(next %s, inst *instrumentor.Instrumentor) %s {\n", constructor, typeName, typeName)
+	fmt.Fprintf(&body, "\treturn &%s{next: next, tracer: instrumentor.NewMethodTracer(inst, %q)}\n}\n", wrapper, typeName)
+
+	for _, m := range methods {
+		fileImports, err := usedImports(m)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, m.name, err)
+		}
+		for importPath, name := range fileImports {
+			imports[importPath] = name
+		}
+		needsBackground := p.writeMethod(&body, wrapper, m, fileImports)
+		if needsBackground {
+			if _, ok := imports["context"]; !ok {
+				imports["context"] = ""
+			}
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by otel-wrap. DO NOT EDIT.\n\npackage %s\n\nimport (\n", p.name)
+	paths := make([]string, 0, len(imports))
+	for importPath := range imports {
+		paths = append(paths, importPath)
+	}
+	// Standard library first, as goimports groups them.
+	sort.Slice(paths, func(i, j int) bool {
+		if si, sj := isStdlib(paths[i]), isStdlib(paths[j]); si != sj {
+			return si
+		}
+		return paths[i] < paths[j]
+	})
+	for i, importPath := range paths {
+		if i > 0 && isStdlib(paths[i-1]) && !isStdlib(importPath) {
+			src.WriteString("\n")
+		}
+		if name := imports[importPath]; name != "" {
+			fmt.Fprintf(&src, "\t%s %q\n", name, importPath)
+		} else {
+			fmt.Fprintf(&src, "\t%q\n", importPath)
+		}
+	}
+	src.WriteString(")\n\n")
+	src.Write(body.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+// writeMethod writes the wrapper method of m, reporting whether it uses
+// context.Background because m takes no context.
+func (p *pkg) writeMethod(b *bytes.Buffer, wrapper string, m method, fileImports map[string]string) bool {
+	contextName := ""
+	if name, ok := fileImports["context"]; ok {
+		contextName = importName("context", name)
+	}
+
+	var params, args []string
+	takesContext := false
+	i := 0
+	for _, field := range fieldList(m.typ.Params) {
+		typ := p.expr(field.Type)
+		for k := range max(len(field.Names), 1) {
+			name := fmt.Sprintf("a%d", i)
+			if k < len(field.Names) && !reservedNames[field.Names[k].Name] {
+				name = field.Names[k].Name
+			}
+			if i == 0 && contextName != "" && typ == contextName+".Context" {
+				name, takesContext = "ctx", true
+			}
+			params = append(params, name+" "+typ)
+			if _, variadic := field.Type.(*ast.Ellipsis); variadic {
+				name += "..."
+			}
+			args = append(args, name)
+			i++
+		}
+	}
+
+	var results []string
+	returnsError := false
+	resultFields := fieldList(m.typ.Results)
+	for j, field := range resultFields {
+		typ := p.expr(field.Type)
+		for range max(len(field.Names), 1) {
+			results = append(results, typ)
+		}
+		if j == len(resultFields)-1 && len(field.Names) <= 1 && typ == "error" {
+			returnsError = true
+		}
+	}
+
+	signature := strings.Join(params, ", ")
+	switch {
+	case returnsError:
+		named := make([]string, len(results))
+		for j, typ := range results[:len(results)-1] {
+			named[j] = "_ " + typ
+		}
+		named[len(named)-1] = "err error"
+		fmt.Fprintf(b, "\nfunc (w *%s) %s(%s) (%s) {\n", wrapper, m.name, signature, strings.Join(named, ", "))
+	case len(results) == 0:
+		fmt.Fprintf(b, "\nfunc (w *%s) %s(%s) {\n", wrapper, m.name, signature)
+	case len(results) == 1:
+		fmt.Fprintf(b, "\nfunc (w *%s) %s(%s) %s {\n", wrapper, m.name, signature, results[0])
+	default:
+		fmt.Fprintf(b, "\nfunc (w *%s) %s(%s) (%s) {\n", wrapper, m.name, signature, strings.Join(results, ", "))
+	}
+
+	if takesContext {
+		fmt.Fprintf(b, "\tctx, end := w.tracer.Start(ctx, %q)\n", m.name)
+	} else {
+		fmt.Fprintf(b, "\t_, end := w.tracer.Start(context.Background(), %q)\n", m.name)
+	}
+	if returnsError {
+		b.WriteString("\tdefer func() { end(err) }()\n")
+	} else {
+		b.WriteString("\tdefer end(nil)\n")
+	}
+	call := fmt.Sprintf("w.next.%s(%s)", m.name, strings.Join(args, ", "))
+	if len(results) > 0 {
+		fmt.Fprintf(b, "\treturn %s\n}\n", call)
+	} else {
+		fmt.Fprintf(b, "\t%s\n}\n", call)
+	}
+	return !takesContext
+}
+
+// reservedNames are parameter names the wrapper methods cannot reuse.
+var reservedNames = map[string]bool{"_": true, "w": true, "ctx": true, "end": true, "err": true}
+
+func fieldList(l *ast.FieldList) []*ast.Field {
+	if l == nil {
+		return nil
+	}
+	return l.List
+}
+
+// usedImports returns the imports of m's file that its signature refers to,
+// as path -> explicit name ("" when the import is unnamed).
+func usedImports(m method) (map[string]string, error) {
+	used := make(map[string]bool)
+	ast.Inspect(m.typ, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+
+	imports := make(map[string]string)
+	for _, spec := range m.file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		explicit := ""
+		if spec.Name != nil {
+			explicit = spec.Name.Name
+		}
+		name := importName(importPath, explicit)
+		if used[name] {
+			imports[importPath] = explicit
+			delete(used, name)
+		}
+	}
+	for name := range used {
+		return nil, fmt.Errorf("cannot resolve package %s: import it with an explicit name", name)
+	}
+	return imports, nil
+}
+
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// importName returns the name an import is referred to by: its explicit
+// name, or the last element of its path, skipping a major version suffix
+// ("github.com/redis/go-redis/v9" is guessed as "go-redis", which then does
+// not resolve and has to be named explicitly).
+func importName(importPath, explicit string) string {
+	if explicit != "" {
+		return explicit
+	}
+	dir, last := path.Split(importPath)
+	if majorVersion.MatchString(last) && dir != "" {
+		last = path.Base(dir)
+	}
+	return last
+}
+
+func isStdlib(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+func upperFirst(s string) string {
+	for i, r := range s {
+		return string(unicode.ToUpper(r)) + s[i+len(string(r)):]
+	}
+	return s
+}
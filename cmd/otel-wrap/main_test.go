@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The wrapper of wraptest.Store is checked in and tested there; it must
+// match what the generator produces now.
+func TestGenerate_MatchesCheckedInWrapper(t *testing.T) {
+	dir := filepath.Join("..", "..", "instrumentor", "internal", "wraptest")
+	got, err := generate(dir, "Store")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join(dir, "store_traced.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("generated wrapper differs from store_traced.go; run go generate ./instrumentor/...:\n%s", got)
+	}
+}
+
+func TestRun_WritesWrapperNextToInterface(t *testing.T) {
+	dir := t.TempDir()
+	writeSource(t, dir, `package repo
+
+import (
+	"context"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+type cache interface {
+	Client(ctx context.Context) *redis.Client
+}
+`)
+
+	var stderr bytes.Buffer
+	if code := run([]string{"-dir", dir, "-type", "cache"}, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr.String())
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "cache_traced.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`redis "github.com/redis/go-redis/v9"`,
+		"func newTracedCache(next cache, inst *instrumentor.Instrumentor) cache {",
+		"func (w *tracedCache) Client(ctx context.Context) *redis.Client {",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("generated code lacks %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeSource(t, dir, `package repo
+
+import (
+	"io"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type Closer interface {
+	io.Closer
+}
+
+type Unresolved interface {
+	Client() *redis.Client
+}
+
+type Generic[T any] interface {
+	Get() T
+}
+`)
+
+	for _, tc := range []struct {
+		args []string
+		code int
+		want string
+	}{
+		{nil, 2, "-type is required"},
+		{[]string{"-dir", dir, "-type", "Missing"}, 1, "interface Missing not found"},
+		{[]string{"-dir", dir, "-type", "Closer"}, 1, "embeds io.Closer"},
+		{[]string{"-dir", dir, "-type", "Unresolved"}, 1, "cannot resolve package redis"},
+		{[]string{"-dir", dir, "-type", "Generic"}, 1, "is generic"},
+	} {
+		var stderr bytes.Buffer
+		if code := run(tc.args, &stderr); code != tc.code || !strings.Contains(stderr.String(), tc.want) {
+			t.Errorf("run(%v) = %d, %q; want %d, %q", tc.args, code, stderr.String(), tc.code, tc.want)
+		}
+	}
+}
+
+func writeSource(t *testing.T, dir, src string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "repo.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
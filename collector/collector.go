@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/RodolfoBonis/go-otel-agent/logger"
 )
@@ -15,6 +16,8 @@ type MetricCollector struct {
 	business    *BusinessCollector
 	performance *PerformanceCollector
 	system      *SystemCollector
+	submit      Submitter
+	stretch     atomic.Int64
 
 	mu       sync.RWMutex
 	running  bool
@@ -43,6 +46,27 @@ func New(log logger.Logger, runtime *RuntimeCollector, business *BusinessCollect
 	}
 }
 
+// UseWorkers runs the periodic work of the runtime and system collectors
+// through submit instead of on their ticker goroutines. It must be called
+// before Start.
+func (mc *MetricCollector) UseWorkers(submit Submitter) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.submit = submit
+}
+
+// SetIntervalFactor makes the runtime and system collectors record every
+// factor-th interval only, e.g. to shed load while the agent is over its
+// resource budget. A factor of 1 restores the configured intervals.
+func (mc *MetricCollector) SetIntervalFactor(factor int) {
+	mc.stretch.Store(int64(max(factor, 1)))
+}
+
+// IntervalFactor returns the factor set with SetIntervalFactor, 1 by default.
+func (mc *MetricCollector) IntervalFactor() int {
+	return int(max(mc.stretch.Load(), 1))
+}
+
 // Start starts all sub-collectors.
 func (mc *MetricCollector) Start(ctx context.Context) error {
 	mc.mu.Lock()
@@ -54,6 +78,8 @@ func (mc *MetricCollector) Start(ctx context.Context) error {
 	mc.running = true
 
 	if mc.runtime != nil {
+		mc.runtime.schedule.submit = mc.submit
+		mc.runtime.schedule.stretch = &mc.stretch
 		go mc.runtime.Collect(ctx, mc.stopChan)
 	}
 	if mc.business != nil {
@@ -63,6 +89,8 @@ func (mc *MetricCollector) Start(ctx context.Context) error {
 		go mc.performance.Collect(ctx, mc.stopChan)
 	}
 	if mc.system != nil {
+		mc.system.schedule.submit = mc.submit
+		mc.system.schedule.stretch = &mc.stretch
 		go mc.system.Collect(ctx, mc.stopChan)
 	}
 
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package collector
+
+// diskUsage is not implemented on this platform.
+func diskUsage(string) (used, total uint64, ok bool) {
+	return 0, 0, false
+}
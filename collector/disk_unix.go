@@ -0,0 +1,17 @@
+//go:build unix
+
+package collector
+
+import "syscall"
+
+// diskUsage returns the used and total bytes of the filesystem holding path.
+func diskUsage(path string) (used, total uint64, ok bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, false
+	}
+	bsize := uint64(st.Bsize)
+	total = uint64(st.Blocks) * bsize
+	used = total - uint64(st.Bfree)*bsize
+	return used, total, total > 0
+}
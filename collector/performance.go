@@ -16,8 +16,6 @@ type PerformanceCollector struct {
 	p99Latency        metric.Float64Gauge
 	requestsPerSecond metric.Float64Gauge
 	messagesPerSecond metric.Float64Gauge
-	cpuUtilization    metric.Float64Gauge
-	memoryUtilization metric.Float64Gauge
 	cacheHitRate      metric.Float64Gauge
 	cacheMissRate     metric.Float64Gauge
 }
@@ -63,18 +61,6 @@ func NewPerformanceCollector(meter metric.Meter, interval time.Duration) (*Perfo
 		return nil, err
 	}
 
-	pc.cpuUtilization, err = meter.Float64Gauge("cpu_utilization_percent",
-		metric.WithDescription("Current CPU utilization percentage"), metric.WithUnit("%"))
-	if err != nil {
-		return nil, err
-	}
-
-	pc.memoryUtilization, err = meter.Float64Gauge("memory_utilization_percent",
-		metric.WithDescription("Current memory utilization percentage"), metric.WithUnit("%"))
-	if err != nil {
-		return nil, err
-	}
-
 	pc.cacheHitRate, err = meter.Float64Gauge("cache_hit_rate_percent",
 		metric.WithDescription("Current cache hit rate percentage"), metric.WithUnit("%"))
 	if err != nil {
@@ -0,0 +1,224 @@
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Linux sources of process and container resource usage.
+const (
+	procSelfStat       = "/proc/self/stat"
+	procSelfIO         = "/proc/self/io"
+	procMeminfo        = "/proc/meminfo"
+	cgroupV2MemCurrent = "/sys/fs/cgroup/memory.current"
+	cgroupV2MemMax     = "/sys/fs/cgroup/memory.max"
+	cgroupV1MemUsage   = "/sys/fs/cgroup/memory/memory.usage_in_bytes"
+	cgroupV1MemLimit   = "/sys/fs/cgroup/memory/memory.limit_in_bytes"
+
+	// userHZ is the clock tick of /proc/<pid>/stat times, 100 on every
+	// mainstream Linux architecture.
+	userHZ = 100
+)
+
+// cpuSampler turns the process's cumulative CPU time into a utilization
+// percentage of the CPUs it may use: the cgroup quota when there is one,
+// otherwise every CPU of the host.
+type cpuSampler struct {
+	lastCPU  time.Duration
+	lastWall time.Time
+}
+
+// sample returns the utilization since the previous call; the first call
+// only establishes the baseline.
+func (s *cpuSampler) sample(now time.Time) (float64, bool) {
+	cpu, ok := processCPUTime()
+	if !ok {
+		return 0, false
+	}
+	lastCPU, lastWall := s.lastCPU, s.lastWall
+	s.lastCPU, s.lastWall = cpu, now
+	if lastWall.IsZero() {
+		return 0, false
+	}
+
+	wall := now.Sub(lastWall)
+	if wall <= 0 {
+		return 0, false
+	}
+	cores, ok := cgroupCPUQuota()
+	if !ok {
+		cores = float64(runtime.NumCPU())
+	}
+	return float64(cpu-lastCPU) / float64(wall) / cores * 100, true
+}
+
+// processCPUTime returns the user plus system CPU time of this process.
+func processCPUTime() (time.Duration, bool) {
+	data, err := os.ReadFile(procSelfStat)
+	if err != nil {
+		return 0, false
+	}
+	// The command name in field 2 may contain spaces; fields after it are
+	// space separated, utime and stime being the 14th and 15th overall.
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return 0, false
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 13 {
+		return 0, false
+	}
+	utime, err1 := strconv.ParseUint(fields[11], 10, 64)
+	stime, err2 := strconv.ParseUint(fields[12], 10, 64)
+	if err1 != nil || err2 != nil {
+		return 0, false
+	}
+	return time.Duration(utime+stime) * time.Second / userHZ, true
+}
+
+// memoryUsage returns the bytes in use and the limit they count against: the
+// cgroup's usage and limit inside a limited container, otherwise the host's
+// used and total memory.
+func memoryUsage() (used, limit uint64, ok bool) {
+	for _, files := range [][2]string{
+		{cgroupV2MemCurrent, cgroupV2MemMax},
+		{cgroupV1MemUsage, cgroupV1MemLimit},
+	} {
+		usage, err := readUint(files[0])
+		if err != nil {
+			continue
+		}
+		cgLimit, err := readUint(files[1])
+		total, hostOK := hostMemTotal()
+		// "max" (v2) fails to parse and v1 reports an unlimited cgroup as
+		// a huge number; both mean the host's memory is the limit.
+		if err != nil || (hostOK && cgLimit >= total) {
+			if !hostOK {
+				return 0, 0, false
+			}
+			cgLimit = total
+		}
+		return usage, cgLimit, cgLimit > 0
+	}
+
+	total, available, ok := hostMeminfo()
+	if !ok || total == 0 {
+		return 0, 0, false
+	}
+	return total - available, total, true
+}
+
+// Limits are the memory and CPUs available to the process.
+type Limits struct {
+	// MemoryBytes is the cgroup memory limit, or the host's memory when
+	// the process is not in a limited cgroup. 0 when unknown.
+	MemoryBytes uint64
+	// CPUs is the cgroup CPU quota in cores, or runtime.NumCPU.
+	CPUs float64
+	// ContainerMemory reports whether MemoryBytes is a cgroup limit.
+	ContainerMemory bool
+	// ContainerCPU reports whether CPUs is a cgroup quota.
+	ContainerCPU bool
+}
+
+// ReadLimits returns the memory and CPU limits of the process, read from its
+// cgroup (v2, then v1) and falling back to the host.
+func ReadLimits() Limits {
+	var l Limits
+	total, hostOK := hostMemTotal()
+	for _, path := range []string{cgroupV2MemMax, cgroupV1MemLimit} {
+		// "max" (v2) fails to parse and v1 reports an unlimited cgroup as
+		// a huge number
+		if limit, err := readUint(path); err == nil && limit > 0 && (!hostOK || limit < total) {
+			l.MemoryBytes, l.ContainerMemory = limit, true
+			break
+		}
+	}
+	if !l.ContainerMemory && hostOK {
+		l.MemoryBytes = total
+	}
+
+	l.CPUs, l.ContainerCPU = cgroupCPUQuota()
+	if !l.ContainerCPU {
+		l.CPUs = float64(runtime.NumCPU())
+	}
+	return l
+}
+
+func hostMemTotal() (uint64, bool) {
+	total, _, ok := hostMeminfo()
+	return total, ok
+}
+
+// hostMeminfo returns MemTotal and MemAvailable in bytes.
+func hostMeminfo() (total, available uint64, ok bool) {
+	f, err := os.Open(procMeminfo)
+	if err != nil {
+		return 0, 0, false
+	}
+	defer f.Close()
+
+	var found int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() && found < 2 {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total = kb * 1024
+			found++
+		case "MemAvailable:":
+			available = kb * 1024
+			found++
+		}
+	}
+	return total, available, found == 2
+}
+
+// processIO returns the bytes this process caused to be read from and
+// written to storage.
+func processIO() (read, written uint64, ok bool) {
+	f, err := os.Open(procSelfIO)
+	if err != nil {
+		return 0, 0, false
+	}
+	defer f.Close()
+
+	var found int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), ":")
+		n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "read_bytes":
+			read = n
+			found++
+		case "write_bytes":
+			written = n
+			found++
+		}
+	}
+	return read, written, found == 2
+}
+
+func readUint(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
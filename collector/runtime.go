@@ -2,81 +2,212 @@ package collector
 
 import (
 	"context"
+	"math"
+	"os"
 	"runtime"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 )
 
-// RuntimeCollector collects Go runtime metrics.
+// runtime/metrics names read on every collection. Unlike
+// runtime.ReadMemStats, reading them does not stop the world.
+const (
+	rmHeapObjects   = "/memory/classes/heap/objects:bytes"
+	rmHeapUnused    = "/memory/classes/heap/unused:bytes"
+	rmHeapFree      = "/memory/classes/heap/free:bytes"
+	rmHeapReleased  = "/memory/classes/heap/released:bytes"
+	rmHeapStacks    = "/memory/classes/heap/stacks:bytes"
+	rmOSStacks      = "/memory/classes/os-stacks:bytes"
+	rmTotal         = "/memory/classes/total:bytes"
+	rmGCCycles      = "/gc/cycles/total:gc-cycles"
+	rmGoroutines    = "/sched/goroutines:goroutines"
+	rmGOMAXPROCS    = "/sched/gomaxprocs:threads"
+	rmGCCPU         = "/cpu/classes/gc/total:cpu-seconds"
+	rmTotalCPU      = "/cpu/classes/total:cpu-seconds"
+	rmMutexWait     = "/sync/mutex/wait/total:seconds"
+	rmGCPauses      = "/sched/pauses/total/gc:seconds"
+	rmSchedLatency  = "/sched/latencies:seconds"
+	cgroupV2CPUMax  = "/sys/fs/cgroup/cpu.max"
+	cgroupV1Quota   = "/sys/fs/cgroup/cpu/cpu.cfs_quota_us"
+	cgroupV1Period  = "/sys/fs/cgroup/cpu/cpu.cfs_period_us"
+	defaultInterval = 15 * time.Second
+)
+
+// blockProfileRate is the last rate passed to SetBlockProfileRate; the
+// runtime has no getter for it. -1 means it was never set through here.
+var blockProfileRate = func() *atomic.Int64 {
+	var v atomic.Int64
+	v.Store(-1)
+	return &v
+}()
+
+// schedQuantiles are reported for go_sched_latency_seconds.
+var schedQuantiles = []float64{0.5, 0.9, 0.99}
+
+// RuntimeCollector collects Go runtime metrics from runtime/metrics.
+// Gauges and counters are observed in a callback at each metric collection;
+// the GC pause histogram is fed every interval with the pauses observed
+// since the previous read.
 type RuntimeCollector struct {
-	interval      time.Duration
-	memAlloc      metric.Int64Gauge
-	memSys        metric.Int64Gauge
-	memHeapAlloc  metric.Int64Gauge
-	memHeapSys    metric.Int64Gauge
-	memStack      metric.Int64Gauge
-	memGCCount    metric.Int64Counter
-	memGCPause    metric.Float64Histogram
-	goroutines    metric.Int64Gauge
-	gcCPUFraction metric.Float64Gauge
+	interval time.Duration
+
+	mu          sync.Mutex
+	samples     []metrics.Sample
+	index       map[string]int
+	schedCounts []uint64
+
+	gcPause     metric.Float64Histogram
+	pauses      []metrics.Sample
+	pauseCounts []uint64
+
+	schedule schedule
 }
 
 // NewRuntimeCollector creates a new runtime metrics collector.
 func NewRuntimeCollector(meter metric.Meter, interval time.Duration) (*RuntimeCollector, error) {
-	rc := &RuntimeCollector{interval: interval}
-	var err error
-
-	rc.memAlloc, err = meter.Int64Gauge("go_memory_alloc_bytes",
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	rc := &RuntimeCollector{
+		interval: interval,
+		index:    make(map[string]int),
+		pauses:   []metrics.Sample{{Name: rmGCPauses}},
+	}
+	for i, name := range []string{
+		rmHeapObjects, rmHeapUnused, rmHeapFree, rmHeapReleased, rmHeapStacks, rmOSStacks,
+		rmTotal, rmGCCycles, rmGoroutines, rmGOMAXPROCS, rmGCCPU, rmTotalCPU, rmMutexWait,
+		rmSchedLatency,
+	} {
+		rc.samples = append(rc.samples, metrics.Sample{Name: name})
+		rc.index[name] = i
+	}
+	memAlloc, err := meter.Int64ObservableGauge("go_memory_alloc_bytes",
 		metric.WithDescription("Current allocated memory in bytes"), metric.WithUnit("By"))
 	if err != nil {
 		return nil, err
 	}
 
-	rc.memSys, err = meter.Int64Gauge("go_memory_sys_bytes",
+	memSys, err := meter.Int64ObservableGauge("go_memory_sys_bytes",
 		metric.WithDescription("Total system memory in bytes"), metric.WithUnit("By"))
 	if err != nil {
 		return nil, err
 	}
 
-	rc.memHeapAlloc, err = meter.Int64Gauge("go_memory_heap_alloc_bytes",
+	memHeapAlloc, err := meter.Int64ObservableGauge("go_memory_heap_alloc_bytes",
 		metric.WithDescription("Current heap allocated memory in bytes"), metric.WithUnit("By"))
 	if err != nil {
 		return nil, err
 	}
 
-	rc.memHeapSys, err = meter.Int64Gauge("go_memory_heap_sys_bytes",
+	memHeapSys, err := meter.Int64ObservableGauge("go_memory_heap_sys_bytes",
 		metric.WithDescription("Total heap system memory in bytes"), metric.WithUnit("By"))
 	if err != nil {
 		return nil, err
 	}
 
-	rc.memStack, err = meter.Int64Gauge("go_memory_stack_bytes",
+	memStack, err := meter.Int64ObservableGauge("go_memory_stack_bytes",
 		metric.WithDescription("Current stack memory in bytes"), metric.WithUnit("By"))
 	if err != nil {
 		return nil, err
 	}
 
-	rc.memGCCount, err = meter.Int64Counter("go_gc_collections_total",
+	gcCount, err := meter.Int64ObservableCounter("go_gc_collections_total",
 		metric.WithDescription("Total number of GC collections"))
 	if err != nil {
 		return nil, err
 	}
 
-	rc.memGCPause, err = meter.Float64Histogram("go_gc_pause_seconds",
+	goroutines, err := meter.Int64ObservableGauge("go_goroutines",
+		metric.WithDescription("Current number of goroutines"))
+	if err != nil {
+		return nil, err
+	}
+
+	gcCPUFraction, err := meter.Float64ObservableGauge("go_gc_cpu_fraction",
+		metric.WithDescription("Fraction of CPU time used by GC"))
+	if err != nil {
+		return nil, err
+	}
+
+	gomaxprocs, err := meter.Int64ObservableGauge("go_gomaxprocs",
+		metric.WithDescription("Current GOMAXPROCS setting"))
+	if err != nil {
+		return nil, err
+	}
+
+	cpuQuota, err := meter.Float64ObservableGauge("go_cpu_quota_cores",
+		metric.WithDescription("CPU limit of the container's cgroup in cores; absent when unlimited"))
+	if err != nil {
+		return nil, err
+	}
+
+	mutexWait, err := meter.Float64ObservableCounter("go_sync_mutex_wait_seconds_total",
+		metric.WithDescription("Time goroutines spent blocked on sync.Mutex and sync.RWMutex"), metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	mutexProfileFraction, err := meter.Int64ObservableGauge("go_mutex_profile_fraction",
+		metric.WithDescription("Current runtime.SetMutexProfileFraction rate (0 = mutex profiling off)"))
+	if err != nil {
+		return nil, err
+	}
+
+	blockRate, err := meter.Int64ObservableGauge("go_block_profile_rate",
+		metric.WithDescription("Block profile rate set through collector.SetBlockProfileRate"))
+	if err != nil {
+		return nil, err
+	}
+
+	rc.gcPause, err = meter.Float64Histogram("go_gc_pause_seconds",
 		metric.WithDescription("GC pause duration in seconds"), metric.WithUnit("s"))
 	if err != nil {
 		return nil, err
 	}
 
-	rc.goroutines, err = meter.Int64Gauge("go_goroutines",
-		metric.WithDescription("Current number of goroutines"))
+	schedLatency, err := meter.Float64ObservableGauge("go_sched_latency_seconds",
+		metric.WithDescription("Time goroutines spent runnable before running, by quantile since the previous collection"),
+		metric.WithUnit("s"))
 	if err != nil {
 		return nil, err
 	}
 
-	rc.gcCPUFraction, err = meter.Float64Gauge("go_gc_cpu_fraction",
-		metric.WithDescription("Fraction of CPU time used by GC"))
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+		metrics.Read(rc.samples)
+
+		heapObjects := rc.intValue(rmHeapObjects)
+		o.ObserveInt64(memAlloc, heapObjects)
+		o.ObserveInt64(memHeapAlloc, heapObjects)
+		o.ObserveInt64(memSys, rc.intValue(rmTotal))
+		o.ObserveInt64(memHeapSys, heapObjects+rc.intValue(rmHeapUnused)+rc.intValue(rmHeapFree)+rc.intValue(rmHeapReleased))
+		o.ObserveInt64(memStack, rc.intValue(rmHeapStacks)+rc.intValue(rmOSStacks))
+		o.ObserveInt64(gcCount, rc.intValue(rmGCCycles))
+		o.ObserveInt64(goroutines, rc.intValue(rmGoroutines))
+		o.ObserveInt64(gomaxprocs, rc.intValue(rmGOMAXPROCS))
+		if total := rc.floatValue(rmTotalCPU); total > 0 {
+			o.ObserveFloat64(gcCPUFraction, rc.floatValue(rmGCCPU)/total)
+		}
+		o.ObserveFloat64(mutexWait, rc.floatValue(rmMutexWait))
+		o.ObserveInt64(mutexProfileFraction, int64(runtime.SetMutexProfileFraction(-1)))
+		if cores, ok := cgroupCPUQuota(); ok {
+			o.ObserveFloat64(cpuQuota, cores)
+		}
+		if rate := blockProfileRate.Load(); rate >= 0 {
+			o.ObserveInt64(blockRate, rate)
+		}
+		rc.observeSchedLatency(o, schedLatency)
+		return nil
+	}, memAlloc, memSys, memHeapAlloc, memHeapSys, memStack, gcCount, goroutines,
+		gcCPUFraction, gomaxprocs, cpuQuota, mutexWait, mutexProfileFraction, blockRate, schedLatency)
 	if err != nil {
 		return nil, err
 	}
@@ -84,14 +215,62 @@ func NewRuntimeCollector(meter metric.Meter, interval time.Duration) (*RuntimeCo
 	return rc, nil
 }
 
-// Collect runs the runtime metric collection loop.
+// SetBlockProfileRate calls runtime.SetBlockProfileRate and reports the rate
+// as go_block_profile_rate, since the runtime cannot be asked for it.
+func SetBlockProfileRate(rate int) {
+	runtime.SetBlockProfileRate(rate)
+	blockProfileRate.Store(int64(max(rate, 0)))
+}
+
+func (rc *RuntimeCollector) intValue(name string) int64 {
+	if v := rc.samples[rc.index[name]].Value; v.Kind() == metrics.KindUint64 {
+		return int64(v.Uint64())
+	}
+	return 0
+}
+
+func (rc *RuntimeCollector) floatValue(name string) float64 {
+	if v := rc.samples[rc.index[name]].Value; v.Kind() == metrics.KindFloat64 {
+		return v.Float64()
+	}
+	return 0
+}
+
+// observeSchedLatency reports quantiles of the scheduling latencies counted
+// since the previous collection. Nothing is reported on the first collection
+// or when no goroutine was scheduled in between.
+func (rc *RuntimeCollector) observeSchedLatency(o metric.Observer, gauge metric.Float64ObservableGauge) {
+	v := rc.samples[rc.index[rmSchedLatency]].Value
+	if v.Kind() != metrics.KindFloat64Histogram {
+		return
+	}
+	h := v.Float64Histogram()
+	delta, total := countDeltas(rc.schedCounts, h.Counts)
+	rc.schedCounts = append(rc.schedCounts[:0], h.Counts...)
+	if total == 0 {
+		return
+	}
+
+	for _, q := range schedQuantiles {
+		rank := uint64(math.Ceil(q * float64(total)))
+		var seen uint64
+		for b, n := range delta {
+			if seen += n; seen >= rank {
+				o.ObserveFloat64(gauge, bucketValue(h.Buckets, b),
+					metric.WithAttributes(attribute.String("quantile", strconv.FormatFloat(q, 'f', -1, 64))))
+				break
+			}
+		}
+	}
+}
+
+// Collect feeds the GC pause histogram every interval until ctx is done or
+// stop is closed.
 func (rc *RuntimeCollector) Collect(ctx context.Context, stop <-chan struct{}) {
 	ticker := time.NewTicker(rc.interval)
 	defer ticker.Stop()
 
-	var lastNumGC uint32
-	var lastPauseTotal time.Duration
-
+	rc.recordPauses(ctx)
 	for {
 		select {
 		case <-ctx.Done():
@@ -99,28 +278,85 @@ func (rc *RuntimeCollector) Collect(ctx context.Context, stop <-chan struct{}) {
 		case <-stop:
 			return
 		case <-ticker.C:
-			var m runtime.MemStats
-			runtime.ReadMemStats(&m)
-
-			rc.memAlloc.Record(ctx, int64(m.Alloc))
-			rc.memSys.Record(ctx, int64(m.Sys))
-			rc.memHeapAlloc.Record(ctx, int64(m.HeapAlloc))
-			rc.memHeapSys.Record(ctx, int64(m.HeapSys))
-			rc.memStack.Record(ctx, int64(m.StackSys))
-			rc.goroutines.Record(ctx, int64(runtime.NumGoroutine()))
-			rc.gcCPUFraction.Record(ctx, m.GCCPUFraction)
-
-			if m.NumGC > lastNumGC {
-				rc.memGCCount.Add(ctx, int64(m.NumGC-lastNumGC))
-				lastNumGC = m.NumGC
-			}
+			rc.schedule.run(ctx, rc.recordPauses)
+		}
+	}
+}
 
-			totalPauseNs := time.Duration(m.PauseTotalNs)
-			if totalPauseNs > lastPauseTotal {
-				pauseDiff := totalPauseNs - lastPauseTotal
-				rc.memGCPause.Record(ctx, pauseDiff.Seconds())
-				lastPauseTotal = totalPauseNs
-			}
+// recordPauses records the GC pauses counted since the last call at the
+// upper bound of their runtime bucket. The first call only establishes the
+// baseline.
+func (rc *RuntimeCollector) recordPauses(ctx context.Context) {
+	metrics.Read(rc.pauses)
+	v := rc.pauses[0].Value
+	if v.Kind() != metrics.KindFloat64Histogram {
+		return
+	}
+	h := v.Float64Histogram()
+	delta, _ := countDeltas(rc.pauseCounts, h.Counts)
+	rc.pauseCounts = append(rc.pauseCounts[:0], h.Counts...)
+	for b, n := range delta {
+		value := bucketValue(h.Buckets, b)
+		for range n {
+			rc.gcPause.Record(ctx, value)
+		}
+	}
+}
+
+// countDeltas returns the per-bucket increase from last to counts and its
+// sum. Without a matching previous read there is no delta.
+func countDeltas(last, counts []uint64) ([]uint64, uint64) {
+	if len(last) != len(counts) {
+		return nil, 0
+	}
+	delta := make([]uint64, len(counts))
+	var total uint64
+	for b := range counts {
+		delta[b] = counts[b] - last[b]
+		total += delta[b]
+	}
+	return delta, total
+}
+
+// bucketValue is a representative value for bucket b, whose bounds are
+// buckets[b] and buckets[b+1]; infinite bounds fall back to the finite one.
+func bucketValue(buckets []float64, b int) float64 {
+	if upper := buckets[b+1]; !math.IsInf(upper, 1) {
+		return upper
+	}
+	return buckets[b]
+}
+
+// cgroupCPUQuota returns the container's CPU limit in cores from cgroup v2
+// or v1, and false when there is no limit or no cgroup.
+func cgroupCPUQuota() (float64, bool) {
+	if data, err := os.ReadFile(cgroupV2CPUMax); err == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) != 2 || fields[0] == "max" {
+			return 0, false
 		}
+		return quotaCores(fields[0], fields[1])
+	}
+
+	quota, err := os.ReadFile(cgroupV1Quota)
+	if err != nil {
+		return 0, false
+	}
+	period, err := os.ReadFile(cgroupV1Period)
+	if err != nil {
+		return 0, false
+	}
+	return quotaCores(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+func quotaCores(quota, period string) (float64, bool) {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0, false
 	}
+	return q / p, true
 }
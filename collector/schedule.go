@@ -0,0 +1,45 @@
+package collector
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Submitter queues work on a bounded worker pool, returning an error when
+// the work is rejected (e.g. because the queue is full).
+type Submitter func(task func(context.Context)) error
+
+// schedule runs a collector's periodic work on the worker pool when there is
+// one, so a slow source (e.g. /proc on a loaded host) does not hold up the
+// ticker. A run is skipped while the previous one is still queued or
+// running, and when the pool rejects it; the next tick catches up. While
+// stretch is above 1, only one tick in stretch runs.
+type schedule struct {
+	submit  Submitter
+	stretch *atomic.Int64
+	ticks   int64
+	busy    atomic.Bool
+}
+
+func (s *schedule) run(ctx context.Context, work func(context.Context)) {
+	s.ticks++
+	if s.stretch != nil {
+		if n := s.stretch.Load(); n > 1 && s.ticks%n != 0 {
+			return
+		}
+	}
+	if s.submit == nil {
+		work(ctx)
+		return
+	}
+	if !s.busy.CompareAndSwap(false, true) {
+		return
+	}
+	err := s.submit(func(context.Context) {
+		defer s.busy.Store(false)
+		work(ctx)
+	})
+	if err != nil {
+		s.busy.Store(false)
+	}
+}
@@ -2,8 +2,11 @@ package collector
 
 import (
 	"context"
+	"database/sql"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 )
 
@@ -11,17 +14,72 @@ import (
 type SystemCollector struct {
 	interval         time.Duration
 	dbConnections    metric.Int64Gauge
+	dbIdle           metric.Int64Gauge
+	dbMaxOpen        metric.Int64Gauge
+	dbWaitCount      metric.Int64Gauge
 	redisConnections metric.Int64Gauge
 	httpConnections  metric.Int64Gauge
 	queueDepth       metric.Int64Gauge
 	queueRate        metric.Float64Gauge
 	healthScore      metric.Float64Gauge
 	uptime           metric.Int64Gauge
+
+	cpu       bool
+	memory    bool
+	diskPaths []string
+
+	cpuUtilization    metric.Float64Gauge
+	memoryUtilization metric.Float64Gauge
+	memoryUsage       metric.Int64Gauge
+	memoryLimit       metric.Int64Gauge
+	diskUsage         metric.Int64Gauge
+	diskCapacity      metric.Int64Gauge
+	diskUtilization   metric.Float64Gauge
+	diskRead          metric.Int64Counter
+	diskWritten       metric.Int64Counter
+
+	cpuSampler          cpuSampler
+	lastRead, lastWrite uint64
+
+	dbMu    sync.RWMutex
+	dbStats map[string]func() sql.DBStats
+
+	schedule schedule
+}
+
+// SystemCollectorOption enables optional resource metrics of a SystemCollector.
+type SystemCollectorOption func(*SystemCollector)
+
+// WithCPUMetrics reports the process's CPU utilization as a percentage of the
+// CPUs it may use, the cgroup quota inside a limited container.
+func WithCPUMetrics() SystemCollectorOption {
+	return func(sc *SystemCollector) {
+		sc.cpu = true
+	}
+}
+
+// WithMemoryMetrics reports memory usage against the cgroup limit, or against
+// the host's memory outside a limited container.
+func WithMemoryMetrics() SystemCollectorOption {
+	return func(sc *SystemCollector) {
+		sc.memory = true
+	}
+}
+
+// WithDiskMetrics reports the usage of the filesystems holding paths and the
+// process's storage IO.
+func WithDiskMetrics(paths ...string) SystemCollectorOption {
+	return func(sc *SystemCollector) {
+		sc.diskPaths = paths
+	}
 }
 
 // NewSystemCollector creates a new system metrics collector.
-func NewSystemCollector(meter metric.Meter, interval time.Duration) (*SystemCollector, error) {
-	sc := &SystemCollector{interval: interval}
+func NewSystemCollector(meter metric.Meter, interval time.Duration, opts ...SystemCollectorOption) (*SystemCollector, error) {
+	sc := &SystemCollector{interval: interval, dbStats: make(map[string]func() sql.DBStats)}
+	for _, opt := range opts {
+		opt(sc)
+	}
 	var err error
 
 	sc.dbConnections, err = meter.Int64Gauge("database_connections_active",
@@ -30,6 +88,24 @@ func NewSystemCollector(meter metric.Meter, interval time.Duration) (*SystemColl
 		return nil, err
 	}
 
+	sc.dbIdle, err = meter.Int64Gauge("database_connections_idle",
+		metric.WithDescription("Current idle database connections"))
+	if err != nil {
+		return nil, err
+	}
+
+	sc.dbMaxOpen, err = meter.Int64Gauge("database_connections_max",
+		metric.WithDescription("Maximum open database connections"))
+	if err != nil {
+		return nil, err
+	}
+
+	sc.dbWaitCount, err = meter.Int64Gauge("database_connections_wait_total",
+		metric.WithDescription("Total number of waits for a database connection"))
+	if err != nil {
+		return nil, err
+	}
+
 	sc.redisConnections, err = meter.Int64Gauge("redis_connections_active",
 		metric.WithDescription("Current active Redis connections"))
 	if err != nil {
@@ -66,6 +142,60 @@ func NewSystemCollector(meter metric.Meter, interval time.Duration) (*SystemColl
 		return nil, err
 	}
 
+	sc.cpuUtilization, err = meter.Float64Gauge("cpu_utilization_percent",
+		metric.WithDescription("Current CPU utilization percentage"), metric.WithUnit("%"))
+	if err != nil {
+		return nil, err
+	}
+
+	sc.memoryUtilization, err = meter.Float64Gauge("memory_utilization_percent",
+		metric.WithDescription("Current memory utilization percentage"), metric.WithUnit("%"))
+	if err != nil {
+		return nil, err
+	}
+
+	sc.memoryUsage, err = meter.Int64Gauge("memory_usage_bytes",
+		metric.WithDescription("Memory in use by the container, or the host outside one"), metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+
+	sc.memoryLimit, err = meter.Int64Gauge("memory_limit_bytes",
+		metric.WithDescription("Memory limit of the container, or the host's memory outside one"), metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+
+	sc.diskUsage, err = meter.Int64Gauge("disk_usage_bytes",
+		metric.WithDescription("Used bytes of the filesystem"), metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+
+	sc.diskCapacity, err = meter.Int64Gauge("disk_capacity_bytes",
+		metric.WithDescription("Total bytes of the filesystem"), metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+
+	sc.diskUtilization, err = meter.Float64Gauge("disk_utilization_percent",
+		metric.WithDescription("Current filesystem utilization percentage"), metric.WithUnit("%"))
+	if err != nil {
+		return nil, err
+	}
+
+	sc.diskRead, err = meter.Int64Counter("disk_io_read_bytes_total",
+		metric.WithDescription("Bytes the process read from storage"), metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+
+	sc.diskWritten, err = meter.Int64Counter("disk_io_write_bytes_total",
+		metric.WithDescription("Bytes the process wrote to storage"), metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+
 	return sc, nil
 }
 
@@ -75,6 +205,7 @@ func (sc *SystemCollector) Collect(ctx context.Context, stop <-chan struct{}) {
 	defer ticker.Stop()
 
 	startTime := time.Now()
+	sc.recordResources(ctx, startTime)
 
 	for {
 		select {
@@ -83,7 +214,71 @@ func (sc *SystemCollector) Collect(ctx context.Context, stop <-chan struct{}) {
 		case <-stop:
 			return
 		case <-ticker.C:
-			sc.uptime.Record(ctx, int64(time.Since(startTime).Seconds()))
+			sc.schedule.run(ctx, func(ctx context.Context) {
+				sc.uptime.Record(ctx, int64(time.Since(startTime).Seconds()))
+				sc.recordDBStats(ctx)
+				sc.recordResources(ctx, time.Now())
+			})
+		}
+	}
+}
+
+// recordResources records the enabled CPU, memory and disk metrics. Sources
+// that are unavailable on this platform are skipped.
+func (sc *SystemCollector) recordResources(ctx context.Context, now time.Time) {
+	if sc.cpu {
+		if pct, ok := sc.cpuSampler.sample(now); ok {
+			sc.cpuUtilization.Record(ctx, pct)
+		}
+	}
+
+	if sc.memory {
+		if used, limit, ok := memoryUsage(); ok {
+			sc.memoryUsage.Record(ctx, int64(used))
+			sc.memoryLimit.Record(ctx, int64(limit))
+			sc.memoryUtilization.Record(ctx, float64(used)/float64(limit)*100)
+		}
+	}
+
+	if len(sc.diskPaths) == 0 {
+		return
+	}
+	for _, path := range sc.diskPaths {
+		used, total, ok := diskUsage(path)
+		if !ok {
+			continue
 		}
+		attrs := metric.WithAttributes(attribute.String("path", path))
+		sc.diskUsage.Record(ctx, int64(used), attrs)
+		sc.diskCapacity.Record(ctx, int64(total), attrs)
+		sc.diskUtilization.Record(ctx, float64(used)/float64(total)*100, attrs)
+	}
+	if read, written, ok := processIO(); ok {
+		sc.diskRead.Add(ctx, int64(read-sc.lastRead))
+		sc.diskWritten.Add(ctx, int64(written-sc.lastWrite))
+		sc.lastRead, sc.lastWrite = read, written
+	}
+}
+
+// RegisterDBStats adds a connection pool whose statistics are reported on
+// every collection tick, labelled with the given pool name. Registering the
+// same name again replaces the previous source.
+func (sc *SystemCollector) RegisterDBStats(name string, stats func() sql.DBStats) {
+	sc.dbMu.Lock()
+	defer sc.dbMu.Unlock()
+	sc.dbStats[name] = stats
+}
+
+func (sc *SystemCollector) recordDBStats(ctx context.Context) {
+	sc.dbMu.RLock()
+	defer sc.dbMu.RUnlock()
+
+	for name, statsFn := range sc.dbStats {
+		stats := statsFn()
+		attrs := metric.WithAttributes(attribute.String("db.client.connection.pool.name", name))
+		sc.dbConnections.Record(ctx, int64(stats.InUse), attrs)
+		sc.dbIdle.Record(ctx, int64(stats.Idle), attrs)
+		sc.dbMaxOpen.Record(ctx, int64(stats.MaxOpenConnections), attrs)
+		sc.dbWaitCount.Record(ctx, stats.WaitCount, attrs)
 	}
 }
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"math"
+	"time"
+)
+
+// tuningTier holds the defaults for processes with up to maxMemory bytes.
+type tuningTier struct {
+	maxMemory       uint64
+	minCPUs         float64
+	queueSize       int
+	batchSize       int
+	workerQueue     int
+	runtimeInterval time.Duration
+}
+
+// tuningTiers go from small pods to large nodes. The third tier matches the
+// static defaults; the last one needs enough CPUs to drain its queues.
+var tuningTiers = []tuningTier{
+	{maxMemory: 512 << 20, queueSize: 512, batchSize: 128, workerQueue: 250, runtimeInterval: 30 * time.Second},
+	{maxMemory: 2 << 30, queueSize: 1024, batchSize: 256, workerQueue: 500, runtimeInterval: 15 * time.Second},
+	{maxMemory: 8 << 30, queueSize: 2048, batchSize: 512, workerQueue: 1000, runtimeInterval: 10 * time.Second},
+	{maxMemory: math.MaxUint64, minCPUs: 4, queueSize: 8192, batchSize: 1024, workerQueue: 2000, runtimeInterval: 10 * time.Second},
+}
+
+// maxTunedWorkers caps the worker pool picked from the CPU count.
+const maxTunedWorkers = 8
+
+// AutoTune sizes the batch queues and batches of traces and logs, the worker
+// pool and the runtime metric interval for a process with memoryBytes of
+// memory and cpus CPUs (its cgroup limits in a container): small pods get
+// small queues and less frequent runtime metrics, large nodes deeper queues.
+// Only keys whose provenance is still SourceDefault are changed, so explicit
+// configuration always wins; their provenance becomes SourceAuto. It returns
+// the changed keys. Unknown resources (0) leave the defaults alone.
+func (c *Config) AutoTune(memoryBytes uint64, cpus float64, p Provenance) []string {
+	if memoryBytes == 0 || cpus <= 0 {
+		return nil
+	}
+
+	var tier tuningTier
+	for i, t := range tuningTiers {
+		if memoryBytes <= t.maxMemory || i == len(tuningTiers)-1 {
+			tier = t
+			if cpus < t.minCPUs {
+				tier = tuningTiers[i-1]
+			}
+			break
+		}
+	}
+
+	var changed []string
+	setInt := func(key string, field *int, v int) {
+		if p[key] == SourceDefault && *field != v {
+			*field = v
+			changed = append(changed, key)
+		}
+	}
+
+	setInt("traces.queue_size", &c.Traces.QueueSize, tier.queueSize)
+	setInt("traces.batch_size", &c.Traces.BatchSize, min(tier.batchSize, c.Traces.QueueSize))
+	setInt("traces.max_export_batch", &c.Traces.MaxExportBatch, min(tier.batchSize, c.Traces.QueueSize))
+	setInt("logs.queue_size", &c.Logs.QueueSize, tier.queueSize)
+	setInt("logs.batch_size", &c.Logs.BatchSize, min(tier.batchSize, c.Logs.QueueSize))
+	setInt("performance.worker_pool_size", &c.Performance.WorkerPoolSize, min(max(int(math.Ceil(cpus)), 1), maxTunedWorkers))
+	setInt("performance.queue_buffer_size", &c.Performance.QueueBufferSize, tier.workerQueue)
+
+	if key := "metrics.runtime_interval"; p[key] == SourceDefault && c.Metrics.RuntimeInterval != tier.runtimeInterval {
+		c.Metrics.RuntimeInterval = tier.runtimeInterval
+		changed = append(changed, key)
+	}
+
+	p.Set(SourceAuto, changed...)
+	return changed
+}
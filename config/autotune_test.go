@@ -0,0 +1,84 @@
+package config
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func defaultTunable() *Config {
+	return &Config{
+		Traces:      TracesConfig{QueueSize: 2048, BatchSize: 512, MaxExportBatch: 512},
+		Logs:        LogsConfig{QueueSize: 2048, BatchSize: 512},
+		Metrics:     MetricsConfig{RuntimeInterval: 10 * time.Second},
+		Performance: PerformanceConfig{WorkerPoolSize: 4, QueueBufferSize: 1000},
+	}
+}
+
+func TestAutoTune_SizesByAvailableResources(t *testing.T) {
+	tests := []struct {
+		name            string
+		memory          uint64
+		cpus            float64
+		queue, batch    int
+		workers         int
+		runtimeInterval time.Duration
+	}{
+		{"small pod", 128 << 20, 0.5, 512, 128, 1, 30 * time.Second},
+		{"medium pod", 1 << 30, 2, 1024, 256, 2, 15 * time.Second},
+		{"default tier", 4 << 30, 4, 2048, 512, 4, 10 * time.Second},
+		{"large node", 64 << 30, 16, 8192, 1024, 8, 10 * time.Second},
+		{"large memory, few cpus", 64 << 30, 2, 2048, 512, 2, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultTunable()
+			p := EnvProvenance()
+			cfg.AutoTune(tt.memory, tt.cpus, p)
+
+			if cfg.Traces.QueueSize != tt.queue || cfg.Logs.QueueSize != tt.queue {
+				t.Errorf("queue sizes = %d/%d, want %d", cfg.Traces.QueueSize, cfg.Logs.QueueSize, tt.queue)
+			}
+			if cfg.Traces.BatchSize != tt.batch || cfg.Traces.MaxExportBatch != tt.batch || cfg.Logs.BatchSize != tt.batch {
+				t.Errorf("batch sizes = %d/%d/%d, want %d", cfg.Traces.BatchSize, cfg.Traces.MaxExportBatch, cfg.Logs.BatchSize, tt.batch)
+			}
+			if cfg.Performance.WorkerPoolSize != tt.workers {
+				t.Errorf("worker_pool_size = %d, want %d", cfg.Performance.WorkerPoolSize, tt.workers)
+			}
+			if cfg.Metrics.RuntimeInterval != tt.runtimeInterval {
+				t.Errorf("runtime_interval = %v, want %v", cfg.Metrics.RuntimeInterval, tt.runtimeInterval)
+			}
+		})
+	}
+}
+
+func TestAutoTune_KeepsExplicitValues(t *testing.T) {
+	t.Setenv("OTEL_BSP_MAX_QUEUE_SIZE", "4096")
+
+	cfg := defaultTunable()
+	cfg.Traces.QueueSize = 4096
+	p := EnvProvenance()
+	p.Set(SourceOption, "metrics.runtime_interval")
+
+	changed := cfg.AutoTune(128<<20, 1, p)
+
+	if cfg.Traces.QueueSize != 4096 || cfg.Metrics.RuntimeInterval != 10*time.Second {
+		t.Errorf("explicit values changed: queue %d, runtime interval %v", cfg.Traces.QueueSize, cfg.Metrics.RuntimeInterval)
+	}
+	if slices.Contains(changed, "traces.queue_size") || slices.Contains(changed, "metrics.runtime_interval") {
+		t.Errorf("changed = %v, want explicit keys left out", changed)
+	}
+	if cfg.Logs.QueueSize != 512 || p["logs.queue_size"] != SourceAuto {
+		t.Errorf("logs.queue_size = %d (%s), want 512 (auto)", cfg.Logs.QueueSize, p["logs.queue_size"])
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("tuned config is invalid: %v", err)
+	}
+}
+
+func TestAutoTune_UnknownResourcesKeepDefaults(t *testing.T) {
+	cfg := defaultTunable()
+	if changed := cfg.AutoTune(0, 0, EnvProvenance()); changed != nil {
+		t.Errorf("changed = %v, want nothing", changed)
+	}
+}
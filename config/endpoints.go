@@ -0,0 +1,29 @@
+package config
+
+// RegistryEndpoint looks up the collector endpoint for the configured
+// environment and region in EndpointRegistry. Keys are tried from most to
+// least specific: "<environment>/<region>", "<region>", "<environment>". The
+// endpoint is returned without its URL scheme, like Endpoint.
+func (c *Config) RegistryEndpoint() (string, bool) {
+	if len(c.EndpointRegistry) == 0 {
+		return "", false
+	}
+
+	var keys []string
+	if c.Environment != "" && c.Region != "" {
+		keys = append(keys, c.Environment+"/"+c.Region)
+	}
+	if c.Region != "" {
+		keys = append(keys, c.Region)
+	}
+	if c.Environment != "" {
+		keys = append(keys, c.Environment)
+	}
+
+	for _, key := range keys {
+		if endpoint, ok := c.EndpointRegistry[key]; ok && endpoint != "" {
+			return stripURLScheme(endpoint), true
+		}
+	}
+	return "", false
+}
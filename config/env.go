@@ -0,0 +1,590 @@
+package config
+
+import (
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envSource looks up an environment variable, returning "" when it is unset.
+type envSource func(key string) string
+
+var (
+	osEnv envSource = os.Getenv
+	noEnv envSource = func(string) string { return "" }
+)
+
+// FromEnv loads the configuration from environment variables, falling back
+// to the defaults for unset ones.
+func FromEnv() *Config {
+	return osEnv.load()
+}
+
+// Defaults returns the configuration used when no environment variable, file
+// or option sets a value.
+func Defaults() *Config {
+	return noEnv.load()
+}
+
+func (src envSource) load() *Config {
+	env := src.getStringEnv("development", "ENV", "DEPLOYMENT_ENVIRONMENT")
+
+	cfg := &Config{
+		Enabled:     src.getBoolEnv(true, "SIGNOZ_ENABLED", "OTEL_ENABLED"),
+		ServiceName: src.getStringEnv("", "OTEL_SERVICE_NAME"),
+		Namespace:   src.getStringEnv("", "OTEL_SERVICE_NAMESPACE"),
+		Version:     src.getStringEnv("0.0.0", "OTEL_SERVICE_VERSION", "VERSION"),
+		Environment: env,
+
+		Endpoint:         stripURLScheme(src.getStringEnv("signoz-otel-collector.signoz.svc.cluster.local:4317", "OTEL_EXPORTER_OTLP_ENDPOINT")),
+		ExporterProtocol: src.getStringEnv("grpc", "OTEL_EXPORTER_OTLP_PROTOCOL"),
+		Insecure:         src.getBoolEnv(true, "OTEL_EXPORTER_OTLP_INSECURE"),
+		Timeout:          src.getDurationEnv("OTEL_EXPORTER_OTLP_TIMEOUT", 10*time.Second),
+		Compression:      src.getStringEnv("gzip", "OTEL_EXPORTER_OTLP_COMPRESSION"),
+
+		Region:           src.getStringEnv("", "OTEL_REGION", "CLOUD_REGION", "AWS_REGION"),
+		EndpointRegistry: parseKeyValuePairs(src("OTEL_ENDPOINT_REGISTRY")),
+
+		Auth:   src.loadAuthConfig(),
+		TLS:    src.loadTLSConfig(),
+		Stdout: src.loadStdoutConfig(),
+
+		Resource:             src.loadResourceConfig(env),
+		Propagators:          src.getStringSliceEnv("OTEL_PROPAGATORS", []string{"tracecontext", "baggage"}),
+		Traces:               src.loadTracesConfig(env),
+		Metrics:              src.loadMetricsConfig(),
+		Logs:                 src.loadLogsConfig(),
+		Performance:          src.loadPerformanceConfig(),
+		Features:             src.loadFeaturesConfig(env),
+		RouteExclusion:       src.loadRouteExclusionConfig(),
+		MetricRouteExclusion: src.loadMetricRouteExclusionConfig(),
+		Scrub:                src.loadScrubConfig(),
+		Blocklist:            src.loadBlocklistConfig(),
+		HTTP:                 src.loadHTTPConfig(),
+	}
+
+	// An explicit OTEL_EXPORTER_OTLP_ENDPOINT always wins over the registry.
+	if src("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		if endpoint, ok := cfg.RegistryEndpoint(); ok {
+			cfg.Endpoint = endpoint
+		}
+	}
+
+	return cfg
+}
+
+func (src envSource) loadAuthConfig() AuthConfig {
+	cfg := AuthConfig{
+		Headers:        make(map[string]string),
+		HeadersFromEnv: make(map[string]string),
+	}
+
+	if token := src("SIGNOZ_ACCESS_TOKEN"); token != "" {
+		cfg.Headers["signoz-access-token"] = token
+	}
+
+	if headerStr := src("OTEL_EXPORTER_OTLP_HEADERS"); headerStr != "" {
+		pairs := strings.Split(headerStr, ",")
+		for _, pair := range pairs {
+			parts := strings.SplitN(pair, "=", 2)
+			if len(parts) == 2 {
+				cfg.Headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+			}
+		}
+	}
+
+	return cfg
+}
+
+func (src envSource) loadTLSConfig() TLSConfig {
+	return TLSConfig{
+		Insecure:           src.getBoolEnv(true, "OTEL_EXPORTER_OTLP_INSECURE"),
+		CAFile:             src.getStringEnv("", "OTEL_EXPORTER_OTLP_CERTIFICATE"),
+		CertFile:           src.getStringEnv("", "OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE"),
+		KeyFile:            src.getStringEnv("", "OTEL_EXPORTER_OTLP_CLIENT_KEY"),
+		InsecureSkipVerify: src.getBoolEnv(false, "OTEL_EXPORTER_OTLP_TLS_SKIP_VERIFY"),
+		MinVersion:         src.getStringEnv("1.2", "OTEL_EXPORTER_OTLP_TLS_MIN_VERSION"),
+	}
+}
+
+func (src envSource) loadStdoutConfig() StdoutConfig {
+	return StdoutConfig{
+		Path:        src.getStringEnv("", "OTEL_EXPORTER_STDOUT_PATH"),
+		PrettyPrint: src.getBoolEnv(true, "OTEL_EXPORTER_STDOUT_PRETTY"),
+		MaxSizeMB:   src.getIntEnv("OTEL_EXPORTER_STDOUT_MAX_SIZE_MB", 100),
+		MaxBackups:  src.getIntEnv("OTEL_EXPORTER_STDOUT_MAX_BACKUPS", 3),
+	}
+}
+
+func (src envSource) loadResourceConfig(env string) ResourceConfig {
+	return ResourceConfig{
+		ServiceNamespace:      src.getStringEnv("", "OTEL_SERVICE_NAMESPACE"),
+		ServiceInstance:       src.getStringEnv("", "OTEL_SERVICE_INSTANCE"),
+		DeploymentEnvironment: env,
+
+		InstanceIDStrategy: src.getStringEnv("hostname", "OTEL_SERVICE_INSTANCE_ID_STRATEGY"),
+		InstanceIDFile:     src.getStringEnv("", "OTEL_SERVICE_INSTANCE_ID_FILE"),
+
+		K8sPodName:     src.getStringEnv("", "POD_NAME", "K8S_POD_NAME"),
+		K8sPodUID:      src.getStringEnv("", "POD_UID", "K8S_POD_UID"),
+		K8sPodIP:       src.getStringEnv("", "POD_IP", "K8S_POD_IP"),
+		K8sNamespace:   src.getStringEnv("", "POD_NAMESPACE", "K8S_NAMESPACE"),
+		K8sNodeName:    src.getStringEnv("", "NODE_NAME", "K8S_NODE_NAME"),
+		K8sClusterName: src.getStringEnv("", "K8S_CLUSTER_NAME"),
+
+		ContainerName: src.getStringEnv("", "CONTAINER_NAME"),
+		ContainerID:   src.getStringEnv("", "CONTAINER_ID"),
+
+		Detectors:       src.getStringSliceEnv("OTEL_RESOURCE_DETECTORS", nil),
+		DetectorTimeout: src.getDurationEnv("OTEL_RESOURCE_DETECTORS_TIMEOUT", 2*time.Second),
+
+		CustomAttributes: parseKeyValuePairs(src("OTEL_RESOURCE_ATTRIBUTES")),
+
+		PerSignalAttributes: PerSignalAttributes{
+			Traces:  parseKeyValuePairs(src("OTEL_TRACES_RESOURCE_ATTRIBUTES")),
+			Metrics: parseKeyValuePairs(src("OTEL_METRICS_RESOURCE_ATTRIBUTES")),
+			Logs:    parseKeyValuePairs(src("OTEL_LOGS_RESOURCE_ATTRIBUTES")),
+		},
+	}
+}
+
+func (src envSource) loadTracesConfig(env string) TracesConfig {
+	samplerType := src.getStringEnv("parent_based", "OTEL_TRACES_SAMPLER")
+	return TracesConfig{
+		Enabled: src.getBoolEnv(true, "OTEL_TRACES_ENABLED"),
+
+		Sampling: SamplingConfig{
+			Type:     samplerType,
+			Rate:     src.getFloat64Env("OTEL_TRACES_SAMPLER_ARG", defaultSamplerArg(samplerType, env)),
+			PerRoute: parsePerRouteSampling(src("OTEL_TRACES_SAMPLING_ROUTES")),
+
+			TenantKey:    src.getStringEnv("tenant.id", "OTEL_TRACES_SAMPLING_TENANT_KEY"),
+			TenantQuota:  src.getIntEnv("OTEL_TRACES_SAMPLING_TENANT_QUOTA", 0),
+			TenantQuotas: parseTenantQuotas(src("OTEL_TRACES_SAMPLING_TENANT_QUOTAS")),
+		},
+
+		MaxAttributesPerSpan: src.getIntEnv("OTEL_SPAN_ATTRIBUTE_COUNT_LIMIT", 128),
+		MaxEventsPerSpan:     src.getIntEnv("OTEL_SPAN_EVENT_COUNT_LIMIT", 128),
+		MaxLinksPerSpan:      src.getIntEnv("OTEL_SPAN_LINK_COUNT_LIMIT", 128),
+		RepeatedEventsEvery:  src.getIntEnv("OTEL_SPAN_REPEATED_EVENTS_EVERY", 0),
+		EventPayloadMaxSize:  src.getIntEnv("OTEL_SPAN_EVENT_PAYLOAD_MAX_SIZE", 4096),
+
+		Compression: SpanCompressionConfig{
+			Enabled:     src.getBoolEnv(false, "OTEL_TRACES_COMPRESSION_ENABLED"),
+			MinSpans:    src.getIntEnv("OTEL_TRACES_COMPRESSION_MIN_SPANS", 5),
+			MaxDuration: src.getDurationEnv("OTEL_TRACES_COMPRESSION_MAX_DURATION", 50*time.Millisecond),
+		},
+
+		ErrorStackTraces: src.getBoolEnv(false, "OTEL_TRACES_ERROR_STACKTRACES"),
+		ErrorStackFrames: src.getIntEnv("OTEL_TRACES_ERROR_STACK_FRAMES", 32),
+		ErrorChainDepth:  src.getIntEnv("OTEL_TRACES_ERROR_CHAIN_DEPTH", 5),
+
+		MonotonicTimestamps: src.getBoolEnv(false, "OTEL_TRACES_MONOTONIC_TIMESTAMPS"),
+		ClockSkewTolerance:  src.getDurationEnv("OTEL_TRACES_CLOCK_SKEW_TOLERANCE", time.Second),
+
+		RecentSpans: src.getIntEnv("OTEL_DEBUG_RECENT_SPANS", 100),
+
+		BatchTimeout:   src.getDurationEnv("OTEL_BSP_SCHEDULE_DELAY", 5*time.Second),
+		BatchSize:      src.getIntEnv("OTEL_BSP_MAX_EXPORT_BATCH_SIZE", 512),
+		QueueSize:      src.getIntEnv("OTEL_BSP_MAX_QUEUE_SIZE", 2048),
+		MaxExportBatch: src.getIntEnv("OTEL_BSP_EXPORT_BATCH_SIZE", 512),
+
+		DynamicBatching: DynamicBatchingConfig{
+			Enabled:          src.getBoolEnv(false, "OTEL_BSP_DYNAMIC_ENABLED"),
+			MinBatchSize:     src.getIntEnv("OTEL_BSP_DYNAMIC_MIN_BATCH_SIZE", 64),
+			MaxBatchSize:     src.getIntEnv("OTEL_BSP_DYNAMIC_MAX_BATCH_SIZE", 2048),
+			MinScheduleDelay: src.getDurationEnv("OTEL_BSP_DYNAMIC_MIN_SCHEDULE_DELAY", 200*time.Millisecond),
+			MaxScheduleDelay: src.getDurationEnv("OTEL_BSP_DYNAMIC_MAX_SCHEDULE_DELAY", 5*time.Second),
+		},
+
+		ExcludedPaths: src.getStringSliceEnv("OTEL_TRACES_EXCLUDED_PATHS", []string{
+			"/health", "/healthz", "/health_check", "/metrics", "/ready", "/live",
+		}),
+
+		MinimalSpans: src.getBoolEnv(false, "OTEL_TRACES_MINIMAL_SPANS"),
+
+		BaggageKeys: src.getStringSliceEnv("OTEL_TRACES_BAGGAGE_KEYS", nil),
+
+		Exporter: src.loadSignalExporterConfig("OTEL_EXPORTER_OTLP_TRACES_"),
+	}
+}
+
+func (src envSource) loadMetricsConfig() MetricsConfig {
+	return MetricsConfig{
+		Enabled: src.getBoolEnv(true, "OTEL_METRICS_ENABLED"),
+
+		DefaultInterval: src.getDurationEnv("OTEL_METRIC_EXPORT_INTERVAL", 30*time.Second),
+		RuntimeInterval: src.getDurationEnv("OTEL_RUNTIME_METRIC_INTERVAL", 10*time.Second),
+
+		HTTP:     src.getBoolEnv(true, "OTEL_METRICS_HTTP_ENABLED"),
+		Database: src.getBoolEnv(true, "OTEL_METRICS_DATABASE_ENABLED"),
+		Redis:    src.getBoolEnv(true, "OTEL_METRICS_REDIS_ENABLED"),
+		AMQP:     src.getBoolEnv(true, "OTEL_METRICS_AMQP_ENABLED"),
+		Runtime:  src.getBoolEnv(true, "OTEL_METRICS_RUNTIME_ENABLED"),
+		Business: src.getBoolEnv(true, "OTEL_METRICS_BUSINESS_ENABLED"),
+
+		CPU:    src.getBoolEnv(true, "OTEL_METRICS_CPU_ENABLED"),
+		Memory: src.getBoolEnv(true, "OTEL_METRICS_MEMORY_ENABLED"),
+		Disk:   src.getBoolEnv(false, "OTEL_METRICS_DISK_ENABLED"),
+
+		DiskPaths: src.getStringSliceEnv("OTEL_METRICS_DISK_PATHS", []string{"/"}),
+
+		HTTPLatencyBoundaries: src.getFloat64SliceEnv("OTEL_HTTP_LATENCY_BOUNDARIES",
+			[]float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1.0, 2.5, 5.0, 7.5, 10.0}),
+		DBLatencyBoundaries: src.getFloat64SliceEnv("OTEL_DB_LATENCY_BOUNDARIES",
+			[]float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0}),
+
+		BaggageKeys: src.getStringSliceEnv("OTEL_METRICS_BAGGAGE_KEYS", nil),
+
+		TemporalityPreference: strings.ToLower(src.getStringEnv(TemporalityCumulative, "OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE")),
+
+		Cardinality: CardinalityConfig{
+			DropAttributes:     src.getStringSliceEnv("OTEL_METRICS_DROP_ATTRIBUTES", []string{"error_message", "user_id"}),
+			MaxAttributeLength: src.getIntEnv("OTEL_METRICS_MAX_ATTR_LENGTH", 256),
+			UseExponentialHist: src.getBoolEnv(false, "OTEL_METRICS_EXPONENTIAL_HIST"),
+
+			MaxSeriesPerInstrument: src.getIntEnv("OTEL_METRICS_CARDINALITY_LIMIT", 2000),
+
+			HistogramAggregation: parseKeyValuePairs(src("OTEL_METRICS_HISTOGRAM_AGGREGATION")),
+		},
+
+		Exporter: src.loadSignalExporterConfig("OTEL_EXPORTER_OTLP_METRICS_"),
+	}
+}
+
+func (src envSource) loadLogsConfig() LogsConfig {
+	return LogsConfig{
+		Enabled: src.getBoolEnv(true, "OTEL_LOGS_ENABLED"),
+
+		TraceCorrelation: src.getBoolEnv(true, "OTEL_LOGS_TRACE_CORRELATION"),
+		SpanCorrelation:  src.getBoolEnv(true, "OTEL_LOGS_SPAN_CORRELATION"),
+		ExportLevels:     src.getStringSliceEnv("OTEL_LOGS_EXPORT_LEVELS", []string{"info", "warn", "error"}),
+
+		BatchTimeout: src.getDurationEnv("OTEL_BLRP_SCHEDULE_DELAY", 5*time.Second),
+		BatchSize:    src.getIntEnv("OTEL_BLRP_MAX_EXPORT_BATCH_SIZE", 512),
+		QueueSize:    src.getIntEnv("OTEL_BLRP_MAX_QUEUE_SIZE", 2048),
+
+		DropPolicy:   strings.ToLower(src.getStringEnv("", "OTEL_LOGS_DROP_POLICY")),
+		BlockTimeout: src.getDurationEnv("OTEL_LOGS_BLOCK_TIMEOUT", 100*time.Millisecond),
+
+		SDKVerbosity: src.getIntEnv("OTEL_LOGS_SDK_VERBOSITY", 1),
+
+		StructuredFields: src.getBoolEnv(true, "OTEL_LOGS_STRUCTURED"),
+		CustomFields:     parseKeyValuePairs(src("OTEL_LOGS_CUSTOM_FIELDS")),
+
+		Exporter: src.loadSignalExporterConfig("OTEL_EXPORTER_OTLP_LOGS_"),
+	}
+}
+
+// loadSignalExporterConfig reads the standard per-signal exporter variables,
+// e.g. OTEL_EXPORTER_OTLP_METRICS_ENDPOINT for prefix
+// "OTEL_EXPORTER_OTLP_METRICS_".
+func (src envSource) loadSignalExporterConfig(prefix string) SignalExporterConfig {
+	return SignalExporterConfig{
+		Endpoint: src.getStringEnv("", prefix+"ENDPOINT"),
+		Protocol: src.getStringEnv("", prefix+"PROTOCOL"),
+		Headers:  parseKeyValuePairs(src(prefix + "HEADERS")),
+	}
+}
+
+func (src envSource) loadPerformanceConfig() PerformanceConfig {
+	return PerformanceConfig{
+		MaxMemoryUsage:     src.getInt64Env("OTEL_MAX_MEMORY_USAGE", 128*1024*1024),
+		MemoryLimitPercent: src.getIntEnv("OTEL_MEMORY_LIMIT_PERCENT", 10),
+		MaxCPUUsage:        src.getFloat64Env("OTEL_MAX_CPU_USAGE", 0.1),
+		WorkerPoolSize:     src.getIntEnv("OTEL_WORKER_POOL_SIZE", 4),
+		QueueBufferSize:    src.getIntEnv("OTEL_QUEUE_BUFFER_SIZE", 1000),
+
+		AutoTune:       src.getBoolEnv(true, "OTEL_AUTO_TUNE"),
+		SetMemoryLimit: src.getBoolEnv(false, "OTEL_SET_GOMEMLIMIT"),
+
+		MaxBatchSize:   src.getIntEnv("OTEL_MAX_BATCH_SIZE", 1000),
+		FlushTimeout:   src.getDurationEnv("OTEL_FLUSH_TIMEOUT", 5*time.Second),
+		RetryAttempts:  src.getIntEnv("OTEL_RETRY_ATTEMPTS", 3),
+		RetryBackoff:   src.getDurationEnv("OTEL_RETRY_BACKOFF", 1*time.Second),
+		ConnectionPool: src.getIntEnv("OTEL_CONNECTION_POOL", 5),
+
+		ShutdownTimeout:   src.getDurationEnv("OTEL_SHUTDOWN_TIMEOUT", 10*time.Second),
+		FlushOnlyShutdown: src.getBoolEnv(false, "OTEL_SHUTDOWN_FLUSH_ONLY"),
+
+		AdaptiveSampling:     src.getBoolEnv(true, "OTEL_ADAPTIVE_SAMPLING"),
+		TargetSpansPerSecond: src.getFloat64Env("OTEL_ADAPTIVE_SAMPLING_TARGET", 100),
+		ErrorSamplingBoost:   src.getFloat64Env("OTEL_ERROR_SAMPLING_BOOST", 5.0),
+		ErrorBoostUnsampled:  src.getBoolEnv(false, "OTEL_ERROR_SAMPLING_BOOST_UNSAMPLED"),
+	}
+}
+
+func (src envSource) loadFeaturesConfig(env string) FeaturesConfig {
+	return FeaturesConfig{
+		AutoHTTP:     src.getBoolEnv(true, "OTEL_AUTO_HTTP"),
+		AutoDatabase: src.getBoolEnv(true, "OTEL_AUTO_DATABASE"),
+		AutoRedis:    src.getBoolEnv(true, "OTEL_AUTO_REDIS"),
+		AutoAMQP:     src.getBoolEnv(true, "OTEL_AUTO_AMQP"),
+		AutoKafka:    src.getBoolEnv(true, "OTEL_AUTO_KAFKA"),
+		AutoGRPC:     src.getBoolEnv(true, "OTEL_AUTO_GRPC"),
+		AutoGraphQL:  src.getBoolEnv(true, "OTEL_AUTO_GRAPHQL"),
+
+		DistributedTracing: src.getBoolEnv(true, "OTEL_DISTRIBUTED_TRACING"),
+		ErrorTracking:      src.getBoolEnv(true, "OTEL_ERROR_TRACKING"),
+		PerformanceMonitor: src.getBoolEnv(true, "OTEL_PERFORMANCE_MONITOR"),
+		BusinessMetrics:    src.getBoolEnv(true, "OTEL_BUSINESS_METRICS"),
+
+		HealthChecks:    src.getBoolEnv(true, "OTEL_HEALTH_CHECKS"),
+		ReadinessProbes: src.getBoolEnv(true, "OTEL_READINESS_PROBES"),
+		LivenessProbes:  src.getBoolEnv(true, "OTEL_LIVENESS_PROBES"),
+
+		DebugMode: src.getBoolEnv(env == "development", "OTEL_DEBUG_MODE"),
+		DryRun:    src.getBoolEnv(false, "OTEL_DRY_RUN"),
+
+		SelfTelemetry: src.getBoolEnv(false, "OTEL_AGENT_SELF_TELEMETRY"),
+
+		ResourceBudget: src.getBoolEnv(false, "OTEL_RESOURCE_BUDGET"),
+	}
+}
+
+func (src envSource) loadRouteExclusionConfig() RouteExclusionConfig {
+	return RouteExclusionConfig{
+		ExactPaths: src.getStringSliceEnv("OTEL_TRACES_EXCLUDED_PATHS", []string{
+			"/health", "/healthz", "/health_check", "/metrics", "/ready", "/live",
+		}),
+		PrefixPaths: src.getStringSliceEnv("OTEL_TRACES_EXCLUDED_PREFIXES", nil),
+		Patterns: src.getStringSliceEnv("OTEL_TRACES_EXCLUDED_PATTERNS", []string{
+			"/*/health", "/*/healthz", "/*/health_check",
+			"/*/metrics", "/*/ready", "/*/live",
+			"/*/*/health", "/*/*/healthz", "/*/*/health_check",
+			"/*/*/metrics", "/*/*/ready", "/*/*/live",
+		}),
+		KeepMetrics: src.getBoolEnv(false, "OTEL_TRACES_EXCLUDED_KEEP_METRICS"),
+	}
+}
+
+func (src envSource) loadMetricRouteExclusionConfig() RouteExclusionConfig {
+	return RouteExclusionConfig{
+		ExactPaths:  src.getStringSliceEnv("OTEL_METRICS_EXCLUDED_PATHS", nil),
+		PrefixPaths: src.getStringSliceEnv("OTEL_METRICS_EXCLUDED_PREFIXES", nil),
+		Patterns:    src.getStringSliceEnv("OTEL_METRICS_EXCLUDED_PATTERNS", nil),
+	}
+}
+
+func (src envSource) loadScrubConfig() ScrubConfig {
+	return ScrubConfig{
+		Enabled:              src.getBoolEnv(false, "OTEL_PII_SCRUB_ENABLED"),
+		SensitiveKeys:        src.getStringSliceEnv("OTEL_PII_SENSITIVE_KEYS", []string{"password", "token", "secret", "key", "email"}),
+		SensitivePatterns:    src.getStringSliceEnv("OTEL_PII_SENSITIVE_PATTERNS", []string{".*password.*", ".*token.*", ".*secret.*"}),
+		RedactedValue:        src.getStringEnv("[REDACTED]", "OTEL_PII_REDACTED_VALUE"),
+		DBStatementMaxLength: src.getIntEnv("OTEL_PII_DB_STATEMENT_MAX_LENGTH", 2048),
+		SQLSanitize:          src.getBoolEnv(false, "OTEL_PII_SQL_SANITIZE"),
+		SQLDialect:           src.getStringEnv("generic", "OTEL_PII_SQL_DIALECT"),
+		Detectors:            src.getStringSliceEnv("OTEL_PII_DETECTORS", nil),
+		CaptureMessagingKeys: src.getBoolEnv(false, "OTEL_PII_CAPTURE_MESSAGING_KEYS"),
+	}
+}
+
+func (src envSource) loadBlocklistConfig() BlocklistConfig {
+	return BlocklistConfig{
+		Enabled: src.getBoolEnv(false, "OTEL_BLOCKLIST_ENABLED"),
+		Keys:    src.getStringSliceEnv("OTEL_BLOCKLIST_KEYS", []string{"user.id", "enduser.id"}),
+		Values:  src.getStringSliceEnv("OTEL_BLOCKLIST_VALUES", nil),
+	}
+}
+
+func (src envSource) loadHTTPConfig() HTTPConfig {
+	return HTTPConfig{
+		CaptureRequestHeaders:  src.getBoolEnv(true, "OTEL_HTTP_CAPTURE_REQUEST_HEADERS"),
+		CaptureResponseHeaders: src.getBoolEnv(true, "OTEL_HTTP_CAPTURE_RESPONSE_HEADERS"),
+		AllowedRequestHeaders:  src.getStringSliceEnv("OTEL_HTTP_ALLOWED_REQUEST_HEADERS", nil),
+		AllowedResponseHeaders: src.getStringSliceEnv("OTEL_HTTP_ALLOWED_RESPONSE_HEADERS", nil),
+		CaptureQueryParams:     src.getBoolEnv(true, "OTEL_HTTP_CAPTURE_QUERY_PARAMS"),
+		CaptureRequestBody:     src.getBoolEnv(false, "OTEL_HTTP_CAPTURE_REQUEST_BODY"),
+		CaptureResponseBody:    src.getBoolEnv(false, "OTEL_HTTP_CAPTURE_RESPONSE_BODY"),
+		RequestBodyMaxSize:     src.getIntEnv("OTEL_HTTP_REQUEST_BODY_MAX_SIZE", 8192),
+		ResponseBodyMaxSize:    src.getIntEnv("OTEL_HTTP_RESPONSE_BODY_MAX_SIZE", 8192),
+		BodyAllowedContentTypes: src.getStringSliceEnv("OTEL_HTTP_BODY_ALLOWED_CONTENT_TYPES", []string{
+			"application/json", "application/xml", "text/plain",
+		}),
+		RecordExceptionEvents: src.getBoolEnv(true, "OTEL_HTTP_RECORD_EXCEPTION_EVENTS"),
+		SensitiveHeaders: src.getStringSliceEnv("OTEL_HTTP_SENSITIVE_HEADERS", []string{
+			"authorization", "cookie", "set-cookie", "x-api-key", "x-auth-token",
+		}),
+		CaptureQueueTime:         src.getBoolEnv(true, "OTEL_HTTP_CAPTURE_QUEUE_TIME"),
+		CaptureMultipartMetadata: src.getBoolEnv(false, "OTEL_HTTP_CAPTURE_MULTIPART_METADATA"),
+	}
+}
+
+// getStringEnv returns the value of the first non-empty env var, or defaultValue.
+func (src envSource) getStringEnv(defaultValue string, keys ...string) string {
+	for _, key := range keys {
+		if value := src(key); value != "" {
+			return value
+		}
+	}
+	return defaultValue
+}
+
+func (src envSource) getBoolEnv(defaultValue bool, keys ...string) bool {
+	for _, key := range keys {
+		if value := src(key); value != "" {
+			return value == "true" || value == "1" || value == "yes"
+		}
+	}
+	return defaultValue
+}
+
+func (src envSource) getIntEnv(key string, defaultValue int) int {
+	if value := src(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func (src envSource) getInt64Env(key string, defaultValue int64) int64 {
+	if value := src(key); value != "" {
+		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func (src envSource) getFloat64Env(key string, defaultValue float64) float64 {
+	if value := src(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func (src envSource) getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := src(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+		if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return time.Duration(ms) * time.Millisecond
+		}
+	}
+	return defaultValue
+}
+
+func (src envSource) getStringSliceEnv(key string, defaultValue []string) []string {
+	if value := src(key); value != "" {
+		parts := strings.Split(value, ",")
+		result := make([]string, 0, len(parts))
+		for _, p := range parts {
+			trimmed := strings.TrimSpace(p)
+			if trimmed != "" {
+				result = append(result, trimmed)
+			}
+		}
+		if len(result) > 0 {
+			return result
+		}
+	}
+	return defaultValue
+}
+
+func (src envSource) getFloat64SliceEnv(key string, defaultValue []float64) []float64 {
+	if value := src(key); value != "" {
+		parts := strings.Split(value, ",")
+		result := make([]float64, 0, len(parts))
+		for _, part := range parts {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(part), 64); err == nil {
+				result = append(result, f)
+			}
+		}
+		if len(result) > 0 {
+			return result
+		}
+	}
+	return defaultValue
+}
+
+// defaultRateLimitedSpansPerSecond is the default budget of the
+// "rate_limited" sampler when OTEL_TRACES_SAMPLER_ARG is not set.
+const defaultRateLimitedSpansPerSecond = 100
+
+// defaultSamplerArg returns the default sampler argument: a spans-per-second
+// budget for the rate_limited sampler, otherwise the environment's ratio.
+func defaultSamplerArg(samplerType, env string) float64 {
+	if samplerType == "rate_limited" {
+		return defaultRateLimitedSpansPerSecond
+	}
+	return defaultSamplingRate(env)
+}
+
+func defaultSamplingRate(env string) float64 {
+	switch env {
+	case "production":
+		return 0.1
+	case "staging":
+		return 0.5
+	default:
+		return 1.0
+	}
+}
+
+func parseKeyValuePairs(value string) map[string]string {
+	result := make(map[string]string)
+	if value == "" {
+		return result
+	}
+	pairs := strings.Split(value, ",")
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) == 2 {
+			result[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	return result
+}
+
+func parsePerRouteSampling(value string) map[string]float64 {
+	result := make(map[string]float64)
+	if value == "" {
+		return result
+	}
+	pairs := strings.Split(value, ",")
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) == 2 {
+			if rate, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err == nil {
+				result[strings.TrimSpace(parts[0])] = rate
+			}
+		}
+	}
+	return result
+}
+
+// parseTenantQuotas parses "tenant:quota" pairs. The quota follows the last
+// colon, so tenant IDs may contain colons.
+func parseTenantQuotas(value string) map[string]int {
+	result := make(map[string]int)
+	if value == "" {
+		return result
+	}
+	for _, pair := range strings.Split(value, ",") {
+		i := strings.LastIndex(pair, ":")
+		if i < 0 {
+			continue
+		}
+		if quota, err := strconv.Atoi(strings.TrimSpace(pair[i+1:])); err == nil {
+			result[strings.TrimSpace(pair[:i])] = quota
+		}
+	}
+	return result
+}
+
+func stripURLScheme(endpoint string) string {
+	if endpoint == "" {
+		return endpoint
+	}
+	if parsedURL, err := url.Parse(endpoint); err == nil && parsedURL.Host != "" {
+		return parsedURL.Host
+	}
+	return endpoint
+}
@@ -0,0 +1,437 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// getStringEnv
+// ---------------------------------------------------------------------------
+
+func TestGetStringEnv_DefaultWhenNoVars(t *testing.T) {
+	t.Setenv("KEY_A", "")
+	t.Setenv("KEY_B", "")
+
+	got := osEnv.getStringEnv("fallback", "KEY_A", "KEY_B")
+	if got != "fallback" {
+		t.Errorf("expected 'fallback', got %q", got)
+	}
+}
+
+func TestGetStringEnv_FirstNonEmptyWins(t *testing.T) {
+	t.Setenv("KEY_A", "")
+	t.Setenv("KEY_B", "value-b")
+	t.Setenv("KEY_C", "value-c")
+
+	got := osEnv.getStringEnv("fallback", "KEY_A", "KEY_B", "KEY_C")
+	if got != "value-b" {
+		t.Errorf("expected 'value-b', got %q", got)
+	}
+}
+
+func TestGetStringEnv_FirstKeyTakesPrecedence(t *testing.T) {
+	t.Setenv("KEY_A", "value-a")
+	t.Setenv("KEY_B", "value-b")
+
+	got := osEnv.getStringEnv("fallback", "KEY_A", "KEY_B")
+	if got != "value-a" {
+		t.Errorf("expected 'value-a', got %q", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// getBoolEnv
+// ---------------------------------------------------------------------------
+
+func TestGetBoolEnv_DefaultWhenUnset(t *testing.T) {
+	t.Setenv("BOOL_KEY", "")
+
+	if got := osEnv.getBoolEnv(true, "BOOL_KEY"); !got {
+		t.Error("expected default true")
+	}
+	if got := osEnv.getBoolEnv(false, "BOOL_KEY"); got {
+		t.Error("expected default false")
+	}
+}
+
+func TestGetBoolEnv_TruthyValues(t *testing.T) {
+	truthy := []string{"true", "1", "yes"}
+	for _, v := range truthy {
+		t.Run(v, func(t *testing.T) {
+			t.Setenv("BOOL_KEY", v)
+			if got := osEnv.getBoolEnv(false, "BOOL_KEY"); !got {
+				t.Errorf("expected true for %q", v)
+			}
+		})
+	}
+}
+
+func TestGetBoolEnv_FalsyValues(t *testing.T) {
+	falsy := []string{"false", "0", "no", "random"}
+	for _, v := range falsy {
+		t.Run(v, func(t *testing.T) {
+			t.Setenv("BOOL_KEY", v)
+			if got := osEnv.getBoolEnv(true, "BOOL_KEY"); got {
+				t.Errorf("expected false for %q", v)
+			}
+		})
+	}
+}
+
+func TestGetBoolEnv_MultipleKeys(t *testing.T) {
+	t.Setenv("BOOL_A", "")
+	t.Setenv("BOOL_B", "true")
+
+	if got := osEnv.getBoolEnv(false, "BOOL_A", "BOOL_B"); !got {
+		t.Error("expected true from second key")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// getIntEnv
+// ---------------------------------------------------------------------------
+
+func TestGetIntEnv_Default(t *testing.T) {
+	t.Setenv("INT_KEY", "")
+	if got := osEnv.getIntEnv("INT_KEY", 42); got != 42 {
+		t.Errorf("expected 42, got %d", got)
+	}
+}
+
+func TestGetIntEnv_Valid(t *testing.T) {
+	t.Setenv("INT_KEY", "100")
+	if got := osEnv.getIntEnv("INT_KEY", 42); got != 100 {
+		t.Errorf("expected 100, got %d", got)
+	}
+}
+
+func TestGetIntEnv_InvalidFallsBackToDefault(t *testing.T) {
+	t.Setenv("INT_KEY", "not-a-number")
+	if got := osEnv.getIntEnv("INT_KEY", 42); got != 42 {
+		t.Errorf("expected default 42 for invalid input, got %d", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// getFloat64Env
+// ---------------------------------------------------------------------------
+
+func TestGetFloat64Env_Default(t *testing.T) {
+	t.Setenv("FLOAT_KEY", "")
+	if got := osEnv.getFloat64Env("FLOAT_KEY", 3.14); got != 3.14 {
+		t.Errorf("expected 3.14, got %f", got)
+	}
+}
+
+func TestGetFloat64Env_Valid(t *testing.T) {
+	t.Setenv("FLOAT_KEY", "0.75")
+	if got := osEnv.getFloat64Env("FLOAT_KEY", 0.0); got != 0.75 {
+		t.Errorf("expected 0.75, got %f", got)
+	}
+}
+
+func TestGetFloat64Env_InvalidFallsBackToDefault(t *testing.T) {
+	t.Setenv("FLOAT_KEY", "abc")
+	if got := osEnv.getFloat64Env("FLOAT_KEY", 1.5); got != 1.5 {
+		t.Errorf("expected default 1.5 for invalid input, got %f", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// getDurationEnv
+// ---------------------------------------------------------------------------
+
+func TestGetDurationEnv_Default(t *testing.T) {
+	t.Setenv("DUR_KEY", "")
+	if got := osEnv.getDurationEnv("DUR_KEY", 5*time.Second); got != 5*time.Second {
+		t.Errorf("expected 5s, got %v", got)
+	}
+}
+
+func TestGetDurationEnv_GoDurationString(t *testing.T) {
+	t.Setenv("DUR_KEY", "30s")
+	if got := osEnv.getDurationEnv("DUR_KEY", 0); got != 30*time.Second {
+		t.Errorf("expected 30s, got %v", got)
+	}
+}
+
+func TestGetDurationEnv_MillisecondsInteger(t *testing.T) {
+	t.Setenv("DUR_KEY", "500")
+	if got := osEnv.getDurationEnv("DUR_KEY", 0); got != 500*time.Millisecond {
+		t.Errorf("expected 500ms, got %v", got)
+	}
+}
+
+func TestGetDurationEnv_InvalidFallsBackToDefault(t *testing.T) {
+	t.Setenv("DUR_KEY", "not-a-duration")
+	if got := osEnv.getDurationEnv("DUR_KEY", 10*time.Second); got != 10*time.Second {
+		t.Errorf("expected default 10s for invalid input, got %v", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// getStringSliceEnv
+// ---------------------------------------------------------------------------
+
+func TestGetStringSliceEnv_Default(t *testing.T) {
+	t.Setenv("SLICE_KEY", "")
+	def := []string{"a", "b"}
+	got := osEnv.getStringSliceEnv("SLICE_KEY", def)
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("expected default [a b], got %v", got)
+	}
+}
+
+func TestGetStringSliceEnv_CommaSeparated(t *testing.T) {
+	t.Setenv("SLICE_KEY", " foo , bar , baz ")
+	got := osEnv.getStringSliceEnv("SLICE_KEY", nil)
+	if len(got) != 3 || got[0] != "foo" || got[1] != "bar" || got[2] != "baz" {
+		t.Errorf("expected [foo bar baz], got %v", got)
+	}
+}
+
+func TestGetStringSliceEnv_EmptyItemsFiltered(t *testing.T) {
+	t.Setenv("SLICE_KEY", "a,,b, ,c")
+	got := osEnv.getStringSliceEnv("SLICE_KEY", nil)
+	if len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Errorf("expected [a b c], got %v", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// getFloat64SliceEnv
+// ---------------------------------------------------------------------------
+
+func TestGetFloat64SliceEnv_Default(t *testing.T) {
+	t.Setenv("FSLICE_KEY", "")
+	def := []float64{1.0, 2.0}
+	got := osEnv.getFloat64SliceEnv("FSLICE_KEY", def)
+	if len(got) != 2 || got[0] != 1.0 || got[1] != 2.0 {
+		t.Errorf("expected default [1 2], got %v", got)
+	}
+}
+
+func TestGetFloat64SliceEnv_CommaSeparated(t *testing.T) {
+	t.Setenv("FSLICE_KEY", "0.5, 1.0, 2.5")
+	got := osEnv.getFloat64SliceEnv("FSLICE_KEY", nil)
+	if len(got) != 3 || got[0] != 0.5 || got[1] != 1.0 || got[2] != 2.5 {
+		t.Errorf("expected [0.5 1 2.5], got %v", got)
+	}
+}
+
+func TestGetFloat64SliceEnv_InvalidEntriesSkipped(t *testing.T) {
+	t.Setenv("FSLICE_KEY", "1.0, bad, 3.0")
+	got := osEnv.getFloat64SliceEnv("FSLICE_KEY", nil)
+	if len(got) != 2 || got[0] != 1.0 || got[1] != 3.0 {
+		t.Errorf("expected [1 3], got %v", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// parsePerRouteSampling
+// ---------------------------------------------------------------------------
+
+func TestParsePerRouteSampling_Empty(t *testing.T) {
+	got := parsePerRouteSampling("")
+	if len(got) != 0 {
+		t.Errorf("expected empty map, got %v", got)
+	}
+}
+
+func TestParsePerRouteSampling_SingleRoute(t *testing.T) {
+	got := parsePerRouteSampling("/api/health:0.01")
+	if rate, ok := got["/api/health"]; !ok || rate != 0.01 {
+		t.Errorf("expected /api/health -> 0.01, got %v", got)
+	}
+}
+
+func TestParsePerRouteSampling_MultipleRoutes(t *testing.T) {
+	got := parsePerRouteSampling("/health:0.01, /api:0.5")
+	if len(got) != 2 {
+		t.Errorf("expected 2 entries, got %d", len(got))
+	}
+	if rate := got["/health"]; rate != 0.01 {
+		t.Errorf("expected /health -> 0.01, got %f", rate)
+	}
+	if rate := got["/api"]; rate != 0.5 {
+		t.Errorf("expected /api -> 0.5, got %f", rate)
+	}
+}
+
+func TestParsePerRouteSampling_InvalidRateSkipped(t *testing.T) {
+	got := parsePerRouteSampling("/good:0.5,/bad:notanumber,/also-good:0.1")
+	if len(got) != 2 {
+		t.Errorf("expected 2 valid entries, got %d: %v", len(got), got)
+	}
+}
+
+func TestParsePerRouteSampling_MalformedEntrySkipped(t *testing.T) {
+	got := parsePerRouteSampling("/health:0.5,nocolon,/api:1.0")
+	if len(got) != 2 {
+		t.Errorf("expected 2 entries (malformed skipped), got %d", len(got))
+	}
+}
+
+func TestParseTenantQuotas(t *testing.T) {
+	got := parseTenantQuotas("acme:600, org:eu:30,bad:x,nocolon")
+	if len(got) != 2 || got["acme"] != 600 || got["org:eu"] != 30 {
+		t.Errorf("got %v, want acme=600 and org:eu=30", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// parseKeyValuePairs
+// ---------------------------------------------------------------------------
+
+func TestParseKeyValuePairs_Empty(t *testing.T) {
+	got := parseKeyValuePairs("")
+	if len(got) != 0 {
+		t.Errorf("expected empty map, got %v", got)
+	}
+}
+
+func TestParseKeyValuePairs_SinglePair(t *testing.T) {
+	got := parseKeyValuePairs("env=prod")
+	if got["env"] != "prod" {
+		t.Errorf("expected env=prod, got %v", got)
+	}
+}
+
+func TestParseKeyValuePairs_MultiplePairs(t *testing.T) {
+	got := parseKeyValuePairs("env=prod, region=us-east-1")
+	if len(got) != 2 {
+		t.Errorf("expected 2 pairs, got %d", len(got))
+	}
+	if got["env"] != "prod" {
+		t.Errorf("expected env=prod, got %q", got["env"])
+	}
+	if got["region"] != "us-east-1" {
+		t.Errorf("expected region=us-east-1, got %q", got["region"])
+	}
+}
+
+func TestParseKeyValuePairs_ValueWithEquals(t *testing.T) {
+	got := parseKeyValuePairs("url=https://host?a=b")
+	if got["url"] != "https://host?a=b" {
+		t.Errorf("expected value with equals preserved, got %q", got["url"])
+	}
+}
+
+func TestParseKeyValuePairs_MalformedEntrySkipped(t *testing.T) {
+	got := parseKeyValuePairs("good=value,noequals,also_good=ok")
+	if len(got) != 2 {
+		t.Errorf("expected 2 valid pairs, got %d: %v", len(got), got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// stripURLScheme
+// ---------------------------------------------------------------------------
+
+func TestStripURLScheme_EmptyString(t *testing.T) {
+	got := stripURLScheme("")
+	if got != "" {
+		t.Errorf("expected empty string, got %q", got)
+	}
+}
+
+func TestStripURLScheme_HTTPSUrl(t *testing.T) {
+	got := stripURLScheme("https://collector.example.com:4317")
+	if got != "collector.example.com:4317" {
+		t.Errorf("expected 'collector.example.com:4317', got %q", got)
+	}
+}
+
+func TestStripURLScheme_HTTPUrl(t *testing.T) {
+	got := stripURLScheme("http://localhost:4317")
+	if got != "localhost:4317" {
+		t.Errorf("expected 'localhost:4317', got %q", got)
+	}
+}
+
+func TestStripURLScheme_NoScheme(t *testing.T) {
+	got := stripURLScheme("collector.example.com:4317")
+	// url.Parse without scheme will not populate Host, so the original is returned.
+	if got != "collector.example.com:4317" {
+		t.Errorf("expected original value returned, got %q", got)
+	}
+}
+
+func TestStripURLScheme_GRPCScheme(t *testing.T) {
+	got := stripURLScheme("grpc://collector.example.com:4317")
+	if got != "collector.example.com:4317" {
+		t.Errorf("expected 'collector.example.com:4317', got %q", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// defaultSamplingRate
+// ---------------------------------------------------------------------------
+
+func TestDefaultSamplingRate_Production(t *testing.T) {
+	if got := defaultSamplingRate("production"); got != 0.1 {
+		t.Errorf("expected 0.1, got %f", got)
+	}
+}
+
+func TestDefaultSamplingRate_Staging(t *testing.T) {
+	if got := defaultSamplingRate("staging"); got != 0.5 {
+		t.Errorf("expected 0.5, got %f", got)
+	}
+}
+
+func TestDefaultSamplingRate_Development(t *testing.T) {
+	if got := defaultSamplingRate("development"); got != 1.0 {
+		t.Errorf("expected 1.0, got %f", got)
+	}
+}
+
+func TestDefaultSamplingRate_UnknownEnvironment(t *testing.T) {
+	if got := defaultSamplingRate("custom-env"); got != 1.0 {
+		t.Errorf("expected 1.0 for unknown env, got %f", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// getInt64Env (additional coverage)
+// ---------------------------------------------------------------------------
+
+func TestGetInt64Env_Default(t *testing.T) {
+	t.Setenv("I64_KEY", "")
+	if got := osEnv.getInt64Env("I64_KEY", 1024); got != 1024 {
+		t.Errorf("expected 1024, got %d", got)
+	}
+}
+
+func TestGetInt64Env_Valid(t *testing.T) {
+	t.Setenv("I64_KEY", "999999999")
+	if got := osEnv.getInt64Env("I64_KEY", 0); got != 999999999 {
+		t.Errorf("expected 999999999, got %d", got)
+	}
+}
+
+func TestGetInt64Env_InvalidFallsBackToDefault(t *testing.T) {
+	t.Setenv("I64_KEY", "xyz")
+	if got := osEnv.getInt64Env("I64_KEY", 512); got != 512 {
+		t.Errorf("expected default 512, got %d", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// loadAuthConfig - SIGNOZ_ACCESS_TOKEN env var handling
+// ---------------------------------------------------------------------------
+
+func TestLoadAuthConfig_NoTokens(t *testing.T) {
+	t.Setenv("SIGNOZ_ACCESS_TOKEN", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "")
+
+	ac := osEnv.loadAuthConfig()
+	// Should have empty but non-nil maps.
+	if ac.Headers == nil {
+		t.Fatal("expected non-nil Headers map")
+	}
+	if len(ac.Headers) != 0 {
+		t.Errorf("expected empty headers, got %v", ac.Headers)
+	}
+}
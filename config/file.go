@@ -0,0 +1,209 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// LoadFromFile reads a YAML (.yaml, .yml) or JSON (.json) configuration file
+// over the Defaults, ignoring the environment. Keys match the json tags of
+// Config; fields absent from the file keep their default. Durations accept Go
+// duration strings ("5s") or integer milliseconds, matching the environment
+// variables.
+//
+// To layer a file between the defaults and the environment, use MergeFile.
+func LoadFromFile(path string) (*Config, error) {
+	cfg := Defaults()
+	if _, err := mergeFile(cfg, path, noEnv); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// MergeFile overlays the settings present in the file onto cfg. Fields whose
+// environment variables (see the env tags) are set are not overwritten, so
+// the environment keeps precedence over the file. Values derived from the
+// ones it sets (the sampling rate and debug mode from the environment, the
+// endpoint from the registry) are refreshed unless set explicitly. It returns
+// the dotted keys that were applied, e.g. "traces.sampling.rate".
+func MergeFile(cfg *Config, path string) ([]string, error) {
+	return mergeFile(cfg, path, osEnv)
+}
+
+func mergeFile(cfg *Config, path string, src envSource) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: failed to read %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return nil, fmt.Errorf("config: failed to parse %s: %w", path, err)
+		}
+	case ".json":
+	default:
+		return nil, fmt.Errorf("config: unsupported file extension %q (use .yaml, .yml or .json)", filepath.Ext(path))
+	}
+
+	var obj map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("config: failed to parse %s: %w", path, err)
+	}
+
+	m := merger{src: src}
+	if err := m.object(reflect.ValueOf(cfg).Elem(), obj, "", ""); err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	src.refreshDerived(cfg, m.applied)
+	return m.applied, nil
+}
+
+// refreshDerived recomputes the values the env loader derives from others
+// when the file set their source but neither the file nor the environment
+// set them.
+func (src envSource) refreshDerived(cfg *Config, keys []string) {
+	applied := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		applied[k] = true
+	}
+
+	if applied["environment"] {
+		env := cfg.Environment
+		if !applied["resource.deployment_environment"] {
+			cfg.Resource.DeploymentEnvironment = env
+		}
+		if !applied["features.debug_mode"] && src("OTEL_DEBUG_MODE") == "" {
+			cfg.Features.DebugMode = env == "development"
+		}
+	}
+
+	if (applied["environment"] || applied["traces.sampling.type"]) &&
+		!applied["traces.sampling.rate"] && src("OTEL_TRACES_SAMPLER_ARG") == "" {
+		cfg.Traces.Sampling.Rate = defaultSamplerArg(cfg.Traces.Sampling.Type, cfg.Environment)
+	}
+
+	if applied["endpoint"] {
+		cfg.Endpoint = stripURLScheme(cfg.Endpoint)
+	} else if src("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		if endpoint, ok := cfg.RegistryEndpoint(); ok {
+			cfg.Endpoint = endpoint
+		}
+	}
+}
+
+type merger struct {
+	src     envSource
+	applied []string
+}
+
+func (m *merger) object(dst reflect.Value, obj map[string]any, prefix, envPrefix string) error {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := prefix + key
+		field, ok := fieldByJSONName(dst.Type(), key)
+		if !ok {
+			return fmt.Errorf("unknown key %q", path)
+		}
+		value := dst.FieldByIndex(field.Index)
+
+		if field.Type.Kind() == reflect.Struct {
+			nested, ok := obj[key].(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s: expected an object", path)
+			}
+			if err := m.object(value, nested, path+".", envPrefix+field.Tag.Get("envPrefix")); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if m.src.envSet(envPrefix, field.Tag.Get("env")) {
+			continue
+		}
+		if err := decodeValue(value, obj[key]); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		m.applied = append(m.applied, path)
+	}
+	return nil
+}
+
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if tag, _, _ := strings.Cut(f.Tag.Get("json"), ","); tag == name {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// envSet reports whether any of the comma-separated env vars is non-empty.
+func (src envSource) envSet(prefix, keys string) bool {
+	if keys == "" {
+		return false
+	}
+	for _, key := range strings.Split(keys, ",") {
+		if src(prefix+key) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeValue(dst reflect.Value, raw any) error {
+	if dst.Type() == durationType {
+		d, err := parseDuration(raw)
+		if err != nil {
+			return err
+		}
+		dst.SetInt(int64(d))
+		return nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	// Decode into a fresh value so maps and slices replace rather than merge.
+	v := reflect.New(dst.Type())
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		return err
+	}
+	dst.Set(v.Elem())
+	return nil
+}
+
+func parseDuration(raw any) (time.Duration, error) {
+	switch v := raw.(type) {
+	case string:
+		return time.ParseDuration(v)
+	case json.Number:
+		ms, err := v.Int64()
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", v.String())
+		}
+		return time.Duration(ms) * time.Millisecond, nil
+	default:
+		return 0, fmt.Errorf("invalid duration %v", raw)
+	}
+}
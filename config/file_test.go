@@ -0,0 +1,131 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFromFile_YAMLAndJSONAreEquivalent(t *testing.T) {
+	yamlPath := writeFile(t, "otel.yaml", `
+service_name: orders
+timeout: 2s
+traces:
+  batch_timeout: 250
+  excluded_paths: [/health]
+route_exclusion:
+  prefix_paths: [/internal]
+resource:
+  custom_attributes:
+    team: payments
+`)
+	jsonPath := writeFile(t, "otel.json", `{
+  "service_name": "orders",
+  "timeout": "2s",
+  "traces": {"batch_timeout": 250, "excluded_paths": ["/health"]},
+  "route_exclusion": {"prefix_paths": ["/internal"]},
+  "resource": {"custom_attributes": {"team": "payments"}}
+}`)
+
+	fromYAML, err := LoadFromFile(yamlPath)
+	if err != nil {
+		t.Fatalf("yaml: %v", err)
+	}
+	fromJSON, err := LoadFromFile(jsonPath)
+	if err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Errorf("yaml and json configs differ:\n%+v\n%+v", fromYAML, fromJSON)
+	}
+
+	if fromYAML.Timeout != 2*time.Second {
+		t.Errorf("Timeout = %v, want 2s", fromYAML.Timeout)
+	}
+	if fromYAML.Traces.BatchTimeout != 250*time.Millisecond {
+		t.Errorf("BatchTimeout = %v, want 250ms (integers are milliseconds)", fromYAML.Traces.BatchTimeout)
+	}
+	if fromYAML.Resource.CustomAttributes["team"] != "payments" {
+		t.Errorf("unexpected custom attributes: %v", fromYAML.Resource.CustomAttributes)
+	}
+}
+
+func TestLoadFromFile_MissingKeysKeepDefaultsAndEnvIsIgnored(t *testing.T) {
+	t.Setenv("OTEL_BSP_MAX_QUEUE_SIZE", "7")
+
+	cfg, err := LoadFromFile(writeFile(t, "otel.yaml", "service_name: orders\nenvironment: production\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Defaults()
+	want.ServiceName = "orders"
+	want.Environment = "production"
+	want.Resource.DeploymentEnvironment = "production"
+	want.Features.DebugMode = false
+	want.Traces.Sampling.Rate = 0.1
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("file config differs from the defaults beyond the file's keys:\n%+v\n%+v", cfg, want)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("defaults plus file should validate: %v", err)
+	}
+}
+
+func TestLoadFromFile_RejectsUnknownKeysAndExtensions(t *testing.T) {
+	if _, err := LoadFromFile(writeFile(t, "otel.yaml", "traces:\n  sample_rate: 1\n")); err == nil {
+		t.Error("expected error for unknown nested key")
+	}
+	if _, err := LoadFromFile(writeFile(t, "otel.toml", "")); err == nil {
+		t.Error("expected error for unsupported extension")
+	}
+	if _, err := LoadFromFile(writeFile(t, "otel.json", `{"timeout": true}`)); err == nil {
+		t.Error("expected error for invalid duration")
+	}
+}
+
+func TestMergeFile_SkipsFieldsSetByEnv(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_COMPRESSION", "none")
+	t.Setenv("OTEL_METRICS_EXCLUDED_PATHS", "/metrics")
+	t.Setenv("OTEL_TRACES_EXCLUDED_PATHS", "")
+
+	cfg := &Config{
+		Compression:          "none",
+		MetricRouteExclusion: RouteExclusionConfig{ExactPaths: []string{"/metrics"}},
+	}
+	path := writeFile(t, "otel.yaml", `
+compression: gzip
+route_exclusion:
+  exact_paths: [/ping]
+metric_route_exclusion:
+  exact_paths: [/status]
+`)
+
+	applied, err := MergeFile(cfg, path)
+	if err != nil {
+		t.Fatalf("MergeFile: %v", err)
+	}
+	if cfg.Compression != "none" {
+		t.Errorf("expected env to keep Compression, got %q", cfg.Compression)
+	}
+	if !reflect.DeepEqual(cfg.MetricRouteExclusion.ExactPaths, []string{"/metrics"}) {
+		t.Errorf("expected env to keep metric exclusions, got %v", cfg.MetricRouteExclusion.ExactPaths)
+	}
+	if !reflect.DeepEqual(cfg.RouteExclusion.ExactPaths, []string{"/ping"}) {
+		t.Errorf("expected file trace exclusions, got %v", cfg.RouteExclusion.ExactPaths)
+	}
+	if !reflect.DeepEqual(applied, []string{"route_exclusion.exact_paths"}) {
+		t.Errorf("applied = %v", applied)
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Configuration sources, lowest precedence first.
+const (
+	SourceDefault = "default"
+	SourceAuto    = "auto"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceOption  = "option"
+)
+
+// Provenance maps the dotted keys of a Config (the json names, as in
+// configuration files, e.g. "traces.sampling.rate") to the source of their
+// value: SourceDefault, SourceAuto, SourceFile, SourceEnv or SourceOption.
+type Provenance map[string]string
+
+// EnvProvenance returns the provenance of a Config loaded from the
+// environment: SourceEnv for fields whose env vars are set, SourceDefault for
+// the others.
+func EnvProvenance() Provenance {
+	p := make(Provenance)
+	walkFields(reflect.ValueOf(Config{}), "", "", func(key, envPrefix string, field reflect.StructField, _ reflect.Value) {
+		p[key] = SourceDefault
+		if osEnv.envSet(envPrefix, field.Tag.Get("env")) {
+			p[key] = SourceEnv
+		}
+	})
+	return p
+}
+
+// Set records source for keys.
+func (p Provenance) Set(source string, keys ...string) {
+	for _, key := range keys {
+		p[key] = source
+	}
+}
+
+// Snapshot captures the field values of a Config so that Changed can report
+// which fields a later step modified.
+type Snapshot map[string]string
+
+// TakeSnapshot returns a Snapshot of cfg.
+func TakeSnapshot(cfg *Config) Snapshot {
+	s := make(Snapshot)
+	walkFields(reflect.ValueOf(cfg).Elem(), "", "", func(key, _ string, _ reflect.StructField, value reflect.Value) {
+		// Maps marshal with sorted keys, so equal values encode equally.
+		data, _ := json.Marshal(value.Interface())
+		s[key] = string(data)
+	})
+	return s
+}
+
+// Changed returns the keys whose value differs between s and after.
+func (s Snapshot) Changed(after Snapshot) []string {
+	var keys []string
+	for key, v := range after {
+		if s[key] != v {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// walkFields calls fn for every non-struct field of v, with its dotted key
+// and the envPrefix of its parents.
+func walkFields(v reflect.Value, prefix, envPrefix string, fn func(key, envPrefix string, field reflect.StructField, value reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		if f.Type.Kind() == reflect.Struct {
+			walkFields(v.Field(i), prefix+name+".", envPrefix+f.Tag.Get("envPrefix"), fn)
+			continue
+		}
+		fn(prefix+name, envPrefix, f, v.Field(i))
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEnvProvenance_UsesEnvTagsAndPrefixes(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_NAME", "checkout")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "tempo:4317")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", "")
+	t.Setenv("OTEL_ENABLED", "")
+	t.Setenv("SIGNOZ_ENABLED", "true")
+
+	p := EnvProvenance()
+	for key, want := range map[string]string{
+		"service_name":              SourceEnv,
+		"enabled":                   SourceEnv,
+		"traces.exporter.endpoint":  SourceEnv,
+		"metrics.exporter.endpoint": SourceDefault,
+	} {
+		if got := p[key]; got != want {
+			t.Errorf("provenance[%q] = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestSnapshot_Changed(t *testing.T) {
+	cfg := &Config{Traces: TracesConfig{Sampling: SamplingConfig{PerRoute: map[string]float64{"/a": 0.1, "/b": 0.2}}}}
+	before := TakeSnapshot(cfg)
+
+	cfg.ServiceName = "api"
+	cfg.Traces.Sampling.PerRoute = map[string]float64{"/b": 0.2, "/a": 0.1}
+	changed := before.Changed(TakeSnapshot(cfg))
+	if !slices.Equal(changed, []string{"service_name"}) {
+		t.Errorf("Changed = %v, want only service_name", changed)
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+import "reflect"
+
+// RedactedValue replaces the values Redacted hides.
+const RedactedValue = "[REDACTED]"
+
+// Redacted returns a copy of c that is safe to print or serve: the values of
+// fields tagged redact:"true", such as credentials in headers and blocked
+// subjects' identifiers, are replaced by RedactedValue. Map keys are kept, so
+// which headers are set stays visible. New secret-bearing fields only need
+// the tag.
+func (c *Config) Redacted() *Config {
+	out := *c
+	redactFields(reflect.ValueOf(&out).Elem())
+	return &out
+}
+
+func redactFields(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f, field := t.Field(i), v.Field(i)
+		switch {
+		case !f.IsExported():
+		case f.Tag.Get("redact") == "true":
+			redactValue(field)
+		case field.Kind() == reflect.Struct:
+			redactFields(field)
+		}
+	}
+}
+
+// redactValue replaces a string, or the string elements of a map or slice,
+// allocating new maps and slices so the original Config is left untouched.
+func redactValue(v reflect.Value) {
+	redacted := reflect.ValueOf(RedactedValue)
+	switch {
+	case v.Kind() == reflect.String && v.Len() > 0:
+		v.SetString(RedactedValue)
+	case v.Kind() == reflect.Map && !v.IsNil() && v.Type().Elem().Kind() == reflect.String:
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, k := range v.MapKeys() {
+			m.SetMapIndex(k, redacted.Convert(v.Type().Elem()))
+		}
+		v.Set(m)
+	case v.Kind() == reflect.Slice && !v.IsNil() && v.Type().Elem().Kind() == reflect.String:
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			s.Index(i).Set(redacted.Convert(v.Type().Elem()))
+		}
+		v.Set(s)
+	}
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRedacted_HidesTaggedFieldsOfACopy(t *testing.T) {
+	cfg := &Config{
+		ServiceName: "api",
+		Auth:        AuthConfig{Headers: map[string]string{"signoz-access-token": "s3cret"}},
+		Traces:      TracesConfig{Exporter: SignalExporterConfig{Headers: map[string]string{"authorization": "Bearer t"}}},
+		Blocklist:   BlocklistConfig{Keys: []string{"user.id"}, Values: []string{"user-42"}},
+	}
+
+	r := cfg.Redacted()
+	if r.Auth.Headers["signoz-access-token"] != RedactedValue || r.Traces.Exporter.Headers["authorization"] != RedactedValue {
+		t.Errorf("headers = %v / %v, want values redacted", r.Auth.Headers, r.Traces.Exporter.Headers)
+	}
+	if !slices.Equal(r.Blocklist.Values, []string{RedactedValue}) {
+		t.Errorf("blocklist values = %v, want redacted", r.Blocklist.Values)
+	}
+	if r.ServiceName != "api" || !slices.Equal(r.Blocklist.Keys, []string{"user.id"}) {
+		t.Errorf("untagged fields changed: %q, %v", r.ServiceName, r.Blocklist.Keys)
+	}
+	if cfg.Auth.Headers["signoz-access-token"] != "s3cret" || cfg.Blocklist.Values[0] != "user-42" {
+		t.Error("Redacted modified the original configuration")
+	}
+}
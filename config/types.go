@@ -6,20 +6,30 @@ import (
 )
 
 // Config holds comprehensive observability configuration.
+//
+// The json tags name the keys used in configuration files and the env tags
+// list the environment variables that set each field (the first non-empty
+// one wins). envPrefix is prepended to the env tags of a nested struct.
 type Config struct {
 	// General settings
-	Enabled     bool   `json:"enabled"`
-	ServiceName string `json:"service_name"`
-	Namespace   string `json:"namespace"`
-	Version     string `json:"version"`
-	Environment string `json:"environment"`
+	Enabled     bool   `json:"enabled" env:"SIGNOZ_ENABLED,OTEL_ENABLED"`
+	ServiceName string `json:"service_name" env:"OTEL_SERVICE_NAME"`
+	Namespace   string `json:"namespace" env:"OTEL_SERVICE_NAMESPACE"`
+	Version     string `json:"version" env:"OTEL_SERVICE_VERSION,VERSION"`
+	Environment string `json:"environment" env:"ENV,DEPLOYMENT_ENVIRONMENT"`
 
 	// Export settings
-	Endpoint         string        `json:"endpoint"`
-	ExporterProtocol string        `json:"exporter_protocol"`
-	Insecure         bool          `json:"insecure"`
-	Timeout          time.Duration `json:"timeout"`
-	Compression      string        `json:"compression"`
+	Endpoint         string        `json:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	ExporterProtocol string        `json:"exporter_protocol" env:"OTEL_EXPORTER_OTLP_PROTOCOL"`
+	Insecure         bool          `json:"insecure" env:"OTEL_EXPORTER_OTLP_INSECURE"`
+	Timeout          time.Duration `json:"timeout" env:"OTEL_EXPORTER_OTLP_TIMEOUT"`
+	Compression      string        `json:"compression" env:"OTEL_EXPORTER_OTLP_COMPRESSION"`
+
+	// Region-aware endpoint selection. EndpointRegistry maps
+	// "<environment>/<region>", "<region>" or "<environment>" to a collector
+	// endpoint; it is used when no endpoint is set explicitly.
+	Region           string            `json:"region" env:"OTEL_REGION,CLOUD_REGION,AWS_REGION"`
+	EndpointRegistry map[string]string `json:"endpoint_registry" env:"OTEL_ENDPOINT_REGISTRY"`
 
 	// Auth for SigNoz Cloud / secured collectors
 	Auth AuthConfig `json:"auth"`
@@ -27,9 +37,17 @@ type Config struct {
 	// TLS configuration
 	TLS TLSConfig `json:"tls"`
 
+	// Stdout exporter settings, used when ExporterProtocol is "stdout"
+	Stdout StdoutConfig `json:"stdout"`
+
 	// Resource attributes
 	Resource ResourceConfig `json:"resource"`
 
+	// Propagators are the context propagation formats used for incoming and
+	// outgoing requests: tracecontext, baggage, b3, b3multi, jaeger, xray,
+	// or none.
+	Propagators []string `json:"propagators" env:"OTEL_PROPAGATORS"`
+
 	// Component-specific settings
 	Traces  TracesConfig  `json:"traces"`
 	Metrics MetricsConfig `json:"metrics"`
@@ -42,201 +60,485 @@ type Config struct {
 	Features FeaturesConfig `json:"features"`
 
 	// Route exclusions
-	RouteExclusion RouteExclusionConfig `json:"route_exclusion"`
+	RouteExclusion RouteExclusionConfig `json:"route_exclusion" envPrefix:"OTEL_TRACES_EXCLUDED_"`
+
+	// Routes excluded from request metrics only (spans are unaffected)
+	MetricRouteExclusion RouteExclusionConfig `json:"metric_route_exclusion" envPrefix:"OTEL_METRICS_EXCLUDED_"`
 
 	// PII scrubbing
 	Scrub ScrubConfig `json:"scrub"`
 
+	// Telemetry suppression for data-removal requests
+	Blocklist BlocklistConfig `json:"blocklist"`
+
 	// HTTP capture settings
 	HTTP HTTPConfig `json:"http"`
 }
 
 // AuthConfig holds authentication headers for OTLP exporters.
 type AuthConfig struct {
-	Headers        map[string]string `json:"headers"`
+	Headers        map[string]string `json:"headers" env:"SIGNOZ_ACCESS_TOKEN,OTEL_EXPORTER_OTLP_HEADERS" redact:"true"`
 	HeadersFromEnv map[string]string `json:"headers_from_env"`
 }
 
+// StdoutConfig configures the stdout exporter for local development. With a
+// Path, telemetry is appended to that file instead of stdout, and the file is
+// rotated once it reaches MaxSizeMB, keeping MaxBackups old files.
+type StdoutConfig struct {
+	Path        string `json:"path" env:"OTEL_EXPORTER_STDOUT_PATH"`
+	PrettyPrint bool   `json:"pretty_print" env:"OTEL_EXPORTER_STDOUT_PRETTY"`
+	MaxSizeMB   int    `json:"max_size_mb" env:"OTEL_EXPORTER_STDOUT_MAX_SIZE_MB"`
+	MaxBackups  int    `json:"max_backups" env:"OTEL_EXPORTER_STDOUT_MAX_BACKUPS"`
+}
+
 // TLSConfig holds TLS settings for OTLP exporters.
 type TLSConfig struct {
-	Insecure           bool   `json:"insecure"`
-	CAFile             string `json:"ca_file"`
-	CertFile           string `json:"cert_file"`
-	KeyFile            string `json:"key_file"`
-	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
-	MinVersion         string `json:"min_version"`
+	Insecure           bool   `json:"insecure" env:"OTEL_EXPORTER_OTLP_INSECURE"`
+	CAFile             string `json:"ca_file" env:"OTEL_EXPORTER_OTLP_CERTIFICATE"`
+	CertFile           string `json:"cert_file" env:"OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE"`
+	KeyFile            string `json:"key_file" env:"OTEL_EXPORTER_OTLP_CLIENT_KEY"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify" env:"OTEL_EXPORTER_OTLP_TLS_SKIP_VERIFY"`
+	MinVersion         string `json:"min_version" env:"OTEL_EXPORTER_OTLP_TLS_MIN_VERSION"`
 }
 
 // ResourceConfig defines resource attributes.
 type ResourceConfig struct {
-	ServiceNamespace      string `json:"service_namespace"`
-	ServiceInstance       string `json:"service_instance"`
-	DeploymentEnvironment string `json:"deployment_environment"`
+	ServiceNamespace      string `json:"service_namespace" env:"OTEL_SERVICE_NAMESPACE"`
+	ServiceInstance       string `json:"service_instance" env:"OTEL_SERVICE_INSTANCE"`
+	DeploymentEnvironment string `json:"deployment_environment" env:"ENV,DEPLOYMENT_ENVIRONMENT"`
+
+	// service.instance.id resolution when ServiceInstance is empty:
+	// "hostname" (default), "pod_uid", "uuid" (random per process) or "file"
+	// (UUID persisted at InstanceIDFile so it survives restarts).
+	InstanceIDStrategy string `json:"instance_id_strategy" env:"OTEL_SERVICE_INSTANCE_ID_STRATEGY"`
+	InstanceIDFile     string `json:"instance_id_file" env:"OTEL_SERVICE_INSTANCE_ID_FILE"`
 
 	// K8s attributes (auto-detected)
-	K8sPodName     string `json:"k8s_pod_name"`
-	K8sPodIP       string `json:"k8s_pod_ip"`
-	K8sNamespace   string `json:"k8s_namespace"`
-	K8sNodeName    string `json:"k8s_node_name"`
-	K8sClusterName string `json:"k8s_cluster_name"`
+	K8sPodName     string `json:"k8s_pod_name" env:"POD_NAME,K8S_POD_NAME"`
+	K8sPodUID      string `json:"k8s_pod_uid" env:"POD_UID,K8S_POD_UID"`
+	K8sPodIP       string `json:"k8s_pod_ip" env:"POD_IP,K8S_POD_IP"`
+	K8sNamespace   string `json:"k8s_namespace" env:"POD_NAMESPACE,K8S_NAMESPACE"`
+	K8sNodeName    string `json:"k8s_node_name" env:"NODE_NAME,K8S_NODE_NAME"`
+	K8sClusterName string `json:"k8s_cluster_name" env:"K8S_CLUSTER_NAME"`
 
 	// Container attributes
-	ContainerName string `json:"container_name"`
-	ContainerID   string `json:"container_id"`
+	ContainerName string `json:"container_name" env:"CONTAINER_NAME"`
+	ContainerID   string `json:"container_id" env:"CONTAINER_ID"`
+
+	// Cloud resource detectors run at startup: "aws", "gcp", "azure". Each
+	// gives up after DetectorTimeout, so startup off-cloud is not blocked.
+	Detectors       []string      `json:"detectors" env:"OTEL_RESOURCE_DETECTORS"`
+	DetectorTimeout time.Duration `json:"detector_timeout" env:"OTEL_RESOURCE_DETECTORS_TIMEOUT"`
 
 	// Custom attributes
-	CustomAttributes map[string]string `json:"custom_attributes"`
+	CustomAttributes map[string]string `json:"custom_attributes" env:"OTEL_RESOURCE_ATTRIBUTES"`
+
+	// Attributes added to the resource of a single signal only
+	PerSignalAttributes PerSignalAttributes `json:"per_signal_attributes"`
+}
+
+// PerSignalAttributes holds resource attributes that only one signal
+// carries, on top of the shared resource. Use it for attributes that are
+// useful on traces or logs but would multiply the number of metric series
+// if every metric carried them. A key also present in the shared resource is
+// overridden for that signal.
+type PerSignalAttributes struct {
+	Traces  map[string]string `json:"traces" env:"OTEL_TRACES_RESOURCE_ATTRIBUTES"`
+	Metrics map[string]string `json:"metrics" env:"OTEL_METRICS_RESOURCE_ATTRIBUTES"`
+	Logs    map[string]string `json:"logs" env:"OTEL_LOGS_RESOURCE_ATTRIBUTES"`
 }
 
 // TracesConfig configures tracing behavior.
 type TracesConfig struct {
-	Enabled bool `json:"enabled"`
+	Enabled bool `json:"enabled" env:"OTEL_TRACES_ENABLED"`
 
 	// Sampling configuration
 	Sampling SamplingConfig `json:"sampling"`
 
 	// Span limits
-	MaxAttributesPerSpan int `json:"max_attributes_per_span"`
-	MaxEventsPerSpan     int `json:"max_events_per_span"`
-	MaxLinksPerSpan      int `json:"max_links_per_span"`
+	MaxAttributesPerSpan int `json:"max_attributes_per_span" env:"OTEL_SPAN_ATTRIBUTE_COUNT_LIMIT"`
+	MaxEventsPerSpan     int `json:"max_events_per_span" env:"OTEL_SPAN_EVENT_COUNT_LIMIT"`
+	MaxLinksPerSpan      int `json:"max_links_per_span" env:"OTEL_SPAN_LINK_COUNT_LIMIT"`
+
+	// RepeatedEventsEvery records only every Nth occurrence of an identical
+	// span event (same name and attributes) within a span, so a hot loop
+	// cannot fill MaxEventsPerSpan; 0 or 1 records every event.
+	RepeatedEventsEvery int `json:"repeated_events_every" env:"OTEL_SPAN_REPEATED_EVENTS_EVERY"`
+
+	// EventPayloadMaxSize bounds the JSON payload, in bytes, that
+	// helper.EmitEvent records on a span event; longer ones are truncated.
+	EventPayloadMaxSize int `json:"event_payload_max_size" env:"OTEL_SPAN_EVENT_PAYLOAD_MAX_SIZE"`
+
+	// Compression collapses runs of consecutive sibling spans with the same
+	// name, such as the cache lookups of a loop, into one span.
+	Compression SpanCompressionConfig `json:"compression"`
+
+	// ErrorStackTraces attaches the caller's stack trace, at most
+	// ErrorStackFrames frames, to errors recorded with
+	// helper.RecordSpanError, with the type and message of up to
+	// ErrorChainDepth wrapped errors.
+	ErrorStackTraces bool `json:"error_stack_traces" env:"OTEL_TRACES_ERROR_STACKTRACES"`
+	ErrorStackFrames int  `json:"error_stack_frames" env:"OTEL_TRACES_ERROR_STACK_FRAMES"`
+	ErrorChainDepth  int  `json:"error_chain_depth" env:"OTEL_TRACES_ERROR_CHAIN_DEPTH"`
+
+	// MonotonicTimestamps takes span and event timestamps from the monotonic
+	// clock anchored to the wall clock, so NTP jumps cannot produce negative
+	// durations. Wall clock skew beyond ClockSkewTolerance is annotated on
+	// the span.
+	MonotonicTimestamps bool          `json:"monotonic_timestamps" env:"OTEL_TRACES_MONOTONIC_TIMESTAMPS"`
+	ClockSkewTolerance  time.Duration `json:"clock_skew_tolerance" env:"OTEL_TRACES_CLOCK_SKEW_TOLERANCE"`
+
+	// RecentSpans is how many finished spans Features.DebugMode keeps in
+	// memory for Agent.RecentSpans; 0 keeps none.
+	RecentSpans int `json:"recent_spans" env:"OTEL_DEBUG_RECENT_SPANS"`
 
 	// Span processors
-	BatchTimeout   time.Duration `json:"batch_timeout"`
-	BatchSize      int           `json:"batch_size"`
-	QueueSize      int           `json:"queue_size"`
-	MaxExportBatch int           `json:"max_export_batch"`
+	BatchTimeout   time.Duration `json:"batch_timeout" env:"OTEL_BSP_SCHEDULE_DELAY"`
+	BatchSize      int           `json:"batch_size" env:"OTEL_BSP_MAX_EXPORT_BATCH_SIZE"`
+	QueueSize      int           `json:"queue_size" env:"OTEL_BSP_MAX_QUEUE_SIZE"`
+	MaxExportBatch int           `json:"max_export_batch" env:"OTEL_BSP_EXPORT_BATCH_SIZE"`
+
+	// DynamicBatching replaces the fixed batch size and schedule delay with
+	// ones that follow the span queue's utilization.
+	DynamicBatching DynamicBatchingConfig `json:"dynamic_batching"`
 
 	// Filtering
-	ExcludedPaths []string `json:"excluded_paths"`
+	ExcludedPaths []string `json:"excluded_paths" env:"OTEL_TRACES_EXCLUDED_PATHS"`
+
+	// MinimalSpans records route, status and duration of every request,
+	// including unsampled ones, in a histogram; full spans stay sampled.
+	MinimalSpans bool `json:"minimal_spans" env:"OTEL_TRACES_MINIMAL_SPANS"`
+
+	// BaggageKeys are baggage members copied onto every span as attributes,
+	// so business context set upstream (tenant.id, user.plan) is queryable.
+	BaggageKeys []string `json:"baggage_keys" env:"OTEL_TRACES_BAGGAGE_KEYS"`
+
+	// Exporter overrides for traces only
+	Exporter SignalExporterConfig `json:"exporter" envPrefix:"OTEL_EXPORTER_OTLP_TRACES_"`
+}
+
+// DynamicBatchingConfig bounds the span batch size and schedule delay when
+// they adapt to queue utilization: bursts grow the batch and shorten the
+// delay, quiet periods shrink the batch and relax the delay.
+type DynamicBatchingConfig struct {
+	Enabled          bool          `json:"enabled" env:"OTEL_BSP_DYNAMIC_ENABLED"`
+	MinBatchSize     int           `json:"min_batch_size" env:"OTEL_BSP_DYNAMIC_MIN_BATCH_SIZE"`
+	MaxBatchSize     int           `json:"max_batch_size" env:"OTEL_BSP_DYNAMIC_MAX_BATCH_SIZE"`
+	MinScheduleDelay time.Duration `json:"min_schedule_delay" env:"OTEL_BSP_DYNAMIC_MIN_SCHEDULE_DELAY"`
+	MaxScheduleDelay time.Duration `json:"max_schedule_delay" env:"OTEL_BSP_DYNAMIC_MAX_SCHEDULE_DELAY"`
+}
+
+// SpanCompressionConfig configures span compression. A run of at least
+// MinSpans consecutive sibling spans with the same name and kind, each
+// lasting at most MaxDuration and without an error status, is exported as
+// its first span, extended to the end of the last one and annotated with the
+// count and the min, max and total duration of the run.
+type SpanCompressionConfig struct {
+	Enabled     bool          `json:"enabled" env:"OTEL_TRACES_COMPRESSION_ENABLED"`
+	MinSpans    int           `json:"min_spans" env:"OTEL_TRACES_COMPRESSION_MIN_SPANS"`
+	MaxDuration time.Duration `json:"max_duration" env:"OTEL_TRACES_COMPRESSION_MAX_DURATION"`
 }
 
 // SamplingConfig defines sampling strategies.
 type SamplingConfig struct {
-	Type     string             `json:"type"`
-	Rate     float64            `json:"rate"`
-	PerRoute map[string]float64 `json:"per_route"` // route -> rate
+	Type     string             `json:"type" env:"OTEL_TRACES_SAMPLER"`
+	Rate     float64            `json:"rate" env:"OTEL_TRACES_SAMPLER_ARG"`
+	PerRoute map[string]float64 `json:"per_route" env:"OTEL_TRACES_SAMPLING_ROUTES"` // route -> rate
+
+	// TenantQuota caps the sampled traces per minute of each tenant, read
+	// from the TenantKey baggage member (default "tenant.id"). TenantQuotas
+	// overrides it per tenant. Zero with no overrides disables quotas.
+	TenantKey    string         `json:"tenant_key" env:"OTEL_TRACES_SAMPLING_TENANT_KEY"`
+	TenantQuota  int            `json:"tenant_quota" env:"OTEL_TRACES_SAMPLING_TENANT_QUOTA"`
+	TenantQuotas map[string]int `json:"tenant_quotas" env:"OTEL_TRACES_SAMPLING_TENANT_QUOTAS"` // tenant -> traces per minute
 }
 
 // MetricsConfig configures metrics behavior.
 type MetricsConfig struct {
-	Enabled bool `json:"enabled"`
+	Enabled bool `json:"enabled" env:"OTEL_METRICS_ENABLED"`
 
 	// Collection intervals
-	DefaultInterval time.Duration `json:"default_interval"`
-	RuntimeInterval time.Duration `json:"runtime_interval"`
+	DefaultInterval time.Duration `json:"default_interval" env:"OTEL_METRIC_EXPORT_INTERVAL"`
+	RuntimeInterval time.Duration `json:"runtime_interval" env:"OTEL_RUNTIME_METRIC_INTERVAL"`
 
 	// Metric types to collect
-	HTTP     bool `json:"http"`
-	Database bool `json:"database"`
-	Redis    bool `json:"redis"`
-	AMQP     bool `json:"amqp"`
-	Runtime  bool `json:"runtime"`
-	Business bool `json:"business"`
+	HTTP     bool `json:"http" env:"OTEL_METRICS_HTTP_ENABLED"`
+	Database bool `json:"database" env:"OTEL_METRICS_DATABASE_ENABLED"`
+	Redis    bool `json:"redis" env:"OTEL_METRICS_REDIS_ENABLED"`
+	AMQP     bool `json:"amqp" env:"OTEL_METRICS_AMQP_ENABLED"`
+	Runtime  bool `json:"runtime" env:"OTEL_METRICS_RUNTIME_ENABLED"`
+	Business bool `json:"business" env:"OTEL_METRICS_BUSINESS_ENABLED"`
 
 	// Resource metrics
-	CPU    bool `json:"cpu"`
-	Memory bool `json:"memory"`
-	Disk   bool `json:"disk"`
+	CPU    bool `json:"cpu" env:"OTEL_METRICS_CPU_ENABLED"`
+	Memory bool `json:"memory" env:"OTEL_METRICS_MEMORY_ENABLED"`
+	Disk   bool `json:"disk" env:"OTEL_METRICS_DISK_ENABLED"`
+
+	// DiskPaths are the filesystems whose usage is reported with Disk.
+	DiskPaths []string `json:"disk_paths" env:"OTEL_METRICS_DISK_PATHS"`
 
 	// Histogram boundaries
-	HTTPLatencyBoundaries []float64 `json:"http_latency_boundaries"`
-	DBLatencyBoundaries   []float64 `json:"db_latency_boundaries"`
+	HTTPLatencyBoundaries []float64 `json:"http_latency_boundaries" env:"OTEL_HTTP_LATENCY_BOUNDARIES"`
+	DBLatencyBoundaries   []float64 `json:"db_latency_boundaries" env:"OTEL_DB_LATENCY_BOUNDARIES"`
+
+	// BaggageKeys are baggage members added as attributes to every
+	// measurement made through the agent's meters.
+	BaggageKeys []string `json:"baggage_keys" env:"OTEL_METRICS_BAGGAGE_KEYS"`
+
+	// TemporalityPreference selects cumulative, delta or lowmemory
+	// temporality for exported metrics. Backends such as Dynatrace or the
+	// Datadog OTLP intake require delta.
+	TemporalityPreference string `json:"temporality_preference" env:"OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE"`
 
 	// Cardinality control
 	Cardinality CardinalityConfig `json:"cardinality"`
+
+	// Exporter overrides for metrics only
+	Exporter SignalExporterConfig `json:"exporter" envPrefix:"OTEL_EXPORTER_OTLP_METRICS_"`
 }
 
 // CardinalityConfig controls metric cardinality.
 type CardinalityConfig struct {
-	DropAttributes     []string `json:"drop_attributes"`
-	MaxAttributeLength int      `json:"max_attribute_length"`
-	UseExponentialHist bool     `json:"use_exponential_hist"`
+	DropAttributes     []string `json:"drop_attributes" env:"OTEL_METRICS_DROP_ATTRIBUTES"`
+	MaxAttributeLength int      `json:"max_attribute_length" env:"OTEL_METRICS_MAX_ATTR_LENGTH"`
+	UseExponentialHist bool     `json:"use_exponential_hist" env:"OTEL_METRICS_EXPONENTIAL_HIST"`
+
+	// MaxSeriesPerInstrument caps the distinct attribute sets of one
+	// instrument per collection; further sets are aggregated into a single
+	// otel.metric.overflow=true data point. Zero or less disables the cap.
+	MaxSeriesPerInstrument int `json:"max_series_per_instrument" env:"OTEL_METRICS_CARDINALITY_LIMIT"`
+
+	// HistogramAggregation overrides the aggregation of individual histograms:
+	// instrument name or path.Match glob -> "explicit" or "exponential".
+	HistogramAggregation map[string]string `json:"histogram_aggregation" env:"OTEL_METRICS_HISTOGRAM_AGGREGATION"`
 }
 
 // LogsConfig configures logging behavior.
 type LogsConfig struct {
-	Enabled bool `json:"enabled"`
+	Enabled bool `json:"enabled" env:"OTEL_LOGS_ENABLED"`
 
-	TraceCorrelation bool     `json:"trace_correlation"`
-	SpanCorrelation  bool     `json:"span_correlation"`
-	ExportLevels     []string `json:"export_levels"`
+	TraceCorrelation bool     `json:"trace_correlation" env:"OTEL_LOGS_TRACE_CORRELATION"`
+	SpanCorrelation  bool     `json:"span_correlation" env:"OTEL_LOGS_SPAN_CORRELATION"`
+	ExportLevels     []string `json:"export_levels" env:"OTEL_LOGS_EXPORT_LEVELS"`
 
-	BatchTimeout time.Duration `json:"batch_timeout"`
-	BatchSize    int           `json:"batch_size"`
-	QueueSize    int           `json:"queue_size"`
+	BatchTimeout time.Duration `json:"batch_timeout" env:"OTEL_BLRP_SCHEDULE_DELAY"`
+	BatchSize    int           `json:"batch_size" env:"OTEL_BLRP_MAX_EXPORT_BATCH_SIZE"`
+	QueueSize    int           `json:"queue_size" env:"OTEL_BLRP_MAX_QUEUE_SIZE"`
 
-	StructuredFields bool              `json:"structured_fields"`
-	CustomFields     map[string]string `json:"custom_fields"`
+	// DropPolicy is what happens to records emitted while the queue is full:
+	// DropOldest, DropNewest or BlockWithTimeout. Empty keeps the SDK batch
+	// processor, which drops the oldest records without counting them.
+	DropPolicy   string        `json:"drop_policy" env:"OTEL_LOGS_DROP_POLICY"`
+	BlockTimeout time.Duration `json:"block_timeout" env:"OTEL_LOGS_BLOCK_TIMEOUT"`
+
+	// SDKVerbosity is the verbosity of the OpenTelemetry SDK's own log
+	// messages written to the agent logger: 0 errors, 1 warnings, 4 info,
+	// 8 debug. Negative discards them.
+	SDKVerbosity int `json:"sdk_verbosity" env:"OTEL_LOGS_SDK_VERBOSITY"`
+
+	StructuredFields bool              `json:"structured_fields" env:"OTEL_LOGS_STRUCTURED"`
+	CustomFields     map[string]string `json:"custom_fields" env:"OTEL_LOGS_CUSTOM_FIELDS"`
+
+	// Exporter overrides for logs only
+	Exporter SignalExporterConfig `json:"exporter" envPrefix:"OTEL_EXPORTER_OTLP_LOGS_"`
+}
+
+// SignalExporterConfig overrides the export settings of a single signal, e.g.
+// to send metrics to a different backend than traces. Empty fields fall back
+// to Config.Endpoint, Config.ExporterProtocol and the Auth headers; Headers,
+// when set, replace the global headers rather than adding to them, so
+// credentials for one backend are not sent to another.
+//
+// For the http protocol Endpoint may be a full URL whose path replaces the
+// default /v1/<signal> path.
+type SignalExporterConfig struct {
+	Endpoint string            `json:"endpoint" env:"ENDPOINT"`
+	Protocol string            `json:"protocol" env:"PROTOCOL"`
+	Headers  map[string]string `json:"headers" env:"HEADERS" redact:"true"`
 }
 
 // PerformanceConfig optimizes performance.
 type PerformanceConfig struct {
-	MaxMemoryUsage     int64   `json:"max_memory_usage"`
-	MemoryLimitPercent int     `json:"memory_limit_percent"`
-	MaxCPUUsage        float64 `json:"max_cpu_usage"`
-	WorkerPoolSize     int     `json:"worker_pool_size"`
-	QueueBufferSize    int     `json:"queue_buffer_size"`
-
-	MaxBatchSize   int           `json:"max_batch_size"`
-	FlushTimeout   time.Duration `json:"flush_timeout"`
-	RetryAttempts  int           `json:"retry_attempts"`
-	RetryBackoff   time.Duration `json:"retry_backoff"`
-	ConnectionPool int           `json:"connection_pool"`
-
-	AdaptiveSampling   bool    `json:"adaptive_sampling"`
-	ErrorSamplingBoost float64 `json:"error_sampling_boost"`
+	MaxMemoryUsage     int64   `json:"max_memory_usage" env:"OTEL_MAX_MEMORY_USAGE"`
+	MemoryLimitPercent int     `json:"memory_limit_percent" env:"OTEL_MEMORY_LIMIT_PERCENT"`
+	MaxCPUUsage        float64 `json:"max_cpu_usage" env:"OTEL_MAX_CPU_USAGE"`
+	WorkerPoolSize     int     `json:"worker_pool_size" env:"OTEL_WORKER_POOL_SIZE"`
+	QueueBufferSize    int     `json:"queue_buffer_size" env:"OTEL_QUEUE_BUFFER_SIZE"`
+
+	// AutoTune sizes queues, batches, the worker pool and the runtime metric
+	// interval from the cgroup memory limit and CPU quota at Init; values set
+	// explicitly are kept. SetMemoryLimit also sets the Go memory limit to
+	// 90% of the container's when GOMEMLIMIT is not set.
+	AutoTune       bool `json:"auto_tune" env:"OTEL_AUTO_TUNE"`
+	SetMemoryLimit bool `json:"set_memory_limit" env:"OTEL_SET_GOMEMLIMIT"`
+
+	MaxBatchSize   int           `json:"max_batch_size" env:"OTEL_MAX_BATCH_SIZE"`
+	FlushTimeout   time.Duration `json:"flush_timeout" env:"OTEL_FLUSH_TIMEOUT"`
+	RetryAttempts  int           `json:"retry_attempts" env:"OTEL_RETRY_ATTEMPTS"`
+	RetryBackoff   time.Duration `json:"retry_backoff" env:"OTEL_RETRY_BACKOFF"`
+	ConnectionPool int           `json:"connection_pool" env:"OTEL_CONNECTION_POOL"`
+
+	// ShutdownTimeout bounds Shutdown, from stopping the collectors to the
+	// last export; 0 leaves it to the caller's context. FlushOnlyShutdown
+	// makes Shutdown flush every signal and keep the providers running, for
+	// serverless runtimes that freeze the process between invocations.
+	ShutdownTimeout   time.Duration `json:"shutdown_timeout" env:"OTEL_SHUTDOWN_TIMEOUT"`
+	FlushOnlyShutdown bool          `json:"flush_only_shutdown" env:"OTEL_SHUTDOWN_FLUSH_ONLY"`
+
+	// Adaptive sampling keeps sampled root spans near TargetSpansPerSecond
+	// (the sampling rate becomes a ceiling) and multiplies the probability of
+	// recently failing operations by ErrorSamplingBoost. It only applies to
+	// ratio-based sampler types. Errors are learned from sampled roots;
+	// ErrorBoostUnsampled also records (without exporting) unsampled roots so
+	// their errors count too, at the cost of a recording span per root.
+	AdaptiveSampling     bool    `json:"adaptive_sampling" env:"OTEL_ADAPTIVE_SAMPLING"`
+	TargetSpansPerSecond float64 `json:"target_spans_per_second" env:"OTEL_ADAPTIVE_SAMPLING_TARGET"`
+	ErrorSamplingBoost   float64 `json:"error_sampling_boost" env:"OTEL_ERROR_SAMPLING_BOOST"`
+	ErrorBoostUnsampled  bool    `json:"error_boost_unsampled" env:"OTEL_ERROR_SAMPLING_BOOST_UNSAMPLED"`
 }
 
 // FeaturesConfig enables/disables specific features.
 type FeaturesConfig struct {
-	AutoHTTP     bool `json:"auto_http"`
-	AutoDatabase bool `json:"auto_database"`
-	AutoRedis    bool `json:"auto_redis"`
-	AutoAMQP     bool `json:"auto_amqp"`
-
-	DistributedTracing bool `json:"distributed_tracing"`
-	ErrorTracking      bool `json:"error_tracking"`
-	PerformanceMonitor bool `json:"performance_monitor"`
-	BusinessMetrics    bool `json:"business_metrics"`
-
-	HealthChecks    bool `json:"health_checks"`
-	ReadinessProbes bool `json:"readiness_probes"`
-	LivenessProbes  bool `json:"liveness_probes"`
-
-	DebugMode bool `json:"debug_mode"`
-	DryRun    bool `json:"dry_run"`
+	AutoHTTP     bool `json:"auto_http" env:"OTEL_AUTO_HTTP"`
+	AutoDatabase bool `json:"auto_database" env:"OTEL_AUTO_DATABASE"`
+	AutoRedis    bool `json:"auto_redis" env:"OTEL_AUTO_REDIS"`
+	AutoAMQP     bool `json:"auto_amqp" env:"OTEL_AUTO_AMQP"`
+	AutoKafka    bool `json:"auto_kafka" env:"OTEL_AUTO_KAFKA"`
+	AutoGRPC     bool `json:"auto_grpc" env:"OTEL_AUTO_GRPC"`
+	AutoGraphQL  bool `json:"auto_graphql" env:"OTEL_AUTO_GRAPHQL"`
+
+	DistributedTracing bool `json:"distributed_tracing" env:"OTEL_DISTRIBUTED_TRACING"`
+	ErrorTracking      bool `json:"error_tracking" env:"OTEL_ERROR_TRACKING"`
+	PerformanceMonitor bool `json:"performance_monitor" env:"OTEL_PERFORMANCE_MONITOR"`
+	BusinessMetrics    bool `json:"business_metrics" env:"OTEL_BUSINESS_METRICS"`
+
+	HealthChecks    bool `json:"health_checks" env:"OTEL_HEALTH_CHECKS"`
+	ReadinessProbes bool `json:"readiness_probes" env:"OTEL_READINESS_PROBES"`
+	LivenessProbes  bool `json:"liveness_probes" env:"OTEL_LIVENESS_PROBES"`
+
+	DebugMode bool `json:"debug_mode" env:"OTEL_DEBUG_MODE"`
+	DryRun    bool `json:"dry_run" env:"OTEL_DRY_RUN"`
+
+	// SelfTelemetry exports otel_agent_* metrics about the agent itself
+	SelfTelemetry bool `json:"self_telemetry" env:"OTEL_AGENT_SELF_TELEMETRY"`
+
+	// ResourceBudget degrades telemetry while the process exceeds the
+	// Performance memory and CPU budgets
+	ResourceBudget bool `json:"resource_budget" env:"OTEL_RESOURCE_BUDGET"`
 }
 
 // RouteExclusionConfig configures route exclusions for tracing and metrics.
 type RouteExclusionConfig struct {
-	ExactPaths  []string `json:"exact_paths"`
-	PrefixPaths []string `json:"prefix_paths"`
-	Patterns    []string `json:"patterns"`
+	ExactPaths  []string `json:"exact_paths" env:"PATHS"`
+	PrefixPaths []string `json:"prefix_paths" env:"PREFIXES"`
+	Patterns    []string `json:"patterns" env:"PATTERNS"`
+
+	// KeepMetrics keeps recording request metrics for routes excluded from
+	// tracing, e.g. to count /health in request-rate metrics used for
+	// autoscaling without tracing it. Ignored for MetricRouteExclusion.
+	KeepMetrics bool `json:"keep_metrics" env:"KEEP_METRICS"`
 }
 
 // ScrubConfig configures PII scrubbing.
 type ScrubConfig struct {
-	Enabled              bool     `json:"enabled"`
-	SensitiveKeys        []string `json:"sensitive_keys"`
-	SensitivePatterns    []string `json:"sensitive_patterns"`
-	RedactedValue        string   `json:"redacted_value"`
-	DBStatementMaxLength int      `json:"db_statement_max_length"`
+	Enabled              bool     `json:"enabled" env:"OTEL_PII_SCRUB_ENABLED"`
+	SensitiveKeys        []string `json:"sensitive_keys" env:"OTEL_PII_SENSITIVE_KEYS"`
+	SensitivePatterns    []string `json:"sensitive_patterns" env:"OTEL_PII_SENSITIVE_PATTERNS"`
+	RedactedValue        string   `json:"redacted_value" env:"OTEL_PII_REDACTED_VALUE"`
+	DBStatementMaxLength int      `json:"db_statement_max_length" env:"OTEL_PII_DB_STATEMENT_MAX_LENGTH"`
+
+	// SQLSanitize replaces the literals in recorded DB statements with ?
+	// placeholders (see scrub.SQL), before truncation.
+	SQLSanitize bool `json:"sql_sanitize" env:"OTEL_PII_SQL_SANITIZE"`
+	// SQLDialect sets the quoting rules of the sanitizer: generic (the
+	// default), postgres, mysql, sqlite or mssql.
+	SQLDialect string `json:"sql_dialect" env:"OTEL_PII_SQL_DIALECT"`
+
+	// Detectors names the built-in value detectors (credit_card, email,
+	// jwt, bearer, ssn, cpf, private_ip) that redact matching values in
+	// span attributes, HTTP bodies and log records whatever their key.
+	Detectors []string `json:"detectors" env:"OTEL_PII_DETECTORS"`
+
+	// CaptureMessagingKeys records message keys (messaging.kafka.message.key)
+	// on messaging spans, scrubbed like HTTP bodies. Keys often carry user or
+	// account IDs, so they are left out by default.
+	CaptureMessagingKeys bool `json:"capture_messaging_keys" env:"OTEL_PII_CAPTURE_MESSAGING_KEYS"`
+}
+
+// BlocklistConfig drops spans and log records that belong to blocked
+// subjects, e.g. users who asked for their data to be removed. A span or log
+// record is blocked when a baggage member or attribute named in Keys has one
+// of Values; the rest of its trace is then blocked too. Values can be
+// changed at runtime through Agent.Blocklist.
+type BlocklistConfig struct {
+	Enabled bool     `json:"enabled" env:"OTEL_BLOCKLIST_ENABLED"`
+	Keys    []string `json:"keys" env:"OTEL_BLOCKLIST_KEYS"`
+	Values  []string `json:"values" env:"OTEL_BLOCKLIST_VALUES" redact:"true"`
 }
 
 // HTTPConfig configures HTTP request/response capture for spans.
 type HTTPConfig struct {
-	CaptureRequestHeaders  bool     `json:"capture_request_headers"`
-	CaptureResponseHeaders bool     `json:"capture_response_headers"`
-	AllowedRequestHeaders  []string `json:"allowed_request_headers"`
-	AllowedResponseHeaders []string `json:"allowed_response_headers"`
-	CaptureQueryParams     bool     `json:"capture_query_params"`
-	CaptureRequestBody     bool     `json:"capture_request_body"`
-	CaptureResponseBody    bool     `json:"capture_response_body"`
-	RequestBodyMaxSize     int      `json:"request_body_max_size"`
-	ResponseBodyMaxSize    int      `json:"response_body_max_size"`
-	BodyAllowedContentTypes []string `json:"body_allowed_content_types"`
-	RecordExceptionEvents  bool     `json:"record_exception_events"`
-	SensitiveHeaders       []string `json:"sensitive_headers"`
+	CaptureRequestHeaders  bool     `json:"capture_request_headers" env:"OTEL_HTTP_CAPTURE_REQUEST_HEADERS"`
+	CaptureResponseHeaders bool     `json:"capture_response_headers" env:"OTEL_HTTP_CAPTURE_RESPONSE_HEADERS"`
+	AllowedRequestHeaders  []string `json:"allowed_request_headers" env:"OTEL_HTTP_ALLOWED_REQUEST_HEADERS"`
+	AllowedResponseHeaders []string `json:"allowed_response_headers" env:"OTEL_HTTP_ALLOWED_RESPONSE_HEADERS"`
+	CaptureQueryParams     bool     `json:"capture_query_params" env:"OTEL_HTTP_CAPTURE_QUERY_PARAMS"`
+	CaptureRequestBody     bool     `json:"capture_request_body" env:"OTEL_HTTP_CAPTURE_REQUEST_BODY"`
+	CaptureResponseBody    bool     `json:"capture_response_body" env:"OTEL_HTTP_CAPTURE_RESPONSE_BODY"`
+	RequestBodyMaxSize     int      `json:"request_body_max_size" env:"OTEL_HTTP_REQUEST_BODY_MAX_SIZE"`
+	ResponseBodyMaxSize    int      `json:"response_body_max_size" env:"OTEL_HTTP_RESPONSE_BODY_MAX_SIZE"`
+	BodyAllowedContentTypes []string `json:"body_allowed_content_types" env:"OTEL_HTTP_BODY_ALLOWED_CONTENT_TYPES"`
+	RecordExceptionEvents  bool     `json:"record_exception_events" env:"OTEL_HTTP_RECORD_EXCEPTION_EVENTS"`
+	SensitiveHeaders       []string `json:"sensitive_headers" env:"OTEL_HTTP_SENSITIVE_HEADERS"`
+
+	// CaptureQueueTime records the time a request spent queued in front of
+	// the service, from load balancer X-Request-Start / X-Queue-Start headers.
+	CaptureQueueTime bool `json:"capture_queue_time" env:"OTEL_HTTP_CAPTURE_QUEUE_TIME"`
+
+	// CaptureMultipartMetadata describes multipart/form-data requests on
+	// the span (part names, file names, sizes and content types) without
+	// recording their contents.
+	CaptureMultipartMetadata bool `json:"capture_multipart_metadata" env:"OTEL_HTTP_CAPTURE_MULTIPART_METADATA"`
+}
+
+// RatioBased reports whether the sampler type samples a ratio of root spans,
+// the types adaptive sampling applies to.
+func (s SamplingConfig) RatioBased() bool {
+	switch s.Type {
+	case "always", "always_on", "never", "always_off", "rate_limited":
+		return false
+	}
+	return true
+}
+
+// SignalExporter returns the endpoint, protocol and headers used to export
+// signal ("traces", "metrics" or "logs") once its overrides are applied.
+func (c *Config) SignalExporter(signal string) SignalExporterConfig {
+	var override SignalExporterConfig
+	switch signal {
+	case "traces":
+		override = c.Traces.Exporter
+	case "metrics":
+		override = c.Metrics.Exporter
+	case "logs":
+		override = c.Logs.Exporter
+	}
+
+	resolved := SignalExporterConfig{
+		Endpoint: c.Endpoint,
+		Protocol: c.ExporterProtocol,
+		Headers:  override.Headers,
+	}
+	if override.Endpoint != "" {
+		resolved.Endpoint = override.Endpoint
+	}
+	if override.Protocol != "" {
+		resolved.Protocol = override.Protocol
+	}
+	if len(resolved.Headers) == 0 {
+		resolved.Headers = c.ResolvedAuthHeaders()
+	}
+	return resolved
 }
 
 // ResolvedAuthHeaders returns all auth headers with env vars resolved.
@@ -0,0 +1,444 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Supported OTLP compression values.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// ProtocolStdout is the exporter protocol that writes telemetry to stdout or
+// a local file instead of sending it to a collector.
+const ProtocolStdout = "stdout"
+
+// Histogram aggregations for CardinalityConfig.HistogramAggregation.
+const (
+	HistogramExplicit    = "explicit"
+	HistogramExponential = "exponential"
+)
+
+// Temporality preferences for MetricsConfig.TemporalityPreference, as in
+// OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE.
+const (
+	TemporalityCumulative = "cumulative"
+	TemporalityDelta      = "delta"
+	TemporalityLowMemory  = "lowmemory"
+)
+
+// Cloud resource detectors for ResourceConfig.Detectors, as in
+// OTEL_RESOURCE_DETECTORS.
+const (
+	DetectorAWS   = "aws"
+	DetectorGCP   = "gcp"
+	DetectorAzure = "azure"
+)
+
+// Log drop policies decide what happens to a log record emitted while the
+// export queue is full.
+const (
+	// DropOldest evicts the oldest queued record to make room.
+	DropOldest = "drop_oldest"
+	// DropNewest discards the record being emitted.
+	DropNewest = "drop_newest"
+	// BlockWithTimeout blocks the emitting goroutine until there is room or
+	// LogsConfig.BlockTimeout passes, then discards the record.
+	BlockWithTimeout = "block_with_timeout"
+)
+
+// customProtocols holds the exporter protocols with a registered factory,
+// as signal -> protocol -> struct{}.
+var customProtocols sync.Map
+
+// RegisterExporterProtocol makes Validate accept protocol for signal
+// ("traces", "metrics" or "logs"). It is called by the provider package's
+// Register*ExporterFactory functions; applications register factories there.
+func RegisterExporterProtocol(signal, protocol string) {
+	protocols, _ := customProtocols.LoadOrStore(signal, &sync.Map{})
+	protocols.(*sync.Map).Store(protocol, struct{}{})
+}
+
+// isCustomProtocol reports whether protocol was registered for any of signals.
+func isCustomProtocol(protocol string, signals ...string) bool {
+	for _, signal := range signals {
+		if protocols, ok := customProtocols.Load(signal); ok {
+			if _, ok := protocols.(*sync.Map).Load(protocol); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NormalizeCompression lowercases and validates an OTLP compression value for
+// the given protocol. An empty value means "none". gRPC supports gzip and
+// none; HTTP additionally supports zstd.
+func NormalizeCompression(protocol, compression string) (string, error) {
+	c := strings.ToLower(strings.TrimSpace(compression))
+	if c == "" {
+		c = CompressionNone
+	}
+
+	switch c {
+	case CompressionNone, CompressionGzip:
+		return c, nil
+	case CompressionZstd:
+		if isHTTPProtocol(protocol) {
+			return c, nil
+		}
+		return "", fmt.Errorf("compression %q is not supported with the grpc protocol (use gzip or none, or switch to http)", compression)
+	default:
+		return "", fmt.Errorf("unsupported compression %q (valid values: gzip, none, zstd for http)", compression)
+	}
+}
+
+func isHTTPProtocol(protocol string) bool {
+	switch strings.ToLower(protocol) {
+	case "http", "http/protobuf":
+		return true
+	}
+	return false
+}
+
+var validSamplerTypes = map[string]bool{
+	"parent_based": true, "parentbased_traceidratio": true,
+	"ratio": true, "traceidratio": true,
+	"always": true, "always_on": true,
+	"never": true, "always_off": true,
+	"rate_limited": true,
+}
+
+var validPropagators = map[string]bool{
+	"tracecontext": true, "baggage": true, "b3": true, "b3multi": true,
+	"jaeger": true, "xray": true, "none": true,
+}
+
+var validInstanceIDStrategies = map[string]bool{
+	"": true, "hostname": true, "pod_uid": true, "uuid": true, "file": true,
+}
+
+var validDropPolicies = map[string]bool{
+	"": true, DropOldest: true, DropNewest: true, BlockWithTimeout: true,
+}
+
+var validLogLevels = map[string]bool{
+	"debug": true, "info": true, "warn": true, "warning": true, "error": true,
+	"dpanic": true, "panic": true, "fatal": true,
+}
+
+var validSQLDialects = map[string]bool{
+	"": true, "generic": true, "postgres": true, "mysql": true, "sqlite": true, "mssql": true,
+}
+
+var validPIIDetectors = map[string]bool{
+	"credit_card": true, "email": true, "jwt": true, "bearer": true,
+	"ssn": true, "cpf": true, "private_ip": true,
+}
+
+var validTLSVersions = map[string]bool{
+	"": true, "1.0": true, "1.1": true, "1.2": true, "1.3": true,
+}
+
+// Validate reports every setting that would make the agent fail to start or
+// silently misbehave. The returned error joins one error per problem.
+// A disabled configuration is always valid.
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.ServiceName == "" {
+		fail("service_name is required (set OTEL_SERVICE_NAME)")
+	}
+
+	// Export
+	if c.ExporterProtocol == ProtocolStdout {
+		if c.Stdout.MaxSizeMB < 0 || c.Stdout.MaxBackups < 0 {
+			fail("stdout.max_size_mb and stdout.max_backups must not be negative")
+		}
+	} else {
+		if c.Endpoint == "" {
+			fail("endpoint is required (set OTEL_EXPORTER_OTLP_ENDPOINT)")
+		}
+		if _, err := NormalizeCompression(c.ExporterProtocol, c.Compression); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	switch c.ExporterProtocol {
+	case "", "grpc", "http", "http/protobuf", ProtocolStdout:
+	default:
+		if !isCustomProtocol(c.ExporterProtocol, "traces", "metrics", "logs") {
+			fail("exporter_protocol %q is not supported (use grpc, http, stdout or a registered exporter factory)", c.ExporterProtocol)
+		}
+	}
+	for _, o := range []struct {
+		signal   string
+		exporter SignalExporterConfig
+	}{
+		{"traces", c.Traces.Exporter},
+		{"metrics", c.Metrics.Exporter},
+		{"logs", c.Logs.Exporter},
+	} {
+		switch o.exporter.Protocol {
+		case "", ProtocolStdout:
+			continue
+		case "grpc", "http", "http/protobuf":
+		default:
+			if !isCustomProtocol(o.exporter.Protocol, o.signal) {
+				fail("%s.exporter.protocol %q is not supported (use grpc, http, stdout or a registered exporter factory)", o.signal, o.exporter.Protocol)
+			}
+			continue
+		}
+		if c.SignalExporter(o.signal).Endpoint == "" {
+			fail("%s.exporter.endpoint is required with protocol %s", o.signal, o.exporter.Protocol)
+		}
+		if _, err := NormalizeCompression(o.exporter.Protocol, c.Compression); err != nil {
+			fail("%s.exporter: %v", o.signal, err)
+		}
+	}
+	for _, signal := range []string{"traces", "metrics", "logs"} {
+		if e := c.SignalExporter(signal); strings.HasPrefix(e.Endpoint, "unix:") && strings.HasPrefix(e.Protocol, "http") {
+			fail("%s: unix socket endpoint %q requires the grpc protocol", signal, e.Endpoint)
+		}
+	}
+	if c.Timeout <= 0 {
+		fail("timeout must be positive, got %v", c.Timeout)
+	}
+
+	// TLS
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		fail("tls.cert_file and tls.key_file must be set together")
+	}
+	if !validTLSVersions[c.TLS.MinVersion] {
+		fail("tls.min_version %q is not supported (use 1.0 to 1.3)", c.TLS.MinVersion)
+	}
+
+	if !validInstanceIDStrategies[strings.ToLower(c.Resource.InstanceIDStrategy)] {
+		fail("resource.instance_id_strategy %q is not supported (use hostname, pod_uid, uuid or file)", c.Resource.InstanceIDStrategy)
+	}
+
+	for _, name := range c.Resource.Detectors {
+		switch name {
+		case DetectorAWS, DetectorGCP, DetectorAzure:
+		default:
+			fail("resource detector %q is not supported (use aws, gcp or azure)", name)
+		}
+	}
+	if len(c.Resource.Detectors) > 0 && c.Resource.DetectorTimeout <= 0 {
+		fail("resource.detector_timeout must be positive, got %v", c.Resource.DetectorTimeout)
+	}
+
+	// Propagation
+	for _, name := range c.Propagators {
+		if !validPropagators[name] {
+			fail("propagator %q is not supported (use tracecontext, baggage, b3, b3multi, jaeger, xray or none)", name)
+		}
+	}
+
+	// Traces
+	if c.Traces.Enabled {
+		if !validSamplerTypes[c.Traces.Sampling.Type] {
+			fail("traces.sampling.type %q is not supported", c.Traces.Sampling.Type)
+		}
+		if r := c.Traces.Sampling.Rate; c.Traces.Sampling.Type == "rate_limited" {
+			if r <= 0 {
+				fail("traces.sampling.rate is the spans-per-second budget of the rate_limited sampler and must be positive, got %v", r)
+			}
+		} else if r < 0 || r > 1 {
+			fail("traces.sampling.rate must be between 0 and 1, got %v", r)
+		}
+		for route, r := range c.Traces.Sampling.PerRoute {
+			if r < 0 || r > 1 {
+				fail("traces.sampling.per_route[%q] must be between 0 and 1, got %v", route, r)
+			}
+		}
+		if c.Traces.Sampling.TenantQuota < 0 {
+			fail("traces.sampling.tenant_quota must not be negative, got %d", c.Traces.Sampling.TenantQuota)
+		}
+		for tenant, q := range c.Traces.Sampling.TenantQuotas {
+			if q < 0 {
+				fail("traces.sampling.tenant_quotas[%q] must not be negative, got %d", tenant, q)
+			}
+		}
+		if c.Traces.QueueSize <= 0 || c.Traces.BatchSize <= 0 {
+			fail("traces.queue_size and traces.batch_size must be positive")
+		} else if c.Traces.BatchSize > c.Traces.QueueSize {
+			fail("traces.batch_size (%d) must not exceed traces.queue_size (%d)", c.Traces.BatchSize, c.Traces.QueueSize)
+		}
+		if d := c.Traces.DynamicBatching; d.Enabled {
+			if d.MinBatchSize <= 0 || d.MinBatchSize > d.MaxBatchSize {
+				fail("traces.dynamic_batching.min_batch_size (%d) must be positive and not exceed max_batch_size (%d)", d.MinBatchSize, d.MaxBatchSize)
+			}
+			if d.MinScheduleDelay <= 0 || d.MinScheduleDelay > d.MaxScheduleDelay {
+				fail("traces.dynamic_batching.min_schedule_delay (%v) must be positive and not exceed max_schedule_delay (%v)", d.MinScheduleDelay, d.MaxScheduleDelay)
+			}
+		}
+		if c.Traces.RepeatedEventsEvery < 0 {
+			fail("traces.repeated_events_every must not be negative, got %d", c.Traces.RepeatedEventsEvery)
+		}
+		if c.Traces.RecentSpans < 0 {
+			fail("traces.recent_spans must not be negative, got %d", c.Traces.RecentSpans)
+		}
+		if c.Traces.EventPayloadMaxSize < 0 {
+			fail("traces.event_payload_max_size must not be negative, got %d", c.Traces.EventPayloadMaxSize)
+		}
+		if sc := c.Traces.Compression; sc.Enabled {
+			if sc.MinSpans < 2 {
+				fail("traces.compression.min_spans must be at least 2, got %d", sc.MinSpans)
+			}
+			if sc.MaxDuration <= 0 {
+				fail("traces.compression.max_duration must be positive, got %v", sc.MaxDuration)
+			}
+		}
+		if c.Traces.ErrorStackTraces {
+			if c.Traces.ErrorStackFrames <= 0 {
+				fail("traces.error_stack_frames must be positive, got %d", c.Traces.ErrorStackFrames)
+			}
+			if c.Traces.ErrorChainDepth < 0 {
+				fail("traces.error_chain_depth must not be negative, got %d", c.Traces.ErrorChainDepth)
+			}
+		}
+		if c.Traces.MonotonicTimestamps && c.Traces.ClockSkewTolerance <= 0 {
+			fail("traces.clock_skew_tolerance must be positive, got %v", c.Traces.ClockSkewTolerance)
+		}
+	}
+
+	if c.Traces.Enabled && c.Performance.AdaptiveSampling && c.Traces.Sampling.RatioBased() {
+		if c.Performance.TargetSpansPerSecond <= 0 {
+			fail("performance.target_spans_per_second must be positive with adaptive sampling, got %v", c.Performance.TargetSpansPerSecond)
+		}
+		if c.Performance.ErrorSamplingBoost < 1 {
+			fail("performance.error_sampling_boost must be at least 1, got %v", c.Performance.ErrorSamplingBoost)
+		}
+	}
+
+	if c.Features.ResourceBudget {
+		if c.Performance.MaxMemoryUsage < 0 {
+			fail("performance.max_memory_usage must not be negative, got %d", c.Performance.MaxMemoryUsage)
+		}
+		if c.Performance.MemoryLimitPercent < 0 || c.Performance.MemoryLimitPercent > 100 {
+			fail("performance.memory_limit_percent must be between 0 and 100, got %d", c.Performance.MemoryLimitPercent)
+		}
+		if c.Performance.MaxCPUUsage < 0 || c.Performance.MaxCPUUsage > 1 {
+			fail("performance.max_cpu_usage is a share of the available CPUs and must be between 0 and 1, got %v", c.Performance.MaxCPUUsage)
+		}
+	}
+	if c.Performance.WorkerPoolSize < 0 {
+		fail("performance.worker_pool_size must not be negative, got %d", c.Performance.WorkerPoolSize)
+	}
+	if c.Performance.QueueBufferSize < 0 {
+		fail("performance.queue_buffer_size must not be negative, got %d", c.Performance.QueueBufferSize)
+	}
+	if c.Performance.ShutdownTimeout < 0 {
+		fail("performance.shutdown_timeout must not be negative, got %v", c.Performance.ShutdownTimeout)
+	}
+
+	// Metrics
+	if c.Metrics.Enabled && c.Metrics.DefaultInterval <= 0 {
+		fail("metrics.default_interval must be positive, got %v", c.Metrics.DefaultInterval)
+	}
+
+	if c.Metrics.Enabled {
+		switch c.Metrics.TemporalityPreference {
+		case "", TemporalityCumulative, TemporalityDelta, TemporalityLowMemory:
+		default:
+			fail("metrics.temporality_preference %q is not supported (use cumulative, delta or lowmemory)", c.Metrics.TemporalityPreference)
+		}
+		for _, name := range slices.Sorted(maps.Keys(c.Metrics.Cardinality.HistogramAggregation)) {
+			if _, err := path.Match(name, ""); err != nil {
+				fail("metrics.cardinality.histogram_aggregation: invalid pattern %q: %v", name, err)
+			}
+			switch agg := c.Metrics.Cardinality.HistogramAggregation[name]; agg {
+			case HistogramExplicit, HistogramExponential:
+			default:
+				fail("metrics.cardinality.histogram_aggregation[%q] %q is not supported (use explicit or exponential)", name, agg)
+			}
+		}
+	}
+
+	// Logs
+	if c.Logs.Enabled {
+		if c.Logs.QueueSize <= 0 || c.Logs.BatchSize <= 0 {
+			fail("logs.queue_size and logs.batch_size must be positive")
+		} else if c.Logs.BatchSize > c.Logs.QueueSize {
+			fail("logs.batch_size (%d) must not exceed logs.queue_size (%d)", c.Logs.BatchSize, c.Logs.QueueSize)
+		}
+		if !validDropPolicies[c.Logs.DropPolicy] {
+			fail("logs.drop_policy %q is not supported (use drop_oldest, drop_newest or block_with_timeout)", c.Logs.DropPolicy)
+		} else if c.Logs.DropPolicy == BlockWithTimeout && c.Logs.BlockTimeout <= 0 {
+			fail("logs.block_timeout must be positive with block_with_timeout, got %v", c.Logs.BlockTimeout)
+		}
+		for _, level := range c.Logs.ExportLevels {
+			if !validLogLevels[strings.ToLower(strings.TrimSpace(level))] {
+				fail("logs.export_levels contains unknown level %q (use debug, info, warn or error)", level)
+			}
+		}
+	}
+
+	// Route exclusions use path.Match globs; a bad pattern never matches.
+	for name, rc := range map[string]RouteExclusionConfig{
+		"route_exclusion":        c.RouteExclusion,
+		"metric_route_exclusion": c.MetricRouteExclusion,
+	} {
+		for _, p := range rc.Patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				fail("%s.patterns: invalid pattern %q: %v", name, p, err)
+			}
+		}
+	}
+
+	if !validSQLDialects[c.Scrub.SQLDialect] {
+		fail("scrub.sql_dialect must be generic, postgres, mysql, sqlite or mssql, got %q", c.Scrub.SQLDialect)
+	}
+
+	for _, d := range c.Scrub.Detectors {
+		if !validPIIDetectors[d] {
+			fail("scrub.detectors contains unknown detector %q (use credit_card, email, jwt, bearer, ssn, cpf or private_ip)", d)
+		}
+	}
+
+	// Scrub patterns that fail to compile are skipped at runtime.
+	if c.Scrub.Enabled {
+		_, invalid := CompilePatterns(c.Scrub.SensitivePatterns)
+		for _, err := range invalid {
+			fail("scrub.sensitive_patterns: %v", err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// CompilePatterns compiles scrub patterns, returning the valid ones and one
+// error per pattern that failed to compile. Scrubbers skip invalid patterns,
+// so callers should surface the errors: data those rules were meant to
+// cover is left unredacted.
+func CompilePatterns(patterns []string) ([]*regexp.Regexp, []error) {
+	var (
+		compiled []*regexp.Regexp
+		invalid  []error
+	)
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			invalid = append(invalid, fmt.Errorf("invalid pattern %q: %w", p, err))
+			continue
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, invalid
+}
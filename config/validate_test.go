@@ -0,0 +1,343 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func validConfig() *Config {
+	return &Config{
+		Enabled:          true,
+		ServiceName:      "orders",
+		Endpoint:         "collector:4317",
+		ExporterProtocol: "grpc",
+		Compression:      "gzip",
+		Timeout:          10 * time.Second,
+		TLS:              TLSConfig{MinVersion: "1.2"},
+		Resource:         ResourceConfig{InstanceIDStrategy: "hostname"},
+		Traces: TracesConfig{
+			Enabled:   true,
+			Sampling:  SamplingConfig{Type: "parent_based", Rate: 0.5},
+			BatchSize: 512,
+			QueueSize: 2048,
+		},
+		Metrics: MetricsConfig{Enabled: true, DefaultInterval: 30 * time.Second},
+		Logs:    LogsConfig{Enabled: true, BatchSize: 512, QueueSize: 2048},
+	}
+}
+
+func TestValidate_AcceptsValidConfig(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	cfg := validConfig()
+	cfg.ServiceName = ""
+	cfg.Compression = "zstd"
+	cfg.Traces.Sampling.Rate = 1.5
+	cfg.Traces.BatchSize = 4096
+	cfg.RouteExclusion.Patterns = []string{"/api/[v1"}
+	cfg.Scrub = ScrubConfig{Enabled: true, SensitivePatterns: []string{"(unclosed"}}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{
+		"service_name",
+		"compression \"zstd\"",
+		"traces.sampling.rate",
+		"traces.batch_size",
+		"route_exclusion.patterns",
+		"scrub.sensitive_patterns",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidate_RateLimitedSamplerArgIsABudget(t *testing.T) {
+	cfg := validConfig()
+	cfg.Traces.Sampling = SamplingConfig{Type: "rate_limited", Rate: 250}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error for 250 spans/s: %v", err)
+	}
+
+	cfg.Traces.Sampling.Rate = 0
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "rate_limited") {
+		t.Errorf("expected rate_limited budget error, got %v", err)
+	}
+}
+
+func TestValidate_StdoutProtocolNeedsNoEndpoint(t *testing.T) {
+	cfg := validConfig()
+	cfg.ExporterProtocol = ProtocolStdout
+	cfg.Endpoint = ""
+	cfg.Compression = "zstd"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Stdout.MaxBackups = -1
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "stdout.max_backups") {
+		t.Errorf("expected stdout.max_backups error, got %v", err)
+	}
+}
+
+func TestValidate_DisabledConfigIsValid(t *testing.T) {
+	if err := (&Config{}).Validate(); err != nil {
+		t.Errorf("unexpected error for disabled config: %v", err)
+	}
+}
+
+func TestValidate_SignalExporterOverrides(t *testing.T) {
+	cfg := validConfig()
+	cfg.Metrics.Exporter = SignalExporterConfig{Endpoint: "https://mimir.example.com/otlp/v1/metrics", Protocol: "http"}
+	cfg.Logs.Exporter = SignalExporterConfig{Protocol: ProtocolStdout}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Compression = "zstd"
+	cfg.ExporterProtocol = "http"
+	cfg.Traces.Exporter.Protocol = "grpc"
+	cfg.Metrics.Exporter.Protocol = "thrift"
+	err := cfg.Validate()
+	for _, want := range []string{"traces.exporter: ", "metrics.exporter.protocol \"thrift\""} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q, got %v", want, err)
+		}
+	}
+}
+
+func TestConfig_SignalExporter(t *testing.T) {
+	cfg := validConfig()
+	cfg.Auth.Headers = map[string]string{"signoz-access-token": "secret"}
+	cfg.Metrics.Exporter = SignalExporterConfig{
+		Endpoint: "mimir:4318",
+		Protocol: "http",
+		Headers:  map[string]string{"X-Scope-OrgID": "team-a"},
+	}
+
+	traces := cfg.SignalExporter("traces")
+	if traces.Endpoint != "collector:4317" || traces.Protocol != "grpc" || traces.Headers["signoz-access-token"] != "secret" {
+		t.Errorf("traces = %+v, want the global settings", traces)
+	}
+
+	metrics := cfg.SignalExporter("metrics")
+	if metrics.Endpoint != "mimir:4318" || metrics.Protocol != "http" {
+		t.Errorf("metrics = %+v, want the override", metrics)
+	}
+	if _, leaked := metrics.Headers["signoz-access-token"]; leaked || metrics.Headers["X-Scope-OrgID"] != "team-a" {
+		t.Errorf("metrics headers = %v, want only the override headers", metrics.Headers)
+	}
+}
+
+func TestValidate_UnixSocketRequiresGRPC(t *testing.T) {
+	cfg := validConfig()
+	cfg.Endpoint = "unix:///var/run/otel/collector.sock"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Metrics.Exporter.Protocol = "http"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "metrics: unix socket endpoint") {
+		t.Errorf("expected unix socket error for metrics, got %v", err)
+	}
+}
+
+func TestValidate_DynamicBatchingBounds(t *testing.T) {
+	cfg := validConfig()
+	cfg.Traces.DynamicBatching = DynamicBatchingConfig{
+		Enabled:          true,
+		MinBatchSize:     64,
+		MaxBatchSize:     2048,
+		MinScheduleDelay: 200 * time.Millisecond,
+		MaxScheduleDelay: 5 * time.Second,
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Traces.DynamicBatching.MinBatchSize = 4096
+	cfg.Traces.DynamicBatching.MaxScheduleDelay = 0
+	err := cfg.Validate()
+	for _, want := range []string{"dynamic_batching.min_batch_size", "dynamic_batching.min_schedule_delay"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func TestValidate_SpanCompression(t *testing.T) {
+	cfg := validConfig()
+	cfg.Traces.Compression = SpanCompressionConfig{Enabled: true, MinSpans: 5, MaxDuration: 50 * time.Millisecond}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Traces.Compression.MinSpans = 1
+	cfg.Traces.Compression.MaxDuration = 0
+	err := cfg.Validate()
+	for _, want := range []string{"compression.min_spans", "compression.max_duration"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func TestValidate_LogDropPolicy(t *testing.T) {
+	cfg := validConfig()
+	cfg.Logs.DropPolicy = BlockWithTimeout
+	cfg.Logs.BlockTimeout = 100 * time.Millisecond
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Logs.BlockTimeout = 0
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "logs.block_timeout") {
+		t.Errorf("expected block_timeout error, got %v", err)
+	}
+
+	cfg.Logs.DropPolicy = "drop_everything"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "logs.drop_policy") {
+		t.Errorf("expected drop_policy error, got %v", err)
+	}
+}
+
+func TestValidate_LogExportLevels(t *testing.T) {
+	cfg := validConfig()
+	cfg.Logs.ExportLevels = []string{"info", "WARNING", "error"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Logs.ExportLevels = []string{"info", "verbose"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "logs.export_levels") {
+		t.Errorf("expected export_levels error, got %v", err)
+	}
+}
+
+func TestValidate_SQLDialect(t *testing.T) {
+	cfg := validConfig()
+	cfg.Scrub.SQLDialect = "postgres"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Scrub.SQLDialect = "oracle"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "scrub.sql_dialect") {
+		t.Errorf("expected sql_dialect error, got %v", err)
+	}
+}
+
+func TestValidate_PIIDetectors(t *testing.T) {
+	cfg := validConfig()
+	cfg.Scrub.Detectors = []string{"credit_card", "email", "jwt"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Scrub.Detectors = []string{"email", "passport"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "scrub.detectors") {
+		t.Errorf("expected detectors error, got %v", err)
+	}
+}
+
+func TestValidate_HistogramAggregation(t *testing.T) {
+	cfg := validConfig()
+	cfg.Metrics.Cardinality.HistogramAggregation = map[string]string{
+		"http.server.request.duration": HistogramExplicit,
+		"db.*":                         HistogramExponential,
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Metrics.Cardinality.HistogramAggregation["rpc.*"] = "summary"
+	cfg.Metrics.Cardinality.HistogramAggregation["[bad"] = HistogramExplicit
+	err := cfg.Validate()
+	for _, want := range []string{`histogram_aggregation["rpc.*"] "summary"`, `invalid pattern "[bad"`} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func TestValidate_TemporalityPreference(t *testing.T) {
+	cfg := validConfig()
+	cfg.Metrics.TemporalityPreference = TemporalityDelta
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Metrics.TemporalityPreference = "sometimes"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "metrics.temporality_preference") {
+		t.Errorf("expected temporality_preference error, got %v", err)
+	}
+}
+
+func TestValidate_Propagators(t *testing.T) {
+	cfg := validConfig()
+	cfg.Propagators = []string{"tracecontext", "baggage", "b3multi", "jaeger", "xray"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Propagators = []string{"tracecontext", "ottrace"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "ottrace") {
+		t.Errorf("expected propagator error, got %v", err)
+	}
+}
+
+func TestValidate_TenantQuotas(t *testing.T) {
+	cfg := validConfig()
+	cfg.Traces.Sampling.TenantQuota = 100
+	cfg.Traces.Sampling.TenantQuotas = map[string]int{"enterprise": 1000, "blocked": 0}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Traces.Sampling.TenantQuotas = map[string]int{"acme": -1}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "acme") {
+		t.Errorf("expected tenant quota error, got %v", err)
+	}
+}
+
+func TestValidate_ClockSkewTolerance(t *testing.T) {
+	cfg := validConfig()
+	cfg.Traces.MonotonicTimestamps = true
+	cfg.Traces.ClockSkewTolerance = 0
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "clock_skew_tolerance") {
+		t.Errorf("expected clock skew tolerance error, got %v", err)
+	}
+
+	cfg.Traces.ClockSkewTolerance = time.Second
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidate_ResourceDetectors(t *testing.T) {
+	cfg := validConfig()
+	cfg.Resource.Detectors = []string{DetectorAWS, DetectorGCP, DetectorAzure}
+	cfg.Resource.DetectorTimeout = time.Second
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Resource.Detectors = []string{"oracle"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "oracle") {
+		t.Errorf("expected detector error, got %v", err)
+	}
+
+	cfg.Resource.Detectors = []string{DetectorAWS}
+	cfg.Resource.DetectorTimeout = 0
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "detector_timeout") {
+		t.Errorf("expected detector_timeout error, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package otelagent
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -117,6 +118,22 @@ func TestLoadConfigFromEnv_SamplingRateProductionDefault(t *testing.T) {
 	}
 }
 
+func TestLoadConfigFromEnv_RateLimitedSamplerDefaultsToSpansPerSecond(t *testing.T) {
+	t.Setenv("ENV", "production")
+	t.Setenv("OTEL_SERVICE_NAME", "orders")
+	t.Setenv("OTEL_TRACES_SAMPLER", "rate_limited")
+	t.Setenv("OTEL_TRACES_SAMPLER_ARG", "")
+
+	cfg := LoadConfigFromEnv()
+
+	if cfg.Traces.Sampling.Rate != 100 {
+		t.Errorf("expected rate_limited default of 100 spans/s, got %v", cfg.Traces.Sampling.Rate)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+}
+
 func TestLoadConfigFromEnv_DebugModeDefaultsByEnvironment(t *testing.T) {
 	tests := []struct {
 		env       string
@@ -138,387 +155,6 @@ func TestLoadConfigFromEnv_DebugModeDefaultsByEnvironment(t *testing.T) {
 	}
 }
 
-// ---------------------------------------------------------------------------
-// getStringEnv
-// ---------------------------------------------------------------------------
-
-func TestGetStringEnv_DefaultWhenNoVars(t *testing.T) {
-	t.Setenv("KEY_A", "")
-	t.Setenv("KEY_B", "")
-
-	got := getStringEnv("fallback", "KEY_A", "KEY_B")
-	if got != "fallback" {
-		t.Errorf("expected 'fallback', got %q", got)
-	}
-}
-
-func TestGetStringEnv_FirstNonEmptyWins(t *testing.T) {
-	t.Setenv("KEY_A", "")
-	t.Setenv("KEY_B", "value-b")
-	t.Setenv("KEY_C", "value-c")
-
-	got := getStringEnv("fallback", "KEY_A", "KEY_B", "KEY_C")
-	if got != "value-b" {
-		t.Errorf("expected 'value-b', got %q", got)
-	}
-}
-
-func TestGetStringEnv_FirstKeyTakesPrecedence(t *testing.T) {
-	t.Setenv("KEY_A", "value-a")
-	t.Setenv("KEY_B", "value-b")
-
-	got := getStringEnv("fallback", "KEY_A", "KEY_B")
-	if got != "value-a" {
-		t.Errorf("expected 'value-a', got %q", got)
-	}
-}
-
-// ---------------------------------------------------------------------------
-// getBoolEnv
-// ---------------------------------------------------------------------------
-
-func TestGetBoolEnv_DefaultWhenUnset(t *testing.T) {
-	t.Setenv("BOOL_KEY", "")
-
-	if got := getBoolEnv(true, "BOOL_KEY"); !got {
-		t.Error("expected default true")
-	}
-	if got := getBoolEnv(false, "BOOL_KEY"); got {
-		t.Error("expected default false")
-	}
-}
-
-func TestGetBoolEnv_TruthyValues(t *testing.T) {
-	truthy := []string{"true", "1", "yes"}
-	for _, v := range truthy {
-		t.Run(v, func(t *testing.T) {
-			t.Setenv("BOOL_KEY", v)
-			if got := getBoolEnv(false, "BOOL_KEY"); !got {
-				t.Errorf("expected true for %q", v)
-			}
-		})
-	}
-}
-
-func TestGetBoolEnv_FalsyValues(t *testing.T) {
-	falsy := []string{"false", "0", "no", "random"}
-	for _, v := range falsy {
-		t.Run(v, func(t *testing.T) {
-			t.Setenv("BOOL_KEY", v)
-			if got := getBoolEnv(true, "BOOL_KEY"); got {
-				t.Errorf("expected false for %q", v)
-			}
-		})
-	}
-}
-
-func TestGetBoolEnv_MultipleKeys(t *testing.T) {
-	t.Setenv("BOOL_A", "")
-	t.Setenv("BOOL_B", "true")
-
-	if got := getBoolEnv(false, "BOOL_A", "BOOL_B"); !got {
-		t.Error("expected true from second key")
-	}
-}
-
-// ---------------------------------------------------------------------------
-// getIntEnv
-// ---------------------------------------------------------------------------
-
-func TestGetIntEnv_Default(t *testing.T) {
-	t.Setenv("INT_KEY", "")
-	if got := getIntEnv("INT_KEY", 42); got != 42 {
-		t.Errorf("expected 42, got %d", got)
-	}
-}
-
-func TestGetIntEnv_Valid(t *testing.T) {
-	t.Setenv("INT_KEY", "100")
-	if got := getIntEnv("INT_KEY", 42); got != 100 {
-		t.Errorf("expected 100, got %d", got)
-	}
-}
-
-func TestGetIntEnv_InvalidFallsBackToDefault(t *testing.T) {
-	t.Setenv("INT_KEY", "not-a-number")
-	if got := getIntEnv("INT_KEY", 42); got != 42 {
-		t.Errorf("expected default 42 for invalid input, got %d", got)
-	}
-}
-
-// ---------------------------------------------------------------------------
-// getFloat64Env
-// ---------------------------------------------------------------------------
-
-func TestGetFloat64Env_Default(t *testing.T) {
-	t.Setenv("FLOAT_KEY", "")
-	if got := getFloat64Env("FLOAT_KEY", 3.14); got != 3.14 {
-		t.Errorf("expected 3.14, got %f", got)
-	}
-}
-
-func TestGetFloat64Env_Valid(t *testing.T) {
-	t.Setenv("FLOAT_KEY", "0.75")
-	if got := getFloat64Env("FLOAT_KEY", 0.0); got != 0.75 {
-		t.Errorf("expected 0.75, got %f", got)
-	}
-}
-
-func TestGetFloat64Env_InvalidFallsBackToDefault(t *testing.T) {
-	t.Setenv("FLOAT_KEY", "abc")
-	if got := getFloat64Env("FLOAT_KEY", 1.5); got != 1.5 {
-		t.Errorf("expected default 1.5 for invalid input, got %f", got)
-	}
-}
-
-// ---------------------------------------------------------------------------
-// getDurationEnv
-// ---------------------------------------------------------------------------
-
-func TestGetDurationEnv_Default(t *testing.T) {
-	t.Setenv("DUR_KEY", "")
-	if got := getDurationEnv("DUR_KEY", 5*time.Second); got != 5*time.Second {
-		t.Errorf("expected 5s, got %v", got)
-	}
-}
-
-func TestGetDurationEnv_GoDurationString(t *testing.T) {
-	t.Setenv("DUR_KEY", "30s")
-	if got := getDurationEnv("DUR_KEY", 0); got != 30*time.Second {
-		t.Errorf("expected 30s, got %v", got)
-	}
-}
-
-func TestGetDurationEnv_MillisecondsInteger(t *testing.T) {
-	t.Setenv("DUR_KEY", "500")
-	if got := getDurationEnv("DUR_KEY", 0); got != 500*time.Millisecond {
-		t.Errorf("expected 500ms, got %v", got)
-	}
-}
-
-func TestGetDurationEnv_InvalidFallsBackToDefault(t *testing.T) {
-	t.Setenv("DUR_KEY", "not-a-duration")
-	if got := getDurationEnv("DUR_KEY", 10*time.Second); got != 10*time.Second {
-		t.Errorf("expected default 10s for invalid input, got %v", got)
-	}
-}
-
-// ---------------------------------------------------------------------------
-// getStringSliceEnv
-// ---------------------------------------------------------------------------
-
-func TestGetStringSliceEnv_Default(t *testing.T) {
-	t.Setenv("SLICE_KEY", "")
-	def := []string{"a", "b"}
-	got := getStringSliceEnv("SLICE_KEY", def)
-	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
-		t.Errorf("expected default [a b], got %v", got)
-	}
-}
-
-func TestGetStringSliceEnv_CommaSeparated(t *testing.T) {
-	t.Setenv("SLICE_KEY", " foo , bar , baz ")
-	got := getStringSliceEnv("SLICE_KEY", nil)
-	if len(got) != 3 || got[0] != "foo" || got[1] != "bar" || got[2] != "baz" {
-		t.Errorf("expected [foo bar baz], got %v", got)
-	}
-}
-
-func TestGetStringSliceEnv_EmptyItemsFiltered(t *testing.T) {
-	t.Setenv("SLICE_KEY", "a,,b, ,c")
-	got := getStringSliceEnv("SLICE_KEY", nil)
-	if len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
-		t.Errorf("expected [a b c], got %v", got)
-	}
-}
-
-// ---------------------------------------------------------------------------
-// getFloat64SliceEnv
-// ---------------------------------------------------------------------------
-
-func TestGetFloat64SliceEnv_Default(t *testing.T) {
-	t.Setenv("FSLICE_KEY", "")
-	def := []float64{1.0, 2.0}
-	got := getFloat64SliceEnv("FSLICE_KEY", def)
-	if len(got) != 2 || got[0] != 1.0 || got[1] != 2.0 {
-		t.Errorf("expected default [1 2], got %v", got)
-	}
-}
-
-func TestGetFloat64SliceEnv_CommaSeparated(t *testing.T) {
-	t.Setenv("FSLICE_KEY", "0.5, 1.0, 2.5")
-	got := getFloat64SliceEnv("FSLICE_KEY", nil)
-	if len(got) != 3 || got[0] != 0.5 || got[1] != 1.0 || got[2] != 2.5 {
-		t.Errorf("expected [0.5 1 2.5], got %v", got)
-	}
-}
-
-func TestGetFloat64SliceEnv_InvalidEntriesSkipped(t *testing.T) {
-	t.Setenv("FSLICE_KEY", "1.0, bad, 3.0")
-	got := getFloat64SliceEnv("FSLICE_KEY", nil)
-	if len(got) != 2 || got[0] != 1.0 || got[1] != 3.0 {
-		t.Errorf("expected [1 3], got %v", got)
-	}
-}
-
-// ---------------------------------------------------------------------------
-// parsePerRouteSampling
-// ---------------------------------------------------------------------------
-
-func TestParsePerRouteSampling_Empty(t *testing.T) {
-	got := parsePerRouteSampling("")
-	if len(got) != 0 {
-		t.Errorf("expected empty map, got %v", got)
-	}
-}
-
-func TestParsePerRouteSampling_SingleRoute(t *testing.T) {
-	got := parsePerRouteSampling("/api/health:0.01")
-	if rate, ok := got["/api/health"]; !ok || rate != 0.01 {
-		t.Errorf("expected /api/health -> 0.01, got %v", got)
-	}
-}
-
-func TestParsePerRouteSampling_MultipleRoutes(t *testing.T) {
-	got := parsePerRouteSampling("/health:0.01, /api:0.5")
-	if len(got) != 2 {
-		t.Errorf("expected 2 entries, got %d", len(got))
-	}
-	if rate := got["/health"]; rate != 0.01 {
-		t.Errorf("expected /health -> 0.01, got %f", rate)
-	}
-	if rate := got["/api"]; rate != 0.5 {
-		t.Errorf("expected /api -> 0.5, got %f", rate)
-	}
-}
-
-func TestParsePerRouteSampling_InvalidRateSkipped(t *testing.T) {
-	got := parsePerRouteSampling("/good:0.5,/bad:notanumber,/also-good:0.1")
-	if len(got) != 2 {
-		t.Errorf("expected 2 valid entries, got %d: %v", len(got), got)
-	}
-}
-
-func TestParsePerRouteSampling_MalformedEntrySkipped(t *testing.T) {
-	got := parsePerRouteSampling("/health:0.5,nocolon,/api:1.0")
-	if len(got) != 2 {
-		t.Errorf("expected 2 entries (malformed skipped), got %d", len(got))
-	}
-}
-
-// ---------------------------------------------------------------------------
-// parseKeyValuePairs
-// ---------------------------------------------------------------------------
-
-func TestParseKeyValuePairs_Empty(t *testing.T) {
-	got := parseKeyValuePairs("")
-	if len(got) != 0 {
-		t.Errorf("expected empty map, got %v", got)
-	}
-}
-
-func TestParseKeyValuePairs_SinglePair(t *testing.T) {
-	got := parseKeyValuePairs("env=prod")
-	if got["env"] != "prod" {
-		t.Errorf("expected env=prod, got %v", got)
-	}
-}
-
-func TestParseKeyValuePairs_MultiplePairs(t *testing.T) {
-	got := parseKeyValuePairs("env=prod, region=us-east-1")
-	if len(got) != 2 {
-		t.Errorf("expected 2 pairs, got %d", len(got))
-	}
-	if got["env"] != "prod" {
-		t.Errorf("expected env=prod, got %q", got["env"])
-	}
-	if got["region"] != "us-east-1" {
-		t.Errorf("expected region=us-east-1, got %q", got["region"])
-	}
-}
-
-func TestParseKeyValuePairs_ValueWithEquals(t *testing.T) {
-	got := parseKeyValuePairs("url=https://host?a=b")
-	if got["url"] != "https://host?a=b" {
-		t.Errorf("expected value with equals preserved, got %q", got["url"])
-	}
-}
-
-func TestParseKeyValuePairs_MalformedEntrySkipped(t *testing.T) {
-	got := parseKeyValuePairs("good=value,noequals,also_good=ok")
-	if len(got) != 2 {
-		t.Errorf("expected 2 valid pairs, got %d: %v", len(got), got)
-	}
-}
-
-// ---------------------------------------------------------------------------
-// stripURLScheme
-// ---------------------------------------------------------------------------
-
-func TestStripURLScheme_EmptyString(t *testing.T) {
-	got := stripURLScheme("")
-	if got != "" {
-		t.Errorf("expected empty string, got %q", got)
-	}
-}
-
-func TestStripURLScheme_HTTPSUrl(t *testing.T) {
-	got := stripURLScheme("https://collector.example.com:4317")
-	if got != "collector.example.com:4317" {
-		t.Errorf("expected 'collector.example.com:4317', got %q", got)
-	}
-}
-
-func TestStripURLScheme_HTTPUrl(t *testing.T) {
-	got := stripURLScheme("http://localhost:4317")
-	if got != "localhost:4317" {
-		t.Errorf("expected 'localhost:4317', got %q", got)
-	}
-}
-
-func TestStripURLScheme_NoScheme(t *testing.T) {
-	got := stripURLScheme("collector.example.com:4317")
-	// url.Parse without scheme will not populate Host, so the original is returned.
-	if got != "collector.example.com:4317" {
-		t.Errorf("expected original value returned, got %q", got)
-	}
-}
-
-func TestStripURLScheme_GRPCScheme(t *testing.T) {
-	got := stripURLScheme("grpc://collector.example.com:4317")
-	if got != "collector.example.com:4317" {
-		t.Errorf("expected 'collector.example.com:4317', got %q", got)
-	}
-}
-
-// ---------------------------------------------------------------------------
-// defaultSamplingRate
-// ---------------------------------------------------------------------------
-
-func TestDefaultSamplingRate_Production(t *testing.T) {
-	if got := defaultSamplingRate("production"); got != 0.1 {
-		t.Errorf("expected 0.1, got %f", got)
-	}
-}
-
-func TestDefaultSamplingRate_Staging(t *testing.T) {
-	if got := defaultSamplingRate("staging"); got != 0.5 {
-		t.Errorf("expected 0.5, got %f", got)
-	}
-}
-
-func TestDefaultSamplingRate_Development(t *testing.T) {
-	if got := defaultSamplingRate("development"); got != 1.0 {
-		t.Errorf("expected 1.0, got %f", got)
-	}
-}
-
-func TestDefaultSamplingRate_UnknownEnvironment(t *testing.T) {
-	if got := defaultSamplingRate("custom-env"); got != 1.0 {
-		t.Errorf("expected 1.0 for unknown env, got %f", got)
-	}
-}
-
 // ---------------------------------------------------------------------------
 // Config.ResolvedAuthHeaders (from config/types.go)
 // ---------------------------------------------------------------------------
@@ -616,6 +252,49 @@ func TestLoadConfigFromEnv_OTLPHeaders(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// LoadConfigFromEnv - Region endpoint registry
+// ---------------------------------------------------------------------------
+
+func TestLoadConfigFromEnv_EndpointRegistry(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("ENV", "production")
+	t.Setenv("OTEL_REGION", "eu-west-1")
+	t.Setenv("OTEL_ENDPOINT_REGISTRY", "eu-west-1=collector.eu:4317,production/eu-west-1=http://prod-eu:4317,us-east-1=collector.us:4317")
+
+	cfg := LoadConfigFromEnv()
+	if cfg.Region != "eu-west-1" {
+		t.Errorf("expected region eu-west-1, got %q", cfg.Region)
+	}
+	if cfg.Endpoint != "prod-eu:4317" {
+		t.Errorf("expected environment/region entry to win, got %q", cfg.Endpoint)
+	}
+}
+
+func TestLoadConfigFromEnv_EndpointRegistryExplicitEndpointWins(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "explicit:4317")
+	t.Setenv("OTEL_REGION", "eu-west-1")
+	t.Setenv("OTEL_ENDPOINT_REGISTRY", "eu-west-1=collector.eu:4317")
+
+	cfg := LoadConfigFromEnv()
+	if cfg.Endpoint != "explicit:4317" {
+		t.Errorf("expected explicit endpoint, got %q", cfg.Endpoint)
+	}
+}
+
+func TestConfig_RegistryEndpoint_FallsBackToEnvironment(t *testing.T) {
+	cfg := &Config{
+		Environment:      "staging",
+		Region:           "ap-south-1",
+		EndpointRegistry: map[string]string{"staging": "staging-collector:4317"},
+	}
+
+	endpoint, ok := cfg.RegistryEndpoint()
+	if !ok || endpoint != "staging-collector:4317" {
+		t.Errorf("RegistryEndpoint() = %q, %v; want staging-collector:4317, true", endpoint, ok)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // LoadConfigFromEnv - Route exclusion defaults
 // ---------------------------------------------------------------------------
@@ -653,28 +332,20 @@ func TestLoadConfigFromEnv_RouteExclusionDefaults(t *testing.T) {
 	}
 }
 
-// ---------------------------------------------------------------------------
-// getInt64Env (additional coverage)
-// ---------------------------------------------------------------------------
+func TestLoadConfigFromEnv_MetricRouteExclusion(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXCLUDED_KEEP_METRICS", "true")
+	t.Setenv("OTEL_METRICS_EXCLUDED_PATHS", "/debug/vars")
+	t.Setenv("OTEL_METRICS_EXCLUDED_PREFIXES", "/internal/")
 
-func TestGetInt64Env_Default(t *testing.T) {
-	t.Setenv("I64_KEY", "")
-	if got := getInt64Env("I64_KEY", 1024); got != 1024 {
-		t.Errorf("expected 1024, got %d", got)
+	cfg := LoadConfigFromEnv()
+	if !cfg.RouteExclusion.KeepMetrics {
+		t.Error("expected RouteExclusion.KeepMetrics to be true")
 	}
-}
-
-func TestGetInt64Env_Valid(t *testing.T) {
-	t.Setenv("I64_KEY", "999999999")
-	if got := getInt64Env("I64_KEY", 0); got != 999999999 {
-		t.Errorf("expected 999999999, got %d", got)
+	if len(cfg.MetricRouteExclusion.ExactPaths) != 1 || cfg.MetricRouteExclusion.ExactPaths[0] != "/debug/vars" {
+		t.Errorf("unexpected metric exact paths: %v", cfg.MetricRouteExclusion.ExactPaths)
 	}
-}
-
-func TestGetInt64Env_InvalidFallsBackToDefault(t *testing.T) {
-	t.Setenv("I64_KEY", "xyz")
-	if got := getInt64Env("I64_KEY", 512); got != 512 {
-		t.Errorf("expected default 512, got %d", got)
+	if len(cfg.MetricRouteExclusion.PrefixPaths) != 1 || cfg.MetricRouteExclusion.PrefixPaths[0] != "/internal/" {
+		t.Errorf("unexpected metric prefix paths: %v", cfg.MetricRouteExclusion.PrefixPaths)
 	}
 }
 
@@ -687,6 +358,8 @@ func TestLoadConfigFromEnv_ScrubDefaults(t *testing.T) {
 	t.Setenv("OTEL_PII_SENSITIVE_KEYS", "")
 	t.Setenv("OTEL_PII_REDACTED_VALUE", "")
 	t.Setenv("OTEL_PII_DB_STATEMENT_MAX_LENGTH", "")
+	t.Setenv("OTEL_PII_SQL_SANITIZE", "")
+	t.Setenv("OTEL_PII_SQL_DIALECT", "")
 
 	cfg := LoadConfigFromEnv()
 	if cfg.Scrub.Enabled {
@@ -698,6 +371,9 @@ func TestLoadConfigFromEnv_ScrubDefaults(t *testing.T) {
 	if cfg.Scrub.DBStatementMaxLength != 2048 {
 		t.Errorf("expected DBStatementMaxLength 2048, got %d", cfg.Scrub.DBStatementMaxLength)
 	}
+	if cfg.Scrub.SQLSanitize || cfg.Scrub.SQLDialect != "generic" {
+		t.Errorf("expected SQL sanitization off with the generic dialect, got %v/%q", cfg.Scrub.SQLSanitize, cfg.Scrub.SQLDialect)
+	}
 	// Verify updated default sensitive keys
 	expectedKeys := []string{"password", "token", "secret", "key", "email"}
 	if len(cfg.Scrub.SensitiveKeys) != len(expectedKeys) {
@@ -756,6 +432,9 @@ func TestLoadConfigFromEnv_HTTPDefaults(t *testing.T) {
 	if len(cfg.HTTP.SensitiveHeaders) != 5 {
 		t.Errorf("expected 5 default sensitive headers, got %d: %v", len(cfg.HTTP.SensitiveHeaders), cfg.HTTP.SensitiveHeaders)
 	}
+	if !cfg.HTTP.CaptureQueueTime {
+		t.Error("expected CaptureQueueTime=true by default")
+	}
 	if len(cfg.HTTP.BodyAllowedContentTypes) != 3 {
 		t.Errorf("expected 3 default content types, got %d: %v", len(cfg.HTTP.BodyAllowedContentTypes), cfg.HTTP.BodyAllowedContentTypes)
 	}
@@ -783,24 +462,6 @@ func TestLoadConfigFromEnv_HTTPCustomEnvVars(t *testing.T) {
 	}
 }
 
-// ---------------------------------------------------------------------------
-// loadAuthConfig - SIGNOZ_ACCESS_TOKEN env var handling
-// ---------------------------------------------------------------------------
-
-func TestLoadAuthConfig_NoTokens(t *testing.T) {
-	t.Setenv("SIGNOZ_ACCESS_TOKEN", "")
-	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "")
-
-	ac := loadAuthConfig()
-	// Should have empty but non-nil maps.
-	if ac.Headers == nil {
-		t.Fatal("expected non-nil Headers map")
-	}
-	if len(ac.Headers) != 0 {
-		t.Errorf("expected empty headers, got %v", ac.Headers)
-	}
-}
-
 // ---------------------------------------------------------------------------
 // Ensure VERSION env var fallback works for Version field
 // ---------------------------------------------------------------------------
@@ -857,3 +518,211 @@ func TestLoadConfigFromEnv_CustomResourceAttributes(t *testing.T) {
 		t.Errorf("expected tier=critical, got %v", cfg.Resource.CustomAttributes)
 	}
 }
+
+func TestLoadConfigFromEnv_PerSignalResourceAttributes(t *testing.T) {
+	t.Setenv("OTEL_LOGS_RESOURCE_ATTRIBUTES", "log.source=app")
+	t.Setenv("OTEL_TRACES_RESOURCE_ATTRIBUTES", "k8s.pod.start_time=2026-10-16T00:00:00Z")
+
+	cfg := LoadConfigFromEnv()
+	attrs := cfg.Resource.PerSignalAttributes
+	if attrs.Logs["log.source"] != "app" || len(attrs.Metrics) != 0 {
+		t.Errorf("PerSignalAttributes = %+v, want log.source on logs only", attrs)
+	}
+	if attrs.Traces["k8s.pod.start_time"] == "" {
+		t.Errorf("traces attributes = %v, want k8s.pod.start_time", attrs.Traces)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// service.instance.id strategy
+// ---------------------------------------------------------------------------
+
+func TestLoadConfigFromEnv_InstanceIDStrategy(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_INSTANCE", "")
+	t.Setenv("OTEL_SERVICE_INSTANCE_ID_STRATEGY", "")
+
+	cfg := LoadConfigFromEnv()
+	if cfg.Resource.InstanceIDStrategy != "hostname" {
+		t.Errorf("expected default InstanceIDStrategy 'hostname', got %q", cfg.Resource.InstanceIDStrategy)
+	}
+	if cfg.Resource.ServiceInstance != "" {
+		t.Errorf("expected empty ServiceInstance when unset, got %q", cfg.Resource.ServiceInstance)
+	}
+
+	t.Setenv("OTEL_SERVICE_INSTANCE_ID_STRATEGY", "file")
+	t.Setenv("OTEL_SERVICE_INSTANCE_ID_FILE", "/var/lib/app/instance-id")
+	t.Setenv("POD_UID", "pod-uid-123")
+
+	cfg = LoadConfigFromEnv()
+	if cfg.Resource.InstanceIDStrategy != "file" {
+		t.Errorf("expected InstanceIDStrategy 'file', got %q", cfg.Resource.InstanceIDStrategy)
+	}
+	if cfg.Resource.InstanceIDFile != "/var/lib/app/instance-id" {
+		t.Errorf("unexpected InstanceIDFile: %q", cfg.Resource.InstanceIDFile)
+	}
+	if cfg.Resource.K8sPodUID != "pod-uid-123" {
+		t.Errorf("expected K8sPodUID 'pod-uid-123', got %q", cfg.Resource.K8sPodUID)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Config files
+// ---------------------------------------------------------------------------
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigFromFile_EnvOverridesFile(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_NAME", "from-env")
+	t.Setenv("OTEL_EXPORTER_OTLP_TIMEOUT", "")
+	t.Setenv("OTEL_TRACES_SAMPLER_ARG", "")
+
+	path := writeConfigFile(t, "otel.yaml", `
+service_name: from-file
+timeout: 3s
+traces:
+  sampling:
+    rate: 0.25
+`)
+
+	cfg, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile: %v", err)
+	}
+	if cfg.ServiceName != "from-env" {
+		t.Errorf("expected env to win for ServiceName, got %q", cfg.ServiceName)
+	}
+	if cfg.Timeout != 3*time.Second {
+		t.Errorf("expected Timeout 3s from file, got %v", cfg.Timeout)
+	}
+	if cfg.Traces.Sampling.Rate != 0.25 {
+		t.Errorf("expected sampling rate 0.25 from file, got %v", cfg.Traces.Sampling.Rate)
+	}
+	// Untouched settings keep their defaults.
+	if cfg.Traces.BatchSize != 512 {
+		t.Errorf("expected default BatchSize 512, got %d", cfg.Traces.BatchSize)
+	}
+}
+
+func TestLoadConfigFromFile_EnvironmentRefreshesDerivedDefaults(t *testing.T) {
+	for _, key := range []string{"ENV", "DEPLOYMENT_ENVIRONMENT", "OTEL_TRACES_SAMPLER_ARG", "OTEL_DEBUG_MODE"} {
+		t.Setenv(key, "")
+	}
+
+	path := writeConfigFile(t, "otel.json", `{"environment": "production"}`)
+
+	cfg, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile: %v", err)
+	}
+	if cfg.Resource.DeploymentEnvironment != "production" {
+		t.Errorf("expected deployment environment 'production', got %q", cfg.Resource.DeploymentEnvironment)
+	}
+	if cfg.Traces.Sampling.Rate != 0.1 {
+		t.Errorf("expected production sampling default 0.1, got %v", cfg.Traces.Sampling.Rate)
+	}
+	if cfg.Features.DebugMode {
+		t.Error("expected debug mode off in production")
+	}
+}
+
+func TestLoadConfigFromFile_RateLimitedSamplerTypeRefreshesDefaultArg(t *testing.T) {
+	for _, key := range []string{"OTEL_TRACES_SAMPLER", "OTEL_TRACES_SAMPLER_ARG"} {
+		t.Setenv(key, "")
+	}
+
+	path := writeConfigFile(t, "otel.yaml", "traces:\n  sampling:\n    type: rate_limited\n")
+
+	cfg, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile: %v", err)
+	}
+	if cfg.Traces.Sampling.Rate != 100 {
+		t.Errorf("expected rate_limited default of 100 spans/s, got %v", cfg.Traces.Sampling.Rate)
+	}
+}
+
+func TestLoadConfigFromFile_ResolvesRegistryEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_ENDPOINT_REGISTRY", "")
+	for _, key := range []string{"OTEL_REGION", "CLOUD_REGION", "AWS_REGION"} {
+		t.Setenv(key, "")
+	}
+
+	path := writeConfigFile(t, "otel.yml", `
+region: eu-west-1
+endpoint_registry:
+  eu-west-1: http://collector.eu:4317
+`)
+
+	cfg, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile: %v", err)
+	}
+	if cfg.Endpoint != "collector.eu:4317" {
+		t.Errorf("expected registry endpoint, got %q", cfg.Endpoint)
+	}
+}
+
+func TestLoadConfigFromFile_Errors(t *testing.T) {
+	if _, err := LoadConfigFromFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected error for missing file")
+	}
+	if _, err := LoadConfigFromFile(writeConfigFile(t, "otel.yaml", "sampling_rate: 1\n")); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
+
+func TestLoadConfigFromEnv_DefaultsValidate(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_NAME", "svc")
+
+	if err := LoadConfigFromEnv().Validate(); err != nil {
+		t.Errorf("defaults should validate, got: %v", err)
+	}
+}
+
+func TestLoadConfigFromEnv_SignalExporterOverrides(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "collector:4317")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", "https://mimir.example.com/otlp/v1/metrics")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_PROTOCOL", "http")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_HEADERS", "X-Scope-OrgID=team-a")
+
+	cfg := LoadConfigFromEnv()
+
+	if got := cfg.SignalExporter("metrics"); got.Endpoint != "https://mimir.example.com/otlp/v1/metrics" ||
+		got.Protocol != "http" || got.Headers["X-Scope-OrgID"] != "team-a" {
+		t.Errorf("metrics exporter = %+v", got)
+	}
+	if got := cfg.SignalExporter("traces"); got.Endpoint != "collector:4317" || got.Protocol != "grpc" {
+		t.Errorf("traces exporter = %+v, want the global endpoint", got)
+	}
+}
+
+func TestLoadConfigFromFile_SignalExporterEnvOverridesFile(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_LOGS_ENDPOINT", "logs-from-env:4317")
+
+	path := writeConfigFile(t, "otel.yaml", `
+logs:
+  exporter:
+    endpoint: logs-from-file:4317
+metrics:
+  exporter:
+    endpoint: metrics-from-file:4317
+`)
+	cfg, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile: %v", err)
+	}
+	if cfg.Logs.Exporter.Endpoint != "logs-from-env:4317" {
+		t.Errorf("logs endpoint = %q, want env to win", cfg.Logs.Exporter.Endpoint)
+	}
+	if cfg.Metrics.Exporter.Endpoint != "metrics-from-file:4317" {
+		t.Errorf("metrics endpoint = %q, want the file value", cfg.Metrics.Exporter.Endpoint)
+	}
+}
@@ -0,0 +1,123 @@
+package otelagent
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/provider"
+)
+
+// DebugHandler returns read-only debug endpoints for agent, mountable on any
+// mux, so services without Gin get the probes ginmiddleware provides:
+//
+//	GET /health        HealthCheck, 503 when unhealthy (Features.HealthChecks)
+//	GET /ready         ReadinessCheck (Features.ReadinessProbes)
+//	GET /live          LivenessCheck (Features.LivenessProbes)
+//	GET /diagnostics   Diagnostics
+//	GET /config        the configuration, with secrets redacted (Config.Redacted)
+//	GET /exporters     exporter health per signal (Features.HealthChecks)
+//	GET /recent-spans  RecentSpans, in debug mode (see RecentSpansHandler)
+//
+// Endpoints whose feature is off answer 404. No token is required, but the
+// configuration and spans describe the service: mount it on an internal
+// listener, not the public router:
+//
+//	mux.Handle("/otel/", http.StripPrefix("/otel", otelagent.DebugHandler(agent)))
+func DebugHandler(agent *Agent) http.Handler {
+	features := agent.config.Features
+	mux := http.NewServeMux()
+	if features.HealthChecks {
+		mux.HandleFunc("GET /health", agent.healthProbe)
+		mux.HandleFunc("GET /exporters", func(w http.ResponseWriter, r *http.Request) {
+			writeAdminJSON(w, http.StatusOK, agent.exporterReports())
+		})
+	}
+	if features.ReadinessProbes {
+		mux.HandleFunc("GET /ready", adminProbe("ready", agent.ReadinessCheck))
+	}
+	if features.LivenessProbes {
+		mux.HandleFunc("GET /live", adminProbe("live", agent.LivenessCheck))
+	}
+	mux.HandleFunc("GET /diagnostics", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJSON(w, http.StatusOK, agent.Diagnostics())
+	})
+	mux.HandleFunc("GET /config", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJSON(w, http.StatusOK, agent.config.Redacted())
+	})
+	mux.Handle("GET /recent-spans", agent.RecentSpansHandler())
+	return mux
+}
+
+// ExporterReport is the exporter health of one signal, as served by
+// DebugHandler.
+type ExporterReport struct {
+	Status              string     `json:"status"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastSuccess         *time.Time `json:"last_success,omitempty"`
+	LastFailure         *time.Time `json:"last_failure,omitempty"`
+}
+
+// exporterReports returns the exporter health of each enabled signal.
+func (a *Agent) exporterReports() map[string]ExporterReport {
+	reports := make(map[string]ExporterReport)
+	for signal, enabled := range map[string]bool{
+		provider.SignalTraces:  a.tracerProvider != nil,
+		provider.SignalMetrics: a.meterProvider != nil,
+		provider.SignalLogs:    a.loggerProvider != nil,
+	} {
+		if !enabled {
+			continue
+		}
+		h := a.health.Signal(signal)
+		report := ExporterReport{
+			Status:              h.Status.String(),
+			ConsecutiveFailures: h.ConsecutiveFailures,
+		}
+		if !h.LastSuccess.IsZero() {
+			report.LastSuccess = &h.LastSuccess
+		}
+		if !h.LastFailure.IsZero() {
+			report.LastFailure = &h.LastFailure
+		}
+		reports[signal] = report
+	}
+	return reports
+}
+
+// RecentSpans returns the last Traces.RecentSpans (OTEL_DEBUG_RECENT_SPANS)
+// finished spans, most recently finished first, scrubbed like exported
+// spans. It returns nil unless Features.DebugMode was on when Init ran.
+func (a *Agent) RecentSpans() []provider.RecentSpan {
+	if a.recentSpans == nil {
+		return nil
+	}
+	return a.recentSpans.Spans()
+}
+
+// RecentSpansHandler serves RecentSpans as a JSON array, to check
+// instrumentation locally without a backend. The trace_id query parameter
+// keeps only the spans of one trace. Without debug mode it answers 404.
+//
+//	mux.Handle("GET /debug/otel/spans", agent.RecentSpansHandler())
+//
+// Span attributes can carry request data: mount it on an internal listener
+// only.
+func (a *Agent) RecentSpansHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.recentSpans == nil {
+			writeAdminJSON(w, http.StatusNotFound, map[string]string{"error": "recent spans are only kept in debug mode"})
+			return
+		}
+		spans := a.recentSpans.Spans()
+		if traceID := r.URL.Query().Get("trace_id"); traceID != "" {
+			matching := spans[:0]
+			for _, s := range spans {
+				if s.TraceID == traceID {
+					matching = append(matching, s)
+				}
+			}
+			spans = matching
+		}
+		writeAdminJSON(w, http.StatusOK, spans)
+	})
+}
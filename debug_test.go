@@ -0,0 +1,147 @@
+package otelagent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/provider"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestRecentSpansHandler(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+	agent := NewAgent(
+		WithServiceName("recent-spans-test"),
+		WithStdoutExporter(),
+		WithDebugMode(true),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+	)
+	agent.Config().Scrub = ScrubConfig{Enabled: true, SensitiveKeys: []string{"password"}}
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	var traceIDs []string
+	for range 2 {
+		_, span := agent.GetTracer("test").Start(context.Background(), "login")
+		span.SetAttributes(attribute.String("password", "hunter2"))
+		span.End()
+		traceIDs = append(traceIDs, span.SpanContext().TraceID().String())
+	}
+
+	rec := httptest.NewRecorder()
+	agent.RecentSpansHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/otel/spans?trace_id="+traceIDs[0], nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var spans []provider.RecentSpan
+	if err := json.Unmarshal(rec.Body.Bytes(), &spans); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(spans) != 1 || spans[0].TraceID != traceIDs[0] {
+		t.Fatalf("spans = %+v, want the span of trace %s", spans, traceIDs[0])
+	}
+	if got := spans[0].Attributes["password"]; got != "[REDACTED]" {
+		t.Errorf("password = %v, want [REDACTED]", got)
+	}
+	if got := len(agent.RecentSpans()); got != 2 {
+		t.Errorf("RecentSpans() = %d spans, want 2", got)
+	}
+}
+
+func TestRecentSpansHandler_NotFoundWithoutDebugMode(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+	agent := NewAgent(
+		WithServiceName("recent-spans-off"),
+		WithStdoutExporter(),
+		WithDebugMode(false),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+	)
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	if spans := agent.RecentSpans(); spans != nil {
+		t.Errorf("RecentSpans() = %v, want nil", spans)
+	}
+	rec := httptest.NewRecorder()
+	agent.RecentSpansHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/otel/spans", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestDebugHandler(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+	agent := NewAgent(
+		WithServiceName("debug-handler-test"),
+		WithStdoutExporter(),
+		WithDebugMode(false),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+	)
+	agent.Config().Auth.Headers = map[string]string{"signoz-access-token": "s3cret"}
+	agent.Config().Blocklist.Values = []string{"user-42"}
+	agent.Config().Features.LivenessProbes = false
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	_, span := agent.GetTracer("test").Start(context.Background(), "op")
+	span.End()
+	if err := agent.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	h := DebugHandler(agent)
+	get := func(path string, body any) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if body != nil && rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), body); err != nil {
+				t.Fatalf("GET %s: decode: %v", path, err)
+			}
+		}
+		return rec.Code
+	}
+
+	for path, want := range map[string]int{
+		"/health":       http.StatusOK,
+		"/ready":        http.StatusOK,
+		"/live":         http.StatusNotFound,
+		"/diagnostics":  http.StatusOK,
+		"/recent-spans": http.StatusNotFound,
+	} {
+		if got := get(path, nil); got != want {
+			t.Errorf("GET %s = %d, want %d", path, got, want)
+		}
+	}
+
+	var cfg Config
+	if code := get("/config", &cfg); code != http.StatusOK {
+		t.Fatalf("GET /config = %d", code)
+	}
+	if got := cfg.Auth.Headers["signoz-access-token"]; got != "[REDACTED]" {
+		t.Errorf("auth header = %q, want [REDACTED]", got)
+	}
+	if len(cfg.Blocklist.Values) != 1 || cfg.Blocklist.Values[0] != "[REDACTED]" {
+		t.Errorf("blocklist values = %v, want [REDACTED]", cfg.Blocklist.Values)
+	}
+	if agent.Config().Auth.Headers["signoz-access-token"] != "s3cret" {
+		t.Error("GET /config redacted the agent's own configuration")
+	}
+
+	var exporters map[string]ExporterReport
+	if code := get("/exporters", &exporters); code != http.StatusOK {
+		t.Fatalf("GET /exporters = %d", code)
+	}
+	if traces := exporters["traces"]; traces.Status != "healthy" || traces.LastSuccess == nil || len(exporters) != 1 {
+		t.Errorf("exporters = %+v, want only traces, healthy after a successful export", exporters)
+	}
+}
@@ -0,0 +1,141 @@
+package otelagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/RodolfoBonis/go-otel-agent/provider"
+)
+
+// DrainReport describes a drain of the telemetry pipelines by Shutdown or
+// Drain.
+type DrainReport struct {
+	// Signals are in drain order: traces, logs, then metrics.
+	Signals []SignalDrain `json:"signals"`
+	// Duration is how long the drain took.
+	Duration time.Duration `json:"duration"`
+	// Shutdown reports whether the providers were shut down rather than
+	// only flushed.
+	Shutdown bool `json:"shutdown"`
+}
+
+// SignalDrain is the part of a DrainReport about one signal.
+type SignalDrain struct {
+	Signal string `json:"signal"`
+	// Exported counts the items exported during the drain.
+	Exported int64 `json:"exported"`
+	// Dropped counts the items lost since Init: spans and log records
+	// dropped by a full queue, a failed export or the drain deadline, and
+	// metric data points of failed exports.
+	Dropped int64  `json:"dropped"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Dropped returns the items dropped across all signals.
+func (r DrainReport) Dropped() int64 {
+	var n int64
+	for _, s := range r.Signals {
+		n += s.Dropped
+	}
+	return n
+}
+
+// Drain flushes traces, then logs, then metrics within
+// Performance.ShutdownTimeout and keeps the providers running, like Shutdown
+// does with WithFlushOnlyShutdown. Call it before a serverless runtime
+// freezes the process; the report says what was exported and dropped.
+func (a *Agent) Drain(ctx context.Context) (DrainReport, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if !a.initialized {
+		return DrainReport{}, nil
+	}
+
+	drainCtx, cancel := a.shutdownContext(ctx)
+	defer cancel()
+
+	report, err := a.drain(drainCtx, false)
+	a.logDrain(ctx, report, err)
+	return report, err
+}
+
+// shutdownContext applies Performance.ShutdownTimeout to ctx.
+func (a *Agent) shutdownContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d := a.config.Performance.ShutdownTimeout; d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return context.WithCancel(ctx)
+}
+
+// drain flushes, or shuts down, the providers one signal at a time. Traces go
+// first and metrics last: exporting spans and log records records metrics of
+// its own (self-telemetry, budget, export stats), which the final metric
+// export then includes.
+func (a *Agent) drain(ctx context.Context, shutdown bool) (DrainReport, error) {
+	type pipeline struct {
+		signal      string
+		flush, stop func(context.Context) error
+	}
+	var pipelines []pipeline
+	if a.tracerProvider != nil {
+		pipelines = append(pipelines, pipeline{provider.SignalTraces, a.tracerProvider.ForceFlush, a.tracerProvider.Shutdown})
+	}
+	if a.loggerProvider != nil {
+		pipelines = append(pipelines, pipeline{provider.SignalLogs, a.loggerProvider.ForceFlush, a.loggerProvider.Shutdown})
+	}
+	if a.meterProvider != nil {
+		pipelines = append(pipelines, pipeline{provider.SignalMetrics, a.meterProvider.ForceFlush, a.meterProvider.Shutdown})
+	}
+
+	start := time.Now()
+	report := DrainReport{Shutdown: shutdown}
+	var errs []error
+	for _, p := range pipelines {
+		step := p.flush
+		if shutdown {
+			step = p.stop
+		}
+
+		before, _ := a.exports.Signal(p.signal)
+		err := step(ctx)
+		after, _ := a.exports.Signal(p.signal)
+
+		s := SignalDrain{
+			Signal:   p.signal,
+			Exported: after.TotalExported - before.TotalExported,
+			Dropped:  a.exports.Dropped(p.signal),
+		}
+		if err != nil {
+			s.Error = err.Error()
+			errs = append(errs, fmt.Errorf("%s: %w", p.signal, err))
+		}
+		report.Signals = append(report.Signals, s)
+	}
+	report.Duration = time.Since(start)
+	return report, errors.Join(errs...)
+}
+
+func (a *Agent) logDrain(ctx context.Context, report DrainReport, err error) {
+	fields := logger.Fields{
+		"duration_ms": report.Duration.Milliseconds(),
+		"shutdown":    report.Shutdown,
+	}
+	for _, s := range report.Signals {
+		fields[s.Signal+"_exported"] = s.Exported
+		fields[s.Signal+"_dropped"] = s.Dropped
+	}
+
+	switch {
+	case err != nil:
+		fields["error"] = err.Error()
+		a.logger.Error(ctx, "Telemetry drain incomplete", fields)
+	case report.Dropped() > 0:
+		a.logger.Warning(ctx, "Telemetry drained, some was dropped", fields)
+	default:
+		a.logger.Info(ctx, "Telemetry drained", fields)
+	}
+}
@@ -1,6 +1,10 @@
 package otelagent
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/RodolfoBonis/go-otel-agent/internal/workerpool"
+)
 
 var (
 	ErrNotInitialized     = errors.New("go-otel-agent: agent not initialized, call Init() first")
@@ -8,4 +12,6 @@ var (
 	ErrInvalidConfig      = errors.New("go-otel-agent: invalid configuration")
 	ErrShutdownTimeout    = errors.New("go-otel-agent: shutdown timed out")
 	ErrMissingServiceName = errors.New("go-otel-agent: service name is required")
+	ErrQueueFull          = workerpool.ErrQueueFull
+	ErrWorkerPoolClosed   = workerpool.ErrClosed
 )
@@ -0,0 +1,52 @@
+package fxmodule
+
+import (
+	"net/http"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/integration/httpclient"
+	"go.uber.org/fx"
+)
+
+// HTTPClientModule provides an instrumented *http.Client and
+// http.RoundTripper for outbound requests; add it next to Module. See
+// HTTPClient to pass httpclient options.
+var HTTPClientModule = HTTPClient()
+
+// HTTPClient provides an http.RoundTripper wrapping http.DefaultTransport
+// with CLIENT spans, trace context propagation and request metrics, and an
+// *http.Client using it. Both are plain pass-throughs when the agent is
+// disabled or Features.AutoHTTP is off.
+func HTTPClient(opts ...httpclient.Option) fx.Option {
+	return fx.Module("go-otel-agent-httpclient",
+		fx.Provide(
+			func(agent *otelagent.Agent) http.RoundTripper {
+				return httpclient.NewTransport(agent, nil, opts...)
+			},
+			func(rt http.RoundTripper) *http.Client {
+				return &http.Client{Transport: rt}
+			},
+		),
+	)
+}
+
+// DecorateHTTPClient instruments the *http.Client the application already
+// provides. The decorated client is a copy whose transport is wrapped; the
+// provided one is left as is.
+func DecorateHTTPClient(opts ...httpclient.Option) fx.Option {
+	return fx.Decorate(func(agent *otelagent.Agent, client *http.Client) *http.Client {
+		if client == nil {
+			return httpclient.WrapClient(agent, nil, opts...)
+		}
+		decorated := *client
+		return httpclient.WrapClient(agent, &decorated, opts...)
+	})
+}
+
+// DecorateRoundTripper instruments the http.RoundTripper the application
+// already provides.
+func DecorateRoundTripper(opts ...httpclient.Option) fx.Option {
+	return fx.Decorate(func(agent *otelagent.Agent, rt http.RoundTripper) http.RoundTripper {
+		return httpclient.NewTransport(agent, rt, opts...)
+	})
+}
@@ -0,0 +1,84 @@
+package fxmodule
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/integration/httpclient"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func testAgentOptions(t *testing.T) fx.Option {
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+	return TracingOnlyModule(
+		otelagent.WithServiceName("fx-httpclient-test"),
+		otelagent.WithStdoutExporter(),
+	)
+}
+
+func TestHTTPClientModule_PropagatesTraceContext(t *testing.T) {
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer srv.Close()
+
+	var client *http.Client
+	var rt http.RoundTripper
+	app := fxtest.New(t, testAgentOptions(t), HTTPClientModule, fx.Populate(&client, &rt))
+	app.RequireStart()
+	defer app.RequireStop()
+
+	if _, ok := rt.(*httpclient.Transport); !ok || client.Transport != rt {
+		t.Fatalf("client transport = %T, round tripper = %T, want the shared *httpclient.Transport", client.Transport, rt)
+	}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if traceparent == "" {
+		t.Error("request sent without a traceparent header")
+	}
+}
+
+func TestDecorateHTTPClient_WrapsACopyOfTheProvidedClient(t *testing.T) {
+	base := &http.Client{Transport: http.DefaultTransport}
+
+	var client *http.Client
+	app := fxtest.New(t,
+		testAgentOptions(t),
+		fx.Supply(base),
+		DecorateHTTPClient(),
+		fx.Populate(&client),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	if _, ok := client.Transport.(*httpclient.Transport); !ok {
+		t.Errorf("decorated transport = %T, want *httpclient.Transport", client.Transport)
+	}
+	if base.Transport != http.DefaultTransport {
+		t.Errorf("provided client transport changed to %T", base.Transport)
+	}
+}
+
+func TestDecorateRoundTripper_WrapsTheProvidedTransport(t *testing.T) {
+	var rt http.RoundTripper
+	app := fxtest.New(t,
+		testAgentOptions(t),
+		fx.Provide(func() http.RoundTripper { return http.DefaultTransport }),
+		DecorateRoundTripper(),
+		fx.Populate(&rt),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	if _, ok := rt.(*httpclient.Transport); !ok {
+		t.Errorf("decorated round tripper = %T, want *httpclient.Transport", rt)
+	}
+}
@@ -2,20 +2,39 @@ package otelagent
 
 import (
 	"fmt"
+	"maps"
+	"time"
 
 	"github.com/RodolfoBonis/go-otel-agent/provider"
 )
 
 // HealthStatus represents the overall health of the agent.
 type HealthStatus struct {
-	Status   string                              `json:"status"` // "ok", "degraded", "unhealthy"
-	Signals  map[string]provider.ExporterStatus   `json:"signals,omitempty"`
-	Running  bool                                 `json:"running"`
-	Enabled  bool                                 `json:"enabled"`
+	Status  string                             `json:"status"` // "ok", "degraded", "unhealthy"
+	Signals map[string]provider.ExporterStatus `json:"signals,omitempty"`
+	Running bool                               `json:"running"`
+	Enabled bool                               `json:"enabled"`
+	// Budget is set with Features.ResourceBudget. While the agent is
+	// degraded by it, Status is at least "degraded".
+	Budget *provider.BudgetStatus `json:"budget,omitempty"`
 }
 
-// HealthCheck returns the current health status of the agent.
+// HealthCheck returns the current health status of the agent. With
+// Features.HealthChecks off, exporter outcomes are not tracked and the
+// status is "ok", without signals, unless the resource budget degrades it.
 func (a *Agent) HealthCheck() HealthStatus {
+	status := a.exporterHealthCheck()
+	if a.budget != nil {
+		budget := a.budget.Status()
+		status.Budget = &budget
+		if budget.State == provider.BudgetDegraded && status.Status == "ok" {
+			status.Status = "degraded"
+		}
+	}
+	return status
+}
+
+func (a *Agent) exporterHealthCheck() HealthStatus {
 	if !a.config.Enabled {
 		return HealthStatus{
 			Status:  "ok",
@@ -23,6 +42,13 @@ func (a *Agent) HealthCheck() HealthStatus {
 			Enabled: false,
 		}
 	}
+	if !a.config.Features.HealthChecks {
+		return HealthStatus{
+			Status:  "ok",
+			Running: a.IsRunning(),
+			Enabled: a.config.Enabled,
+		}
+	}
 
 	overall := a.health.OverallStatus()
 	var status string
@@ -45,13 +71,41 @@ func (a *Agent) HealthCheck() HealthStatus {
 	}
 }
 
-// ReadinessCheck returns true when the agent is initialized and running.
+// ReadinessCheck returns true when the agent is initialized and running and
+// no exporter has become unhealthy, i.e. the collector is still reachable.
+// With Features.HealthChecks off, exporter health is not considered.
 func (a *Agent) ReadinessCheck() bool {
+	a.mu.RLock()
+	ready := a.initialized && a.running
+	a.mu.RUnlock()
+	return ready && a.health.OverallStatus() != provider.ExporterUnhealthy
+}
+
+// LivenessCheck returns true while the agent is initialized and has not been
+// shut down. Unlike ReadinessCheck it ignores exporter health: an unreachable
+// collector is no reason to restart the process.
+func (a *Agent) LivenessCheck() bool {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 	return a.initialized && a.running
 }
 
+// HealthProbes reports what the Features health flags turned on, so a probe
+// that is missing or always "ok" can be traced back to its flag.
+type HealthProbes struct {
+	// ExporterHealth is Features.HealthChecks (OTEL_HEALTH_CHECKS): export
+	// outcomes are tracked, HealthCheck reports per-signal status and
+	// readiness fails while the collector is unreachable. It also serves
+	// GET /health from RegisterHealthRoutes and AdminHandler.
+	ExporterHealth bool `json:"exporter_health"`
+	// Readiness is Features.ReadinessProbes (OTEL_READINESS_PROBES): GET
+	// /ready is served.
+	Readiness bool `json:"readiness"`
+	// Liveness is Features.LivenessProbes (OTEL_LIVENESS_PROBES): GET /live
+	// is served.
+	Liveness bool `json:"liveness"`
+}
+
 // DiagnosticsInfo surfaces runtime configuration for debugging telemetry issues.
 type DiagnosticsInfo struct {
 	Enabled      bool    `json:"enabled"`
@@ -65,6 +119,50 @@ type DiagnosticsInfo struct {
 	TracerType   string  `json:"tracer_type"`
 	LoggerType   string  `json:"logger_type"`
 	Features     any     `json:"features"`
+
+	// SamplerType is the configured root sampler. The rate_limited sampler
+	// has no ratio: SamplingRate and EffectiveSamplingRate are then 0 and its
+	// budget of sampled root spans per second is SpansPerSecond.
+	SamplerType    string  `json:"sampler_type"`
+	SpansPerSecond float64 `json:"spans_per_second,omitempty"`
+
+	// EffectiveSamplingRate is the root sampling probability currently in
+	// use: the adaptive sampler's rate when AdaptiveSampling is on, otherwise
+	// SamplingRate.
+	EffectiveSamplingRate float64 `json:"effective_sampling_rate"`
+	AdaptiveSampling      bool    `json:"adaptive_sampling"`
+
+	// InvalidScrubPatterns counts Scrub.SensitivePatterns that failed to
+	// compile and are not applied; ScrubPatternErrors lists why.
+	InvalidScrubPatterns int      `json:"invalid_scrub_patterns"`
+	ScrubPatternErrors   []string `json:"scrub_pattern_errors,omitempty"`
+
+	// Exports reports, per enabled signal ("traces", "metrics", "logs"),
+	// when data was last exported successfully.
+	Exports map[string]SignalExport `json:"exports,omitempty"`
+
+	// DryRun is Features.DryRun (OTEL_DRY_RUN): nothing is sent, and
+	// Exports counts what would have been exported.
+	DryRun bool `json:"dry_run"`
+
+	// HealthProbes reports which health checks and probe endpoints are on.
+	HealthProbes HealthProbes `json:"health_probes"`
+
+	// ConfigSources maps each configuration key, e.g.
+	// "traces.sampling.rate", to where its value came from: "default",
+	// "file", "env" or "option" (including WithConfig).
+	ConfigSources map[string]string `json:"config_sources"`
+}
+
+// SignalExport describes the last successful export of one signal.
+type SignalExport struct {
+	// LastExport is nil if the signal has not exported anything yet.
+	LastExport *time.Time `json:"last_export,omitempty"`
+	// SinceLastExport is the time elapsed since LastExport, rounded to the
+	// second, or "never".
+	SinceLastExport string `json:"since_last_export"`
+	LastBatchSize   int    `json:"last_batch_size"`
+	TotalExported   int64  `json:"total_exported"`
 }
 
 // Diagnostics returns runtime configuration details for debugging.
@@ -79,6 +177,42 @@ func (a *Agent) Diagnostics() DiagnosticsInfo {
 		loggerType = fmt.Sprintf("%T", a.loggerProvider)
 	}
 
+	samplingRate, spansPerSecond := a.config.Traces.Sampling.Rate, 0.0
+	if a.config.Traces.Sampling.Type == "rate_limited" {
+		samplingRate, spansPerSecond = 0, a.config.Traces.Sampling.Rate
+	}
+	effectiveRate := samplingRate
+	if a.adaptiveSampler != nil {
+		effectiveRate = a.adaptiveSampler.EffectiveRate()
+	}
+
+	var scrubErrors []string
+	for _, err := range a.scrubPatternErrors {
+		scrubErrors = append(scrubErrors, err.Error())
+	}
+
+	exports := make(map[string]SignalExport)
+	for signal, enabled := range map[string]bool{
+		provider.SignalTraces:  a.tracerProvider != nil,
+		provider.SignalMetrics: a.meterProvider != nil,
+		provider.SignalLogs:    a.loggerProvider != nil,
+	} {
+		if !enabled {
+			continue
+		}
+		st, ok := a.exports.Signal(signal)
+		if !ok {
+			exports[signal] = SignalExport{SinceLastExport: "never"}
+			continue
+		}
+		exports[signal] = SignalExport{
+			LastExport:      &st.LastExport,
+			SinceLastExport: time.Since(st.LastExport).Round(time.Second).String(),
+			LastBatchSize:   st.LastBatchSize,
+			TotalExported:   st.TotalExported,
+		}
+	}
+
 	return DiagnosticsInfo{
 		Enabled:      a.config.Enabled,
 		Running:      a.IsRunning(),
@@ -87,9 +221,29 @@ func (a *Agent) Diagnostics() DiagnosticsInfo {
 		Namespace:    a.config.Namespace,
 		Version:      a.config.Version,
 		Endpoint:     a.config.Endpoint,
-		SamplingRate: a.config.Traces.Sampling.Rate,
+		SamplingRate: samplingRate,
 		TracerType:   tracerType,
 		LoggerType:   loggerType,
 		Features:     a.config.Features,
+
+		SamplerType:    a.config.Traces.Sampling.Type,
+		SpansPerSecond: spansPerSecond,
+
+		EffectiveSamplingRate: effectiveRate,
+		AdaptiveSampling:      a.adaptiveSampler != nil,
+
+		InvalidScrubPatterns: len(a.scrubPatternErrors),
+		ScrubPatternErrors:   scrubErrors,
+
+		Exports: exports,
+		DryRun:  a.config.Features.DryRun,
+
+		HealthProbes: HealthProbes{
+			ExporterHealth: a.config.Features.HealthChecks,
+			Readiness:      a.config.Features.ReadinessProbes,
+			Liveness:       a.config.Features.LivenessProbes,
+		},
+
+		ConfigSources: maps.Clone(a.provenance),
 	}
 }
@@ -0,0 +1,71 @@
+package helper
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const asyncComponent = "async"
+
+// DetachedContext returns a context that keeps the values of ctx (span,
+// baggage, logger fields) but not its cancellation or deadline. Background
+// work started from a request handler should run in one, or it is cancelled
+// as soon as the response is written.
+func DetachedContext(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
+
+// Go runs fn in a new goroutine with a detached context and, when the global
+// provider is enabled, in a span of its own. The span starts a new trace
+// linked to the span in ctx instead of being its child, so a long job does
+// not stretch the request trace that started it. An error returned by fn
+// marks the span as failed, and a panic in fn is recovered and recorded with
+// RecordPanic instead of crashing the process:
+//
+//	helper.Go(ctx, "send-receipt", func(ctx context.Context) error {
+//		return mailer.Send(ctx, receipt)
+//	})
+func Go(ctx context.Context, name string, fn func(context.Context) error) {
+	ctx = DetachedContext(ctx)
+	go func() {
+		ctx, span := startLinkedSpan(ctx, name)
+		if span == nil {
+			defer RecoverAndRecord(ctx)
+			_ = fn(ctx)
+			return
+		}
+		defer span.End()
+		defer RecoverAndRecord(ctx)
+
+		if err := fn(ctx); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+	}()
+}
+
+// startLinkedSpan starts a root span linked to the span in ctx, or returns a
+// nil span when the global provider is not set or disabled.
+func startLinkedSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	p := GlobalProvider()
+	if p == nil || !p.IsEnabled() {
+		return ctx, nil
+	}
+
+	spanOpts := []trace.SpanStartOption{
+		trace.WithNewRoot(),
+		trace.WithAttributes(attribute.String("component", asyncComponent)),
+	}
+	if parent := trace.SpanContextFromContext(ctx); parent.IsValid() {
+		spanOpts = append(spanOpts, trace.WithLinks(trace.Link{SpanContext: parent}))
+	}
+	if kind := inferSpanKind(name, asyncComponent); kind != trace.SpanKindUnspecified {
+		spanOpts = append(spanOpts, trace.WithSpanKind(kind))
+	}
+	return p.GetTracer(asyncComponent).Start(ctx, name, spanOpts...)
+}
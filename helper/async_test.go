@@ -0,0 +1,109 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestDetachedContext(t *testing.T) {
+	member, _ := baggage.NewMember("tenant", "acme")
+	bag, _ := baggage.New(member)
+	parent, cancel := context.WithTimeout(baggage.ContextWithBaggage(context.Background(), bag), time.Minute)
+	cancel()
+
+	ctx := DetachedContext(parent)
+	if ctx.Err() != nil {
+		t.Errorf("Err() = %v, want the detached context not cancelled", ctx.Err())
+	}
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("detached context kept the parent deadline")
+	}
+	if got := baggage.FromContext(ctx).Member("tenant").Value(); got != "acme" {
+		t.Errorf("baggage tenant = %q, want acme", got)
+	}
+}
+
+func TestGo_LinkedSpanSurvivesCancelledRequest(t *testing.T) {
+	p, recorder, _ := newRecordingProvider()
+	SetGlobalProvider(p)
+	t.Cleanup(func() { SetGlobalProvider(nil) })
+
+	reqCtx, cancel := context.WithCancel(context.Background())
+	reqCtx, reqSpan := p.tp.Tracer("test").Start(reqCtx, "request")
+
+	errc := make(chan error, 1)
+	Go(reqCtx, "send-receipt", func(ctx context.Context) error {
+		cancel()
+		errc <- ctx.Err()
+		return errors.New("smtp unavailable")
+	})
+	if err := <-errc; err != nil {
+		t.Errorf("background ctx.Err() = %v after the request was cancelled", err)
+	}
+	reqSpan.End()
+	waitForSpans(t, recorder, 2)
+
+	var job sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() == "send-receipt" {
+			job = s
+		}
+	}
+	if job == nil {
+		t.Fatal("no send-receipt span")
+	}
+	if job.Parent().IsValid() || job.SpanContext().TraceID() == reqSpan.SpanContext().TraceID() {
+		t.Error("background span is in the request trace, want a new root")
+	}
+	if links := job.Links(); len(links) != 1 || links[0].SpanContext.SpanID() != reqSpan.SpanContext().SpanID() {
+		t.Errorf("links = %v, want one link to the request span", links)
+	}
+	if job.Status().Code != codes.Error {
+		t.Errorf("status = %v, want Error", job.Status())
+	}
+}
+
+func TestGo_RecoversPanic(t *testing.T) {
+	p, recorder, _ := newRecordingProvider()
+	SetGlobalProvider(p)
+	t.Cleanup(func() { SetGlobalProvider(nil) })
+
+	Go(context.Background(), "explode", func(context.Context) error {
+		panic("boom")
+	})
+	waitForSpans(t, recorder, 1)
+
+	span := recorder.Ended()[0]
+	if span.Status().Code != codes.Error || len(span.Events()) != 1 || span.Events()[0].Name != "exception" {
+		t.Errorf("span = %v %v, want the panic recorded as an exception", span.Status(), span.Events())
+	}
+}
+
+func TestGo_WithoutProvider(t *testing.T) {
+	SetGlobalProvider(nil)
+
+	done := make(chan struct{})
+	Go(context.Background(), "job", func(context.Context) error {
+		defer close(done)
+		panic("recovered without a span")
+	})
+	<-done
+}
+
+func waitForSpans(t *testing.T, recorder *tracetest.SpanRecorder, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(recorder.Ended()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d ended spans, want %d", len(recorder.Ended()), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -6,33 +6,29 @@ import (
 	"go.opentelemetry.io/otel/baggage"
 )
 
-// SetBaggage sets a key-value pair in the context baggage.
+// SetBaggage returns a copy of ctx whose baggage has key set to value,
+// replacing any member with the same key and keeping the others. Baggage is
+// propagated to downstream services, so never put secrets in it. The value
+// is taken as is and percent-encoded on the wire; an invalid key or a
+// baggage over the W3C size limits returns ctx unchanged with the error.
+//
+// List the key in Traces.BaggageKeys or Metrics.BaggageKeys to also record
+// it on spans or metrics.
 func SetBaggage(ctx context.Context, key, value string) (context.Context, error) {
-	member, err := baggage.NewMember(key, value)
+	member, err := baggage.NewMemberRaw(key, value)
 	if err != nil {
 		return ctx, err
 	}
 
-	bag, err := baggage.New(member)
+	bag, err := baggage.FromContext(ctx).SetMember(member)
 	if err != nil {
 		return ctx, err
 	}
-
-	existing := baggage.FromContext(ctx)
-	for _, m := range existing.Members() {
-		if m.Key() != key {
-			newMember, err := baggage.NewMember(m.Key(), m.Value())
-			if err != nil {
-				continue
-			}
-			bag, _ = bag.SetMember(newMember)
-		}
-	}
-
 	return baggage.ContextWithBaggage(ctx, bag), nil
 }
 
-// GetBaggage retrieves a value from the context baggage.
+// GetBaggage retrieves a value from the context baggage, or "" when the key
+// is absent.
 func GetBaggage(ctx context.Context, key string) string {
 	bag := baggage.FromContext(ctx)
 	return bag.Member(key).Value()
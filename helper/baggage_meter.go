@@ -0,0 +1,165 @@
+package helper
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// NewBaggageMeter wraps meter so that every measurement recorded through its
+// synchronous instruments carries the listed baggage members of the
+// measurement's context as attributes. Traces and metrics then share the same
+// business segmentation (deployment.ring, customer.tier, ...) without every
+// call site copying baggage by hand.
+//
+// Keep keys low-cardinality: each distinct value is a new metric series.
+// Absent members are skipped, and attributes passed to Add/Record win over
+// baggage with the same key. Observable instruments have no caller context
+// and are not affected. With no keys, meter is returned unchanged.
+func NewBaggageMeter(meter metric.Meter, keys ...string) metric.Meter {
+	if len(keys) == 0 {
+		return meter
+	}
+	return baggageMeter{Meter: meter, keys: keys}
+}
+
+type baggageMeter struct {
+	metric.Meter
+	keys []string
+}
+
+// baggageOptions prepends the baggage attributes of ctx to opts, so explicit
+// attributes override them.
+func baggageOptions[O any](keys []string, ctx context.Context, opts []O, wrap func(metric.MeasurementOption) O) []O {
+	bag := baggage.FromContext(ctx)
+	if bag.Len() == 0 {
+		return opts
+	}
+	var attrs []attribute.KeyValue
+	for _, key := range keys {
+		if m := bag.Member(key); m.Key() != "" {
+			attrs = append(attrs, attribute.String(key, m.Value()))
+		}
+	}
+	if len(attrs) == 0 {
+		return opts
+	}
+	return append([]O{wrap(metric.WithAttributes(attrs...))}, opts...)
+}
+
+func addOption(o metric.MeasurementOption) metric.AddOption       { return o }
+func recordOption(o metric.MeasurementOption) metric.RecordOption { return o }
+
+func (m baggageMeter) Int64Counter(name string, options ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	inst, err := m.Meter.Int64Counter(name, options...)
+	return baggageInt64Counter{inst, m.keys}, err
+}
+
+func (m baggageMeter) Float64Counter(name string, options ...metric.Float64CounterOption) (metric.Float64Counter, error) {
+	inst, err := m.Meter.Float64Counter(name, options...)
+	return baggageFloat64Counter{inst, m.keys}, err
+}
+
+func (m baggageMeter) Int64UpDownCounter(name string, options ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	inst, err := m.Meter.Int64UpDownCounter(name, options...)
+	return baggageInt64UpDownCounter{inst, m.keys}, err
+}
+
+func (m baggageMeter) Float64UpDownCounter(name string, options ...metric.Float64UpDownCounterOption) (metric.Float64UpDownCounter, error) {
+	inst, err := m.Meter.Float64UpDownCounter(name, options...)
+	return baggageFloat64UpDownCounter{inst, m.keys}, err
+}
+
+func (m baggageMeter) Int64Histogram(name string, options ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	inst, err := m.Meter.Int64Histogram(name, options...)
+	return baggageInt64Histogram{inst, m.keys}, err
+}
+
+func (m baggageMeter) Float64Histogram(name string, options ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	inst, err := m.Meter.Float64Histogram(name, options...)
+	return baggageFloat64Histogram{inst, m.keys}, err
+}
+
+func (m baggageMeter) Int64Gauge(name string, options ...metric.Int64GaugeOption) (metric.Int64Gauge, error) {
+	inst, err := m.Meter.Int64Gauge(name, options...)
+	return baggageInt64Gauge{inst, m.keys}, err
+}
+
+func (m baggageMeter) Float64Gauge(name string, options ...metric.Float64GaugeOption) (metric.Float64Gauge, error) {
+	inst, err := m.Meter.Float64Gauge(name, options...)
+	return baggageFloat64Gauge{inst, m.keys}, err
+}
+
+type baggageInt64Counter struct {
+	metric.Int64Counter
+	keys []string
+}
+
+func (c baggageInt64Counter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	c.Int64Counter.Add(ctx, incr, baggageOptions(c.keys, ctx, options, addOption)...)
+}
+
+type baggageFloat64Counter struct {
+	metric.Float64Counter
+	keys []string
+}
+
+func (c baggageFloat64Counter) Add(ctx context.Context, incr float64, options ...metric.AddOption) {
+	c.Float64Counter.Add(ctx, incr, baggageOptions(c.keys, ctx, options, addOption)...)
+}
+
+type baggageInt64UpDownCounter struct {
+	metric.Int64UpDownCounter
+	keys []string
+}
+
+func (c baggageInt64UpDownCounter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	c.Int64UpDownCounter.Add(ctx, incr, baggageOptions(c.keys, ctx, options, addOption)...)
+}
+
+type baggageFloat64UpDownCounter struct {
+	metric.Float64UpDownCounter
+	keys []string
+}
+
+func (c baggageFloat64UpDownCounter) Add(ctx context.Context, incr float64, options ...metric.AddOption) {
+	c.Float64UpDownCounter.Add(ctx, incr, baggageOptions(c.keys, ctx, options, addOption)...)
+}
+
+type baggageInt64Histogram struct {
+	metric.Int64Histogram
+	keys []string
+}
+
+func (h baggageInt64Histogram) Record(ctx context.Context, value int64, options ...metric.RecordOption) {
+	h.Int64Histogram.Record(ctx, value, baggageOptions(h.keys, ctx, options, recordOption)...)
+}
+
+type baggageFloat64Histogram struct {
+	metric.Float64Histogram
+	keys []string
+}
+
+func (h baggageFloat64Histogram) Record(ctx context.Context, value float64, options ...metric.RecordOption) {
+	h.Float64Histogram.Record(ctx, value, baggageOptions(h.keys, ctx, options, recordOption)...)
+}
+
+type baggageInt64Gauge struct {
+	metric.Int64Gauge
+	keys []string
+}
+
+func (g baggageInt64Gauge) Record(ctx context.Context, value int64, options ...metric.RecordOption) {
+	g.Int64Gauge.Record(ctx, value, baggageOptions(g.keys, ctx, options, recordOption)...)
+}
+
+type baggageFloat64Gauge struct {
+	metric.Float64Gauge
+	keys []string
+}
+
+func (g baggageFloat64Gauge) Record(ctx context.Context, value float64, options ...metric.RecordOption) {
+	g.Float64Gauge.Record(ctx, value, baggageOptions(g.keys, ctx, options, recordOption)...)
+}
@@ -0,0 +1,52 @@
+package helper
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestNewBaggageMeter_AddsConfiguredBaggageKeys(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := NewBaggageMeter(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"),
+		"deployment.ring", "customer.tier")
+
+	ring, _ := baggage.NewMember("deployment.ring", "canary")
+	tier, _ := baggage.NewMember("customer.tier", "gold")
+	user, _ := baggage.NewMember("user.id", "42")
+	bag, _ := baggage.New(ring, tier, user)
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+
+	counter, err := meter.Int64Counter("orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("customer.tier", "override")))
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	attrs := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64]).DataPoints[0].Attributes
+	if v, _ := attrs.Value("deployment.ring"); v.AsString() != "canary" {
+		t.Errorf("deployment.ring = %q, want canary", v.AsString())
+	}
+	if v, _ := attrs.Value("customer.tier"); v.AsString() != "override" {
+		t.Errorf("customer.tier = %q, want the explicit attribute to win", v.AsString())
+	}
+	if attrs.HasValue("user.id") {
+		t.Error("user.id is not a configured key and must not become an attribute")
+	}
+}
+
+func TestNewBaggageMeter_NoKeysReturnsMeter(t *testing.T) {
+	meter := sdkmetric.NewMeterProvider().Meter("test")
+	if got := NewBaggageMeter(meter); got != meter {
+		t.Error("expected the meter to be returned unchanged")
+	}
+}
@@ -0,0 +1,42 @@
+package helper
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSetBaggage(t *testing.T) {
+	ctx, err := SetBaggage(context.Background(), "tenant.id", "org-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = SetBaggage(ctx, "user.plan", "pro plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = SetBaggage(ctx, "tenant.id", "org-456")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := GetBaggage(ctx, "tenant.id"); got != "org-456" {
+		t.Errorf("tenant.id = %q, want the latest value", got)
+	}
+	if got := GetBaggage(ctx, "user.plan"); got != "pro plan" {
+		t.Errorf("user.plan = %q, want values kept as is", got)
+	}
+	if got := GetBaggage(ctx, "missing"); got != "" {
+		t.Errorf("missing = %q, want empty", got)
+	}
+}
+
+func TestSetBaggage_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	got, err := SetBaggage(ctx, "", "v")
+	if err == nil {
+		t.Fatal("expected an error for an empty key")
+	}
+	if got != ctx {
+		t.Error("expected ctx to be returned unchanged")
+	}
+}
@@ -9,6 +9,11 @@ import (
 )
 
 // TraceAndMeasure combines tracing and metrics for a function.
+//
+// Deprecated: use StartOperation or StartOperationWith, which name every
+// metric after the operation. TraceAndMeasure keeps recording
+// <name>_duration_seconds, <component>_operations_total and errors_total for
+// existing dashboards.
 func TraceAndMeasure(ctx context.Context, p TracerMeterProvider, name string, fn func(context.Context) error, opts *SpanOptions) error {
 	start := time.Now()
 
@@ -50,6 +55,9 @@ func TraceAndMeasure(ctx context.Context, p TracerMeterProvider, name string, fn
 }
 
 // TraceAndMeasureWithResult combines tracing and metrics for a function with result.
+//
+// Deprecated: use StartOperation or StartOperationWith, as for
+// TraceAndMeasure.
 func TraceAndMeasureWithResult[T any](ctx context.Context, p TracerMeterProvider, name string, fn func(context.Context) (T, error), opts *SpanOptions) (T, error) {
 	start := time.Now()
 
@@ -29,3 +29,16 @@ func IsTracing(ctx context.Context) bool {
 	span := trace.SpanFromContext(ctx)
 	return span.SpanContext().IsValid()
 }
+
+// IsRecording reports whether the span in ctx records data. Use it to skip
+// building attributes or events that would be discarded anyway.
+func IsRecording(ctx context.Context) bool {
+	return trace.SpanFromContext(ctx).IsRecording()
+}
+
+// IsSampled reports whether the span in ctx will be exported. A span can be
+// recording but not sampled (e.g. observed by a sampler for error boosting),
+// so prefer IsSampled to gate work that only matters in the exported trace.
+func IsSampled(ctx context.Context) bool {
+	return trace.SpanFromContext(ctx).SpanContext().IsSampled()
+}
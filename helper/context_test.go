@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 	"go.opentelemetry.io/otel/trace/noop"
 )
@@ -135,3 +136,43 @@ func TestIsTracing_ReturnsTrueForValidSpan(t *testing.T) {
 		t.Fatal("expected IsTracing to return true for valid span context")
 	}
 }
+
+func TestIsRecordingAndIsSampled(t *testing.T) {
+	recordOnly := sdktrace.NewTracerProvider(sdktrace.WithSampler(recordOnlySampler{}))
+	sampled := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		wantRecording bool
+		wantSampled   bool
+	}{
+		{"no span", context.Background(), false, false},
+		{"record only", startSpan(recordOnly), true, false},
+		{"sampled", startSpan(sampled), true, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsRecording(tc.ctx); got != tc.wantRecording {
+				t.Errorf("IsRecording = %v, want %v", got, tc.wantRecording)
+			}
+			if got := IsSampled(tc.ctx); got != tc.wantSampled {
+				t.Errorf("IsSampled = %v, want %v", got, tc.wantSampled)
+			}
+		})
+	}
+}
+
+// recordOnlySampler records every span without sampling it.
+type recordOnlySampler struct{}
+
+func (recordOnlySampler) ShouldSample(sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return sdktrace.SamplingResult{Decision: sdktrace.RecordOnly}
+}
+
+func (recordOnlySampler) Description() string { return "RecordOnly" }
+
+func startSpan(tp trace.TracerProvider) context.Context {
+	ctx, _ := tp.Tracer("test").Start(context.Background(), "op")
+	return ctx
+}
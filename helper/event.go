@@ -0,0 +1,103 @@
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/RodolfoBonis/go-otel-agent/scrub"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultMaxEventPayloadSize is the size, in bytes, beyond which EmitEvent
+// truncates the encoded payload unless SetEventPayloadLimit changes it.
+const DefaultMaxEventPayloadSize = 4096
+
+// Attributes EmitEvent sets on the span event.
+const (
+	// EventPayloadAttribute holds the scrubbed payload encoded as JSON.
+	EventPayloadAttribute = "event.payload"
+
+	// EventPayloadTruncatedAttribute is true when the payload was cut to
+	// the size limit; the JSON in event.payload is then incomplete.
+	EventPayloadTruncatedAttribute = "event.payload.truncated"
+
+	// EventPayloadErrorAttribute holds why the payload could not be encoded.
+	EventPayloadErrorAttribute = "event.payload.error"
+)
+
+var eventPayloadLimit atomic.Int64
+
+func init() {
+	eventPayloadLimit.Store(DefaultMaxEventPayloadSize)
+}
+
+// SetEventPayloadLimit sets the size, in bytes, of the encoded payload
+// EmitEvent records; longer payloads are truncated. n <= 0 restores
+// DefaultMaxEventPayloadSize.
+func SetEventPayloadLimit(n int) {
+	if n <= 0 {
+		n = DefaultMaxEventPayloadSize
+	}
+	eventPayloadLimit.Store(int64(n))
+}
+
+// EmitEvent records a domain event, such as order.created or
+// payment.declined, on the current span. payload is encoded as JSON
+// (honoring json tags), scrubbed with the default scrubber and stored in
+// event.payload, truncated to the limit set with SetEventPayloadLimit. A nil
+// payload records the event without one. Nothing is encoded unless the span
+// is recording.
+//
+//	helper.EmitEvent(ctx, "order.created", OrderCreated{ID: id, Total: total})
+func EmitEvent(ctx context.Context, name string, payload any) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	if payload == nil {
+		span.AddEvent(name)
+		return
+	}
+	span.AddEvent(name, trace.WithAttributes(eventPayload(payload)...))
+}
+
+// eventPayload returns the attributes carrying payload.
+func eventPayload(payload any) []attribute.KeyValue {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return []attribute.KeyValue{attribute.String(EventPayloadErrorAttribute, err.Error())}
+	}
+
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return []attribute.KeyValue{attribute.String(EventPayloadErrorAttribute, err.Error())}
+	}
+	switch v := decoded.(type) {
+	case map[string]any:
+		decoded = scrub.Map(v)
+	case []any:
+		// Scrub the objects nested in a top-level array as a map value.
+		decoded = scrub.Map(map[string]any{"": v})[""]
+	case string:
+		decoded = scrub.String(v)
+	}
+	if data, err = json.Marshal(decoded); err != nil {
+		return []attribute.KeyValue{attribute.String(EventPayloadErrorAttribute, err.Error())}
+	}
+
+	encoded := string(data)
+	limit := int(eventPayloadLimit.Load())
+	if len(encoded) <= limit {
+		return []attribute.KeyValue{attribute.String(EventPayloadAttribute, encoded)}
+	}
+	for limit > 0 && !utf8.RuneStart(encoded[limit]) {
+		limit--
+	}
+	return []attribute.KeyValue{
+		attribute.String(EventPayloadAttribute, encoded[:limit]),
+		attribute.Bool(EventPayloadTruncatedAttribute, true),
+	}
+}
@@ -0,0 +1,84 @@
+package helper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type orderCreated struct {
+	ID       string `json:"id"`
+	Total    int    `json:"total"`
+	Password string `json:"password"`
+}
+
+func emitOne(t *testing.T, payload any) attribute.Set {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "checkout")
+	EmitEvent(ctx, "order.created", payload)
+	span.End()
+
+	events := recorder.Ended()[0].Events()
+	if len(events) != 1 || events[0].Name != "order.created" {
+		t.Fatalf("events = %v, want one order.created", events)
+	}
+	return attribute.NewSet(events[0].Attributes...)
+}
+
+func TestEmitEvent_EncodesAndScrubsPayload(t *testing.T) {
+	attrs := emitOne(t, orderCreated{ID: "o-1", Total: 42, Password: "hunter2"})
+
+	v, _ := attrs.Value(EventPayloadAttribute)
+	if want := `{"id":"o-1","password":"[REDACTED]","total":42}`; v.AsString() != want {
+		t.Errorf("%s = %s, want %s", EventPayloadAttribute, v.AsString(), want)
+	}
+	if attrs.HasValue(EventPayloadTruncatedAttribute) {
+		t.Errorf("payload under the limit marked truncated")
+	}
+}
+
+func TestEmitEvent_TruncatesLargePayload(t *testing.T) {
+	SetEventPayloadLimit(16)
+	defer SetEventPayloadLimit(0)
+
+	attrs := emitOne(t, map[string]string{"note": strings.Repeat("é", 40)})
+
+	v, _ := attrs.Value(EventPayloadAttribute)
+	if len(v.AsString()) > 16 {
+		t.Errorf("%s is %d bytes, want at most 16", EventPayloadAttribute, len(v.AsString()))
+	}
+	if !strings.HasPrefix(v.AsString(), `{"note":"`) || !strings.HasSuffix(v.AsString(), "é") {
+		t.Errorf("%s = %q, want it cut on a rune boundary", EventPayloadAttribute, v.AsString())
+	}
+	if truncated, _ := attrs.Value(EventPayloadTruncatedAttribute); !truncated.AsBool() {
+		t.Errorf("%s not set", EventPayloadTruncatedAttribute)
+	}
+}
+
+func TestEmitEvent_UnencodablePayload(t *testing.T) {
+	attrs := emitOne(t, map[string]any{"callback": func() {}})
+
+	if attrs.HasValue(EventPayloadAttribute) || !attrs.HasValue(EventPayloadErrorAttribute) {
+		t.Errorf("attributes = %v, want only %s", attrs.ToSlice(), EventPayloadErrorAttribute)
+	}
+}
+
+func TestEmitEvent_SkipsNonRecordingSpan(t *testing.T) {
+	encoded := false
+	EmitEvent(context.Background(), "order.created", marshalerFunc(func() ([]byte, error) {
+		encoded = true
+		return []byte(`{}`), nil
+	}))
+	if encoded {
+		t.Error("payload encoded without a recording span")
+	}
+}
+
+type marshalerFunc func() ([]byte, error)
+
+func (f marshalerFunc) MarshalJSON() ([]byte, error) { return f() }
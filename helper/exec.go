@@ -0,0 +1,107 @@
+package helper
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/scrub"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// CommandOption configures TraceCommand.
+type CommandOption func(*commandOptions)
+
+type commandOptions struct {
+	stderrTail int
+}
+
+// WithStderrTail records up to the last n bytes the command wrote to stderr
+// as the process.stderr.tail attribute, scrubbed with the default scrubber.
+// Stderr still reaches cmd.Stderr unchanged.
+func WithStderrTail(n int) CommandOption {
+	return func(o *commandOptions) {
+		o.stderrTail = n
+	}
+}
+
+// TraceCommand runs cmd in an "exec <binary>" span (component "exec") that
+// records the binary name, duration and exit code, and marks the span as an
+// error when the command fails. Shell-outs are an easy latency source to
+// miss in traces.
+//
+// Build cmd with instrumentor.CommandContext so a child that uses the agent
+// continues the trace.
+func TraceCommand(ctx context.Context, cmd *exec.Cmd, opts ...CommandOption) error {
+	var o commandOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	binary := filepath.Base(cmd.Path)
+	_, span := Trace(ctx, "exec "+binary, &SpanOptions{
+		Component:  "exec",
+		Attributes: []attribute.KeyValue{attribute.String("process.executable.name", binary)},
+	})
+	defer span.End()
+
+	var tail *tailBuffer
+	if o.stderrTail > 0 && span.IsRecording() {
+		tail = &tailBuffer{max: o.stderrTail}
+		if cmd.Stderr == nil {
+			cmd.Stderr = tail
+		} else {
+			cmd.Stderr = io.MultiWriter(cmd.Stderr, tail)
+		}
+	}
+
+	start := time.Now()
+	err := cmd.Run()
+	duration := time.Since(start)
+
+	span.SetAttributes(attribute.Int64("duration_ms", duration.Milliseconds()))
+	if cmd.ProcessState != nil {
+		span.SetAttributes(
+			attribute.Int("process.exit.code", cmd.ProcessState.ExitCode()),
+			attribute.Int("process.pid", cmd.ProcessState.Pid()),
+		)
+	}
+	if tail != nil && len(tail.buf) > 0 {
+		span.SetAttributes(attribute.String("process.stderr.tail", scrub.String(tail.String())))
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+
+	return err
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	max       int
+	buf       []byte
+	truncated bool
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.max; over > 0 {
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	if b.truncated {
+		return "..." + string(b.buf)
+	}
+	return string(b.buf)
+}
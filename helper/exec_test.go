@@ -0,0 +1,51 @@
+package helper
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTraceCommand_RecordsExitCodeAndStderrTail(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	recorder := tracetest.NewSpanRecorder()
+	SetGlobalProvider(tracingProvider{sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))})
+	t.Cleanup(func() { SetGlobalProvider(nil) })
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(sh, "-c", "echo 'first line' >&2; echo 'disk full' >&2; exit 3")
+	cmd.Stderr = &stderr
+	if err := TraceCommand(context.Background(), cmd, WithStderrTail(10)); err == nil {
+		t.Fatal("expected the exit error")
+	}
+
+	if !bytes.Contains(stderr.Bytes(), []byte("first line")) {
+		t.Errorf("stderr = %q, want the full output passed through", stderr.String())
+	}
+	span := recorder.Ended()[0]
+	if span.Name() != "exec sh" || span.Status().Code != codes.Error {
+		t.Errorf("span %q status %v, want a failed \"exec sh\" span", span.Name(), span.Status().Code)
+	}
+	attrs := attribute.NewSet(span.Attributes()...)
+	if v, _ := attrs.Value("process.exit.code"); v.AsInt64() != 3 {
+		t.Errorf("process.exit.code = %v, want 3", v.AsInt64())
+	}
+	if v, _ := attrs.Value("process.stderr.tail"); v.AsString() != "...disk full\n" {
+		t.Errorf("process.stderr.tail = %q, want the last 10 bytes", v.AsString())
+	}
+}
+
+func TestTraceCommand_WithoutProvider(t *testing.T) {
+	if err := TraceCommand(context.Background(), exec.Command("true")); err != nil {
+		t.Skipf("true not available: %v", err)
+	}
+}
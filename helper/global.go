@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -46,6 +47,15 @@ func Measure(ctx context.Context, name string, duration time.Duration, opts *Met
 	RecordDuration(ctx, p, name, duration, opts)
 }
 
+// MeasureMillis records a duration in milliseconds using the global provider.
+func MeasureMillis(ctx context.Context, name string, duration time.Duration, opts *MetricOptions) {
+	p := GlobalProvider()
+	if p == nil {
+		return
+	}
+	RecordDurationMillis(ctx, p, name, duration, opts)
+}
+
 // Count increments a counter metric using the global provider.
 func Count(ctx context.Context, name string, value int64, opts *MetricOptions) {
 	p := GlobalProvider()
@@ -55,6 +65,31 @@ func Count(ctx context.Context, name string, value int64, opts *MetricOptions) {
 	IncrementCounter(ctx, p, name, value, opts)
 }
 
+// CountFloat increments a Float64 counter metric using the global provider.
+func CountFloat(ctx context.Context, name string, value float64, opts *MetricOptions) {
+	p := GlobalProvider()
+	if p == nil {
+		return
+	}
+	IncrementFloat64Counter(ctx, p, name, value, opts)
+}
+
+// UpDown adds delta to an UpDownCounter metric using the global provider.
+func UpDown(ctx context.Context, name string, delta int64, opts *MetricOptions) {
+	p := GlobalProvider()
+	if p == nil {
+		return
+	}
+	AddUpDownCounter(ctx, p, name, delta, opts)
+}
+
+// ObserveGauge registers an observable gauge using the global provider; see
+// RegisterObservableGauge. The gauge is bound to the provider set when it is
+// called, so call it after the agent is running.
+func ObserveGauge(name string, callback func(context.Context) float64, opts *MetricOptions) (metric.Registration, error) {
+	return RegisterObservableGauge(GlobalProvider(), name, callback, opts)
+}
+
 // Event adds an event to the current span.
 func Event(ctx context.Context, name string, attributes ...attribute.KeyValue) {
 	AddSpanEvent(ctx, name, attributes...)
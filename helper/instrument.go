@@ -0,0 +1,275 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// OtherAttrValue replaces the values of a bounded attribute that are not in
+// its allow-list.
+const OtherAttrValue = "_other"
+
+// InstrumentOption configures an instrument built with NewCounter,
+// NewFloat64Counter, NewUpDownCounter, NewHistogram or NewGauge.
+type InstrumentOption func(*instrumentConfig)
+
+type instrumentConfig struct {
+	provider    TracerMeterProvider // nil uses the global provider
+	component   string
+	description string
+	unit        string
+	bounded     map[attribute.Key]map[string]struct{}
+}
+
+// WithUnit sets the instrument's unit ("1", "By", "s", ...).
+func WithUnit(unit string) InstrumentOption {
+	return func(c *instrumentConfig) {
+		c.unit = unit
+	}
+}
+
+// WithDescription sets the instrument's description.
+func WithDescription(description string) InstrumentOption {
+	return func(c *instrumentConfig) {
+		c.description = description
+	}
+}
+
+// WithMeterComponent sets the meter scope and the component attribute, as
+// MetricOptions.Component does. The default is "default".
+func WithMeterComponent(component string) InstrumentOption {
+	return func(c *instrumentConfig) {
+		c.component = component
+	}
+}
+
+// WithMeterProvider records through p instead of the global provider.
+func WithMeterProvider(p TracerMeterProvider) InstrumentOption {
+	return func(c *instrumentConfig) {
+		c.provider = p
+	}
+}
+
+// WithBoundedAttr limits the values of the key attribute to allowed. Other
+// values are recorded as OtherAttrValue, so an unexpected input cannot blow
+// up the metric's cardinality.
+func WithBoundedAttr(key string, allowed ...string) InstrumentOption {
+	return func(c *instrumentConfig) {
+		if c.bounded == nil {
+			c.bounded = make(map[attribute.Key]map[string]struct{})
+		}
+		values := make(map[string]struct{}, len(allowed))
+		for _, v := range allowed {
+			values[v] = struct{}{}
+		}
+		c.bounded[attribute.Key(key)] = values
+	}
+}
+
+func newInstrumentConfig(opts []InstrumentOption) instrumentConfig {
+	c := instrumentConfig{component: "default"}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// describe returns the configured description, or format applied to name.
+func (c *instrumentConfig) describe(format, name string) string {
+	if c.description != "" {
+		return c.description
+	}
+	return fmt.Sprintf(format, name)
+}
+
+// measurement returns the attributes of a measurement: the component, then
+// attrs with bounded values outside their allow-list replaced.
+func (c *instrumentConfig) measurement(attrs []attribute.KeyValue) metric.MeasurementOption {
+	all := make([]attribute.KeyValue, 0, len(attrs)+1)
+	all = append(all, attribute.String("component", c.component))
+	for _, kv := range attrs {
+		if allowed, ok := c.bounded[kv.Key]; ok {
+			if _, ok := allowed[kv.Value.Emit()]; !ok {
+				kv = attribute.String(string(kv.Key), OtherAttrValue)
+			}
+		}
+		all = append(all, kv)
+	}
+	return metric.WithAttributes(all...)
+}
+
+// boundInstrument is an instrument created from the meter of p.
+type boundInstrument[T any] struct {
+	p    TracerMeterProvider
+	inst T
+}
+
+// lazyInstrument creates its instrument on first use, and again whenever the
+// provider changes, so handles can be package variables declared before the
+// agent starts.
+type lazyInstrument[T any] struct {
+	cfg    instrumentConfig
+	create func(metric.Meter) (T, error)
+	cur    atomic.Pointer[boundInstrument[T]]
+}
+
+func (l *lazyInstrument[T]) get() (T, bool) {
+	var zero T
+	p := l.cfg.provider
+	if p == nil {
+		p = GlobalProvider()
+	}
+	if p == nil || !p.IsEnabled() {
+		return zero, false
+	}
+	if b := l.cur.Load(); b != nil && b.p == p {
+		return b.inst, true
+	}
+	inst, err := l.create(p.GetMeter(l.cfg.component))
+	if err != nil {
+		return zero, false
+	}
+	l.cur.Store(&boundInstrument[T]{p: p, inst: inst})
+	return inst, true
+}
+
+// Counter is a typed handle to an Int64Counter.
+//
+//	var accepted = helper.NewCounter("payments.accepted",
+//		helper.WithUnit("1"),
+//		helper.WithBoundedAttr("provider", "stripe", "adyen"),
+//	)
+//
+//	accepted.Add(ctx, 1, attribute.String("provider", name))
+type Counter struct {
+	lazy lazyInstrument[metric.Int64Counter]
+}
+
+// NewCounter returns a Counter named name. The instrument is created on the
+// first Add after a provider is available.
+func NewCounter(name string, opts ...InstrumentOption) *Counter {
+	c := &Counter{}
+	c.lazy = lazyInstrument[metric.Int64Counter]{cfg: newInstrumentConfig(opts)}
+	c.lazy.create = func(m metric.Meter) (metric.Int64Counter, error) {
+		return m.Int64Counter(name,
+			metric.WithDescription(c.lazy.cfg.describe("Counter for %s events", name)),
+			metric.WithUnit(c.lazy.cfg.unit))
+	}
+	return c
+}
+
+// Add adds n to the counter.
+func (c *Counter) Add(ctx context.Context, n int64, attrs ...attribute.KeyValue) {
+	if inst, ok := c.lazy.get(); ok {
+		inst.Add(ctx, n, c.lazy.cfg.measurement(attrs))
+	}
+}
+
+// Float64Counter is a typed handle to a Float64Counter.
+type Float64Counter struct {
+	lazy lazyInstrument[metric.Float64Counter]
+}
+
+// NewFloat64Counter returns a Float64Counter named name. The instrument is
+// created on the first Add after a provider is available.
+func NewFloat64Counter(name string, opts ...InstrumentOption) *Float64Counter {
+	c := &Float64Counter{}
+	c.lazy = lazyInstrument[metric.Float64Counter]{cfg: newInstrumentConfig(opts)}
+	c.lazy.create = func(m metric.Meter) (metric.Float64Counter, error) {
+		return m.Float64Counter(name,
+			metric.WithDescription(c.lazy.cfg.describe("Counter for %s events", name)),
+			metric.WithUnit(c.lazy.cfg.unit))
+	}
+	return c
+}
+
+// Add adds v, which must not be negative, to the counter.
+func (c *Float64Counter) Add(ctx context.Context, v float64, attrs ...attribute.KeyValue) {
+	if inst, ok := c.lazy.get(); ok {
+		inst.Add(ctx, v, c.lazy.cfg.measurement(attrs))
+	}
+}
+
+// UpDownCounter is a typed handle to an Int64UpDownCounter.
+//
+//	var inFlight = helper.NewUpDownCounter("jobs.in_flight", helper.WithUnit("{job}"))
+//
+//	inFlight.Add(ctx, 1)
+//	defer inFlight.Add(ctx, -1)
+type UpDownCounter struct {
+	lazy lazyInstrument[metric.Int64UpDownCounter]
+}
+
+// NewUpDownCounter returns an UpDownCounter named name. The instrument is
+// created on the first Add after a provider is available.
+func NewUpDownCounter(name string, opts ...InstrumentOption) *UpDownCounter {
+	c := &UpDownCounter{}
+	c.lazy = lazyInstrument[metric.Int64UpDownCounter]{cfg: newInstrumentConfig(opts)}
+	c.lazy.create = func(m metric.Meter) (metric.Int64UpDownCounter, error) {
+		return m.Int64UpDownCounter(name,
+			metric.WithDescription(c.lazy.cfg.describe("Current number of %s", name)),
+			metric.WithUnit(c.lazy.cfg.unit))
+	}
+	return c
+}
+
+// Add adds delta, which may be negative, to the counter.
+func (c *UpDownCounter) Add(ctx context.Context, delta int64, attrs ...attribute.KeyValue) {
+	if inst, ok := c.lazy.get(); ok {
+		inst.Add(ctx, delta, c.lazy.cfg.measurement(attrs))
+	}
+}
+
+// Histogram is a typed handle to a Float64Histogram.
+type Histogram struct {
+	lazy lazyInstrument[metric.Float64Histogram]
+}
+
+// NewHistogram returns a Histogram named name. The instrument is created on
+// the first Record after a provider is available.
+func NewHistogram(name string, opts ...InstrumentOption) *Histogram {
+	h := &Histogram{}
+	h.lazy = lazyInstrument[metric.Float64Histogram]{cfg: newInstrumentConfig(opts)}
+	h.lazy.create = func(m metric.Meter) (metric.Float64Histogram, error) {
+		return m.Float64Histogram(name,
+			metric.WithDescription(h.lazy.cfg.describe("Distribution of %s values", name)),
+			metric.WithUnit(h.lazy.cfg.unit))
+	}
+	return h
+}
+
+// Record records v.
+func (h *Histogram) Record(ctx context.Context, v float64, attrs ...attribute.KeyValue) {
+	if inst, ok := h.lazy.get(); ok {
+		inst.Record(ctx, v, h.lazy.cfg.measurement(attrs))
+	}
+}
+
+// Gauge is a typed handle to an Int64Gauge.
+type Gauge struct {
+	lazy lazyInstrument[metric.Int64Gauge]
+}
+
+// NewGauge returns a Gauge named name. The instrument is created on the
+// first Record after a provider is available.
+func NewGauge(name string, opts ...InstrumentOption) *Gauge {
+	g := &Gauge{}
+	g.lazy = lazyInstrument[metric.Int64Gauge]{cfg: newInstrumentConfig(opts)}
+	g.lazy.create = func(m metric.Meter) (metric.Int64Gauge, error) {
+		return m.Int64Gauge(name,
+			metric.WithDescription(g.lazy.cfg.describe("Gauge for %s values", name)),
+			metric.WithUnit(g.lazy.cfg.unit))
+	}
+	return g
+}
+
+// Record sets the gauge to v.
+func (g *Gauge) Record(ctx context.Context, v int64, attrs ...attribute.KeyValue) {
+	if inst, ok := g.lazy.get(); ok {
+		inst.Record(ctx, v, g.lazy.cfg.measurement(attrs))
+	}
+}
@@ -0,0 +1,101 @@
+package helper
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestCounter_BoundsAttributeValues(t *testing.T) {
+	p, reader := newTestProvider()
+	accepted := NewCounter("test.instrument.accepted",
+		WithMeterProvider(p),
+		WithUnit("1"),
+		WithMeterComponent("payments"),
+		WithBoundedAttr("provider", "stripe", "adyen"),
+	)
+
+	ctx := context.Background()
+	accepted.Add(ctx, 1, attribute.String("provider", "stripe"))
+	accepted.Add(ctx, 2, attribute.String("provider", "mystery-psp"))
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	m := rm.ScopeMetrics[0].Metrics[0]
+	if m.Name != "test.instrument.accepted" || m.Unit != "1" {
+		t.Fatalf("metric = %s (%s), want test.instrument.accepted (1)", m.Name, m.Unit)
+	}
+	if scope := rm.ScopeMetrics[0].Scope.Name; scope != "payments" {
+		t.Errorf("scope = %q, want payments", scope)
+	}
+	got := map[string]int64{}
+	for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+		v, _ := dp.Attributes.Value("provider")
+		got[v.AsString()] = dp.Value
+	}
+	if got["stripe"] != 1 || got[OtherAttrValue] != 2 || len(got) != 2 {
+		t.Errorf("data points by provider = %v, want stripe=1 %s=2", got, OtherAttrValue)
+	}
+}
+
+func TestInstruments_BindToGlobalProviderLazily(t *testing.T) {
+	SetGlobalProvider(nil)
+	t.Cleanup(func() { SetGlobalProvider(nil) })
+
+	ctx := context.Background()
+	latency := NewHistogram("test.instrument.latency", WithUnit("s"))
+	depth := NewGauge("test.instrument.depth")
+
+	latency.Record(ctx, 0.5) // no provider yet, dropped
+
+	p, reader := newTestProvider()
+	SetGlobalProvider(p)
+	latency.Record(ctx, 0.25)
+	depth.Record(ctx, 7)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	found := 0
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		switch data := m.Data.(type) {
+		case metricdata.Histogram[float64]:
+			if dp := data.DataPoints[0]; dp.Count != 1 || dp.Sum != 0.25 {
+				t.Errorf("latency count/sum = %d/%v, want 1/0.25", dp.Count, dp.Sum)
+			}
+			found++
+		case metricdata.Gauge[int64]:
+			if v := data.DataPoints[0].Value; v != 7 {
+				t.Errorf("depth = %d, want 7", v)
+			}
+			found++
+		}
+	}
+	if found != 2 {
+		t.Errorf("found %d of the 2 instruments", found)
+	}
+}
+
+func TestUpDownCounter_AddsDeltas(t *testing.T) {
+	p, reader := newTestProvider()
+	inFlight := NewUpDownCounter("test.instrument.in_flight", WithMeterProvider(p), WithUnit("{job}"))
+
+	ctx := context.Background()
+	inFlight.Add(ctx, 1)
+	inFlight.Add(ctx, 1)
+	inFlight.Add(ctx, -1)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	sum := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	if sum.IsMonotonic || sum.DataPoints[0].Value != 1 {
+		t.Errorf("in_flight = %d (monotonic %v), want 1 non-monotonic", sum.DataPoints[0].Value, sum.IsMonotonic)
+	}
+}
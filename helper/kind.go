@@ -0,0 +1,97 @@
+package helper
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// KindOption configures span kind inference.
+type KindOption func(*kindRules)
+
+type prefixRule struct {
+	prefix string
+	kind   trace.SpanKind
+}
+
+type kindRules struct {
+	prefixes   []prefixRule
+	components map[string]trace.SpanKind
+}
+
+var activeKindRules atomic.Pointer[kindRules]
+
+// WithClientKindForPrefix marks spans whose name starts with any of the
+// prefixes as CLIENT spans, e.g. WithClientKindForPrefix("http.", "db.").
+func WithClientKindForPrefix(prefixes ...string) KindOption {
+	return withPrefixKind(trace.SpanKindClient, prefixes)
+}
+
+// WithProducerKindForPrefix marks spans whose name starts with any of the
+// prefixes as PRODUCER spans.
+func WithProducerKindForPrefix(prefixes ...string) KindOption {
+	return withPrefixKind(trace.SpanKindProducer, prefixes)
+}
+
+// WithConsumerKindForPrefix marks spans whose name starts with any of the
+// prefixes as CONSUMER spans.
+func WithConsumerKindForPrefix(prefixes ...string) KindOption {
+	return withPrefixKind(trace.SpanKindConsumer, prefixes)
+}
+
+// WithComponentKind sets the default kind for every span started with
+// SpanOptions.Component equal to component. Component defaults take
+// precedence over name prefixes.
+func WithComponentKind(component string, kind trace.SpanKind) KindOption {
+	return func(r *kindRules) {
+		if r.components == nil {
+			r.components = make(map[string]trace.SpanKind)
+		}
+		r.components[component] = kind
+	}
+}
+
+func withPrefixKind(kind trace.SpanKind, prefixes []string) KindOption {
+	return func(r *kindRules) {
+		for _, p := range prefixes {
+			if p != "" {
+				r.prefixes = append(r.prefixes, prefixRule{prefix: p, kind: kind})
+			}
+		}
+	}
+}
+
+// SetSpanKindInference replaces the rules StartSpan uses to pick a span kind
+// when SpanOptions.Kind is unspecified. It is safe to call while spans are
+// being started; calling it with no options restores the INTERNAL default.
+func SetSpanKindInference(opts ...KindOption) {
+	if len(opts) == 0 {
+		activeKindRules.Store(nil)
+		return
+	}
+	r := &kindRules{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	activeKindRules.Store(r)
+}
+
+// inferSpanKind returns the kind configured for the span, or
+// SpanKindUnspecified when no rule matches. The longest matching prefix wins.
+func inferSpanKind(name, component string) trace.SpanKind {
+	r := activeKindRules.Load()
+	if r == nil {
+		return trace.SpanKindUnspecified
+	}
+	if kind, ok := r.components[component]; ok {
+		return kind
+	}
+	kind, longest := trace.SpanKindUnspecified, 0
+	for _, p := range r.prefixes {
+		if len(p.prefix) > longest && strings.HasPrefix(name, p.prefix) {
+			kind, longest = p.kind, len(p.prefix)
+		}
+	}
+	return kind
+}
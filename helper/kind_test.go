@@ -0,0 +1,69 @@
+package helper
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/metric"
+	noopmetric "go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracingProvider struct{ tp *sdktrace.TracerProvider }
+
+func (p tracingProvider) GetTracer(name string) trace.Tracer { return p.tp.Tracer(name) }
+func (p tracingProvider) GetMeter(name string) metric.Meter {
+	return noopmetric.NewMeterProvider().Meter(name)
+}
+func (p tracingProvider) IsEnabled() bool { return true }
+
+func startedKind(t *testing.T, name string, opts *SpanOptions) trace.SpanKind {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	p := tracingProvider{sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))}
+	_, span := StartSpan(context.Background(), p, name, opts)
+	span.End()
+	return recorder.Ended()[0].SpanKind()
+}
+
+func TestStartSpan_InfersKind(t *testing.T) {
+	SetSpanKindInference(
+		WithClientKindForPrefix("http.", "db."),
+		WithProducerKindForPrefix("kafka."),
+		WithConsumerKindForPrefix("kafka.consume."),
+		WithComponentKind("webhooks", trace.SpanKindServer),
+	)
+	t.Cleanup(func() { SetSpanKindInference() })
+
+	tests := []struct {
+		name string
+		span string
+		opts *SpanOptions
+		want trace.SpanKind
+	}{
+		{"prefix", "db.query", nil, trace.SpanKindClient},
+		{"longest prefix wins", "kafka.consume.orders", nil, trace.SpanKindConsumer},
+		{"shorter prefix", "kafka.publish", nil, trace.SpanKindProducer},
+		{"no match", "process-order", nil, trace.SpanKindInternal},
+		{"component default", "http.receive", &SpanOptions{Component: "webhooks"}, trace.SpanKindServer},
+		{"explicit kind wins", "http.get", &SpanOptions{Kind: trace.SpanKindServer}, trace.SpanKindServer},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := startedKind(t, tc.span, tc.opts); got != tc.want {
+				t.Errorf("kind = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetSpanKindInference_NoOptionsRestoresInternal(t *testing.T) {
+	SetSpanKindInference(WithClientKindForPrefix("http."))
+	SetSpanKindInference()
+
+	if got := startedKind(t, "http.get", nil); got != trace.SpanKindInternal {
+		t.Errorf("kind = %v, want internal", got)
+	}
+}
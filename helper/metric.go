@@ -19,9 +19,18 @@ type MetricOptions struct {
 // instrumentCache caches metric instruments to avoid recreation on every call.
 // Fix: original code recreated instruments on every RecordDuration/IncrementCounter/SetGauge call.
 var (
-	histogramCache sync.Map // key -> metric.Float64Histogram
-	counterCache   sync.Map // key -> metric.Int64Counter
-	gaugeCache     sync.Map // key -> metric.Int64Gauge
+	histogramCache      sync.Map // key -> metric.Float64Histogram
+	int64HistogramCache sync.Map // key -> metric.Int64Histogram
+	counterCache        sync.Map // key -> metric.Int64Counter
+	float64CounterCache sync.Map // key -> metric.Float64Counter
+	upDownCounterCache  sync.Map // key -> metric.Int64UpDownCounter
+	gaugeCache          sync.Map // key -> metric.Int64Gauge
+)
+
+// Duration units supported by the integer duration helpers.
+const (
+	UnitMilliseconds = "ms"
+	UnitMicroseconds = "us"
 )
 
 // RecordDuration records a duration metric with cached instrument.
@@ -103,6 +112,88 @@ func IncrementCounter(ctx context.Context, p TracerMeterProvider, name string, v
 	counter.Add(ctx, value, metric.WithAttributes(attrs...))
 }
 
+// IncrementFloat64Counter adds value to a Float64 counter with cached
+// instrument, for monotonic totals that are not whole numbers (amounts,
+// consumed CPU seconds).
+func IncrementFloat64Counter(ctx context.Context, p TracerMeterProvider, name string, value float64, opts *MetricOptions) {
+	if p == nil || !p.IsEnabled() {
+		return
+	}
+
+	component := "default"
+	if opts != nil && opts.Component != "" {
+		component = opts.Component
+	}
+
+	cacheKey := component + ":" + name
+	var counter metric.Float64Counter
+
+	if cached, ok := float64CounterCache.Load(cacheKey); ok {
+		counter = cached.(metric.Float64Counter)
+	} else {
+		meter := p.GetMeter(component)
+		var err error
+		counter, err = meter.Float64Counter(
+			name,
+			metric.WithDescription(fmt.Sprintf("Counter for %s events", name)),
+		)
+		if err != nil {
+			return
+		}
+		float64CounterCache.Store(cacheKey, counter)
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("component", component),
+	}
+	if opts != nil && len(opts.Attributes) > 0 {
+		attrs = append(attrs, opts.Attributes...)
+	}
+
+	counter.Add(ctx, value, metric.WithAttributes(attrs...))
+}
+
+// AddUpDownCounter adds delta, which may be negative, to an UpDownCounter
+// with cached instrument. Use it for values that go up and down, such as
+// in-flight requests: add 1 when work starts and -1 when it ends.
+func AddUpDownCounter(ctx context.Context, p TracerMeterProvider, name string, delta int64, opts *MetricOptions) {
+	if p == nil || !p.IsEnabled() {
+		return
+	}
+
+	component := "default"
+	if opts != nil && opts.Component != "" {
+		component = opts.Component
+	}
+
+	cacheKey := component + ":" + name
+	var counter metric.Int64UpDownCounter
+
+	if cached, ok := upDownCounterCache.Load(cacheKey); ok {
+		counter = cached.(metric.Int64UpDownCounter)
+	} else {
+		meter := p.GetMeter(component)
+		var err error
+		counter, err = meter.Int64UpDownCounter(
+			name,
+			metric.WithDescription(fmt.Sprintf("Current number of %s", name)),
+		)
+		if err != nil {
+			return
+		}
+		upDownCounterCache.Store(cacheKey, counter)
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("component", component),
+	}
+	if opts != nil && len(opts.Attributes) > 0 {
+		attrs = append(attrs, opts.Attributes...)
+	}
+
+	counter.Add(ctx, delta, metric.WithAttributes(attrs...))
+}
+
 // SetGauge sets a gauge metric value with cached instrument.
 func SetGauge(ctx context.Context, p TracerMeterProvider, name string, value int64, opts *MetricOptions) {
 	if p == nil || !p.IsEnabled() {
@@ -141,3 +232,114 @@ func SetGauge(ctx context.Context, p TracerMeterProvider, name string, value int
 
 	gauge.Record(ctx, value, metric.WithAttributes(attrs...))
 }
+
+// RecordDurationMillis records a duration in whole milliseconds on an Int64
+// histogram with unit "ms", for dashboards standardized on milliseconds.
+func RecordDurationMillis(ctx context.Context, p TracerMeterProvider, name string, duration time.Duration, opts *MetricOptions) {
+	recordInt64Histogram(ctx, p, name, duration.Milliseconds(), UnitMilliseconds,
+		fmt.Sprintf("Duration of %s operations", name), opts)
+}
+
+// RecordDurationMicros records a duration in whole microseconds on an Int64
+// histogram with unit "us".
+func RecordDurationMicros(ctx context.Context, p TracerMeterProvider, name string, duration time.Duration, opts *MetricOptions) {
+	recordInt64Histogram(ctx, p, name, duration.Microseconds(), UnitMicroseconds,
+		fmt.Sprintf("Duration of %s operations", name), opts)
+}
+
+// RecordInt64Histogram records an integer value (sizes, counts per batch,
+// etc.) on an Int64 histogram with cached instrument. unit may be empty.
+func RecordInt64Histogram(ctx context.Context, p TracerMeterProvider, name string, value int64, unit string, opts *MetricOptions) {
+	recordInt64Histogram(ctx, p, name, value, unit,
+		fmt.Sprintf("Distribution of %s values", name), opts)
+}
+
+func recordInt64Histogram(ctx context.Context, p TracerMeterProvider, name string, value int64, unit, description string, opts *MetricOptions) {
+	if p == nil || !p.IsEnabled() {
+		return
+	}
+
+	component := "default"
+	if opts != nil && opts.Component != "" {
+		component = opts.Component
+	}
+
+	// Unit is part of the key: the same name with a different unit is a
+	// different instrument.
+	cacheKey := component + ":" + name + ":" + unit
+	var histogram metric.Int64Histogram
+
+	if cached, ok := int64HistogramCache.Load(cacheKey); ok {
+		histogram = cached.(metric.Int64Histogram)
+	} else {
+		meter := p.GetMeter(component)
+		instOpts := []metric.Int64HistogramOption{metric.WithDescription(description)}
+		if unit != "" {
+			instOpts = append(instOpts, metric.WithUnit(unit))
+		}
+		var err error
+		histogram, err = meter.Int64Histogram(name, instOpts...)
+		if err != nil {
+			return
+		}
+		int64HistogramCache.Store(cacheKey, histogram)
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("component", component),
+	}
+	if opts != nil && len(opts.Attributes) > 0 {
+		attrs = append(attrs, opts.Attributes...)
+	}
+
+	histogram.Record(ctx, value, metric.WithAttributes(attrs...))
+}
+
+// RegisterObservableGauge registers a Float64 gauge whose value is read by
+// calling callback each time metrics are collected, for values that are
+// cheaper to pull than to push (queue depth, pool size). Call Unregister on
+// the returned registration to stop observing. Every call registers a new
+// callback, so register once per gauge.
+func RegisterObservableGauge(p TracerMeterProvider, name string, callback func(context.Context) float64, opts *MetricOptions) (metric.Registration, error) {
+	if p == nil || !p.IsEnabled() {
+		return noopRegistration{}, nil
+	}
+
+	component := "default"
+	if opts != nil && opts.Component != "" {
+		component = opts.Component
+	}
+
+	meter := p.GetMeter(component)
+	gauge, err := meter.Float64ObservableGauge(
+		name,
+		metric.WithDescription(fmt.Sprintf("Gauge for %s values", name)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("helper: failed to create gauge %s: %w", name, err)
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("component", component),
+	}
+	if opts != nil && len(opts.Attributes) > 0 {
+		attrs = append(attrs, opts.Attributes...)
+	}
+	observeOpt := metric.WithAttributes(attrs...)
+
+	reg, err := meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		o.ObserveFloat64(gauge, callback(ctx), observeOpt)
+		return nil
+	}, gauge)
+	if err != nil {
+		return nil, fmt.Errorf("helper: failed to register gauge %s: %w", name, err)
+	}
+	return reg, nil
+}
+
+// noopRegistration is returned when there is no provider to register with.
+type noopRegistration struct {
+	metric.Registration
+}
+
+func (noopRegistration) Unregister() error { return nil }
@@ -0,0 +1,159 @@
+package helper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+	nooptrace "go.opentelemetry.io/otel/trace/noop"
+)
+
+// testProvider is a TracerMeterProvider backed by an in-memory metric reader.
+type testProvider struct {
+	mp *sdkmetric.MeterProvider
+}
+
+func newTestProvider() (*testProvider, *sdkmetric.ManualReader) {
+	reader := sdkmetric.NewManualReader()
+	return &testProvider{mp: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))}, reader
+}
+
+func (p *testProvider) GetTracer(name string) trace.Tracer {
+	return nooptrace.NewTracerProvider().Tracer(name)
+}
+func (p *testProvider) GetMeter(name string) metric.Meter { return p.mp.Meter(name) }
+func (p *testProvider) IsEnabled() bool                   { return true }
+
+func collectInt64Histogram(t *testing.T, reader *sdkmetric.ManualReader, name string) (metricdata.Metrics, metricdata.HistogramDataPoint[int64]) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			hist, ok := m.Data.(metricdata.Histogram[int64])
+			if !ok || len(hist.DataPoints) == 0 {
+				t.Fatalf("metric %q is not an int64 histogram with data", name)
+			}
+			return m, hist.DataPoints[0]
+		}
+	}
+	t.Fatalf("metric %q not found", name)
+	return metricdata.Metrics{}, metricdata.HistogramDataPoint[int64]{}
+}
+
+func TestRecordDurationMillis_RecordsMillisecondsWithUnit(t *testing.T) {
+	p, reader := newTestProvider()
+
+	RecordDurationMillis(context.Background(), p, "test.millis.duration", 1500*time.Millisecond, nil)
+
+	m, dp := collectInt64Histogram(t, reader, "test.millis.duration")
+	if m.Unit != "ms" {
+		t.Errorf("unit = %q, want %q", m.Unit, "ms")
+	}
+	if dp.Sum != 1500 {
+		t.Errorf("sum = %d, want 1500", dp.Sum)
+	}
+}
+
+func TestRecordDurationMicros_RecordsMicrosecondsWithUnit(t *testing.T) {
+	p, reader := newTestProvider()
+
+	RecordDurationMicros(context.Background(), p, "test.micros.duration", 2*time.Millisecond, &MetricOptions{Component: "db"})
+
+	m, dp := collectInt64Histogram(t, reader, "test.micros.duration")
+	if m.Unit != "us" {
+		t.Errorf("unit = %q, want %q", m.Unit, "us")
+	}
+	if dp.Sum != 2000 {
+		t.Errorf("sum = %d, want 2000", dp.Sum)
+	}
+	if v, ok := dp.Attributes.Value("component"); !ok || v.AsString() != "db" {
+		t.Errorf("component attribute = %v, want db", v.AsString())
+	}
+}
+
+func TestRecordInt64Histogram_NilProviderIsNoop(t *testing.T) {
+	// Must not panic.
+	RecordInt64Histogram(context.Background(), nil, "test.noop", 1, "By", nil)
+}
+
+func TestAddUpDownCounter_TracksInFlightWork(t *testing.T) {
+	p, reader := newTestProvider()
+	ctx := context.Background()
+
+	AddUpDownCounter(ctx, p, "test.updown.in_flight", 1, nil)
+	AddUpDownCounter(ctx, p, "test.updown.in_flight", 1, nil)
+	AddUpDownCounter(ctx, p, "test.updown.in_flight", -1, nil)
+	IncrementFloat64Counter(ctx, p, "test.float.amount", 1.25, nil)
+	IncrementFloat64Counter(ctx, p, "test.float.amount", 0.5, nil)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		switch data := m.Data.(type) {
+		case metricdata.Sum[int64]:
+			if data.IsMonotonic || data.DataPoints[0].Value != 1 {
+				t.Errorf("%s = %d (monotonic %v), want 1 non-monotonic", m.Name, data.DataPoints[0].Value, data.IsMonotonic)
+			}
+		case metricdata.Sum[float64]:
+			if data.DataPoints[0].Value != 1.75 {
+				t.Errorf("%s = %v, want 1.75", m.Name, data.DataPoints[0].Value)
+			}
+		default:
+			t.Errorf("unexpected metric %s", m.Name)
+		}
+	}
+}
+
+func TestRegisterObservableGauge_ObservesOnCollect(t *testing.T) {
+	p, reader := newTestProvider()
+	depth := 3.0
+
+	reg, err := RegisterObservableGauge(p, "test.gauge.queue_depth", func(context.Context) float64 { return depth }, &MetricOptions{Component: "queue"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collect := func() []metricdata.DataPoint[float64] {
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(context.Background(), &rm); err != nil {
+			t.Fatal(err)
+		}
+		if len(rm.ScopeMetrics) == 0 || len(rm.ScopeMetrics[0].Metrics) == 0 {
+			return nil
+		}
+		return rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Gauge[float64]).DataPoints
+	}
+
+	depth = 5
+	if dps := collect(); len(dps) != 1 || dps[0].Value != 5 {
+		t.Errorf("gauge = %v, want 5", dps)
+	}
+
+	if err := reg.Unregister(); err != nil {
+		t.Fatal(err)
+	}
+	if dps := collect(); len(dps) != 0 {
+		t.Errorf("gauge observed after Unregister: %v", dps)
+	}
+}
+
+func TestObserveGauge_WithoutGlobalProvider(t *testing.T) {
+	SetGlobalProvider(nil)
+
+	reg, err := ObserveGauge("test.gauge.noop", func(context.Context) float64 { return 1 }, nil)
+	if err != nil || reg.Unregister() != nil {
+		t.Errorf("ObserveGauge without provider = %v, want a no-op registration", err)
+	}
+}
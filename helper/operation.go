@@ -0,0 +1,110 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Operation is a unit of work measured as a span plus RED metrics (rate,
+// errors, duration), all named after the operation:
+//
+//	<name>.duration      histogram of the duration in seconds
+//	<name>.total         counter of finished operations
+//	<name>.errors.total  counter of failed operations, by error.type
+//
+// The duration and total metrics carry success, so the error rate can be
+// read from either.
+//
+//	op := helper.StartOperation(ctx, "checkout")
+//	err := checkout(op.Context(), cart)
+//	op.End(err)
+type Operation struct {
+	p     TracerMeterProvider // nil when disabled
+	ctx   context.Context
+	span  trace.Span
+	name  string
+	opts  *MetricOptions
+	start time.Time
+	ended atomic.Bool
+}
+
+// StartOperation starts an Operation using the global provider. attrs are
+// set on the span and on the metrics, so keep them low-cardinality.
+func StartOperation(ctx context.Context, name string, attrs ...attribute.KeyValue) *Operation {
+	return StartOperationWith(ctx, GlobalProvider(), name, &SpanOptions{Attributes: attrs})
+}
+
+// StartOperationWith starts an Operation using p. opts.Component sets the
+// meter and tracer scope and the component attribute, as for StartSpan.
+func StartOperationWith(ctx context.Context, p TracerMeterProvider, name string, opts *SpanOptions) *Operation {
+	if p == nil || !p.IsEnabled() {
+		return &Operation{ctx: ctx, span: trace.SpanFromContext(ctx), name: name}
+	}
+
+	metricOpts := &MetricOptions{}
+	if opts != nil {
+		metricOpts.Component = opts.Component
+		metricOpts.Attributes = opts.Attributes
+	}
+
+	ctx, span := StartSpan(ctx, p, name, opts)
+	return &Operation{
+		p:     p,
+		ctx:   ctx,
+		span:  span,
+		name:  name,
+		opts:  metricOpts,
+		start: time.Now(),
+	}
+}
+
+// Context returns the context carrying the operation's span, for the work
+// done as part of it.
+func (o *Operation) Context() context.Context {
+	return o.ctx
+}
+
+// Span returns the operation's span, or the span of the parent context when
+// the provider is disabled.
+func (o *Operation) Span() trace.Span {
+	return o.span
+}
+
+// End ends the span and records the metrics, counting the operation as
+// failed when err is not nil. Only the first call has an effect.
+func (o *Operation) End(err error) {
+	if o.p == nil || !o.ended.CompareAndSwap(false, true) {
+		return
+	}
+	duration := time.Since(o.start)
+
+	o.span.SetAttributes(attribute.Int64("duration_ms", duration.Milliseconds()))
+	if err != nil {
+		o.span.RecordError(err)
+		o.span.SetStatus(codes.Error, err.Error())
+	} else {
+		o.span.SetStatus(codes.Ok, "")
+	}
+	o.span.End()
+
+	success := o.withAttributes(attribute.Bool("success", err == nil))
+	RecordDuration(o.ctx, o.p, o.name+".duration", duration, success)
+	IncrementCounter(o.ctx, o.p, o.name+".total", 1, success)
+	if err != nil {
+		IncrementCounter(o.ctx, o.p, o.name+".errors.total", 1,
+			o.withAttributes(attribute.String("error.type", fmt.Sprintf("%T", err))))
+	}
+}
+
+// withAttributes returns the operation's metric options with attrs added.
+func (o *Operation) withAttributes(attrs ...attribute.KeyValue) *MetricOptions {
+	all := make([]attribute.KeyValue, 0, len(o.opts.Attributes)+len(attrs))
+	all = append(all, o.opts.Attributes...)
+	return &MetricOptions{Component: o.opts.Component, Attributes: append(all, attrs...)}
+}
@@ -0,0 +1,131 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordingProvider is a TracerMeterProvider recording spans and metrics.
+type recordingProvider struct {
+	tp *sdktrace.TracerProvider
+	mp *sdkmetric.MeterProvider
+}
+
+func newRecordingProvider() (*recordingProvider, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	recorder := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	return &recordingProvider{
+		tp: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+		mp: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	}, recorder, reader
+}
+
+func (p *recordingProvider) GetTracer(name string) trace.Tracer { return p.tp.Tracer(name) }
+func (p *recordingProvider) GetMeter(name string) metric.Meter  { return p.mp.Meter(name) }
+func (p *recordingProvider) IsEnabled() bool                    { return true }
+
+func collectMetrics(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+func TestOperation_RecordsSpanAndREDMetrics(t *testing.T) {
+	p, recorder, reader := newRecordingProvider()
+
+	op := StartOperationWith(context.Background(), p, "test.op.checkout", &SpanOptions{
+		Component:  "shop",
+		Attributes: []attribute.KeyValue{attribute.String("region", "eu")},
+	})
+	if !trace.SpanFromContext(op.Context()).SpanContext().IsValid() {
+		t.Fatal("Context() does not carry the operation span")
+	}
+	op.End(&fs.PathError{Op: "open", Path: "cart", Err: fs.ErrNotExist})
+	op.End(nil) // ignored
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "test.op.checkout" || spans[0].Status().Code != codes.Error {
+		t.Fatalf("spans = %v, want one failed test.op.checkout span", spans)
+	}
+
+	metrics := collectMetrics(t, reader)
+	hist, ok := metrics["test.op.checkout.duration"].(metricdata.Histogram[float64])
+	if !ok || len(hist.DataPoints) != 1 || hist.DataPoints[0].Count != 1 {
+		t.Fatalf("test.op.checkout.duration = %#v, want one measurement", metrics["test.op.checkout.duration"])
+	}
+	if v, _ := hist.DataPoints[0].Attributes.Value("region"); v.AsString() != "eu" {
+		t.Errorf("duration region = %q, want eu", v.AsString())
+	}
+	if v, _ := hist.DataPoints[0].Attributes.Value("success"); v.AsBool() {
+		t.Error("duration success = true, want false")
+	}
+
+	total, ok := metrics["test.op.checkout.total"].(metricdata.Sum[int64])
+	if !ok || len(total.DataPoints) != 1 || total.DataPoints[0].Value != 1 {
+		t.Fatalf("test.op.checkout.total = %#v, want 1", metrics["test.op.checkout.total"])
+	}
+
+	errs, ok := metrics["test.op.checkout.errors.total"].(metricdata.Sum[int64])
+	if !ok || len(errs.DataPoints) != 1 || errs.DataPoints[0].Value != 1 {
+		t.Fatalf("test.op.checkout.errors.total = %#v, want 1", metrics["test.op.checkout.errors.total"])
+	}
+	if v, _ := errs.DataPoints[0].Attributes.Value("error.type"); v.AsString() != "*fs.PathError" {
+		t.Errorf("error.type = %q, want *fs.PathError", v.AsString())
+	}
+	if v, _ := errs.DataPoints[0].Attributes.Value("component"); v.AsString() != "shop" {
+		t.Errorf("component = %q, want shop", v.AsString())
+	}
+}
+
+func TestOperation_SuccessRecordsNoErrors(t *testing.T) {
+	p, recorder, reader := newRecordingProvider()
+
+	StartOperationWith(context.Background(), p, "test.op.refund", nil).End(nil)
+
+	if spans := recorder.Ended(); len(spans) != 1 || spans[0].Status().Code != codes.Ok {
+		t.Fatalf("spans = %v, want one ok span", spans)
+	}
+	metrics := collectMetrics(t, reader)
+	if _, ok := metrics["test.op.refund.total"]; !ok {
+		t.Error("test.op.refund.total not recorded")
+	}
+	if _, ok := metrics["test.op.refund.errors.total"]; ok {
+		t.Error("test.op.refund.errors.total recorded for a successful operation")
+	}
+}
+
+func TestStartOperation_WithoutGlobalProvider(t *testing.T) {
+	SetGlobalProvider(nil)
+
+	ctx, parent := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "parent")
+	op := StartOperation(ctx, "test.op.noop")
+	op.End(errors.New("boom"))
+
+	if op.Context() != ctx {
+		t.Error("Context() differs from the parent context without a provider")
+	}
+	if !parent.IsRecording() {
+		t.Error("End ended the parent span")
+	}
+	parent.End()
+}
@@ -0,0 +1,82 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/RodolfoBonis/go-otel-agent/scrub"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PanicsMetric counts recovered panics, by exception.type.
+const PanicsMetric = "panics_total"
+
+// PanicOption configures RecoverAndRecord.
+type PanicOption func(*panicOptions)
+
+type panicOptions struct {
+	repanic bool
+}
+
+// WithRepanic panics again with the original value once the panic is
+// recorded, for callers that want the process (or an outer recovery) to
+// handle it as before.
+func WithRepanic() PanicOption {
+	return func(o *panicOptions) {
+		o.repanic = true
+	}
+}
+
+// RecoverAndRecord recovers a panic and records it with RecordPanic. It must
+// be deferred directly, typically at the top of a goroutine, which would
+// otherwise crash the process without a trace of what it was doing:
+//
+//	go func() {
+//		defer helper.RecoverAndRecord(ctx)
+//		process(ctx, job)
+//	}()
+func RecoverAndRecord(ctx context.Context, opts ...PanicOption) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	var o panicOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	RecordPanic(ctx, recovered, debug.Stack())
+	if o.repanic {
+		panic(recovered)
+	}
+}
+
+// RecordPanic records a recovered panic on the span in ctx as an exception
+// event with exception.stacktrace, marks the span as an error and increments
+// panics_total. The message is scrubbed with the default scrubber. Use it
+// from recovery code of your own; stack is normally debug.Stack() taken in
+// the deferred function.
+func RecordPanic(ctx context.Context, recovered any, stack []byte) {
+	excType := fmt.Sprintf("%T", recovered)
+	message := scrub.String(fmt.Sprint(recovered))
+
+	span := trace.SpanFromContext(ctx)
+	if span.IsRecording() {
+		span.AddEvent("exception", trace.WithAttributes(
+			attribute.String("exception.type", excType),
+			attribute.String("exception.message", message),
+			attribute.String("exception.stacktrace", string(stack)),
+			attribute.Bool("exception.escaped", true),
+		))
+		span.SetStatus(codes.Error, "panic: "+message)
+	}
+
+	Count(ctx, PanicsMetric, 1, &MetricOptions{
+		Component:  "panic",
+		Attributes: []attribute.KeyValue{attribute.String("exception.type", excType)},
+	})
+}
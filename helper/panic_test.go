@@ -0,0 +1,53 @@
+package helper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRecoverAndRecord(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "job")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer RecoverAndRecord(ctx)
+		panic("password=hunter2 rejected")
+	}()
+	<-done
+	span.End()
+
+	ended := recorder.Ended()[0]
+	if ended.Status().Code != codes.Error {
+		t.Errorf("status = %v, want Error", ended.Status().Code)
+	}
+	if len(ended.Events()) != 1 {
+		t.Fatalf("expected one exception event, got %d", len(ended.Events()))
+	}
+	attrs := attribute.NewSet(ended.Events()[0].Attributes...)
+	if v, _ := attrs.Value("exception.message"); strings.Contains(v.AsString(), "hunter2") {
+		t.Errorf("exception.message = %q, want it scrubbed", v.AsString())
+	}
+	if v, _ := attrs.Value("exception.stacktrace"); !strings.Contains(v.AsString(), "TestRecoverAndRecord") {
+		t.Errorf("exception.stacktrace does not include the panicking goroutine:\n%s", v.AsString())
+	}
+}
+
+func TestRecoverAndRecord_WithRepanic(t *testing.T) {
+	defer func() {
+		if got := recover(); got != "boom" {
+			t.Errorf("recovered %v, want the original value", got)
+		}
+	}()
+	func() {
+		defer RecoverAndRecord(context.Background(), WithRepanic())
+		panic("boom")
+	}()
+}
@@ -0,0 +1,57 @@
+package helper
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const slowScopeName = "github.com/RodolfoBonis/go-otel-agent/helper"
+
+// TraceIfSlow runs fn and records it as a child span of the span in ctx only
+// if it took at least threshold. The span is created after fn returns, with
+// the buffered start time, so fast iterations of a tight loop cost a clock
+// read instead of a span while slow ones stay visible in the trace.
+//
+// Because the span does not exist while fn runs, spans started inside fn are
+// children of the span in ctx. Without a recording span in ctx, fn just runs.
+func TraceIfSlow(ctx context.Context, name string, threshold time.Duration, fn func(context.Context) error) error {
+	parent := trace.SpanFromContext(ctx)
+	if !parent.IsRecording() {
+		return fn(ctx)
+	}
+
+	start := time.Now()
+	err := fn(ctx)
+	end := time.Now()
+
+	duration := end.Sub(start)
+	if duration < threshold {
+		return err
+	}
+
+	spanOpts := []trace.SpanStartOption{
+		trace.WithTimestamp(start),
+		trace.WithAttributes(
+			attribute.Int64("duration_ms", duration.Milliseconds()),
+			attribute.Int64("slow.threshold_ms", threshold.Milliseconds()),
+		),
+	}
+	if kind := inferSpanKind(name, ""); kind != trace.SpanKindUnspecified {
+		spanOpts = append(spanOpts, trace.WithSpanKind(kind))
+	}
+
+	_, span := parent.TracerProvider().Tracer(slowScopeName).Start(ctx, name, spanOpts...)
+	if err != nil {
+		span.RecordError(err, trace.WithTimestamp(end))
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End(trace.WithTimestamp(end))
+
+	return err
+}
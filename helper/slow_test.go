@@ -0,0 +1,57 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTraceIfSlow_RecordsOnlySlowCalls(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "batch")
+
+	fast := func(context.Context) error { return nil }
+	slow := func(context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return errors.New("timeout")
+	}
+	for range 100 {
+		_ = TraceIfSlow(ctx, "item", 10*time.Millisecond, fast)
+	}
+	if err := TraceIfSlow(ctx, "item", 10*time.Millisecond, slow); err == nil {
+		t.Error("expected fn's error to be returned")
+	}
+	parent.End()
+
+	ended := recorder.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("recorded %d spans, want the slow item and the parent", len(ended))
+	}
+	item := ended[0]
+	if item.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("slow span is not a child of the span in ctx")
+	}
+	if d := item.EndTime().Sub(item.StartTime()); d < 20*time.Millisecond {
+		t.Errorf("span duration = %v, want the buffered execution time", d)
+	}
+	if item.Status().Code != codes.Error {
+		t.Errorf("status = %v, want error", item.Status().Code)
+	}
+}
+
+func TestTraceIfSlow_RunsWithoutSpan(t *testing.T) {
+	called := false
+	_ = TraceIfSlow(context.Background(), "item", 0, func(context.Context) error {
+		called = true
+		return nil
+	})
+	if !called {
+		t.Error("fn was not called")
+	}
+}
@@ -26,7 +26,9 @@ type SpanOptions struct {
 	Kind       trace.SpanKind
 }
 
-// StartSpan starts a new span with simplified configuration.
+// StartSpan starts a new span with simplified configuration. When opts.Kind
+// is unspecified the kind is inferred from the rules set with
+// SetSpanKindInference, falling back to INTERNAL.
 func StartSpan(ctx context.Context, p TracerMeterProvider, name string, opts *SpanOptions) (context.Context, trace.Span) {
 	if p == nil || !p.IsEnabled() {
 		return ctx, trace.SpanFromContext(ctx)
@@ -40,14 +42,19 @@ func StartSpan(ctx context.Context, p TracerMeterProvider, name string, opts *Sp
 	tracer := p.GetTracer(component)
 	spanOpts := []trace.SpanStartOption{}
 
+	kind := trace.SpanKindUnspecified
 	if opts != nil {
-		if opts.Kind != trace.SpanKindUnspecified {
-			spanOpts = append(spanOpts, trace.WithSpanKind(opts.Kind))
-		}
+		kind = opts.Kind
 		if len(opts.Attributes) > 0 {
 			spanOpts = append(spanOpts, trace.WithAttributes(opts.Attributes...))
 		}
 	}
+	if kind == trace.SpanKindUnspecified {
+		kind = inferSpanKind(name, component)
+	}
+	if kind != trace.SpanKindUnspecified {
+		spanOpts = append(spanOpts, trace.WithSpanKind(kind))
+	}
 
 	ctx, span := tracer.Start(ctx, name, spanOpts...)
 	span.SetAttributes(attribute.String("component", component))
@@ -117,13 +124,28 @@ func SetSpanAttributes(ctx context.Context, attributes ...attribute.KeyValue) {
 	}
 }
 
-// RecordSpanError records an error on the current span.
+// SetSpanAttributesFunc sets the attributes returned by fn on the current
+// span, calling fn only when the span is sampled. Use it for attributes that
+// are expensive to build, such as serialized payloads.
+func SetSpanAttributesFunc(ctx context.Context, fn func() []attribute.KeyValue) {
+	span := trace.SpanFromContext(ctx)
+	if span.IsRecording() && span.SpanContext().IsSampled() {
+		span.SetAttributes(fn()...)
+	}
+}
+
+// RecordSpanError records an error on the current span. With
+// SetErrorStackTraces enabled, the exception event also carries the caller's
+// stack trace and the error's wrap chain.
 func RecordSpanError(ctx context.Context, err error, attributes ...attribute.KeyValue) {
 	if err == nil {
 		return
 	}
 	span := trace.SpanFromContext(ctx)
 	if span.SpanContext().IsValid() {
+		if details := exceptionDetails(err); details != nil {
+			attributes = append(details, attributes...)
+		}
 		span.RecordError(err, trace.WithAttributes(attributes...))
 		span.SetStatus(codes.Error, err.Error())
 	}
@@ -0,0 +1,39 @@
+package helper
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetSpanAttributesFunc_OnlyBuildsForSampledSpans(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		sampler sdktrace.Sampler
+		want    bool
+	}{
+		{"sampled", sdktrace.AlwaysSample(), true},
+		{"record only", recordOnlySampler{}, false},
+		{"dropped", sdktrace.NeverSample(), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(tc.sampler), sdktrace.WithSpanProcessor(recorder))
+			ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+
+			called := false
+			SetSpanAttributesFunc(ctx, func() []attribute.KeyValue {
+				called = true
+				return []attribute.KeyValue{attribute.String("payload", "{...}")}
+			})
+			span.End()
+
+			if called != tc.want {
+				t.Errorf("fn called = %v, want %v", called, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const helperPackage = "github.com/RodolfoBonis/go-otel-agent/helper."
+
+// Defaults for SetErrorStackTraces.
+const (
+	DefaultMaxStackFrames = 32
+	DefaultMaxErrorChain  = 5
+)
+
+// StackTraceOption configures the stack traces RecordSpanError attaches.
+type StackTraceOption func(*stackTraceConfig)
+
+type stackTraceConfig struct {
+	maxFrames int
+	maxChain  int
+}
+
+var activeStackTraces atomic.Pointer[stackTraceConfig]
+
+// WithMaxStackFrames limits exception.stacktrace to the innermost n frames
+// of the caller; the rest are elided.
+func WithMaxStackFrames(n int) StackTraceOption {
+	return func(c *stackTraceConfig) {
+		c.maxFrames = n
+	}
+}
+
+// WithMaxErrorChain limits how many wrapped errors (errors.Unwrap) are
+// recorded as exception.cause.<n>.type and exception.cause.<n>.message.
+// Zero records none.
+func WithMaxErrorChain(n int) StackTraceOption {
+	return func(c *stackTraceConfig) {
+		c.maxChain = n
+	}
+}
+
+// SetErrorStackTraces makes RecordSpanError (and Error) attach the caller's
+// stack trace to the exception event as exception.stacktrace, and the wrap
+// chain of the error as numbered exception.cause attributes. Stack traces
+// are off by default; calling it with enabled false turns them off again.
+// It is safe to call while errors are being recorded.
+func SetErrorStackTraces(enabled bool, opts ...StackTraceOption) {
+	if !enabled {
+		activeStackTraces.Store(nil)
+		return
+	}
+	c := &stackTraceConfig{maxFrames: DefaultMaxStackFrames, maxChain: DefaultMaxErrorChain}
+	for _, opt := range opts {
+		opt(c)
+	}
+	activeStackTraces.Store(c)
+}
+
+// exceptionDetails returns the stack trace and cause attributes for err, or
+// nil when stack traces are off.
+func exceptionDetails(err error) []attribute.KeyValue {
+	c := activeStackTraces.Load()
+	if c == nil {
+		return nil
+	}
+	attrs := []attribute.KeyValue{attribute.String("exception.stacktrace", callerStack(c.maxFrames))}
+	return append(attrs, errorChain(err, c.maxChain)...)
+}
+
+// callerStack formats up to maxFrames frames of the stack, starting at the
+// first caller outside the helper package, like debug.Stack does.
+func callerStack(maxFrames int) string {
+	pcs := make([]uintptr, maxFrames+16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	var b strings.Builder
+	n := 0
+	for {
+		f, more := frames.Next()
+		inHelper := strings.HasPrefix(f.Function, helperPackage) && !strings.HasSuffix(f.File, "_test.go")
+		if n > 0 || !inHelper {
+			if n == maxFrames {
+				b.WriteString("...\n")
+				break
+			}
+			fmt.Fprintf(&b, "%s(...)\n\t%s:%d\n", f.Function, f.File, f.Line)
+			n++
+		}
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// errorChain records the errors wrapped by err, outermost first, as
+// exception.cause.1.type, exception.cause.1.message, and so on.
+func errorChain(err error, maxDepth int) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for depth := 1; depth <= maxDepth; depth++ {
+		err = errors.Unwrap(err)
+		if err == nil {
+			break
+		}
+		prefix := "exception.cause." + strconv.Itoa(depth) + "."
+		attrs = append(attrs,
+			attribute.String(prefix+"type", fmt.Sprintf("%T", err)),
+			attribute.String(prefix+"message", err.Error()),
+		)
+	}
+	return attrs
+}
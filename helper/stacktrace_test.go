@@ -0,0 +1,80 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var errConnRefused = errors.New("connection refused")
+
+func recordedException(t *testing.T, err error) attribute.Set {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "op")
+	Error(ctx, err, attribute.String("order.id", "42"))
+	span.End()
+	return attribute.NewSet(recorder.Ended()[0].Events()[0].Attributes...)
+}
+
+func TestRecordSpanError_StackTracesOffByDefault(t *testing.T) {
+	attrs := recordedException(t, errConnRefused)
+	if _, ok := attrs.Value("exception.stacktrace"); ok {
+		t.Error("exception.stacktrace recorded without SetErrorStackTraces")
+	}
+}
+
+func TestRecordSpanError_AttachesStackAndChain(t *testing.T) {
+	SetErrorStackTraces(true)
+	defer SetErrorStackTraces(false)
+
+	err := fmt.Errorf("charging order: %w", fmt.Errorf("calling gateway: %w", errConnRefused))
+	attrs := recordedException(t, err)
+
+	stack, _ := attrs.Value("exception.stacktrace")
+	lines := strings.Split(stack.AsString(), "\n")
+	if !strings.Contains(lines[0], "recordedException") {
+		t.Errorf("stack starts at %q, want the caller of Error", lines[0])
+	}
+	for key, want := range map[string]string{
+		"exception.cause.1.message": "calling gateway: connection refused",
+		"exception.cause.2.message": "connection refused",
+		"exception.cause.2.type":    "*errors.errorString",
+		"order.id":                  "42",
+	} {
+		if v, _ := attrs.Value(attribute.Key(key)); v.AsString() != want {
+			t.Errorf("%s = %q, want %q", key, v.AsString(), want)
+		}
+	}
+	if _, ok := attrs.Value("exception.cause.3.message"); ok {
+		t.Error("recorded a cause past the end of the chain")
+	}
+}
+
+func TestRecordSpanError_LimitsFramesAndChain(t *testing.T) {
+	SetErrorStackTraces(true, WithMaxStackFrames(1), WithMaxErrorChain(1))
+	defer SetErrorStackTraces(false)
+
+	err := fmt.Errorf("a: %w", fmt.Errorf("b: %w", errConnRefused))
+	attrs := recordedException(t, err)
+
+	stack, _ := attrs.Value("exception.stacktrace")
+	if got := strings.Count(stack.AsString(), "\t"); got != 1 {
+		t.Errorf("stack has %d frames, want 1:\n%s", got, stack.AsString())
+	}
+	if !strings.HasSuffix(stack.AsString(), "...\n") {
+		t.Errorf("truncated stack is not marked:\n%s", stack.AsString())
+	}
+	if _, ok := attrs.Value("exception.cause.1.message"); !ok {
+		t.Error("first cause missing")
+	}
+	if _, ok := attrs.Value("exception.cause.2.message"); ok {
+		t.Error("cause recorded past the chain limit")
+	}
+}
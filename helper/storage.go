@@ -0,0 +1,126 @@
+package helper
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Transfer directions for TraceTransfer and TraceCopy, recorded as
+// storage.operation.
+const (
+	TransferRead  = "read"
+	TransferWrite = "write"
+)
+
+// TransferSizeMetric is the histogram of bytes moved per traced transfer.
+const TransferSizeMetric = "storage.transfer.size"
+
+// TraceTransfer runs fn, which moves data and returns the byte count, in a
+// span (component "storage") that records storage.operation,
+// storage.bytes, the throughput in storage.throughput (bytes per second) and
+// duration_ms, and marks the span as an error when fn fails. The byte count
+// also goes to the storage.transfer.size histogram, so the I/O phases of
+// ETL-style jobs show up next to their CPU work.
+//
+// Wrap transfers that report their own size, like an S3 upload whose body
+// is a CountingReader:
+//
+//	body := helper.NewCountingReader(f)
+//	_, err := helper.TraceTransfer(ctx, "s3 upload", helper.TransferWrite, func(ctx context.Context) (int64, error) {
+//		_, err := uploader.Upload(ctx, &s3.PutObjectInput{Bucket: &bucket, Key: &key, Body: body})
+//		return body.N(), err
+//	}, attribute.String("aws.s3.bucket", bucket))
+func TraceTransfer(ctx context.Context, name, direction string, fn func(context.Context) (int64, error), attrs ...attribute.KeyValue) (int64, error) {
+	opAttr := attribute.String("storage.operation", direction)
+	ctx, span := Trace(ctx, name, &SpanOptions{
+		Component:  "storage",
+		Attributes: append([]attribute.KeyValue{opAttr}, attrs...),
+	})
+	defer span.End()
+
+	start := time.Now()
+	n, err := fn(ctx)
+	duration := time.Since(start)
+
+	span.SetAttributes(
+		attribute.Int64("storage.bytes", n),
+		attribute.Int64("duration_ms", duration.Milliseconds()),
+	)
+	if seconds := duration.Seconds(); seconds > 0 {
+		span.SetAttributes(attribute.Float64("storage.throughput", float64(n)/seconds))
+	}
+	if p := GlobalProvider(); p != nil {
+		RecordInt64Histogram(ctx, p, TransferSizeMetric, n, "By", &MetricOptions{
+			Component:  "storage",
+			Attributes: []attribute.KeyValue{opAttr, attribute.Bool("error", err != nil)},
+		})
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	return n, err
+}
+
+// TraceCopy is io.Copy traced with TraceTransfer.
+func TraceCopy(ctx context.Context, name, direction string, dst io.Writer, src io.Reader, attrs ...attribute.KeyValue) (int64, error) {
+	return TraceTransfer(ctx, name, direction, func(context.Context) (int64, error) {
+		return io.Copy(dst, src)
+	}, attrs...)
+}
+
+// TraceReadFile is os.ReadFile traced with TraceTransfer, in a
+// "file read" span carrying file.path.
+func TraceReadFile(ctx context.Context, path string) ([]byte, error) {
+	var data []byte
+	_, err := TraceTransfer(ctx, "file read", TransferRead, func(context.Context) (int64, error) {
+		var err error
+		data, err = os.ReadFile(path)
+		return int64(len(data)), err
+	}, attribute.String("file.path", path))
+	return data, err
+}
+
+// TraceWriteFile is os.WriteFile traced with TraceTransfer, in a
+// "file write" span carrying file.path. The recorded size is len(data) even
+// when the write fails part way.
+func TraceWriteFile(ctx context.Context, path string, data []byte, perm os.FileMode) error {
+	_, err := TraceTransfer(ctx, "file write", TransferWrite, func(context.Context) (int64, error) {
+		return int64(len(data)), os.WriteFile(path, data, perm)
+	}, attribute.String("file.path", path))
+	return err
+}
+
+// CountingReader counts the bytes read through it, for transfers that
+// consume a reader without reporting how much they read. It is safe to call
+// N while another goroutine reads.
+type CountingReader struct {
+	r io.Reader
+	n atomic.Int64
+}
+
+// NewCountingReader wraps r.
+func NewCountingReader(r io.Reader) *CountingReader {
+	return &CountingReader{r: r}
+}
+
+// Read implements io.Reader.
+func (c *CountingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// N returns the bytes read so far.
+func (c *CountingReader) N() int64 {
+	return c.n.Load()
+}
@@ -0,0 +1,96 @@
+package helper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func recordStorageSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	SetGlobalProvider(tracingProvider{sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))})
+	t.Cleanup(func() { SetGlobalProvider(nil) })
+	return recorder
+}
+
+func TestTraceFile_RecordsBytesAndPath(t *testing.T) {
+	recorder := recordStorageSpans(t)
+	path := filepath.Join(t.TempDir(), "export.csv")
+	data := []byte(strings.Repeat("id,amount\n", 100))
+
+	if err := TraceWriteFile(context.Background(), path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := TraceReadFile(context.Background(), path)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("TraceReadFile = %d bytes, %v", len(got), err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	for i, want := range []string{TransferWrite, TransferRead} {
+		attrs := attribute.NewSet(spans[i].Attributes()...)
+		if v, _ := attrs.Value("storage.operation"); v.AsString() != want {
+			t.Errorf("span %q storage.operation = %q, want %q", spans[i].Name(), v.AsString(), want)
+		}
+		if v, _ := attrs.Value("storage.bytes"); v.AsInt64() != int64(len(data)) {
+			t.Errorf("span %q storage.bytes = %d, want %d", spans[i].Name(), v.AsInt64(), len(data))
+		}
+		if v, _ := attrs.Value("file.path"); v.AsString() != path {
+			t.Errorf("span %q file.path = %q", spans[i].Name(), v.AsString())
+		}
+	}
+}
+
+func TestTraceCopy_CountsPartialTransferOnError(t *testing.T) {
+	recorder := recordStorageSpans(t)
+	broken := errors.New("connection reset")
+
+	src := NewCountingReader(&failingReader{data: []byte("partial"), err: broken})
+	var dst bytes.Buffer
+	n, err := TraceCopy(context.Background(), "s3 download", TransferRead, &dst, src)
+	if !errors.Is(err, broken) || n != 7 || src.N() != 7 {
+		t.Fatalf("TraceCopy = %d, %v; counted %d", n, err, src.N())
+	}
+
+	span := recorder.Ended()[0]
+	if span.Status().Code != codes.Error {
+		t.Errorf("status = %v, want Error", span.Status().Code)
+	}
+	attrs := attribute.NewSet(span.Attributes()...)
+	if v, _ := attrs.Value("storage.bytes"); v.AsInt64() != 7 {
+		t.Errorf("storage.bytes = %d, want 7", v.AsInt64())
+	}
+}
+
+func TestTraceReadFile_WithoutProvider(t *testing.T) {
+	if _, err := TraceReadFile(context.Background(), filepath.Join(t.TempDir(), "missing")); !os.IsNotExist(err) {
+		t.Errorf("err = %v, want not exist", err)
+	}
+}
+
+type failingReader struct {
+	data []byte
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
@@ -0,0 +1,37 @@
+package helper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// StartTimer starts timing a long operation using the global provider. While
+// it runs the <name>.active up-down counter is raised by one; the returned
+// stop function lowers it again and records the elapsed time on the
+// <name>.duration histogram, in seconds. Only the first call to stop has an
+// effect.
+//
+//	stop := helper.StartTimer(ctx, "db.migration", nil)
+//	defer stop()
+func StartTimer(ctx context.Context, name string, opts *MetricOptions) func() {
+	return StartTimerWith(ctx, GlobalProvider(), name, opts)
+}
+
+// StartTimerWith is StartTimer using p.
+func StartTimerWith(ctx context.Context, p TracerMeterProvider, name string, opts *MetricOptions) func() {
+	if p == nil || !p.IsEnabled() {
+		return func() {}
+	}
+
+	AddUpDownCounter(ctx, p, name+".active", 1, opts)
+	start := time.Now()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			RecordDuration(ctx, p, name+".duration", time.Since(start), opts)
+			AddUpDownCounter(ctx, p, name+".active", -1, opts)
+		})
+	}
+}
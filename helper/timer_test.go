@@ -0,0 +1,52 @@
+package helper
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestStartTimer_TracksActiveAndDuration(t *testing.T) {
+	p, reader := newTestProvider()
+	ctx := context.Background()
+
+	collect := func() map[string]metricdata.Aggregation {
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(ctx, &rm); err != nil {
+			t.Fatal(err)
+		}
+		metrics := map[string]metricdata.Aggregation{}
+		for _, m := range rm.ScopeMetrics[0].Metrics {
+			metrics[m.Name] = m.Data
+		}
+		return metrics
+	}
+
+	stop := StartTimerWith(ctx, p, "test.timer.migration", &MetricOptions{Component: "db"})
+
+	running := collect()
+	if active := running["test.timer.migration.active"].(metricdata.Sum[int64]); active.DataPoints[0].Value != 1 {
+		t.Errorf("active while running = %d, want 1", active.DataPoints[0].Value)
+	}
+	if _, ok := running["test.timer.migration.duration"]; ok {
+		t.Error("duration recorded before stop")
+	}
+
+	stop()
+	stop() // ignored
+
+	stopped := collect()
+	if active := stopped["test.timer.migration.active"].(metricdata.Sum[int64]); active.DataPoints[0].Value != 0 {
+		t.Errorf("active after stop = %d, want 0", active.DataPoints[0].Value)
+	}
+	hist, ok := stopped["test.timer.migration.duration"].(metricdata.Histogram[float64])
+	if !ok || hist.DataPoints[0].Count != 1 {
+		t.Errorf("duration = %#v, want one measurement", stopped["test.timer.migration.duration"])
+	}
+}
+
+func TestStartTimer_WithoutGlobalProvider(t *testing.T) {
+	SetGlobalProvider(nil)
+	StartTimer(context.Background(), "test.timer.noop", nil)() // must not panic
+}
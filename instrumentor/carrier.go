@@ -0,0 +1,73 @@
+package instrumentor
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// NewMapCarrier adapts a map[string]any (AMQP tables, decoded JSON payloads,
+// Redis pub/sub envelopes) for propagation. Values are written as strings;
+// string and []byte values are read back. For map[string]string use
+// propagation.MapCarrier.
+func NewMapCarrier(m map[string]any) propagation.TextMapCarrier {
+	return mapCarrier(m)
+}
+
+type mapCarrier map[string]any
+
+func (c mapCarrier) Get(key string) string {
+	switch v := c[key].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return ""
+}
+
+func (c mapCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c mapCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// NewHeaderCarrier adapts a multi-valued, case-insensitive header map such
+// as gRPC metadata.MD. Keys are written lowercased, as gRPC requires, and
+// read case-insensitively; Get returns the first value. For http.Header use
+// propagation.HeaderCarrier.
+func NewHeaderCarrier(h map[string][]string) propagation.TextMapCarrier {
+	return headerCarrier(h)
+}
+
+type headerCarrier map[string][]string
+
+func (c headerCarrier) Get(key string) string {
+	if v := c[strings.ToLower(key)]; len(v) > 0 {
+		return v[0]
+	}
+	for k, v := range c {
+		if len(v) > 0 && strings.EqualFold(k, key) {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c[strings.ToLower(key)] = []string{value}
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
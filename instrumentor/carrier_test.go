@@ -0,0 +1,50 @@
+package instrumentor_test
+
+import (
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/instrumentor"
+	"github.com/RodolfoBonis/go-otel-agent/instrumentor/carriertest"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestMapCarrier_Conformance(t *testing.T) {
+	carriertest.Run(t, func() propagation.TextMapCarrier {
+		return instrumentor.NewMapCarrier(map[string]any{})
+	})
+}
+
+func TestMapCarrier_ReadsBytesAndIgnoresOtherTypes(t *testing.T) {
+	c := instrumentor.NewMapCarrier(map[string]any{
+		"traceparent": []byte("00-abc"),
+		"retries":     3,
+	})
+	if got := c.Get("traceparent"); got != "00-abc" {
+		t.Errorf("Get([]byte) = %q, want %q", got, "00-abc")
+	}
+	if got := c.Get("retries"); got != "" {
+		t.Errorf("Get(int) = %q, want empty", got)
+	}
+}
+
+func TestHeaderCarrier_Conformance(t *testing.T) {
+	carriertest.Run(t, func() propagation.TextMapCarrier {
+		return instrumentor.NewHeaderCarrier(map[string][]string{})
+	})
+}
+
+func TestHeaderCarrier_LowercasesKeysAndReadsCaseInsensitively(t *testing.T) {
+	md := map[string][]string{"X-Tenant": {"acme", "other"}}
+	c := instrumentor.NewHeaderCarrier(md)
+
+	c.Set("Traceparent", "00-abc")
+	if _, ok := md["traceparent"]; !ok {
+		t.Errorf("Set should store lowercased key, got %v", md)
+	}
+	if got := c.Get("TRACEPARENT"); got != "00-abc" {
+		t.Errorf("Get = %q, want %q", got, "00-abc")
+	}
+	if got := c.Get("x-tenant"); got != "acme" {
+		t.Errorf("Get = %q, want first value %q", got, "acme")
+	}
+}
@@ -0,0 +1,97 @@
+// Package carriertest checks that a propagation.TextMapCarrier for a custom
+// transport behaves the way OpenTelemetry propagators expect, so a carrier
+// for a bespoke transport can be verified with a single call:
+//
+//	func TestCarrier(t *testing.T) {
+//		carriertest.Run(t, func() propagation.TextMapCarrier {
+//			return newEnvelopeCarrier(&Envelope{})
+//		})
+//	}
+package carriertest
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Run runs the conformance suite as subtests. newCarrier must return a
+// carrier over fresh, empty storage on every call.
+func Run(t *testing.T, newCarrier func() propagation.TextMapCarrier) {
+	t.Helper()
+
+	t.Run("MissingKeyIsEmpty", func(t *testing.T) {
+		c := newCarrier()
+		if got := c.Get("traceparent"); got != "" {
+			t.Errorf("Get on empty carrier = %q, want empty", got)
+		}
+		if keys := c.Keys(); len(keys) != 0 {
+			t.Errorf("Keys on empty carrier = %v, want none", keys)
+		}
+	})
+
+	t.Run("SetOverwrites", func(t *testing.T) {
+		c := newCarrier()
+		c.Set("traceparent", "first")
+		c.Set("traceparent", "second")
+		if got := c.Get("traceparent"); got != "second" {
+			t.Errorf("Get after overwrite = %q, want %q", got, "second")
+		}
+		if n := count(c.Keys(), "traceparent"); n != 1 {
+			t.Errorf("Keys lists traceparent %d times, want once", n)
+		}
+	})
+
+	t.Run("KeysListsSetKeys", func(t *testing.T) {
+		c := newCarrier()
+		c.Set("traceparent", "a")
+		c.Set("baggage", "b")
+		for _, k := range []string{"traceparent", "baggage"} {
+			if count(c.Keys(), k) == 0 {
+				t.Errorf("Keys = %v, missing %q", c.Keys(), k)
+			}
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		prop := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+		sc := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+			SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+			TraceFlags: trace.FlagsSampled,
+		})
+		member, _ := baggage.NewMember("tenant", "acme")
+		bag, _ := baggage.New(member)
+		ctx := baggage.ContextWithBaggage(trace.ContextWithSpanContext(context.Background(), sc), bag)
+
+		c := newCarrier()
+		prop.Inject(ctx, c)
+		out := prop.Extract(context.Background(), c)
+
+		got := trace.SpanContextFromContext(out)
+		if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() || !got.IsSampled() {
+			t.Errorf("extracted span context %v, want %v", got, sc)
+		}
+		if !got.IsRemote() {
+			t.Error("extracted span context should be remote")
+		}
+		if v := baggage.FromContext(out).Member("tenant").Value(); v != "acme" {
+			t.Errorf("extracted baggage tenant = %q, want %q", v, "acme")
+		}
+	})
+}
+
+func count(keys []string, key string) int {
+	n := 0
+	for k := range slices.Values(keys) {
+		if k == key {
+			n++
+		}
+	}
+	return n
+}
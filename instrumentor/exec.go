@@ -0,0 +1,101 @@
+package instrumentor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Trace context crosses process boundaries as environment variables named
+// after the propagation fields, uppercased with non-alphanumerics replaced by
+// underscores: TRACEPARENT, TRACESTATE, BAGGAGE.
+
+// CommandContext is exec.CommandContext with the current trace context added
+// to the child's environment, so a child using the agent can continue the
+// trace via ContextFromEnvironment.
+func CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	InjectCommand(ctx, cmd)
+	return cmd
+}
+
+// InjectCommand adds the trace context in ctx to cmd.Env. A nil cmd.Env is
+// treated as the current process environment, as exec.Cmd does.
+func InjectCommand(ctx context.Context, cmd *exec.Cmd) {
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = InjectEnv(ctx, env)
+}
+
+// InjectEnv returns env ("KEY=value" entries) with the trace context in ctx
+// set, replacing any propagation variables already present.
+func InjectEnv(ctx context.Context, env []string) []string {
+	carrier := envCarrier{}
+	InjectContext(ctx, carrier)
+	if len(carrier) == 0 {
+		return env
+	}
+
+	out := make([]string, 0, len(env)+len(carrier))
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		if _, replaced := carrier[name]; !replaced {
+			out = append(out, kv)
+		}
+	}
+	for name, value := range carrier {
+		out = append(out, name+"="+value)
+	}
+	return out
+}
+
+// ExtractEnv returns ctx with the trace context found in env ("KEY=value"
+// entries).
+func ExtractEnv(ctx context.Context, env []string) context.Context {
+	carrier := make(envCarrier, len(env))
+	for _, kv := range env {
+		if name, value, ok := strings.Cut(kv, "="); ok {
+			carrier[name] = value
+		}
+	}
+	return ExtractContext(ctx, carrier)
+}
+
+// ContextFromEnvironment returns ctx with the trace context the parent
+// process passed through CommandContext, InjectCommand or InjectEnv. Call it
+// in the child after agent.Init, which installs the propagators, and start
+// the child's root span from the returned context.
+func ContextFromEnvironment(ctx context.Context) context.Context {
+	return ExtractEnv(ctx, os.Environ())
+}
+
+// envCarrier maps propagation fields to environment variable names.
+type envCarrier map[string]string
+
+func envName(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
+
+func (c envCarrier) Get(key string) string { return c[envName(key)] }
+
+func (c envCarrier) Set(key, value string) { c[envName(key)] = value }
+
+func (c envCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
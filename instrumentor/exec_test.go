@@ -0,0 +1,94 @@
+package instrumentor_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/instrumentor"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func useW3CPropagators(t *testing.T) {
+	t.Helper()
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+}
+
+func parentContext() (context.Context, trace.SpanContext) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	member, _ := baggage.NewMember("tenant", "acme")
+	bag, _ := baggage.New(member)
+	return baggage.ContextWithBaggage(trace.ContextWithSpanContext(context.Background(), sc), bag), sc
+}
+
+func TestInjectEnv_RoundTrip(t *testing.T) {
+	useW3CPropagators(t)
+	ctx, sc := parentContext()
+
+	env := instrumentor.InjectEnv(ctx, []string{"PATH=/usr/bin", "TRACEPARENT=stale"})
+
+	if !slices.Contains(env, "PATH=/usr/bin") {
+		t.Errorf("existing variables should be kept: %v", env)
+	}
+	if slices.Contains(env, "TRACEPARENT=stale") {
+		t.Errorf("stale TRACEPARENT should be replaced: %v", env)
+	}
+	if !slices.ContainsFunc(env, func(kv string) bool { return strings.HasPrefix(kv, "BAGGAGE=") }) {
+		t.Errorf("expected BAGGAGE variable: %v", env)
+	}
+
+	out := instrumentor.ExtractEnv(context.Background(), env)
+	if got := trace.SpanContextFromContext(out); got.TraceID() != sc.TraceID() || !got.IsRemote() {
+		t.Errorf("extracted %v, want remote parent %v", got, sc)
+	}
+	if v := baggage.FromContext(out).Member("tenant").Value(); v != "acme" {
+		t.Errorf("baggage tenant = %q, want acme", v)
+	}
+}
+
+func TestInjectEnv_NoSpanLeavesEnvUnchanged(t *testing.T) {
+	useW3CPropagators(t)
+	env := []string{"PATH=/usr/bin"}
+	if got := instrumentor.InjectEnv(context.Background(), env); !slices.Equal(got, env) {
+		t.Errorf("InjectEnv = %v, want %v", got, env)
+	}
+}
+
+// TestCommandContext_ChildContinuesTrace re-runs the test binary as a child
+// process that reports the trace ID it extracts from its environment.
+func TestCommandContext_ChildContinuesTrace(t *testing.T) {
+	useW3CPropagators(t)
+	ctx, sc := parentContext()
+
+	cmd := instrumentor.CommandContext(ctx, os.Args[0], "-test.run=^TestHelperChildProcess$")
+	cmd.Env = append(cmd.Env, "GO_OTEL_AGENT_HELPER_CHILD=1")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("child failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); !strings.HasPrefix(got, sc.TraceID().String()) {
+		t.Errorf("child saw trace %q, want %s", got, sc.TraceID())
+	}
+}
+
+func TestHelperChildProcess(t *testing.T) {
+	if os.Getenv("GO_OTEL_AGENT_HELPER_CHILD") != "1" {
+		t.Skip("helper process for TestCommandContext_ChildContinuesTrace")
+	}
+	useW3CPropagators(t)
+	ctx := instrumentor.ContextFromEnvironment(context.Background())
+	fmt.Println(trace.SpanContextFromContext(ctx).TraceID())
+	os.Exit(0)
+}
@@ -38,6 +38,10 @@ func (i *Instrumentor) StartSpan(ctx context.Context, name string, opts ...trace
 }
 
 // TraceFunction automatically instruments a function with tracing.
+//
+// Deprecated: TraceFunction calls fn through reflection, so argument and
+// result types are only checked at run time. Use TraceFunc0, TraceFunc1 or
+// TraceFunc2, which keep fn's signature.
 func (i *Instrumentor) TraceFunction(ctx context.Context, fn interface{}, args ...interface{}) ([]interface{}, error) {
 	if !i.enabled {
 		return callFunction(fn, args...)
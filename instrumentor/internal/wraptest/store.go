@@ -0,0 +1,26 @@
+// Package wraptest declares interfaces wrapped by cmd/otel-wrap, to test the
+// generated code.
+package wraptest
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate go run ../../../cmd/otel-wrap -type Store
+
+// Store is a key-value store covering the signatures otel-wrap handles.
+type Store interface {
+	Flusher
+
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Keys(prefix string, limit ...int) []string
+	Stats() (hits, misses int)
+	Close()
+}
+
+// Flusher is embedded in Store.
+type Flusher interface {
+	Flush(context.Context) error
+}
@@ -0,0 +1,100 @@
+package wraptest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/instrumentor"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type recordingProvider struct {
+	tp *sdktrace.TracerProvider
+	mp *sdkmetric.MeterProvider
+}
+
+func (p recordingProvider) GetTracer(name string) trace.Tracer { return p.tp.Tracer(name) }
+func (p recordingProvider) GetMeter(name string) metric.Meter  { return p.mp.Meter(name) }
+func (p recordingProvider) IsEnabled() bool                    { return true }
+
+var errMissing = errors.New("missing")
+
+// memStore checks that the traced wrapper passes its span on.
+type memStore struct{ data map[string][]byte }
+
+func (s *memStore) Flush(context.Context) error { return nil }
+func (s *memStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		return nil, errors.New("no span in context")
+	}
+	v, ok := s.data[key]
+	if !ok {
+		return nil, errMissing
+	}
+	return v, nil
+}
+func (s *memStore) Put(_ context.Context, key string, value []byte, _ time.Duration) error {
+	s.data[key] = value
+	return nil
+}
+func (s *memStore) Keys(string, ...int) []string { return nil }
+func (s *memStore) Stats() (int, int)            { return 1, 2 }
+func (s *memStore) Close()                       {}
+
+func TestNewTracedStore(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	inst := instrumentor.New(recordingProvider{
+		tp: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+		mp: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+	store := NewTracedStore(&memStore{data: map[string][]byte{}}, inst)
+
+	ctx := context.Background()
+	if err := store.Put(ctx, "k", []byte("v"), time.Minute); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if v, err := store.Get(ctx, "k"); err != nil || string(v) != "v" {
+		t.Fatalf("Get = %q, %v", v, err)
+	}
+	if _, err := store.Get(ctx, "absent"); !errors.Is(err, errMissing) {
+		t.Fatalf("Get(absent) error = %v, want errMissing", err)
+	}
+	if hits, misses := store.Stats(); hits != 1 || misses != 2 {
+		t.Errorf("Stats = %d, %d", hits, misses)
+	}
+
+	spans := recorder.Ended()
+	var names []string
+	for _, s := range spans {
+		names = append(names, s.Name())
+	}
+	if len(spans) != 4 || names[0] != "Store.Put" || names[1] != "Store.Get" || names[3] != "Store.Stats" {
+		t.Fatalf("spans = %v, want Store.Put, Store.Get, Store.Get, Store.Stats", names)
+	}
+	if spans[1].Status().Code != codes.Unset || spans[2].Status().Code != codes.Error {
+		t.Errorf("Get statuses = %v, %v, want unset then error", spans[1].Status(), spans[2].Status())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	calls := map[string]uint64{}
+	for _, dp := range rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[float64]).DataPoints {
+		method, _ := dp.Attributes.Value("method")
+		success, _ := dp.Attributes.Value("success")
+		calls[method.AsString()+"/"+success.Emit()] += dp.Count
+	}
+	if calls["Get/true"] != 1 || calls["Get/false"] != 1 || calls["Put/true"] != 1 {
+		t.Errorf("%s calls = %v", instrumentor.MethodDurationMetric, calls)
+	}
+}
@@ -0,0 +1,58 @@
+// Code generated by otel-wrap. DO NOT EDIT.
+
+package wraptest
+
+import (
+	"context"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/instrumentor"
+)
+
+// tracedStore is a Store whose method calls are traced and measured.
+type tracedStore struct {
+	next   Store
+	tracer *instrumentor.MethodTracer
+}
+
+// NewTracedStore returns next with every method call traced as a
+// "Store.<Method>" span and recorded in instrumentor.MethodDurationMetric.
+func NewTracedStore(next Store, inst *instrumentor.Instrumentor) Store {
+	return &tracedStore{next: next, tracer: instrumentor.NewMethodTracer(inst, "Store")}
+}
+
+func (w *tracedStore) Close() {
+	_, end := w.tracer.Start(context.Background(), "Close")
+	defer end(nil)
+	w.next.Close()
+}
+
+func (w *tracedStore) Flush(ctx context.Context) (err error) {
+	ctx, end := w.tracer.Start(ctx, "Flush")
+	defer func() { end(err) }()
+	return w.next.Flush(ctx)
+}
+
+func (w *tracedStore) Get(ctx context.Context, key string) (_ []byte, err error) {
+	ctx, end := w.tracer.Start(ctx, "Get")
+	defer func() { end(err) }()
+	return w.next.Get(ctx, key)
+}
+
+func (w *tracedStore) Keys(prefix string, limit ...int) []string {
+	_, end := w.tracer.Start(context.Background(), "Keys")
+	defer end(nil)
+	return w.next.Keys(prefix, limit...)
+}
+
+func (w *tracedStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) (err error) {
+	ctx, end := w.tracer.Start(ctx, "Put")
+	defer func() { end(err) }()
+	return w.next.Put(ctx, key, value, ttl)
+}
+
+func (w *tracedStore) Stats() (int, int) {
+	_, end := w.tracer.Start(context.Background(), "Stats")
+	defer end(nil)
+	return w.next.Stats()
+}
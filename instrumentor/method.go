@@ -0,0 +1,60 @@
+package instrumentor
+
+import (
+	"context"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/helper"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MethodDurationMetric is the histogram, in seconds, in which MethodTracer
+// records every call, with the type, method and success attributes.
+const MethodDurationMetric = "method.duration"
+
+// MethodTracer traces and measures the method calls of one wrapped type.
+// The wrappers generated by cmd/otel-wrap use it, and so can hand-written
+// ones:
+//
+//	func (r *tracedRepo) Load(ctx context.Context, id string) (_ *Order, err error) {
+//		ctx, end := r.tracer.Start(ctx, "Load")
+//		defer func() { end(err) }()
+//		return r.next.Load(ctx, id)
+//	}
+type MethodTracer struct {
+	i        *Instrumentor
+	typeName string
+}
+
+// NewMethodTracer returns a MethodTracer for the methods of typeName.
+func NewMethodTracer(i *Instrumentor, typeName string) *MethodTracer {
+	return &MethodTracer{i: i, typeName: typeName}
+}
+
+// Start starts the span "Type.Method" and returns the context to pass on
+// and the function to call with the method's error when it returns, which
+// ends the span, with an error status when err is not nil, and records the
+// call in MethodDurationMetric.
+func (t *MethodTracer) Start(ctx context.Context, method string) (context.Context, func(err error)) {
+	if t.i == nil || !t.i.enabled {
+		return ctx, func(error) {}
+	}
+
+	ctx, span := t.i.StartSpan(ctx, t.typeName+"."+method, trace.WithAttributes(
+		attribute.String("code.function.name", t.typeName+"."+method),
+	))
+	start := time.Now()
+	return ctx, func(err error) {
+		endFuncSpan(span, err)
+		span.End()
+		helper.RecordDuration(ctx, t.i.provider, MethodDurationMetric, time.Since(start), &helper.MetricOptions{
+			Component: "instrumentor",
+			Attributes: []attribute.KeyValue{
+				attribute.String("type", t.typeName),
+				attribute.String("method", method),
+				attribute.Bool("success", err == nil),
+			},
+		})
+	}
+}
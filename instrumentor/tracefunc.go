@@ -0,0 +1,93 @@
+package instrumentor
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceFunc0 returns fn wrapped so that every call runs in a span named
+// after fn, e.g. "orders.(*Repo).LoadAll", which ends with an error status
+// when fn fails. Unlike TraceFunction the signature is kept, so calls stay
+// type-checked:
+//
+//	loadAll := instrumentor.TraceFunc0(agent.Instrumentor(), repo.LoadAll)
+//	orders, err := loadAll(ctx)
+//
+// With instrumentation disabled fn is returned as is.
+func TraceFunc0[R any](i *Instrumentor, fn func(context.Context) (R, error)) func(context.Context) (R, error) {
+	if !i.enabled {
+		return fn
+	}
+	name, qualified := funcNames(fn)
+	return func(ctx context.Context) (R, error) {
+		ctx, span := i.startFuncSpan(ctx, name, qualified)
+		defer span.End()
+		r, err := fn(ctx)
+		endFuncSpan(span, err)
+		return r, err
+	}
+}
+
+// TraceFunc1 is TraceFunc0 for functions taking one argument after the
+// context.
+//
+//	load := instrumentor.TraceFunc1(agent.Instrumentor(), repo.Load)
+//	order, err := load(ctx, orderID)
+func TraceFunc1[A, R any](i *Instrumentor, fn func(context.Context, A) (R, error)) func(context.Context, A) (R, error) {
+	if !i.enabled {
+		return fn
+	}
+	name, qualified := funcNames(fn)
+	return func(ctx context.Context, a A) (R, error) {
+		ctx, span := i.startFuncSpan(ctx, name, qualified)
+		defer span.End()
+		r, err := fn(ctx, a)
+		endFuncSpan(span, err)
+		return r, err
+	}
+}
+
+// TraceFunc2 is TraceFunc0 for functions taking two arguments after the
+// context.
+func TraceFunc2[A, B, R any](i *Instrumentor, fn func(context.Context, A, B) (R, error)) func(context.Context, A, B) (R, error) {
+	if !i.enabled {
+		return fn
+	}
+	name, qualified := funcNames(fn)
+	return func(ctx context.Context, a A, b B) (R, error) {
+		ctx, span := i.startFuncSpan(ctx, name, qualified)
+		defer span.End()
+		r, err := fn(ctx, a, b)
+		endFuncSpan(span, err)
+		return r, err
+	}
+}
+
+func (i *Instrumentor) startFuncSpan(ctx context.Context, name, qualified string) (context.Context, trace.Span) {
+	return i.StartSpan(ctx, name, trace.WithAttributes(attribute.String("code.function.name", qualified)))
+}
+
+func endFuncSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// funcNames returns the span name of fn, its package-qualified name without
+// the import path ("orders.(*Repo).Load"), and its fully-qualified name.
+// Method values lose the "-fm" suffix the compiler gives them.
+func funcNames(fn any) (name, qualified string) {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "function", "function"
+	}
+	qualified = strings.TrimSuffix(f.Name(), "-fm")
+	return qualified[strings.LastIndex(qualified, "/")+1:], qualified
+}
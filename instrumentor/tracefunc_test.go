@@ -0,0 +1,85 @@
+package instrumentor_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/instrumentor"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type recordingProvider struct{ tp *sdktrace.TracerProvider }
+
+func (p recordingProvider) GetTracer(name string) trace.Tracer { return p.tp.Tracer(name) }
+func (p recordingProvider) GetMeter(name string) metric.Meter {
+	return noop.NewMeterProvider().Meter(name)
+}
+func (p recordingProvider) IsEnabled() bool { return true }
+
+type orderRepo struct{ missing error }
+
+func (r *orderRepo) Load(ctx context.Context, id string) (string, error) {
+	if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		return "", errors.New("no span in context")
+	}
+	if id == "" {
+		return "", r.missing
+	}
+	return "order " + id, nil
+}
+
+func TestTraceFunc1_KeepsSignatureAndNamesSpanAfterFunction(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	inst := instrumentor.New(recordingProvider{sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))})
+	repo := &orderRepo{missing: errors.New("not found")}
+
+	load := instrumentor.TraceFunc1(inst, repo.Load)
+	if got, err := load(context.Background(), "42"); err != nil || got != "order 42" {
+		t.Fatalf("load = %q, %v", got, err)
+	}
+	if _, err := load(context.Background(), ""); err == nil {
+		t.Fatal("load(\"\") returned no error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	if want := "instrumentor_test.(*orderRepo).Load"; spans[0].Name() != want {
+		t.Errorf("span name = %q, want %q", spans[0].Name(), want)
+	}
+	var qualified string
+	for _, kv := range spans[0].Attributes() {
+		if kv.Key == "code.function.name" {
+			qualified = kv.Value.AsString()
+		}
+	}
+	if !strings.HasSuffix(qualified, "/instrumentor_test.(*orderRepo).Load") {
+		t.Errorf("code.function.name = %q", qualified)
+	}
+	if spans[0].Status().Code != codes.Unset || spans[1].Status().Code != codes.Error {
+		t.Errorf("statuses = %v, %v, want unset then error", spans[0].Status(), spans[1].Status())
+	}
+}
+
+func TestTraceFunc_DisabledReturnsFunctionUnwrapped(t *testing.T) {
+	inst := instrumentor.New(nil)
+	calls := 0
+	fn := instrumentor.TraceFunc2(inst, func(_ context.Context, a, b int) (int, error) {
+		calls++
+		return a + b, nil
+	})
+	if got, _ := fn(context.Background(), 2, 3); got != 5 || calls != 1 {
+		t.Errorf("fn(2, 3) = %d after %d calls, want 5 after 1", got, calls)
+	}
+	if got, _ := instrumentor.TraceFunc0(inst, func(context.Context) (bool, error) { return true, nil })(context.Background()); !got {
+		t.Error("TraceFunc0 result lost")
+	}
+}
@@ -8,40 +8,15 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
 
-// amqpHeaderCarrier adapts AMQP message headers for OTel propagation.
-type amqpHeaderCarrier amqp.Table
-
-func (c amqpHeaderCarrier) Get(key string) string {
-	if v, ok := c[key]; ok {
-		if s, ok := v.(string); ok {
-			return s
-		}
-	}
-	return ""
-}
-
-func (c amqpHeaderCarrier) Set(key, value string) {
-	c[key] = value
-}
-
-func (c amqpHeaderCarrier) Keys() []string {
-	keys := make([]string, 0, len(c))
-	for k := range c {
-		keys = append(keys, k)
-	}
-	return keys
-}
-
 // InjectContext injects trace context into AMQP message headers.
 func InjectContext(ctx context.Context, headers amqp.Table) amqp.Table {
 	if headers == nil {
 		headers = amqp.Table{}
 	}
-	instrumentor.InjectContext(ctx, amqpHeaderCarrier(headers))
+	instrumentor.InjectContext(ctx, instrumentor.NewMapCarrier(headers))
 	return headers
 }
 
@@ -50,7 +25,7 @@ func ExtractContext(ctx context.Context, headers amqp.Table) context.Context {
 	if headers == nil {
 		return ctx
 	}
-	return instrumentor.ExtractContext(ctx, amqpHeaderCarrier(headers))
+	return instrumentor.ExtractContext(ctx, instrumentor.NewMapCarrier(headers))
 }
 
 // PublishWithTrace publishes an AMQP message with trace context propagation.
@@ -75,7 +50,7 @@ func PublishWithTrace(ctx context.Context, agent *otelagent.Agent, ch *amqp.Chan
 	if msg.Headers == nil {
 		msg.Headers = amqp.Table{}
 	}
-	instrumentor.InjectContext(ctx, amqpHeaderCarrier(msg.Headers))
+	instrumentor.InjectContext(ctx, instrumentor.NewMapCarrier(msg.Headers))
 
 	err := ch.PublishWithContext(ctx, exchange, routingKey, false, false, msg)
 	if err != nil {
@@ -110,6 +85,3 @@ func StartConsumeSpan(ctx context.Context, agent *otelagent.Agent, delivery amqp
 
 	return ctx, span
 }
-
-// Ensure amqpHeaderCarrier implements propagation.TextMapCarrier.
-var _ propagation.TextMapCarrier = amqpHeaderCarrier(nil)
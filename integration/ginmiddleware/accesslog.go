@@ -0,0 +1,55 @@
+package ginmiddleware
+
+import (
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// WithAccessLog emits one structured log per request through the agent
+// Logger, replacing gin.Logger(). Records are logged with the request context,
+// so they carry trace_id/span_id and are exported over OTLP with the other
+// logs. 5xx responses log at error level, 4xx at warning, the rest at info.
+// Routes excluded from both tracing and metrics are not logged.
+func WithAccessLog() MiddlewareOption {
+	return func(cfg *middlewareConfig) {
+		cfg.accessLog = true
+	}
+}
+
+// logAccess writes the access log record for a completed request.
+func logAccess(log logger.Logger, c *gin.Context, route string, duration time.Duration, statusCode int, queue time.Duration, queued bool) {
+	if route == "" {
+		route = "unknown"
+	}
+
+	fields := logger.Fields{
+		"http.request.method":       c.Request.Method,
+		"http.route":                route,
+		"url.path":                  c.Request.URL.Path,
+		"http.response.status_code": statusCode,
+		"http.response.body.size":   c.Writer.Size(),
+		"duration_ms":               float64(duration.Microseconds()) / 1000,
+		"client.address":            c.ClientIP(),
+	}
+	if ua := c.Request.UserAgent(); ua != "" {
+		fields["user_agent.original"] = ua
+	}
+	if queued {
+		fields["queue_ms"] = float64(queue.Microseconds()) / 1000
+	}
+	if len(c.Errors) > 0 {
+		fields["error"] = c.Errors.String()
+	}
+
+	ctx := c.Request.Context()
+	switch {
+	case statusCode >= 500:
+		log.Error(ctx, "HTTP request", fields)
+	case statusCode >= 400:
+		log.Warning(ctx, "HTTP request", fields)
+	default:
+		log.Info(ctx, "HTTP request", fields)
+	}
+}
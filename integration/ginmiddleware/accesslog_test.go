@@ -0,0 +1,106 @@
+package ginmiddleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type logEntry struct {
+	level  string
+	ctx    context.Context
+	fields logger.Fields
+}
+
+// recordingLogger captures log calls; embedding NoopLogger covers the rest.
+type recordingLogger struct {
+	logger.NoopLogger
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) record(level string, ctx context.Context, fields []logger.Fields) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e := logEntry{level: level, ctx: ctx}
+	if len(fields) > 0 {
+		e.fields = fields[0]
+	}
+	l.entries = append(l.entries, e)
+}
+
+func (l *recordingLogger) Info(ctx context.Context, _ string, f ...logger.Fields) {
+	l.record("info", ctx, f)
+}
+
+func (l *recordingLogger) Warning(ctx context.Context, _ string, f ...logger.Fields) {
+	l.record("warn", ctx, f)
+}
+
+func (l *recordingLogger) Error(ctx context.Context, _ string, f ...logger.Fields) {
+	l.record("error", ctx, f)
+}
+
+func TestWithAccessLog_LogsOneCorrelatedRecordPerRequest(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+	log := &recordingLogger{}
+	agent := otelagent.NewAgent(otelagent.WithServiceName("access"), otelagent.WithLogger(log))
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(New(agent, "access", WithAccessLog()))
+	engine.GET("/orders/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/7", nil))
+
+	var access []logEntry
+	for _, e := range log.entries {
+		if e.fields["http.route"] != nil {
+			access = append(access, e)
+		}
+	}
+	if len(access) != 1 {
+		t.Fatalf("expected 1 access log, got %d", len(access))
+	}
+	e := access[0]
+	if e.level != "warn" {
+		t.Errorf("level = %s, want warn for 404", e.level)
+	}
+	if e.fields["http.route"] != "/orders/:id" || e.fields["http.response.status_code"] != http.StatusNotFound {
+		t.Errorf("unexpected fields: %v", e.fields)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if got := trace.SpanContextFromContext(e.ctx).TraceID(); got != spans[0].SpanContext().TraceID() {
+		t.Errorf("access log trace ID %s does not match span %s", got, spans[0].SpanContext().TraceID())
+	}
+}
+
+func TestNew_NoAccessLogByDefault(t *testing.T) {
+	agenttest.RecordSpans(t)
+	log := &recordingLogger{}
+	agent := otelagent.NewAgent(otelagent.WithServiceName("access"), otelagent.WithLogger(log))
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(New(agent, "access"))
+	engine.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	for _, e := range log.entries {
+		if e.fields["http.route"] != nil {
+			t.Fatalf("unexpected access log: %v", e.fields)
+		}
+	}
+}
@@ -1,27 +1,70 @@
 package ginmiddleware
 
 import (
-	"bytes"
-
 	"github.com/gin-gonic/gin"
 )
 
-// BodyLogWriter is a custom writer for capturing HTTP response body content.
+// minBodyBuffer is the smallest buffer BodyLogWriter allocates, so small
+// responses written in many pieces do not reallocate for each one.
+const minBodyBuffer = 512
+
+// BodyLogWriter is a gin.ResponseWriter that tees the response body into a
+// buffer of at most maxSize bytes. Beyond that the rest of the response is
+// only passed through, so large downloads never grow the buffer past the cap.
 type BodyLogWriter struct {
 	gin.ResponseWriter
-	Body *bytes.Buffer
+	body      []byte
+	maxSize   int
+	truncated bool
+}
+
+// NewBodyLogWriter wraps w to capture up to maxSize bytes of the response
+// body. A maxSize of zero or less captures the whole body.
+func NewBodyLogWriter(w gin.ResponseWriter, maxSize int) *BodyLogWriter {
+	return &BodyLogWriter{ResponseWriter: w, maxSize: maxSize}
 }
 
 // Write writes data to both the buffer and the underlying ResponseWriter.
 func (w *BodyLogWriter) Write(b []byte) (int, error) {
-	w.Body.Write(b)
+	capture(w, b)
 	return w.ResponseWriter.Write(b)
 }
 
-// NewBodyLogWriter wraps a gin.ResponseWriter to capture the response body.
-func NewBodyLogWriter(w gin.ResponseWriter) *BodyLogWriter {
-	return &BodyLogWriter{
-		ResponseWriter: w,
-		Body:           &bytes.Buffer{},
+// WriteString writes s to both the buffer and the underlying ResponseWriter.
+func (w *BodyLogWriter) WriteString(s string) (int, error) {
+	capture(w, s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Body returns the captured body, at most maxSize bytes of it.
+func (w *BodyLogWriter) Body() []byte {
+	return w.body
+}
+
+// Truncated reports whether the response was longer than the captured body.
+func (w *BodyLogWriter) Truncated() bool {
+	return w.truncated
+}
+
+// capture appends as much of p to the buffer as the cap allows, growing it
+// to at most maxSize.
+func capture[T string | []byte](w *BodyLogWriter, p T) {
+	n := len(p)
+	if w.maxSize > 0 && len(w.body)+n > w.maxSize {
+		n = w.maxSize - len(w.body)
+		w.truncated = true
+	}
+	if n <= 0 {
+		return
+	}
+	if need := len(w.body) + n; need > cap(w.body) {
+		size := max(2*cap(w.body), need, minBodyBuffer)
+		if w.maxSize > 0 {
+			size = min(size, w.maxSize)
+		}
+		grown := make([]byte, len(w.body), size)
+		copy(grown, w.body)
+		w.body = grown
 	}
+	w.body = append(w.body, p[:n]...)
 }
@@ -0,0 +1,77 @@
+package ginmiddleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestBodyLogWriter_CapsBuffer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	w := NewBodyLogWriter(c.Writer, 10)
+
+	_, _ = w.Write([]byte("hello "))
+	_, _ = io.WriteString(w, "world, and more")
+	_, _ = w.Write(make([]byte, 1<<20))
+
+	if got := string(w.Body()); got != "hello worl" || !w.Truncated() {
+		t.Errorf("body = %q, truncated = %v; want the first 10 bytes, truncated", got, w.Truncated())
+	}
+	if cap(w.Body()) > 10 {
+		t.Errorf("buffer capacity = %d, want at most the cap", cap(w.Body()))
+	}
+	if rec.Body.Len() != 21+1<<20 {
+		t.Errorf("client got %d bytes, want the whole response", rec.Body.Len())
+	}
+}
+
+func TestBodyLogWriter_UncappedKeepsWholeBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	w := NewBodyLogWriter(c.Writer, 0)
+
+	body := strings.Repeat("x", 5000)
+	_, _ = w.WriteString(body)
+	if string(w.Body()) != body || w.Truncated() {
+		t.Errorf("captured %d bytes, truncated = %v; want the whole body", len(w.Body()), w.Truncated())
+	}
+}
+
+func TestNew_MarksTruncatedResponseBody(t *testing.T) {
+	t.Setenv("OTEL_HTTP_CAPTURE_RESPONSE_BODY", "true")
+	t.Setenv("OTEL_HTTP_RESPONSE_BODY_MAX_SIZE", "16")
+	recorder := agenttest.RecordSpans(t)
+	agent := otelagent.NewAgent(otelagent.WithServiceName("downloads"), otelagent.WithLogger(&logger.NoopLogger{}))
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(New(agent, "downloads"))
+	engine.GET("/export", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat("row\n", 100))
+	})
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/export", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	attrs := make(map[string]string)
+	for _, kv := range spans[0].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["http.response.body"] != strings.Repeat("row\n", 4) || attrs["http.response.body.truncated"] != "true" {
+		t.Errorf("body = %q, truncated = %q; want 16 bytes, truncated", attrs["http.response.body"], attrs["http.response.body.truncated"])
+	}
+	if attrs["http.response.body.size"] != "400" {
+		t.Errorf("http.response.body.size = %s, want the full 400 bytes", attrs["http.response.body.size"])
+	}
+}
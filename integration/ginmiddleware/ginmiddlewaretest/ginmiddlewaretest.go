@@ -0,0 +1,177 @@
+// Package ginmiddlewaretest runs Gin handlers through ginmiddleware with an
+// in-memory span recorder and compares the produced spans against golden
+// JSON fixtures, so services can guard their own enrichment hooks against
+// regressions.
+//
+//	h := ginmiddlewaretest.New(t, agent, "orders-api")
+//	h.Engine.GET("/orders/:id", handler)
+//	h.Do(httptest.NewRequest(http.MethodGet, "/orders/42", nil))
+//	ginmiddlewaretest.AssertGolden(t, "testdata/get_order.golden.json", h.Spans())
+//
+// Set GOLDEN_UPDATE=1 to (re)write fixtures instead of comparing.
+package ginmiddlewaretest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/integration/ginmiddleware"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// UpdateEnv is the environment variable that makes AssertGolden write
+// fixtures instead of comparing against them.
+const UpdateEnv = "GOLDEN_UPDATE"
+
+// Span is a deterministic snapshot of a recorded span: IDs and timestamps
+// are dropped and the parent is referenced by name.
+type Span struct {
+	Name              string         `json:"name"`
+	Kind              string         `json:"kind"`
+	Parent            string         `json:"parent,omitempty"`
+	Status            string         `json:"status"`
+	StatusDescription string         `json:"status_description,omitempty"`
+	Attributes        map[string]any `json:"attributes,omitempty"`
+	Events            []Event        `json:"events,omitempty"`
+}
+
+// Event is a deterministic snapshot of a span event.
+type Event struct {
+	Name       string         `json:"name"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// Harness serves requests through a Gin engine with the middleware
+// installed and records every span produced while handling them.
+type Harness struct {
+	// Engine has the middleware installed; register routes on it.
+	Engine *gin.Engine
+
+	recorder *tracetest.SpanRecorder
+	ignored  map[string]struct{}
+}
+
+// New creates a Harness. It installs a recording TracerProvider as the
+// global provider for the duration of the test and restores the previous
+// one on cleanup, so tests using it must not run in parallel.
+func New(t testing.TB, agent *otelagent.Agent, serviceName string, opts ...ginmiddleware.MiddlewareOption) *Harness {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(ginmiddleware.New(agent, serviceName, opts...))
+
+	return &Harness{
+		Engine:   engine,
+		recorder: recorder,
+		ignored:  make(map[string]struct{}),
+	}
+}
+
+// IgnoreAttributes drops the given attribute keys from snapshots, for
+// values that legitimately vary between runs.
+func (h *Harness) IgnoreAttributes(keys ...string) *Harness {
+	for _, k := range keys {
+		h.ignored[k] = struct{}{}
+	}
+	return h
+}
+
+// Do serves req and returns the recorded response.
+func (h *Harness) Do(req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.Engine.ServeHTTP(w, req)
+	return w
+}
+
+// Spans returns snapshots of all spans ended so far, in end order.
+func (h *Harness) Spans() []Span {
+	ended := h.recorder.Ended()
+
+	names := make(map[trace.SpanID]string, len(ended))
+	for _, s := range ended {
+		names[s.SpanContext().SpanID()] = s.Name()
+	}
+
+	spans := make([]Span, 0, len(ended))
+	for _, s := range ended {
+		snap := Span{
+			Name:              s.Name(),
+			Kind:              s.SpanKind().String(),
+			Parent:            names[s.Parent().SpanID()],
+			Status:            s.Status().Code.String(),
+			StatusDescription: s.Status().Description,
+			Attributes:        h.attributes(s.Attributes()),
+		}
+		for _, e := range s.Events() {
+			snap.Events = append(snap.Events, Event{Name: e.Name, Attributes: h.attributes(e.Attributes)})
+		}
+		spans = append(spans, snap)
+	}
+	return spans
+}
+
+// Reset discards recorded spans so the Harness can be reused.
+func (h *Harness) Reset() {
+	h.recorder.Reset()
+}
+
+func (h *Harness) attributes(kvs []attribute.KeyValue) map[string]any {
+	if len(kvs) == 0 {
+		return nil
+	}
+	attrs := make(map[string]any, len(kvs))
+	for _, kv := range kvs {
+		if _, skip := h.ignored[string(kv.Key)]; skip {
+			continue
+		}
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return attrs
+}
+
+// AssertGolden compares spans against the JSON fixture at path. With
+// GOLDEN_UPDATE=1 the fixture is written (creating directories) instead.
+func AssertGolden(t testing.TB, path string, spans []Span) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(spans, "", "  ")
+	if err != nil {
+		t.Fatalf("ginmiddlewaretest: failed to encode spans: %v", err)
+	}
+	got = append(got, '\n')
+
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("ginmiddlewaretest: failed to create fixture dir: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("ginmiddlewaretest: failed to write fixture: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ginmiddlewaretest: failed to read fixture %s (run with %s=1 to create it): %v", path, UpdateEnv, err)
+	}
+	if !bytes.Equal(bytes.TrimSpace(got), bytes.TrimSpace(want)) {
+		t.Errorf("spans do not match golden fixture %s (run with %s=1 to update)\n--- got:\n%s\n--- want:\n%s", path, UpdateEnv, got, want)
+	}
+}
@@ -0,0 +1,52 @@
+package ginmiddlewaretest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+)
+
+func TestHarness_MatchesGoldenForRouteWithChildSpan(t *testing.T) {
+	h := New(t, agenttest.NewUninitialized(), "orders-api")
+	h.Engine.GET("/orders/:id", func(c *gin.Context) {
+		_, span := otel.Tracer("handler").Start(c.Request.Context(), "load order")
+		span.End()
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+
+	w := h.Do(httptest.NewRequest(http.MethodGet, "/orders/42", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+
+	AssertGolden(t, "testdata/get_order.golden.json", h.Spans())
+}
+
+func TestHarness_MatchesGoldenForHandlerError(t *testing.T) {
+	h := New(t, agenttest.NewUninitialized(), "orders-api").IgnoreAttributes("user_agent.original")
+	h.Engine.POST("/orders", func(c *gin.Context) {
+		_ = c.Error(errors.New("inventory unavailable"))
+		c.Status(http.StatusInternalServerError)
+	})
+
+	h.Do(httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+	AssertGolden(t, "testdata/create_order_error.golden.json", h.Spans())
+}
+
+func TestHarness_ResetDiscardsSpans(t *testing.T) {
+	h := New(t, agenttest.NewUninitialized(), "orders-api")
+	h.Engine.GET("/ping", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	h.Do(httptest.NewRequest(http.MethodGet, "/ping", nil))
+	h.Reset()
+
+	if n := len(h.Spans()); n != 0 {
+		t.Errorf("expected no spans after Reset, got %d", n)
+	}
+}
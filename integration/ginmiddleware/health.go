@@ -32,6 +32,35 @@ func ReadinessHandler(agent *otelagent.Agent) gin.HandlerFunc {
 	}
 }
 
+// LivenessHandler returns a Gin handler for the liveness probe.
+func LivenessHandler(agent *otelagent.Agent) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if agent.LivenessCheck() {
+			c.JSON(http.StatusOK, gin.H{"live": true})
+		} else {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"live": false})
+		}
+	}
+}
+
+// RegisterHealthRoutes registers the probes enabled in the agent's Features:
+// GET /health (HealthChecks), GET /ready (ReadinessProbes) and GET /live
+// (LivenessProbes). A disabled probe is not registered and answers 404.
+//
+//	ginmiddleware.RegisterHealthRoutes(r, agent)
+func RegisterHealthRoutes(r gin.IRoutes, agent *otelagent.Agent) {
+	features := agent.Config().Features
+	if features.HealthChecks {
+		r.GET("/health", HealthHandler(agent))
+	}
+	if features.ReadinessProbes {
+		r.GET("/ready", ReadinessHandler(agent))
+	}
+	if features.LivenessProbes {
+		r.GET("/live", LivenessHandler(agent))
+	}
+}
+
 // DiagnosticsHandler returns a Gin handler that exposes runtime config
 // for debugging telemetry issues (missing traces, wrong sampling, etc.).
 func DiagnosticsHandler(agent *otelagent.Agent) gin.HandlerFunc {
@@ -0,0 +1,34 @@
+package ginmiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRegisterHealthRoutes_FollowsFeatureFlags(t *testing.T) {
+	agent := otelagent.NewAgent(
+		otelagent.WithServiceName("probes"),
+		otelagent.WithLogger(&logger.NoopLogger{}),
+		otelagent.WithHealthProbes(true, false, true),
+	)
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	RegisterHealthRoutes(engine, agent)
+
+	for path, want := range map[string]int{
+		"/health": http.StatusOK,
+		"/ready":  http.StatusNotFound,
+		"/live":   http.StatusServiceUnavailable, // not initialized
+	} {
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
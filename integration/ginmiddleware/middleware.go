@@ -27,7 +27,18 @@ const scopeName = "github.com/RodolfoBonis/go-otel-agent/integration/ginmiddlewa
 type MiddlewareOption func(*middlewareConfig)
 
 type middlewareConfig struct {
-	customFilter func(*http.Request) bool
+	customFilter  func(*http.Request) bool
+	accessLog     bool
+	recoverPanics bool
+	repanic       bool
+	requestID     *requestIDConfig
+
+	// routeOverrides are keyed by "METHOD route", or " route" for every
+	// method.
+	routeOverrides map[string]*routeOverride
+
+	openAPI    *openAPIRoutes
+	openAPIErr error
 }
 
 // WithFilter adds a custom filter function. Return false to skip instrumentation.
@@ -47,19 +58,25 @@ func WithFilter(fn func(*http.Request) bool) MiddlewareOption {
 //
 //	tracer.Start → c.Next() → enrichSpan → defer span.End()
 func New(agent *otelagent.Agent, serviceName string, opts ...MiddlewareOption) gin.HandlerFunc {
-	if agent == nil || !agent.IsEnabled() {
-		return func(c *gin.Context) { c.Next() }
-	}
-
 	mCfg := &middlewareConfig{}
 	for _, opt := range opts {
 		opt(mCfg)
 	}
 
+	if agent == nil || !agent.IsEnabled() {
+		// Request IDs do not depend on telemetry being enabled
+		if mCfg.requestID != nil {
+			return requestIDHandler(mCfg.requestID)
+		}
+		return func(c *gin.Context) { c.Next() }
+	}
+
 	var (
 		initOnce       sync.Once
 		tracer         trace.Tracer
 		httpDuration   metric.Float64Histogram
+		queueDuration  metric.Float64Histogram
+		stageDuration  metric.Float64Histogram
 		requestCounter metric.Int64Counter
 		errorCounter   metric.Int64Counter
 		scrubber       *provider.HTTPScrubber
@@ -69,15 +86,28 @@ func New(agent *otelagent.Agent, serviceName string, opts ...MiddlewareOption) g
 		initOnce.Do(func() {
 			scrubber = provider.NewHTTPScrubber(agent.Config().HTTP, agent.Config().Scrub)
 
+			if mCfg.openAPIErr != nil {
+				agent.Logger().Warning(context.Background(), "ginmiddleware: OpenAPI spec ignored", logger.Fields{
+					"error": mCfg.openAPIErr.Error(),
+				})
+			}
+
 			tp := otel.GetTracerProvider()
 			tracer = tp.Tracer(scopeName)
 
-			agent.Logger().Debug(context.Background(), "ginmiddleware lazy init", logger.Fields{
+			sampling := agent.Config().Traces.Sampling
+			fields := logger.Fields{
 				"tracer_provider_type": fmt.Sprintf("%T", tp),
 				"service":              serviceName,
-				"sampling_rate":        agent.Config().Traces.Sampling.Rate,
+				"sampler":              sampling.Type,
 				"endpoint":             agent.Config().Endpoint,
-			})
+			}
+			if sampling.Type == "rate_limited" {
+				fields["spans_per_second"] = sampling.Rate
+			} else {
+				fields["sampling_rate"] = sampling.Rate
+			}
+			agent.Logger().Debug(context.Background(), "ginmiddleware lazy init", fields)
 
 			meter := agent.GetMeter(scopeName)
 			httpDuration, _ = meter.Float64Histogram(
@@ -85,6 +115,16 @@ func New(agent *otelagent.Agent, serviceName string, opts ...MiddlewareOption) g
 				metric.WithDescription("HTTP server request duration"),
 				metric.WithUnit("s"),
 			)
+			queueDuration, _ = meter.Float64Histogram(
+				"http.server.request.queue.duration",
+				metric.WithDescription("Time requests spent queued before reaching the service (from X-Request-Start)"),
+				metric.WithUnit("s"),
+			)
+			stageDuration, _ = meter.Float64Histogram(
+				"http.server.request.stage.duration",
+				metric.WithDescription("Time spent in each request stage timed with StageTimer"),
+				metric.WithUnit("s"),
+			)
 			requestCounter, _ = meter.Int64Counter(
 				"http.server.request.total",
 				metric.WithDescription("Total HTTP server requests"),
@@ -96,9 +136,50 @@ func New(agent *otelagent.Agent, serviceName string, opts ...MiddlewareOption) g
 		})
 	}
 
+	// recordMetrics records request metrics (bounded cardinality).
+	recordMetrics := func(c *gin.Context, route string, duration time.Duration, statusCode int, queue time.Duration, queued bool, stages *stageTimings) {
+		if route == "" {
+			route = "unknown"
+		}
+
+		metricAttrs := []attribute.KeyValue{
+			attribute.String("http.request.method", c.Request.Method),
+			attribute.String("http.route", route),
+			attribute.Int("http.response.status_code", statusCode),
+		}
+
+		if httpDuration != nil {
+			httpDuration.Record(c.Request.Context(), duration.Seconds(), metric.WithAttributes(metricAttrs...))
+		}
+		if queued && queueDuration != nil {
+			queueDuration.Record(c.Request.Context(), queue.Seconds(), metric.WithAttributes(metricAttrs...))
+		}
+		if stageDuration != nil {
+			stages.each(duration, func(stage string, d time.Duration) {
+				stageDuration.Record(c.Request.Context(), d.Seconds(), metric.WithAttributes(
+					append(metricAttrs[:len(metricAttrs):len(metricAttrs)], attribute.String("stage", stage))...,
+				))
+			})
+		}
+		if requestCounter != nil {
+			requestCounter.Add(c.Request.Context(), 1, metric.WithAttributes(metricAttrs...))
+		}
+		if statusCode >= 400 && errorCounter != nil {
+			errorCounter.Add(c.Request.Context(), 1, metric.WithAttributes(metricAttrs...))
+		}
+	}
+
 	return func(c *gin.Context) {
-		// Check exclusion before any work
-		if agent.RouteMatcher().ShouldExclude(c.Request.URL.Path) {
+		// Request IDs are assigned on excluded routes too, for their logs
+		if mCfg.requestID != nil {
+			assignRequestID(c, mCfg.requestID)
+		}
+
+		// Check exclusion before any work. Trace and metric exclusion are
+		// independent so e.g. /health can be counted but not traced.
+		traced := agent.ShouldTraceRoute(c.Request.URL.Path)
+		metered := agent.ShouldRecordRouteMetrics(c.Request.URL.Path)
+		if !traced && !metered {
 			c.Next()
 			return
 		}
@@ -112,11 +193,37 @@ func New(agent *otelagent.Agent, serviceName string, opts ...MiddlewareOption) g
 		// Lazy init on first request (after agent.Init() has completed)
 		lazyInit()
 
-		httpCfg := agent.Config().HTTP
+		route, operationID := mCfg.route(c)
+		override := mCfg.routeOverride(c, route)
+		httpCfg := override.httpConfig(agent.Config().HTTP)
+		if agent.Degraded() {
+			httpCfg.CaptureRequestBody, httpCfg.CaptureResponseBody = false, false
+		}
 		start := time.Now()
+		stages := newStageTimings(c)
+
+		// Queuing in front of the service (LB/ingress) happens before start
+		var queue time.Duration
+		var queued bool
+		if httpCfg.CaptureQueueTime {
+			queue, queued = queueTime(c.Request, start)
+		}
+
+		if !traced {
+			recovered := runHandlers(c, mCfg)
+			duration := time.Since(start)
+			statusCode := recovered.status(c)
+			recordMetrics(c, route, duration, statusCode, queue, queued, stages)
+			if mCfg.accessLog {
+				logAccess(agent.Logger(), c, route, duration, statusCode, queue, queued)
+			}
+			recovered.resume(mCfg)
+			return
+		}
 
 		// Extract propagation context from incoming headers (W3C traceparent, baggage)
 		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx = override.spanContext(ctx)
 
 		spanName := fmt.Sprintf("%s %s", c.Request.Method, c.Request.URL.Path)
 
@@ -127,13 +234,21 @@ func New(agent *otelagent.Agent, serviceName string, opts ...MiddlewareOption) g
 		)
 		defer span.End()
 
+		if queued {
+			span.SetAttributes(attribute.Float64("http.server.request.queue.duration", queue.Seconds()))
+		}
+
 		// Propagate trace context into the request so handlers and downstream
 		// instrumentation (GORM, otelhttp clients) use the correct parent span.
 		c.Request = c.Request.WithContext(ctx)
 
+		// Body capture, header scrubbing and the other large attributes are
+		// only worth their cost on spans that will be exported.
+		sampled := span.SpanContext().IsSampled()
+
 		// Capture request body BEFORE handler runs (if enabled)
 		var reqBody string
-		if httpCfg.CaptureRequestBody && scrubber.IsAllowedContentType(c.ContentType()) {
+		if sampled && httpCfg.CaptureRequestBody && scrubber.IsAllowedContentType(c.ContentType()) {
 			bodyBytes, err := io.ReadAll(c.Request.Body)
 			if err == nil && len(bodyBytes) > 0 {
 				reqBody = string(bodyBytes)
@@ -143,17 +258,17 @@ func New(agent *otelagent.Agent, serviceName string, opts ...MiddlewareOption) g
 
 		// Wrap response writer for body capture (if enabled)
 		var blw *BodyLogWriter
-		if httpCfg.CaptureResponseBody {
-			blw = NewBodyLogWriter(c.Writer)
+		if sampled && httpCfg.CaptureResponseBody {
+			blw = NewBodyLogWriter(c.Writer, httpCfg.ResponseBodyMaxSize)
 			c.Writer = blw
 		}
 
 		// ---- Run handler chain ----
-		c.Next()
+		recovered := runHandlers(c, mCfg)
 
 		// ---- Post-handler: span is still open, enrichment works ----
 		duration := time.Since(start)
-		statusCode := c.Writer.Status()
+		statusCode := recovered.status(c)
 
 		// Response attributes
 		span.SetAttributes(
@@ -161,14 +276,17 @@ func New(agent *otelagent.Agent, serviceName string, opts ...MiddlewareOption) g
 			attribute.Int("http.response.body.size", c.Writer.Size()),
 		)
 
-		if route := c.FullPath(); route != "" {
+		if route != "" {
 			span.SetAttributes(attribute.String("http.route", route))
 			// Update span name to use the registered route pattern
 			span.SetName(fmt.Sprintf("%s %s", c.Request.Method, route))
 		}
+		if operationID != "" {
+			span.SetAttributes(attribute.String("openapi.operation_id", operationID))
+		}
 
 		// Span status
-		if statusCode >= 500 {
+		if statusCode >= 500 && recovered == nil {
 			span.SetStatus(codes.Error, "")
 		}
 		if len(c.Errors) > 0 {
@@ -179,29 +297,27 @@ func New(agent *otelagent.Agent, serviceName string, opts ...MiddlewareOption) g
 		}
 
 		// Custom enrichment: headers, body, query params, user context
-		enrichSpan(c, span, httpCfg, scrubber, reqBody, blw, statusCode)
-
-		// Record metrics (bounded cardinality)
-		route := c.FullPath()
-		if route == "" {
-			route = "unknown"
+		if sampled {
+			enrichSpan(c, span, httpCfg, scrubber, reqBody, blw, statusCode)
+			stages.each(duration, func(stage string, d time.Duration) {
+				span.SetAttributes(attribute.Float64("http.server.stage."+stage+".duration", d.Seconds()))
+			})
 		}
 
-		metricAttrs := []attribute.KeyValue{
-			attribute.String("http.request.method", c.Request.Method),
-			attribute.String("http.route", route),
-			attribute.Int("http.response.status_code", statusCode),
-		}
+		// Trace ID response header for debugging
+		c.Header("X-Trace-Id", span.SpanContext().TraceID().String())
 
-		if httpDuration != nil {
-			httpDuration.Record(c.Request.Context(), duration.Seconds(), metric.WithAttributes(metricAttrs...))
+		if metered {
+			recordMetrics(c, route, duration, statusCode, queue, queued, stages)
 		}
-		if requestCounter != nil {
-			requestCounter.Add(c.Request.Context(), 1, metric.WithAttributes(metricAttrs...))
-		}
-		if statusCode >= 400 && errorCounter != nil {
-			errorCounter.Add(c.Request.Context(), 1, metric.WithAttributes(metricAttrs...))
+
+		// Logged while the span is still current so the record is correlated
+		if mCfg.accessLog {
+			logAccess(agent.Logger(), c, route, duration, statusCode, queue, queued)
 		}
+
+		// Re-raised last; the deferred span.End() still runs as it unwinds.
+		recovered.resume(mCfg)
 	}
 }
 
@@ -241,10 +357,10 @@ func requestAttrs(server string, c *gin.Context) []attribute.KeyValue {
 // enrichSpan adds HTTP headers, query params, body, user context, and error events to the span.
 func enrichSpan(c *gin.Context, span trace.Span, httpCfg otelagent.HTTPConfig, scrubber *provider.HTTPScrubber, reqBody string, blw *BodyLogWriter, statusCode int) {
 	// Client IP and request ID
-	span.SetAttributes(
-		attribute.String("http.client_ip", c.ClientIP()),
-		attribute.String("http.request.id", c.GetString("requestID")),
-	)
+	span.SetAttributes(attribute.String("http.client_ip", c.ClientIP()))
+	if reqID := c.GetString(string(logger.RequestIDKey)); reqID != "" {
+		span.SetAttributes(attribute.String("http.request.id", reqID))
+	}
 
 	// Request headers
 	if httpCfg.CaptureRequestHeaders {
@@ -278,17 +394,22 @@ func enrichSpan(c *gin.Context, span trace.Span, httpCfg otelagent.HTTPConfig, s
 	}
 
 	// Response body
-	if httpCfg.CaptureResponseBody && blw != nil && blw.Body.Len() > 0 {
-		respBody := blw.Body.String()
+	if httpCfg.CaptureResponseBody && blw != nil && len(blw.Body()) > 0 {
 		if scrubber.IsAllowedContentType(c.Writer.Header().Get("Content-Type")) {
-			scrubbed := scrubber.ScrubBody(respBody, httpCfg.ResponseBodyMaxSize)
-			span.SetAttributes(
-				attribute.String("http.response.body", scrubbed),
-				attribute.Int("http.response.body.size", len(respBody)),
-			)
+			// The writer already capped the body, so ScrubBody only redacts
+			scrubbed := scrubber.ScrubBody(string(blw.Body()), 0)
+			span.SetAttributes(attribute.String("http.response.body", scrubbed))
+			if blw.Truncated() {
+				span.SetAttributes(attribute.Bool("http.response.body.truncated", true))
+			}
 		}
 	}
 
+	// Multipart metadata, from the form the handler parsed
+	if httpCfg.CaptureMultipartMetadata {
+		span.SetAttributes(multipartAttrs(c.Request.MultipartForm)...)
+	}
+
 	// User context (spans only, not metrics - cardinality fix)
 	if userID, exists := c.Get("user_id"); exists {
 		span.SetAttributes(attribute.String("user.id", fmt.Sprintf("%v", userID)))
@@ -297,9 +418,6 @@ func enrichSpan(c *gin.Context, span trace.Span, httpCfg otelagent.HTTPConfig, s
 		span.SetAttributes(attribute.String("user.role", fmt.Sprintf("%v", userRole)))
 	}
 
-	// Trace ID response header for debugging
-	c.Header("X-Trace-Id", span.SpanContext().TraceID().String())
-
 	// Exception events for 4xx/5xx
 	if httpCfg.RecordExceptionEvents && statusCode >= 400 {
 		errMsg := http.StatusText(statusCode)
@@ -0,0 +1,97 @@
+package ginmiddleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordOnlySampler records spans without sampling them, like the adaptive
+// sampler does for unsampled roots while error boosting is enabled.
+type recordOnlySampler struct{}
+
+func (recordOnlySampler) ShouldSample(sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return sdktrace.SamplingResult{Decision: sdktrace.RecordOnly}
+}
+
+func (recordOnlySampler) Description() string { return "RecordOnly" }
+
+func serveWithBodyCapture(t *testing.T, sampler sdktrace.Sampler) (sdktrace.ReadOnlySpan, string) {
+	t.Helper()
+	t.Setenv("OTEL_HTTP_CAPTURE_REQUEST_BODY", "true")
+	t.Setenv("OTEL_HTTP_CAPTURE_RESPONSE_BODY", "true")
+
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler), sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	agent := otelagent.NewAgent(otelagent.WithServiceName("gating"), otelagent.WithLogger(&logger.NoopLogger{}))
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(New(agent, "gating"))
+
+	var handlerBody string
+	engine.POST("/orders", func(c *gin.Context) {
+		b, _ := io.ReadAll(c.Request.Body)
+		handlerBody = string(b)
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/orders?page=2", strings.NewReader(`{"item":"book"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	return spans[0], handlerBody
+}
+
+// hasAttr reports whether span has the attribute key, or any attribute under
+// key when it ends with a dot.
+func hasAttr(span sdktrace.ReadOnlySpan, key string) bool {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key || strings.HasSuffix(key, ".") && strings.HasPrefix(string(kv.Key), key) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNew_EnrichesSampledSpans(t *testing.T) {
+	span, _ := serveWithBodyCapture(t, sdktrace.AlwaysSample())
+
+	for _, key := range []string{"http.request.body", "http.response.body", "http.request.header.", "url.query"} {
+		if !hasAttr(span, key) {
+			t.Errorf("expected %s attribute on sampled span", key)
+		}
+	}
+}
+
+func TestNew_SkipsExpensiveEnrichmentForUnsampledSpans(t *testing.T) {
+	span, handlerBody := serveWithBodyCapture(t, recordOnlySampler{})
+
+	for _, key := range []string{"http.request.body", "http.response.body", "http.request.header.", "url.query"} {
+		if hasAttr(span, key) {
+			t.Errorf("unexpected %s attribute on unsampled span", key)
+		}
+	}
+	if !hasAttr(span, "http.response.status_code") || !hasAttr(span, "http.route") {
+		t.Error("status code and route should still be set for samplers and processors")
+	}
+	if handlerBody != `{"item":"book"}` {
+		t.Errorf("handler read body %q", handlerBody)
+	}
+}
@@ -0,0 +1,77 @@
+package ginmiddleware
+
+import (
+	"mime/multipart"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// maxMultipartFiles bounds the files described on one span.
+	maxMultipartFiles = 64
+	// maxFilenameLength bounds each recorded file name.
+	maxFilenameLength = 256
+)
+
+// multipartAttrs describes form, a multipart form parsed by the handler (as
+// c.FormFile, c.MultipartForm and form binding do), without its contents:
+// the names of the value parts and, as parallel arrays, the field name, file
+// name, size and content type of each file. It returns nil for a nil form.
+func multipartAttrs(form *multipart.Form) []attribute.KeyValue {
+	if form == nil {
+		return nil
+	}
+
+	fields := make([]string, 0, len(form.Value))
+	for name := range form.Value {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+
+	fileFields := make([]string, 0, len(form.File))
+	for name := range form.File {
+		fileFields = append(fileFields, name)
+	}
+	sort.Strings(fileFields)
+
+	var (
+		names, filenames, contentTypes []string
+		sizes                          []int64
+		total                          int64
+		count                          int
+	)
+	for _, field := range fileFields {
+		for _, fh := range form.File[field] {
+			count++
+			total += fh.Size
+			if len(names) == maxMultipartFiles {
+				continue
+			}
+			filename := fh.Filename
+			if len(filename) > maxFilenameLength {
+				filename = filename[:maxFilenameLength]
+			}
+			names = append(names, field)
+			filenames = append(filenames, filename)
+			sizes = append(sizes, fh.Size)
+			contentTypes = append(contentTypes, fh.Header.Get("Content-Type"))
+		}
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.Int("http.request.multipart.parts", len(fields)+count),
+		attribute.StringSlice("http.request.multipart.fields", fields),
+		attribute.Int("http.request.multipart.files.count", count),
+		attribute.Int64("http.request.multipart.files.total_size", total),
+	}
+	if count > 0 {
+		attrs = append(attrs,
+			attribute.StringSlice("http.request.multipart.files.name", names),
+			attribute.StringSlice("http.request.multipart.files.filename", filenames),
+			attribute.Int64Slice("http.request.multipart.files.size", sizes),
+			attribute.StringSlice("http.request.multipart.files.content_type", contentTypes),
+		)
+	}
+	return attrs
+}
@@ -0,0 +1,80 @@
+package ginmiddleware
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestNew_CapturesMultipartMetadata(t *testing.T) {
+	t.Setenv("OTEL_HTTP_CAPTURE_MULTIPART_METADATA", "true")
+	recorder := agenttest.RecordSpans(t)
+	agent := otelagent.NewAgent(otelagent.WithServiceName("uploads"), otelagent.WithLogger(&logger.NoopLogger{}))
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(New(agent, "uploads"))
+	engine.POST("/upload", func(c *gin.Context) {
+		if _, err := c.FormFile("avatar"); err != nil {
+			t.Errorf("FormFile: %v", err)
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	_ = w.WriteField("user_id", "u-1")
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="avatar"; filename="me.png"`)
+	h.Set("Content-Type", "image/png")
+	part, _ := w.CreatePart(h)
+	_, _ = part.Write(bytes.Repeat([]byte{0x89}, 1024))
+	_ = w.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if got := attrs["http.request.multipart.parts"].AsInt64(); got != 2 {
+		t.Errorf("parts = %d, want 2", got)
+	}
+	if got := attrs["http.request.multipart.fields"].AsStringSlice(); len(got) != 1 || got[0] != "user_id" {
+		t.Errorf("fields = %v, want [user_id]", got)
+	}
+	if got := attrs["http.request.multipart.files.filename"].AsStringSlice(); len(got) != 1 || got[0] != "me.png" {
+		t.Errorf("filenames = %v, want [me.png]", got)
+	}
+	if got := attrs["http.request.multipart.files.size"].AsInt64Slice(); len(got) != 1 || got[0] != 1024 {
+		t.Errorf("sizes = %v, want [1024]", got)
+	}
+	if got := attrs["http.request.multipart.files.content_type"].AsStringSlice(); len(got) != 1 || got[0] != "image/png" {
+		t.Errorf("content types = %v, want [image/png]", got)
+	}
+	for key := range attrs {
+		if key == "http.request.body" {
+			t.Error("multipart contents should not be recorded")
+		}
+	}
+}
+
+func TestMultipartAttrs_NilForm(t *testing.T) {
+	if attrs := multipartAttrs(nil); attrs != nil {
+		t.Errorf("multipartAttrs(nil) = %v, want nil", attrs)
+	}
+}
@@ -0,0 +1,174 @@
+package ginmiddleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-yaml"
+)
+
+// openAPIMethods are the operation keys of an OpenAPI path item.
+var openAPIMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// WithOpenAPISpec resolves request paths to the route templates and
+// operation IDs of doc, an OpenAPI 3 or Swagger 2 document in JSON or YAML.
+// Requests Gin did not route itself (NoRoute handlers such as reverse
+// proxies) get the template as span name and http.route instead of
+// "unknown", and every request matching an operation is tagged with
+// openapi.operation_id. A document that fails to parse is reported through
+// the agent logger on the first request and otherwise ignored.
+func WithOpenAPISpec(doc []byte) MiddlewareOption {
+	return func(cfg *middlewareConfig) {
+		cfg.openAPI, cfg.openAPIErr = parseOpenAPI(doc)
+	}
+}
+
+// route returns the route template of c, Gin's registered route or else the
+// template of the OpenAPI spec, and the operation ID of the request in the
+// spec. Both are empty when unknown.
+func (cfg *middlewareConfig) route(c *gin.Context) (route, operationID string) {
+	route = c.FullPath()
+	if cfg.openAPI == nil {
+		return route, ""
+	}
+	template, operationID, ok := cfg.openAPI.match(c.Request.Method, c.Request.URL.Path)
+	if ok && route == "" {
+		route = template
+	}
+	return route, operationID
+}
+
+// openAPIRoutes matches request paths against the path templates of an
+// OpenAPI document. Templates are stored in a tree of path segments.
+type openAPIRoutes struct {
+	root openAPINode
+}
+
+type openAPINode struct {
+	literal map[string]*openAPINode
+	param   *openAPINode // a {name} segment
+	// template and operations are set on the node a path template ends at.
+	template   string
+	operations map[string]string // upper-case method -> operationId
+}
+
+type openAPIDoc struct {
+	BasePath string `json:"basePath"`
+	Servers  []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// parseOpenAPI builds the routes of doc. Templates are prefixed with the
+// base path (Swagger 2 basePath, or the path of the first OpenAPI 3
+// server), which is where requests reach them.
+func parseOpenAPI(doc []byte) (*openAPIRoutes, error) {
+	data, err := yaml.YAMLToJSON(doc)
+	if err != nil {
+		return nil, fmt.Errorf("ginmiddleware: failed to parse OpenAPI spec: %w", err)
+	}
+	var spec openAPIDoc
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("ginmiddleware: failed to parse OpenAPI spec: %w", err)
+	}
+	if len(spec.Paths) == 0 {
+		return nil, fmt.Errorf("ginmiddleware: OpenAPI spec has no paths")
+	}
+
+	base := spec.BasePath
+	if base == "" && len(spec.Servers) > 0 {
+		if u, err := url.Parse(spec.Servers[0].URL); err == nil {
+			base = u.Path
+		}
+	}
+	base = strings.TrimSuffix(base, "/")
+
+	routes := &openAPIRoutes{}
+	for path, item := range spec.Paths {
+		node := &routes.root
+		template := base + "/" + strings.Trim(path, "/")
+		if len(template) > 1 {
+			template = strings.TrimSuffix(template, "/")
+		}
+		for _, seg := range splitPath(template) {
+			node = node.child(seg)
+		}
+		node.template = template
+		node.operations = make(map[string]string)
+		for method, raw := range item {
+			if !openAPIMethods[method] {
+				continue
+			}
+			var op struct {
+				OperationID string `json:"operationId"`
+			}
+			_ = json.Unmarshal(raw, &op)
+			node.operations[strings.ToUpper(method)] = op.OperationID
+		}
+	}
+	return routes, nil
+}
+
+func (n *openAPINode) child(seg string) *openAPINode {
+	if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+		if n.param == nil {
+			n.param = &openAPINode{}
+		}
+		return n.param
+	}
+	if n.literal == nil {
+		n.literal = make(map[string]*openAPINode)
+	}
+	c, ok := n.literal[seg]
+	if !ok {
+		c = &openAPINode{}
+		n.literal[seg] = c
+	}
+	return c
+}
+
+// match returns the template path matches and the operation ID of method
+// on it, if any. Literal segments win over parameters, as OpenAPI requires
+// of concrete and templated paths.
+func (r *openAPIRoutes) match(method, path string) (template, operationID string, ok bool) {
+	node := r.root.match(splitPath(path))
+	if node == nil {
+		return "", "", false
+	}
+	return node.template, node.operations[method], true
+}
+
+func (n *openAPINode) match(segs []string) *openAPINode {
+	if len(segs) == 0 {
+		if n.operations == nil {
+			return nil
+		}
+		return n
+	}
+	if c := n.literal[segs[0]]; c != nil {
+		if found := c.match(segs[1:]); found != nil {
+			return found
+		}
+	}
+	if n.param != nil && segs[0] != "" {
+		return n.param.match(segs[1:])
+	}
+	return nil
+}
+
+// splitPath splits a path into its segments, ignoring leading and trailing
+// slashes.
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
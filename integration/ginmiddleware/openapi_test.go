@@ -0,0 +1,114 @@
+package ginmiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest"
+	"github.com/gin-gonic/gin"
+)
+
+const ordersSpec = `
+openapi: 3.0.3
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /orders/{id}:
+    parameters:
+      - name: id
+        in: path
+    get:
+      operationId: getOrder
+    delete:
+      operationId: cancelOrder
+  /orders/latest:
+    get:
+      operationId: getLatestOrder
+  /:
+    get:
+      operationId: root
+`
+
+func TestOpenAPIRoutes_Match(t *testing.T) {
+	routes, err := parseOpenAPI([]byte(ordersSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		method, path          string
+		template, operationID string
+		ok                    bool
+	}{
+		{"GET", "/v1/orders/42", "/v1/orders/{id}", "getOrder", true},
+		{"DELETE", "/v1/orders/42/", "/v1/orders/{id}", "cancelOrder", true},
+		{"GET", "/v1/orders/latest", "/v1/orders/latest", "getLatestOrder", true},
+		{"POST", "/v1/orders/42", "/v1/orders/{id}", "", true},
+		{"GET", "/v1", "/v1", "root", true},
+		{"GET", "/v1/orders", "", "", false},
+		{"GET", "/v1/orders/42/items", "", "", false},
+	}
+	for _, tt := range tests {
+		template, operationID, ok := routes.match(tt.method, tt.path)
+		if template != tt.template || operationID != tt.operationID || ok != tt.ok {
+			t.Errorf("match(%s %s) = %q, %q, %v; want %q, %q, %v",
+				tt.method, tt.path, template, operationID, ok, tt.template, tt.operationID, tt.ok)
+		}
+	}
+}
+
+func TestParseOpenAPI_Errors(t *testing.T) {
+	for _, doc := range []string{"paths: [", `{"openapi": "3.0.0"}`} {
+		if _, err := parseOpenAPI([]byte(doc)); err == nil {
+			t.Errorf("parseOpenAPI(%q) succeeded, want an error", doc)
+		}
+	}
+}
+
+func TestNew_OpenAPISpecNamesProxiedRoutes(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+	log := &recordingLogger{}
+	agent := otelagent.NewAgent(otelagent.WithServiceName("gateway"), otelagent.WithLogger(log))
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(New(agent, "gateway", WithOpenAPISpec([]byte(ordersSpec))))
+	engine.NoRoute(func(c *gin.Context) { c.Status(http.StatusOK) }) // reverse proxy
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/orders/42", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if name := spans[0].Name(); name != "GET /v1/orders/{id}" {
+		t.Errorf("span name = %q, want the OpenAPI template", name)
+	}
+	attrs := make(map[string]string)
+	for _, kv := range spans[0].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["http.route"] != "/v1/orders/{id}" || attrs["openapi.operation_id"] != "getOrder" {
+		t.Errorf("http.route = %q, openapi.operation_id = %q", attrs["http.route"], attrs["openapi.operation_id"])
+	}
+}
+
+func TestNew_InvalidOpenAPISpecIsReported(t *testing.T) {
+	agenttest.RecordSpans(t)
+	log := &recordingLogger{}
+	agent := otelagent.NewAgent(otelagent.WithServiceName("gateway"), otelagent.WithLogger(log))
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(New(agent, "gateway", WithOpenAPISpec([]byte("paths: ["))))
+	engine.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	var warned bool
+	for _, e := range log.entries {
+		warned = warned || e.level == "warn" && e.fields["error"] != nil
+	}
+	if !warned {
+		t.Error("expected a warning about the invalid spec")
+	}
+}
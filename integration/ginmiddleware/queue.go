@@ -0,0 +1,57 @@
+package ginmiddleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// queueTimeHeaders are set by load balancers and proxies with the time the
+// request was received, e.g. nginx `proxy_set_header X-Request-Start "t=${msec}"`.
+var queueTimeHeaders = []string{"X-Request-Start", "X-Queue-Start"}
+
+// queueTime returns how long the request waited between the proxy and now,
+// based on the first parseable queue-time header. Negative values (clock skew
+// between hosts) are discarded.
+func queueTime(req *http.Request, now time.Time) (time.Duration, bool) {
+	for _, h := range queueTimeHeaders {
+		v := req.Header.Get(h)
+		if v == "" {
+			continue
+		}
+		start, ok := parseQueueStart(v)
+		if !ok {
+			continue
+		}
+		if d := now.Sub(start); d >= 0 {
+			return d, true
+		}
+		return 0, false
+	}
+	return 0, false
+}
+
+// parseQueueStart parses "t=<timestamp>" or "<timestamp>". The unit is
+// inferred from the magnitude, so seconds (with fraction), milliseconds,
+// microseconds and nanoseconds since the epoch are all accepted.
+func parseQueueStart(value string) (time.Time, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "t=")
+	ts, err := strconv.ParseFloat(value, 64)
+	if err != nil || ts <= 0 {
+		return time.Time{}, false
+	}
+
+	var nanos float64
+	switch {
+	case ts > 1e18:
+		nanos = ts
+	case ts > 1e15:
+		nanos = ts * 1e3
+	case ts > 1e12:
+		nanos = ts * 1e6
+	default:
+		nanos = ts * 1e9
+	}
+	return time.Unix(0, int64(nanos)), true
+}
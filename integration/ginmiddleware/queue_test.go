@@ -0,0 +1,84 @@
+package ginmiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseQueueStart_InfersUnit(t *testing.T) {
+	want := time.Unix(1700000000, 123000000)
+
+	tests := []string{
+		"t=1700000000.123",    // nginx ${msec}
+		"1700000000123",       // milliseconds
+		"t=1700000000123000",  // microseconds (Apache %t)
+		"1700000000123000000", // nanoseconds
+	}
+	for _, v := range tests {
+		got, ok := parseQueueStart(v)
+		if !ok {
+			t.Errorf("parseQueueStart(%q) failed", v)
+			continue
+		}
+		if d := got.Sub(want); d < -time.Millisecond || d > time.Millisecond {
+			t.Errorf("parseQueueStart(%q) = %v, want %v", v, got, want)
+		}
+	}
+
+	for _, v := range []string{"", "t=", "abc", "-5"} {
+		if _, ok := parseQueueStart(v); ok {
+			t.Errorf("parseQueueStart(%q) should fail", v)
+		}
+	}
+}
+
+func TestQueueTime_IgnoresClockSkew(t *testing.T) {
+	now := time.Now()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	req.Header.Set("X-Queue-Start", "t="+strconv.FormatInt(now.Add(-50*time.Millisecond).UnixMilli(), 10))
+	if d, ok := queueTime(req, now); !ok || d < 49*time.Millisecond || d > 51*time.Millisecond {
+		t.Errorf("queueTime = %v, %v; want ~50ms", d, ok)
+	}
+
+	req.Header.Set("X-Queue-Start", strconv.FormatInt(now.Add(time.Second).UnixMilli(), 10))
+	if _, ok := queueTime(req, now); ok {
+		t.Error("expected future timestamps to be discarded")
+	}
+}
+
+func TestNew_RecordsQueueTimeOnSpan(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+
+	agent := otelagent.NewAgent(otelagent.WithServiceName("queue"), otelagent.WithLogger(&logger.NoopLogger{}))
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(New(agent, "queue"))
+	engine.GET("/orders", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set("X-Request-Start", "t="+strconv.FormatInt(time.Now().Add(-200*time.Millisecond).UnixMicro(), 10))
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	for _, kv := range spans[0].Attributes() {
+		if kv.Key == "http.server.request.queue.duration" {
+			if got := kv.Value.AsFloat64(); got < 0.2 || got > 1 {
+				t.Errorf("queue duration = %vs, want ~0.2s", got)
+			}
+			return
+		}
+	}
+	t.Error("expected http.server.request.queue.duration attribute")
+}
@@ -0,0 +1,66 @@
+package ginmiddleware
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/RodolfoBonis/go-otel-agent/helper"
+	"github.com/gin-gonic/gin"
+)
+
+// WithPanicRecovery recovers panics in the handler chain, replacing
+// gin.Recovery(). The panic is recorded on the request span as an exception
+// event with its stack trace (see helper.RecordPanic), the span is marked as
+// an error and panics_total is incremented. The request is then completed
+// as a 500, or, with repanic, the panic continues once the span and metrics
+// are recorded, for an outer recovery or net/http to handle.
+func WithPanicRecovery(repanic bool) MiddlewareOption {
+	return func(cfg *middlewareConfig) {
+		cfg.recoverPanics = true
+		cfg.repanic = repanic
+	}
+}
+
+// panicState is a panic recovered by runHandlers.
+type panicState struct {
+	value any
+}
+
+// runHandlers runs the rest of the chain, recovering a panic when recovery
+// is enabled. A recovered panic is recorded and, unless it will be
+// re-raised, answered with a 500.
+func runHandlers(c *gin.Context, cfg *middlewareConfig) (p *panicState) {
+	if !cfg.recoverPanics {
+		c.Next()
+		return nil
+	}
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		helper.RecordPanic(c.Request.Context(), recovered, debug.Stack())
+		if !cfg.repanic {
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}
+		p = &panicState{value: recovered}
+	}()
+	c.Next()
+	return nil
+}
+
+// status is the response status to record: a panic that is re-raised has
+// not written one yet, but will end as a 500.
+func (p *panicState) status(c *gin.Context) int {
+	if p != nil && !c.Writer.Written() {
+		return http.StatusInternalServerError
+	}
+	return c.Writer.Status()
+}
+
+// resume re-raises the panic when configured to.
+func (p *panicState) resume(cfg *middlewareConfig) {
+	if p != nil && cfg.repanic {
+		panic(p.value)
+	}
+}
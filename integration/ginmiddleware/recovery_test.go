@@ -0,0 +1,85 @@
+package ginmiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func panickingEngine(t *testing.T, opts ...MiddlewareOption) (*gin.Engine, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	agent := otelagent.NewAgent(otelagent.WithServiceName("recovery"), otelagent.WithLogger(&logger.NoopLogger{}))
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(New(agent, "recovery", opts...))
+	engine.GET("/boom", func(c *gin.Context) {
+		var orders []string
+		_ = orders[3]
+	})
+	return engine, recorder
+}
+
+func TestWithPanicRecovery_RecordsAndAnswers500(t *testing.T) {
+	engine, recorder := panickingEngine(t, WithPanicRecovery(false))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/boom", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+
+	span := recorder.Ended()[0]
+	if span.Status().Code != codes.Error || !strings.HasPrefix(span.Status().Description, "panic: ") {
+		t.Errorf("status = %v %q, want a panic error", span.Status().Code, span.Status().Description)
+	}
+	var found bool
+	for _, ev := range span.Events() {
+		attrs := attribute.NewSet(ev.Attributes...)
+		if st, ok := attrs.Value("exception.stacktrace"); ok {
+			found = true
+			if !strings.Contains(st.AsString(), "recovery_test.go") {
+				t.Errorf("stack trace does not include the panicking handler:\n%s", st.AsString())
+			}
+		}
+	}
+	if !found {
+		t.Error("no exception event with a stack trace")
+	}
+}
+
+func TestWithPanicRecovery_Repanic(t *testing.T) {
+	engine, recorder := panickingEngine(t, WithPanicRecovery(true))
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to be re-raised")
+			}
+		}()
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boom", nil))
+	}()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Status().Code != codes.Error {
+		t.Fatalf("expected the span to end with an error before the panic continued, got %d spans", len(spans))
+	}
+	attrs := attribute.NewSet(spans[0].Attributes()...)
+	if v, _ := attrs.Value("http.response.status_code"); v.AsInt64() != http.StatusInternalServerError {
+		t.Errorf("http.response.status_code = %d, want 500", v.AsInt64())
+	}
+}
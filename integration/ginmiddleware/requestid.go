@@ -0,0 +1,146 @@
+package ginmiddleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultRequestIDHeader is the header request IDs are read from and
+// written to unless WithRequestIDHeader is used.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds accepted incoming IDs, which end up in every
+// log record and span of the request.
+const maxRequestIDLength = 128
+
+// RequestIDFormat is the format of generated request IDs.
+type RequestIDFormat string
+
+const (
+	// RequestIDUUID generates random (version 4) UUIDs.
+	RequestIDUUID RequestIDFormat = "uuid"
+	// RequestIDULID generates ULIDs, which sort by creation time.
+	RequestIDULID RequestIDFormat = "ulid"
+)
+
+// RequestIDOption configures request ID handling.
+type RequestIDOption func(*requestIDConfig)
+
+type requestIDConfig struct {
+	header string
+	format RequestIDFormat
+}
+
+// WithRequestIDHeader reads and writes request IDs in header instead of
+// X-Request-ID.
+func WithRequestIDHeader(header string) RequestIDOption {
+	return func(cfg *requestIDConfig) {
+		cfg.header = header
+	}
+}
+
+// WithRequestIDFormat sets the format of generated request IDs (UUID by
+// default). Incoming IDs are accepted in any format.
+func WithRequestIDFormat(format RequestIDFormat) RequestIDOption {
+	return func(cfg *requestIDConfig) {
+		cfg.format = format
+	}
+}
+
+func newRequestIDConfig(opts []RequestIDOption) *requestIDConfig {
+	cfg := &requestIDConfig{header: DefaultRequestIDHeader, format: RequestIDUUID}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithRequestID makes the middleware assign every request an ID, as
+// RequestID does, before the span starts, so the span and every log record
+// of the request carry it.
+func WithRequestID(opts ...RequestIDOption) MiddlewareOption {
+	return func(cfg *middlewareConfig) {
+		cfg.requestID = newRequestIDConfig(opts)
+	}
+}
+
+// RequestID returns a middleware that assigns every request an ID: the one
+// in the X-Request-ID header when it is a plausible ID, otherwise a new one.
+// The ID is stored in the gin context under "requestID" and in the request
+// context under logger.RequestIDKey, so log records carry it, and it is
+// echoed in the response header and set as http.request.id on the current
+// span. With New, use WithRequestID instead.
+func RequestID(opts ...RequestIDOption) gin.HandlerFunc {
+	return requestIDHandler(newRequestIDConfig(opts))
+}
+
+func requestIDHandler(cfg *requestIDConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := assignRequestID(c, cfg)
+		if span := trace.SpanFromContext(c.Request.Context()); span.IsRecording() {
+			span.SetAttributes(attribute.String("http.request.id", id))
+		}
+		c.Next()
+	}
+}
+
+// assignRequestID stores the request ID of c and returns it.
+func assignRequestID(c *gin.Context, cfg *requestIDConfig) string {
+	id := c.GetHeader(cfg.header)
+	if !validRequestID(id) {
+		id = newRequestID(cfg.format)
+	}
+	c.Set(string(logger.RequestIDKey), id)
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), logger.RequestIDKey, id))
+	c.Header(cfg.header, id)
+	return id
+}
+
+// validRequestID reports whether an incoming ID is short and made of
+// printable ASCII without spaces, so it cannot forge log lines or headers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID(format RequestIDFormat) string {
+	if format == RequestIDULID {
+		return newULID(time.Now())
+	}
+	return uuid.NewString()
+}
+
+// crockford is the base32 alphabet of ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newULID returns a ULID: a 48-bit millisecond timestamp and 80 random
+// bits, as 26 Crockford base32 characters.
+func newULID(t time.Time) string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(t.UnixMilli())<<16)
+	_, _ = rand.Read(b[6:])
+
+	hi, lo := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	var out [26]byte
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
@@ -0,0 +1,94 @@
+package ginmiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestNew_WithRequestID(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+	agent := otelagent.NewAgent(otelagent.WithServiceName("ids"), otelagent.WithLogger(&logger.NoopLogger{}))
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(New(agent, "ids", WithRequestID(WithRequestIDHeader("X-Correlation-ID"))))
+
+	var ctxID string
+	engine.GET("/orders", func(c *gin.Context) {
+		ctxID, _ = c.Request.Context().Value(logger.RequestIDKey).(string)
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set("X-Correlation-ID", "abc-123")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	if ctxID != "abc-123" || w.Header().Get("X-Correlation-ID") != "abc-123" {
+		t.Errorf("context ID %q, response header %q; want the incoming ID", ctxID, w.Header().Get("X-Correlation-ID"))
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	var spanID string
+	for _, kv := range spans[0].Attributes() {
+		if kv.Key == "http.request.id" {
+			spanID = kv.Value.AsString()
+		}
+	}
+	if spanID != "abc-123" {
+		t.Errorf("http.request.id = %q, want abc-123", spanID)
+	}
+}
+
+func TestRequestID_GeneratesInvalidOrMissingIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for name, tc := range map[string]struct {
+		format   RequestIDFormat
+		incoming string
+		length   int
+	}{
+		"uuid":             {RequestIDUUID, "", 36},
+		"ulid":             {RequestIDULID, "", 26},
+		"forged log line":  {RequestIDUUID, "a\nlevel=error", 36},
+		"oversized header": {RequestIDUUID, strings.Repeat("x", maxRequestIDLength+1), 36},
+	} {
+		t.Run(name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(RequestID(WithRequestIDFormat(tc.format)))
+			var ginID string
+			engine.GET("/", func(c *gin.Context) { ginID = c.GetString("requestID") })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.incoming != "" {
+				req.Header[DefaultRequestIDHeader] = []string{tc.incoming}
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			got := w.Header().Get(DefaultRequestIDHeader)
+			if len(got) != tc.length || got != ginID {
+				t.Errorf("response ID %q, gin ID %q; want a new %d-character ID", got, ginID, tc.length)
+			}
+		})
+	}
+}
+
+func TestNewULID_SortsByTime(t *testing.T) {
+	earlier := newULID(time.UnixMilli(1_700_000_000_000))
+	later := newULID(time.UnixMilli(1_700_000_000_001))
+	if earlier[:10] >= later[:10] {
+		t.Errorf("timestamp part of %s should sort before %s", earlier, later)
+	}
+	if strings.Trim(earlier, crockford) != "" {
+		t.Errorf("%s has characters outside the Crockford alphabet", earlier)
+	}
+}
@@ -0,0 +1,92 @@
+package ginmiddleware
+
+import (
+	"context"
+	"strings"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/provider"
+	"github.com/gin-gonic/gin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// RouteOptions overrides the agent configuration for one route.
+type RouteOptions struct {
+	// CaptureRequestBody and CaptureResponseBody replace the global body
+	// capture settings on the route: false turns capture off there, true
+	// turns it on even when it is off globally.
+	CaptureRequestBody  bool
+	CaptureResponseBody bool
+
+	// SamplingRate samples requests to the route, when they start a trace,
+	// at this ratio instead of with the configured sampler. Zero keeps the
+	// configured sampler; values above 1 sample every request.
+	SamplingRate float64
+}
+
+// routeOverride is a RouteOptions with its sampler built once.
+type routeOverride struct {
+	RouteOptions
+	sampler sdktrace.Sampler // nil without SamplingRate
+}
+
+// WithRouteOverride overrides options for route, so noisy or sensitive
+// endpoints can opt out of body capture or sample at their own rate without
+// global configuration changes. route is the registered Gin route, as in
+// c.FullPath(), optionally preceded by a method: "/api/upload" applies to
+// every method, "POST /api/upload" to POST only and wins over the former.
+//
+//	ginmiddleware.WithRouteOverride("POST /api/upload", ginmiddleware.RouteOptions{
+//		CaptureRequestBody: false,
+//		SamplingRate:       0.01,
+//	})
+func WithRouteOverride(route string, opts RouteOptions) MiddlewareOption {
+	return func(cfg *middlewareConfig) {
+		if cfg.routeOverrides == nil {
+			cfg.routeOverrides = make(map[string]*routeOverride)
+		}
+		o := &routeOverride{RouteOptions: opts}
+		if opts.SamplingRate > 0 {
+			o.sampler = sdktrace.TraceIDRatioBased(min(opts.SamplingRate, 1))
+		}
+		method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok {
+			method, path = "", method
+		}
+		cfg.routeOverrides[strings.ToUpper(method)+" "+strings.TrimSpace(path)] = o
+	}
+}
+
+// routeOverride returns the override for route, the route template of c,
+// if any.
+func (cfg *middlewareConfig) routeOverride(c *gin.Context, route string) *routeOverride {
+	if len(cfg.routeOverrides) == 0 {
+		return nil
+	}
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+	if o, ok := cfg.routeOverrides[c.Request.Method+" "+route]; ok {
+		return o
+	}
+	return cfg.routeOverrides[" "+route]
+}
+
+// httpConfig returns cfg with the settings o overrides. A nil o returns
+// cfg unchanged.
+func (o *routeOverride) httpConfig(cfg otelagent.HTTPConfig) otelagent.HTTPConfig {
+	if o != nil {
+		cfg.CaptureRequestBody = o.CaptureRequestBody
+		cfg.CaptureResponseBody = o.CaptureResponseBody
+	}
+	return cfg
+}
+
+// spanContext returns the context to start the request span with: ctx,
+// carrying the sampler of o when it has one.
+func (o *routeOverride) spanContext(ctx context.Context) context.Context {
+	if o == nil || o.sampler == nil {
+		return ctx
+	}
+	return provider.ContextWithRootSampler(ctx, o.sampler)
+}
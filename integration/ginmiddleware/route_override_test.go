@@ -0,0 +1,60 @@
+package ginmiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestNew_RouteOverrideDisablesBodyCapture(t *testing.T) {
+	t.Setenv("OTEL_HTTP_CAPTURE_REQUEST_BODY", "true")
+	recorder := agenttest.RecordSpans(t)
+	agent := otelagent.NewAgent(otelagent.WithServiceName("uploads"), otelagent.WithLogger(&logger.NoopLogger{}))
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(New(agent, "uploads", WithRouteOverride("POST /upload", RouteOptions{})))
+	engine.POST("/upload", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	engine.POST("/orders", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	for _, path := range []string{"/upload", "/orders"} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"a":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if hasAttr(spans[0], "http.request.body") {
+		t.Error("overridden route captured the request body")
+	}
+	if !hasAttr(spans[1], "http.request.body") {
+		t.Error("other routes should keep the global body capture")
+	}
+}
+
+func TestMiddlewareConfig_RouteOverrideMethodWins(t *testing.T) {
+	cfg := &middlewareConfig{}
+	WithRouteOverride("/upload", RouteOptions{SamplingRate: 0.5})(cfg)
+	WithRouteOverride("post /upload", RouteOptions{CaptureResponseBody: true, SamplingRate: 2})(cfg)
+
+	gin.SetMode(gin.TestMode)
+	for method, wantCapture := range map[string]bool{http.MethodPost: true, http.MethodPut: false} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(method, "/upload", nil)
+		o := cfg.routeOverride(c, "/upload")
+		if o == nil || o.CaptureResponseBody != wantCapture {
+			t.Errorf("%s override = %+v, want CaptureResponseBody %v", method, o, wantCapture)
+		}
+	}
+	if got := cfg.routeOverrides["POST /upload"].sampler.Description(); got != "AlwaysOnSampler" {
+		t.Errorf("sampler = %s, want the rate capped at 1", got)
+	}
+}
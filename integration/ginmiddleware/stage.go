@@ -0,0 +1,103 @@
+package ginmiddleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stagesKey is the gin context key holding the request's *stageTimings.
+const stagesKey = "otelagent.stages"
+
+// otherStage collects the part of the request not covered by any stage.
+const otherStage = "other"
+
+// stageTimings accumulates per-stage durations of one request. Handlers may
+// time stages from several goroutines.
+type stageTimings struct {
+	mu     sync.Mutex
+	order  []string
+	totals map[string]time.Duration
+}
+
+func (s *stageTimings) add(stage string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.totals[stage]; !ok {
+		s.order = append(s.order, stage)
+	}
+	s.totals[stage] += d
+}
+
+// each calls fn for every stage in the order stages were first recorded,
+// then for "other" with the part of total not covered by them. Nothing is
+// reported when no stage was timed.
+func (s *stageTimings) each(total time.Duration, fn func(stage string, d time.Duration)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, stage := range s.order {
+		fn(stage, s.totals[stage])
+		total -= s.totals[stage]
+	}
+	// Stages timed concurrently can add up to more than the request took.
+	if len(s.order) > 0 && total > 0 {
+		fn(otherStage, total)
+	}
+}
+
+// StageTimer starts timing a stage of the current request and returns the
+// function that stops it. Time spent in a stage is summed across calls, so
+// a stage entered in a loop is reported once:
+//
+//	stop := ginmiddleware.StageTimer(c, "db")
+//	orders, err := repo.List(ctx)
+//	stop()
+//
+//	defer ginmiddleware.StageTimer(c, "render")()
+//
+// At the end of the request the middleware adds one
+// http.server.stage.<stage>.duration attribute (seconds) to the server span
+// and records each stage in the http.server.request.stage.duration histogram
+// with a "stage" attribute. The time outside every stage is reported as
+// "other", so the stages of a route stack up to its total duration. Keep
+// stage names few and fixed: each one is a metric series per route.
+//
+// Without the middleware in the chain, StageTimer does nothing.
+func StageTimer(c *gin.Context, stage string) (stop func()) {
+	stages := requestStages(c)
+	if stages == nil {
+		return func() {}
+	}
+	start := time.Now()
+	var once sync.Once
+	return func() {
+		once.Do(func() { stages.add(stage, time.Since(start)) })
+	}
+}
+
+// AddStageDuration adds d to a stage of the current request, for durations
+// measured elsewhere, e.g. reported by a client library.
+func AddStageDuration(c *gin.Context, stage string, d time.Duration) {
+	if stages := requestStages(c); stages != nil {
+		stages.add(stage, d)
+	}
+}
+
+func requestStages(c *gin.Context) *stageTimings {
+	if c == nil {
+		return nil
+	}
+	v, ok := c.Get(stagesKey)
+	if !ok {
+		return nil
+	}
+	stages, _ := v.(*stageTimings)
+	return stages
+}
+
+func newStageTimings(c *gin.Context) *stageTimings {
+	stages := &stageTimings{totals: make(map[string]time.Duration)}
+	c.Set(stagesKey, stages)
+	return stages
+}
@@ -0,0 +1,79 @@
+package ginmiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestStageTimer_AddsStageBreakdownToSpan(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+
+	agent := otelagent.NewAgent(otelagent.WithServiceName("stages"), otelagent.WithLogger(&logger.NoopLogger{}))
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(New(agent, "stages"))
+	engine.GET("/orders", func(c *gin.Context) {
+		for range 2 {
+			stop := StageTimer(c, "db")
+			time.Sleep(5 * time.Millisecond)
+			stop()
+		}
+		AddStageDuration(c, "render", time.Second)
+		c.Status(http.StatusOK)
+	})
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	got := make(map[string]float64)
+	for _, kv := range spans[0].Attributes() {
+		got[string(kv.Key)] = kv.Value.AsFloat64()
+	}
+	if d := got["http.server.stage.db.duration"]; d < 0.01 || d > 1 {
+		t.Errorf("db stage = %vs, want both timings summed (~0.01s)", d)
+	}
+	if d := got["http.server.stage.render.duration"]; d != 1 {
+		t.Errorf("render stage = %vs, want 1s", d)
+	}
+	if _, ok := got["http.server.stage.other.duration"]; ok {
+		t.Error("stages exceeding the request duration should leave no other stage")
+	}
+}
+
+func TestStageTimer_WithoutMiddlewareIsNoop(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	StageTimer(c, "db")()
+	AddStageDuration(c, "db", time.Second)
+	if _, ok := c.Get(stagesKey); ok {
+		t.Error("expected no stage timings without the middleware")
+	}
+}
+
+func TestStageTimings_ReportsOther(t *testing.T) {
+	stages := &stageTimings{totals: make(map[string]time.Duration)}
+	stages.add("db", 30*time.Millisecond)
+	stages.add("render", 10*time.Millisecond)
+	stages.add("db", 10*time.Millisecond)
+
+	var order []string
+	got := make(map[string]time.Duration)
+	stages.each(100*time.Millisecond, func(stage string, d time.Duration) {
+		order = append(order, stage)
+		got[stage] = d
+	})
+	if len(order) != 3 || order[0] != "db" || order[1] != "render" || order[2] != otherStage {
+		t.Errorf("order = %v, want [db render other]", order)
+	}
+	if got["db"] != 40*time.Millisecond || got[otherStage] != 50*time.Millisecond {
+		t.Errorf("durations = %v", got)
+	}
+}
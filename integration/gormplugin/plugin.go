@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/provider"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -122,14 +123,10 @@ func Instrument(db *gorm.DB, agent *otelagent.Agent, opts ...InstrumentOption) e
 		pluginOpts = append(pluginOpts, tracing.WithAttributes(staticAttrs...))
 	}
 
-	// Apply SQL truncation when configured
-	if agent.Config().Scrub.DBStatementMaxLength > 0 {
-		maxLen := agent.Config().Scrub.DBStatementMaxLength
+	// Apply SQL sanitization and truncation when configured
+	if scrub := agent.Config().Scrub; scrub.SQLSanitize || scrub.DBStatementMaxLength > 0 {
 		pluginOpts = append(pluginOpts, tracing.WithQueryFormatter(func(query string) string {
-			if len(query) > maxLen {
-				return query[:maxLen] + "..."
-			}
-			return query
+			return provider.DBStatement(query, scrub)
 		}))
 	}
 
@@ -0,0 +1,218 @@
+// Package graphqlplugin provides operation and resolver spans and operation
+// duration metrics for GraphQL servers. Every GraphQL request is a POST to
+// the same URL, so HTTP spans and metrics cannot tell operations apart; the
+// spans and the graphql.operation.duration histogram of this package are
+// keyed by operation name instead.
+//
+// The package does not depend on a GraphQL library. With gqlgen, call it
+// from the server's response and field hooks:
+//
+//	srv := handler.NewDefaultServer(schema)
+//	srv.AroundResponses(func(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+//		oc := graphql.GetOperationContext(ctx)
+//		ctx, end := graphqlplugin.StartOperation(ctx, agent, graphqlplugin.Operation{
+//			Name: oc.OperationName,
+//			Type: string(oc.Operation.Operation),
+//		})
+//		resp := next(ctx)
+//		errs := make([]error, 0, len(resp.Errors))
+//		for _, err := range resp.Errors {
+//			errs = append(errs, err)
+//		}
+//		end(errs...)
+//		return resp
+//	})
+//	srv.AroundFields(func(ctx context.Context, next graphql.Resolver) (any, error) {
+//		fc := graphql.GetFieldContext(ctx)
+//		if !fc.IsResolver {
+//			return next(ctx) // plain struct fields are not worth a span
+//		}
+//		ctx, end := graphqlplugin.StartField(ctx, agent, graphqlplugin.Field{
+//			ParentType: fc.Object,
+//			Name:       fc.Field.Name,
+//			Path:       fc.Path().String(),
+//		})
+//		res, err := next(ctx)
+//		end(err)
+//		return res, err
+//	})
+package graphqlplugin
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const scopeName = "github.com/RodolfoBonis/go-otel-agent/integration/graphqlplugin"
+
+// maxMetricOperationNames bounds the operation names recorded on
+// graphql.operation.duration per agent. Names come from clients, so later
+// ones are recorded as otherOperationName; spans always carry the name.
+const maxMetricOperationNames = 100
+
+const otherOperationName = "_OTHER"
+
+// Operation describes a GraphQL operation.
+type Operation struct {
+	// Name is the operation name; empty for anonymous operations.
+	Name string
+	// Type is "query", "mutation" or "subscription".
+	Type string
+}
+
+// Field describes the resolution of one field.
+type Field struct {
+	// ParentType is the type the field belongs to, e.g. "Query" or "Order".
+	ParentType string
+	// Name is the field name, e.g. "items".
+	Name string
+	// Path is the response path of the field, e.g. "order.items[0].product".
+	Path string
+}
+
+// metricOperationNames maps *otelagent.Agent -> *operationNames.
+var metricOperationNames sync.Map
+
+// operationNames is the set of operation names an agent records on its
+// metrics.
+type operationNames struct {
+	mu    sync.Mutex
+	names map[string]struct{}
+}
+
+// metricOperationName returns the graphql.operation.name to record on
+// metrics for name: name itself for the first maxMetricOperationNames
+// distinct names, otherOperationName afterwards.
+func metricOperationName(agent *otelagent.Agent, name string) string {
+	v, _ := metricOperationNames.LoadOrStore(agent, &operationNames{names: make(map[string]struct{})})
+	set := v.(*operationNames)
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	if _, ok := set.names[name]; ok {
+		return name
+	}
+	if len(set.names) >= maxMetricOperationNames {
+		return otherOperationName
+	}
+	set.names[name] = struct{}{}
+	return name
+}
+
+// instrumentCache maps *otelagent.Agent -> metric.Float64Histogram. The
+// histogram is only cached once the agent is running so operations served
+// before Init do not pin the noop meter.
+var instrumentCache sync.Map
+
+func enabled(agent *otelagent.Agent) bool {
+	return agent != nil && agent.IsEnabled() && agent.Config().Features.AutoGraphQL
+}
+
+func operationDuration(agent *otelagent.Agent) metric.Float64Histogram {
+	if cached, ok := instrumentCache.Load(agent); ok {
+		return cached.(metric.Float64Histogram)
+	}
+
+	h, _ := agent.GetMeter(scopeName).Float64Histogram(
+		"graphql.operation.duration",
+		metric.WithDescription("Duration of GraphQL operations"),
+		metric.WithUnit("s"),
+	)
+
+	if agent.IsRunning() {
+		instrumentCache.Store(agent, h)
+	}
+	return h
+}
+
+// StartOperation starts the span of a GraphQL operation, named after its
+// type and name ("query GetOrder"), and returns the function that ends it
+// with the errors of the response. Errors mark the span as failed, are
+// recorded on it, and are counted in graphql.operation.duration through
+// error.type. Operation names are client-supplied, so only the first
+// maxMetricOperationNames distinct names are recorded on the metric, later
+// ones as "_OTHER". With the agent disabled or OTEL_AUTO_GRAPHQL=false, ctx is
+// returned unchanged with a no-op end function.
+func StartOperation(ctx context.Context, agent *otelagent.Agent, op Operation) (context.Context, func(errs ...error)) {
+	if !enabled(agent) {
+		return ctx, func(...error) {}
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("graphql.operation.type", op.Type)}
+	metricAttrs := attrs
+	if op.Name != "" {
+		attrs = append(attrs, attribute.String("graphql.operation.name", op.Name))
+		metricAttrs = append(metricAttrs[:1:1], attribute.String("graphql.operation.name", metricOperationName(agent, op.Name)))
+	}
+	start := time.Now()
+	// The global TracerProvider is resolved per call, so servers set up
+	// before agent.Init() emit spans once the agent is running.
+	ctx, span := otel.GetTracerProvider().Tracer(scopeName).Start(ctx, operationSpanName(op),
+		trace.WithAttributes(attrs...),
+	)
+
+	return ctx, func(errs ...error) {
+		if err := errors.Join(errs...); err != nil {
+			for _, e := range errs {
+				if e != nil {
+					span.RecordError(e)
+				}
+			}
+			span.SetStatus(codes.Error, err.Error())
+			metricAttrs = append(metricAttrs, attribute.String("error.type", "graphql"))
+		}
+		operationDuration(agent).Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(metricAttrs...))
+		span.End()
+	}
+}
+
+// StartField starts a child span for the resolution of a field, named after
+// the field ("Order.items") so that span names stay few while the response
+// path, which includes list indexes, is the graphql.field.path attribute.
+// The returned function ends the span with the resolver's error.
+func StartField(ctx context.Context, agent *otelagent.Agent, f Field) (context.Context, func(err error)) {
+	if !enabled(agent) {
+		return ctx, func(error) {}
+	}
+
+	name := f.Name
+	if f.ParentType != "" {
+		name = f.ParentType + "." + f.Name
+	}
+	ctx, span := otel.GetTracerProvider().Tracer(scopeName).Start(ctx, name,
+		trace.WithAttributes(
+			attribute.String("graphql.field.name", f.Name),
+			attribute.String("graphql.field.parent_type", f.ParentType),
+			attribute.String("graphql.field.path", f.Path),
+		),
+	)
+
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// operationSpanName follows the GraphQL semantic conventions: the operation
+// type and name, or the type alone for anonymous operations.
+func operationSpanName(op Operation) string {
+	switch {
+	case op.Type == "":
+		return "GraphQL Operation"
+	case op.Name == "":
+		return op.Type
+	default:
+		return op.Type + " " + op.Name
+	}
+}
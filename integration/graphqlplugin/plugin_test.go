@@ -0,0 +1,101 @@
+package graphqlplugin
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func attrValue(attrs []attribute.KeyValue, key string) string {
+	for _, a := range attrs {
+		if string(a.Key) == key {
+			return a.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestStartOperation_ResolverSpansAreChildren(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+	agent := agenttest.NewUninitialized()
+
+	ctx, end := StartOperation(context.Background(), agent, Operation{Name: "GetOrder", Type: "query"})
+	_, endField := StartField(ctx, agent, Field{ParentType: "Query", Name: "order", Path: "order"})
+	endField(nil)
+	end()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	field, op := spans[0], spans[1]
+	if op.Name() != "query GetOrder" || attrValue(op.Attributes(), "graphql.operation.name") != "GetOrder" {
+		t.Errorf("operation span %q with attributes %v", op.Name(), op.Attributes())
+	}
+	if field.Name() != "Query.order" || attrValue(field.Attributes(), "graphql.field.path") != "order" {
+		t.Errorf("field span %q with attributes %v", field.Name(), field.Attributes())
+	}
+	if field.Parent().SpanID() != op.SpanContext().SpanID() {
+		t.Error("field span should be a child of the operation span")
+	}
+	if op.Status().Code == codes.Error {
+		t.Error("operation without errors marked as failed")
+	}
+}
+
+func TestStartOperation_ErrorsFailTheSpan(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+	agent := agenttest.NewUninitialized()
+
+	ctx, end := StartOperation(context.Background(), agent, Operation{Type: "mutation"})
+	_, endField := StartField(ctx, agent, Field{ParentType: "Mutation", Name: "placeOrder", Path: "placeOrder"})
+	endField(errors.New("out of stock"))
+	end(errors.New("out of stock"), nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	for _, span := range spans {
+		if span.Status().Code != codes.Error || len(span.Events()) != 1 {
+			t.Errorf("span %q: status %v with %d events, want an error with one exception", span.Name(), span.Status(), len(span.Events()))
+		}
+	}
+	if name := spans[1].Name(); name != "mutation" {
+		t.Errorf("anonymous operation span name = %q, want mutation", name)
+	}
+}
+
+func TestStartOperation_Disabled(t *testing.T) {
+	t.Setenv("OTEL_AUTO_GRAPHQL", "false")
+	recorder := agenttest.RecordSpans(t)
+	agent := agenttest.NewUninitialized()
+
+	ctx := context.Background()
+	got, end := StartOperation(ctx, agent, Operation{Name: "GetOrder", Type: "query"})
+	end()
+	if got != ctx || len(recorder.Ended()) != 0 {
+		t.Error("expected no span with OTEL_AUTO_GRAPHQL=false")
+	}
+}
+
+func TestMetricOperationName_BoundsDistinctNames(t *testing.T) {
+	agent := agenttest.NewUninitialized()
+	for i := range maxMetricOperationNames {
+		name := "Op" + strconv.Itoa(i)
+		if got := metricOperationName(agent, name); got != name {
+			t.Fatalf("metricOperationName(%q) = %q, want the name itself", name, got)
+		}
+	}
+	if got := metricOperationName(agent, "Flood"); got != otherOperationName {
+		t.Errorf("name past the limit recorded as %q, want %q", got, otherOperationName)
+	}
+	if got := metricOperationName(agent, "Op0"); got != "Op0" {
+		t.Errorf("known name recorded as %q, want Op0", got)
+	}
+}
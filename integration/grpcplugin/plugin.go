@@ -0,0 +1,267 @@
+// Package grpcplugin provides spans, metrics and trace context propagation
+// for gRPC servers and clients as grpc stats handlers. Streaming RPCs also
+// get per-stream message counts and their own duration histogram, since a
+// stream that lives for minutes does not belong in the same buckets as a
+// unary call.
+package grpcplugin
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+const scopeName = "github.com/RodolfoBonis/go-otel-agent/integration/grpcplugin"
+
+// Span attributes set on streaming RPCs when they end.
+const (
+	MessagesSentAttribute     = "rpc.stream.messages_sent"
+	MessagesReceivedAttribute = "rpc.stream.messages_received"
+)
+
+// streamDurationBoundaries spans a quick server stream up to an hour-long
+// subscription.
+var streamDurationBoundaries = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 1800, 3600}
+
+// NewServerHandler returns a stats handler for grpc.NewServer:
+//
+//	srv := grpc.NewServer(grpc.StatsHandler(grpcplugin.NewServerHandler(agent)))
+//
+// Every RPC gets a server span continuing the caller's trace. Unary RPCs are
+// recorded in rpc.server.call.duration. Streaming RPCs are recorded in
+// rpc.server.stream.duration, their received and sent message counts in
+// rpc.server.requests_per_rpc and rpc.server.responses_per_rpc, and the
+// counts are set on the span as rpc.stream.messages_received and
+// rpc.stream.messages_sent.
+func NewServerHandler(agent *otelagent.Agent) stats.Handler {
+	return &handler{agent: agent, side: "server", kind: trace.SpanKindServer}
+}
+
+// NewClientHandler returns the stats handler for grpc.NewClient, the client
+// counterpart of NewServerHandler: it injects trace context into the
+// outgoing metadata and records the rpc.client.* instruments.
+//
+//	conn, err := grpc.NewClient(target, grpc.WithStatsHandler(grpcplugin.NewClientHandler(agent)))
+func NewClientHandler(agent *otelagent.Agent) stats.Handler {
+	return &handler{agent: agent, side: "client", kind: trace.SpanKindClient}
+}
+
+type handler struct {
+	agent *otelagent.Agent
+	side  string
+	kind  trace.SpanKind
+}
+
+// instruments holds the RPC instruments of one side for one agent.
+type instruments struct {
+	callDuration    metric.Float64Histogram
+	streamDuration  metric.Float64Histogram
+	requestsPerRPC  metric.Int64Histogram
+	responsesPerRPC metric.Int64Histogram
+}
+
+// instrumentCache maps agent and side to *instruments. Instruments are only
+// cached once the agent is running so RPCs served before Init do not pin the
+// noop meter.
+var instrumentCache sync.Map
+
+type instrumentKey struct {
+	agent *otelagent.Agent
+	side  string
+}
+
+func (h *handler) instruments() *instruments {
+	key := instrumentKey{h.agent, h.side}
+	if cached, ok := instrumentCache.Load(key); ok {
+		return cached.(*instruments)
+	}
+
+	meter := h.agent.GetMeter(scopeName)
+	prefix := "rpc." + h.side + "."
+	inst := &instruments{}
+	inst.callDuration, _ = meter.Float64Histogram(prefix+"call.duration",
+		metric.WithDescription("Duration of unary gRPC calls"),
+		metric.WithUnit("s"),
+	)
+	inst.streamDuration, _ = meter.Float64Histogram(prefix+"stream.duration",
+		metric.WithDescription("Duration of streaming gRPC calls"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(streamDurationBoundaries...),
+	)
+	inst.requestsPerRPC, _ = meter.Int64Histogram(prefix+"requests_per_rpc",
+		metric.WithDescription("Request messages per streaming gRPC call"),
+		metric.WithUnit("{count}"),
+	)
+	inst.responsesPerRPC, _ = meter.Int64Histogram(prefix+"responses_per_rpc",
+		metric.WithDescription("Response messages per streaming gRPC call"),
+		metric.WithUnit("{count}"),
+	)
+
+	if h.agent.IsRunning() {
+		instrumentCache.Store(key, inst)
+	}
+	return inst
+}
+
+// rpcState is the per-RPC state carried in the RPC context.
+type rpcState struct {
+	span      trace.Span
+	attrs     []attribute.KeyValue
+	streaming atomic.Bool
+	sent      atomic.Int64
+	received  atomic.Int64
+}
+
+type rpcStateKey struct{}
+
+// TagRPC starts the RPC span.
+func (h *handler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	if h.agent == nil || !h.agent.IsEnabled() || !h.agent.Config().Features.AutoGRPC {
+		return ctx
+	}
+
+	propagator := otel.GetTextMapPropagator()
+	if h.kind == trace.SpanKindServer {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = propagator.Extract(ctx, metadataCarrier(md))
+	}
+
+	service, method := splitMethod(info.FullMethodName)
+	attrs := []attribute.KeyValue{
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", method),
+	}
+	// The global TracerProvider is resolved per RPC, so handlers created
+	// before agent.Init() emit spans once the agent is running.
+	ctx, span := otel.GetTracerProvider().Tracer(scopeName).Start(ctx, strings.TrimPrefix(info.FullMethodName, "/"),
+		trace.WithSpanKind(h.kind),
+		trace.WithAttributes(attrs...),
+	)
+
+	if h.kind == trace.SpanKindClient {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		md = md.Copy()
+		propagator.Inject(ctx, metadataCarrier(md))
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+	return context.WithValue(ctx, rpcStateKey{}, &rpcState{span: span, attrs: attrs})
+}
+
+// HandleRPC counts messages and ends the span and measurements with the RPC.
+func (h *handler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	state, ok := ctx.Value(rpcStateKey{}).(*rpcState)
+	if !ok {
+		return
+	}
+
+	switch rs := rs.(type) {
+	case *stats.Begin:
+		state.streaming.Store(rs.IsClientStream || rs.IsServerStream)
+	case *stats.InPayload:
+		state.received.Add(1)
+	case *stats.OutPayload:
+		state.sent.Add(1)
+	case *stats.End:
+		h.end(ctx, state, rs)
+	}
+}
+
+func (h *handler) end(ctx context.Context, state *rpcState, rs *stats.End) {
+	code := status.Code(rs.Error)
+	attrs := append(state.attrs[:len(state.attrs):len(state.attrs)], attribute.Int("rpc.grpc.status_code", int(code)))
+	span := state.span
+	span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+	if rs.Error != nil && h.isError(code) {
+		span.RecordError(rs.Error)
+		span.SetStatus(codes.Error, status.Convert(rs.Error).Message())
+	}
+
+	inst := h.instruments()
+	opt := metric.WithAttributes(attrs...)
+	duration := rs.EndTime.Sub(rs.BeginTime).Seconds()
+	if !state.streaming.Load() {
+		inst.callDuration.Record(ctx, duration, opt)
+		span.End(trace.WithTimestamp(rs.EndTime))
+		return
+	}
+
+	sent, received := state.sent.Load(), state.received.Load()
+	requests, responses := received, sent
+	if h.kind == trace.SpanKindClient {
+		requests, responses = sent, received
+	}
+	span.SetAttributes(
+		attribute.Int64(MessagesSentAttribute, sent),
+		attribute.Int64(MessagesReceivedAttribute, received),
+	)
+	inst.streamDuration.Record(ctx, duration, opt)
+	inst.requestsPerRPC.Record(ctx, requests, opt)
+	inst.responsesPerRPC.Record(ctx, responses, opt)
+	span.End(trace.WithTimestamp(rs.EndTime))
+}
+
+// isError follows the semantic conventions: any non-OK code fails a client
+// span, while a server span fails only on codes that point at the server.
+func (h *handler) isError(code grpccodes.Code) bool {
+	if h.kind == trace.SpanKindClient {
+		return code != grpccodes.OK
+	}
+	switch code {
+	case grpccodes.Unknown, grpccodes.DeadlineExceeded, grpccodes.Unimplemented,
+		grpccodes.Internal, grpccodes.Unavailable, grpccodes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// TagConn implements stats.Handler.
+func (h *handler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn implements stats.Handler.
+func (h *handler) HandleConn(context.Context, stats.ConnStats) {}
+
+// splitMethod splits "/package.Service/Method" into service and method.
+func splitMethod(fullMethod string) (service, method string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "", service
+	}
+	return service, method
+}
+
+// metadataCarrier adapts gRPC metadata to propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
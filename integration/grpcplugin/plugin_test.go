@@ -0,0 +1,142 @@
+package grpcplugin
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialHealth serves the gRPC health service, whose Watch RPC is server
+// streaming, over an in-memory listener with both handlers installed.
+func dialHealth(t *testing.T) healthpb.HealthClient {
+	t.Helper()
+	agent := agenttest.NewUninitialized()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.StatsHandler(NewServerHandler(agent)))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(NewClientHandler(agent)),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func spanAttrs(s sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range s.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestHandlers_UnaryCallPropagatesTrace(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+	client := dialHealth(t)
+
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected client and server spans, got %d", len(spans))
+	}
+	byKind := map[trace.SpanKind]sdktrace.ReadOnlySpan{}
+	for _, s := range spans {
+		byKind[s.SpanKind()] = s
+	}
+	server, caller := byKind[trace.SpanKindServer], byKind[trace.SpanKindClient]
+	if server == nil || caller == nil {
+		t.Fatalf("span kinds: %v", byKind)
+	}
+	if server.Parent().SpanID() != caller.SpanContext().SpanID() {
+		t.Error("server span is not a child of the client span")
+	}
+	attrs := spanAttrs(server)
+	if server.Name() != "grpc.health.v1.Health/Check" || attrs["rpc.service"].AsString() != "grpc.health.v1.Health" ||
+		attrs["rpc.method"].AsString() != "Check" {
+		t.Errorf("server span %q with %v", server.Name(), attrs)
+	}
+	if _, ok := attrs[MessagesSentAttribute]; ok {
+		t.Error("unary calls must not carry stream message counts")
+	}
+}
+
+func TestHandlers_CountStreamMessages(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+	client := dialHealth(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	cancel()
+	_, _ = stream.Recv()
+
+	// The server side ends asynchronously after the cancellation.
+	for deadline := time.Now().Add(5 * time.Second); len(recorder.Ended()) < 2; {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected client and server spans, got %d", len(recorder.Ended()))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, s := range recorder.Ended() {
+		attrs := spanAttrs(s)
+		sent, received := attrs[MessagesSentAttribute].AsInt64(), attrs[MessagesReceivedAttribute].AsInt64()
+		switch s.SpanKind() {
+		case trace.SpanKindServer:
+			if sent != 1 || received != 1 {
+				t.Errorf("server stream sent %d, received %d; want 1 and 1", sent, received)
+			}
+			if s.Status().Code == codes.Error {
+				t.Error("a cancelled stream must not fail the server span")
+			}
+		case trace.SpanKindClient:
+			if sent != 1 || received != 1 {
+				t.Errorf("client stream sent %d, received %d; want 1 and 1", sent, received)
+			}
+			if s.Status().Code != codes.Error {
+				t.Errorf("cancelled client stream status = %v, want Error", s.Status())
+			}
+		}
+	}
+}
+
+func TestSplitMethod(t *testing.T) {
+	tests := []struct{ in, service, method string }{
+		{"/grpc.health.v1.Health/Check", "grpc.health.v1.Health", "Check"},
+		{"pkg.Svc/Do", "pkg.Svc", "Do"},
+		{"/Do", "", "Do"},
+	}
+	for _, tt := range tests {
+		if service, method := splitMethod(tt.in); service != tt.service || method != tt.method {
+			t.Errorf("splitMethod(%q) = %q, %q; want %q, %q", tt.in, service, method, tt.service, tt.method)
+		}
+	}
+}
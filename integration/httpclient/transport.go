@@ -0,0 +1,285 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/provider"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const scopeName = "github.com/RodolfoBonis/go-otel-agent/integration/httpclient"
+
+// routeTemplateKey is the context key for the outbound route template.
+type routeTemplateKey struct{}
+
+// ContextWithRouteTemplate attaches a low-cardinality route template
+// (e.g. "/users/{id}") to the request context. It is used as url.template
+// on spans and metrics so outbound calls can be grouped per host+route.
+func ContextWithRouteTemplate(ctx context.Context, template string) context.Context {
+	return context.WithValue(ctx, routeTemplateKey{}, template)
+}
+
+// RouteTemplateFromContext returns the route template set with
+// ContextWithRouteTemplate, or an empty string.
+func RouteTemplateFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(routeTemplateKey{}).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// Option configures the instrumented transport.
+type Option func(*transportConfig)
+
+type transportConfig struct {
+	routeTemplate func(*http.Request) string
+}
+
+// WithRouteTemplate sets a function that derives the route template from
+// the outbound request. Defaults to RouteTemplateFromContext.
+func WithRouteTemplate(fn func(*http.Request) string) Option {
+	return func(cfg *transportConfig) {
+		cfg.routeTemplate = fn
+	}
+}
+
+// Transport is an http.RoundTripper that creates CLIENT spans, propagates
+// W3C trace context, and records outbound request metrics.
+type Transport struct {
+	base     http.RoundTripper
+	agent    *otelagent.Agent
+	cfg      transportConfig
+	scrubber *provider.HTTPScrubber
+
+	initOnce     sync.Once
+	duration     metric.Float64Histogram
+	errorCounter metric.Int64Counter
+}
+
+// NewTransport wraps base with OTel instrumentation. When the agent is nil
+// or disabled, base is returned unchanged.
+func NewTransport(agent *otelagent.Agent, base http.RoundTripper, opts ...Option) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if agent == nil || !agent.IsEnabled() || !agent.Config().Features.AutoHTTP {
+		return base
+	}
+
+	t := &Transport{
+		base:     base,
+		agent:    agent,
+		cfg:      transportConfig{routeTemplate: func(r *http.Request) string { return RouteTemplateFromContext(r.Context()) }},
+		scrubber: provider.NewHTTPScrubber(agent.Config().HTTP, agent.Config().Scrub),
+	}
+	for _, opt := range opts {
+		opt(&t.cfg)
+	}
+	return t
+}
+
+// WrapClient replaces the client's transport with an instrumented one.
+// A nil client is replaced by a new http.Client.
+func WrapClient(agent *otelagent.Agent, client *http.Client, opts ...Option) *http.Client {
+	if client == nil {
+		client = &http.Client{}
+	}
+	client.Transport = NewTransport(agent, client.Transport, opts...)
+	return client
+}
+
+// lazyInit creates the instruments from the agent's meter on the first
+// request after agent.Init() (FX lifecycle ordering); requests made before
+// it are not measured rather than pinned to a no-op meter.
+func (t *Transport) lazyInit() {
+	if !t.agent.IsRunning() {
+		return
+	}
+	t.initOnce.Do(func() {
+		meter := t.agent.GetMeter(scopeName)
+		t.duration, _ = meter.Float64Histogram(
+			"http.client.request.duration",
+			metric.WithDescription("HTTP client request duration"),
+			metric.WithUnit("s"),
+		)
+		t.errorCounter, _ = meter.Int64Counter(
+			"http.client.errors.total",
+			metric.WithDescription("Total HTTP client errors"),
+		)
+	})
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.lazyInit()
+
+	httpCfg := t.agent.Config().HTTP
+	if t.agent.Degraded() {
+		httpCfg.CaptureRequestBody, httpCfg.CaptureResponseBody = false, false
+	}
+	template := t.cfg.routeTemplate(req)
+	start := time.Now()
+
+	spanName := fmt.Sprintf("HTTP %s %s", req.Method, req.URL.Host)
+	ctx, span := t.agent.GetTracer(scopeName).Start(req.Context(), spanName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(t.requestAttrs(req, template)...),
+	)
+
+	// RoundTrippers must not modify the caller's request.
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	// Only the first RequestBodyMaxSize bytes are read; the body sent is
+	// the same stream, replayed.
+	if max := httpCfg.RequestBodyMaxSize; httpCfg.CaptureRequestBody && max > 0 &&
+		req.Body != nil && req.Body != http.NoBody &&
+		t.scrubber.IsAllowedContentType(req.Header.Get("Content-Type")) {
+		var head []byte
+		head, req.Body = peekBody(req.Body, max)
+		t.setBodyAttrs(span, "http.request.body", head, max, req.ContentLength)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+
+	metricAttrs := []attribute.KeyValue{
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Hostname()),
+	}
+	if template != "" {
+		metricAttrs = append(metricAttrs, attribute.String("url.template", template))
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+
+		metricAttrs = append(metricAttrs, attribute.String("error.type", fmt.Sprintf("%T", err)))
+		t.record(ctx, start, metricAttrs, true)
+		return resp, err
+	}
+
+	span.SetAttributes(
+		attribute.Int("http.response.status_code", resp.StatusCode),
+		attribute.Int("http.status_code", resp.StatusCode),
+	)
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+
+	metricAttrs = append(metricAttrs, attribute.Int("http.response.status_code", resp.StatusCode))
+	t.record(ctx, start, metricAttrs, resp.StatusCode >= 400)
+
+	// The first ResponseBodyMaxSize bytes are read before returning, so the
+	// span ends with the call even if the caller never closes the body.
+	if max := httpCfg.ResponseBodyMaxSize; httpCfg.CaptureResponseBody && max > 0 &&
+		resp.Body != nil && resp.Body != http.NoBody &&
+		t.scrubber.IsAllowedContentType(resp.Header.Get("Content-Type")) {
+		var head []byte
+		head, resp.Body = peekBody(resp.Body, max)
+		t.setBodyAttrs(span, "http.response.body", head, max, resp.ContentLength)
+	}
+
+	span.End()
+	return resp, nil
+}
+
+// setBodyAttrs attaches the scrubbed head of a body (truncated past max) as
+// key, and its size as key.size when known.
+func (t *Transport) setBodyAttrs(span trace.Span, key string, head []byte, max int, contentLength int64) {
+	if len(head) == 0 {
+		return
+	}
+	span.SetAttributes(attribute.String(key, t.scrubber.ScrubBody(string(head), max)))
+	if size, ok := bodySize(head, max, contentLength); ok {
+		span.SetAttributes(attribute.Int(key+".size", size))
+	}
+}
+
+func (t *Transport) record(ctx context.Context, start time.Time, attrs []attribute.KeyValue, failed bool) {
+	if t.duration != nil {
+		t.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+	}
+	if failed && t.errorCounter != nil {
+		t.errorCounter.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+}
+
+// requestAttrs returns HTTP client semconv attributes, including the legacy
+// names (net.peer.name, http.url, http.method) used by SigNoz External Calls.
+func (t *Transport) requestAttrs(req *http.Request, template string) []attribute.KeyValue {
+	u := *req.URL
+	u.RawQuery = t.scrubber.ScrubQueryString(u.RawQuery)
+	u.User = nil
+	fullURL := u.String()
+
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Hostname()),
+		attribute.String("url.full", fullURL),
+		attribute.String("net.peer.name", req.URL.Hostname()),
+		attribute.String("http.url", fullURL),
+		attribute.String("http.method", req.Method),
+	}
+
+	if port := req.URL.Port(); port != "" {
+		if p, err := strconv.Atoi(port); err == nil {
+			attrs = append(attrs, attribute.Int("server.port", p))
+		}
+	}
+	if template != "" {
+		attrs = append(attrs, attribute.String("url.template", template))
+	}
+
+	return attrs
+}
+
+// peekBody reads up to limit+1 bytes of body, the extra byte telling a body
+// longer than limit, and returns them with a body that replays them before
+// the unread rest. A failed read is replayed as the same error after the
+// bytes read, so capture never changes what the caller receives.
+func peekBody(body io.ReadCloser, limit int) ([]byte, io.ReadCloser) {
+	head, err := io.ReadAll(io.LimitReader(body, int64(limit)+1))
+	rest := io.Reader(body)
+	if err != nil {
+		rest = errReader{err}
+	}
+	return head, replayBody{Reader: io.MultiReader(bytes.NewReader(head), rest), Closer: body}
+}
+
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+// bodySize returns the size of a body whose first bytes are head: its
+// Content-Length when known, otherwise len(head) unless it was truncated. A
+// request with a body reports an unknown length as 0, a response as -1.
+func bodySize(head []byte, limit int, contentLength int64) (int, bool) {
+	switch {
+	case contentLength > 0:
+		return int(contentLength), true
+	case len(head) <= limit:
+		return len(head), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,191 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func attrValue(attrs []attribute.KeyValue, key string) (attribute.Value, bool) {
+	for _, a := range attrs {
+		if string(a.Key) == key {
+			return a.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestNewTransport_DisabledAgentReturnsBase(t *testing.T) {
+	base := &http.Transport{}
+	rt := NewTransport(otelagent.NewAgent(otelagent.WithEnabled(false), otelagent.WithLogger(&logger.NoopLogger{})), base)
+
+	if rt != base {
+		t.Error("expected base transport to be returned for disabled agent")
+	}
+}
+
+func TestTransport_CreatesClientSpanAndPropagates(t *testing.T) {
+	agent, recorder := agenttest.NewAgent(t)
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client := WrapClient(agent, nil)
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/users/42", nil)
+	req = req.WithContext(ContextWithRouteTemplate(req.Context(), "/users/{id}"))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if traceparent == "" {
+		t.Error("expected traceparent header to be injected")
+	}
+	if req.Header.Get("traceparent") != "" {
+		t.Error("expected caller request headers to be left untouched")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("span kind = %v, want client", span.SpanKind())
+	}
+	if v, ok := attrValue(span.Attributes(), "url.template"); !ok || v.AsString() != "/users/{id}" {
+		t.Errorf("url.template = %v, want /users/{id}", v.AsString())
+	}
+	if v, ok := attrValue(span.Attributes(), "http.response.status_code"); !ok || v.AsInt64() != 404 {
+		t.Errorf("http.response.status_code = %v, want 404", v.AsInt64())
+	}
+	if span.Status().Code.String() != "Error" {
+		t.Errorf("expected error status for 404, got %v", span.Status().Code)
+	}
+}
+
+func TestTransport_CapturesScrubbedBodies(t *testing.T) {
+	agent, recorder := agenttest.NewAgent(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"abc"}`))
+	}))
+	defer srv.Close()
+
+	agent.Config().HTTP.CaptureRequestBody = true
+	agent.Config().HTTP.CaptureResponseBody = true
+	agent.Config().HTTP.BodyAllowedContentTypes = []string{"application/json"}
+	agent.Config().Scrub.Enabled = true
+	agent.Config().Scrub.SensitivePatterns = []string{"abc", "hunter2"}
+
+	client := WrapClient(agent, nil)
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{"password":"hunter2"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if string(body) != `{"token":"abc"}` {
+		t.Errorf("response body altered for caller: %q", body)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	reqBody, _ := attrValue(spans[0].Attributes(), "http.request.body")
+	if strings.Contains(reqBody.AsString(), "hunter2") {
+		t.Errorf("request body not scrubbed: %q", reqBody.AsString())
+	}
+	respBody, ok := attrValue(spans[0].Attributes(), "http.response.body")
+	if !ok || strings.Contains(respBody.AsString(), "abc") {
+		t.Errorf("response body missing or not scrubbed: %q", respBody.AsString())
+	}
+}
+
+func TestTransport_CapsRequestBodyCaptureAndSendsItWhole(t *testing.T) {
+	agent, recorder := agenttest.NewAgent(t)
+
+	var received []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	agent.Config().HTTP.CaptureRequestBody = true
+	agent.Config().HTTP.RequestBodyMaxSize = 16
+	agent.Config().HTTP.BodyAllowedContentTypes = []string{"text/plain"}
+
+	sent := strings.Repeat("0123456789", 10)
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, io.NopCloser(strings.NewReader(sent)))
+	req.Header.Set("Content-Type", "text/plain")
+	resp, err := WrapClient(agent, nil).Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if string(received) != sent {
+		t.Errorf("server received %d bytes, want the whole %d-byte body", len(received), len(sent))
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if v, _ := attrValue(spans[0].Attributes(), "http.request.body"); v.AsString() != sent[:16]+"...[truncated]" {
+		t.Errorf("http.request.body = %q, want the first 16 bytes, truncated", v.AsString())
+	}
+	if _, ok := attrValue(spans[0].Attributes(), "http.request.body.size"); ok {
+		t.Error("size of a truncated body of unknown length should be omitted")
+	}
+}
+
+func TestTransport_EndsSpanWithoutWaitingForTheResponseBody(t *testing.T) {
+	agent, recorder := agenttest.NewAgent(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":42}`))
+	}))
+	defer srv.Close()
+
+	agent.Config().HTTP.CaptureResponseBody = true
+	agent.Config().HTTP.BodyAllowedContentTypes = []string{"application/json"}
+
+	resp, err := WrapClient(agent, nil).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// The body is neither read nor closed yet.
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected the span to end with RoundTrip, got %d spans", len(spans))
+	}
+	if v, _ := attrValue(spans[0].Attributes(), "http.response.body"); v.AsString() != `{"id":42}` {
+		t.Errorf("http.response.body = %q", v.AsString())
+	}
+	if body, _ := io.ReadAll(resp.Body); string(body) != `{"id":42}` {
+		t.Errorf("response body altered for caller: %q", body)
+	}
+}
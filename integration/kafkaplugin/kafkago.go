@@ -0,0 +1,133 @@
+package kafkaplugin
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/instrumentor"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// kafkaGoHeaderCarrier adapts kafka-go message headers for OTel propagation.
+type kafkaGoHeaderCarrier struct {
+	headers *[]kafka.Header
+}
+
+func (c kafkaGoHeaderCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c kafkaGoHeaderCarrier) Set(key, value string) {
+	for i, h := range *c.headers {
+		if h.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c kafkaGoHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.headers))
+	for _, h := range *c.headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
+
+// MessageWriter is the subset of *kafka.Writer used by WriteMessages.
+type MessageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// InjectContext injects trace context into a kafka-go message's headers.
+func InjectContext(ctx context.Context, msg *kafka.Message) {
+	instrumentor.InjectContext(ctx, kafkaGoHeaderCarrier{headers: &msg.Headers})
+}
+
+// ExtractContext extracts trace context from a kafka-go message's headers.
+func ExtractContext(ctx context.Context, msg kafka.Message) context.Context {
+	if len(msg.Headers) == 0 {
+		return ctx
+	}
+	return instrumentor.ExtractContext(ctx, kafkaGoHeaderCarrier{headers: &msg.Headers})
+}
+
+// WriteMessages writes msgs with one PRODUCER span per message, injecting each
+// span's context into the message headers, and records the publish duration.
+func WriteMessages(ctx context.Context, agent *otelagent.Agent, w MessageWriter, msgs ...kafka.Message) error {
+	if !enabled(agent) || len(msgs) == 0 {
+		return w.WriteMessages(ctx, msgs...)
+	}
+
+	defaultTopic := ""
+	if kw, ok := w.(*kafka.Writer); ok {
+		defaultTopic = kw.Topic
+	}
+
+	// Headers are added to copies: the caller's messages, and the header
+	// arrays they may share, are left untouched.
+	msgs = slices.Clone(msgs)
+	spans := make([]trace.Span, len(msgs))
+	for i := range msgs {
+		msgs[i].Headers = slices.Clone(msgs[i].Headers)
+		topic := msgs[i].Topic
+		if topic == "" {
+			topic = defaultTopic
+		}
+		spanCtx, span := startProducerSpan(ctx, agent, topic, msgs[i].Key)
+		if len(msgs) > 1 {
+			span.SetAttributes(attribute.Int("messaging.batch.message_count", len(msgs)))
+		}
+		InjectContext(spanCtx, &msgs[i])
+		spans[i] = span
+	}
+
+	start := time.Now()
+	err := w.WriteMessages(ctx, msgs...)
+
+	topic := msgs[0].Topic
+	if topic == "" {
+		topic = defaultTopic
+	}
+	recordDuration(ctx, getInstruments(agent).publishDuration, start, "send", topic, "", err)
+	for _, span := range spans {
+		endSpan(span, err)
+	}
+	return err
+}
+
+// StartConsumeSpan starts a CONSUMER span for a kafka-go message, parented to
+// the producer's context from the headers. Caller must call span.End().
+func StartConsumeSpan(ctx context.Context, agent *otelagent.Agent, msg kafka.Message, group string) (context.Context, trace.Span) {
+	if !enabled(agent) {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	ctx = ExtractContext(ctx, msg)
+	return startConsumerSpan(ctx, agent, msg.Topic, msg.Partition, msg.Offset, msg.Key, group)
+}
+
+// ProcessMessage runs handler inside a CONSUMER span for msg, recording
+// errors on the span and the processing duration histogram.
+func ProcessMessage(ctx context.Context, agent *otelagent.Agent, msg kafka.Message, group string, handler func(context.Context) error) error {
+	if !enabled(agent) {
+		return handler(ctx)
+	}
+
+	ctx, span := StartConsumeSpan(ctx, agent, msg, group)
+	return process(ctx, agent, span, msg.Topic, group, handler)
+}
+
+// Ensure kafkaGoHeaderCarrier implements propagation.TextMapCarrier.
+var _ propagation.TextMapCarrier = kafkaGoHeaderCarrier{}
@@ -0,0 +1,132 @@
+// Package kafkaplugin provides trace context propagation, messaging spans and
+// publish/consume duration metrics for segmentio/kafka-go and IBM/sarama.
+package kafkaplugin
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/scrub"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const scopeName = "github.com/RodolfoBonis/go-otel-agent/integration/kafkaplugin"
+
+// instruments holds the duration histograms for one agent.
+type instruments struct {
+	publishDuration metric.Float64Histogram
+	consumeDuration metric.Float64Histogram
+}
+
+// instrumentCache maps *otelagent.Agent -> *instruments. Instruments are only
+// cached once the agent is running so calls made before Init do not pin the
+// noop meter.
+var instrumentCache sync.Map
+
+func enabled(agent *otelagent.Agent) bool {
+	return agent != nil && agent.IsEnabled() && agent.Config().Features.AutoKafka
+}
+
+func getInstruments(agent *otelagent.Agent) *instruments {
+	if cached, ok := instrumentCache.Load(agent); ok {
+		return cached.(*instruments)
+	}
+
+	meter := agent.GetMeter(scopeName)
+	inst := &instruments{}
+	inst.publishDuration, _ = meter.Float64Histogram(
+		"messaging.client.operation.duration",
+		metric.WithDescription("Duration of Kafka publish operations"),
+		metric.WithUnit("s"),
+	)
+	inst.consumeDuration, _ = meter.Float64Histogram(
+		"messaging.process.duration",
+		metric.WithDescription("Duration of Kafka message processing"),
+		metric.WithUnit("s"),
+	)
+
+	if agent.IsRunning() {
+		instrumentCache.Store(agent, inst)
+	}
+	return inst
+}
+
+// messageAttrs returns the messaging semconv attributes shared by producer
+// and consumer spans. Negative partition/offset values are omitted. The
+// message key is only recorded, scrubbed, with Scrub.CaptureMessagingKeys.
+func messageAttrs(agent *otelagent.Agent, operation, topic string, partition int, offset int64, key []byte) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("messaging.system", "kafka"),
+		attribute.String("messaging.destination.name", topic),
+		attribute.String("messaging.operation.type", operation),
+	}
+	if partition >= 0 {
+		attrs = append(attrs, attribute.String("messaging.destination.partition.id", strconv.Itoa(partition)))
+	}
+	if offset >= 0 {
+		attrs = append(attrs, attribute.Int64("messaging.kafka.offset", offset))
+	}
+	if len(key) > 0 && agent.Config().Scrub.CaptureMessagingKeys {
+		attrs = append(attrs, attribute.String("messaging.kafka.message.key", scrub.String(string(key))))
+	}
+	return attrs
+}
+
+// Spans use the global TracerProvider, resolved per call, so helpers used
+// before agent.Init() still emit spans once the agent is running.
+func startProducerSpan(ctx context.Context, agent *otelagent.Agent, topic string, key []byte) (context.Context, trace.Span) {
+	return otel.GetTracerProvider().Tracer(scopeName).Start(ctx, "send "+topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(messageAttrs(agent, "send", topic, -1, -1, key)...),
+	)
+}
+
+func startConsumerSpan(ctx context.Context, agent *otelagent.Agent, topic string, partition int, offset int64, key []byte, group string) (context.Context, trace.Span) {
+	attrs := messageAttrs(agent, "process", topic, partition, offset, key)
+	if group != "" {
+		attrs = append(attrs, attribute.String("messaging.consumer.group.name", group))
+	}
+	return otel.GetTracerProvider().Tracer(scopeName).Start(ctx, "process "+topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func recordDuration(ctx context.Context, h metric.Float64Histogram, start time.Time, operation, topic, group string, err error) {
+	attrs := []attribute.KeyValue{
+		attribute.String("messaging.system", "kafka"),
+		attribute.String("messaging.destination.name", topic),
+		attribute.String("messaging.operation.name", operation),
+	}
+	if group != "" {
+		attrs = append(attrs, attribute.String("messaging.consumer.group.name", group))
+	}
+	if err != nil {
+		attrs = append(attrs, attribute.String("error.type", "_OTHER"))
+	}
+	h.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// process runs handler inside a consumer span and records its duration.
+func process(ctx context.Context, agent *otelagent.Agent, span trace.Span, topic, group string, handler func(context.Context) error) error {
+	start := time.Now()
+	err := handler(ctx)
+	recordDuration(ctx, getInstruments(agent).consumeDuration, start, "process", topic, group, err)
+	endSpan(span, err)
+	return err
+}
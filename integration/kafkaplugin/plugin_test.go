@@ -0,0 +1,183 @@
+package kafkaplugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/instrumentor/carriertest"
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func attrValue(attrs []attribute.KeyValue, key string) (attribute.Value, bool) {
+	for _, a := range attrs {
+		if string(a.Key) == key {
+			return a.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+type fakeWriter struct {
+	written []kafka.Message
+	err     error
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	w.written = append(w.written, msgs...)
+	return w.err
+}
+
+type fakeSender struct {
+	sent *sarama.ProducerMessage
+}
+
+func (s *fakeSender) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	s.sent = msg
+	return 3, 42, nil
+}
+
+func TestWriteMessages_PropagatesToConsumer(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+	agent := agenttest.NewUninitialized()
+
+	w := &fakeWriter{}
+	if err := WriteMessages(context.Background(), agent, w, kafka.Message{Topic: "orders", Key: []byte("k1")}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if len(w.written) != 1 || len(w.written[0].Headers) == 0 {
+		t.Fatal("expected trace headers on written message")
+	}
+
+	consumed := w.written[0]
+	consumed.Partition, consumed.Offset = 2, 7
+	err := ProcessMessage(context.Background(), agent, consumed, "billing", func(ctx context.Context) error {
+		return errors.New("handler failed")
+	})
+	if err == nil {
+		t.Fatal("expected handler error to be returned")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	producer, consumer := spans[0], spans[1]
+	if producer.SpanKind() != trace.SpanKindProducer || consumer.SpanKind() != trace.SpanKindConsumer {
+		t.Errorf("span kinds = %v, %v", producer.SpanKind(), consumer.SpanKind())
+	}
+	if consumer.Parent().SpanID() != producer.SpanContext().SpanID() {
+		t.Error("consumer span is not parented to the producer span")
+	}
+	if v, _ := attrValue(consumer.Attributes(), "messaging.consumer.group.name"); v.AsString() != "billing" {
+		t.Errorf("consumer group = %q, want billing", v.AsString())
+	}
+	if v, _ := attrValue(consumer.Attributes(), "messaging.kafka.offset"); v.AsInt64() != 7 {
+		t.Errorf("offset = %d, want 7", v.AsInt64())
+	}
+	if consumer.Status().Code.String() != "Error" {
+		t.Errorf("expected error status on consumer span, got %v", consumer.Status().Code)
+	}
+}
+
+func TestWriteMessages_LeavesCallerMessagesUntouched(t *testing.T) {
+	agenttest.RecordSpans(t)
+
+	// Spare capacity: appending a header in place would write into it.
+	headers := make([]kafka.Header, 1, 4)
+	headers[0] = kafka.Header{Key: "tenant", Value: []byte("acme")}
+	msgs := []kafka.Message{{Topic: "orders", Headers: headers}}
+
+	w := &fakeWriter{}
+	if err := WriteMessages(context.Background(), agenttest.NewUninitialized(), w, msgs...); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if len(w.written[0].Headers) < 2 {
+		t.Fatal("expected trace headers on written message")
+	}
+	if len(msgs[0].Headers) != 1 || headers[:2][1].Key != "" {
+		t.Errorf("caller's message was modified: %v", headers[:2])
+	}
+}
+
+func TestMessageKey_OnlyRecordedWhenEnabledAndScrubbed(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+	msg := kafka.Message{Topic: "orders", Key: []byte("token=abc")}
+
+	_ = WriteMessages(context.Background(), agenttest.NewUninitialized(), &fakeWriter{}, msg)
+	_ = WriteMessages(context.Background(), agenttest.NewUninitialized(otelagent.WithMessagingKeyCapture(true)), &fakeWriter{}, msg)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if v, ok := attrValue(spans[0].Attributes(), "messaging.kafka.message.key"); ok {
+		t.Errorf("key recorded by default: %q", v.AsString())
+	}
+	if v, _ := attrValue(spans[1].Attributes(), "messaging.kafka.message.key"); v.AsString() != "[REDACTED]" {
+		t.Errorf("key = %q, want it scrubbed", v.AsString())
+	}
+}
+
+func TestWriteMessages_DisabledAgentPassesThrough(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+
+	w := &fakeWriter{}
+	_ = WriteMessages(context.Background(), agenttest.NewUninitialized(otelagent.WithEnabled(false)), w, kafka.Message{Topic: "orders"})
+
+	if len(w.written[0].Headers) != 0 {
+		t.Error("expected no headers for disabled agent")
+	}
+	if n := len(recorder.Ended()); n != 0 {
+		t.Errorf("expected no spans, got %d", n)
+	}
+}
+
+func TestSendSaramaMessage_RecordsPartitionAndPropagates(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+	agent := agenttest.NewUninitialized()
+
+	sender := &fakeSender{}
+	msg := &sarama.ProducerMessage{Topic: "payments", Value: sarama.StringEncoder("{}")}
+	if _, _, err := SendSaramaMessage(context.Background(), agent, sender, msg); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+
+	headers := make([]*sarama.RecordHeader, len(sender.sent.Headers))
+	for i := range sender.sent.Headers {
+		headers[i] = &sender.sent.Headers[i]
+	}
+	ctx := ExtractSaramaContext(context.Background(), &sarama.ConsumerMessage{Topic: "payments", Headers: headers})
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if trace.SpanContextFromContext(ctx).TraceID() != spans[0].SpanContext().TraceID() {
+		t.Error("extracted trace ID does not match producer span")
+	}
+	if v, _ := attrValue(spans[0].Attributes(), "messaging.destination.partition.id"); v.AsString() != "3" {
+		t.Errorf("partition = %q, want 3", v.AsString())
+	}
+	if v, _ := attrValue(spans[0].Attributes(), "messaging.kafka.offset"); v.AsInt64() != 42 {
+		t.Errorf("offset = %d, want 42", v.AsInt64())
+	}
+}
+
+func TestKafkaGoHeaderCarrier_Conformance(t *testing.T) {
+	carriertest.Run(t, func() propagation.TextMapCarrier {
+		return kafkaGoHeaderCarrier{headers: &[]kafka.Header{}}
+	})
+}
+
+func TestSaramaProducerCarrier_Conformance(t *testing.T) {
+	carriertest.Run(t, func() propagation.TextMapCarrier {
+		return saramaProducerCarrier{msg: &sarama.ProducerMessage{}}
+	})
+}
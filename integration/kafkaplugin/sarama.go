@@ -0,0 +1,146 @@
+package kafkaplugin
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/instrumentor"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// saramaProducerCarrier adapts sarama producer message headers.
+type saramaProducerCarrier struct {
+	msg *sarama.ProducerMessage
+}
+
+func (c saramaProducerCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c saramaProducerCarrier) Set(key, value string) {
+	for i, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (c saramaProducerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		keys = append(keys, string(h.Key))
+	}
+	return keys
+}
+
+// saramaConsumerCarrier adapts sarama consumer message headers (read-only).
+type saramaConsumerCarrier []*sarama.RecordHeader
+
+func (c saramaConsumerCarrier) Get(key string) string {
+	for _, h := range c {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c saramaConsumerCarrier) Set(string, string) {}
+
+func (c saramaConsumerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for _, h := range c {
+		if h != nil {
+			keys = append(keys, string(h.Key))
+		}
+	}
+	return keys
+}
+
+// SaramaSender is the subset of sarama.SyncProducer used by SendSaramaMessage.
+type SaramaSender interface {
+	SendMessage(msg *sarama.ProducerMessage) (partition int32, offset int64, err error)
+}
+
+// InjectSaramaContext injects trace context into a sarama producer message.
+func InjectSaramaContext(ctx context.Context, msg *sarama.ProducerMessage) {
+	instrumentor.InjectContext(ctx, saramaProducerCarrier{msg: msg})
+}
+
+// ExtractSaramaContext extracts trace context from a sarama consumer message.
+func ExtractSaramaContext(ctx context.Context, msg *sarama.ConsumerMessage) context.Context {
+	if msg == nil || len(msg.Headers) == 0 {
+		return ctx
+	}
+	return instrumentor.ExtractContext(ctx, saramaConsumerCarrier(msg.Headers))
+}
+
+// SendSaramaMessage sends msg through a sync producer inside a PRODUCER span,
+// injecting trace context into its headers and recording the partition,
+// offset and publish duration.
+func SendSaramaMessage(ctx context.Context, agent *otelagent.Agent, p SaramaSender, msg *sarama.ProducerMessage) (int32, int64, error) {
+	if !enabled(agent) {
+		return p.SendMessage(msg)
+	}
+
+	var key []byte
+	if msg.Key != nil {
+		key, _ = msg.Key.Encode()
+	}
+
+	ctx, span := startProducerSpan(ctx, agent, msg.Topic, key)
+	InjectSaramaContext(ctx, msg)
+
+	start := time.Now()
+	partition, offset, err := p.SendMessage(msg)
+	recordDuration(ctx, getInstruments(agent).publishDuration, start, "send", msg.Topic, "", err)
+
+	if err == nil {
+		span.SetAttributes(
+			attribute.String("messaging.destination.partition.id", strconv.FormatInt(int64(partition), 10)),
+			attribute.Int64("messaging.kafka.offset", offset),
+		)
+	}
+	endSpan(span, err)
+	return partition, offset, err
+}
+
+// StartSaramaConsumeSpan starts a CONSUMER span for a sarama message, parented
+// to the producer's context from the headers. Caller must call span.End().
+func StartSaramaConsumeSpan(ctx context.Context, agent *otelagent.Agent, msg *sarama.ConsumerMessage, group string) (context.Context, trace.Span) {
+	if !enabled(agent) || msg == nil {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	ctx = ExtractSaramaContext(ctx, msg)
+	return startConsumerSpan(ctx, agent, msg.Topic, int(msg.Partition), msg.Offset, msg.Key, group)
+}
+
+// ProcessSaramaMessage runs handler inside a CONSUMER span for msg, recording
+// errors on the span and the processing duration histogram.
+func ProcessSaramaMessage(ctx context.Context, agent *otelagent.Agent, msg *sarama.ConsumerMessage, group string, handler func(context.Context) error) error {
+	if !enabled(agent) || msg == nil {
+		return handler(ctx)
+	}
+
+	ctx, span := StartSaramaConsumeSpan(ctx, agent, msg, group)
+	return process(ctx, agent, span, msg.Topic, group, handler)
+}
+
+// Ensure the sarama carriers implement propagation.TextMapCarrier.
+var (
+	_ propagation.TextMapCarrier = saramaProducerCarrier{}
+	_ propagation.TextMapCarrier = saramaConsumerCarrier(nil)
+)
@@ -0,0 +1,294 @@
+package sqlplugin
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+)
+
+var (
+	_ driver.Driver        = (*otelDriver)(nil)
+	_ driver.DriverContext = (*otelDriver)(nil)
+	_ driver.Connector     = (*otelConnector)(nil)
+	_ driver.Connector     = (*dsnConnector)(nil)
+
+	_ driver.Conn               = (*otelConn)(nil)
+	_ driver.ConnPrepareContext = (*otelConn)(nil)
+	_ driver.ConnBeginTx        = (*otelConn)(nil)
+	_ driver.ExecerContext      = (*otelConn)(nil)
+	_ driver.QueryerContext     = (*otelConn)(nil)
+	_ driver.Pinger             = (*otelConn)(nil)
+	_ driver.SessionResetter    = (*otelConn)(nil)
+	_ driver.Validator          = (*otelConn)(nil)
+	_ driver.NamedValueChecker  = (*otelConn)(nil)
+
+	_ driver.Stmt              = (*otelStmt)(nil)
+	_ driver.StmtExecContext   = (*otelStmt)(nil)
+	_ driver.StmtQueryContext  = (*otelStmt)(nil)
+	_ driver.NamedValueChecker = (*otelStmt)(nil)
+
+	_ driver.Tx = (*otelTx)(nil)
+)
+
+// otelDriver wraps a driver.Driver so every connection it opens is traced.
+type otelDriver struct {
+	driver.Driver
+	cfg *instrumentConfig
+}
+
+func (d *otelDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &otelConn{Conn: conn, cfg: d.cfg}, nil
+}
+
+func (d *otelDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.Driver.(driver.DriverContext); ok {
+		connector, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return &otelConnector{Connector: connector, driver: d, cfg: d.cfg}, nil
+	}
+	return &dsnConnector{dsn: name, driver: d}, nil
+}
+
+// otelConnector wraps a driver.Connector.
+type otelConnector struct {
+	driver.Connector
+	driver *otelDriver
+	cfg    *instrumentConfig
+}
+
+func (c *otelConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &otelConn{Conn: conn, cfg: c.cfg}, nil
+}
+
+func (c *otelConnector) Driver() driver.Driver { return c.driver }
+
+// dsnConnector adapts a driver without DriverContext support to a Connector.
+type dsnConnector struct {
+	dsn    string
+	driver *otelDriver
+}
+
+func (c *dsnConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *dsnConnector) Driver() driver.Driver { return c.driver }
+
+// otelConn traces queries, statements and transactions on a driver.Conn.
+// Optional interfaces the underlying connection lacks are reported through
+// driver.ErrSkip so database/sql falls back exactly as it would unwrapped.
+type otelConn struct {
+	driver.Conn
+	cfg *instrumentConfig
+}
+
+func (c *otelConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *otelConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	_, span := c.cfg.startSpan(ctx, "Prepare", query)
+
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if pc, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = pc.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return &otelStmt{Stmt: stmt, query: query, cfg: c.cfg}, nil
+}
+
+func (c *otelConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *otelConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	_, span := c.cfg.startSpan(ctx, "Begin", "")
+
+	var (
+		tx  driver.Tx
+		err error
+	)
+	if bt, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = bt.BeginTx(ctx, opts)
+	} else {
+		//nolint:staticcheck // fallback for drivers without BeginTx
+		tx, err = c.Conn.Begin()
+	}
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return &otelTx{Tx: tx, ctx: ctx, cfg: c.cfg}, nil
+}
+
+func (c *otelConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := c.cfg.startSpan(ctx, "Exec", query)
+	res, err := execer.ExecContext(ctx, query, args)
+	endSpan(span, err)
+	return res, err
+}
+
+func (c *otelConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := c.cfg.startSpan(ctx, "Query", query)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	endSpan(span, err)
+	return rows, err
+}
+
+func (c *otelConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *otelConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *otelConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *otelConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// otelStmt traces executions of a prepared statement.
+type otelStmt struct {
+	driver.Stmt
+	query string
+	cfg   *instrumentConfig
+}
+
+func (s *otelStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), valuesToNamed(args))
+}
+
+func (s *otelStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), valuesToNamed(args))
+}
+
+func (s *otelStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	ctx, span := s.cfg.startSpan(ctx, "Exec", s.query)
+
+	var (
+		res driver.Result
+		err error
+	)
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = execer.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			//nolint:staticcheck // fallback for drivers without StmtExecContext
+			res, err = s.Stmt.Exec(values)
+		}
+	}
+	endSpan(span, err)
+	return res, err
+}
+
+func (s *otelStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, span := s.cfg.startSpan(ctx, "Query", s.query)
+
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			//nolint:staticcheck // fallback for drivers without StmtQueryContext
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	endSpan(span, err)
+	return rows, err
+}
+
+func (s *otelStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// otelTx traces Commit and Rollback using the context the transaction began with.
+type otelTx struct {
+	driver.Tx
+	ctx context.Context
+	cfg *instrumentConfig
+}
+
+func (t *otelTx) Commit() error {
+	_, span := t.cfg.startSpan(t.ctx, "Commit", "")
+	err := t.Tx.Commit()
+	endSpan(span, err)
+	return err
+}
+
+func (t *otelTx) Rollback() error {
+	_, span := t.cfg.startSpan(t.ctx, "Rollback", "")
+	err := t.Tx.Rollback()
+	endSpan(span, err)
+	return err
+}
+
+func valuesToNamed(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return named
+}
+
+func namedToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, nv := range args {
+		if nv.Name != "" {
+			return nil, errors.New("sqlplugin: driver does not support named parameters")
+		}
+		values[i] = nv.Value
+	}
+	return values, nil
+}
@@ -0,0 +1,215 @@
+package sqlplugin
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/provider"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const scopeName = "github.com/RodolfoBonis/go-otel-agent/integration/sqlplugin"
+
+// Option configures database/sql instrumentation.
+type Option func(*instrumentConfig)
+
+type instrumentConfig struct {
+	dbSystem string
+	dbName   string
+	poolName string
+	attrs    []attribute.KeyValue
+	scrub    otelagent.ScrubConfig
+}
+
+// WithDBSystem sets the db.system.name attribute (e.g. "postgresql", "mysql").
+// Defaults to the driver name passed to Open.
+func WithDBSystem(system string) Option {
+	return func(cfg *instrumentConfig) {
+		cfg.dbSystem = system
+	}
+}
+
+// WithDBName adds the db.namespace attribute to every DB span.
+func WithDBName(name string) Option {
+	return func(cfg *instrumentConfig) {
+		cfg.dbName = name
+	}
+}
+
+// WithPoolName sets the name under which connection-pool gauges are
+// reported. Defaults to the DB name, then the DB system.
+func WithPoolName(name string) Option {
+	return func(cfg *instrumentConfig) {
+		cfg.poolName = name
+	}
+}
+
+// WithAttributes adds static attributes to every DB span.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(cfg *instrumentConfig) {
+		cfg.attrs = append(cfg.attrs, attrs...)
+	}
+}
+
+// Open opens a database like sql.Open, wrapping the registered driver with
+// tracing and registering connection-pool gauges with the agent.
+func Open(agent *otelagent.Agent, driverName, dsn string, opts ...Option) (*sql.DB, error) {
+	if !enabled(agent) {
+		return sql.Open(driverName, dsn)
+	}
+
+	// sql.Open does not connect; it is only used to resolve the driver.
+	probe, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	d := probe.Driver()
+	_ = probe.Close()
+
+	cfg := newConfig(agent, driverName, opts)
+	wrapped := &otelDriver{Driver: d, cfg: cfg}
+
+	var db *sql.DB
+	if dc, ok := d.(driver.DriverContext); ok {
+		connector, err := dc.OpenConnector(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open connector for %s: %w", driverName, err)
+		}
+		db = sql.OpenDB(&otelConnector{Connector: connector, driver: wrapped, cfg: cfg})
+	} else {
+		db = sql.OpenDB(&dsnConnector{dsn: dsn, driver: wrapped})
+	}
+
+	registerPool(agent, cfg, db)
+	return db, nil
+}
+
+// OpenDB wraps an existing driver.Connector, like sql.OpenDB.
+func OpenDB(agent *otelagent.Agent, connector driver.Connector, opts ...Option) *sql.DB {
+	if !enabled(agent) {
+		return sql.OpenDB(connector)
+	}
+
+	cfg := newConfig(agent, "", opts)
+	wrapped := &otelDriver{Driver: connector.Driver(), cfg: cfg}
+	db := sql.OpenDB(&otelConnector{Connector: connector, driver: wrapped, cfg: cfg})
+
+	registerPool(agent, cfg, db)
+	return db
+}
+
+// WrapDriver returns an instrumented driver.Driver, for use with
+// sql.Register when the caller manages registration itself.
+func WrapDriver(agent *otelagent.Agent, d driver.Driver, opts ...Option) driver.Driver {
+	if !enabled(agent) {
+		return d
+	}
+	return &otelDriver{Driver: d, cfg: newConfig(agent, "", opts)}
+}
+
+// RegisterDBStats reports the pool statistics of db through the agent's
+// SystemCollector. Open and OpenDB call this automatically.
+func RegisterDBStats(agent *otelagent.Agent, name string, db *sql.DB) {
+	if agent == nil || !agent.IsEnabled() || !agent.Config().Metrics.Database {
+		return
+	}
+	agent.RegisterDBStats(name, db.Stats)
+}
+
+func enabled(agent *otelagent.Agent) bool {
+	return agent != nil && agent.IsEnabled() && agent.Config().Features.AutoDatabase
+}
+
+func newConfig(agent *otelagent.Agent, driverName string, opts []Option) *instrumentConfig {
+	cfg := &instrumentConfig{
+		dbSystem: driverName,
+		scrub:    agent.Config().Scrub,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+func registerPool(agent *otelagent.Agent, cfg *instrumentConfig, db *sql.DB) {
+	name := cfg.poolName
+	if name == "" {
+		name = cfg.dbName
+	}
+	if name == "" {
+		name = cfg.dbSystem
+	}
+	if name == "" {
+		name = "default"
+	}
+	RegisterDBStats(agent, name, db)
+}
+
+// startSpan starts a CLIENT span for a database operation. The tracer is
+// resolved on every call so spans use the real provider regardless of
+// initialization order (same approach as gormplugin).
+func (cfg *instrumentConfig) startSpan(ctx context.Context, op, query string) (context.Context, trace.Span) {
+	attrs := make([]attribute.KeyValue, 0, len(cfg.attrs)+8)
+	if cfg.dbSystem != "" {
+		attrs = append(attrs,
+			attribute.String("db.system.name", cfg.dbSystem),
+			attribute.String("db.system", cfg.dbSystem),
+		)
+	}
+	if cfg.dbName != "" {
+		attrs = append(attrs,
+			attribute.String("db.namespace", cfg.dbName),
+			attribute.String("db.name", cfg.dbName),
+		)
+	}
+
+	spanName := "sql." + op
+	if query != "" {
+		stmt := provider.DBStatement(query, cfg.scrub)
+		attrs = append(attrs,
+			attribute.String("db.query.text", stmt),
+			attribute.String("db.statement", stmt),
+		)
+		if operation := queryOperation(query); operation != "" {
+			attrs = append(attrs,
+				attribute.String("db.operation.name", operation),
+				attribute.String("db.operation", operation),
+			)
+			// Executions are named after the statement; Prepare keeps "sql.Prepare".
+			if op == "Exec" || op == "Query" {
+				spanName = operation
+			}
+		}
+	}
+	attrs = append(attrs, cfg.attrs...)
+
+	return otel.GetTracerProvider().Tracer(scopeName).Start(ctx, spanName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// endSpan records err (ignoring driver.ErrSkip and sql.ErrNoRows) and ends span.
+func endSpan(span trace.Span, err error) {
+	if err != nil && err != driver.ErrSkip && err != sql.ErrNoRows {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// queryOperation returns the uppercased leading SQL keyword (SELECT, INSERT...).
+func queryOperation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,220 @@
+package sqlplugin
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const fakeDriverName = "sqlplugin-fake"
+
+func init() {
+	sql.Register(fakeDriverName, fakeDriver{})
+}
+
+// fakeDriver is a minimal context-aware driver that returns empty results.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if strings.HasPrefix(query, "FAIL") {
+		return nil, errors.New("boom")
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return fakeRows{}, nil
+}
+
+type fakeStmt struct{}
+
+func (fakeStmt) Close() error                               { return nil }
+func (fakeStmt) NumInput() int                              { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (fakeStmt) Query([]driver.Value) (driver.Rows, error)  { return fakeRows{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string         { return []string{"id"} }
+func (fakeRows) Close() error              { return nil }
+func (fakeRows) Next([]driver.Value) error { return io.EOF }
+
+func attrValue(attrs []attribute.KeyValue, key string) (attribute.Value, bool) {
+	for _, a := range attrs {
+		if string(a.Key) == key {
+			return a.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestOpen_DisabledAgentReturnsUninstrumentedDB(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+
+	db, err := Open(agenttest.NewUninitialized(otelagent.WithEnabled(false)), fakeDriverName, "")
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("INSERT INTO t VALUES (1)"); err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	if n := len(recorder.Ended()); n != 0 {
+		t.Errorf("expected no spans for disabled agent, got %d", n)
+	}
+}
+
+func TestOpen_TracesExecAndQuery(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+
+	db, err := Open(agenttest.NewUninitialized(), fakeDriverName, "", WithDBSystem("postgresql"), WithDBName("orders"))
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.ExecContext(context.Background(), "INSERT INTO orders VALUES (1)"); err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	rows, err := db.QueryContext(context.Background(), "select id from orders")
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	_ = rows.Close()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	if spans[0].Name() != "INSERT" || spans[1].Name() != "SELECT" {
+		t.Errorf("span names = %q, %q, want INSERT, SELECT", spans[0].Name(), spans[1].Name())
+	}
+	span := spans[1]
+	if span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("span kind = %v, want client", span.SpanKind())
+	}
+	if v, _ := attrValue(span.Attributes(), "db.system.name"); v.AsString() != "postgresql" {
+		t.Errorf("db.system.name = %q, want postgresql", v.AsString())
+	}
+	if v, _ := attrValue(span.Attributes(), "db.namespace"); v.AsString() != "orders" {
+		t.Errorf("db.namespace = %q, want orders", v.AsString())
+	}
+	if v, _ := attrValue(span.Attributes(), "db.statement"); v.AsString() != "select id from orders" {
+		t.Errorf("db.statement = %q", v.AsString())
+	}
+}
+
+func TestOpen_TruncatesStatementAndRecordsErrors(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+
+	agent := agenttest.NewUninitialized()
+	agent.Config().Scrub.DBStatementMaxLength = 10
+
+	db, err := Open(agent, fakeDriverName, "")
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("FAIL THIS VERY LONG STATEMENT"); err == nil {
+		t.Fatal("expected exec error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if v, _ := attrValue(spans[0].Attributes(), "db.statement"); v.AsString() != "FAIL THIS ..." {
+		t.Errorf("db.statement = %q, want truncated", v.AsString())
+	}
+	if spans[0].Status().Code.String() != "Error" {
+		t.Errorf("expected error status, got %v", spans[0].Status().Code)
+	}
+}
+
+func TestOpen_SanitizesStatement(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+
+	agent := agenttest.NewUninitialized()
+	agent.Config().Scrub.SQLSanitize = true
+
+	db, err := Open(agent, fakeDriverName, "")
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("update users set email = 'a@b.com' where id = 7"); err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if v, _ := attrValue(spans[0].Attributes(), "db.statement"); v.AsString() != "update users set email = ? where id = ?" {
+		t.Errorf("db.statement = %q, want literals replaced", v.AsString())
+	}
+	if v, _ := attrValue(spans[0].Attributes(), "db.operation.name"); v.AsString() != "UPDATE" {
+		t.Errorf("db.operation.name = %q, want UPDATE", v.AsString())
+	}
+}
+
+func TestOpen_TracesPreparedStatementsAndTransactions(t *testing.T) {
+	recorder := agenttest.RecordSpans(t)
+
+	db, err := Open(agenttest.NewUninitialized(), fakeDriverName, "")
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+	stmt, err := tx.Prepare("UPDATE orders SET paid = ?")
+	if err != nil {
+		t.Fatalf("prepare failed: %v", err)
+	}
+	if _, err := stmt.Exec(true); err != nil {
+		t.Fatalf("stmt exec failed: %v", err)
+	}
+	_ = stmt.Close()
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+
+	var names []string
+	for _, s := range recorder.Ended() {
+		names = append(names, s.Name())
+	}
+	want := []string{"sql.Begin", "sql.Prepare", "UPDATE", "sql.Commit"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("span names = %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,123 @@
+// Package agenttest provides the fixtures shared by the tests of the
+// integrations: an initialized agent whose spans are kept in memory, or an
+// uninitialized one next to a global TracerProvider recording every span.
+package agenttest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/RodolfoBonis/go-otel-agent/provider"
+	"go.opentelemetry.io/otel"
+	logglobal "go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// protocol is the traces exporter protocol whose exporter is the Spans of
+// the running test.
+const protocol = "agenttest"
+
+var (
+	register sync.Once
+
+	mu      sync.Mutex
+	current *Spans
+)
+
+// Spans collects the spans exported by an agent created with NewAgent.
+type Spans struct {
+	agent *otelagent.Agent
+
+	mu    sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+// Ended flushes the agent and returns the spans exported so far, in export
+// order.
+func (s *Spans) Ended() []sdktrace.ReadOnlySpan {
+	_ = s.agent.ForceFlush(context.Background())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sdktrace.ReadOnlySpan(nil), s.spans...)
+}
+
+func (s *Spans) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.spans = append(s.spans, spans...)
+	return nil
+}
+
+func (s *Spans) Shutdown(context.Context) error { return nil }
+
+// NewAgent creates and initializes an agent (metrics and logs disabled) that
+// exports spans to the returned Spans. Init installs the agent's providers
+// globally; the agent is shut down and the previous global providers are
+// restored on cleanup, so tests using it must not run in parallel.
+func NewAgent(t testing.TB, opts ...otelagent.Option) (*otelagent.Agent, *Spans) {
+	t.Helper()
+	register.Do(func() {
+		provider.RegisterTraceExporterFactory(protocol, func(context.Context, provider.ExporterSettings) (sdktrace.SpanExporter, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return current, nil
+		})
+	})
+
+	agent := otelagent.NewAgent(append([]otelagent.Option{
+		otelagent.WithServiceName("agenttest"),
+		otelagent.WithLogger(&logger.NoopLogger{}),
+		otelagent.WithDisabledSignals(otelagent.SignalMetrics, otelagent.SignalLogs),
+	}, opts...)...)
+	agent.Config().Traces.Exporter.Protocol = protocol
+	spans := &Spans{agent: agent}
+
+	prevTracer, prevMeter := otel.GetTracerProvider(), otel.GetMeterProvider()
+	prevPropagator, prevLogger := otel.GetTextMapPropagator(), logglobal.GetLoggerProvider()
+	t.Cleanup(func() {
+		_ = agent.Shutdown(context.Background())
+		otel.SetTracerProvider(prevTracer)
+		otel.SetMeterProvider(prevMeter)
+		otel.SetTextMapPropagator(prevPropagator)
+		logglobal.SetLoggerProvider(prevLogger)
+	})
+
+	mu.Lock()
+	current = spans
+	mu.Unlock()
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("agenttest: Init: %v", err)
+	}
+	return agent, spans
+}
+
+// NewUninitialized creates an agent (logging nowhere) that is never
+// initialized, so integrations given it use the global providers, as set by
+// RecordSpans.
+func NewUninitialized(opts ...otelagent.Option) *otelagent.Agent {
+	return otelagent.NewAgent(append([]otelagent.Option{
+		otelagent.WithServiceName("agenttest"),
+		otelagent.WithLogger(&logger.NoopLogger{}),
+	}, opts...)...)
+}
+
+// RecordSpans installs a global TracerProvider that records every span and
+// the W3C trace context propagator. The previous ones are restored on
+// cleanup, so tests using it must not run in parallel.
+func RecordSpans(t testing.TB) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	prevTracer, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevTracer)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return recorder
+}
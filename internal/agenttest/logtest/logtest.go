@@ -0,0 +1,43 @@
+// Package logtest provides the log record fixture shared by the tests of the
+// logger packages. It lives apart from agenttest, which imports the agent and
+// so the logger, to stay importable from the logger package's own tests.
+package logtest
+
+import (
+	"context"
+	"sync"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// Exporter keeps the log records it is given.
+type Exporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+// NewLoggerProvider returns a LoggerProvider exporting every record
+// synchronously to the returned Exporter.
+func NewLoggerProvider() (*sdklog.LoggerProvider, *Exporter) {
+	exporter := &Exporter{}
+	return sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter))), exporter
+}
+
+// Records returns the records exported so far.
+func (e *Exporter) Records() []sdklog.Record {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdklog.Record(nil), e.records...)
+}
+
+func (e *Exporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *Exporter) Shutdown(context.Context) error   { return nil }
+func (e *Exporter) ForceFlush(context.Context) error { return nil }
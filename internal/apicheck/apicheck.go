@@ -0,0 +1,184 @@
+// Package apicheck lists the exported API of a package, one declaration per
+// line, so a test can compare the stable packages against golden files and
+// catch changes that would break code built against an earlier release.
+package apicheck
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Exported returns the exported declarations of the package in dir, sorted:
+// functions, methods of exported types, types with their exported fields and
+// interface methods, constants and variables. Parameter names are left out,
+// since renaming one does not break callers.
+func Exported(dir string) ([]string, error) {
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, fmt.Errorf("apicheck: failed to load package in %s: %w", dir, err)
+	}
+
+	fset := token.NewFileSet()
+	l := lister{fset: fset}
+	for _, name := range pkg.GoFiles {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("apicheck: failed to parse %s: %w", name, err)
+		}
+		for _, decl := range file.Decls {
+			l.decl(decl)
+		}
+	}
+
+	sort.Strings(l.lines)
+	return l.lines, nil
+}
+
+type lister struct {
+	fset  *token.FileSet
+	lines []string
+}
+
+func (l *lister) add(format string, args ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func (l *lister) decl(decl ast.Decl) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if !d.Name.IsExported() {
+			return
+		}
+		if d.Recv == nil {
+			l.add("func %s%s", d.Name.Name, l.signature(d.Type))
+			return
+		}
+		recv := l.expr(d.Recv.List[0].Type)
+		if base := strings.TrimLeft(recv, "*"); !ast.IsExported(strings.SplitN(base, "[", 2)[0]) {
+			return
+		}
+		l.add("method (%s) %s%s", recv, d.Name.Name, l.signature(d.Type))
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				l.typeSpec(s)
+			case *ast.ValueSpec:
+				l.valueSpec(d.Tok, s)
+			}
+		}
+	}
+}
+
+func (l *lister) typeSpec(s *ast.TypeSpec) {
+	if !s.Name.IsExported() {
+		return
+	}
+	name := s.Name.Name
+	if s.TypeParams != nil {
+		name += "[" + l.fields(s.TypeParams, true) + "]"
+	}
+
+	switch t := s.Type.(type) {
+	case *ast.StructType:
+		l.add("type %s struct", name)
+		for _, f := range t.Fields.List {
+			typ := l.expr(f.Type)
+			if len(f.Names) == 0 {
+				if ast.IsExported(strings.TrimLeft(typ[strings.LastIndex(typ, ".")+1:], "*")) {
+					l.add("field %s.%s embedded", s.Name.Name, typ)
+				}
+				continue
+			}
+			for _, n := range f.Names {
+				if n.IsExported() {
+					l.add("field %s.%s %s", s.Name.Name, n.Name, typ)
+				}
+			}
+		}
+	case *ast.InterfaceType:
+		l.add("type %s interface", name)
+		for _, m := range t.Methods.List {
+			if len(m.Names) == 0 {
+				l.add("method %s.%s embedded", s.Name.Name, l.expr(m.Type))
+				continue
+			}
+			if ft, ok := m.Type.(*ast.FuncType); ok && m.Names[0].IsExported() {
+				l.add("method %s.%s%s", s.Name.Name, m.Names[0].Name, l.signature(ft))
+			}
+		}
+	default:
+		if s.Assign.IsValid() {
+			l.add("type %s = %s", name, l.expr(s.Type))
+		} else {
+			l.add("type %s %s", name, l.expr(s.Type))
+		}
+	}
+}
+
+func (l *lister) valueSpec(tok token.Token, s *ast.ValueSpec) {
+	for _, n := range s.Names {
+		if !n.IsExported() {
+			continue
+		}
+		if s.Type != nil {
+			l.add("%s %s %s", tok, n.Name, l.expr(s.Type))
+		} else {
+			l.add("%s %s", tok, n.Name)
+		}
+	}
+}
+
+// signature formats a function type without the func keyword and without
+// parameter names: "(context.Context, string) error".
+func (l *lister) signature(ft *ast.FuncType) string {
+	var b strings.Builder
+	if ft.TypeParams != nil {
+		b.WriteString("[" + l.fields(ft.TypeParams, true) + "]")
+	}
+	b.WriteString("(" + l.fields(ft.Params, false) + ")")
+	if ft.Results != nil && len(ft.Results.List) > 0 {
+		results := l.fields(ft.Results, false)
+		if len(ft.Results.List) == 1 && len(ft.Results.List[0].Names) <= 1 {
+			b.WriteString(" " + results)
+		} else {
+			b.WriteString(" (" + results + ")")
+		}
+	}
+	return b.String()
+}
+
+// fields formats a field list as comma-separated types, repeating a type
+// once per name. Type parameters keep their names, which callers use.
+func (l *lister) fields(list *ast.FieldList, names bool) string {
+	var parts []string
+	for _, f := range list.List {
+		typ := l.expr(f.Type)
+		n := max(len(f.Names), 1)
+		for i := range n {
+			if names && len(f.Names) > 0 {
+				parts = append(parts, f.Names[i].Name+" "+typ)
+			} else {
+				parts = append(parts, typ)
+			}
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// expr prints an expression on one line.
+func (l *lister) expr(e ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, l.fset, e); err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
@@ -0,0 +1,103 @@
+package apicheck
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden API files in testdata")
+
+// stablePackages are covered by the semver guarantees: a declaration may be
+// added to them in a minor release, but not removed or changed outside a
+// major one. Packages under x/ are experimental and not listed.
+var stablePackages = map[string]string{
+	"otelagent": "../..",
+	"config":    "../../config",
+	"helper":    "../../helper",
+}
+
+func TestStableAPI(t *testing.T) {
+	for name, dir := range stablePackages {
+		t.Run(name, func(t *testing.T) {
+			lines, err := Exported(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := strings.Join(lines, "\n") + "\n"
+			golden := filepath.Join("testdata", name+".api")
+
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run go test ./internal/apicheck -update to create it)", err)
+			}
+			removed, added := diff(strings.Split(strings.TrimSpace(string(want)), "\n"), lines)
+			for _, line := range removed {
+				t.Errorf("breaking change, removed or changed: %s", line)
+			}
+			for _, line := range added {
+				t.Errorf("new API, not in %s: %s", golden, line)
+			}
+			if len(removed)+len(added) > 0 {
+				t.Log("if the change is intended, run go test ./internal/apicheck -update and commit the golden file")
+			}
+		})
+	}
+}
+
+func TestExported(t *testing.T) {
+	lines, err := Exported("testdata/sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"const Answer",
+		"field Options.Name string",
+		"field Options.Timeout time.Duration",
+		"func Identity[T any](T) T",
+		"func New(string, ...Option) (*Client, error)",
+		"method (*Client) Do(context.Context) error",
+		"method Doer.Do(context.Context) error",
+		"type Client struct",
+		"type Doer interface",
+		"type ID = string",
+		"type Option func(*Options)",
+		"type Options struct",
+		"var ErrClosed error",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Exported() =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// diff returns the lines only in want and the lines only in got.
+func diff(want, got []string) (removed, added []string) {
+	in := func(lines []string) map[string]bool {
+		m := make(map[string]bool, len(lines))
+		for _, l := range lines {
+			m[l] = true
+		}
+		return m
+	}
+	wantSet, gotSet := in(want), in(got)
+	for _, l := range want {
+		if !gotSet[l] {
+			removed = append(removed, l)
+		}
+	}
+	for _, l := range got {
+		if !wantSet[l] {
+			added = append(added, l)
+		}
+	}
+	return removed, added
+}
@@ -0,0 +1,46 @@
+// Package sample exercises every kind of declaration apicheck lists.
+package sample
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const Answer = 42
+
+const internalLimit = 10
+
+var ErrClosed error = errors.New("closed")
+
+type ID = string
+
+type Option func(*Options)
+
+type Options struct {
+	Name    string
+	Timeout time.Duration
+	retries int
+}
+
+type Doer interface {
+	Do(ctx context.Context) error
+}
+
+type Client struct {
+	opts Options
+}
+
+func New(name string, opts ...Option) (*Client, error) {
+	return &Client{}, nil
+}
+
+func Identity[T any](v T) T { return v }
+
+func (c *Client) Do(ctx context.Context) error { return nil }
+
+func (c *Client) close() {}
+
+type helper struct{}
+
+func (helper) Exported() {}
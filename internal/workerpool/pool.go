@@ -0,0 +1,124 @@
+// Package workerpool runs asynchronous telemetry work on a fixed number of
+// goroutines fed by a bounded queue, so bursts of background work cannot
+// grow the number of goroutines or the memory they hold without limit.
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync"
+
+	"github.com/RodolfoBonis/go-otel-agent/helper"
+)
+
+var (
+	// ErrQueueFull is returned by Submit when every worker is busy and the
+	// queue is full. The task is not run.
+	ErrQueueFull = errors.New("go-otel-agent: worker queue is full")
+	// ErrClosed is returned by Submit once the pool is closed.
+	ErrClosed = errors.New("go-otel-agent: worker pool is closed")
+)
+
+// Task is a unit of work run by the pool. Its context is cancelled when
+// Close gives up waiting for the queue to drain.
+type Task func(ctx context.Context)
+
+// Pool runs submitted tasks on a fixed set of workers.
+type Pool struct {
+	tasks  chan Task
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// New starts workers goroutines (at least one) serving a queue of
+// queueSize tasks. With a queue size of 0, tasks are only handed to idle
+// workers.
+func New(workers, queueSize int) *Pool {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		tasks:  make(chan Task, max(queueSize, 0)),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	for range max(workers, 1) {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+// Submit queues task without blocking. When the queue is full it returns
+// ErrQueueFull, leaving the caller to drop, retry or run the work itself.
+func (p *Pool) Submit(task Task) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+	select {
+	case p.tasks <- task:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// QueueDepth returns the number of tasks waiting for a worker.
+func (p *Pool) QueueDepth() int {
+	return len(p.tasks)
+}
+
+// Capacity returns the size of the queue.
+func (p *Pool) Capacity() int {
+	return cap(p.tasks)
+}
+
+// Close stops accepting tasks and waits for the queued and running ones to
+// finish. When ctx is done first, the context of the remaining tasks is
+// cancelled and Close returns ctx.Err() without waiting further.
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for task := range p.tasks {
+		p.run(task)
+	}
+}
+
+// run runs task, recording a panic instead of losing the worker to it.
+func (p *Pool) run(task Task) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			helper.RecordPanic(p.ctx, recovered, debug.Stack())
+		}
+	}()
+	task(p.ctx)
+}
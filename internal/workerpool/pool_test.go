@@ -0,0 +1,89 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool_BoundsQueue(t *testing.T) {
+	p := New(1, 2)
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	if err := p.Submit(func(context.Context) { close(started); <-release }); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	for i := range 2 {
+		if err := p.Submit(func(context.Context) {}); err != nil {
+			t.Fatalf("Submit %d: %v", i, err)
+		}
+	}
+	if got := p.QueueDepth(); got != 2 {
+		t.Errorf("QueueDepth = %d, want 2", got)
+	}
+	if err := p.Submit(func(context.Context) {}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Submit on a full queue = %v, want ErrQueueFull", err)
+	}
+
+	close(release)
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Submit(func(context.Context) {}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Submit after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestPool_CloseDrainsQueue(t *testing.T) {
+	p := New(2, 10)
+	var ran atomic.Int32
+	for range 10 {
+		if err := p.Submit(func(context.Context) { ran.Add(1) }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if ran.Load() != 10 {
+		t.Errorf("ran %d tasks before Close returned, want 10", ran.Load())
+	}
+}
+
+func TestPool_CloseCancelsTasksAtDeadline(t *testing.T) {
+	p := New(1, 1)
+	cancelled := make(chan struct{})
+	started := make(chan struct{})
+	if err := p.Submit(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close = %v, want DeadlineExceeded", err)
+	}
+	<-cancelled
+}
+
+func TestPool_SurvivesPanics(t *testing.T) {
+	p := New(1, 1)
+	if err := p.Submit(func(context.Context) { panic("boom") }); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	for p.Submit(func(context.Context) { close(done) }) != nil {
+		time.Sleep(time.Millisecond)
+	}
+	<-done
+	_ = p.Close(context.Background())
+}
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// BridgeOption configures the OTel log bridge set up by EnableOTelBridge.
+type BridgeOption func(*BridgeConfig)
+
+// BridgeConfig is the configuration of an OTel log bridge. Logger
+// implementations with a bridge of their own apply the BridgeOptions passed
+// to their EnableOTelBridge to one.
+type BridgeConfig struct {
+	// ExportLevels are the level names exported via OTLP, as in
+	// LogsConfig.ExportLevels; empty exports every level.
+	ExportLevels []string
+}
+
+// WithExportLevels exports only entries at the named levels ("debug",
+// "info", "warn", "error"), like OTEL_LOGS_EXPORT_LEVELS. "error" also
+// covers the more severe dpanic, panic and fatal levels. Entries at other
+// levels are still written locally.
+func WithExportLevels(levels ...string) BridgeOption {
+	return func(c *BridgeConfig) {
+		c.ExportLevels = levels
+	}
+}
+
+// NewBridgeConfig applies opts.
+func NewBridgeConfig(opts ...BridgeOption) BridgeConfig {
+	var c BridgeConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// ExportFilter returns a function reporting whether entries at a level are
+// exported. Unknown level names are ignored.
+func (c BridgeConfig) ExportFilter() func(zapcore.Level) bool {
+	if len(c.ExportLevels) == 0 {
+		return func(zapcore.Level) bool { return true }
+	}
+	var exported [zapcore.FatalLevel - zapcore.DebugLevel + 1]bool
+	for _, name := range c.ExportLevels {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "warning" {
+			name = "warn"
+		}
+		l, err := zapcore.ParseLevel(name)
+		if err != nil {
+			continue
+		}
+		exported[l-zapcore.DebugLevel] = true
+		if l == zapcore.ErrorLevel {
+			for more := l + 1; more <= zapcore.FatalLevel; more++ {
+				exported[more-zapcore.DebugLevel] = true
+			}
+		}
+	}
+	return func(level zapcore.Level) bool {
+		if level < zapcore.DebugLevel || level > zapcore.FatalLevel {
+			return false
+		}
+		return exported[level-zapcore.DebugLevel]
+	}
+}
+
+// levelFilterCore passes to core only the entries at exported levels.
+type levelFilterCore struct {
+	zapcore.Core
+	exports func(zapcore.Level) bool
+}
+
+func (c levelFilterCore) Enabled(level zapcore.Level) bool {
+	return c.exports(level) && c.Core.Enabled(level)
+}
+
+func (c levelFilterCore) With(fields []zapcore.Field) zapcore.Core {
+	return levelFilterCore{Core: c.Core.With(fields), exports: c.exports}
+}
+
+func (c levelFilterCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.exports(e.Level) {
+		return ce
+	}
+	return c.Core.Check(e, ce)
+}
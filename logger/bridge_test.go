@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestBridgeConfig_ExportFilter(t *testing.T) {
+	exports := NewBridgeConfig(WithExportLevels("Warning", " error ", "bogus")).ExportFilter()
+	for level, want := range map[zapcore.Level]bool{
+		zapcore.DebugLevel:  false,
+		zapcore.InfoLevel:   false,
+		zapcore.WarnLevel:   true,
+		zapcore.ErrorLevel:  true,
+		zapcore.DPanicLevel: true,
+		zapcore.PanicLevel:  true,
+		zapcore.FatalLevel:  true,
+	} {
+		if got := exports(level); got != want {
+			t.Errorf("exports(%v) = %v, want %v", level, got, want)
+		}
+	}
+
+	all := NewBridgeConfig().ExportFilter()
+	if !all(zapcore.DebugLevel) {
+		t.Error("no export levels should export every level")
+	}
+}
+
+func TestLevelFilterCore_KeepsLocalOutput(t *testing.T) {
+	local, localLogs := observer.New(zapcore.DebugLevel)
+	exported, exportedLogs := observer.New(zapcore.DebugLevel)
+	filtered := levelFilterCore{Core: exported, exports: NewBridgeConfig(WithExportLevels("warn", "error")).ExportFilter()}
+	l := &CustomLogger{logger: zap.New(zapcore.NewTee(local, filtered.With([]zapcore.Field{zap.String("k", "v")})))}
+
+	l.Info(context.Background(), "info")
+	l.Warning(context.Background(), "warn")
+	l.Error(context.Background(), "error")
+
+	if n := localLogs.Len(); n != 3 {
+		t.Errorf("local entries = %d, want 3", n)
+	}
+	if n := exportedLogs.Len(); n != 2 {
+		t.Fatalf("exported entries = %d, want 2", n)
+	}
+	if got := exportedLogs.All()[0]; got.Message != "warn" || got.ContextMap()["k"] != "v" {
+		t.Errorf("first exported entry = %v, want warn with the With field", got)
+	}
+}
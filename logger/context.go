@@ -0,0 +1,43 @@
+package logger
+
+import "context"
+
+// fieldsKey is the context key of the fields added by WithContextFields.
+const fieldsKey contextKey = "fields"
+
+// WithContextFields returns a copy of ctx carrying fields, merged over any
+// fields ctx already carries. Every record logged with the returned context
+// includes them, so a middleware can attach per-request fields (tenant,
+// user ID) once instead of passing them to every log call:
+//
+//	ctx = logger.WithContextFields(ctx, logger.Fields{"tenant": tenant})
+//	log.Info(ctx, "order placed") // carries tenant
+//
+// Fields passed to the log call win over context fields with the same key.
+func WithContextFields(ctx context.Context, fields Fields) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	parent := ContextFields(ctx)
+	if len(parent) == 0 {
+		return context.WithValue(ctx, fieldsKey, fields)
+	}
+	merged := make(Fields, len(parent)+len(fields))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, fieldsKey, merged)
+}
+
+// ContextFields returns the fields added to ctx by WithContextFields, or nil.
+// The map must not be modified.
+func ContextFields(ctx context.Context) Fields {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey).(Fields)
+	return fields
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest/logtest"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithContextFields_MergesAndCallerWins(t *testing.T) {
+	l, logs := newObservedLogger(zapcore.InfoLevel)
+	ctx := WithContextFields(context.Background(), Fields{"tenant": "acme", "user": "u-1"})
+	ctx = WithContextFields(ctx, Fields{"user": "u-2"})
+
+	l.Info(ctx, "msg", Fields{"tenant": "override"})
+
+	got := logs.All()[0].ContextMap()
+	if got["tenant"] != "override" || got["user"] != "u-2" {
+		t.Errorf("fields = %v, want caller tenant and latest context user", got)
+	}
+	if ContextFields(context.Background()) != nil {
+		t.Error("ContextFields of a plain context should be nil")
+	}
+}
+
+func TestEnableOTelBridge_ExportsWithFields(t *testing.T) {
+	provider, exporter := logtest.NewLoggerProvider()
+
+	l, logs := newObservedLogger(zapcore.InfoLevel)
+	withEnv := l.With(Fields{"env": "prod"}).(*CustomLogger)
+	withEnv.EnableOTelBridge(provider)
+	withEnv.Info(context.Background(), "started")
+
+	if got := logs.All()[0].ContextMap()["env"]; got != "prod" {
+		t.Errorf("local env field = %v, want prod", got)
+	}
+	records := exporter.Records()
+	if len(records) != 1 {
+		t.Fatalf("exported %d records, want 1", len(records))
+	}
+	var env string
+	records[0].WalkAttributes(func(kv otellog.KeyValue) bool {
+		if kv.Key == "env" {
+			env = kv.Value.AsString()
+		}
+		return true
+	})
+	if env != "prod" {
+		t.Errorf("exported env attribute = %q, want prod", env)
+	}
+}
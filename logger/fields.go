@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Valuer is implemented by values that compute their logged form on demand.
+// LogValue is only called when the entry is actually written, so expensive
+// representations cost nothing at disabled levels.
+type Valuer interface {
+	LogValue() any
+}
+
+// FieldsBuilder builds Fields whose values are already typed zap fields, so
+// they are written without the per-value type switch (and, for structs,
+// reflection) that plain map values go through. Use it on hot logging paths:
+//
+//	log.Info(ctx, "order placed", logger.NewFields(3).
+//		String("order_id", id).
+//		Int("items", n).
+//		Object("customer", customer). // zapcore.ObjectMarshaler
+//		Build())
+type FieldsBuilder struct {
+	fields Fields
+}
+
+// NewFields returns a builder sized for n fields.
+func NewFields(n int) *FieldsBuilder {
+	return &FieldsBuilder{fields: make(Fields, n)}
+}
+
+func (b *FieldsBuilder) add(f zap.Field) *FieldsBuilder {
+	b.fields[f.Key] = f
+	return b
+}
+
+// String adds a string field.
+func (b *FieldsBuilder) String(key, value string) *FieldsBuilder {
+	return b.add(zap.String(key, value))
+}
+
+// Int adds an int field.
+func (b *FieldsBuilder) Int(key string, value int) *FieldsBuilder {
+	return b.add(zap.Int(key, value))
+}
+
+// Int64 adds an int64 field.
+func (b *FieldsBuilder) Int64(key string, value int64) *FieldsBuilder {
+	return b.add(zap.Int64(key, value))
+}
+
+// Float64 adds a float64 field.
+func (b *FieldsBuilder) Float64(key string, value float64) *FieldsBuilder {
+	return b.add(zap.Float64(key, value))
+}
+
+// Bool adds a bool field.
+func (b *FieldsBuilder) Bool(key string, value bool) *FieldsBuilder {
+	return b.add(zap.Bool(key, value))
+}
+
+// Duration adds a time.Duration field.
+func (b *FieldsBuilder) Duration(key string, value time.Duration) *FieldsBuilder {
+	return b.add(zap.Duration(key, value))
+}
+
+// Time adds a time.Time field.
+func (b *FieldsBuilder) Time(key string, value time.Time) *FieldsBuilder {
+	return b.add(zap.Time(key, value))
+}
+
+// Err adds an error under the "error" key. A nil error is skipped.
+func (b *FieldsBuilder) Err(err error) *FieldsBuilder {
+	if err == nil {
+		return b
+	}
+	return b.add(zap.Error(err))
+}
+
+// Object adds a value that encodes itself via MarshalLogObject.
+func (b *FieldsBuilder) Object(key string, value zapcore.ObjectMarshaler) *FieldsBuilder {
+	return b.add(zap.Object(key, value))
+}
+
+// Array adds a value that encodes itself via MarshalLogArray.
+func (b *FieldsBuilder) Array(key string, value zapcore.ArrayMarshaler) *FieldsBuilder {
+	return b.add(zap.Array(key, value))
+}
+
+// Any adds a field of any type, like a plain Fields entry.
+func (b *FieldsBuilder) Any(key string, value any) *FieldsBuilder {
+	b.fields[key] = value
+	return b
+}
+
+// Build returns the fields. The builder must not be used afterwards.
+func (b *FieldsBuilder) Build() Fields {
+	return b.fields
+}
+
+// toZapField converts a Fields entry, taking the typed paths for common
+// values before falling back to zap.Any.
+func toZapField(key string, value any) zap.Field {
+	switch v := value.(type) {
+	case zap.Field:
+		v.Key = key
+		return v
+	case string:
+		return zap.String(key, v)
+	case int:
+		return zap.Int(key, v)
+	case int64:
+		return zap.Int64(key, v)
+	case float64:
+		return zap.Float64(key, v)
+	case bool:
+		return zap.Bool(key, v)
+	case time.Duration:
+		return zap.Duration(key, v)
+	case time.Time:
+		return zap.Time(key, v)
+	case error:
+		return zap.NamedError(key, v)
+	case zapcore.ObjectMarshaler:
+		return zap.Object(key, v)
+	case zapcore.ArrayMarshaler:
+		return zap.Array(key, v)
+	case Valuer:
+		resolved := v.LogValue()
+		if _, nested := resolved.(Valuer); nested {
+			return zap.Any(key, resolved)
+		}
+		return toZapField(key, resolved)
+	case fmt.Stringer:
+		return zap.Stringer(key, v)
+	default:
+		return zap.Any(key, v)
+	}
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newObservedLogger(level zapcore.Level) (*CustomLogger, *observer.ObservedLogs) {
+	core, logs := observer.New(level)
+	return &CustomLogger{logger: zap.New(core)}, logs
+}
+
+type customer struct{ id, tier string }
+
+func (c customer) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("id", c.id)
+	enc.AddString("tier", c.tier)
+	return nil
+}
+
+type lazyValue struct{ calls *int }
+
+func (v lazyValue) LogValue() any {
+	*v.calls++
+	return "computed"
+}
+
+func TestToZapField_TypedConversions(t *testing.T) {
+	tests := []struct {
+		value any
+		want  zapcore.FieldType
+	}{
+		{"s", zapcore.StringType},
+		{42, zapcore.Int64Type},
+		{int64(42), zapcore.Int64Type},
+		{1.5, zapcore.Float64Type},
+		{true, zapcore.BoolType},
+		{time.Second, zapcore.DurationType},
+		{time.Now(), zapcore.TimeType},
+		{errors.New("boom"), zapcore.ErrorType},
+		{customer{"c1", "gold"}, zapcore.ObjectMarshalerType},
+		{zap.Uint8("ignored", 7), zapcore.Uint8Type},
+		{struct{ A int }{1}, zapcore.ReflectType},
+	}
+	for _, tc := range tests {
+		f := toZapField("k", tc.value)
+		if f.Type != tc.want || f.Key != "k" {
+			t.Errorf("toZapField(%T) = {key %q, type %v}, want {key \"k\", type %v}", tc.value, f.Key, f.Type, tc.want)
+		}
+	}
+}
+
+func TestFieldsBuilder_WritesTypedFields(t *testing.T) {
+	l, logs := newObservedLogger(zapcore.InfoLevel)
+
+	l.Info(context.Background(), "order placed", NewFields(4).
+		String("order_id", "o-1").
+		Int("items", 3).
+		Object("customer", customer{"c1", "gold"}).
+		Err(nil).
+		Build())
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	got := entries[0].ContextMap()
+	if got["order_id"] != "o-1" || got["items"] != int64(3) {
+		t.Errorf("fields = %v", got)
+	}
+	if c, ok := got["customer"].(map[string]any); !ok || c["tier"] != "gold" {
+		t.Errorf("customer = %v, want object encoded via MarshalLogObject", got["customer"])
+	}
+	if _, ok := got["error"]; ok {
+		t.Error("nil error should be skipped")
+	}
+}
+
+func TestLogger_ValuerOnlyResolvedWhenEnabled(t *testing.T) {
+	l, logs := newObservedLogger(zapcore.InfoLevel)
+	calls := 0
+
+	l.Debug(context.Background(), "skipped", Fields{"payload": lazyValue{&calls}})
+	if calls != 0 {
+		t.Errorf("LogValue called %d times for a disabled level", calls)
+	}
+
+	l.Info(context.Background(), "written", Fields{"payload": lazyValue{&calls}})
+	if calls != 1 || logs.All()[0].ContextMap()["payload"] != "computed" {
+		t.Errorf("calls = %d, entry = %v", calls, logs.All())
+	}
+}
+
+func TestLogger_ContextTraceIDOverridesCallerField(t *testing.T) {
+	l, logs := newObservedLogger(zapcore.InfoLevel)
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	l.Info(ctx, "msg", Fields{"trace_id": "stale", "k": "v"}, Fields{"k": "override"})
+
+	got := logs.All()[0].ContextMap()
+	if got["trace_id"] != sc.TraceID().String() || got["k"] != "override" {
+		t.Errorf("fields = %v", got)
+	}
+	if n := len(logs.All()[0].Context); n != 4 { // k, trace_id, span_id + otelzap context field
+		t.Errorf("expected no duplicate keys, got %d fields", n)
+	}
+}
@@ -35,6 +35,9 @@ type Logger interface {
 // CustomLogger is a zap-based implementation of Logger with automatic trace correlation.
 type CustomLogger struct {
 	logger *zap.Logger
+	// fields are the fields added by With, replayed on the OTel bridge core
+	// since EnableOTelBridge adds it after they were bound to the others.
+	fields []zap.Field
 }
 
 // NewLogger creates a new logger instance.
@@ -74,11 +77,15 @@ func NewLogger(environment string) Logger {
 }
 
 // EnableOTelBridge adds an OTel log bridge core so zap entries are
-// also exported as OTel log records via OTLP.
-func (cl *CustomLogger) EnableOTelBridge(provider otellog.LoggerProvider) {
+// also exported as OTel log records via OTLP. With WithExportLevels, only
+// entries at those levels are exported; the local output keeps every entry.
+func (cl *CustomLogger) EnableOTelBridge(provider otellog.LoggerProvider, opts ...BridgeOption) {
 	otelCore := otelzap.NewCore("go-otel-agent",
 		otelzap.WithLoggerProvider(provider),
-	)
+	).With(cl.fields)
+	if bc := NewBridgeConfig(opts...); len(bc.ExportLevels) > 0 {
+		otelCore = levelFilterCore{Core: otelCore, exports: bc.ExportFilter()}
+	}
 	cl.logger = cl.logger.WithOptions(
 		zap.WrapCore(func(existing zapcore.Core) zapcore.Core {
 			return zapcore.NewTee(existing, otelCore)
@@ -87,19 +94,28 @@ func (cl *CustomLogger) EnableOTelBridge(provider otellog.LoggerProvider) {
 }
 
 func (cl *CustomLogger) Debug(ctx context.Context, message string, fields ...Fields) {
-	cl.logger.Debug(message, cl.zapFields(ctx, fields...)...)
+	// Check first so fields are only built when the level is enabled
+	if ce := cl.logger.Check(zapcore.DebugLevel, message); ce != nil {
+		ce.Write(cl.zapFields(ctx, fields...)...)
+	}
 }
 
 func (cl *CustomLogger) Info(ctx context.Context, message string, fields ...Fields) {
-	cl.logger.Info(message, cl.zapFields(ctx, fields...)...)
+	if ce := cl.logger.Check(zapcore.InfoLevel, message); ce != nil {
+		ce.Write(cl.zapFields(ctx, fields...)...)
+	}
 }
 
 func (cl *CustomLogger) Warning(ctx context.Context, message string, fields ...Fields) {
-	cl.logger.Warn(message, cl.zapFields(ctx, fields...)...)
+	if ce := cl.logger.Check(zapcore.WarnLevel, message); ce != nil {
+		ce.Write(cl.zapFields(ctx, fields...)...)
+	}
 }
 
 func (cl *CustomLogger) Error(ctx context.Context, message string, fields ...Fields) {
-	cl.logger.Error(message, cl.zapFields(ctx, fields...)...)
+	if ce := cl.logger.Check(zapcore.ErrorLevel, message); ce != nil {
+		ce.Write(cl.zapFields(ctx, fields...)...)
+	}
 }
 
 func (cl *CustomLogger) Fatal(ctx context.Context, message string, fields ...Fields) {
@@ -111,7 +127,11 @@ func (cl *CustomLogger) Panic(ctx context.Context, message string, fields ...Fie
 }
 
 func (cl *CustomLogger) With(fields Fields) Logger {
-	return &CustomLogger{logger: cl.logger.With(cl.fieldsToZap(fields)...)}
+	zfs := cl.fieldsToZap(fields)
+	return &CustomLogger{
+		logger: cl.logger.With(zfs...),
+		fields: append(cl.fields[:len(cl.fields):len(cl.fields)], zfs...),
+	}
 }
 
 func (cl *CustomLogger) LogError(ctx context.Context, message string, err error) {
@@ -129,28 +149,51 @@ func (cl *CustomLogger) LogError(ctx context.Context, message string, err error)
 
 // zapFields merges context and custom fields, automatically injecting trace context.
 func (cl *CustomLogger) zapFields(ctx context.Context, fields ...Fields) []zap.Field {
-	allFields := make(Fields)
-	for _, f := range fields {
-		for k, v := range f {
-			allFields[k] = v
+	// Fields from WithContextFields come first so the caller's win
+	if ctxFields := ContextFields(ctx); len(ctxFields) > 0 {
+		fields = append([]Fields{ctxFields}, fields...)
+	}
+
+	// A single map (the common case) needs no merging: keys are unique.
+	merged := Fields(nil)
+	switch len(fields) {
+	case 0:
+	case 1:
+		merged = fields[0]
+	default:
+		merged = make(Fields)
+		for _, f := range fields {
+			for k, v := range f {
+				merged[k] = v
+			}
 		}
 	}
 
 	// Auto trace injection - always check for span context
+	var traceID, spanID, reqID string
 	if ctx != nil {
-		span := trace.SpanFromContext(ctx)
-		if span.SpanContext().IsValid() {
-			allFields["trace_id"] = span.SpanContext().TraceID().String()
-			allFields["span_id"] = span.SpanContext().SpanID().String()
+		if sc := trace.SpanFromContext(ctx).SpanContext(); sc.IsValid() {
+			traceID, spanID = sc.TraceID().String(), sc.SpanID().String()
 		}
 
 		// Add requestID from context if present
-		if reqID, ok := ctx.Value(RequestIDKey).(string); ok && reqID != "" {
-			allFields["requestID"] = reqID
-		}
+		reqID, _ = ctx.Value(RequestIDKey).(string)
 	}
 
-	zfs := cl.fieldsToZap(allFields)
+	zfs := make([]zap.Field, 0, len(merged)+4)
+	for k, v := range merged {
+		// Context values win over caller fields with the same key
+		if (traceID != "" && (k == "trace_id" || k == "span_id")) || (reqID != "" && k == "requestID") {
+			continue
+		}
+		zfs = append(zfs, toZapField(k, v))
+	}
+	if traceID != "" {
+		zfs = append(zfs, zap.String("trace_id", traceID), zap.String("span_id", spanID))
+	}
+	if reqID != "" {
+		zfs = append(zfs, zap.String("requestID", reqID))
+	}
 
 	// Pass context to otelzap bridge for native OTel trace correlation.
 	// SkipType is invisible to stdout (AddTo is no-op) but otelzap checks
@@ -165,7 +208,7 @@ func (cl *CustomLogger) zapFields(ctx context.Context, fields ...Fields) []zap.F
 func (cl *CustomLogger) fieldsToZap(fields Fields) []zap.Field {
 	zfs := make([]zap.Field, 0, len(fields))
 	for k, v := range fields {
-		zfs = append(zfs, zap.Any(k, v))
+		zfs = append(zfs, toZapField(k, v))
 	}
 	return zfs
 }
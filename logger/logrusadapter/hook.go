@@ -0,0 +1,82 @@
+// Package logrusadapter connects logrus to the agent, for services that
+// cannot move to zap. New adapts a *logrus.Logger to logger.Logger, so it
+// can be passed to WithLogger, and Hook exports logrus entries through the
+// OTel LoggerProvider with their span context.
+package logrusadapter
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/bridges/otellogrus"
+	otellog "go.opentelemetry.io/otel/log"
+)
+
+// DefaultName is the instrumentation scope of entries exported by Hook, the
+// same as the zap bridge's.
+const DefaultName = "go-otel-agent"
+
+// HookOption configures a Hook.
+type HookOption func(*hookOptions)
+
+type hookOptions struct {
+	name   string
+	levels []logrus.Level
+}
+
+// WithName sets the instrumentation scope name of exported entries.
+func WithName(name string) HookOption {
+	return func(o *hookOptions) {
+		o.name = name
+	}
+}
+
+// WithLevels exports only entries of the given levels; all levels are
+// exported by default.
+func WithLevels(levels ...logrus.Level) HookOption {
+	return func(o *hookOptions) {
+		o.levels = levels
+	}
+}
+
+// Hook is a logrus.Hook that exports entries as OTel log records. An entry
+// logged with WithContext carries the span context of its context, so logs
+// and traces are linked in the backend.
+type Hook struct {
+	hook *otellogrus.Hook
+}
+
+var _ logrus.Hook = (*Hook)(nil)
+
+// NewHook returns a Hook exporting through provider, typically the agent's
+// LoggerProvider.
+//
+//	log.AddHook(logrusadapter.NewHook(agent.LoggerProvider()))
+//	log.WithContext(ctx).Info("order placed")
+func NewHook(provider otellog.LoggerProvider, opts ...HookOption) *Hook {
+	o := hookOptions{name: DefaultName, levels: logrus.AllLevels}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Hook{hook: otellogrus.NewHook(o.name,
+		otellogrus.WithLoggerProvider(provider),
+		otellogrus.WithLevels(o.levels),
+	)}
+}
+
+// Levels returns the levels the hook exports.
+func (h *Hook) Levels() []logrus.Level {
+	return h.hook.Levels()
+}
+
+// Fire exports entry.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		// The SDK reads the span context from the context, which must not
+		// be nil; leave the caller's entry untouched.
+		withContext := *entry
+		withContext.Context = context.Background()
+		entry = &withContext
+	}
+	return h.hook.Fire(entry)
+}
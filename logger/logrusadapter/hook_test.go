@@ -0,0 +1,68 @@
+package logrusadapter
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest/logtest"
+	"github.com/sirupsen/logrus"
+	otellog "go.opentelemetry.io/otel/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func spanContext(t *testing.T) (context.Context, trace.SpanContext) {
+	t.Helper()
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "op")
+	t.Cleanup(func() { span.End() })
+	return ctx, span.SpanContext()
+}
+
+func TestHook_ExportsWithSpanContext(t *testing.T) {
+	provider, exporter := logtest.NewLoggerProvider()
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	log.AddHook(NewHook(provider))
+
+	ctx, sc := spanContext(t)
+	log.WithContext(ctx).WithField("order_id", "o-1").Warn("card declined")
+	log.Info("no context")
+
+	records := exporter.Records()
+	if len(records) != 2 {
+		t.Fatalf("exported %d records, want 2", len(records))
+	}
+	r := records[0]
+	if r.Body().AsString() != "card declined" || r.TraceID() != sc.TraceID() || r.SpanID() != sc.SpanID() {
+		t.Errorf("exported record body %q, trace %s/%s; want the message and span context", r.Body().AsString(), r.TraceID(), r.SpanID())
+	}
+	if r.Severity() != otellog.SeverityWarn || r.InstrumentationScope().Name != DefaultName {
+		t.Errorf("severity %v, scope %q; want WARN and %q", r.Severity(), r.InstrumentationScope().Name, DefaultName)
+	}
+	var orderID string
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		if kv.Key == "order_id" {
+			orderID = kv.Value.AsString()
+		}
+		return true
+	})
+	if orderID != "o-1" {
+		t.Errorf("order_id attribute = %q, want o-1", orderID)
+	}
+}
+
+func TestHook_WithLevels(t *testing.T) {
+	provider, exporter := logtest.NewLoggerProvider()
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	log.AddHook(NewHook(provider, WithLevels(logrus.ErrorLevel)))
+
+	log.Info("kept local")
+	log.Error("exported")
+
+	records := exporter.Records()
+	if len(records) != 1 || records[0].Body().AsString() != "exported" {
+		t.Errorf("exported records = %v, want only the error", records)
+	}
+}
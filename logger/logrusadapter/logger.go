@@ -0,0 +1,172 @@
+package logrusadapter
+
+import (
+	"context"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/sirupsen/logrus"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger implements logger.Logger on top of a *logrus.Logger.
+type Logger struct {
+	entry *logrus.Entry
+}
+
+var _ logger.Logger = (*Logger)(nil)
+
+// New returns a logger.Logger writing to l. Entries carry trace_id, span_id
+// and requestID from the context, like the zap logger's, and are logged
+// with that context so a Hook can export them with their span context. The
+// agent calls EnableOTelBridge to add one once its LoggerProvider exists.
+//
+//	agent := otelagent.NewAgent(otelagent.WithLogger(logrusadapter.New(logrus.StandardLogger())))
+func New(l *logrus.Logger) logger.Logger {
+	return &Logger{entry: logrus.NewEntry(l)}
+}
+
+// EnableOTelBridge adds a Hook exporting through provider to the underlying
+// *logrus.Logger. Hooks belong to the logrus logger, so entries logged
+// through it directly are exported too. With logger.WithExportLevels, only
+// entries at those levels are exported.
+func (l *Logger) EnableOTelBridge(provider otellog.LoggerProvider, opts ...logger.BridgeOption) {
+	exports := logger.NewBridgeConfig(opts...).ExportFilter()
+	var levels []logrus.Level
+	for _, level := range logrus.AllLevels {
+		if exports(zapLevel(level)) {
+			levels = append(levels, level)
+		}
+	}
+	l.entry.Logger.AddHook(NewHook(provider, WithLevels(levels...)))
+}
+
+// zapLevel maps a logrus level to the zap level of the same severity, the
+// scale of export level names.
+func zapLevel(level logrus.Level) zapcore.Level {
+	switch level {
+	case logrus.PanicLevel:
+		return zapcore.PanicLevel
+	case logrus.FatalLevel:
+		return zapcore.FatalLevel
+	case logrus.ErrorLevel:
+		return zapcore.ErrorLevel
+	case logrus.WarnLevel:
+		return zapcore.WarnLevel
+	case logrus.InfoLevel:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+func (l *Logger) Debug(ctx context.Context, message string, fields ...logger.Fields) {
+	l.log(ctx, logrus.DebugLevel, message, fields)
+}
+
+func (l *Logger) Info(ctx context.Context, message string, fields ...logger.Fields) {
+	l.log(ctx, logrus.InfoLevel, message, fields)
+}
+
+func (l *Logger) Warning(ctx context.Context, message string, fields ...logger.Fields) {
+	l.log(ctx, logrus.WarnLevel, message, fields)
+}
+
+func (l *Logger) Error(ctx context.Context, message string, fields ...logger.Fields) {
+	l.log(ctx, logrus.ErrorLevel, message, fields)
+}
+
+// Fatal logs at logrus.FatalLevel, which exits the process through the
+// logger's ExitFunc.
+func (l *Logger) Fatal(ctx context.Context, message string, fields ...logger.Fields) {
+	l.withContext(ctx, fields).Fatal(message)
+}
+
+// Panic logs at logrus.PanicLevel, which panics with the entry.
+func (l *Logger) Panic(ctx context.Context, message string, fields ...logger.Fields) {
+	l.withContext(ctx, fields).Panic(message)
+}
+
+func (l *Logger) With(fields logger.Fields) logger.Logger {
+	return &Logger{entry: l.entry.WithFields(convert(fields))}
+}
+
+func (l *Logger) LogError(ctx context.Context, message string, err error) {
+	if err == nil {
+		return
+	}
+
+	fields := logger.Fields{"error": err.Error()}
+	if appErr, ok := err.(interface{ ToLogFields() map[string]interface{} }); ok {
+		fields = appErr.ToLogFields()
+	}
+
+	l.Error(ctx, message, fields)
+}
+
+// log checks the level first so fields are only converted when the entry
+// is written.
+func (l *Logger) log(ctx context.Context, level logrus.Level, message string, fields []logger.Fields) {
+	if !l.entry.Logger.IsLevelEnabled(level) {
+		return
+	}
+	l.withContext(ctx, fields).Log(level, message)
+}
+
+// withContext returns the entry with fields, the trace and request IDs of
+// ctx, and ctx itself.
+func (l *Logger) withContext(ctx context.Context, fields []logger.Fields) *logrus.Entry {
+	data := make(logrus.Fields, 4)
+	// Fields from logger.WithContextFields come first so the caller's win
+	for k, v := range convert(logger.ContextFields(ctx)) {
+		data[k] = v
+	}
+	for _, f := range fields {
+		for k, v := range convert(f) {
+			data[k] = v
+		}
+	}
+
+	entry := l.entry
+	if ctx != nil {
+		// Context values win over caller fields with the same key
+		if sc := trace.SpanFromContext(ctx).SpanContext(); sc.IsValid() {
+			data["trace_id"] = sc.TraceID().String()
+			data["span_id"] = sc.SpanID().String()
+		}
+		if reqID, _ := ctx.Value(logger.RequestIDKey).(string); reqID != "" {
+			data["requestID"] = reqID
+		}
+		entry = entry.WithContext(ctx)
+	}
+	return entry.WithFields(data)
+}
+
+// convert turns fields into logrus fields: typed zap fields from
+// logger.NewFields are unwrapped and Valuers resolved.
+func convert(fields logger.Fields) logrus.Fields {
+	out := make(logrus.Fields, len(fields))
+	for k, v := range fields {
+		out[k] = value(v)
+	}
+	return out
+}
+
+func value(v any) any {
+	switch val := v.(type) {
+	case zap.Field:
+		enc := zapcore.NewMapObjectEncoder()
+		val.AddTo(enc)
+		return enc.Fields[val.Key]
+	case logger.Valuer:
+		resolved := val.LogValue()
+		if _, nested := resolved.(logger.Valuer); nested {
+			return resolved
+		}
+		return value(resolved)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,94 @@
+package logrusadapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest/logtest"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type lazy struct{ calls *int }
+
+func (l lazy) LogValue() any {
+	*l.calls++
+	return "expensive"
+}
+
+func newJSONLogger(level logrus.Level) (*logrus.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&buf)
+	log.SetFormatter(&logrus.JSONFormatter{})
+	log.SetLevel(level)
+	return log, &buf
+}
+
+func decodeLine(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("output is not one JSON entry: %v\n%s", err, buf.String())
+	}
+	return line
+}
+
+func TestLogger_WritesFieldsWithTraceContext(t *testing.T) {
+	l, buf := newJSONLogger(logrus.InfoLevel)
+	log := New(l).With(logger.Fields{"service": "orders"})
+
+	ctx, sc := spanContext(t)
+	ctx = context.WithValue(ctx, logger.RequestIDKey, "req-1")
+	log.Info(ctx, "order placed",
+		logger.NewFields(2).String("order_id", "o-1").Duration("took", time.Second).Build(),
+		logger.Fields{"trace_id": "spoofed"})
+
+	line := decodeLine(t, buf)
+	for key, want := range map[string]any{
+		"msg": "order placed", "service": "orders", "order_id": "o-1",
+		"trace_id": sc.TraceID().String(), "span_id": sc.SpanID().String(), "requestID": "req-1",
+	} {
+		if line[key] != want {
+			t.Errorf("%s = %v, want %v", key, line[key], want)
+		}
+	}
+}
+
+func TestLogger_SkipsDisabledLevels(t *testing.T) {
+	l, buf := newJSONLogger(logrus.InfoLevel)
+	log := New(l)
+
+	calls := 0
+	log.Debug(context.Background(), "noisy", logger.Fields{"value": lazy{&calls}})
+	if buf.Len() != 0 || calls != 0 {
+		t.Errorf("debug entry written (%q) or valuer resolved %d times", buf.String(), calls)
+	}
+
+	log.LogError(context.Background(), "charge failed", errors.New("declined"))
+	if line := decodeLine(t, buf); line["level"] != "error" || line["error"] != "declined" {
+		t.Errorf("entry = %v, want an error with the error", line)
+	}
+}
+
+func TestLogger_EnableOTelBridge(t *testing.T) {
+	l, buf := newJSONLogger(logrus.InfoLevel)
+	log := New(l)
+	provider, exporter := logtest.NewLoggerProvider()
+	log.(*Logger).EnableOTelBridge(provider)
+
+	ctx, sc := spanContext(t)
+	log.Info(ctx, "exported")
+
+	records := exporter.Records()
+	if len(records) != 1 || records[0].TraceID() != sc.TraceID() {
+		t.Errorf("exported records = %v, want the info entry with its trace", records)
+	}
+	if buf.Len() == 0 {
+		t.Error("entry not written to the logrus output")
+	}
+}
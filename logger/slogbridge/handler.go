@@ -0,0 +1,196 @@
+// Package slogbridge connects log/slog to the agent. New adapts an
+// *slog.Logger to logger.Logger, so it can be passed to WithLogger, and
+// Handler correlates slog records with traces and exports them through the
+// OTel log bridge, the same way the zap logger does.
+package slogbridge
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultName is the instrumentation scope of records exported by Handler,
+// the same as the zap bridge's.
+const DefaultName = "go-otel-agent"
+
+// Option configures a Handler.
+type Option func(*handlerOptions)
+
+type handlerOptions struct {
+	name     string
+	provider otellog.LoggerProvider
+	exports  func(zapcore.Level) bool
+}
+
+// WithLoggerProvider exports records through provider, typically the
+// agent's LoggerProvider, in addition to writing them to the wrapped
+// handler.
+func WithLoggerProvider(provider otellog.LoggerProvider) Option {
+	return func(o *handlerOptions) {
+		o.provider = provider
+	}
+}
+
+// WithExportLevels exports only records at the named levels, as
+// logger.WithExportLevels does for the zap logger. Records at other levels
+// are still written to the wrapped handler.
+func WithExportLevels(levels ...string) Option {
+	return func(o *handlerOptions) {
+		o.exports = logger.NewBridgeConfig(logger.WithExportLevels(levels...)).ExportFilter()
+	}
+}
+
+// WithName sets the instrumentation scope name of exported records.
+func WithName(name string) Option {
+	return func(o *handlerOptions) {
+		o.name = name
+	}
+}
+
+// Handler is an slog.Handler that adds trace_id, span_id and requestID from
+// the record's context to the records it writes to the wrapped handler and,
+// with WithLoggerProvider, exports every record as an OTel log record
+// carrying the span context natively.
+type Handler struct {
+	name    string
+	next    slog.Handler
+	bridge  slog.Handler // nil without a LoggerProvider
+	exports func(zapcore.Level) bool
+	// scope replays the WithAttrs and WithGroup calls on a bridge added
+	// later by withProvider.
+	scope []func(slog.Handler) slog.Handler
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler wraps next, the handler writing records locally (a
+// slog.JSONHandler, say).
+//
+//	slog.SetDefault(slog.New(slogbridge.NewHandler(
+//		slog.NewJSONHandler(os.Stdout, nil),
+//		slogbridge.WithLoggerProvider(agent.LoggerProvider()),
+//	)))
+func NewHandler(next slog.Handler, opts ...Option) *Handler {
+	o := handlerOptions{name: DefaultName}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	h := &Handler{name: o.name, next: next, exports: o.exports}
+	if o.provider != nil {
+		h.bridge = otelslog.NewHandler(o.name, otelslog.WithLoggerProvider(o.provider))
+	}
+	return h
+}
+
+// Enabled reports whether the wrapped handler or the bridge handles level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level) || h.exported(ctx, level)
+}
+
+// exported reports whether records at level are exported.
+func (h *Handler) exported(ctx context.Context, level slog.Level) bool {
+	if h.bridge == nil || (h.exports != nil && !h.exports(zapLevel(level))) {
+		return false
+	}
+	return h.bridge.Enabled(ctx, level)
+}
+
+// Handle writes r, with the trace context of ctx, to the wrapped handler and
+// exports it through the bridge.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	if h.next.Enabled(ctx, r.Level) {
+		local := r
+		if attrs := contextAttrs(ctx); len(attrs) > 0 {
+			local = r.Clone()
+			local.AddAttrs(attrs...)
+		}
+		errs = append(errs, h.next.Handle(ctx, local))
+	}
+	if h.exported(ctx, r.Level) {
+		errs = append(errs, h.bridge.Handle(ctx, r))
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a Handler whose records carry attrs.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := &Handler{name: h.name, next: h.next.WithAttrs(attrs), exports: h.exports}
+	c.scope = append(h.scope[:len(h.scope):len(h.scope)], func(b slog.Handler) slog.Handler { return b.WithAttrs(attrs) })
+	if h.bridge != nil {
+		c.bridge = h.bridge.WithAttrs(attrs)
+	}
+	return c
+}
+
+// WithGroup returns a Handler that qualifies later attributes with name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	c := &Handler{name: h.name, next: h.next.WithGroup(name), exports: h.exports}
+	c.scope = append(h.scope[:len(h.scope):len(h.scope)], func(b slog.Handler) slog.Handler { return b.WithGroup(name) })
+	if h.bridge != nil {
+		c.bridge = h.bridge.WithGroup(name)
+	}
+	return c
+}
+
+// withProvider returns a copy of h that also exports, through provider, the
+// records at the levels exports accepts.
+func (h *Handler) withProvider(provider otellog.LoggerProvider, exports func(zapcore.Level) bool) *Handler {
+	var bridge slog.Handler = otelslog.NewHandler(h.name, otelslog.WithLoggerProvider(provider))
+	for _, apply := range h.scope {
+		bridge = apply(bridge)
+	}
+	return &Handler{
+		name:    h.name,
+		next:    h.next,
+		bridge:  bridge,
+		exports: exports,
+		scope:   h.scope,
+	}
+}
+
+// zapLevel maps an slog level to the zap level of the same severity, the
+// scale of export level names.
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	case level < LevelPanic:
+		return zapcore.ErrorLevel
+	case level < LevelFatal:
+		return zapcore.PanicLevel
+	default:
+		return zapcore.FatalLevel
+	}
+}
+
+// contextAttrs returns the trace and request ID attributes of ctx, with the
+// same keys the zap logger uses.
+func contextAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	var attrs []slog.Attr
+	if sc := trace.SpanFromContext(ctx).SpanContext(); sc.IsValid() {
+		attrs = append(attrs,
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	if reqID, _ := ctx.Value(logger.RequestIDKey).(string); reqID != "" {
+		attrs = append(attrs, slog.String("requestID", reqID))
+	}
+	return attrs
+}
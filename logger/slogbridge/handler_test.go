@@ -0,0 +1,100 @@
+package slogbridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest/logtest"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	otellog "go.opentelemetry.io/otel/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func spanContext(t *testing.T) (context.Context, trace.SpanContext) {
+	t.Helper()
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "op")
+	t.Cleanup(func() { span.End() })
+	return ctx, span.SpanContext()
+}
+
+func decodeLine(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("output is not one JSON record: %v\n%s", err, buf.String())
+	}
+	return line
+}
+
+func TestHandler_InjectsTraceContext(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil)))
+
+	ctx, sc := spanContext(t)
+	ctx = context.WithValue(ctx, logger.RequestIDKey, "req-1")
+	log.InfoContext(ctx, "order placed", "order_id", "o-1")
+
+	line := decodeLine(t, &buf)
+	if line["trace_id"] != sc.TraceID().String() || line["span_id"] != sc.SpanID().String() {
+		t.Errorf("trace_id/span_id = %v/%v, want %s/%s", line["trace_id"], line["span_id"], sc.TraceID(), sc.SpanID())
+	}
+	if line["requestID"] != "req-1" || line["order_id"] != "o-1" {
+		t.Errorf("record = %v, want requestID and order_id", line)
+	}
+}
+
+func TestHandler_ExportsThroughLoggerProvider(t *testing.T) {
+	var buf bytes.Buffer
+	provider, exporter := logtest.NewLoggerProvider()
+	log := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), WithLoggerProvider(provider))).With("component", "billing")
+
+	ctx, sc := spanContext(t)
+	log.WarnContext(ctx, "card declined")
+
+	records := exporter.Records()
+	if len(records) != 1 {
+		t.Fatalf("exported %d records, want 1", len(records))
+	}
+	r := records[0]
+	if r.Body().AsString() != "card declined" || r.TraceID() != sc.TraceID() || r.SpanID() != sc.SpanID() {
+		t.Errorf("exported record body %q, trace %s/%s; want the message and span context", r.Body().AsString(), r.TraceID(), r.SpanID())
+	}
+	if r.InstrumentationScope().Name != DefaultName {
+		t.Errorf("scope = %q, want %q", r.InstrumentationScope().Name, DefaultName)
+	}
+	var component string
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		if kv.Key == "component" {
+			component = kv.Value.AsString()
+		}
+		return true
+	})
+	if component != "billing" {
+		t.Errorf("component attribute = %q, want billing", component)
+	}
+	if buf.Len() == 0 {
+		t.Error("record not written to the wrapped handler")
+	}
+}
+
+func TestHandler_ExportLevels(t *testing.T) {
+	var buf bytes.Buffer
+	provider, exporter := logtest.NewLoggerProvider()
+	log := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), WithLoggerProvider(provider), WithExportLevels("warn", "error")))
+
+	log.Info("cache miss")
+	log.Warn("slow query")
+	log.Log(context.Background(), LevelFatal, "out of memory")
+
+	records := exporter.Records()
+	if len(records) != 2 || records[0].Body().AsString() != "slow query" {
+		t.Errorf("exported %d records, want the warn and fatal ones", len(records))
+	}
+	if n := bytes.Count(buf.Bytes(), []byte("\n")); n != 3 {
+		t.Errorf("wrote %d records locally, want 3", n)
+	}
+}
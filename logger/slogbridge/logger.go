@@ -0,0 +1,158 @@
+package slogbridge
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Levels of Fatal and Panic entries, above slog.LevelError as in zap.
+const (
+	LevelPanic = slog.LevelError + 2
+	LevelFatal = slog.LevelError + 4
+)
+
+// Logger implements logger.Logger on top of an *slog.Logger.
+type Logger struct {
+	logger *slog.Logger
+}
+
+var _ logger.Logger = (*Logger)(nil)
+
+// New returns a logger.Logger writing to l. Records go through a Handler
+// wrapping l's handler, so they carry trace_id and span_id like the zap
+// logger's; the agent calls EnableOTelBridge to export them once its
+// LoggerProvider exists.
+//
+//	agent := otelagent.NewAgent(otelagent.WithLogger(slogbridge.New(slog.Default())))
+func New(l *slog.Logger) logger.Logger {
+	h, ok := l.Handler().(*Handler)
+	if !ok {
+		h = NewHandler(l.Handler())
+	}
+	return &Logger{logger: slog.New(h)}
+}
+
+// EnableOTelBridge exports the logger's records through provider as OTel
+// log records, in addition to writing them to the slog handler. With
+// logger.WithExportLevels, only records at those levels are exported.
+func (l *Logger) EnableOTelBridge(provider otellog.LoggerProvider, opts ...logger.BridgeOption) {
+	if h, ok := l.logger.Handler().(*Handler); ok {
+		l.logger = slog.New(h.withProvider(provider, logger.NewBridgeConfig(opts...).ExportFilter()))
+	}
+}
+
+func (l *Logger) Debug(ctx context.Context, message string, fields ...logger.Fields) {
+	l.log(ctx, slog.LevelDebug, message, fields)
+}
+
+func (l *Logger) Info(ctx context.Context, message string, fields ...logger.Fields) {
+	l.log(ctx, slog.LevelInfo, message, fields)
+}
+
+func (l *Logger) Warning(ctx context.Context, message string, fields ...logger.Fields) {
+	l.log(ctx, slog.LevelWarn, message, fields)
+}
+
+func (l *Logger) Error(ctx context.Context, message string, fields ...logger.Fields) {
+	l.log(ctx, slog.LevelError, message, fields)
+}
+
+// Fatal logs at LevelFatal and exits the process with status 1.
+func (l *Logger) Fatal(ctx context.Context, message string, fields ...logger.Fields) {
+	l.log(ctx, LevelFatal, message, fields)
+	os.Exit(1)
+}
+
+// Panic logs at LevelPanic and panics with message.
+func (l *Logger) Panic(ctx context.Context, message string, fields ...logger.Fields) {
+	l.log(ctx, LevelPanic, message, fields)
+	panic(message)
+}
+
+func (l *Logger) With(fields logger.Fields) logger.Logger {
+	return &Logger{logger: slog.New(l.logger.Handler().WithAttrs(attrs(fields)))}
+}
+
+func (l *Logger) LogError(ctx context.Context, message string, err error) {
+	if err == nil {
+		return
+	}
+
+	fields := logger.Fields{"error": err.Error()}
+	if appErr, ok := err.(interface{ ToLogFields() map[string]interface{} }); ok {
+		fields = appErr.ToLogFields()
+	}
+
+	l.Error(ctx, message, fields)
+}
+
+// log builds the record itself, rather than calling slog.Logger.Log, so the
+// source location is the caller of the logger.Logger method.
+func (l *Logger) log(ctx context.Context, level slog.Level, message string, fields []logger.Fields) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	h := l.logger.Handler()
+	if !h.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // runtime.Callers, log, the Logger method
+	r := slog.NewRecord(time.Now(), level, message, pcs[0])
+	// Fields from logger.WithContextFields lose to the caller's
+	if ctxFields := logger.ContextFields(ctx); len(ctxFields) > 0 {
+		merged := make(logger.Fields, len(ctxFields))
+		for _, f := range append([]logger.Fields{ctxFields}, fields...) {
+			for k, v := range f {
+				merged[k] = v
+			}
+		}
+		fields = []logger.Fields{merged}
+	}
+	for _, f := range fields {
+		r.AddAttrs(attrs(f)...)
+	}
+	_ = h.Handle(ctx, r)
+}
+
+// attrs converts fields to slog attributes.
+func attrs(fields logger.Fields) []slog.Attr {
+	out := make([]slog.Attr, 0, len(fields))
+	for k, v := range fields {
+		out = append(out, slog.Any(k, value(v)))
+	}
+	return out
+}
+
+// value converts a Fields value to one slog renders well: typed zap fields
+// from logger.NewFields are unwrapped and Valuers resolved lazily.
+func value(v any) any {
+	switch val := v.(type) {
+	case zap.Field:
+		enc := zapcore.NewMapObjectEncoder()
+		val.AddTo(enc)
+		return enc.Fields[val.Key]
+	case logger.Valuer:
+		return valuer{val}
+	default:
+		return v
+	}
+}
+
+// valuer defers a logger.Valuer to slog's own lazy LogValuer resolution.
+type valuer struct {
+	v logger.Valuer
+}
+
+func (v valuer) LogValue() slog.Value {
+	return slog.AnyValue(value(v.v.LogValue()))
+}
@@ -0,0 +1,89 @@
+package slogbridge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/internal/agenttest/logtest"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	otellog "go.opentelemetry.io/otel/log"
+)
+
+type lazy struct{ calls *int }
+
+func (l lazy) LogValue() any {
+	*l.calls++
+	return "expensive"
+}
+
+func TestLogger_WritesFieldsWithTraceContext(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true}))).
+		With(logger.Fields{"service": "orders"})
+
+	ctx, sc := spanContext(t)
+	log.Info(ctx, "order placed",
+		logger.NewFields(2).String("order_id", "o-1").Duration("took", time.Second).Build(),
+		logger.Fields{"items": 3})
+
+	line := decodeLine(t, &buf)
+	for key, want := range map[string]any{
+		"msg": "order placed", "service": "orders", "order_id": "o-1",
+		"items": float64(3), "trace_id": sc.TraceID().String(),
+	} {
+		if line[key] != want {
+			t.Errorf("%s = %v, want %v", key, line[key], want)
+		}
+	}
+	if source, _ := line["source"].(map[string]any); !strings.HasSuffix(source["file"].(string), "logger_test.go") {
+		t.Errorf("source = %v, want the caller of Info", line["source"])
+	}
+}
+
+func TestLogger_SkipsDisabledLevels(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	calls := 0
+	log.Debug(context.Background(), "noisy", logger.Fields{"value": lazy{&calls}})
+	if buf.Len() != 0 || calls != 0 {
+		t.Errorf("debug record written (%q) or valuer resolved %d times", buf.String(), calls)
+	}
+
+	log.LogError(context.Background(), "charge failed", errors.New("declined"))
+	if line := decodeLine(t, &buf); line["level"] != "ERROR" || line["error"] != "declined" {
+		t.Errorf("record = %v, want an ERROR with the error", line)
+	}
+}
+
+func TestLogger_EnableOTelBridge(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(slog.New(slog.NewJSONHandler(&buf, nil))).With(logger.Fields{"team": "payments"})
+	provider, exporter := logtest.NewLoggerProvider()
+	log.(*Logger).EnableOTelBridge(provider)
+
+	log.Info(context.Background(), "exported")
+
+	records := exporter.Records()
+	if len(records) != 1 || records[0].Body().AsString() != "exported" {
+		t.Fatalf("exported records = %v, want the info record", records)
+	}
+	var team string
+	records[0].WalkAttributes(func(kv otellog.KeyValue) bool {
+		if kv.Key == "team" {
+			team = kv.Value.AsString()
+		}
+		return true
+	})
+	if team != "payments" {
+		t.Errorf("team attribute = %q, want the field added before bridging", team)
+	}
+	if buf.Len() == 0 {
+		t.Error("record not written to the slog handler")
+	}
+}
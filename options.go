@@ -1,7 +1,15 @@
 package otelagent
 
 import (
+	"time"
+
 	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/RodolfoBonis/go-otel-agent/provider"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"google.golang.org/grpc"
 )
 
 // Option configures the Agent.
@@ -14,6 +22,15 @@ func WithConfig(cfg *Config) Option {
 	}
 }
 
+// WithConfigFile layers a YAML or JSON configuration file (see
+// LoadConfigFromFile) under the environment and the other options, wherever
+// it appears in the option list. Errors reading the file are returned by Init.
+func WithConfigFile(path string) Option {
+	return func(a *Agent) {
+		a.configFile = path
+	}
+}
+
 // WithLogger sets a custom logger.
 func WithLogger(l logger.Logger) Option {
 	return func(a *Agent) {
@@ -21,6 +38,25 @@ func WithLogger(l logger.Logger) Option {
 	}
 }
 
+// WithErrorHandler sets a custom handler for internal OTel SDK errors.
+// By default, SDK errors are logged through the agent logger with rate limiting.
+// Init installs the handler globally and Shutdown puts the previous one back.
+func WithErrorHandler(h otel.ErrorHandler) Option {
+	return func(a *Agent) {
+		a.errorHandler = h
+	}
+}
+
+// WithSDKLogVerbosity sets the verbosity of the OpenTelemetry SDK's own log
+// messages written to the agent logger, like OTEL_LOGS_SDK_VERBOSITY:
+// provider.SDKLogErrors, SDKLogWarnings (the default), SDKLogInfo or
+// SDKLogDebug. Negative discards them.
+func WithSDKLogVerbosity(verbosity int) Option {
+	return func(a *Agent) {
+		a.config.Logs.SDKVerbosity = verbosity
+	}
+}
+
 // WithServiceName sets the service name.
 func WithServiceName(name string) Option {
 	return func(a *Agent) {
@@ -42,10 +78,37 @@ func WithServiceVersion(version string) Option {
 	}
 }
 
-// WithEndpoint sets the OTLP collector endpoint.
+// WithEndpoint sets the OTLP collector endpoint. An explicit endpoint, like
+// one from the environment, the config file or WithConfig, takes precedence
+// over the region endpoint registry.
 func WithEndpoint(endpoint string) Option {
 	return func(a *Agent) {
 		a.config.Endpoint = endpoint
+		a.endpointExplicit = true
+	}
+}
+
+// WithRegion sets the deployment region and selects the collector endpoint
+// registered for it (see WithEndpointRegistry / OTEL_ENDPOINT_REGISTRY).
+func WithRegion(region string) Option {
+	return func(a *Agent) {
+		a.config.Region = region
+		a.resolveEndpoint = true
+	}
+}
+
+// WithEndpointRegistry sets the mapping of "<environment>/<region>",
+// "<region>" or "<environment>" to collector endpoints. Entries are merged
+// into any registry loaded from OTEL_ENDPOINT_REGISTRY.
+func WithEndpointRegistry(registry map[string]string) Option {
+	return func(a *Agent) {
+		if a.config.EndpointRegistry == nil {
+			a.config.EndpointRegistry = make(map[string]string, len(registry))
+		}
+		for k, v := range registry {
+			a.config.EndpointRegistry[k] = v
+		}
+		a.resolveEndpoint = true
 	}
 }
 
@@ -82,6 +145,16 @@ func WithAutoInstrumentation(http, database, redis, amqp bool) Option {
 	}
 }
 
+// WithHealthProbes enables/disables exporter health tracking with the
+// /health probe, the /ready probe and the /live probe.
+func WithHealthProbes(health, readiness, liveness bool) Option {
+	return func(a *Agent) {
+		a.config.Features.HealthChecks = health
+		a.config.Features.ReadinessProbes = readiness
+		a.config.Features.LivenessProbes = liveness
+	}
+}
+
 // WithRouteExclusions sets route exclusion configuration.
 func WithRouteExclusions(cfg RouteExclusionConfig) Option {
 	return func(a *Agent) {
@@ -89,6 +162,15 @@ func WithRouteExclusions(cfg RouteExclusionConfig) Option {
 	}
 }
 
+// WithMetricRouteExclusions sets routes excluded from request metrics only.
+// Spans for these routes are still created unless they are also excluded
+// via WithRouteExclusions.
+func WithMetricRouteExclusions(cfg RouteExclusionConfig) Option {
+	return func(a *Agent) {
+		a.config.MetricRouteExclusion = cfg
+	}
+}
+
 // WithEnvironment sets the deployment environment.
 func WithEnvironment(env string) Option {
 	return func(a *Agent) {
@@ -96,6 +178,44 @@ func WithEnvironment(env string) Option {
 	}
 }
 
+// WithStdoutExporter writes all signals to stdout, pretty-printed, instead
+// of sending them to a collector. Meant for local development; set
+// Stdout.Path (OTEL_EXPORTER_STDOUT_PATH) to write to a rotating file.
+func WithStdoutExporter() Option {
+	return func(a *Agent) {
+		a.config.ExporterProtocol = ProtocolStdout
+	}
+}
+
+// WithResourceAttributes adds attributes to the resource of every signal,
+// for values only known at runtime such as the build SHA or region. Calls
+// accumulate. Attributes from the config and OTEL_RESOURCE_ATTRIBUTES win
+// on conflicting keys.
+func WithResourceAttributes(attrs ...attribute.KeyValue) Option {
+	return func(a *Agent) {
+		a.resourceOptions = append(a.resourceOptions, provider.WithResourceAttributes(attrs...))
+	}
+}
+
+// WithResourceDetectors runs resource detectors during Init, e.g. the EC2,
+// ECS or GCP detectors from opentelemetry-go-contrib. Calls accumulate. A
+// failing detector is logged as a warning and does not fail Init.
+func WithResourceDetectors(detectors ...resource.Detector) Option {
+	return func(a *Agent) {
+		a.resourceOptions = append(a.resourceOptions, provider.WithResourceDetectors(detectors...))
+	}
+}
+
+// WithGRPCDialOptions passes extra grpc.DialOptions to the gRPC trace, metric
+// and log exporters, e.g. a custom dialer, keepalive parameters or a
+// load-balancing service config. Each call replaces the previous options.
+// Unix domain sockets need no dialer: use a "unix:///path/to.sock" endpoint.
+func WithGRPCDialOptions(opts ...grpc.DialOption) Option {
+	return func(a *Agent) {
+		a.grpcDialOptions = opts
+	}
+}
+
 // WithInsecure sets whether to use insecure connection.
 func WithInsecure(insecure bool) Option {
 	return func(a *Agent) {
@@ -117,9 +237,200 @@ func WithAuthHeaders(headers map[string]string) Option {
 	}
 }
 
+// WithMinimalSpans records the route, status and duration of every request,
+// sampled or not, so SLO metrics cover all traffic while full spans stay at
+// the sampled rate. Needs metrics to be enabled.
+func WithMinimalSpans(enabled bool) Option {
+	return func(a *Agent) {
+		a.config.Traces.MinimalSpans = enabled
+	}
+}
+
+// WithMetricBaggageKeys adds the named baggage members as attributes to every
+// measurement made through the agent's meters. Keep them low-cardinality.
+func WithMetricBaggageKeys(keys ...string) Option {
+	return func(a *Agent) {
+		a.config.Metrics.BaggageKeys = keys
+	}
+}
+
+// WithPropagators sets the context propagation formats, like
+// OTEL_PROPAGATORS: "tracecontext", "baggage", "b3", "b3multi", "jaeger",
+// "xray" or "none". List several to interoperate with legacy services
+// during a migration, e.g. WithPropagators("tracecontext", "baggage", "b3").
+func WithPropagators(names ...string) Option {
+	return func(a *Agent) {
+		a.config.Propagators = names
+	}
+}
+
+// WithRepeatedEventSampling records only every Nth occurrence of an
+// identical span event within a span, like OTEL_SPAN_REPEATED_EVENTS_EVERY.
+func WithRepeatedEventSampling(every int) Option {
+	return func(a *Agent) {
+		a.config.Traces.RepeatedEventsEvery = every
+	}
+}
+
+// WithEventPayloadMaxSize bounds the JSON payload helper.EmitEvent records,
+// like OTEL_SPAN_EVENT_PAYLOAD_MAX_SIZE.
+func WithEventPayloadMaxSize(bytes int) Option {
+	return func(a *Agent) {
+		a.config.Traces.EventPayloadMaxSize = bytes
+	}
+}
+
+// WithSpanCompression exports runs of at least minSpans consecutive sibling
+// spans with the same name, each lasting at most maxDuration, as one span
+// with their count and durations, like OTEL_TRACES_COMPRESSION_ENABLED.
+func WithSpanCompression(minSpans int, maxDuration time.Duration) Option {
+	return func(a *Agent) {
+		a.config.Traces.Compression.Enabled = true
+		a.config.Traces.Compression.MinSpans = minSpans
+		a.config.Traces.Compression.MaxDuration = maxDuration
+	}
+}
+
+// WithErrorStackTraces attaches the caller's stack trace, at most maxFrames
+// frames, and up to maxChain wrapped errors to errors recorded with
+// helper.RecordSpanError, like OTEL_TRACES_ERROR_STACKTRACES.
+func WithErrorStackTraces(maxFrames, maxChain int) Option {
+	return func(a *Agent) {
+		a.config.Traces.ErrorStackTraces = true
+		a.config.Traces.ErrorStackFrames = maxFrames
+		a.config.Traces.ErrorChainDepth = maxChain
+	}
+}
+
+// WithMonotonicTimestamps takes span timestamps from the monotonic clock and
+// annotates wall clock skew beyond tolerance, like
+// OTEL_TRACES_MONOTONIC_TIMESTAMPS and OTEL_TRACES_CLOCK_SKEW_TOLERANCE.
+func WithMonotonicTimestamps(tolerance time.Duration) Option {
+	return func(a *Agent) {
+		a.config.Traces.MonotonicTimestamps = true
+		a.config.Traces.ClockSkewTolerance = tolerance
+	}
+}
+
+// WithMessagingKeyCapture records message keys on messaging spans, scrubbed
+// with the agent's rules. Off by default, as keys often identify users.
+func WithMessagingKeyCapture(enabled bool) Option {
+	return func(a *Agent) {
+		a.config.Scrub.CaptureMessagingKeys = enabled
+	}
+}
+
+// WithTenantSamplingQuota caps the sampled traces per minute of every
+// tenant, read from the baggage member key, with per-tenant overrides (nil
+// for none). A tenant over its quota has its new traces dropped while other
+// tenants keep sampling.
+func WithTenantSamplingQuota(key string, perMinute int, overrides map[string]int) Option {
+	return func(a *Agent) {
+		a.config.Traces.Sampling.TenantKey = key
+		a.config.Traces.Sampling.TenantQuota = perMinute
+		a.config.Traces.Sampling.TenantQuotas = overrides
+	}
+}
+
+// WithSpanBaggageKeys copies the named baggage members onto every span as
+// attributes, e.g. "tenant.id" and "user.plan" set by an upstream service.
+func WithSpanBaggageKeys(keys ...string) Option {
+	return func(a *Agent) {
+		a.config.Traces.BaggageKeys = keys
+	}
+}
+
+// WithMetricTemporality sets the temporality the metric exporter requests
+// per instrument kind, overriding Metrics.TemporalityPreference. For the
+// standard preferences use provider.TemporalitySelector:
+//
+//	otelagent.WithMetricTemporality(provider.TemporalitySelector(otelagent.TemporalityDelta))
+func WithMetricTemporality(selector sdkmetric.TemporalitySelector) Option {
+	return func(a *Agent) {
+		a.metricTemporality = selector
+	}
+}
+
+// WithMetricAggregation sets the metric exporter's default aggregation per
+// instrument kind. Histogram views configured by the agent still apply.
+func WithMetricAggregation(selector sdkmetric.AggregationSelector) Option {
+	return func(a *Agent) {
+		a.metricAggregation = selector
+	}
+}
+
+// WithDynamicBatching lets the span batch size and schedule delay follow
+// queue utilization within the Traces.DynamicBatching bounds.
+func WithDynamicBatching(enabled bool) Option {
+	return func(a *Agent) {
+		a.config.Traces.DynamicBatching.Enabled = enabled
+	}
+}
+
+// WithLogDropPolicy sets what happens to log records emitted while the export
+// queue is full: DropOldest, DropNewest, or BlockWithTimeout, which blocks the
+// caller for up to Logs.BlockTimeout. Drops are counted in
+// otel_agent_logs_dropped_total.
+func WithLogDropPolicy(policy string) Option {
+	return func(a *Agent) {
+		a.config.Logs.DropPolicy = policy
+	}
+}
+
+// WithSelfTelemetry exports otel_agent_* metrics about the agent itself:
+// sampling decisions, queue utilization, export latency, redactions and
+// route-exclusion hits.
+func WithSelfTelemetry(enabled bool) Option {
+	return func(a *Agent) {
+		a.config.Features.SelfTelemetry = enabled
+	}
+}
+
 // WithDebugMode enables debug mode.
 func WithDebugMode(debug bool) Option {
 	return func(a *Agent) {
 		a.config.Features.DebugMode = debug
 	}
 }
+
+// WithShutdownTimeout bounds Shutdown (10s by default): short enough for
+// Lambda or pods with a tight termination grace period, long enough for big
+// queues. Data still queued when it expires is dropped and reported. 0 leaves
+// the deadline to the context passed to Shutdown.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(a *Agent) {
+		a.config.Performance.ShutdownTimeout = d
+	}
+}
+
+// WithFlushOnlyShutdown makes Shutdown flush every signal but keep the
+// providers, collectors and worker pool running, so a serverless runtime can
+// call it before the process is frozen and keep using the agent after thaw.
+func WithFlushOnlyShutdown(enabled bool) Option {
+	return func(a *Agent) {
+		a.config.Performance.FlushOnlyShutdown = enabled
+	}
+}
+
+// WithShutdownOnSignal installs a SIGTERM/SIGINT hook at Init that flushes
+// and shuts the agent down, so the tail of the telemetry is not lost when a
+// service never calls Shutdown. The signal is then raised again so the
+// process exits as it would have. It is meant for services that do not handle
+// signals: one registered with signal.Notify would receive the signal twice
+// and drain while the agent is shutting down. Those should use
+// WithFlushOnSignal, or call Shutdown or RunUntilSignal themselves.
+func WithShutdownOnSignal(enabled bool) Option {
+	return func(a *Agent) {
+		a.setSignalHook(signalHookShutdown, enabled)
+	}
+}
+
+// WithFlushOnSignal installs a SIGTERM/SIGINT hook at Init that only flushes
+// the agent, for services that handle signals themselves: the signal is not
+// raised again and shutting down, once in-flight work is drained, is left to
+// the application. It replaces WithShutdownOnSignal.
+func WithFlushOnSignal(enabled bool) Option {
+	return func(a *Agent) {
+		a.setSignalHook(signalHookFlush, enabled)
+	}
+}
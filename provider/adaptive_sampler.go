@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	adaptiveWindow = time.Second
+
+	// maxFailingOperations bounds how many recently failing operations are
+	// boosted.
+	maxFailingOperations = 64
+	// errorTTL is how long a failing operation stays boosted.
+	errorTTL = time.Minute
+	// errorRefresh is how often repeated errors of an operation refresh its
+	// entry; an error storm does not rebuild the set on every span.
+	errorRefresh = time.Second
+)
+
+// AdaptiveSampler is a root sampler that keeps the sampled span rate near a
+// spans-per-second budget and boosts operations that recently ended in error.
+//
+// The probability is recomputed every second as min(rate, target/throughput),
+// so the configured rate is a ceiling that is only lowered under load.
+//
+// Error boosting is tail-sampling-lite: the sampler is also a SpanProcessor
+// that remembers the last failing local roots (keyed by url.path, or the span
+// name), and multiplies the probability of matching roots by the boost
+// factor. Only recorded roots are seen, so by default errors are learned from
+// sampled traces; with recordUnsampled, unsampled roots are recorded but not
+// exported so their errors count too.
+//
+// ShouldSample takes no lock: the window is counted with atomics and the set
+// of failing operations is an immutable map replaced on every change.
+//
+// Use it as the root of a ParentBased sampler and register it as a span
+// processor; NewTraceProvider does both via WithAdaptiveSampler.
+type AdaptiveSampler struct {
+	ceiling         float64
+	target          float64
+	boost           float64
+	recordUnsampled bool
+	now             func() time.Time
+
+	rate atomic.Uint64 // math.Float64bits of the effective probability
+
+	windowStart atomic.Int64 // UnixNano; whoever advances it recomputes rate
+	count       atomic.Int64
+
+	mu      sync.Mutex // serializes writers of failing
+	failing atomic.Pointer[map[string]time.Time]
+}
+
+// NewAdaptiveSampler creates an AdaptiveSampler from the sampling rate (the
+// ceiling), Performance.TargetSpansPerSecond, Performance.ErrorSamplingBoost
+// and Performance.ErrorBoostUnsampled.
+func NewAdaptiveSampler(sampling config.SamplingConfig, perf config.PerformanceConfig) *AdaptiveSampler {
+	ceiling := math.Min(math.Max(sampling.Rate, 0), 1)
+	s := &AdaptiveSampler{
+		ceiling: ceiling,
+		target:  perf.TargetSpansPerSecond,
+		boost:   math.Max(perf.ErrorSamplingBoost, 1),
+		now:     time.Now,
+	}
+	s.recordUnsampled = perf.ErrorBoostUnsampled && s.boost > 1
+	s.rate.Store(math.Float64bits(ceiling))
+	s.windowStart.Store(s.now().UnixNano())
+	return s
+}
+
+// EffectiveRate returns the probability currently applied to root spans,
+// before any error boost.
+func (s *AdaptiveSampler) EffectiveRate() float64 {
+	return math.Float64frombits(s.rate.Load())
+}
+
+// ShouldSample implements sdktrace.Sampler.
+func (s *AdaptiveSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	s.observe()
+
+	prob := s.EffectiveRate()
+	if s.failedRecently(p.Name, p.Attributes) {
+		prob = math.Min(1, prob*s.boost)
+	}
+
+	decision := sdktrace.Drop
+	switch {
+	case traceIDBelow(p.TraceID, prob):
+		decision = sdktrace.RecordAndSample
+	case s.recordUnsampled:
+		decision = sdktrace.RecordOnly
+	}
+
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+// Description implements sdktrace.Sampler.
+func (s *AdaptiveSampler) Description() string {
+	return fmt.Sprintf("AdaptiveSampler{rate:%g,target:%g,boost:%g}", s.ceiling, s.target, s.boost)
+}
+
+// observe counts a root decision and recomputes the rate once per window.
+func (s *AdaptiveSampler) observe() {
+	s.count.Add(1)
+	now := s.now()
+	start := s.windowStart.Load()
+	elapsed := now.Sub(time.Unix(0, start))
+	if elapsed < adaptiveWindow || !s.windowStart.CompareAndSwap(start, now.UnixNano()) {
+		return
+	}
+
+	rate := s.ceiling
+	if throughput := float64(s.count.Swap(0)) / elapsed.Seconds(); s.target > 0 && throughput > 0 {
+		rate = math.Min(s.ceiling, s.target/throughput)
+	}
+	// Smooth over consecutive windows to avoid oscillating on bursts.
+	rate = (s.EffectiveRate() + rate) / 2
+	s.rate.Store(math.Float64bits(rate))
+}
+
+func (s *AdaptiveSampler) failedRecently(name string, attrs []attribute.KeyValue) bool {
+	failing := s.failing.Load()
+	if failing == nil || len(*failing) == 0 {
+		return false
+	}
+	at, ok := (*failing)[samplingKey(name, attrs)]
+	return ok && s.now().Sub(at) < errorTTL
+}
+
+// OnStart implements sdktrace.SpanProcessor.
+func (s *AdaptiveSampler) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+// OnEnd records local root spans that ended in error.
+func (s *AdaptiveSampler) OnEnd(span sdktrace.ReadOnlySpan) {
+	if s.boost <= 1 || span.Status().Code != codes.Error {
+		return
+	}
+	if parent := span.Parent(); parent.IsValid() && !parent.IsRemote() {
+		return
+	}
+	s.recordFailure(samplingKey(span.Name(), span.Attributes()))
+}
+
+// recordFailure replaces the failing set with one that includes key, dropping
+// expired entries and, past maxFailingOperations, the oldest one.
+func (s *AdaptiveSampler) recordFailure(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var old map[string]time.Time
+	if p := s.failing.Load(); p != nil {
+		old = *p
+	}
+	if at, ok := old[key]; ok && now.Sub(at) < errorRefresh {
+		return
+	}
+
+	next := make(map[string]time.Time, len(old)+1)
+	oldestKey, oldest := "", now
+	for k, at := range old {
+		if now.Sub(at) >= errorTTL {
+			continue
+		}
+		next[k] = at
+		if k != key && at.Before(oldest) {
+			oldestKey, oldest = k, at
+		}
+	}
+	next[key] = now
+	if len(next) > maxFailingOperations {
+		delete(next, oldestKey)
+	}
+	s.failing.Store(&next)
+}
+
+// Shutdown implements sdktrace.SpanProcessor.
+func (s *AdaptiveSampler) Shutdown(context.Context) error { return nil }
+
+// ForceFlush implements sdktrace.SpanProcessor.
+func (s *AdaptiveSampler) ForceFlush(context.Context) error { return nil }
+
+// samplingKey identifies an operation stably between span start and end:
+// HTTP server spans are renamed to their route after the handler runs, so
+// url.path is preferred over the name.
+func samplingKey(name string, attrs []attribute.KeyValue) string {
+	for _, kv := range attrs {
+		if kv.Key == "url.path" {
+			return kv.Value.AsString()
+		}
+	}
+	return name
+}
+
+// traceIDBelow makes the same deterministic decision as TraceIDRatioBased.
+func traceIDBelow(id trace.TraceID, prob float64) bool {
+	if prob >= 1 {
+		return true
+	}
+	if prob <= 0 {
+		return false
+	}
+	x := binary.BigEndian.Uint64(id[8:16]) >> 1
+	return x < uint64(prob*(1<<63))
+}
@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestAdaptiveSampler(rate, target, boost float64) (*AdaptiveSampler, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	s := NewAdaptiveSampler(
+		config.SamplingConfig{Rate: rate},
+		config.PerformanceConfig{TargetSpansPerSecond: target, ErrorSamplingBoost: boost},
+	)
+	s.now = clock.now
+	s.windowStart.Store(clock.t.UnixNano())
+	return s, clock
+}
+
+// traceIDAt returns a trace ID that TraceIDRatioBased samples for any
+// probability above frac.
+func traceIDAt(frac float64) trace.TraceID {
+	var id trace.TraceID
+	id[0] = 1
+	binary.BigEndian.PutUint64(id[8:], uint64(frac*(1<<63))<<1)
+	return id
+}
+
+func sample(s *AdaptiveSampler, frac float64, path string) sdktrace.SamplingDecision {
+	return s.ShouldSample(sdktrace.SamplingParameters{
+		ParentContext: context.Background(),
+		TraceID:       traceIDAt(frac),
+		Name:          "GET " + path,
+		Attributes:    []attribute.KeyValue{attribute.String("url.path", path)},
+	}).Decision
+}
+
+func TestAdaptiveSampler_ConvergesToBudget(t *testing.T) {
+	s, clock := newTestAdaptiveSampler(1.0, 100, 1)
+
+	// 1000 roots per second against a budget of 100/s.
+	for w := 0; w < 8; w++ {
+		for i := 0; i < 1000; i++ {
+			sample(s, 0.5, "/orders")
+		}
+		clock.advance(time.Second)
+	}
+	sample(s, 0.5, "/orders")
+
+	if got := s.EffectiveRate(); got < 0.09 || got > 0.12 {
+		t.Errorf("EffectiveRate = %v, want ~0.1", got)
+	}
+}
+
+func TestAdaptiveSampler_RateIsCappedByConfiguredRate(t *testing.T) {
+	s, clock := newTestAdaptiveSampler(0.5, 1000, 1)
+
+	for i := 0; i < 10; i++ {
+		sample(s, 0.9, "/orders")
+	}
+	clock.advance(time.Second)
+	sample(s, 0.9, "/orders")
+
+	if got := s.EffectiveRate(); got != 0.5 {
+		t.Errorf("EffectiveRate = %v, want ceiling 0.5 under budget", got)
+	}
+}
+
+func TestAdaptiveSampler_BoostsRecentlyFailingOperations(t *testing.T) {
+	s, clock := newTestAdaptiveSampler(0.1, 1000, 5)
+	s.recordUnsampled = true
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(s)),
+		sdktrace.WithSpanProcessor(s),
+	)
+
+	// An unsampled failing root is still recorded, so the sampler sees it.
+	_, span := tp.Tracer("test").Start(context.Background(), "GET /checkout",
+		trace.WithAttributes(attribute.String("url.path", "/checkout")))
+	span.SetStatus(codes.Error, "boom")
+	span.End()
+
+	if got := sample(s, 0.3, "/checkout"); got != sdktrace.RecordAndSample {
+		t.Errorf("failing operation decision = %v, want RecordAndSample (0.1 * 5 boost)", got)
+	}
+	if got := sample(s, 0.3, "/orders"); got != sdktrace.RecordOnly {
+		t.Errorf("healthy operation decision = %v, want RecordOnly", got)
+	}
+
+	clock.advance(errorTTL + time.Second)
+	if got := sample(s, 0.3, "/checkout"); got == sdktrace.RecordAndSample {
+		t.Error("boost should expire after errorTTL")
+	}
+}
+
+func TestAdaptiveSampler_LearnsErrorsFromSampledRootsByDefault(t *testing.T) {
+	s, _ := newTestAdaptiveSampler(0.1, 1000, 5)
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(s)),
+		sdktrace.WithSpanProcessor(s),
+	)
+
+	if got := sample(s, 0.3, "/orders"); got != sdktrace.Drop {
+		t.Errorf("unsampled root decision = %v, want Drop unless ErrorBoostUnsampled is set", got)
+	}
+
+	// A sampled root, here continuing a sampled remote trace, reports its error.
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceIDAt(0.9),
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), parent)
+	_, span := tp.Tracer("test").Start(ctx, "GET /checkout",
+		trace.WithAttributes(attribute.String("url.path", "/checkout")))
+	span.SetStatus(codes.Error, "boom")
+	span.End()
+
+	if got := sample(s, 0.3, "/checkout"); got != sdktrace.RecordAndSample {
+		t.Errorf("failing operation decision = %v, want RecordAndSample (0.1 * 5 boost)", got)
+	}
+}
+
+func TestAdaptiveSampler_FailingSetIsBounded(t *testing.T) {
+	s, clock := newTestAdaptiveSampler(0.1, 1000, 5)
+
+	for i := 0; i <= maxFailingOperations; i++ {
+		s.recordFailure(fmt.Sprintf("/op/%d", i))
+		clock.advance(time.Millisecond)
+	}
+
+	failing := *s.failing.Load()
+	if len(failing) != maxFailingOperations {
+		t.Errorf("failing set holds %d operations, want %d", len(failing), maxFailingOperations)
+	}
+	if _, ok := failing["/op/0"]; ok {
+		t.Error("the oldest failing operation should have been evicted")
+	}
+}
+
+func TestAdaptiveSampler_DropsWithoutBoost(t *testing.T) {
+	s, _ := newTestAdaptiveSampler(0.1, 1000, 1)
+
+	if got := sample(s, 0.5, "/orders"); got != sdktrace.Drop {
+		t.Errorf("decision = %v, want Drop when error boost is disabled", got)
+	}
+	if got := sample(s, 0.05, "/orders"); got != sdktrace.RecordAndSample {
+		t.Errorf("decision = %v, want RecordAndSample below the rate", got)
+	}
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// BaggageSpanProcessor copies an allow-list of baggage members from the
+// context a span is started with onto the span as string attributes. Baggage
+// propagates across services, so business context set at the edge
+// (tenant.id, user.plan) becomes queryable on every downstream span.
+//
+// Attributes already set when the span starts win over baggage. Register it
+// before processors that inspect attributes on start, such as the scrubber,
+// so copied values are scrubbed too.
+type BaggageSpanProcessor struct {
+	keys []string
+}
+
+// NewBaggageSpanProcessor returns a processor that copies the listed
+// baggage keys onto spans.
+func NewBaggageSpanProcessor(keys []string) *BaggageSpanProcessor {
+	return &BaggageSpanProcessor{keys: keys}
+}
+
+// OnStart copies the allow-listed baggage members of ctx onto s.
+func (p *BaggageSpanProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	bag := baggage.FromContext(ctx)
+	if bag.Len() == 0 {
+		return
+	}
+
+	var attrs []attribute.KeyValue
+	for _, key := range p.keys {
+		if m := bag.Member(key); m.Key() != "" && !hasAttribute(s.Attributes(), key) {
+			attrs = append(attrs, attribute.String(key, m.Value()))
+		}
+	}
+	if len(attrs) > 0 {
+		s.SetAttributes(attrs...)
+	}
+}
+
+// OnEnd is a no-op.
+func (p *BaggageSpanProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+// Shutdown is a no-op.
+func (p *BaggageSpanProcessor) Shutdown(context.Context) error { return nil }
+
+// ForceFlush is a no-op.
+func (p *BaggageSpanProcessor) ForceFlush(context.Context) error { return nil }
+
+func hasAttribute(attrs []attribute.KeyValue, key string) bool {
+	for _, kv := range attrs {
+		if string(kv.Key) == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestBaggageSpanProcessor_CopiesAllowListedKeys(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(NewBaggageSpanProcessor([]string{"tenant.id", "user.plan"})),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+
+	tenant, _ := baggage.NewMember("tenant.id", "org-123")
+	plan, _ := baggage.NewMember("user.plan", "pro")
+	secret, _ := baggage.NewMember("session", "s3cret")
+	bag, _ := baggage.New(tenant, plan, secret)
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+
+	_, span := tp.Tracer("test").Start(ctx, "op",
+		trace.WithAttributes(attribute.String("user.plan", "explicit")))
+	span.End()
+
+	attrs := make(map[attribute.Key]string)
+	for _, kv := range recorder.Ended()[0].Attributes() {
+		attrs[kv.Key] = kv.Value.AsString()
+	}
+	if attrs["tenant.id"] != "org-123" {
+		t.Errorf("tenant.id = %q, want org-123", attrs["tenant.id"])
+	}
+	if attrs["user.plan"] != "explicit" {
+		t.Errorf("user.plan = %q, want the span's own attribute to win", attrs["user.plan"])
+	}
+	if _, ok := attrs["session"]; ok {
+		t.Error("session is not allow-listed and must not be copied")
+	}
+}
@@ -0,0 +1,261 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// blockedTraceTTL is how long a trace stays blocked after it was last
+	// matched, long enough for its remaining spans and logs to end.
+	blockedTraceTTL = 10 * time.Minute
+	// maxBlockedTraces bounds the blocked-trace set under heavy traffic from
+	// blocked subjects. Past it, the least recently matched trace is
+	// evicted, so blocking new traces never fails.
+	maxBlockedTraces = 10000
+)
+
+// Blocklist drops telemetry about blocked subjects, such as users who asked
+// for their data to be removed. A span or log record matches when one of the
+// configured keys, looked up in baggage and then in its attributes, holds a
+// blocked value. Its trace is then blocked too, so later spans and logs of
+// the same trace are dropped even without the identifier.
+//
+// Values can be added and removed at runtime. Blocking is best effort: spans
+// of a trace that were exported before the identifier appeared are not
+// recalled.
+type Blocklist struct {
+	keys []string
+
+	mu     sync.RWMutex
+	values map[string]struct{}
+	traces map[trace.TraceID]time.Time // blocked trace -> expiry
+	now    func() time.Time
+
+	// evicted counts blocked traces dropped from a full set before they
+	// expired; their remaining spans and logs are no longer blocked
+	evicted atomic.Int64
+}
+
+// NewBlocklist creates a Blocklist that checks cfg.Keys for cfg.Values.
+func NewBlocklist(cfg config.BlocklistConfig) *Blocklist {
+	b := &Blocklist{
+		keys:   slices.Clone(cfg.Keys),
+		values: make(map[string]struct{}, len(cfg.Values)),
+		traces: make(map[trace.TraceID]time.Time),
+		now:    time.Now,
+	}
+	b.Add(cfg.Values...)
+	return b
+}
+
+// Add blocks the given identifiers.
+func (b *Blocklist) Add(values ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, v := range values {
+		if v != "" {
+			b.values[v] = struct{}{}
+		}
+	}
+}
+
+// Remove unblocks the given identifiers. Traces already blocked stay
+// blocked until they expire.
+func (b *Blocklist) Remove(values ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, v := range values {
+		delete(b.values, v)
+	}
+}
+
+// Values returns the blocked identifiers, sorted.
+func (b *Blocklist) Values() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	values := make([]string, 0, len(b.values))
+	for v := range b.values {
+		values = append(values, v)
+	}
+	slices.Sort(values)
+	return values
+}
+
+// BlockTrace drops the remaining spans and logs of the given trace.
+func (b *Blocklist) BlockTrace(id trace.TraceID) {
+	if !id.IsValid() {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if _, ok := b.traces[id]; !ok && len(b.traces) >= maxBlockedTraces {
+		var oldest trace.TraceID
+		var oldestExpiry time.Time
+		for t, expiry := range b.traces {
+			if now.After(expiry) {
+				delete(b.traces, t)
+			} else if oldestExpiry.IsZero() || expiry.Before(oldestExpiry) {
+				oldest, oldestExpiry = t, expiry
+			}
+		}
+		// New matches are the likeliest to still have spans and logs to
+		// come, so the trace matched longest ago makes room
+		if len(b.traces) >= maxBlockedTraces {
+			delete(b.traces, oldest)
+			b.evicted.Add(1)
+		}
+	}
+	b.traces[id] = now.Add(blockedTraceTTL)
+}
+
+// Evicted returns how many blocked traces were evicted from the full
+// blocked-trace set before they expired.
+func (b *Blocklist) Evicted() int64 {
+	return b.evicted.Load()
+}
+
+// empty reports whether nothing is blocked, so callers can skip matching.
+func (b *Blocklist) empty() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.values) == 0 && len(b.traces) == 0
+}
+
+func (b *Blocklist) blockedValue(v string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	_, ok := b.values[v]
+	return ok
+}
+
+func (b *Blocklist) traceBlocked(id trace.TraceID) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	expiry, ok := b.traces[id]
+	return ok && b.now().Before(expiry)
+}
+
+// matchBaggage reports whether a configured key in the baggage of ctx holds
+// a blocked value.
+func (b *Blocklist) matchBaggage(ctx context.Context) bool {
+	bag := baggage.FromContext(ctx)
+	if bag.Len() == 0 {
+		return false
+	}
+	for _, k := range b.keys {
+		if v := bag.Member(k).Value(); v != "" && b.blockedValue(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Blocklist) matchAttributes(attrs []attribute.KeyValue) bool {
+	for _, kv := range attrs {
+		if slices.Contains(b.keys, string(kv.Key)) && b.blockedValue(kv.Value.Emit()) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewBlocklistSpanProcessor wraps next, typically the batch span processor,
+// so that spans of blocked traces never reach it.
+func NewBlocklistSpanProcessor(next sdktrace.SpanProcessor, b *Blocklist) sdktrace.SpanProcessor {
+	return &blocklistSpanProcessor{next: next, list: b}
+}
+
+type blocklistSpanProcessor struct {
+	next sdktrace.SpanProcessor
+	list *Blocklist
+}
+
+func (p *blocklistSpanProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	if !p.list.empty() && (p.list.matchBaggage(ctx) || p.list.matchAttributes(s.Attributes())) {
+		p.list.BlockTrace(s.SpanContext().TraceID())
+	}
+	p.next.OnStart(ctx, s)
+}
+
+func (p *blocklistSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !p.list.empty() {
+		id := s.SpanContext().TraceID()
+		if p.list.traceBlocked(id) {
+			return
+		}
+		if p.list.matchAttributes(s.Attributes()) {
+			p.list.BlockTrace(id)
+			return
+		}
+	}
+	p.next.OnEnd(s)
+}
+
+func (p *blocklistSpanProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *blocklistSpanProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// NewBlocklistLogProcessor wraps next, typically the batch log processor, so
+// that log records of blocked subjects and traces never reach it.
+func NewBlocklistLogProcessor(next log.Processor, b *Blocklist) log.Processor {
+	return &blocklistLogProcessor{next: next, list: b}
+}
+
+type blocklistLogProcessor struct {
+	next log.Processor
+	list *Blocklist
+}
+
+func (p *blocklistLogProcessor) OnEmit(ctx context.Context, r *log.Record) error {
+	if !p.list.empty() {
+		if p.list.traceBlocked(r.TraceID()) {
+			return nil
+		}
+		if p.list.matchBaggage(ctx) || p.matchRecord(r) {
+			p.list.BlockTrace(r.TraceID())
+			return nil
+		}
+	}
+	return p.next.OnEmit(ctx, r)
+}
+
+func (p *blocklistLogProcessor) matchRecord(r *log.Record) bool {
+	matched := false
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		if slices.Contains(p.list.keys, kv.Key) && p.list.blockedValue(kv.Value.String()) {
+			matched = true
+		}
+		return !matched
+	})
+	return matched
+}
+
+func (p *blocklistLogProcessor) Enabled(ctx context.Context, param log.EnabledParameters) bool {
+	return p.next.Enabled(ctx, param)
+}
+
+func (p *blocklistLogProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *blocklistLogProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newBlocklistTracer(values ...string) (*Blocklist, trace.Tracer, *tracetest.SpanRecorder) {
+	b := NewBlocklist(config.BlocklistConfig{Keys: []string{"user.id", "enduser.id"}, Values: values})
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(NewBlocklistSpanProcessor(recorder, b)))
+	return b, tp.Tracer("test"), recorder
+}
+
+func endedNames(r *tracetest.SpanRecorder) []string {
+	var names []string
+	for _, s := range r.Ended() {
+		names = append(names, s.Name())
+	}
+	return names
+}
+
+func TestBlocklistSpanProcessor_DropsTracesOfBlockedBaggage(t *testing.T) {
+	_, tracer, recorder := newBlocklistTracer("u-42")
+
+	member, _ := baggage.NewMember("user.id", "u-42")
+	bag, _ := baggage.New(member)
+	ctx, root := tracer.Start(baggage.ContextWithBaggage(context.Background(), bag), "blocked-root")
+	_, child := tracer.Start(ctx, "blocked-child")
+	child.End()
+	root.End()
+
+	_, other := tracer.Start(context.Background(), "kept")
+	other.End()
+
+	if got := endedNames(recorder); len(got) != 1 || got[0] != "kept" {
+		t.Errorf("exported spans = %v, want only kept", got)
+	}
+}
+
+func TestBlocklistSpanProcessor_AttributeSetLateBlocksRestOfTrace(t *testing.T) {
+	_, tracer, recorder := newBlocklistTracer("u-42")
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, auth := tracer.Start(ctx, "auth")
+	auth.SetAttributes(attribute.String("enduser.id", "u-42"))
+	auth.End()
+	_, after := tracer.Start(ctx, "after-auth")
+	after.End()
+	root.End()
+
+	if got := endedNames(recorder); len(got) != 0 {
+		t.Errorf("exported spans = %v, want none", got)
+	}
+}
+
+func TestBlocklist_AddAndRemoveAtRuntime(t *testing.T) {
+	b, tracer, recorder := newBlocklistTracer()
+
+	start := func(name string) {
+		_, s := tracer.Start(context.Background(), name, trace.WithAttributes(attribute.String("user.id", "u-7")))
+		s.End()
+	}
+	start("before-add")
+	b.Add("u-7")
+	start("while-blocked")
+	b.Remove("u-7")
+	start("after-remove")
+
+	if got := endedNames(recorder); len(got) != 2 || got[0] != "before-add" || got[1] != "after-remove" {
+		t.Errorf("exported spans = %v", got)
+	}
+	if len(b.Values()) != 0 {
+		t.Errorf("Values = %v, want empty after Remove", b.Values())
+	}
+}
+
+func TestBlocklist_BlockedTracesExpire(t *testing.T) {
+	b := NewBlocklist(config.BlocklistConfig{})
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	b.now = clock.now
+
+	id := trace.TraceID{1}
+	b.BlockTrace(id)
+	if !b.traceBlocked(id) {
+		t.Fatal("expected trace to be blocked")
+	}
+	clock.advance(blockedTraceTTL + time.Second)
+	if b.traceBlocked(id) {
+		t.Error("expected blocked trace to expire")
+	}
+}
+
+func TestBlocklist_FullSetEvictsOldestTraceInsteadOfFailingOpen(t *testing.T) {
+	b := NewBlocklist(config.BlocklistConfig{})
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	b.now = clock.now
+
+	traceID := func(i int) trace.TraceID {
+		var id trace.TraceID
+		id[0], id[1], id[2] = 1, byte(i>>8), byte(i)
+		return id
+	}
+	for i := range maxBlockedTraces {
+		b.BlockTrace(traceID(i))
+		clock.advance(time.Millisecond)
+	}
+
+	newest := trace.TraceID{0xff}
+	b.BlockTrace(newest)
+	if !b.traceBlocked(newest) {
+		t.Fatal("new trace not blocked with the blocked-trace set full")
+	}
+	if b.traceBlocked(traceID(0)) || !b.traceBlocked(traceID(1)) {
+		t.Error("expected only the trace matched longest ago to be evicted")
+	}
+	if got := b.Evicted(); got != 1 {
+		t.Errorf("Evicted() = %d, want 1", got)
+	}
+}
+
+type recordingLogProcessor struct{ bodies []string }
+
+func (p *recordingLogProcessor) OnEmit(_ context.Context, r *log.Record) error {
+	p.bodies = append(p.bodies, r.Body().AsString())
+	return nil
+}
+func (p *recordingLogProcessor) Enabled(context.Context, log.EnabledParameters) bool { return true }
+func (p *recordingLogProcessor) Shutdown(context.Context) error                      { return nil }
+func (p *recordingLogProcessor) ForceFlush(context.Context) error                    { return nil }
+
+func TestBlocklistLogProcessor_DropsBlockedRecordsAndTraces(t *testing.T) {
+	b := NewBlocklist(config.BlocklistConfig{Keys: []string{"user.id"}, Values: []string{"u-42"}})
+	next := &recordingLogProcessor{}
+	p := NewBlocklistLogProcessor(next, b)
+
+	traceCtx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{9}, SpanID: trace.SpanID{1},
+	}))
+	logger := log.NewLoggerProvider(log.WithProcessor(p)).Logger("test")
+	emit := func(ctx context.Context, body string, attrs ...otellog.KeyValue) {
+		var r otellog.Record
+		r.SetBody(otellog.StringValue(body))
+		r.AddAttributes(attrs...)
+		logger.Emit(ctx, r)
+	}
+
+	emit(traceCtx, "login", otellog.String("user.id", "u-42"))
+	emit(traceCtx, "same trace, no identifier")
+	emit(context.Background(), "unrelated", otellog.String("user.id", "u-1"))
+
+	if len(next.bodies) != 1 || next.bodies[0] != "unrelated" {
+		t.Errorf("forwarded records = %v, want only unrelated", next.bodies)
+	}
+}
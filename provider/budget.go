@@ -0,0 +1,257 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Resource budget states, as in BudgetStatus.State.
+const (
+	BudgetNormal   = "normal"
+	BudgetDegraded = "degraded"
+)
+
+const (
+	// degradedSamplingRatio is the share of sampled traces kept while the
+	// budget is exceeded.
+	degradedSamplingRatio = 0.1
+	// budgetRecoverRatio is how far below every limit usage must fall
+	// before degradation ends, so the agent does not flap at the limit.
+	budgetRecoverRatio = 0.8
+)
+
+// rmHeapObjects is the runtime/metrics name of the heap held by objects.
+const rmHeapObjects = "/memory/classes/heap/objects:bytes"
+
+// BudgetUsage is the resource usage measured by a ResourceBudget.
+type BudgetUsage struct {
+	// HeapBytes is the heap occupied by objects, live or not yet swept.
+	HeapBytes int64 `json:"heap_bytes"`
+	// CPU is the share (0-1) of the available CPUs (GOMAXPROCS) the process
+	// used, user and system time.
+	CPU float64 `json:"cpu"`
+}
+
+// BudgetStatus reports whether the agent is degraded by its resource budget.
+type BudgetStatus struct {
+	State   string      `json:"state"`
+	Since   time.Time   `json:"since"`
+	Reasons []string    `json:"reasons,omitempty"`
+	Usage   BudgetUsage `json:"usage"`
+	// MaxHeapBytes is the effective memory budget: the lower of
+	// MaxMemoryUsage and MemoryLimitPercent of GOMEMLIMIT, 0 for none.
+	MaxHeapBytes int64   `json:"max_heap_bytes,omitempty"`
+	MaxCPU       float64 `json:"max_cpu,omitempty"`
+}
+
+// ResourceBudget enforces Performance.MaxMemoryUsage, MemoryLimitPercent and
+// MaxCPUUsage on the process. The Go runtime does not account memory or CPU
+// per library, so the budgets apply to the whole process: when it is under
+// pressure the agent sheds its own work. Each Check measures usage; when a
+// budget is exceeded the agent degrades (fewer sampled traces, no body
+// capture, slower collectors) until usage is back under 80% of every
+// budget. All methods are safe on a nil *ResourceBudget, which never
+// degrades.
+type ResourceBudget struct {
+	maxHeap int64
+	maxCPU  float64
+	log     logger.Logger
+
+	degraded atomic.Bool
+
+	mu        sync.Mutex
+	status    BudgetStatus
+	lastCheck time.Time
+	lastCPU   time.Duration
+	onChange  []func(degraded bool)
+
+	// cpuTime returns the CPU time used by the process, false where it
+	// cannot be read; heapBytes returns BudgetUsage.HeapBytes.
+	cpuTime   func() (time.Duration, bool)
+	heapBytes func() int64
+	now       func() time.Time
+}
+
+// NewResourceBudget creates the budget described by perf.
+func NewResourceBudget(perf config.PerformanceConfig, log logger.Logger) *ResourceBudget {
+	b := &ResourceBudget{
+		maxHeap: perf.MaxMemoryUsage,
+		maxCPU:  perf.MaxCPUUsage,
+		log:     log,
+
+		cpuTime:   processCPUTime,
+		heapBytes: heapObjectBytes,
+		now:       time.Now,
+	}
+	if limit := debug.SetMemoryLimit(-1); limit != math.MaxInt64 && perf.MemoryLimitPercent > 0 {
+		share := limit / 100 * int64(perf.MemoryLimitPercent)
+		if b.maxHeap <= 0 || share < b.maxHeap {
+			b.maxHeap = share
+		}
+	}
+	b.lastCheck = b.now()
+	b.lastCPU, _ = b.cpuTime()
+	b.status = BudgetStatus{State: BudgetNormal, Since: b.lastCheck, MaxHeapBytes: b.maxHeap, MaxCPU: b.maxCPU}
+	return b
+}
+
+// Degraded reports whether a budget is currently exceeded.
+func (b *ResourceBudget) Degraded() bool {
+	return b != nil && b.degraded.Load()
+}
+
+// Status returns the current state and the usage measured by the last Check.
+func (b *ResourceBudget) Status() BudgetStatus {
+	if b == nil {
+		return BudgetStatus{State: BudgetNormal}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := b.status
+	status.Reasons = append([]string(nil), status.Reasons...)
+	return status
+}
+
+// OnChange calls fn with the new state on every transition.
+func (b *ResourceBudget) OnChange(fn func(degraded bool)) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onChange = append(b.onChange, fn)
+}
+
+// Check measures the process's usage since the previous Check and degrades
+// or recovers accordingly, logging the transition.
+func (b *ResourceBudget) Check(ctx context.Context) {
+	if b == nil {
+		return
+	}
+	var usage BudgetUsage
+	b.mu.Lock()
+	now := b.now()
+	wall := now.Sub(b.lastCheck)
+	b.lastCheck = now
+	if cpu, ok := b.cpuTime(); ok {
+		if wall > 0 {
+			usage.CPU = float64(cpu-b.lastCPU) / float64(wall) / float64(runtime.GOMAXPROCS(0))
+		}
+		b.lastCPU = cpu
+	}
+	b.mu.Unlock()
+
+	if b.maxHeap > 0 {
+		usage.HeapBytes = b.heapBytes()
+	}
+	b.evaluate(ctx, now, usage)
+}
+
+func (b *ResourceBudget) evaluate(ctx context.Context, now time.Time, usage BudgetUsage) {
+	var reasons []string
+	recovered := true
+	if b.maxHeap > 0 {
+		if usage.HeapBytes > b.maxHeap {
+			reasons = append(reasons, fmt.Sprintf("heap %d bytes over budget of %d", usage.HeapBytes, b.maxHeap))
+		}
+		recovered = recovered && float64(usage.HeapBytes) < float64(b.maxHeap)*budgetRecoverRatio
+	}
+	if b.maxCPU > 0 {
+		if usage.CPU > b.maxCPU {
+			reasons = append(reasons, fmt.Sprintf("cpu %.3f over budget of %.3f", usage.CPU, b.maxCPU))
+		}
+		recovered = recovered && usage.CPU < b.maxCPU*budgetRecoverRatio
+	}
+
+	b.mu.Lock()
+	was := b.status.State == BudgetDegraded
+	degraded := len(reasons) > 0 || was && !recovered
+	b.status.Usage = usage
+	if degraded && len(reasons) > 0 {
+		b.status.Reasons = reasons
+	}
+	var callbacks []func(bool)
+	if degraded != was {
+		b.status.Since = now
+		b.status.State = BudgetNormal
+		if degraded {
+			b.status.State = BudgetDegraded
+		} else {
+			b.status.Reasons = nil
+		}
+		callbacks = append(callbacks, b.onChange...)
+	}
+	b.mu.Unlock()
+
+	if degraded == was {
+		return
+	}
+	b.degraded.Store(degraded)
+	fields := logger.Fields{"heap_bytes": usage.HeapBytes, "cpu": usage.CPU}
+	if degraded {
+		fields["reasons"] = reasons
+		b.log.Warning(ctx, "Agent over its resource budget; degrading telemetry", fields)
+	} else {
+		b.log.Info(ctx, "Agent back under its resource budget; telemetry restored", fields)
+	}
+	for _, fn := range callbacks {
+		fn(degraded)
+	}
+}
+
+// heapObjectBytes reads the heap held by objects from runtime/metrics,
+// which, unlike runtime.ReadMemStats, does not stop the world.
+func heapObjectBytes() int64 {
+	sample := []metrics.Sample{{Name: rmHeapObjects}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return int64(sample[0].Value.Uint64())
+}
+
+// WithTraceBudget degrades the trace pipeline while b is exceeded.
+func WithTraceBudget(b *ResourceBudget) TraceProviderOption {
+	return func(o *traceProviderOptions) {
+		o.budget = b
+	}
+}
+
+// budgetSampler keeps degradedSamplingRatio of the root spans next samples
+// while the budget is exceeded. Children follow their root.
+type budgetSampler struct {
+	next   sdktrace.Sampler
+	budget *ResourceBudget
+	ratio  sdktrace.Sampler
+}
+
+func newBudgetSampler(next sdktrace.Sampler, b *ResourceBudget) budgetSampler {
+	return budgetSampler{next: next, budget: b, ratio: sdktrace.TraceIDRatioBased(degradedSamplingRatio)}
+}
+
+func (s budgetSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	res := s.next.ShouldSample(p)
+	if res.Decision == sdktrace.RecordAndSample && s.budget.Degraded() &&
+		!trace.SpanContextFromContext(p.ParentContext).IsValid() &&
+		s.ratio.ShouldSample(p).Decision == sdktrace.Drop {
+		res.Decision = sdktrace.Drop
+		res.Attributes = nil
+	}
+	return res
+}
+
+func (s budgetSampler) Description() string {
+	return s.next.Description()
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package provider
+
+import "time"
+
+// processCPUTime is not implemented on this platform; the CPU budget is not
+// checked.
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
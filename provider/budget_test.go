@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestResourceBudget_DegradesAndRecoversWithHysteresis(t *testing.T) {
+	b := NewResourceBudget(config.PerformanceConfig{MaxMemoryUsage: 1000, MaxCPUUsage: 0.1}, &logger.NoopLogger{})
+	var transitions []bool
+	b.OnChange(func(degraded bool) { transitions = append(transitions, degraded) })
+
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	steps := []struct {
+		usage    BudgetUsage
+		degraded bool
+	}{
+		{BudgetUsage{HeapBytes: 500, CPU: 0.05}, false},
+		{BudgetUsage{HeapBytes: 1500, CPU: 0.05}, true},
+		{BudgetUsage{HeapBytes: 900, CPU: 0.05}, true}, // under budget, not yet under 80%
+		{BudgetUsage{HeapBytes: 700, CPU: 0.09}, true}, // CPU still above 80%
+		{BudgetUsage{HeapBytes: 700, CPU: 0.05}, false},
+		{BudgetUsage{HeapBytes: 700, CPU: 0.2}, true},
+	}
+	for i, step := range steps {
+		now = now.Add(time.Second)
+		b.evaluate(ctx, now, step.usage)
+		if b.Degraded() != step.degraded {
+			t.Fatalf("step %d (%+v): Degraded() = %v, want %v", i, step.usage, b.Degraded(), step.degraded)
+		}
+	}
+
+	if len(transitions) != 3 || !transitions[0] || transitions[1] || !transitions[2] {
+		t.Errorf("transitions = %v, want degrade, recover, degrade", transitions)
+	}
+	status := b.Status()
+	if status.State != BudgetDegraded || !status.Since.Equal(now) || len(status.Reasons) != 1 || status.Usage.CPU != 0.2 {
+		t.Errorf("Status() = %+v", status)
+	}
+}
+
+func TestResourceBudget_CheckMeasuresProcessCPU(t *testing.T) {
+	b := NewResourceBudget(config.PerformanceConfig{MaxCPUUsage: 0.5}, &logger.NoopLogger{})
+	start := time.Unix(1700000000, 0)
+	b.now = func() time.Time { return start }
+	b.lastCheck = start
+	b.lastCPU = time.Minute
+
+	// The process kept every CPU busy for the whole second.
+	b.cpuTime = func() (time.Duration, bool) {
+		return time.Minute + time.Second*time.Duration(runtime.GOMAXPROCS(0)), true
+	}
+	b.now = func() time.Time { return start.Add(time.Second) }
+	b.Check(context.Background())
+
+	if got := b.Status().Usage.CPU; got < 0.99 || got > 1.01 {
+		t.Errorf("CPU = %v, want 1", got)
+	}
+	if !b.Degraded() {
+		t.Error("not degraded with CPU over budget")
+	}
+}
+
+func TestResourceBudget_SlowExportsAreNotCPU(t *testing.T) {
+	b := NewResourceBudget(config.PerformanceConfig{MaxCPUUsage: 0.5}, &logger.NoopLogger{})
+	b.lastCheck = time.Now().Add(-100 * time.Millisecond)
+
+	// Waiting on an unreachable collector takes no CPU
+	time.Sleep(100 * time.Millisecond)
+	b.Check(context.Background())
+
+	if b.Degraded() {
+		t.Errorf("degraded while idle: %+v", b.Status())
+	}
+}
+
+func TestHeapObjectBytes(t *testing.T) {
+	held := make([]byte, 4<<20)
+	if got := heapObjectBytes(); got < int64(len(held)) {
+		t.Errorf("heapObjectBytes() = %d, want at least the 4MiB held by the test", got)
+	}
+	runtime.KeepAlive(held)
+}
+
+func TestBudgetSampler_ThinsRootsOnlyWhileDegraded(t *testing.T) {
+	b := NewResourceBudget(config.PerformanceConfig{MaxCPUUsage: 0.1}, &logger.NoopLogger{})
+	s := newBudgetSampler(sdktrace.AlwaysSample(), b)
+
+	root := func(frac float64) sdktrace.SamplingDecision {
+		return s.ShouldSample(sdktrace.SamplingParameters{ParentContext: context.Background(), TraceID: traceIDAt(frac)}).Decision
+	}
+	if root(0.5) != sdktrace.RecordAndSample {
+		t.Fatal("root dropped before the budget is exceeded")
+	}
+
+	b.evaluate(context.Background(), time.Now(), BudgetUsage{CPU: 1})
+	if root(0.5) != sdktrace.Drop || root(0.05) != sdktrace.RecordAndSample {
+		t.Error("degraded sampler does not keep exactly the lowest 10% of trace IDs")
+	}
+
+	parent := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceIDAt(0.5), SpanID: trace.SpanID{1}, TraceFlags: trace.FlagsSampled,
+	}))
+	if d := s.ShouldSample(sdktrace.SamplingParameters{ParentContext: parent, TraceID: traceIDAt(0.5)}).Decision; d != sdktrace.RecordAndSample {
+		t.Errorf("child of a sampled parent = %v, want it kept", d)
+	}
+}
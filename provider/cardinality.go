@@ -0,0 +1,289 @@
+package provider
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// overflowKey marks the data point into which the SDK aggregates the
+// measurements of attribute sets beyond the cardinality limit.
+const overflowKey = attribute.Key("otel.metric.overflow")
+
+// cardinalityMetricExporter truncates long string attribute values and warns,
+// once per instrument, when an instrument hits the cardinality limit.
+type cardinalityMetricExporter struct {
+	metric.Exporter
+	maxLength int
+	log       logger.Logger
+	warned    sync.Map // scope + instrument name -> struct{}
+}
+
+func (e *cardinalityMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	for _, sm := range rm.ScopeMetrics {
+		for i := range sm.Metrics {
+			var overflow bool
+			sm.Metrics[i].Data, overflow = e.guard(sm.Metrics[i].Data)
+			if overflow {
+				e.warnOverflow(ctx, sm.Scope.Name, sm.Metrics[i].Name)
+			}
+		}
+	}
+	return e.Exporter.Export(ctx, rm)
+}
+
+// guard truncates the attributes of every data point and reports whether one
+// of them is the overflow data point. The SDK has already aggregated the
+// points by their full attributes, so points whose truncated attributes
+// collide are merged into one rather than exported as duplicate series.
+func (e *cardinalityMetricExporter) guard(data metricdata.Aggregation) (metricdata.Aggregation, bool) {
+	var overflow bool
+	switch d := data.(type) {
+	case metricdata.Sum[int64]:
+		d.DataPoints, overflow = truncatePoints(d.DataPoints, e.maxLength, mergeSum[int64])
+		return d, overflow
+	case metricdata.Sum[float64]:
+		d.DataPoints, overflow = truncatePoints(d.DataPoints, e.maxLength, mergeSum[float64])
+		return d, overflow
+	case metricdata.Gauge[int64]:
+		d.DataPoints, overflow = truncatePoints(d.DataPoints, e.maxLength, mergeGauge[int64])
+		return d, overflow
+	case metricdata.Gauge[float64]:
+		d.DataPoints, overflow = truncatePoints(d.DataPoints, e.maxLength, mergeGauge[float64])
+		return d, overflow
+	case metricdata.Histogram[int64]:
+		d.DataPoints, overflow = truncatePoints(d.DataPoints, e.maxLength, mergeHistogram[int64])
+		return d, overflow
+	case metricdata.Histogram[float64]:
+		d.DataPoints, overflow = truncatePoints(d.DataPoints, e.maxLength, mergeHistogram[float64])
+		return d, overflow
+	case metricdata.ExponentialHistogram[int64]:
+		d.DataPoints, overflow = truncatePoints(d.DataPoints, e.maxLength, mergeExponentialHistogram[int64])
+		return d, overflow
+	case metricdata.ExponentialHistogram[float64]:
+		d.DataPoints, overflow = truncatePoints(d.DataPoints, e.maxLength, mergeExponentialHistogram[float64])
+		return d, overflow
+	case metricdata.Summary:
+		// Quantiles cannot be merged; colliding summaries keep their
+		// full attributes.
+		d.DataPoints, overflow = truncatePoints(d.DataPoints, e.maxLength, func(*metricdata.SummaryDataPoint, metricdata.SummaryDataPoint) bool {
+			return false
+		})
+		return d, overflow
+	}
+	return data, false
+}
+
+// dataPoint is a metricdata data point type.
+type dataPoint interface {
+	metricdata.DataPoint[int64] | metricdata.DataPoint[float64] |
+		metricdata.HistogramDataPoint[int64] | metricdata.HistogramDataPoint[float64] |
+		metricdata.ExponentialHistogramDataPoint[int64] | metricdata.ExponentialHistogramDataPoint[float64] |
+		metricdata.SummaryDataPoint
+}
+
+// truncatePoints truncates the attributes of points in place, merging a
+// point into an earlier one with the same truncated attributes. A point merge
+// cannot absorb keeps its full attributes. It reports whether one of the
+// points is the overflow data point.
+func truncatePoints[P dataPoint](points []P, maxLength int, merge func(dst *P, src P) bool) ([]P, bool) {
+	var overflow bool
+	seen := make(map[attribute.Distinct]int, len(points))
+	out := points[:0]
+	for _, p := range points {
+		attrs := pointAttributes(&p)
+		if attrs.HasValue(overflowKey) {
+			overflow = true
+		}
+		truncated := truncateAttributes(*attrs, maxLength)
+		if i, ok := seen[truncated.Equivalent()]; ok {
+			if merge(&out[i], p) {
+				continue
+			}
+		} else {
+			*attrs = truncated
+			seen[truncated.Equivalent()] = len(out)
+		}
+		out = append(out, p)
+	}
+	return out, overflow
+}
+
+// pointAttributes returns the attributes of p.
+func pointAttributes[P dataPoint](p *P) *attribute.Set {
+	switch p := any(p).(type) {
+	case *metricdata.DataPoint[int64]:
+		return &p.Attributes
+	case *metricdata.DataPoint[float64]:
+		return &p.Attributes
+	case *metricdata.HistogramDataPoint[int64]:
+		return &p.Attributes
+	case *metricdata.HistogramDataPoint[float64]:
+		return &p.Attributes
+	case *metricdata.ExponentialHistogramDataPoint[int64]:
+		return &p.Attributes
+	case *metricdata.ExponentialHistogramDataPoint[float64]:
+		return &p.Attributes
+	case *metricdata.SummaryDataPoint:
+		return &p.Attributes
+	}
+	panic("unreachable")
+}
+
+// mergeSum adds src to dst.
+func mergeSum[N int64 | float64](dst *metricdata.DataPoint[N], src metricdata.DataPoint[N]) bool {
+	dst.Value += src.Value
+	mergeTimes(&dst.StartTime, &dst.Time, src.StartTime, src.Time)
+	dst.Exemplars = append(dst.Exemplars, src.Exemplars...)
+	return true
+}
+
+// mergeGauge keeps the most recent of dst and src.
+func mergeGauge[N int64 | float64](dst *metricdata.DataPoint[N], src metricdata.DataPoint[N]) bool {
+	if src.Time.After(dst.Time) {
+		dst.Value = src.Value
+	}
+	mergeTimes(&dst.StartTime, &dst.Time, src.StartTime, src.Time)
+	dst.Exemplars = append(dst.Exemplars, src.Exemplars...)
+	return true
+}
+
+// mergeHistogram adds src to dst when both have the same bucket bounds.
+func mergeHistogram[N int64 | float64](dst *metricdata.HistogramDataPoint[N], src metricdata.HistogramDataPoint[N]) bool {
+	if !slices.Equal(dst.Bounds, src.Bounds) || len(dst.BucketCounts) != len(src.BucketCounts) {
+		return false
+	}
+	counts := slices.Clone(dst.BucketCounts)
+	for i, c := range src.BucketCounts {
+		counts[i] += c
+	}
+	dst.BucketCounts = counts
+	dst.Count += src.Count
+	dst.Sum += src.Sum
+	dst.Min = mergeExtrema(dst.Min, src.Min, true)
+	dst.Max = mergeExtrema(dst.Max, src.Max, false)
+	mergeTimes(&dst.StartTime, &dst.Time, src.StartTime, src.Time)
+	dst.Exemplars = append(dst.Exemplars, src.Exemplars...)
+	return true
+}
+
+// mergeExponentialHistogram adds src to dst at the coarser of their scales.
+func mergeExponentialHistogram[N int64 | float64](dst *metricdata.ExponentialHistogramDataPoint[N], src metricdata.ExponentialHistogramDataPoint[N]) bool {
+	if dst.ZeroThreshold != src.ZeroThreshold {
+		return false
+	}
+	scale := min(dst.Scale, src.Scale)
+	dst.PositiveBucket = mergeExponentialBuckets(dst.PositiveBucket, dst.Scale, src.PositiveBucket, src.Scale, scale)
+	dst.NegativeBucket = mergeExponentialBuckets(dst.NegativeBucket, dst.Scale, src.NegativeBucket, src.Scale, scale)
+	dst.Scale = scale
+	dst.Count += src.Count
+	dst.Sum += src.Sum
+	dst.ZeroCount += src.ZeroCount
+	dst.Min = mergeExtrema(dst.Min, src.Min, true)
+	dst.Max = mergeExtrema(dst.Max, src.Max, false)
+	mergeTimes(&dst.StartTime, &dst.Time, src.StartTime, src.Time)
+	dst.Exemplars = append(dst.Exemplars, src.Exemplars...)
+	return true
+}
+
+// mergeExponentialBuckets adds buckets a (at scale aScale) and b (at bScale)
+// at scale, which is not finer than either. Lowering the scale by one merges
+// each pair of adjacent buckets, so an index is shifted right by the
+// difference.
+func mergeExponentialBuckets(a metricdata.ExponentialBucket, aScale int32, b metricdata.ExponentialBucket, bScale, scale int32) metricdata.ExponentialBucket {
+	if len(a.Counts) == 0 && len(b.Counts) == 0 {
+		return metricdata.ExponentialBucket{}
+	}
+	lo, hi := int32(math.MaxInt32), int32(math.MinInt32)
+	for _, bk := range []struct {
+		metricdata.ExponentialBucket
+		scale int32
+	}{{a, aScale}, {b, bScale}} {
+		if n := len(bk.Counts); n > 0 {
+			lo = min(lo, bk.Offset>>(bk.scale-scale))
+			hi = max(hi, (bk.Offset+int32(n)-1)>>(bk.scale-scale))
+		}
+	}
+	merged := metricdata.ExponentialBucket{Offset: lo, Counts: make([]uint64, hi-lo+1)}
+	for _, bk := range []struct {
+		metricdata.ExponentialBucket
+		scale int32
+	}{{a, aScale}, {b, bScale}} {
+		for i, c := range bk.Counts {
+			merged.Counts[(bk.Offset+int32(i))>>(bk.scale-scale)-lo] += c
+		}
+	}
+	return merged
+}
+
+// mergeExtrema combines two optional minimums, or maximums.
+func mergeExtrema[N int64 | float64](a, b metricdata.Extrema[N], minimum bool) metricdata.Extrema[N] {
+	av, aok := a.Value()
+	bv, bok := b.Value()
+	switch {
+	case aok && bok:
+		if (bv < av) == minimum {
+			return b
+		}
+		return a
+	case bok:
+		return b
+	}
+	return a
+}
+
+// mergeTimes widens the [start, end] window to cover [srcStart, srcEnd].
+func mergeTimes(start, end *time.Time, srcStart, srcEnd time.Time) {
+	if srcStart.Before(*start) {
+		*start = srcStart
+	}
+	if srcEnd.After(*end) {
+		*end = srcEnd
+	}
+}
+
+func (e *cardinalityMetricExporter) warnOverflow(ctx context.Context, scope, name string) {
+	if _, seen := e.warned.LoadOrStore(scope+"/"+name, struct{}{}); seen || e.log == nil {
+		return
+	}
+	e.log.Warning(ctx, "Metric cardinality limit reached; new attribute sets go to the otel.metric.overflow data point", logger.Fields{
+		"metric": name, "scope": scope,
+	})
+}
+
+// truncateAttributes shortens string values longer than maxLength bytes,
+// keeping them valid UTF-8. A maxLength of zero or less keeps set unchanged.
+func truncateAttributes(set attribute.Set, maxLength int) attribute.Set {
+	if maxLength <= 0 {
+		return set
+	}
+	kvs := set.ToSlice()
+	changed := false
+	for i, kv := range kvs {
+		if v := kv.Value.AsString(); kv.Value.Type() == attribute.STRING && len(v) > maxLength {
+			kvs[i] = kv.Key.String(truncateString(v, maxLength))
+			changed = true
+		}
+	}
+	if !changed {
+		return set
+	}
+	return attribute.NewSet(kvs...)
+}
+
+// truncateString cuts s to at most maxLength bytes without splitting a rune.
+func truncateString(s string, maxLength int) string {
+	n := maxLength
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
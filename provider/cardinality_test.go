@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// capturingMetricExporter keeps the last exported batch; the embedded
+// exporter is nil and only Export may be called.
+type capturingMetricExporter struct {
+	metric.Exporter
+	got *metricdata.ResourceMetrics
+}
+
+func (c *capturingMetricExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	c.got = rm
+	return nil
+}
+
+// warningLogger counts Warning calls.
+type warningLogger struct {
+	logger.NoopLogger
+	mu       sync.Mutex
+	warnings []logger.Fields
+}
+
+func (w *warningLogger) Warning(_ context.Context, _ string, fields ...logger.Fields) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.warnings = append(w.warnings, fields...)
+}
+
+func collectCounter(t *testing.T, opts []metric.Option, record func(otelmetric.Int64Counter)) *metricdata.ResourceMetrics {
+	t.Helper()
+	reader := metric.NewManualReader()
+	mp := metric.NewMeterProvider(append(opts, metric.WithReader(reader))...)
+	counter, err := mp.Meter("test").Int64Counter("requests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record(counter)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	return &rm
+}
+
+func TestCardinalityMetricExporter_TruncatesStringAttributes(t *testing.T) {
+	rm := collectCounter(t, nil, func(c otelmetric.Int64Counter) {
+		c.Add(context.Background(), 1, otelmetric.WithAttributes(
+			attribute.String("route", "/orders/123456"),
+			attribute.String("region", "héé"),
+			attribute.Int("status", 200),
+		))
+	})
+
+	inner := &capturingMetricExporter{}
+	exp := &cardinalityMetricExporter{Exporter: inner, maxLength: 4}
+	if err := exp.Export(context.Background(), rm); err != nil {
+		t.Fatal(err)
+	}
+
+	attrs := inner.got.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64]).DataPoints[0].Attributes
+	if v, _ := attrs.Value("route"); v.AsString() != "/ord" {
+		t.Errorf("route = %q, want /ord", v.AsString())
+	}
+	// "héé" is 5 bytes; cutting at 4 would split the second é.
+	if v, _ := attrs.Value("region"); v.AsString() != "hé" {
+		t.Errorf("region = %q, want hé", v.AsString())
+	}
+	if v, _ := attrs.Value("status"); v.AsInt64() != 200 {
+		t.Errorf("status = %d, want non-string attributes untouched", v.AsInt64())
+	}
+}
+
+func TestCardinalityMetricExporter_MergesPointsWhoseTruncatedAttributesCollide(t *testing.T) {
+	rm := collectCounter(t, nil, func(c otelmetric.Int64Counter) {
+		c.Add(context.Background(), 1, otelmetric.WithAttributes(attribute.String("route", "/orders/1")))
+		c.Add(context.Background(), 2, otelmetric.WithAttributes(attribute.String("route", "/orders/2")))
+		c.Add(context.Background(), 4, otelmetric.WithAttributes(attribute.String("route", "/users")))
+	})
+
+	inner := &capturingMetricExporter{}
+	exp := &cardinalityMetricExporter{Exporter: inner, maxLength: 7}
+	if err := exp.Export(context.Background(), rm); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]int64{}
+	for _, dp := range inner.got.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64]).DataPoints {
+		v, _ := dp.Attributes.Value("route")
+		got[v.AsString()] += dp.Value
+	}
+	if len(got) != 2 || got["/orders"] != 3 || got["/users"] != 4 {
+		t.Errorf("points = %v, want /orders=3 and /users=4 in one point each", got)
+	}
+	if n := len(inner.got.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64]).DataPoints); n != 2 {
+		t.Errorf("%d data points, want 2", n)
+	}
+}
+
+func TestMergeExponentialBuckets_DownscalesTheFinerBuckets(t *testing.T) {
+	// At scale 1, indexes 2..5 fall into indexes 1, 1, 2, 2 at scale 0.
+	fine := metricdata.ExponentialBucket{Offset: 2, Counts: []uint64{1, 2, 3, 4}}
+	coarse := metricdata.ExponentialBucket{Offset: 0, Counts: []uint64{10}}
+
+	got := mergeExponentialBuckets(fine, 1, coarse, 0, 0)
+	if got.Offset != 0 || !slices.Equal(got.Counts, []uint64{10, 3, 7}) {
+		t.Errorf("merged = %+v, want offset 0 counts [10 3 7]", got)
+	}
+}
+
+func TestCardinalityMetricExporter_WarnsOnceOnOverflow(t *testing.T) {
+	rm := collectCounter(t, []metric.Option{metric.WithCardinalityLimit(3)}, func(c otelmetric.Int64Counter) {
+		for _, user := range []string{"a", "b", "c", "d", "e"} {
+			c.Add(context.Background(), 1, otelmetric.WithAttributes(attribute.String("user", user)))
+		}
+	})
+
+	var overflow bool
+	for _, dp := range rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64]).DataPoints {
+		overflow = overflow || dp.Attributes.HasValue(overflowKey)
+	}
+	if !overflow {
+		t.Fatal("expected the SDK to produce an overflow data point")
+	}
+
+	log := &warningLogger{}
+	exp := &cardinalityMetricExporter{Exporter: &capturingMetricExporter{}, log: log}
+	for range 2 {
+		if err := exp.Export(context.Background(), rm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(log.warnings) != 1 || log.warnings[0]["metric"] != "requests" {
+		t.Errorf("warnings = %v, want one for requests", log.warnings)
+	}
+}
+
+func TestMetricViews_DropAttributes(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Metrics.Cardinality.DropAttributes = []string{"user_id"}
+
+	rm := collectCounter(t, []metric.Option{metric.WithView(metricViews(cfg)...)}, func(c otelmetric.Int64Counter) {
+		c.Add(context.Background(), 1, otelmetric.WithAttributes(
+			attribute.String("user_id", "42"),
+			attribute.String("route", "/orders"),
+		))
+	})
+
+	attrs := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64]).DataPoints[0].Attributes
+	if attrs.HasValue("user_id") {
+		t.Error("user_id should be dropped")
+	}
+	if !attrs.HasValue("route") {
+		t.Error("route should be kept")
+	}
+}
@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+const (
+	// ClockSkewAttribute is set on a span started while the wall clock
+	// differed from the monotonic clock by more than the tolerance: wall
+	// minus monotonic time, in milliseconds.
+	ClockSkewAttribute = "otel.clock.skew_ms"
+
+	// ClockCorrectionAttribute is set on a span whose explicit start or end
+	// timestamp was moved because it was in the future or before the start:
+	// the shift applied, in milliseconds.
+	ClockCorrectionAttribute = "otel.clock.correction_ms"
+
+	// reanchorAfter is how long a skew must last before the anchor follows
+	// the wall clock, so a clock fixed by NTP is eventually trusted while a
+	// transient jump is ignored.
+	reanchorAfter = time.Minute
+)
+
+// NewMonotonicTracerProvider returns a TracerProvider whose span and event
+// timestamps come from the monotonic clock, anchored to the wall clock when
+// it is created. An NTP step of the wall clock then no longer produces
+// negative or inflated durations, or children that start before their parent.
+//
+// Skew is annotated rather than hidden: spans started while the wall clock
+// and the anchored clock disagree by more than tolerance carry
+// otel.clock.skew_ms. Once the skew has lasted a minute the anchor is moved
+// to the wall clock. Explicit timestamps (trace.WithTimestamp) in the future,
+// or end timestamps before the start, are clamped and the span carries
+// otel.clock.correction_ms.
+func NewMonotonicTracerProvider(tp trace.TracerProvider, tolerance time.Duration) trace.TracerProvider {
+	origin := time.Now()
+	clock := newAnchoredClock(tolerance, time.Now, func() time.Duration { return time.Since(origin) })
+	return &monotonicTracerProvider{tp: tp, clock: clock}
+}
+
+// anchoredClock is a wall clock that advances with the monotonic clock.
+type anchoredClock struct {
+	tolerance time.Duration
+	wall      func() time.Time
+	elapsed   func() time.Duration // monotonic
+
+	mu            sync.Mutex
+	anchorWall    time.Time
+	anchorElapsed time.Duration
+	skewSince     time.Duration
+	skewed        bool
+}
+
+func newAnchoredClock(tolerance time.Duration, wall func() time.Time, elapsed func() time.Duration) *anchoredClock {
+	return &anchoredClock{
+		tolerance:     tolerance,
+		wall:          wall,
+		elapsed:       elapsed,
+		anchorWall:    wall().Round(0),
+		anchorElapsed: elapsed(),
+	}
+}
+
+// now returns the anchored time, the monotonic reading it was derived from,
+// and the skew of the wall clock from it, zero within tolerance.
+func (c *anchoredClock) now() (time.Time, time.Duration, time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Round(0) drops monotonic readings, so wall times are compared.
+	elapsed := c.elapsed()
+	wall := c.wall().Round(0)
+	anchored := c.anchorWall.Add(elapsed - c.anchorElapsed)
+	skew := wall.Sub(anchored)
+	switch {
+	case skew.Abs() <= c.tolerance:
+		c.skewed = false
+		return anchored, elapsed, 0
+	case !c.skewed:
+		c.skewed, c.skewSince = true, elapsed
+	case elapsed-c.skewSince >= reanchorAfter:
+		c.anchorWall, c.anchorElapsed = wall, elapsed
+		c.skewed = false
+		return wall, elapsed, 0
+	}
+	return anchored, elapsed, skew
+}
+
+type monotonicTracerProvider struct {
+	embedded.TracerProvider
+	tp    trace.TracerProvider
+	clock *anchoredClock
+}
+
+func (p *monotonicTracerProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return &monotonicTracer{tracer: p.tp.Tracer(name, opts...), provider: p}
+}
+
+type monotonicTracer struct {
+	embedded.Tracer
+	tracer   trace.Tracer
+	provider *monotonicTracerProvider
+}
+
+func (t *monotonicTracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	clock := t.provider.clock
+	now, nowElapsed, skew := clock.now()
+
+	start := now
+	var correction time.Duration
+	cfg := trace.NewSpanStartConfig(opts...)
+	if ts := cfg.Timestamp(); !ts.IsZero() {
+		start = ts
+		if ts.Sub(now) > clock.tolerance {
+			start, correction = now, now.Sub(ts)
+		}
+	}
+	opts = append(opts[:len(opts):len(opts)], trace.WithTimestamp(start))
+
+	ctx, span := t.tracer.Start(ctx, spanName, opts...)
+	if !span.IsRecording() {
+		return ctx, span
+	}
+	if skew != 0 {
+		span.SetAttributes(attribute.Int64(ClockSkewAttribute, skew.Milliseconds()))
+	}
+	if correction != 0 {
+		span.SetAttributes(attribute.Int64(ClockCorrectionAttribute, correction.Milliseconds()))
+	}
+	s := &monotonicSpan{Span: span, provider: t.provider, start: start, begin: now, beginElapsed: nowElapsed}
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+// monotonicSpan times its end and events from the monotonic time elapsed
+// since it started, so moving the anchor cannot reorder them.
+type monotonicSpan struct {
+	trace.Span
+	provider     *monotonicTracerProvider
+	start        time.Time // as recorded, possibly explicit
+	begin        time.Time // anchored time at Start
+	beginElapsed time.Duration
+}
+
+func (s *monotonicSpan) now() time.Time {
+	return s.begin.Add(s.provider.clock.elapsed() - s.beginElapsed)
+}
+
+func (s *monotonicSpan) End(opts ...trace.SpanEndOption) {
+	now := s.now()
+	end := now
+	cfg := trace.NewSpanEndConfig(opts...)
+	ts := cfg.Timestamp()
+	if !ts.IsZero() && ts.Sub(now) <= s.provider.clock.tolerance {
+		end = ts
+	}
+	if end.Before(s.start) {
+		end = s.start
+	}
+	if !ts.IsZero() && !end.Equal(ts) {
+		s.Span.SetAttributes(attribute.Int64(ClockCorrectionAttribute, end.Sub(ts).Milliseconds()))
+	}
+	s.Span.End(append(opts[:len(opts):len(opts)], trace.WithTimestamp(end))...)
+}
+
+func (s *monotonicSpan) AddEvent(name string, opts ...trace.EventOption) {
+	s.Span.AddEvent(name, s.eventOptions(opts)...)
+}
+
+func (s *monotonicSpan) RecordError(err error, opts ...trace.EventOption) {
+	s.Span.RecordError(err, s.eventOptions(opts)...)
+}
+
+// eventOptions timestamps events with the span's clock. The timestamp goes
+// first so that one given by the caller still wins.
+func (s *monotonicSpan) eventOptions(opts []trace.EventOption) []trace.EventOption {
+	return append([]trace.EventOption{trace.WithTimestamp(s.now())}, opts...)
+}
+
+// TracerProvider returns the monotonic provider, so tracers obtained from
+// the span use the anchored clock too.
+func (s *monotonicSpan) TracerProvider() trace.TracerProvider {
+	return s.provider
+}
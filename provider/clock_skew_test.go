@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// skewingHost is a host whose wall clock can jump independently of its
+// monotonic clock, as under an NTP step.
+type skewingHost struct {
+	wallOffset time.Duration
+	elapsed    time.Duration
+}
+
+var hostBoot = time.Unix(1700000000, 0)
+
+func (h *skewingHost) wall() time.Time               { return hostBoot.Add(h.elapsed + h.wallOffset) }
+func (h *skewingHost) monotonic() time.Duration      { return h.elapsed }
+func (h *skewingHost) advance(d time.Duration)       { h.elapsed += d }
+func (h *skewingHost) jumpWallClock(d time.Duration) { h.wallOffset += d }
+
+func newMonotonic(t *testing.T) (trace.Tracer, *skewingHost, *tracetest.SpanRecorder) {
+	t.Helper()
+	host := &skewingHost{}
+	recorder := tracetest.NewSpanRecorder()
+	tp := &monotonicTracerProvider{
+		tp:    sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+		clock: newAnchoredClock(time.Second, host.wall, host.monotonic),
+	}
+	return tp.Tracer("test"), host, recorder
+}
+
+func int64Attr(s sdktrace.ReadOnlySpan, key string) (int64, bool) {
+	for _, kv := range s.Attributes() {
+		if kv.Key == attribute.Key(key) {
+			return kv.Value.AsInt64(), true
+		}
+	}
+	return 0, false
+}
+
+func TestMonotonic_WallClockJumpKeepsDuration(t *testing.T) {
+	tracer, host, recorder := newMonotonic(t)
+
+	_, span := tracer.Start(context.Background(), "op")
+	host.advance(200 * time.Millisecond)
+	host.jumpWallClock(-5 * time.Second)
+	span.AddEvent("halfway")
+	host.advance(300 * time.Millisecond)
+	span.End()
+
+	s := recorder.Ended()[0]
+	if d := s.EndTime().Sub(s.StartTime()); d != 500*time.Millisecond {
+		t.Errorf("duration = %v, want 500ms despite the wall clock jump", d)
+	}
+	if got := s.Events()[0].Time.Sub(s.StartTime()); got != 200*time.Millisecond {
+		t.Errorf("event offset = %v, want 200ms", got)
+	}
+	if _, ok := int64Attr(s, ClockSkewAttribute); ok {
+		t.Error("span started before the jump carries a skew attribute")
+	}
+}
+
+func TestMonotonic_AnnotatesSkewAndReanchors(t *testing.T) {
+	tracer, host, recorder := newMonotonic(t)
+
+	host.jumpWallClock(-5 * time.Second)
+	_, span := tracer.Start(context.Background(), "skewed")
+	span.End()
+
+	s := recorder.Ended()[0]
+	if skew, _ := int64Attr(s, ClockSkewAttribute); skew != -5000 {
+		t.Errorf("%s = %d, want -5000", ClockSkewAttribute, skew)
+	}
+	if want := hostBoot; !s.StartTime().Equal(want) {
+		t.Errorf("start = %v, want anchored time %v", s.StartTime(), want)
+	}
+
+	host.advance(reanchorAfter)
+	_, span = tracer.Start(context.Background(), "reanchored")
+	span.End()
+
+	s = recorder.Ended()[1]
+	if _, ok := int64Attr(s, ClockSkewAttribute); ok {
+		t.Error("skew still annotated after the anchor followed the wall clock")
+	}
+	if want := host.wall(); !s.StartTime().Equal(want) {
+		t.Errorf("start = %v, want wall time %v after re-anchoring", s.StartTime(), want)
+	}
+}
+
+func TestMonotonic_ClampsExplicitTimestamps(t *testing.T) {
+	tracer, host, recorder := newMonotonic(t)
+
+	_, span := tracer.Start(context.Background(), "future", trace.WithTimestamp(hostBoot.Add(time.Hour)))
+	span.End()
+	s := recorder.Ended()[0]
+	if !s.StartTime().Equal(hostBoot) {
+		t.Errorf("future start = %v, want clamped to %v", s.StartTime(), hostBoot)
+	}
+	if c, _ := int64Attr(s, ClockCorrectionAttribute); c != -time.Hour.Milliseconds() {
+		t.Errorf("%s = %d, want %d", ClockCorrectionAttribute, c, -time.Hour.Milliseconds())
+	}
+
+	host.advance(time.Second)
+	start := host.wall()
+	_, span = tracer.Start(context.Background(), "backwards")
+	span.End(trace.WithTimestamp(start.Add(-2 * time.Second)))
+	s = recorder.Ended()[1]
+	if !s.EndTime().Equal(s.StartTime()) {
+		t.Errorf("end before start: duration = %v, want 0", s.EndTime().Sub(s.StartTime()))
+	}
+	if c, _ := int64Attr(s, ClockCorrectionAttribute); c != 2000 {
+		t.Errorf("%s = %d, want 2000", ClockCorrectionAttribute, c)
+	}
+}
+
+func TestMonotonic_KeepsPastExplicitStart(t *testing.T) {
+	tracer, host, recorder := newMonotonic(t)
+	host.advance(time.Minute)
+
+	queued := hostBoot.Add(10 * time.Second)
+	_, span := tracer.Start(context.Background(), "dequeued", trace.WithTimestamp(queued))
+	span.End()
+
+	s := recorder.Ended()[0]
+	if !s.StartTime().Equal(queued) {
+		t.Errorf("start = %v, want explicit %v", s.StartTime(), queued)
+	}
+	if _, ok := int64Attr(s, ClockCorrectionAttribute); ok {
+		t.Error("past explicit start was corrected")
+	}
+}
@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	ec2 "go.opentelemetry.io/contrib/detectors/aws/ec2/v2"
+	"go.opentelemetry.io/contrib/detectors/aws/ecs"
+	"go.opentelemetry.io/contrib/detectors/aws/lambda"
+	"go.opentelemetry.io/contrib/detectors/gcp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// azureIMDSEndpoint is the Azure instance metadata service, a variable so
+// tests can point it at a local server. The AWS and GCP detectors honour
+// AWS_EC2_METADATA_SERVICE_ENDPOINT and GCE_METADATA_HOST instead.
+var azureIMDSEndpoint = "http://169.254.169.254"
+
+// errNotOnCloud is returned by a cloud detector whose environment variables
+// and metadata endpoint are absent: the normal case off that cloud, so it is
+// not reported.
+var errNotOnCloud = errors.New("not running on this cloud")
+
+// cloudDetector runs the OTEL_RESOURCE_DETECTORS cloud detectors
+// concurrently, all bounded by timeout, and merges what they find in the
+// configured order. AWS and GCP use the contrib detectors; the contrib Azure
+// VM detector needs a newer OpenTelemetry than this module, so Azure reads the
+// instance metadata service itself.
+type cloudDetector struct {
+	names   []string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// newCloudDetector returns the detector for cfg.Resource.Detectors, or nil
+// if none are configured.
+func newCloudDetector(cfg *config.Config) resource.Detector {
+	if len(cfg.Resource.Detectors) == 0 {
+		return nil
+	}
+	return &cloudDetector{
+		names:   cfg.Resource.Detectors,
+		timeout: cfg.Resource.DetectorTimeout,
+		// Link-local metadata services must not go through HTTP_PROXY.
+		client: &http.Client{Transport: &http.Transport{Proxy: nil}},
+	}
+}
+
+// Detect implements resource.Detector. A detector still running when the
+// timeout expires, such as the GCP one, which ignores ctx, is left to finish
+// in the background and counts as off that cloud.
+func (d *cloudDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	type result struct {
+		i     int
+		attrs []attribute.KeyValue
+		err   error
+	}
+	results := make(chan result, len(d.names))
+	for i, name := range d.names {
+		go func() {
+			r := result{i: i}
+			switch name {
+			case config.DetectorAWS:
+				r.attrs, r.err = detectAWS(ctx)
+			case config.DetectorGCP:
+				r.attrs, r.err = detectWith(ctx, gcp.NewDetector())
+			case config.DetectorAzure:
+				r.attrs, r.err = d.detectAzure(ctx)
+			default:
+				r.err = fmt.Errorf("unsupported resource detector %q", name)
+			}
+			results <- r
+		}()
+	}
+
+	attrs := make([][]attribute.KeyValue, len(d.names))
+	errs := make([]error, len(d.names))
+collect:
+	for range d.names {
+		select {
+		case r := <-results:
+			attrs[r.i], errs[r.i] = r.attrs, r.err
+		case <-ctx.Done():
+			break collect
+		}
+	}
+
+	var all []attribute.KeyValue
+	var err error
+	for i, name := range d.names {
+		for _, kv := range attrs[i] {
+			// Metadata the platform does not expose is left out, not empty.
+			if kv.Value.Type() != attribute.STRING || kv.Value.AsString() != "" {
+				all = append(all, kv)
+			}
+		}
+		if errs[i] != nil && !errors.Is(errs[i], errNotOnCloud) {
+			err = errors.Join(err, fmt.Errorf("%s detector: %w", name, errs[i]))
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: %w", resource.ErrPartialResource, err)
+	}
+	// Schemaless, so merging with the SDK's own detectors cannot conflict.
+	return resource.NewSchemaless(all...), err
+}
+
+// detectWith runs a contrib detector and returns its attributes; an empty
+// result means the process is not on that platform.
+func detectWith(ctx context.Context, detector resource.Detector) ([]attribute.KeyValue, error) {
+	res, err := detector.Detect(ctx)
+	if res.Len() == 0 && err == nil {
+		return nil, errNotOnCloud
+	}
+	return res.Attributes(), err
+}
+
+// detectAWS recognizes Lambda and ECS by their environment and EC2 (and
+// EKS nodes) through IMDSv2.
+func detectAWS(ctx context.Context) ([]attribute.KeyValue, error) {
+	if os.Getenv("AWS_LAMBDA_FUNCTION_NAME") != "" {
+		attrs, err := detectWith(ctx, lambda.NewResourceDetector())
+		// The Lambda detector does not set the platform.
+		return append(attrs, semconv.CloudPlatformAWSLambda), err
+	}
+	if os.Getenv("ECS_CONTAINER_METADATA_URI_V4") != "" || os.Getenv("ECS_CONTAINER_METADATA_URI") != "" {
+		return detectWith(ctx, ecs.NewResourceDetector())
+	}
+
+	attrs, err := detectWith(ctx, ec2.NewResourceDetector())
+	if onKubernetes() {
+		for i, kv := range attrs {
+			if kv.Key == semconv.CloudPlatformKey {
+				attrs[i] = semconv.CloudPlatformAWSEKS
+			}
+		}
+	}
+	return attrs, err
+}
+
+// detectAzure recognizes Functions and App Service by their environment and
+// VMs (and AKS nodes) through the instance metadata service.
+func (d *cloudDetector) detectAzure(ctx context.Context) ([]attribute.KeyValue, error) {
+	if site := os.Getenv("WEBSITE_SITE_NAME"); site != "" {
+		attrs := []attribute.KeyValue{
+			semconv.CloudProviderAzure,
+			semconv.CloudRegion(os.Getenv("REGION_NAME")),
+		}
+		if os.Getenv("FUNCTIONS_WORKER_RUNTIME") != "" {
+			return append(attrs, semconv.CloudPlatformAzureFunctions, semconv.FaaSName(site)), nil
+		}
+		return append(attrs, semconv.CloudPlatformAzureAppService), nil
+	}
+
+	body, err := d.fetch(ctx, http.MethodGet, azureIMDSEndpoint+"/metadata/instance/compute?api-version=2021-02-01&format=json",
+		map[string]string{"Metadata": "true"})
+	if err != nil {
+		return nil, errNotOnCloud
+	}
+	var compute struct {
+		Location          string `json:"location"`
+		Name              string `json:"name"`
+		VMID              string `json:"vmId"`
+		VMSize            string `json:"vmSize"`
+		SubscriptionID    string `json:"subscriptionId"`
+		ResourceGroupName string `json:"resourceGroupName"`
+		Zone              string `json:"zone"`
+	}
+	if err := json.Unmarshal(body, &compute); err != nil {
+		return nil, fmt.Errorf("decode Azure instance metadata: %w", err)
+	}
+
+	platform := semconv.CloudPlatformAzureVM
+	if onKubernetes() {
+		platform = semconv.CloudPlatformAzureAKS
+	}
+	attrs := []attribute.KeyValue{
+		semconv.CloudProviderAzure,
+		platform,
+		semconv.CloudRegion(compute.Location),
+		semconv.CloudAccountID(compute.SubscriptionID),
+		semconv.HostID(compute.VMID),
+		semconv.HostName(compute.Name),
+		semconv.HostType(compute.VMSize),
+		attribute.String("azure.resourcegroup.name", compute.ResourceGroupName),
+	}
+	if compute.Zone != "" {
+		attrs = append(attrs, semconv.CloudAvailabilityZone(compute.Zone))
+	}
+	return attrs, nil
+}
+
+// fetch performs a metadata request and returns the body of a 200 response.
+func (d *cloudDetector) fetch(ctx context.Context, method, url string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s", method, url, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+func onKubernetes() bool {
+	return os.Getenv("KUBERNETES_SERVICE_HOST") != ""
+}
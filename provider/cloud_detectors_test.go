@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// metadataServer serves "METHOD /path" -> body to requests carrying header.
+func metadataServer(t *testing.T, header, value string, paths map[string]string) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := paths[r.Method+" "+r.URL.Path]
+		if !ok || r.Header.Get(header) != value {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// clearCloudEnv hides the environment variables the detectors look at, in
+// case the tests run on a cloud.
+func clearCloudEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{
+		"AWS_LAMBDA_FUNCTION_NAME", "ECS_CONTAINER_METADATA_URI", "ECS_CONTAINER_METADATA_URI_V4",
+		"AWS_EC2_METADATA_DISABLED", "KUBERNETES_SERVICE_HOST",
+		"K_SERVICE", "FUNCTION_TARGET", "WEBSITE_SITE_NAME",
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+func detect(t *testing.T, names ...string) (*resource.Resource, error) {
+	t.Helper()
+	cfg := &config.Config{Resource: config.ResourceConfig{Detectors: names, DetectorTimeout: time.Second}}
+	return newCloudDetector(cfg).Detect(context.Background())
+}
+
+func assertResource(t *testing.T, res *resource.Resource, want map[attribute.Key]string) {
+	t.Helper()
+	set := res.Set()
+	for key, value := range want {
+		if got, _ := set.Value(key); got.Emit() != value {
+			t.Errorf("%s = %q, want %q", key, got.Emit(), value)
+		}
+	}
+}
+
+func TestCloudDetector_EC2(t *testing.T) {
+	clearCloudEnv(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token" &&
+			r.Header.Get("X-aws-ec2-metadata-token-ttl-seconds") != "":
+			w.Header().Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+			_, _ = w.Write([]byte("tok"))
+		case r.URL.Path == "/latest/dynamic/instance-identity/document" &&
+			r.Header.Get("X-aws-ec2-metadata-token") == "tok":
+			_, _ = w.Write([]byte(`{"accountId":"123456789012","region":"eu-west-1","availabilityZone":"eu-west-1b",
+				"instanceId":"i-0abc","instanceType":"m6i.large","imageId":"ami-1"}`))
+		case r.URL.Path == "/latest/meta-data/hostname" && r.Header.Get("X-aws-ec2-metadata-token") == "tok":
+			_, _ = w.Write([]byte("ip-10-0-0-1.eu-west-1.compute.internal"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", srv.URL)
+
+	res, err := detect(t, config.DetectorAWS)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	assertResource(t, res, map[attribute.Key]string{
+		"cloud.provider":          "aws",
+		"cloud.platform":          "aws_ec2",
+		"cloud.account.id":        "123456789012",
+		"cloud.region":            "eu-west-1",
+		"cloud.availability_zone": "eu-west-1b",
+		"host.id":                 "i-0abc",
+		"host.type":               "m6i.large",
+		"host.name":               "ip-10-0-0-1.eu-west-1.compute.internal",
+	})
+}
+
+func TestCloudDetector_Lambda(t *testing.T) {
+	clearCloudEnv(t)
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "resize")
+	t.Setenv("AWS_LAMBDA_FUNCTION_VERSION", "$LATEST")
+	t.Setenv("AWS_LAMBDA_FUNCTION_MEMORY_SIZE", "128")
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	res, err := detect(t, config.DetectorAWS)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	assertResource(t, res, map[attribute.Key]string{
+		"cloud.platform": "aws_lambda",
+		"cloud.region":   "us-east-1",
+		"faas.name":      "resize",
+		"faas.version":   "$LATEST",
+	})
+	if _, ok := res.Set().Value("faas.instance"); ok {
+		t.Error("faas.instance is set although AWS_LAMBDA_LOG_STREAM_NAME is not")
+	}
+}
+
+func TestCloudDetector_ECS(t *testing.T) {
+	clearCloudEnv(t)
+	uri := metadataServer(t, "", "", map[string]string{
+		"GET /v4": `{"DockerId":"c1","Name":"api","ContainerARN":"arn:aws:ecs:us-west-2:111122223333:container/prod/abc/c1"}`,
+		"GET /v4/task": `{"Cluster":"arn:aws:ecs:us-west-2:111122223333:cluster/prod",
+			"TaskARN":"arn:aws:ecs:us-west-2:111122223333:task/prod/abc","Family":"api","Revision":"7",
+			"AvailabilityZone":"us-west-2a","LaunchType":"FARGATE"}`,
+	})
+	t.Setenv("ECS_CONTAINER_METADATA_URI_V4", uri+"/v4")
+
+	res, err := detect(t, config.DetectorAWS)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	assertResource(t, res, map[attribute.Key]string{
+		"cloud.platform":      "aws_ecs",
+		"cloud.region":        "us-west-2",
+		"cloud.account.id":    "111122223333",
+		"aws.ecs.task.family": "api",
+		"aws.ecs.launchtype":  "fargate",
+	})
+}
+
+func TestCloudDetector_GKE(t *testing.T) {
+	clearCloudEnv(t)
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	uri := metadataServer(t, "Metadata-Flavor", "Google", map[string]string{
+		"GET /computeMetadata/v1/project/project-id":                   "shop-prod",
+		"GET /computeMetadata/v1/instance/id":                          "8812",
+		"GET /computeMetadata/v1/instance/attributes/cluster-location": "us-central1-a",
+		"GET /computeMetadata/v1/instance/attributes/cluster-name":     "checkout",
+	})
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(uri, "http://"))
+
+	res, err := detect(t, config.DetectorGCP)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	assertResource(t, res, map[attribute.Key]string{
+		"cloud.provider":          "gcp",
+		"cloud.platform":          "gcp_kubernetes_engine",
+		"cloud.account.id":        "shop-prod",
+		"cloud.availability_zone": "us-central1-a",
+		"host.id":                 "8812",
+		"k8s.cluster.name":        "checkout",
+	})
+}
+
+func TestCloudDetector_AzureVM(t *testing.T) {
+	clearCloudEnv(t)
+	defer func(prev string) { azureIMDSEndpoint = prev }(azureIMDSEndpoint)
+	azureIMDSEndpoint = metadataServer(t, "Metadata", "true", map[string]string{
+		"GET /metadata/instance/compute": `{"location":"westeurope","name":"vm-1","vmId":"0e1f",
+			"vmSize":"Standard_D2s_v5","subscriptionId":"sub-9","resourceGroupName":"rg","zone":"2"}`,
+	})
+
+	res, err := detect(t, config.DetectorAzure)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	assertResource(t, res, map[attribute.Key]string{
+		"cloud.provider":          "azure",
+		"cloud.platform":          "azure_vm",
+		"cloud.region":            "westeurope",
+		"cloud.availability_zone": "2",
+		"host.id":                 "0e1f",
+	})
+}
+
+func TestCloudDetector_OffCloudIsQuietAndBounded(t *testing.T) {
+	clearCloudEnv(t)
+	stop := make(chan struct{})
+	hang := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-stop:
+		}
+	}))
+	defer hang.Close()
+	// The GCP detector ignores the timeout and keeps its request open.
+	defer close(stop)
+	defer func(prev string) { azureIMDSEndpoint = prev }(azureIMDSEndpoint)
+	azureIMDSEndpoint = hang.URL
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", hang.URL)
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(hang.URL, "http://"))
+
+	cfg := &config.Config{Resource: config.ResourceConfig{
+		Detectors:       []string{config.DetectorAWS, config.DetectorGCP, config.DetectorAzure},
+		DetectorTimeout: 100 * time.Millisecond,
+	}}
+	start := time.Now()
+	res, err := newCloudDetector(cfg).Detect(context.Background())
+	if err != nil {
+		t.Errorf("off-cloud detection reported %v", err)
+	}
+	if res.Len() != 0 {
+		t.Errorf("off-cloud detection found %v", res.Attributes())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("detection took %v, want it bounded by the timeout", elapsed)
+	}
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported OTLP compression values.
+const (
+	CompressionNone = config.CompressionNone
+	CompressionGzip = config.CompressionGzip
+	CompressionZstd = config.CompressionZstd
+)
+
+// NormalizeCompression lowercases and validates an OTLP compression value for
+// the given protocol. See config.NormalizeCompression.
+func NormalizeCompression(protocol, compression string) (string, error) {
+	return config.NormalizeCompression(protocol, compression)
+}
+
+// zstdEncoder is shared by all exporters; EncodeAll is safe for concurrent use.
+var zstdEncoder, _ = zstd.NewWriter(nil)
+
+// zstdTransport compresses OTLP/HTTP request bodies with zstd, since the
+// OTLP HTTP exporters only support gzip natively.
+type zstdTransport struct {
+	base http.RoundTripper
+}
+
+// newZstdHTTPClient returns an HTTP client that zstd-encodes request bodies.
+// A custom client replaces the exporter's own, so tlsCfg must be applied here.
+func newZstdHTTPClient(timeout time.Duration, tlsCfg *tls.Config) *http.Client {
+	var base http.RoundTripper = http.DefaultTransport
+	if tlsCfg != nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = tlsCfg
+		base = t
+	}
+	return &http.Client{
+		Transport: &zstdTransport{base: base},
+		Timeout:   timeout,
+	}
+}
+
+func (t *zstdTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Header.Get("Content-Encoding") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	compressed := zstdEncoder.EncodeAll(body, nil)
+
+	// RoundTrippers must not modify the caller's request.
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(compressed))
+	out.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	out.ContentLength = int64(len(compressed))
+	out.Header.Set("Content-Encoding", CompressionZstd)
+
+	return t.base.RoundTrip(out)
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestNormalizeCompression(t *testing.T) {
+	tests := []struct {
+		protocol, compression string
+		want                  string
+		wantErr               bool
+	}{
+		{"grpc", "", "none", false},
+		{"grpc", "GZIP", "gzip", false},
+		{"grpc", "none", "none", false},
+		{"grpc", "zstd", "", true},
+		{"http", "zstd", "zstd", false},
+		{"http/protobuf", " Zstd ", "zstd", false},
+		{"http", "snappy", "", true},
+		{"grpc", "snappy", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeCompression(tt.protocol, tt.compression)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NormalizeCompression(%q, %q) error = %v, wantErr %v", tt.protocol, tt.compression, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeCompression(%q, %q) = %q, want %q", tt.protocol, tt.compression, got, tt.want)
+		}
+	}
+}
+
+func TestZstdTransport_CompressesBody(t *testing.T) {
+	var encoding, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		dec, err := zstd.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("failed to create zstd reader: %v", err)
+			return
+		}
+		defer dec.Close()
+		data, _ := io.ReadAll(dec)
+		body = string(data)
+	}))
+	defer srv.Close()
+
+	resp, err := newZstdHTTPClient(0, nil).Post(srv.URL, "application/x-protobuf", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if encoding != "zstd" {
+		t.Errorf("Content-Encoding = %q, want zstd", encoding)
+	}
+	if body != "payload" {
+		t.Errorf("decoded body = %q, want payload", body)
+	}
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// The dry-run exporters replace the real ones when Features.DryRun is on,
+// so instrumentation can be checked without a collector (e.g. in CI). The
+// pipelines run as usual (sampling, processors, batching, scrubbing) but
+// each batch is only summarized in a debug log line. Exports succeed, so
+// ExportStats counts what would have been exported.
+
+type dryRunSpanExporter struct {
+	log logger.Logger
+}
+
+func (e dryRunSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	fields := SummarizeSpans(spans)
+	fields["signal"] = SignalTraces
+	e.log.Debug(ctx, "Dry run: span batch not exported", fields)
+	return nil
+}
+
+func (dryRunSpanExporter) Shutdown(context.Context) error { return nil }
+
+type dryRunMetricExporter struct {
+	log         logger.Logger
+	temporality metric.TemporalitySelector
+	aggregation metric.AggregationSelector
+}
+
+func (e dryRunMetricExporter) Temporality(k metric.InstrumentKind) metricdata.Temporality {
+	return e.temporality(k)
+}
+
+func (e dryRunMetricExporter) Aggregation(k metric.InstrumentKind) metric.Aggregation {
+	if e.aggregation != nil {
+		return e.aggregation(k)
+	}
+	return metric.DefaultAggregationSelector(k)
+}
+
+func (e dryRunMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	points := dataPointCount(rm)
+	if points == 0 {
+		return nil
+	}
+	counts := make(map[string]int)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			counts[m.Name] += metricDataPoints(m.Data)
+		}
+	}
+	e.log.Debug(ctx, "Dry run: metric batch not exported", logger.Fields{
+		"signal":      SignalMetrics,
+		"data_points": points,
+		"top_metrics": topCounts(counts, inspectTopSpanNames),
+	})
+	return nil
+}
+
+func (dryRunMetricExporter) ForceFlush(context.Context) error { return nil }
+func (dryRunMetricExporter) Shutdown(context.Context) error   { return nil }
+
+type dryRunLogExporter struct {
+	log logger.Logger
+}
+
+func (e dryRunLogExporter) Export(ctx context.Context, records []log.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	counts := make(map[string]int)
+	for _, r := range records {
+		counts[r.Severity().String()]++
+	}
+	e.log.Debug(ctx, "Dry run: log batch not exported", logger.Fields{
+		"signal":     SignalLogs,
+		"records":    len(records),
+		"severities": topCounts(counts, len(counts)),
+	})
+	return nil
+}
+
+func (dryRunLogExporter) ForceFlush(context.Context) error { return nil }
+func (dryRunLogExporter) Shutdown(context.Context) error   { return nil }
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestNewTraceProvider_DryRunSummarizesInsteadOfExporting(t *testing.T) {
+	stats := NewExportStats()
+	log := &leveledLogger{}
+	cfg := &config.Config{
+		// Nothing listens here: a real export would fail.
+		Endpoint:         "127.0.0.1:1",
+		ExporterProtocol: "grpc",
+		Insecure:         true,
+		Timeout:          time.Second,
+		Features:         config.FeaturesConfig{DryRun: true},
+		Traces: config.TracesConfig{
+			Sampling:       config.SamplingConfig{Type: "always_on"},
+			BatchTimeout:   time.Hour,
+			QueueSize:      8,
+			MaxExportBatch: 8,
+		},
+	}
+	tp, err := NewTraceProvider(cfg, resource.Empty(), log, WithTraceExportStats(stats))
+	if err != nil {
+		t.Fatalf("NewTraceProvider: %v", err)
+	}
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	for _, name := range []string{"checkout", "checkout", "refund"} {
+		_, span := tp.Tracer("test").Start(context.Background(), name)
+		span.End()
+	}
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	if st, _ := stats.Signal(SignalTraces); st.TotalExported != 3 {
+		t.Errorf("TotalExported = %d, want the 3 spans that would have been exported", st.TotalExported)
+	}
+	i := slices.IndexFunc(log.entries, func(e leveledEntry) bool { return e.message == "Dry run: span batch not exported" })
+	if i < 0 {
+		t.Fatalf("no dry-run summary logged: %+v", log.entries)
+	}
+	entry := log.entries[i]
+	if entry.level != "debug" || entry.fields["spans"] != 3 {
+		t.Errorf("summary = %s %v, want a debug line for 3 spans", entry.level, entry.fields)
+	}
+	if top := entry.fields["top_spans"].([]string); !slices.Equal(top, []string{"checkout (2)", "refund (1)"}) {
+		t.Errorf("top_spans = %v", top)
+	}
+}
@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// dynamicGrowAt and dynamicShrinkAt are the queue utilizations, checked
+	// after every export, above which batches grow and below which they
+	// shrink.
+	dynamicGrowAt   = 0.5
+	dynamicShrinkAt = 0.1
+
+	dynamicExportTimeout = 30 * time.Second
+)
+
+// DynamicBatchProcessor is a batch span processor whose batch size and
+// schedule delay follow queue utilization within the configured bounds. When
+// spans pile up it doubles the batch size and halves the delay, draining a
+// burst in fewer, larger exports; when the queue stays nearly empty it halves
+// the batch size, so a trickle of spans is exported without waiting for a
+// full batch, and relaxes the delay.
+//
+// Like the SDK batch processor it only exports sampled spans and drops spans
+// when the queue is full.
+type DynamicBatchProcessor struct {
+	exporter sdktrace.SpanExporter
+	bounds   config.DynamicBatchingConfig
+
+	queue     chan sdktrace.ReadOnlySpan
+	batchSize atomic.Int64
+	delay     atomic.Int64
+	dropped   atomic.Uint64
+
+	flush    chan chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewDynamicBatchProcessor starts a DynamicBatchProcessor exporting to
+// exporter. It starts from batchSize and delay, clamped to bounds.
+func NewDynamicBatchProcessor(exporter sdktrace.SpanExporter, bounds config.DynamicBatchingConfig, queueSize, batchSize int, delay time.Duration) *DynamicBatchProcessor {
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	bounds.MaxBatchSize = min(max(bounds.MaxBatchSize, 1), queueSize)
+	bounds.MinBatchSize = min(max(bounds.MinBatchSize, 1), bounds.MaxBatchSize)
+	bounds.MinScheduleDelay = max(bounds.MinScheduleDelay, time.Millisecond)
+	bounds.MaxScheduleDelay = max(bounds.MaxScheduleDelay, bounds.MinScheduleDelay)
+
+	p := &DynamicBatchProcessor{
+		exporter: exporter,
+		bounds:   bounds,
+		queue:    make(chan sdktrace.ReadOnlySpan, queueSize),
+		flush:    make(chan chan struct{}),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	p.batchSize.Store(int64(min(max(batchSize, bounds.MinBatchSize), bounds.MaxBatchSize)))
+	p.delay.Store(int64(min(max(delay, bounds.MinScheduleDelay), bounds.MaxScheduleDelay)))
+
+	go p.run()
+	return p
+}
+
+// BatchSize returns the current number of spans that triggers an export.
+func (p *DynamicBatchProcessor) BatchSize() int {
+	return int(p.batchSize.Load())
+}
+
+// ScheduleDelay returns the current longest wait before a partial batch is
+// exported.
+func (p *DynamicBatchProcessor) ScheduleDelay() time.Duration {
+	return time.Duration(p.delay.Load())
+}
+
+// Dropped returns the number of spans dropped because the queue was full.
+func (p *DynamicBatchProcessor) Dropped() uint64 {
+	return p.dropped.Load()
+}
+
+// OnStart does nothing.
+func (p *DynamicBatchProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+// OnEnd queues sampled spans for export.
+func (p *DynamicBatchProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+	select {
+	case <-p.stop:
+		return
+	default:
+	}
+	select {
+	case p.queue <- s:
+	default:
+		p.dropped.Add(1)
+	}
+}
+
+// ForceFlush exports every queued span.
+func (p *DynamicBatchProcessor) ForceFlush(ctx context.Context) error {
+	done := make(chan struct{})
+	select {
+	case p.flush <- done:
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown exports every queued span and shuts the exporter down.
+func (p *DynamicBatchProcessor) Shutdown(ctx context.Context) error {
+	var err error
+	p.stopOnce.Do(func() {
+		close(p.stop)
+		select {
+		case <-p.done:
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		}
+		err = p.exporter.Shutdown(ctx)
+	})
+	return err
+}
+
+func (p *DynamicBatchProcessor) run() {
+	defer close(p.done)
+
+	batch := make([]sdktrace.ReadOnlySpan, 0, p.bounds.MaxBatchSize)
+	timer := time.NewTimer(p.ScheduleDelay())
+	defer timer.Stop()
+
+	export := func() {
+		if len(batch) > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), dynamicExportTimeout)
+			_ = p.exporter.ExportSpans(ctx, batch)
+			cancel()
+			clear(batch)
+			batch = batch[:0]
+		}
+		p.adjust()
+		timer.Reset(p.ScheduleDelay())
+	}
+	drain := func() {
+		for {
+			select {
+			case s := <-p.queue:
+				batch = append(batch, s)
+				if len(batch) >= p.BatchSize() {
+					export()
+				}
+			default:
+				export()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-p.stop:
+			drain()
+			return
+		case done := <-p.flush:
+			drain()
+			close(done)
+		case <-timer.C:
+			export()
+		case s := <-p.queue:
+			batch = append(batch, s)
+			if len(batch) >= p.BatchSize() {
+				export()
+			}
+		}
+	}
+}
+
+// adjust moves the batch size and schedule delay one step toward the
+// current queue utilization.
+func (p *DynamicBatchProcessor) adjust() {
+	utilization := float64(len(p.queue)) / float64(cap(p.queue))
+	size, delay := p.BatchSize(), p.ScheduleDelay()
+
+	switch {
+	case utilization >= dynamicGrowAt:
+		size = min(size*2, p.bounds.MaxBatchSize)
+		delay = max(delay/2, p.bounds.MinScheduleDelay)
+	case utilization <= dynamicShrinkAt:
+		size = max(size/2, p.bounds.MinBatchSize)
+		delay = min(delay*2, p.bounds.MaxScheduleDelay)
+	default:
+		return
+	}
+	p.batchSize.Store(int64(size))
+	p.delay.Store(int64(delay))
+}
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// gatedSpanExporter blocks exports until gate is closed and records batch sizes.
+type gatedSpanExporter struct {
+	gate    chan struct{}
+	mu      sync.Mutex
+	batches []int
+}
+
+func (e *gatedSpanExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	<-e.gate
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.batches = append(e.batches, len(spans))
+	return nil
+}
+
+func (e *gatedSpanExporter) Shutdown(context.Context) error { return nil }
+
+func (e *gatedSpanExporter) exported() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	total := 0
+	for _, n := range e.batches {
+		total += n
+	}
+	return total
+}
+
+var testDynamicBounds = config.DynamicBatchingConfig{
+	MinBatchSize:     4,
+	MaxBatchSize:     64,
+	MinScheduleDelay: 10 * time.Millisecond,
+	MaxScheduleDelay: time.Second,
+}
+
+func endSpans(p sdktrace.SpanProcessor, sampler sdktrace.Sampler, n int) {
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(p), sdktrace.WithSampler(sampler))
+	tracer := tp.Tracer("test")
+	for range n {
+		_, span := tracer.Start(context.Background(), "op")
+		span.End()
+	}
+}
+
+func TestDynamicBatchProcessor_GrowsUnderBurst(t *testing.T) {
+	exp := &gatedSpanExporter{gate: make(chan struct{})}
+	p := NewDynamicBatchProcessor(exp, testDynamicBounds, 100, 8, 500*time.Millisecond)
+	defer p.Shutdown(context.Background())
+
+	// The first full batch blocks in the exporter while the queue fills up.
+	endSpans(p, sdktrace.AlwaysSample(), 80)
+	close(exp.gate)
+	if err := p.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := p.BatchSize(); got <= 8 {
+		t.Errorf("BatchSize() = %d, want it grown above 8 after a burst", got)
+	}
+	if got := p.ScheduleDelay(); got >= 500*time.Millisecond {
+		t.Errorf("ScheduleDelay() = %v, want it shortened after a burst", got)
+	}
+	if got := exp.exported(); got != 80 {
+		t.Errorf("exported %d spans, want 80", got)
+	}
+}
+
+func TestDynamicBatchProcessor_ShrinksWhenQuiet(t *testing.T) {
+	exp := &gatedSpanExporter{gate: make(chan struct{})}
+	close(exp.gate)
+	p := NewDynamicBatchProcessor(exp, testDynamicBounds, 100, 32, 100*time.Millisecond)
+	defer p.Shutdown(context.Background())
+
+	endSpans(p, sdktrace.AlwaysSample(), 1)
+	if err := p.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := p.BatchSize(); got >= 32 {
+		t.Errorf("BatchSize() = %d, want it shrunk below 32 while quiet", got)
+	}
+	if got := p.ScheduleDelay(); got <= 100*time.Millisecond {
+		t.Errorf("ScheduleDelay() = %v, want it relaxed while quiet", got)
+	}
+}
+
+func TestDynamicBatchProcessor_StaysWithinBounds(t *testing.T) {
+	exp := &gatedSpanExporter{gate: make(chan struct{})}
+	close(exp.gate)
+	p := NewDynamicBatchProcessor(exp, testDynamicBounds, 32, 1000, time.Hour)
+	defer p.Shutdown(context.Background())
+
+	if got := p.BatchSize(); got != 32 {
+		t.Errorf("BatchSize() = %d, want it capped at the queue size", got)
+	}
+	if got := p.ScheduleDelay(); got != time.Second {
+		t.Errorf("ScheduleDelay() = %v, want it capped at MaxScheduleDelay", got)
+	}
+}
+
+func TestDynamicBatchProcessor_ExportsOnlySampledSpansAndFlushesOnShutdown(t *testing.T) {
+	exp := &gatedSpanExporter{gate: make(chan struct{})}
+	close(exp.gate)
+	p := NewDynamicBatchProcessor(exp, testDynamicBounds, 100, 64, time.Second)
+
+	endSpans(p, sdktrace.AlwaysSample(), 3)
+	endSpans(p, recordOnlySampler{}, 5)
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := exp.exported(); got != 3 {
+		t.Errorf("exported %d spans, want the 3 sampled ones", got)
+	}
+	endSpans(p, sdktrace.AlwaysSample(), 1)
+	if got := exp.exported(); got != 3 {
+		t.Errorf("exported %d spans after Shutdown, want 3", got)
+	}
+}
+
+type recordOnlySampler struct{}
+
+func (recordOnlySampler) ShouldSample(sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return sdktrace.SamplingResult{Decision: sdktrace.RecordOnly}
+}
+
+func (recordOnlySampler) Description() string { return "RecordOnly" }
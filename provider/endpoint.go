@@ -0,0 +1,16 @@
+package provider
+
+import (
+	"net/url"
+	"strings"
+)
+
+// splitEndpoint returns the host:port the OTLP exporters dial and, when the
+// endpoint is a URL with a path (e.g. https://mimir/otlp/v1/metrics), that
+// path. Endpoints without a scheme are returned unchanged.
+func splitEndpoint(endpoint string) (host, path string) {
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		return u.Host, strings.TrimSuffix(u.Path, "/")
+	}
+	return endpoint, ""
+}
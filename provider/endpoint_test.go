@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSplitEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint, host, path string
+	}{
+		{"collector:4317", "collector:4317", ""},
+		{"http://collector:4318", "collector:4318", ""},
+		{"https://mimir.example.com/otlp/v1/metrics", "mimir.example.com", "/otlp/v1/metrics"},
+	}
+	for _, tc := range tests {
+		host, path := splitEndpoint(tc.endpoint)
+		if host != tc.host || path != tc.path {
+			t.Errorf("splitEndpoint(%q) = %q, %q; want %q, %q", tc.endpoint, host, path, tc.host, tc.path)
+		}
+	}
+}
+
+func TestHTTPTraceExporter_UsesSignalOverride(t *testing.T) {
+	var gotPath, gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotHeader = r.URL.Path, r.Header.Get("X-Tenant")
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{
+		Endpoint:         "unused:4317",
+		ExporterProtocol: "grpc",
+		Insecure:         true,
+		Timeout:          5 * time.Second,
+		Auth:             config.AuthConfig{Headers: map[string]string{"X-Tenant": "global"}},
+		Traces: config.TracesConfig{Exporter: config.SignalExporterConfig{
+			Endpoint: srv.URL + "/custom/v1/traces",
+			Protocol: "http",
+			Headers:  map[string]string{"X-Tenant": "traces-only"},
+		}},
+	}
+	exp, err := createTraceExporter(context.Background(), cfg, &logger.NoopLogger{})
+	if err != nil {
+		t.Fatalf("createTraceExporter: %v", err)
+	}
+	defer func() { _ = exp.Shutdown(context.Background()) }()
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	span.End()
+
+	if gotPath != "/custom/v1/traces" || gotHeader != "traces-only" {
+		t.Errorf("request path = %q, X-Tenant = %q; want the per-signal override", gotPath, gotHeader)
+	}
+}
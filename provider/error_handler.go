@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/go-logr/logr"
+	"github.com/go-logr/stdr"
+	"go.opentelemetry.io/otel"
+)
+
+// defaultErrorLogInterval is the minimum time between two log entries for
+// the same SDK error message.
+const defaultErrorLogInterval = 10 * time.Second
+
+// maxErrorMessages bounds the distinct messages the rate limiter tracks, so
+// errors embedding IDs or addresses cannot grow it without limit. Messages
+// beyond it are rate limited together under overflowErrorKey.
+const maxErrorMessages = 256
+
+// overflowErrorKey tracks the messages that did not fit in the rate limiter.
+const overflowErrorKey = ""
+
+// LoggerErrorHandler is an otel.ErrorHandler that routes internal SDK errors
+// (export failures, dropped data) through the agent Logger instead of the
+// default stderr printing. Repeated errors with the same message are rate
+// limited; suppressed occurrences are reported on the next emitted entry.
+type LoggerErrorHandler struct {
+	logger   logger.Logger
+	interval time.Duration
+
+	mu         sync.Mutex
+	lastLogged map[string]time.Time
+	suppressed map[string]int
+}
+
+// NewLoggerErrorHandler creates an error handler that logs through log,
+// emitting at most one entry per distinct error message every interval.
+// A non-positive interval falls back to 10s.
+func NewLoggerErrorHandler(log logger.Logger, interval time.Duration) *LoggerErrorHandler {
+	if interval <= 0 {
+		interval = defaultErrorLogInterval
+	}
+	return &LoggerErrorHandler{
+		logger:     log,
+		interval:   interval,
+		lastLogged: make(map[string]time.Time),
+		suppressed: make(map[string]int),
+	}
+}
+
+// Handle logs err unless the same message was already logged within the
+// rate limit interval.
+func (h *LoggerErrorHandler) Handle(err error) {
+	if err == nil || h.logger == nil {
+		return
+	}
+
+	msg := err.Error()
+	now := time.Now()
+
+	h.mu.Lock()
+	key := msg
+	if _, ok := h.lastLogged[key]; !ok && len(h.lastLogged) >= maxErrorMessages {
+		h.sweep(now)
+		if len(h.lastLogged) >= maxErrorMessages {
+			key = overflowErrorKey
+		}
+	}
+	if last, ok := h.lastLogged[key]; ok && now.Sub(last) < h.interval {
+		h.suppressed[key]++
+		h.mu.Unlock()
+		return
+	}
+	suppressed := h.suppressed[key]
+	h.lastLogged[key] = now
+	delete(h.suppressed, key)
+	h.mu.Unlock()
+
+	h.logger.Error(context.Background(), "OpenTelemetry SDK error", logger.Fields{
+		"error":      msg,
+		"source":     "otel-sdk",
+		"suppressed": suppressed,
+	})
+}
+
+// sweep forgets the messages last logged an interval or more before now; a
+// new occurrence of one of them would be logged anyway. h.mu must be held.
+func (h *LoggerErrorHandler) sweep(now time.Time) {
+	for msg, last := range h.lastLogged {
+		if now.Sub(last) >= h.interval {
+			delete(h.lastLogged, msg)
+			delete(h.suppressed, msg)
+		}
+	}
+}
+
+// SetGlobalErrorHandling installs h as the global otel error handler and l
+// as the global otel logger, and returns the function that puts back the
+// previous error handler and the SDK's default stderr logger (the SDK has no
+// getter for the current one). Nothing is put back if another handler was
+// installed since.
+func SetGlobalErrorHandling(h otel.ErrorHandler, l logr.Logger) (restore func()) {
+	prev := otel.GetErrorHandler()
+	// The SDK's default handler forwards for good to the first handler set,
+	// so what is installed must be able to let go of h.
+	installed := &releasableErrorHandler{}
+	installed.target.Store(&h)
+	otel.SetErrorHandler(installed)
+	otel.SetLogger(l)
+
+	return func() {
+		installed.target.Store(nil)
+		if current, ok := otel.GetErrorHandler().(*releasableErrorHandler); ok && current == installed {
+			otel.SetErrorHandler(prev)
+			otel.SetLogger(stdr.New(log.New(os.Stderr, "", log.LstdFlags|log.Lshortfile)))
+		}
+	}
+}
+
+// releasableErrorHandler forwards to target until it is released, then
+// prints errors to stderr like the SDK's default handler.
+type releasableErrorHandler struct {
+	target atomic.Pointer[otel.ErrorHandler]
+}
+
+func (h *releasableErrorHandler) Handle(err error) {
+	if target := h.target.Load(); target != nil {
+		(*target).Handle(err)
+		return
+	}
+	log.Print(err)
+}
+
+// Ensure LoggerErrorHandler implements otel.ErrorHandler.
+var _ otel.ErrorHandler = (*LoggerErrorHandler)(nil)
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel"
+)
+
+// recordingLogger captures Error calls for assertions.
+type recordingLogger struct {
+	logger.NoopLogger
+	mu      sync.Mutex
+	entries []logger.Fields
+}
+
+func (r *recordingLogger) Error(_ context.Context, _ string, fields ...logger.Fields) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	merged := logger.Fields{}
+	for _, f := range fields {
+		for k, v := range f {
+			merged[k] = v
+		}
+	}
+	r.entries = append(r.entries, merged)
+}
+
+func (r *recordingLogger) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+func TestLoggerErrorHandler_LogsError(t *testing.T) {
+	rec := &recordingLogger{}
+	h := NewLoggerErrorHandler(rec, time.Minute)
+
+	h.Handle(errors.New("export failed"))
+
+	if rec.count() != 1 {
+		t.Fatalf("expected 1 log entry, got %d", rec.count())
+	}
+	if rec.entries[0]["error"] != "export failed" {
+		t.Errorf("error field = %v, want %q", rec.entries[0]["error"], "export failed")
+	}
+}
+
+func TestLoggerErrorHandler_RateLimitsSameMessage(t *testing.T) {
+	rec := &recordingLogger{}
+	h := NewLoggerErrorHandler(rec, time.Minute)
+
+	for i := 0; i < 5; i++ {
+		h.Handle(errors.New("export failed"))
+	}
+
+	if rec.count() != 1 {
+		t.Errorf("expected repeated errors to be rate limited to 1 entry, got %d", rec.count())
+	}
+}
+
+func TestLoggerErrorHandler_DistinctMessagesNotLimited(t *testing.T) {
+	rec := &recordingLogger{}
+	h := NewLoggerErrorHandler(rec, time.Minute)
+
+	h.Handle(errors.New("export failed"))
+	h.Handle(errors.New("queue full"))
+
+	if rec.count() != 2 {
+		t.Errorf("expected 2 log entries for distinct messages, got %d", rec.count())
+	}
+}
+
+func TestLoggerErrorHandler_ReportsSuppressedCount(t *testing.T) {
+	rec := &recordingLogger{}
+	h := NewLoggerErrorHandler(rec, 10*time.Millisecond)
+
+	h.Handle(errors.New("export failed"))
+	h.Handle(errors.New("export failed"))
+	h.Handle(errors.New("export failed"))
+	time.Sleep(20 * time.Millisecond)
+	h.Handle(errors.New("export failed"))
+
+	if rec.count() != 2 {
+		t.Fatalf("expected 2 log entries, got %d", rec.count())
+	}
+	if got := rec.entries[1]["suppressed"]; got != 2 {
+		t.Errorf("suppressed = %v, want 2", got)
+	}
+}
+
+func TestLoggerErrorHandler_BoundsTrackedMessages(t *testing.T) {
+	rec := &recordingLogger{}
+	h := NewLoggerErrorHandler(rec, 20*time.Millisecond)
+
+	for i := range maxErrorMessages + 3 {
+		h.Handle(fmt.Errorf("dial 10.0.0.%d: refused", i))
+	}
+	// The first message past the limit is logged, the rest are suppressed
+	// with it.
+	if rec.count() != maxErrorMessages+1 {
+		t.Errorf("expected %d log entries, got %d", maxErrorMessages+1, rec.count())
+	}
+	if n := len(h.lastLogged); n > maxErrorMessages+1 {
+		t.Errorf("tracking %d messages, want at most %d", n, maxErrorMessages+1)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	h.Handle(errors.New("export failed"))
+	if _, ok := h.lastLogged["export failed"]; !ok || len(h.lastLogged) > 2 {
+		t.Errorf("tracking %d messages after the interval, want expired ones swept", len(h.lastLogged))
+	}
+}
+
+func TestLoggerErrorHandler_NilErrorIgnored(t *testing.T) {
+	rec := &recordingLogger{}
+	h := NewLoggerErrorHandler(rec, time.Minute)
+
+	h.Handle(nil)
+
+	if rec.count() != 0 {
+		t.Errorf("expected nil error to be ignored, got %d entries", rec.count())
+	}
+}
+
+func TestSetGlobalErrorHandling_RestoreDetachesTheHandler(t *testing.T) {
+	log := &recordingLogger{}
+	before := otel.GetErrorHandler()
+	restore := SetGlobalErrorHandling(NewLoggerErrorHandler(log, 0), logr.Discard())
+
+	otel.Handle(errors.New("export failed"))
+	if log.count() != 1 {
+		t.Fatalf("logged %d errors while installed, want 1", log.count())
+	}
+
+	restore()
+	if _, ok := otel.GetErrorHandler().(*releasableErrorHandler); ok {
+		t.Error("the agent's handler is still installed after restore")
+	}
+	// Handlers returned before the first SetErrorHandler keep forwarding to
+	// it; they must not reach the released logger either.
+	before.Handle(errors.New("late error"))
+	if log.count() != 1 {
+		t.Errorf("logged %d errors after restore, want 1", log.count())
+	}
+}
@@ -0,0 +1,218 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+const (
+	// ErrorFingerprintAttribute is set on a span to the fingerprint of the
+	// first error recorded on it, so backends can group spans by issue.
+	ErrorFingerprintAttribute = "error.fingerprint"
+
+	// ErrorsMetric counts recorded errors by fingerprint and component.
+	ErrorsMetric = "errors_total"
+
+	// maxErrorFingerprints bounds the fingerprints used as metric attribute
+	// values; later ones are counted as "other". Span attributes always
+	// carry the real fingerprint.
+	maxErrorFingerprints = 500
+
+	// maxFingerprintMessage is how much of the cleaned message is hashed.
+	maxFingerprintMessage = 256
+
+	modulePath = "github.com/RodolfoBonis/go-otel-agent/"
+)
+
+// volatileTokens match the parts of an error message that change between
+// occurrences of the same error, most specific first.
+var volatileTokens = []struct {
+	re          *regexp.Regexp
+	placeholder string
+}{
+	{regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), "<uuid>"},
+	{regexp.MustCompile(`[^\s@]+@[^\s@]+\.[a-zA-Z]{2,}`), "<email>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`\b0x[0-9a-fA-F]+\b`), "<hex>"},
+	{regexp.MustCompile(`\b[0-9a-fA-F]{16,}\b`), "<hex>"},
+	{regexp.MustCompile(`"[^"]*"|'[^']*'`), "<str>"},
+	{regexp.MustCompile(`\d+`), "<n>"},
+}
+
+// NormalizeErrorMessage replaces the volatile parts of an error message
+// (IDs, addresses, numbers, quoted values) with placeholders, so that
+// "user 42 not found" and "user 7 not found" are the same issue.
+func NormalizeErrorMessage(msg string) string {
+	for _, t := range volatileTokens {
+		msg = t.re.ReplaceAllString(msg, t.placeholder)
+	}
+	if len(msg) > maxFingerprintMessage {
+		msg = msg[:maxFingerprintMessage]
+	}
+	return msg
+}
+
+// ErrorFingerprint returns the fingerprint of err as recorded from the
+// caller: a hash of the type of its innermost error, its normalized message
+// and the calling function. Log it next to an error to find its spans.
+func ErrorFingerprint(err error) string {
+	return fingerprint(errorType(err), err.Error(), callerFrame())
+}
+
+func fingerprint(errType, message, frame string) string {
+	sum := sha256.Sum256([]byte(errType + "\x00" + NormalizeErrorMessage(message) + "\x00" + frame))
+	return hex.EncodeToString(sum[:8])
+}
+
+// errorType is the type of the innermost error of a wrap chain, which
+// identifies the failure better than a *fmt.wrapError.
+func errorType(err error) string {
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return fmt.Sprintf("%T", err)
+		}
+		err = next
+	}
+}
+
+// callerFrame returns the first function on the stack outside the runtime,
+// the OpenTelemetry SDK and the agent itself (other than its tests): the
+// code that recorded the error, or that panicked when a recovered panic is
+// recorded. Function names rather than lines keep fingerprints stable
+// across unrelated edits.
+func callerFrame() string {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	for {
+		f, more := frames.Next()
+		switch {
+		case strings.HasPrefix(f.Function, "runtime."),
+			strings.HasPrefix(f.Function, "go.opentelemetry.io/"),
+			strings.HasPrefix(f.Function, modulePath) && !strings.HasSuffix(f.File, "_test.go"):
+		default:
+			return f.Function
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// NewErrorTrackingTracerProvider returns a TracerProvider whose spans
+// fingerprint the errors recorded on them, through RecordError or an
+// "exception" event. The first fingerprint of a span is set as
+// error.fingerprint, and every error increments errors_total{fingerprint,
+// component} on meter, where component is the tracer name.
+func NewErrorTrackingTracerProvider(tp trace.TracerProvider, meter metric.Meter) trace.TracerProvider {
+	p := &errorTrackingTracerProvider{tp: tp, seen: make(map[string]bool)}
+	p.errors, _ = meter.Int64Counter(ErrorsMetric,
+		metric.WithDescription("Recorded errors by fingerprint and component"),
+	)
+	return p
+}
+
+type errorTrackingTracerProvider struct {
+	embedded.TracerProvider
+	tp     trace.TracerProvider
+	errors metric.Int64Counter
+
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func (p *errorTrackingTracerProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return &errorTrackingTracer{tracer: p.tp.Tracer(name, opts...), provider: p, component: name}
+}
+
+// metricFingerprint bounds the fingerprints used as metric attributes.
+func (p *errorTrackingTracerProvider) metricFingerprint(fp string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.seen[fp] {
+		return fp
+	}
+	if len(p.seen) >= maxErrorFingerprints {
+		return "other"
+	}
+	p.seen[fp] = true
+	return fp
+}
+
+type errorTrackingTracer struct {
+	embedded.Tracer
+	tracer    trace.Tracer
+	provider  *errorTrackingTracerProvider
+	component string
+}
+
+func (t *errorTrackingTracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx, span := t.tracer.Start(ctx, spanName, opts...)
+	if !span.IsRecording() {
+		return ctx, span
+	}
+	tracked := &errorTrackingSpan{Span: span, tracer: t}
+	return trace.ContextWithSpan(ctx, tracked), tracked
+}
+
+type errorTrackingSpan struct {
+	trace.Span
+	tracer *errorTrackingTracer
+
+	mu          sync.Mutex
+	fingerprint string
+}
+
+func (s *errorTrackingSpan) RecordError(err error, opts ...trace.EventOption) {
+	if err != nil {
+		s.track(context.Background(), fingerprint(errorType(err), err.Error(), callerFrame()))
+	}
+	s.Span.RecordError(err, opts...)
+}
+
+func (s *errorTrackingSpan) AddEvent(name string, opts ...trace.EventOption) {
+	if name == "exception" {
+		cfg := trace.NewEventConfig(opts...)
+		attrs := attribute.NewSet(cfg.Attributes()...)
+		excType, _ := attrs.Value("exception.type")
+		message, _ := attrs.Value("exception.message")
+		s.track(context.Background(), fingerprint(excType.AsString(), message.AsString(), callerFrame()))
+	}
+	s.Span.AddEvent(name, opts...)
+}
+
+func (s *errorTrackingSpan) track(ctx context.Context, fp string) {
+	s.mu.Lock()
+	first := s.fingerprint == ""
+	if first {
+		s.fingerprint = fp
+	}
+	s.mu.Unlock()
+	if first {
+		s.Span.SetAttributes(attribute.String(ErrorFingerprintAttribute, fp))
+	}
+
+	p := s.tracer.provider
+	p.errors.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("fingerprint", p.metricFingerprint(fp)),
+		attribute.String("component", s.tracer.component),
+	))
+}
+
+// TracerProvider returns the tracking provider, so tracers obtained from the
+// span track errors too.
+func (s *errorTrackingSpan) TracerProvider() trace.TracerProvider {
+	return s.tracer.provider
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type notFoundError struct{ id int }
+
+func (e notFoundError) Error() string { return fmt.Sprintf("order %d not found", e.id) }
+
+func newErrorTracking(t *testing.T) (trace.TracerProvider, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	tp := NewErrorTrackingTracerProvider(
+		sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"),
+	)
+	return tp, recorder, reader
+}
+
+func spanFingerprint(s sdktrace.ReadOnlySpan) string {
+	for _, kv := range s.Attributes() {
+		if kv.Key == ErrorFingerprintAttribute {
+			return kv.Value.AsString()
+		}
+	}
+	return ""
+}
+
+func recordLookupFailure(tracer trace.Tracer, id int) {
+	_, span := tracer.Start(context.Background(), "lookup")
+	span.RecordError(fmt.Errorf("loading order: %w", notFoundError{id}))
+	span.End()
+}
+
+func TestErrorTracking_GroupsOccurrencesOfTheSameError(t *testing.T) {
+	tp, recorder, reader := newErrorTracking(t)
+	tracer := tp.Tracer("orders")
+
+	recordLookupFailure(tracer, 42)
+	recordLookupFailure(tracer, 7)
+	_, span := tracer.Start(context.Background(), "charge")
+	span.RecordError(errors.New("card declined"))
+	span.End()
+
+	spans := recorder.Ended()
+	first, second, other := spanFingerprint(spans[0]), spanFingerprint(spans[1]), spanFingerprint(spans[2])
+	if first == "" || first != second {
+		t.Errorf("same error with different IDs got fingerprints %q and %q", first, second)
+	}
+	if other == "" || other == first {
+		t.Errorf("different error got fingerprint %q, want a distinct one", other)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int64)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name != ErrorsMetric {
+			continue
+		}
+		for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+			fp, _ := dp.Attributes.Value("fingerprint")
+			component, _ := dp.Attributes.Value("component")
+			if component.AsString() != "orders" {
+				t.Errorf("component = %q, want the tracer name", component.AsString())
+			}
+			counts[fp.AsString()] = dp.Value
+		}
+	}
+	if counts[first] != 2 || counts[other] != 1 {
+		t.Errorf("errors_total = %v, want 2 for %s and 1 for %s", counts, first, other)
+	}
+}
+
+func TestErrorTracking_ExceptionEventsAndFirstErrorWins(t *testing.T) {
+	tp, recorder, _ := newErrorTracking(t)
+
+	_, span := tp.Tracer("jobs").Start(context.Background(), "job")
+	span.AddEvent("exception", trace.WithAttributes(
+		attribute.String("exception.type", "runtime.boundsError"),
+		attribute.String("exception.message", "index out of range [3] with length 0"),
+	))
+	span.RecordError(errors.New("later"))
+	span.End()
+
+	got := spanFingerprint(recorder.Ended()[0])
+	// Recorded from this test function, the first frame outside the agent.
+	frame := "github.com/RodolfoBonis/go-otel-agent/provider.TestErrorTracking_ExceptionEventsAndFirstErrorWins"
+	want := fingerprint("runtime.boundsError", "index out of range [9] with length 2", frame)
+	if got != want {
+		t.Errorf("fingerprint = %q, want %q from the exception event", got, want)
+	}
+}
+
+func TestNormalizeErrorMessage(t *testing.T) {
+	tests := map[string]string{
+		`user 42 not found`:                                                `user <n> not found`,
+		`dial tcp 10.0.3.7:5432: connection refused`:                       `dial tcp <ip>: connection refused`,
+		`order 3f2b8c1e-0d4a-4b6e-9f1a-2c3d4e5f6a7b: invalid state "paid"`: `order <uuid>: invalid state <str>`,
+		`mail to jane@example.com bounced`:                                 `mail to <email> bounced`,
+	}
+	for in, want := range tests {
+		if got := NormalizeErrorMessage(in); got != want {
+			t.Errorf("NormalizeErrorMessage(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
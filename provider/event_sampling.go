@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+const (
+	// EventOccurrenceAttribute is set on a sampled repeated event to the
+	// occurrence it is: 1, N+1, 2N+1, ...
+	EventOccurrenceAttribute = "otel.event.occurrence"
+
+	// EventsSampledOutAttribute is set on a span, when it ends, to the
+	// number of repeated events that were not recorded.
+	EventsSampledOutAttribute = "otel.span.events.sampled_out"
+
+	// maxTrackedEvents bounds the distinct events counted per span; events
+	// beyond it are recorded unsampled and left to the span event limit.
+	maxTrackedEvents = 256
+)
+
+// NewEventSamplingTracerProvider returns a TracerProvider whose spans record
+// only every Nth occurrence of an identical event, i.e. one with the same
+// name and attributes, within a span. A loop adding the same event thousands
+// of times then yields a handful of events instead of filling the span event
+// limit and evicting the events that matter. Distinct events are always
+// recorded. With every <= 1, tp is returned unchanged.
+func NewEventSamplingTracerProvider(tp trace.TracerProvider, every int) trace.TracerProvider {
+	if every <= 1 {
+		return tp
+	}
+	return &eventSamplingTracerProvider{tp: tp, every: every}
+}
+
+type eventSamplingTracerProvider struct {
+	embedded.TracerProvider
+	tp    trace.TracerProvider
+	every int
+}
+
+func (p *eventSamplingTracerProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return &eventSamplingTracer{tracer: p.tp.Tracer(name, opts...), provider: p}
+}
+
+type eventSamplingTracer struct {
+	embedded.Tracer
+	tracer   trace.Tracer
+	provider *eventSamplingTracerProvider
+}
+
+func (t *eventSamplingTracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx, span := t.tracer.Start(ctx, spanName, opts...)
+	if !span.IsRecording() {
+		return ctx, span
+	}
+	sampled := &eventSamplingSpan{Span: span, provider: t.provider}
+	return trace.ContextWithSpan(ctx, sampled), sampled
+}
+
+// eventKey identifies identical events: same name, same attribute set.
+type eventKey struct {
+	name  string
+	attrs attribute.Distinct
+}
+
+// eventSamplingSpan counts events by eventKey and drops repeats that are not
+// on the sampling interval.
+type eventSamplingSpan struct {
+	trace.Span
+	provider *eventSamplingTracerProvider
+
+	mu      sync.Mutex
+	counts  map[eventKey]int
+	dropped int
+}
+
+func (s *eventSamplingSpan) AddEvent(name string, opts ...trace.EventOption) {
+	cfg := trace.NewEventConfig(opts...)
+	set := attribute.NewSet(cfg.Attributes()...)
+	key := eventKey{name: name, attrs: set.Equivalent()}
+
+	s.mu.Lock()
+	if s.counts == nil {
+		s.counts = make(map[eventKey]int)
+	}
+	n, tracked := s.counts[key]
+	if !tracked && len(s.counts) >= maxTrackedEvents {
+		s.mu.Unlock()
+		s.Span.AddEvent(name, opts...)
+		return
+	}
+	n++
+	s.counts[key] = n
+	keep := (n-1)%s.provider.every == 0
+	if !keep {
+		s.dropped++
+	}
+	s.mu.Unlock()
+
+	if !keep {
+		return
+	}
+	if n > 1 {
+		opts = append(opts[:len(opts):len(opts)], trace.WithAttributes(attribute.Int(EventOccurrenceAttribute, n)))
+	}
+	s.Span.AddEvent(name, opts...)
+}
+
+func (s *eventSamplingSpan) End(opts ...trace.SpanEndOption) {
+	s.mu.Lock()
+	dropped := s.dropped
+	s.mu.Unlock()
+	if dropped > 0 {
+		s.Span.SetAttributes(attribute.Int(EventsSampledOutAttribute, dropped))
+	}
+	s.Span.End(opts...)
+}
+
+// TracerProvider returns the sampling provider, so tracers obtained from the
+// span sample events too.
+func (s *eventSamplingSpan) TracerProvider() trace.TracerProvider {
+	return s.provider
+}
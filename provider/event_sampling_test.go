@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEventSamplingTracerProvider_SamplesRepeatedEvents(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := NewEventSamplingTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), 10)
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "loop")
+	for range 25 {
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(attribute.String("reason", "timeout")))
+	}
+	span.AddEvent("retry", trace.WithAttributes(attribute.String("reason", "refused")))
+	span.AddEvent("done")
+	span.End()
+
+	ended := recorder.Ended()[0]
+	var occurrences []int64
+	for _, e := range ended.Events() {
+		if e.Name != "retry" {
+			continue
+		}
+		occurrence := int64(1)
+		for _, kv := range e.Attributes {
+			if kv.Key == EventOccurrenceAttribute {
+				occurrence = kv.Value.AsInt64()
+			}
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	// Occurrences 1, 11 and 21 of the timeout retry, plus the distinct
+	// refused retry.
+	if want := []int64{1, 11, 21, 1}; !slices.Equal(occurrences, want) {
+		t.Errorf("recorded retry occurrences %v, want %v", occurrences, want)
+	}
+	if n := len(ended.Events()); n != 5 {
+		t.Errorf("recorded %d events, want 5", n)
+	}
+
+	var sampledOut int64
+	for _, kv := range ended.Attributes() {
+		if kv.Key == EventsSampledOutAttribute {
+			sampledOut = kv.Value.AsInt64()
+		}
+	}
+	if sampledOut != 22 {
+		t.Errorf("%s = %d, want 22", EventsSampledOutAttribute, sampledOut)
+	}
+}
+
+func TestEventSamplingTracerProvider_DisabledReturnsProvider(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	if got := NewEventSamplingTracerProvider(tp, 1); got != trace.TracerProvider(tp) {
+		t.Errorf("every=1 wrapped the provider: %T", got)
+	}
+}
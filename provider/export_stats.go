@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Signal names used by ExportStats.
+const (
+	SignalTraces  = "traces"
+	SignalMetrics = "metrics"
+	SignalLogs    = "logs"
+)
+
+// SignalExportStats describes the successful exports of one signal.
+type SignalExportStats struct {
+	// LastExport is when a non-empty batch was last exported successfully.
+	LastExport time.Time
+	// LastBatchSize is the number of spans, metric data points or log
+	// records in that batch.
+	LastBatchSize int
+	// TotalExported counts items across all successful exports.
+	TotalExported int64
+}
+
+// ExportStats records when each signal was last exported and how large the
+// batches were, so a signal that silently stopped flowing can be spotted from
+// the service itself.
+type ExportStats struct {
+	mu      sync.RWMutex
+	signals map[string]SignalExportStats
+	now     func() time.Time
+
+	// spans and log records handed to the batch processors, and metric
+	// data points of failed exports, for Dropped
+	tracesQueued  atomic.Int64
+	logsQueued    atomic.Int64
+	metricsFailed atomic.Int64
+}
+
+// NewExportStats creates an empty export tracker.
+func NewExportStats() *ExportStats {
+	return &ExportStats{
+		signals: make(map[string]SignalExportStats),
+		now:     time.Now,
+	}
+}
+
+// RecordExport records a successful export of n items for signal. Empty
+// batches are ignored: they do not mean data is flowing.
+func (s *ExportStats) RecordExport(signal string, n int) {
+	if n <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.signals[signal]
+	st.LastExport = s.now()
+	st.LastBatchSize = n
+	st.TotalExported += int64(n)
+	s.signals[signal] = st
+}
+
+// Signal returns the stats for signal and whether it has ever exported.
+func (s *ExportStats) Signal(signal string) (SignalExportStats, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	st, ok := s.signals[signal]
+	return st, ok
+}
+
+// Dropped returns how many items of signal were lost so far: spans and log
+// records that were queued for export but never exported (a full queue, a
+// failed export, or a shutdown deadline), and metric data points of failed
+// exports. Items still waiting in a queue count as well, so the value is only
+// exact once the pipeline has been flushed.
+func (s *ExportStats) Dropped(signal string) int64 {
+	var queued int64
+	switch signal {
+	case SignalTraces:
+		queued = s.tracesQueued.Load()
+	case SignalLogs:
+		queued = s.logsQueued.Load()
+	case SignalMetrics:
+		return s.metricsFailed.Load()
+	default:
+		return 0
+	}
+	st, _ := s.Signal(signal)
+	return max(queued-st.TotalExported, 0)
+}
+
+// WithTraceExportStats records successful span exports in s.
+func WithTraceExportStats(s *ExportStats) TraceProviderOption {
+	return func(o *traceProviderOptions) {
+		o.exportStats = s
+	}
+}
+
+// WithMetricExportStats records successful metric exports in s.
+func WithMetricExportStats(s *ExportStats) MetricProviderOption {
+	return func(o *metricProviderOptions) {
+		o.exportStats = s
+	}
+}
+
+// WithLogExportStats records successful log exports in s.
+func WithLogExportStats(s *ExportStats) LogProviderOption {
+	return func(o *logProviderOptions) {
+		o.exportStats = s
+	}
+}
+
+type statsSpanExporter struct {
+	sdktrace.SpanExporter
+	stats *ExportStats
+}
+
+func (e statsSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	if err == nil {
+		e.stats.RecordExport(SignalTraces, len(spans))
+	}
+	return err
+}
+
+type statsMetricExporter struct {
+	metric.Exporter
+	stats *ExportStats
+}
+
+func (e statsMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, rm)
+	if err == nil {
+		e.stats.RecordExport(SignalMetrics, dataPointCount(rm))
+	} else {
+		e.stats.metricsFailed.Add(int64(dataPointCount(rm)))
+	}
+	return err
+}
+
+type statsLogExporter struct {
+	log.Exporter
+	stats *ExportStats
+}
+
+func (e statsLogExporter) Export(ctx context.Context, records []log.Record) error {
+	err := e.Exporter.Export(ctx, records)
+	if err == nil {
+		e.stats.RecordExport(SignalLogs, len(records))
+	}
+	return err
+}
+
+// statsSpanProcessor counts the spans next, the batch span processor, queues
+// for export.
+type statsSpanProcessor struct {
+	sdktrace.SpanProcessor
+	stats *ExportStats
+}
+
+func (p statsSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		p.stats.tracesQueued.Add(1)
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// statsLogProcessor counts the records next, the batch log processor, queues
+// for export.
+type statsLogProcessor struct {
+	log.Processor
+	stats *ExportStats
+}
+
+func (p statsLogProcessor) OnEmit(ctx context.Context, r *log.Record) error {
+	p.stats.logsQueued.Add(1)
+	return p.Processor.OnEmit(ctx, r)
+}
+
+// dataPointCount counts the data points across every metric in rm.
+func dataPointCount(rm *metricdata.ResourceMetrics) int {
+	n := 0
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			n += metricDataPoints(m.Data)
+		}
+	}
+	return n
+}
+
+// metricDataPoints counts the data points of one metric.
+func metricDataPoints(data metricdata.Aggregation) int {
+	switch d := data.(type) {
+	case metricdata.Gauge[int64]:
+		return len(d.DataPoints)
+	case metricdata.Gauge[float64]:
+		return len(d.DataPoints)
+	case metricdata.Sum[int64]:
+		return len(d.DataPoints)
+	case metricdata.Sum[float64]:
+		return len(d.DataPoints)
+	case metricdata.Histogram[int64]:
+		return len(d.DataPoints)
+	case metricdata.Histogram[float64]:
+		return len(d.DataPoints)
+	case metricdata.ExponentialHistogram[int64]:
+		return len(d.DataPoints)
+	case metricdata.ExponentialHistogram[float64]:
+		return len(d.DataPoints)
+	case metricdata.Summary:
+		return len(d.DataPoints)
+	}
+	return 0
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type failingSpanExporter struct {
+	sdktrace.SpanExporter
+	err error
+}
+
+func (e failingSpanExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error {
+	return e.err
+}
+
+// failingMetricExporter fails every export; only Export may be called.
+type failingMetricExporter struct {
+	metric.Exporter
+}
+
+func (failingMetricExporter) Export(context.Context, *metricdata.ResourceMetrics) error {
+	return errors.New("unavailable")
+}
+
+type nopLogExporter struct{}
+
+func (nopLogExporter) Export(context.Context, []log.Record) error { return nil }
+func (nopLogExporter) Shutdown(context.Context) error             { return nil }
+func (nopLogExporter) ForceFlush(context.Context) error           { return nil }
+
+func TestExportStats_RecordsSuccessfulBatches(t *testing.T) {
+	stats := NewExportStats()
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	stats.now = clock.now
+
+	spans := tracetest.SpanStubs{{Name: "a"}, {Name: "b"}}.Snapshots()
+	exp := statsSpanExporter{SpanExporter: tracetest.NewInMemoryExporter(), stats: stats}
+	if err := exp.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatal(err)
+	}
+	clock.advance(time.Minute)
+	if err := exp.ExportSpans(context.Background(), spans[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	st, ok := stats.Signal(SignalTraces)
+	if !ok || st.LastBatchSize != 1 || st.TotalExported != 3 || !st.LastExport.Equal(time.Unix(1060, 0)) {
+		t.Errorf("traces = %+v, %v", st, ok)
+	}
+
+	logs := statsLogExporter{Exporter: nopLogExporter{}, stats: stats}
+	_ = logs.Export(context.Background(), make([]log.Record, 4))
+	if st, _ := stats.Signal(SignalLogs); st.LastBatchSize != 4 {
+		t.Errorf("logs = %+v, want batch of 4", st)
+	}
+}
+
+func TestExportStats_IgnoresFailuresAndEmptyBatches(t *testing.T) {
+	stats := NewExportStats()
+
+	failing := statsSpanExporter{SpanExporter: failingSpanExporter{err: errors.New("unavailable")}, stats: stats}
+	_ = failing.ExportSpans(context.Background(), tracetest.SpanStubs{{Name: "a"}}.Snapshots())
+	stats.RecordExport(SignalMetrics, 0)
+
+	for _, signal := range []string{SignalTraces, SignalMetrics} {
+		if st, ok := stats.Signal(signal); ok {
+			t.Errorf("%s recorded %+v, want nothing", signal, st)
+		}
+	}
+}
+
+func TestDataPointCount(t *testing.T) {
+	rm := &metricdata.ResourceMetrics{ScopeMetrics: []metricdata.ScopeMetrics{{
+		Metrics: []metricdata.Metrics{
+			{Data: metricdata.Sum[int64]{DataPoints: make([]metricdata.DataPoint[int64], 2)}},
+			{Data: metricdata.Gauge[float64]{DataPoints: make([]metricdata.DataPoint[float64], 1)}},
+			{Data: metricdata.Histogram[float64]{DataPoints: make([]metricdata.HistogramDataPoint[float64], 3)}},
+		},
+	}}}
+	if n := dataPointCount(rm); n != 6 {
+		t.Errorf("dataPointCount = %d, want 6", n)
+	}
+}
+
+func TestExportStats_DroppedCountsQueuedButNotExported(t *testing.T) {
+	stats := NewExportStats()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(statsSpanProcessor{
+		SpanProcessor: sdktrace.NewSimpleSpanProcessor(statsSpanExporter{SpanExporter: exporter, stats: stats}),
+		stats:         stats,
+	}))
+	for range 3 {
+		_, span := tp.Tracer("test").Start(context.Background(), "op")
+		span.End()
+	}
+	if got := stats.Dropped(SignalTraces); got != 0 {
+		t.Errorf("Dropped(traces) = %d with every span exported, want 0", got)
+	}
+
+	// Queued records that never reach an export are dropped
+	logs := statsLogProcessor{Processor: log.NewBatchProcessor(nopLogExporter{}), stats: stats}
+	defer func() { _ = logs.Shutdown(context.Background()) }()
+	for range 2 {
+		_ = logs.OnEmit(context.Background(), &log.Record{})
+	}
+	if got := stats.Dropped(SignalLogs); got != 2 {
+		t.Errorf("Dropped(logs) = %d before the flush, want 2", got)
+	}
+
+	metrics := statsMetricExporter{Exporter: failingMetricExporter{}, stats: stats}
+	_ = metrics.Export(context.Background(), &metricdata.ResourceMetrics{ScopeMetrics: []metricdata.ScopeMetrics{{
+		Metrics: []metricdata.Metrics{{Data: metricdata.Sum[int64]{DataPoints: make([]metricdata.DataPoint[int64], 4)}}},
+	}}})
+	if got := stats.Dropped(SignalMetrics); got != 4 {
+		t.Errorf("Dropped(metrics) = %d after a failed export, want 4", got)
+	}
+}
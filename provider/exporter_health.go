@@ -1,8 +1,16 @@
 package provider
 
 import (
+	"context"
 	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 // ExporterStatus represents the health status of an exporter.
@@ -35,6 +43,7 @@ type ExporterHealth struct {
 	lastSuccess         map[string]time.Time
 	degradedThreshold   int
 	unhealthyThreshold  int
+	failures            otelmetric.Int64Counter
 }
 
 // NewExporterHealth creates a new exporter health tracker.
@@ -64,6 +73,19 @@ func (h *ExporterHealth) RecordFailure(signal string) {
 
 	h.consecutiveFailures[signal]++
 	h.lastFailure[signal] = time.Now()
+	if h.failures != nil {
+		h.failures.Add(context.Background(), 1,
+			otelmetric.WithAttributes(attribute.String("signal", signal)))
+	}
+}
+
+// SetFailureCounter counts every failed export in c, labelled by signal.
+// The counter usually comes from the agent's own MeterProvider, which only
+// exists after the trace exporter is already being tracked.
+func (h *ExporterHealth) SetFailureCounter(c otelmetric.Int64Counter) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failures = c
 }
 
 // Status returns the health status for the given signal.
@@ -107,8 +129,107 @@ func (h *ExporterHealth) SignalStatuses() map[string]ExporterStatus {
 	defer h.mu.RUnlock()
 
 	statuses := make(map[string]ExporterStatus)
-	for signal := range h.consecutiveFailures {
-		statuses[signal] = h.Status(signal)
+	for signal, failures := range h.consecutiveFailures {
+		status := ExporterHealthy
+		if failures >= h.unhealthyThreshold {
+			status = ExporterUnhealthy
+		} else if failures >= h.degradedThreshold {
+			status = ExporterDegraded
+		}
+		statuses[signal] = status
 	}
 	return statuses
 }
+
+// SignalHealth is the export history of one signal.
+type SignalHealth struct {
+	Status              ExporterStatus
+	ConsecutiveFailures int
+	// LastSuccess and LastFailure are zero until the first export of
+	// that outcome.
+	LastSuccess time.Time
+	LastFailure time.Time
+}
+
+// Signal returns the export history of signal.
+func (h *ExporterHealth) Signal(signal string) SignalHealth {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	failures := h.consecutiveFailures[signal]
+	status := ExporterHealthy
+	if failures >= h.unhealthyThreshold {
+		status = ExporterUnhealthy
+	} else if failures >= h.degradedThreshold {
+		status = ExporterDegraded
+	}
+	return SignalHealth{
+		Status:              status,
+		ConsecutiveFailures: failures,
+		LastSuccess:         h.lastSuccess[signal],
+		LastFailure:         h.lastFailure[signal],
+	}
+}
+
+// record records the outcome of one export call.
+func (h *ExporterHealth) record(signal string, err error) {
+	if err != nil {
+		h.RecordFailure(signal)
+		return
+	}
+	h.RecordSuccess(signal)
+}
+
+// WithTraceExporterHealth records the outcome of every span export in h.
+func WithTraceExporterHealth(h *ExporterHealth) TraceProviderOption {
+	return func(o *traceProviderOptions) {
+		o.health = h
+	}
+}
+
+// WithMetricExporterHealth records the outcome of every metric export in h.
+func WithMetricExporterHealth(h *ExporterHealth) MetricProviderOption {
+	return func(o *metricProviderOptions) {
+		o.health = h
+	}
+}
+
+// WithLogExporterHealth records the outcome of every log export in h.
+func WithLogExporterHealth(h *ExporterHealth) LogProviderOption {
+	return func(o *logProviderOptions) {
+		o.health = h
+	}
+}
+
+type healthSpanExporter struct {
+	sdktrace.SpanExporter
+	health *ExporterHealth
+}
+
+func (e healthSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.health.record(SignalTraces, err)
+	return err
+}
+
+type healthMetricExporter struct {
+	metric.Exporter
+	health *ExporterHealth
+}
+
+func (e healthMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, rm)
+	e.health.record(SignalMetrics, err)
+	return err
+}
+
+type healthLogExporter struct {
+	log.Exporter
+	health *ExporterHealth
+}
+
+func (e healthLogExporter) Export(ctx context.Context, records []log.Record) error {
+	err := e.Exporter.Export(ctx, records)
+	e.health.record(SignalLogs, err)
+	return err
+}
@@ -1,7 +1,15 @@
 package provider
 
 import (
+	"context"
+	"errors"
 	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestNewExporterHealth_CreatesValidTracker(t *testing.T) {
@@ -166,6 +174,25 @@ func TestSignalStatuses_ReturnsAllTrackedSignals(t *testing.T) {
 	}
 }
 
+func TestSignal_ReportsExportHistory(t *testing.T) {
+	h := NewExporterHealth()
+	if got := h.Signal("traces"); got.Status != ExporterHealthy || !got.LastSuccess.IsZero() || !got.LastFailure.IsZero() {
+		t.Errorf("Signal before any export = %+v, want healthy with no history", got)
+	}
+
+	h.RecordSuccess("traces")
+	for range 3 {
+		h.RecordFailure("traces")
+	}
+	got := h.Signal("traces")
+	if got.Status != ExporterDegraded || got.ConsecutiveFailures != 3 {
+		t.Errorf("Signal = %+v, want degraded after 3 failures", got)
+	}
+	if got.LastSuccess.IsZero() || got.LastFailure.Before(got.LastSuccess) {
+		t.Errorf("LastSuccess/LastFailure = %v/%v, want a success then a failure", got.LastSuccess, got.LastFailure)
+	}
+}
+
 func TestExporterStatus_String(t *testing.T) {
 	tests := []struct {
 		status ExporterStatus
@@ -184,3 +211,60 @@ func TestExporterStatus_String(t *testing.T) {
 		}
 	}
 }
+
+func TestHealthExporters_RecordExportOutcomes(t *testing.T) {
+	h := NewExporterHealth()
+	spans := tracetest.SpanStubs{{Name: "a"}}.Snapshots()
+
+	failing := healthSpanExporter{SpanExporter: failingSpanExporter{err: errors.New("unavailable")}, health: h}
+	for range 3 {
+		_ = failing.ExportSpans(context.Background(), spans)
+	}
+	if got := h.Status(SignalTraces); got != ExporterDegraded {
+		t.Errorf("traces after 3 failures = %v, want degraded", got)
+	}
+
+	ok := healthSpanExporter{SpanExporter: tracetest.NewInMemoryExporter(), health: h}
+	if err := ok.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.Status(SignalTraces); got != ExporterHealthy {
+		t.Errorf("traces after a success = %v, want healthy", got)
+	}
+
+	logs := healthLogExporter{Exporter: nopLogExporter{}, health: h}
+	_ = logs.Export(context.Background(), make([]log.Record, 1))
+	if _, ok := h.SignalStatuses()[SignalLogs]; !ok {
+		t.Error("log export was not recorded")
+	}
+}
+
+func TestExporterHealth_CountsFailures(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	counter, err := mp.Meter("test").Int64Counter("otel_agent_export_failures_total")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewExporterHealth()
+	h.SetFailureCounter(counter)
+	h.RecordFailure(SignalTraces)
+	h.RecordFailure(SignalTraces)
+	h.RecordSuccess(SignalTraces)
+	h.RecordFailure(SignalLogs)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	sum := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	got := map[string]int64{}
+	for _, dp := range sum.DataPoints {
+		signal, _ := dp.Attributes.Value(attribute.Key("signal"))
+		got[signal.AsString()] = dp.Value
+	}
+	if got[SignalTraces] != 2 || got[SignalLogs] != 1 {
+		t.Errorf("failures by signal = %v, want traces=2 logs=1", got)
+	}
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ExporterFactoryVersion is the version of the exporter factory API:
+// ExporterSettings and the *ExporterFactory signatures. It changes only on
+// incompatible changes; new ExporterSettings fields do not bump it, so
+// factories should not construct ExporterSettings or compare it.
+const ExporterFactoryVersion = 1
+
+// ExporterSettings is what an exporter factory receives.
+type ExporterSettings struct {
+	// Config is the agent configuration. Factories may read any section,
+	// e.g. Timeout, TLS or Performance.RetryAttempts.
+	Config *config.Config
+
+	// Exporter is the resolved exporter configuration of the signal: the
+	// per-signal endpoint, protocol and headers, falling back to the global
+	// ones.
+	Exporter config.SignalExporterConfig
+
+	// Logger is the agent logger.
+	Logger logger.Logger
+}
+
+// TraceExporterFactory creates the span exporter of a custom protocol.
+type TraceExporterFactory func(ctx context.Context, settings ExporterSettings) (sdktrace.SpanExporter, error)
+
+// MetricExporterFactory creates the metric exporter of a custom protocol.
+// The returned exporter decides temporality and default aggregation.
+type MetricExporterFactory func(ctx context.Context, settings ExporterSettings) (metric.Exporter, error)
+
+// LogExporterFactory creates the log exporter of a custom protocol.
+type LogExporterFactory func(ctx context.Context, settings ExporterSettings) (log.Exporter, error)
+
+var exporterFactories = struct {
+	mu      sync.RWMutex
+	traces  map[string]TraceExporterFactory
+	metrics map[string]MetricExporterFactory
+	logs    map[string]LogExporterFactory
+}{
+	traces:  make(map[string]TraceExporterFactory),
+	metrics: make(map[string]MetricExporterFactory),
+	logs:    make(map[string]LogExporterFactory),
+}
+
+// RegisterTraceExporterFactory makes protocol usable as the traces (or
+// global) exporter protocol, so proprietary exporters plug in without
+// forking the agent:
+//
+//	func init() {
+//		provider.RegisterTraceExporterFactory("kafka", func(ctx context.Context, s provider.ExporterSettings) (sdktrace.SpanExporter, error) {
+//			return kafkaexporter.New(s.Exporter.Endpoint, "spans")
+//		})
+//	}
+//
+// The exporter is wrapped like the built-in ones (reconnect, export stats,
+// health, self-telemetry). Register before Init, typically from init. Like
+// sql.Register, it panics if factory is nil, protocol is built in
+// (grpc, http, http/protobuf, stdout) or already registered.
+func RegisterTraceExporterFactory(protocol string, factory TraceExporterFactory) {
+	registerFactory(exporterFactories.traces, SignalTraces, protocol, factory, factory == nil)
+}
+
+// RegisterMetricExporterFactory is RegisterTraceExporterFactory for metrics.
+func RegisterMetricExporterFactory(protocol string, factory MetricExporterFactory) {
+	registerFactory(exporterFactories.metrics, SignalMetrics, protocol, factory, factory == nil)
+}
+
+// RegisterLogExporterFactory is RegisterTraceExporterFactory for logs.
+func RegisterLogExporterFactory(protocol string, factory LogExporterFactory) {
+	registerFactory(exporterFactories.logs, SignalLogs, protocol, factory, factory == nil)
+}
+
+// registerFactory takes isNil from the caller: a nil func stored in a type
+// parameter cannot be compared to nil.
+func registerFactory[F any](factories map[string]F, signal, protocol string, factory F, isNil bool) {
+	if isNil {
+		panic(fmt.Sprintf("provider: nil %s exporter factory for protocol %q", signal, protocol))
+	}
+	switch protocol {
+	case "", "grpc", "http", "http/protobuf", ProtocolStdout:
+		panic(fmt.Sprintf("provider: cannot register a %s exporter factory for built-in protocol %q", signal, protocol))
+	}
+
+	exporterFactories.mu.Lock()
+	defer exporterFactories.mu.Unlock()
+	if _, dup := factories[protocol]; dup {
+		panic(fmt.Sprintf("provider: %s exporter factory for protocol %q registered twice", signal, protocol))
+	}
+	factories[protocol] = factory
+	config.RegisterExporterProtocol(signal, protocol)
+}
+
+// lookupFactory returns the factory registered for protocol.
+func lookupFactory[F any](factories map[string]F, protocol string) (F, bool) {
+	exporterFactories.mu.RLock()
+	defer exporterFactories.mu.RUnlock()
+	f, ok := factories[protocol]
+	return f, ok
+}
+
+func exporterSettings(cfg *config.Config, signal string, log logger.Logger) ExporterSettings {
+	return ExporterSettings{Config: cfg, Exporter: cfg.SignalExporter(signal), Logger: log}
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const testProtocol = "test-memory"
+
+var (
+	registerTestFactory sync.Once
+	testSpans           = tracetest.NewInMemoryExporter()
+	testSettings        ExporterSettings
+)
+
+// useTestFactory registers testProtocol once per process, so the tests also
+// pass with -count=N.
+func useTestFactory() {
+	registerTestFactory.Do(func() {
+		RegisterTraceExporterFactory(testProtocol, func(_ context.Context, s ExporterSettings) (sdktrace.SpanExporter, error) {
+			testSettings = s
+			return testSpans, nil
+		})
+	})
+}
+
+func TestRegisterTraceExporterFactory_UsedForProtocol(t *testing.T) {
+	useTestFactory()
+	testSpans.Reset()
+
+	cfg := &config.Config{
+		ServiceName:      "registry",
+		Endpoint:         "broker:9092",
+		ExporterProtocol: testProtocol,
+		Traces: config.TracesConfig{
+			Sampling:       config.SamplingConfig{Type: "always_on"},
+			QueueSize:      2048,
+			MaxExportBatch: 512,
+		},
+	}
+	tp, err := NewTraceProvider(cfg, resource.Empty(), &logger.NoopLogger{})
+	if err != nil {
+		t.Fatalf("NewTraceProvider: %v", err)
+	}
+	_, span := tp.Tracer("test").Start(context.Background(), "publish")
+	span.End()
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if spans := testSpans.GetSpans(); len(spans) != 1 || spans[0].Name != "publish" {
+		t.Errorf("exported %v, want the publish span", spans)
+	}
+	if testSettings.Exporter.Endpoint != "broker:9092" || testSettings.Config != cfg {
+		t.Errorf("factory got settings %+v", testSettings)
+	}
+}
+
+func TestRegisterTraceExporterFactory_Validation(t *testing.T) {
+	useTestFactory()
+
+	cfg := &config.Config{Enabled: true, ExporterProtocol: testProtocol}
+	if err := cfg.Validate(); err != nil && strings.Contains(err.Error(), "exporter_protocol") {
+		t.Errorf("registered protocol rejected: %v", err)
+	}
+
+	cfg.Metrics.Exporter.Protocol = testProtocol
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "metrics.exporter.protocol") {
+		t.Errorf("expected metrics to reject a traces-only protocol, got %v", err)
+	}
+}
+
+func TestRegisterTraceExporterFactory_Panics(t *testing.T) {
+	useTestFactory()
+	factory := func(context.Context, ExporterSettings) (sdktrace.SpanExporter, error) { return nil, nil }
+
+	for name, register := range map[string]func(){
+		"nil":       func() { RegisterTraceExporterFactory("custom", nil) },
+		"built-in":  func() { RegisterTraceExporterFactory("grpc", factory) },
+		"duplicate": func() { RegisterTraceExporterFactory(testProtocol, factory) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			register()
+		}()
+	}
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+)
+
+type countingTraceService struct {
+	collectortrace.UnimplementedTraceServiceServer
+	spans atomic.Int64
+}
+
+func (s *countingTraceService) Export(_ context.Context, req *collectortrace.ExportTraceServiceRequest) (*collectortrace.ExportTraceServiceResponse, error) {
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			s.spans.Add(int64(len(ss.Spans)))
+		}
+	}
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+// startUnixCollector serves the OTLP trace service on a unix socket.
+func startUnixCollector(t *testing.T) (string, *countingTraceService) {
+	t.Helper()
+	// Socket paths are limited to ~100 bytes, so avoid the long t.TempDir.
+	dir, err := os.MkdirTemp("", "otlp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	path := filepath.Join(dir, "collector.sock")
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := &countingTraceService{}
+	srv := grpc.NewServer()
+	collectortrace.RegisterTraceServiceServer(srv, svc)
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(srv.Stop)
+	return path, svc
+}
+
+func exportOneSpan(t *testing.T, cfg *config.Config, dialOpts ...grpc.DialOption) {
+	t.Helper()
+	exp, err := createGRPCTraceExporter(context.Background(), cfg, &logger.NoopLogger{}, dialOpts...)
+	if err != nil {
+		t.Fatalf("createGRPCTraceExporter: %v", err)
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	span.End()
+	_ = tp.Shutdown(context.Background())
+}
+
+func TestGRPCTraceExporter_UnixSocketEndpoint(t *testing.T) {
+	path, svc := startUnixCollector(t)
+
+	exportOneSpan(t, &config.Config{Endpoint: "unix://" + path, Insecure: true, Timeout: 5 * time.Second})
+
+	if n := svc.spans.Load(); n != 1 {
+		t.Errorf("collector received %d spans, want 1", n)
+	}
+}
+
+func TestGRPCTraceExporter_CustomDialer(t *testing.T) {
+	path, svc := startUnixCollector(t)
+
+	var dials atomic.Int32
+	dialer := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		dials.Add(1)
+		return (&net.Dialer{}).DialContext(ctx, "unix", path)
+	})
+	exportOneSpan(t, &config.Config{Endpoint: "localhost:1", Insecure: true, Timeout: 5 * time.Second}, dialer)
+
+	if dials.Load() == 0 || svc.spans.Load() != 1 {
+		t.Errorf("dials = %d, spans = %d; want the custom dialer to carry the export", dials.Load(), svc.spans.Load())
+	}
+}
@@ -1,11 +1,16 @@
 package provider
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/url"
 	"regexp"
 	"strings"
 	"sync"
 
 	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/RodolfoBonis/go-otel-agent/scrub"
 )
 
 // HTTPScrubber provides PII scrubbing for HTTP request/response data.
@@ -14,7 +19,10 @@ type HTTPScrubber struct {
 	scrubCfg config.ScrubConfig
 
 	sensitiveHeaderSet map[string]struct{}
+	sensitiveKeySet    map[string]struct{}
 	compiledPatterns   []*regexp.Regexp
+	invalidPatterns    []error
+	detectors          []*scrub.Detector
 	allowedContentSet  map[string]struct{}
 	once               sync.Once
 }
@@ -41,16 +49,24 @@ func (s *HTTPScrubber) init() {
 			s.allowedContentSet[strings.ToLower(strings.TrimSpace(ct))] = struct{}{}
 		}
 
+		s.sensitiveKeySet = make(map[string]struct{}, len(s.scrubCfg.SensitiveKeys))
+		for _, k := range s.scrubCfg.SensitiveKeys {
+			s.sensitiveKeySet[k] = struct{}{}
+		}
+
 		if s.scrubCfg.Enabled {
-			for _, pattern := range s.scrubCfg.SensitivePatterns {
-				if re, err := regexp.Compile(pattern); err == nil {
-					s.compiledPatterns = append(s.compiledPatterns, re)
-				}
-			}
+			s.compiledPatterns, s.invalidPatterns = config.CompilePatterns(s.scrubCfg.SensitivePatterns)
+			s.detectors, _ = scrub.Detectors(s.scrubCfg.Detectors)
 		}
 	})
 }
 
+// InvalidPatterns returns one error per sensitive pattern that failed to
+// compile and is therefore not applied to bodies and query strings.
+func (s *HTTPScrubber) InvalidPatterns() []error {
+	return s.invalidPatterns
+}
+
 // ScrubHeaders filters and redacts HTTP headers. Sensitive headers are always
 // redacted regardless of scrub config. Returns key-value pairs suitable for
 // span attributes.
@@ -81,8 +97,9 @@ func (s *HTTPScrubber) ScrubHeaders(headers map[string][]string, allowed []strin
 	return result
 }
 
-// ScrubQueryString redacts sensitive query parameter values.
-// Only scrubs when ScrubConfig.Enabled is true.
+// ScrubQueryString redacts the values of sensitive query parameters, and
+// the values the configured detectors find in the others. Only scrubs when
+// ScrubConfig.Enabled is true.
 func (s *HTTPScrubber) ScrubQueryString(rawQuery string) string {
 	if rawQuery == "" {
 		return ""
@@ -106,6 +123,8 @@ func (s *HTTPScrubber) ScrubQueryString(rawQuery string) string {
 		key := kv[0]
 		if s.isKeyMatch(key) {
 			result = append(result, key+"="+s.redactedValue())
+		} else if len(s.detectors) > 0 {
+			result = append(result, key+"="+s.redactDetectedQueryValue(kv[1]))
 		} else {
 			result = append(result, part)
 		}
@@ -114,28 +133,165 @@ func (s *HTTPScrubber) ScrubQueryString(rawQuery string) string {
 	return strings.Join(result, "&")
 }
 
-// ScrubBody truncates and redacts sensitive patterns in body content.
-// Returns the scrubbed body string.
+// ScrubBody truncates and redacts sensitive data in body content. When
+// scrubbing is enabled, a JSON body has the values of sensitive keys
+// redacted at any depth and is re-serialized compactly before truncation;
+// other bodies, including JSON cut short by a capture limit, get
+// pattern-based redaction. Values found by the configured detectors are
+// redacted in both. Returns the scrubbed body string.
 func (s *HTTPScrubber) ScrubBody(body string, maxSize int) string {
 	if body == "" {
 		return ""
 	}
 
+	scrubbedJSON := false
+	if s.scrubCfg.Enabled && looksLikeJSON(body) {
+		if scrubbed, ok := s.scrubJSON(body); ok {
+			body, scrubbedJSON = scrubbed, true
+		}
+	}
+
 	// Truncate
 	if maxSize > 0 && len(body) > maxSize {
 		body = body[:maxSize] + "...[truncated]"
 	}
 
 	// Apply pattern-based redaction when scrubbing is enabled
-	if s.scrubCfg.Enabled {
+	if s.scrubCfg.Enabled && !scrubbedJSON {
 		for _, re := range s.compiledPatterns {
 			body = re.ReplaceAllString(body, s.redactedValue())
 		}
+		body = scrub.RedactDetected(body, s.detectors, s.redactedValue())
 	}
 
 	return body
 }
 
+// looksLikeJSON reports whether body starts like a JSON object or array.
+func looksLikeJSON(body string) bool {
+	trimmed := strings.TrimLeft(body, " \t\r\n")
+	return trimmed != "" && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// scrubJSON re-serializes the JSON document body with the values of
+// sensitive keys, whole objects and arrays included, replaced by the
+// redacted value. Key order is kept. It reports false when body is not a
+// single valid JSON document.
+func (s *HTTPScrubber) scrubJSON(body string) (string, bool) {
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+
+	var buf bytes.Buffer
+	buf.Grow(len(body))
+	if err := s.scrubJSONValue(dec, &buf); err != nil {
+		return "", false
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return "", false
+	}
+	return buf.String(), true
+}
+
+func (s *HTTPScrubber) scrubJSONValue(dec *json.Decoder, buf *bytes.Buffer) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		if str, isString := tok.(string); isString {
+			tok = scrub.RedactDetected(str, s.detectors, s.redactedValue())
+		}
+		return writeJSONToken(buf, tok)
+	}
+
+	switch delim {
+	case '{':
+		buf.WriteByte('{')
+		for i := 0; dec.More(); i++ {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := keyTok.(string)
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONToken(buf, key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+
+			if s.isSensitiveJSONKey(key) {
+				var skipped json.RawMessage
+				if err := dec.Decode(&skipped); err != nil {
+					return err
+				}
+				if err := writeJSONToken(buf, s.redactedValue()); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := s.scrubJSONValue(dec, buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case '[':
+		buf.WriteByte('[')
+		for i := 0; dec.More(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := s.scrubJSONValue(dec, buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	}
+
+	// Closing delimiter.
+	_, err = dec.Token()
+	return err
+}
+
+// writeJSONToken writes a scalar token (string, json.Number, bool or nil).
+func writeJSONToken(buf *bytes.Buffer, tok json.Token) error {
+	if n, ok := tok.(json.Number); ok {
+		buf.WriteString(n.String())
+		return nil
+	}
+	data, err := json.Marshal(tok)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// isSensitiveJSONKey reports whether key matches a sensitive key exactly or
+// a sensitive pattern, the rules ScrubProcessor applies to attribute keys.
+func (s *HTTPScrubber) isSensitiveJSONKey(key string) bool {
+	if _, ok := s.sensitiveKeySet[key]; ok {
+		return true
+	}
+	return s.isKeyMatch(key)
+}
+
+// redactDetectedQueryValue applies the detectors to the decoded value of a
+// query parameter, re-encoding it only when something was redacted.
+func (s *HTTPScrubber) redactDetectedQueryValue(value string) string {
+	decoded, err := url.QueryUnescape(value)
+	if err != nil {
+		decoded = value
+	}
+	if redacted := scrub.RedactDetected(decoded, s.detectors, s.redactedValue()); redacted != decoded {
+		return url.QueryEscape(redacted)
+	}
+	return value
+}
+
 // IsAllowedContentType checks if the content-type is eligible for body capture.
 func (s *HTTPScrubber) IsAllowedContentType(contentType string) bool {
 	if len(s.allowedContentSet) == 0 {
@@ -217,6 +217,57 @@ func TestScrubBody_DisabledScrub(t *testing.T) {
 	}
 }
 
+func TestScrubBody_RedactsJSONByKey(t *testing.T) {
+	s := NewHTTPScrubber(defaultHTTPConfig(), defaultScrubConfig())
+
+	body := `{"user": "john", "password": "secret123",
+		"profile": {"refresh_token": {"value": "r1", "exp": 3600}, "age": 42},
+		"items": [{"sku": "a<b", "api_secret": ["x", "y"]}, null, true, 1.50]}`
+	want := `{"user":"john","password":"[REDACTED]",` +
+		`"profile":{"refresh_token":"[REDACTED]","age":42},` +
+		`"items":[{"sku":"a\u003cb","api_secret":"[REDACTED]"},null,true,1.50]}`
+
+	if got := s.ScrubBody(body, 8192); got != want {
+		t.Errorf("ScrubBody(JSON) =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestScrubBody_JSONRedactedBeforeTruncation(t *testing.T) {
+	s := NewHTTPScrubber(defaultHTTPConfig(), defaultScrubConfig())
+
+	got := s.ScrubBody(`{"token": "abcdefghijklmnopqrstuvwxyz", "id": 1}`, 20)
+	if want := `{"token":"[REDACTED]...[truncated]`; got != want {
+		t.Errorf("ScrubBody = %q, want %q", got, want)
+	}
+}
+
+func TestScrubBody_InvalidJSONFallsBackToPatterns(t *testing.T) {
+	s := NewHTTPScrubber(defaultHTTPConfig(), defaultScrubConfig())
+
+	// A body cut short by the capture limit is not valid JSON.
+	body := `{"user": "john", "password": "secret1`
+	got := s.ScrubBody(body, 0)
+	if got == body {
+		t.Errorf("ScrubBody = %q, want pattern-based redaction", got)
+	}
+}
+
+func TestScrubBody_RedactsDetectedValues(t *testing.T) {
+	scrubCfg := defaultScrubConfig()
+	scrubCfg.Detectors = []string{"email"}
+	s := NewHTTPScrubber(defaultHTTPConfig(), scrubCfg)
+
+	if got := s.ScrubBody(`{"note": "reply to jane@example.com"}`, 0); got != `{"note":"reply to [REDACTED]"}` {
+		t.Errorf("ScrubBody(JSON) = %q", got)
+	}
+	if got := s.ScrubBody("reply to jane@example.com", 0); got != "reply to [REDACTED]" {
+		t.Errorf("ScrubBody(text) = %q", got)
+	}
+	if got := s.ScrubQueryString("q=jane%40example.com&page=2"); got != "q=%5BREDACTED%5D&page=2" {
+		t.Errorf("ScrubQueryString = %q", got)
+	}
+}
+
 // --- IsAllowedContentType ---
 
 func TestIsAllowedContentType_JSONAllowed(t *testing.T) {
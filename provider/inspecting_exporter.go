@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// inspectTopSpanNames is how many of the most frequent span names are logged
+// per batch.
+const inspectTopSpanNames = 5
+
+// InspectingSpanExporter wraps a SpanExporter and logs a summary of every
+// batch it sends: span count, approximate payload size and the most frequent
+// span names. It is installed in debug mode to check what actually leaves
+// the process when data goes missing or bandwidth looks too high.
+//
+// Only traces are inspected: the summary is written through the agent
+// logger, which may itself export over OTLP, so wrapping the log exporter
+// would feed back into itself.
+type InspectingSpanExporter struct {
+	next sdktrace.SpanExporter
+	log  logger.Logger
+}
+
+// NewInspectingSpanExporter wraps next, logging each batch summary to log at
+// debug level.
+func NewInspectingSpanExporter(next sdktrace.SpanExporter, log logger.Logger) *InspectingSpanExporter {
+	return &InspectingSpanExporter{next: next, log: log}
+}
+
+// ExportSpans implements sdktrace.SpanExporter.
+func (e *InspectingSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	start := time.Now()
+	err := e.next.ExportSpans(ctx, spans)
+
+	fields := SummarizeSpans(spans)
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	e.log.Debug(context.Background(), "OTLP span batch", fields)
+	return err
+}
+
+// Shutdown implements sdktrace.SpanExporter.
+func (e *InspectingSpanExporter) Shutdown(ctx context.Context) error {
+	return e.next.Shutdown(ctx)
+}
+
+// SummarizeSpans describes a batch of spans: "spans" (count), "errors"
+// (spans with error status), "approx_bytes" (estimated uncompressed OTLP
+// size) and "top_spans" (most frequent names with their counts).
+func SummarizeSpans(spans []sdktrace.ReadOnlySpan) logger.Fields {
+	counts := make(map[string]int)
+	size, errs := 0, 0
+	for _, s := range spans {
+		counts[s.Name()]++
+		size += approxSpanSize(s)
+		if s.Status().Code == codes.Error {
+			errs++
+		}
+	}
+
+	return logger.Fields{
+		"spans":        len(spans),
+		"errors":       errs,
+		"approx_bytes": size,
+		"top_spans":    topCounts(counts, inspectTopSpanNames),
+	}
+}
+
+// topCounts renders the n most frequent names of counts as "name (count)".
+func topCounts(counts map[string]int, n int) []string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Or(counts[b]-counts[a], cmp.Compare(a, b))
+	})
+	if len(names) > n {
+		names = names[:n]
+	}
+	top := make([]string, len(names))
+	for i, name := range names {
+		top[i] = fmt.Sprintf("%s (%d)", name, counts[name])
+	}
+	return top
+}
+
+// approxSpanSize estimates the protobuf-encoded size of a span: fixed-size
+// IDs and timestamps plus the variable-length name, attributes, events and
+// links. It ignores varint and tag overhead, so it slightly underestimates.
+func approxSpanSize(s sdktrace.ReadOnlySpan) int {
+	const fixed = 16 + 8 + 8 + 8 + 8 + 4 // trace, span, parent IDs, start, end, kind
+	n := fixed + len(s.Name()) + len(s.Status().Description) + attrsSize(s.Attributes())
+	for _, ev := range s.Events() {
+		n += 8 + len(ev.Name) + attrsSize(ev.Attributes)
+	}
+	for _, l := range s.Links() {
+		n += 16 + 8 + attrsSize(l.Attributes)
+	}
+	return n
+}
+
+func attrsSize(attrs []attribute.KeyValue) int {
+	n := 0
+	for _, kv := range attrs {
+		n += len(kv.Key)
+		switch kv.Value.Type() {
+		case attribute.STRING:
+			n += len(kv.Value.AsString())
+		case attribute.STRINGSLICE:
+			for _, v := range kv.Value.AsStringSlice() {
+				n += len(v)
+			}
+		case attribute.BOOLSLICE:
+			n += len(kv.Value.AsBoolSlice())
+		case attribute.INT64SLICE:
+			n += 8 * len(kv.Value.AsInt64Slice())
+		case attribute.FLOAT64SLICE:
+			n += 8 * len(kv.Value.AsFloat64Slice())
+		default:
+			n += 8
+		}
+	}
+	return n
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// debugLogger captures Debug calls for assertions.
+type debugLogger struct {
+	logger.NoopLogger
+	fields []logger.Fields
+}
+
+func (l *debugLogger) Debug(_ context.Context, _ string, fields ...logger.Fields) {
+	l.fields = append(l.fields, fields...)
+}
+
+type failingExporter struct{ tracetest.InMemoryExporter }
+
+func (e *failingExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error {
+	return errors.New("collector unavailable")
+}
+
+func TestSummarizeSpans(t *testing.T) {
+	stubs := tracetest.SpanStubs{
+		{Name: "GET /orders"},
+		{Name: "GET /orders"},
+		{Name: "SELECT orders", Attributes: []attribute.KeyValue{attribute.String("db.statement", "SELECT * FROM orders")}},
+		{Name: "GET /users", Status: sdktrace.Status{Code: codes.Error}},
+	}
+
+	got := SummarizeSpans(stubs.Snapshots())
+
+	if got["spans"] != 4 || got["errors"] != 1 {
+		t.Errorf("spans/errors = %v/%v, want 4/1", got["spans"], got["errors"])
+	}
+	wantTop := []string{"GET /orders (2)", "GET /users (1)", "SELECT orders (1)"}
+	if top := got["top_spans"].([]string); !slices.Equal(top, wantTop) {
+		t.Errorf("top_spans = %v, want %v", top, wantTop)
+	}
+	if size := got["approx_bytes"].(int); size < len("db.statement")+len("SELECT * FROM orders") {
+		t.Errorf("approx_bytes = %d, should include attribute sizes", size)
+	}
+}
+
+func TestInspectingSpanExporter_LogsEachBatch(t *testing.T) {
+	log := &debugLogger{}
+	next := tracetest.NewInMemoryExporter()
+	exp := NewInspectingSpanExporter(next, log)
+
+	spans := tracetest.SpanStubs{{Name: "GET /orders"}}.Snapshots()
+	if err := exp.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+
+	if len(next.GetSpans()) != 1 {
+		t.Errorf("wrapped exporter got %d spans, want 1", len(next.GetSpans()))
+	}
+	if len(log.fields) != 1 || log.fields[0]["spans"] != 1 {
+		t.Errorf("logged %v, want one summary with spans=1", log.fields)
+	}
+}
+
+func TestInspectingSpanExporter_ReportsExportError(t *testing.T) {
+	log := &debugLogger{}
+	exp := NewInspectingSpanExporter(&failingExporter{}, log)
+
+	err := exp.ExportSpans(context.Background(), tracetest.SpanStubs{{Name: "op"}}.Snapshots())
+	if err == nil {
+		t.Fatal("expected export error to be returned")
+	}
+	if len(log.fields) != 1 || log.fields[0]["error"] != "collector unavailable" {
+		t.Errorf("logged %v, want the export error", log.fields)
+	}
+}
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/google/uuid"
+)
+
+// Instance ID strategies for service.instance.id.
+const (
+	InstanceIDHostname = "hostname"
+	InstanceIDPodUID   = "pod_uid"
+	InstanceIDUUID     = "uuid"
+	InstanceIDFile     = "file"
+)
+
+var (
+	processInstanceID     string
+	processInstanceIDOnce sync.Once
+)
+
+// ResolveInstanceID returns the service.instance.id for the given config.
+// An explicit Resource.ServiceInstance always wins; otherwise the value is
+// derived from Resource.InstanceIDStrategy.
+func ResolveInstanceID(cfg *config.Config) (string, error) {
+	if cfg.Resource.ServiceInstance != "" {
+		return cfg.Resource.ServiceInstance, nil
+	}
+
+	switch strings.ToLower(cfg.Resource.InstanceIDStrategy) {
+	case "", InstanceIDHostname:
+		return hostname(), nil
+	case InstanceIDPodUID:
+		// Fall back to hostname outside Kubernetes (or when the downward API
+		// does not expose the pod UID).
+		if cfg.Resource.K8sPodUID != "" {
+			return cfg.Resource.K8sPodUID, nil
+		}
+		return hostname(), nil
+	case InstanceIDUUID:
+		processInstanceIDOnce.Do(func() {
+			processInstanceID = uuid.NewString()
+		})
+		return processInstanceID, nil
+	case InstanceIDFile:
+		return fileInstanceID(instanceIDFilePath(cfg))
+	default:
+		return "", fmt.Errorf("unsupported instance ID strategy: %s (use 'hostname', 'pod_uid', 'uuid' or 'file')", cfg.Resource.InstanceIDStrategy)
+	}
+}
+
+// instanceIDFilePath returns the configured file path, defaulting to a
+// per-service file under the OS temp directory.
+func instanceIDFilePath(cfg *config.Config) string {
+	if cfg.Resource.InstanceIDFile != "" {
+		return cfg.Resource.InstanceIDFile
+	}
+	name := cfg.ServiceName
+	if name == "" {
+		name = "service"
+	}
+	return filepath.Join(os.TempDir(), "go-otel-agent", name+".instance-id")
+}
+
+// fileInstanceID reads a previously persisted instance ID from path, creating
+// and persisting a new UUID when the file does not exist yet.
+func fileInstanceID(path string) (string, error) {
+	if data, err := os.ReadFile(path); err == nil {
+		if id := strings.TrimSpace(string(data)); id != "" {
+			return id, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read instance ID file %s: %w", path, err)
+	}
+
+	id := uuid.NewString()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create instance ID directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(id+"\n"), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write instance ID file %s: %w", path, err)
+	}
+	return id, nil
+}
+
+func hostname() string {
+	if h, err := os.Hostname(); err == nil {
+		return h
+	}
+	return "unknown"
+}
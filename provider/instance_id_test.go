@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+)
+
+func TestResolveInstanceID_ExplicitValueWins(t *testing.T) {
+	cfg := &config.Config{Resource: config.ResourceConfig{
+		ServiceInstance:    "explicit-id",
+		InstanceIDStrategy: InstanceIDUUID,
+	}}
+
+	got, err := ResolveInstanceID(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "explicit-id" {
+		t.Errorf("ResolveInstanceID = %q, want %q", got, "explicit-id")
+	}
+}
+
+func TestResolveInstanceID_Hostname(t *testing.T) {
+	cfg := &config.Config{Resource: config.ResourceConfig{InstanceIDStrategy: InstanceIDHostname}}
+
+	got, err := ResolveInstanceID(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != hostname() {
+		t.Errorf("ResolveInstanceID = %q, want hostname %q", got, hostname())
+	}
+}
+
+func TestResolveInstanceID_PodUID(t *testing.T) {
+	cfg := &config.Config{Resource: config.ResourceConfig{
+		InstanceIDStrategy: InstanceIDPodUID,
+		K8sPodUID:          "0b9f2a4e-uid",
+	}}
+
+	got, err := ResolveInstanceID(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "0b9f2a4e-uid" {
+		t.Errorf("ResolveInstanceID = %q, want %q", got, "0b9f2a4e-uid")
+	}
+}
+
+func TestResolveInstanceID_PodUIDFallsBackToHostname(t *testing.T) {
+	cfg := &config.Config{Resource: config.ResourceConfig{InstanceIDStrategy: InstanceIDPodUID}}
+
+	got, err := ResolveInstanceID(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != hostname() {
+		t.Errorf("ResolveInstanceID = %q, want hostname fallback %q", got, hostname())
+	}
+}
+
+func TestResolveInstanceID_UUIDStableWithinProcess(t *testing.T) {
+	cfg := &config.Config{Resource: config.ResourceConfig{InstanceIDStrategy: InstanceIDUUID}}
+
+	first, err := ResolveInstanceID(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _ := ResolveInstanceID(cfg)
+
+	if len(first) != 36 {
+		t.Errorf("expected UUID, got %q", first)
+	}
+	if first != second {
+		t.Errorf("expected same UUID within a process, got %q and %q", first, second)
+	}
+}
+
+func TestResolveInstanceID_FilePersistsAcrossCalls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "instance-id")
+	cfg := &config.Config{Resource: config.ResourceConfig{
+		InstanceIDStrategy: InstanceIDFile,
+		InstanceIDFile:     path,
+	}}
+
+	first, err := ResolveInstanceID(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected instance ID file to be written: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != first {
+		t.Errorf("file content = %q, want %q", data, first)
+	}
+
+	second, err := ResolveInstanceID(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != second {
+		t.Errorf("expected persisted ID %q, got %q", first, second)
+	}
+}
+
+func TestResolveInstanceID_UnknownStrategy(t *testing.T) {
+	cfg := &config.Config{Resource: config.ResourceConfig{InstanceIDStrategy: "bogus"}}
+
+	if _, err := ResolveInstanceID(cfg); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"time"
 
@@ -9,91 +10,222 @@ import (
 	"github.com/RodolfoBonis/go-otel-agent/logger"
 	otlploggrpc "go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
 	otlploghttp "go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	otelmetric "go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/resource"
+	"google.golang.org/grpc"
 )
 
+// LogProviderOption customizes NewLogProvider.
+type LogProviderOption func(*logProviderOptions)
+
+type logProviderOptions struct {
+	exportStats *ExportStats
+	health      *ExporterHealth
+	blocklist   *Blocklist
+	self        *SelfTelemetry
+	reconnector *Reconnector
+	dropMeter   otelmetric.Meter
+	dialOptions []grpc.DialOption
+}
+
+// WithLogGRPCDialOptions passes extra grpc.DialOptions (custom dialers,
+// keepalive, load balancing) to the gRPC log exporter.
+func WithLogGRPCDialOptions(opts ...grpc.DialOption) LogProviderOption {
+	return func(o *logProviderOptions) {
+		o.dialOptions = opts
+	}
+}
+
+// WithLogBlocklist drops log records of subjects and traces blocked by b.
+func WithLogBlocklist(b *Blocklist) LogProviderOption {
+	return func(o *logProviderOptions) {
+		o.blocklist = b
+	}
+}
+
 // NewLogProvider creates a LoggerProvider with OTLP exporter.
-func NewLogProvider(cfg *config.Config, res *resource.Resource, lgr logger.Logger) (*log.LoggerProvider, error) {
+func NewLogProvider(cfg *config.Config, res *resource.Resource, lgr logger.Logger, opts ...LogProviderOption) (*log.LoggerProvider, error) {
 	ctx := context.Background()
 
-	exporter, err := createLogExporter(ctx, cfg, lgr)
+	var o logProviderOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	res, err := SignalResource(res, cfg, SignalLogs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build logs resource: %w", err)
+	}
+
+	exporter, err := createLogExporter(ctx, cfg, lgr, o.dialOptions...)
 	if err != nil {
 		return nil, err
 	}
+	if o.reconnector != nil {
+		swap := newSwappable(exporter, func() (log.Exporter, error) {
+			return createLogExporter(ctx, cfg, lgr, o.dialOptions...)
+		})
+		exporter = reconnectingLogExporter{swap}
+		o.reconnector.register(SignalLogs, swap.reconnect)
+	}
+	if o.exportStats != nil {
+		exporter = statsLogExporter{Exporter: exporter, stats: o.exportStats}
+	}
+	if o.health != nil {
+		exporter = healthLogExporter{Exporter: exporter, health: o.health}
+	}
+	var queue *queueUsage
+	if o.self != nil {
+		queue = o.self.trackQueue(SignalLogs, cfg.Logs.QueueSize)
+		exporter = selfTelemetryLogExporter{Exporter: exporter, st: o.self, queue: queue}
+	}
 
-	provider := log.NewLoggerProvider(
-		log.WithProcessor(log.NewBatchProcessor(exporter,
+	var processor log.Processor
+	if cfg.Logs.DropPolicy != "" {
+		var dropped otelmetric.Int64Counter
+		if o.dropMeter != nil {
+			dropped, err = o.dropMeter.Int64Counter(LogsDroppedMetric,
+				otelmetric.WithDescription("Log records dropped because the export queue was full"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create dropped logs counter: %w", err)
+			}
+		}
+		processor = NewDropPolicyLogProcessor(exporter, cfg.Logs, 5*time.Second, dropped)
+	} else {
+		processor = log.NewBatchProcessor(exporter,
 			log.WithExportTimeout(cfg.Logs.BatchTimeout),
 			log.WithExportMaxBatchSize(cfg.Logs.BatchSize),
 			log.WithExportInterval(5*time.Second),
-		)),
+			log.WithMaxQueueSize(cfg.Logs.QueueSize),
+		)
+	}
+	if o.exportStats != nil {
+		processor = statsLogProcessor{Processor: processor, stats: o.exportStats}
+	}
+	if queue != nil {
+		processor = queueLogProcessor{Processor: processor, queue: queue}
+	}
+	// Scrub before the batch processor but after the blocklist, which
+	// must see the identifiers the detectors would redact.
+	if cfg.Scrub.Enabled && len(cfg.Scrub.Detectors) > 0 {
+		processor = NewScrubLogProcessor(processor, cfg.Scrub)
+	}
+	if o.blocklist != nil {
+		processor = NewBlocklistLogProcessor(processor, o.blocklist)
+	}
+
+	provider := log.NewLoggerProvider(
+		log.WithProcessor(processor),
 		log.WithResource(res),
 	)
 
 	return provider, nil
 }
 
-func createLogExporter(ctx context.Context, cfg *config.Config, lgr logger.Logger) (log.Exporter, error) {
-	protocol := cfg.ExporterProtocol
+func createLogExporter(ctx context.Context, cfg *config.Config, lgr logger.Logger, dialOpts ...grpc.DialOption) (log.Exporter, error) {
+	if cfg.Features.DryRun {
+		return dryRunLogExporter{log: lgr}, nil
+	}
+	protocol := cfg.SignalExporter(SignalLogs).Protocol
 	if protocol == "" {
 		protocol = "grpc"
 	}
 
 	switch protocol {
 	case "grpc":
-		return createGRPCLogExporter(ctx, cfg, lgr)
+		return createGRPCLogExporter(ctx, cfg, lgr, dialOpts...)
 	case "http", "http/protobuf":
 		return createHTTPLogExporter(ctx, cfg, lgr)
+	case ProtocolStdout:
+		return createStdoutLogExporter(ctx, cfg, lgr)
 	default:
-		return nil, fmt.Errorf("unsupported OTLP protocol: %s (use 'grpc' or 'http')", protocol)
+		if factory, ok := lookupFactory(exporterFactories.logs, protocol); ok {
+			return factory(ctx, exporterSettings(cfg, SignalLogs, lgr))
+		}
+		return nil, fmt.Errorf("unsupported OTLP protocol: %s (use 'grpc', 'http', 'stdout' or a registered exporter factory)", protocol)
 	}
 }
 
-func createGRPCLogExporter(ctx context.Context, cfg *config.Config, lgr logger.Logger) (log.Exporter, error) {
+func createGRPCLogExporter(ctx context.Context, cfg *config.Config, lgr logger.Logger, dialOpts ...grpc.DialOption) (log.Exporter, error) {
+	exp := cfg.SignalExporter(SignalLogs)
+	host, _ := splitEndpoint(exp.Endpoint)
 	opts := []otlploggrpc.Option{
-		otlploggrpc.WithEndpoint(cfg.Endpoint),
+		otlploggrpc.WithEndpoint(host),
 		otlploggrpc.WithTimeout(cfg.Timeout),
 	}
 
 	if cfg.Insecure {
 		opts = append(opts, otlploggrpc.WithInsecure())
+	} else {
+		creds, err := buildTLSCredentials(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlploggrpc.WithTLSCredentials(creds))
 	}
-	if cfg.Compression != "" && cfg.Compression != "none" {
-		opts = append(opts, otlploggrpc.WithCompressor(cfg.Compression))
+	compression, err := NormalizeCompression("grpc", cfg.Compression)
+	if err != nil {
+		return nil, err
+	}
+	if compression != CompressionNone {
+		opts = append(opts, otlploggrpc.WithCompressor(compression))
 	}
 
-	headers := cfg.ResolvedAuthHeaders()
+	headers := exp.Headers
 	if len(headers) > 0 {
 		opts = append(opts, otlploggrpc.WithHeaders(headers))
 	}
 
+	if len(dialOpts) > 0 {
+		opts = append(opts, otlploggrpc.WithDialOption(dialOpts...))
+	}
+
 	exporter, err := otlploggrpc.New(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create OTLP gRPC log exporter: %w", err)
 	}
 
 	lgr.Info(ctx, "OTLP log exporter initialized", logger.Fields{
-		"protocol": "grpc", "endpoint": cfg.Endpoint,
+		"protocol": "grpc", "endpoint": exp.Endpoint,
 	})
 
 	return exporter, nil
 }
 
 func createHTTPLogExporter(ctx context.Context, cfg *config.Config, lgr logger.Logger) (log.Exporter, error) {
+	exp := cfg.SignalExporter(SignalLogs)
+	host, path := splitEndpoint(exp.Endpoint)
 	opts := []otlploghttp.Option{
-		otlploghttp.WithEndpoint(cfg.Endpoint),
+		otlploghttp.WithEndpoint(host),
 		otlploghttp.WithTimeout(cfg.Timeout),
 	}
+	if path != "" {
+		opts = append(opts, otlploghttp.WithURLPath(path))
+	}
 
+	var tlsCfg *tls.Config
 	if cfg.Insecure {
 		opts = append(opts, otlploghttp.WithInsecure())
+	} else {
+		var err error
+		if tlsCfg, err = BuildTLSConfig(cfg.TLS); err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlploghttp.WithTLSClientConfig(tlsCfg))
+	}
+	compression, err := NormalizeCompression("http", cfg.Compression)
+	if err != nil {
+		return nil, err
 	}
-	if cfg.Compression != "" && cfg.Compression != "none" {
+	switch compression {
+	case CompressionGzip:
 		opts = append(opts, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+	case CompressionZstd:
+		opts = append(opts, otlploghttp.WithHTTPClient(newZstdHTTPClient(cfg.Timeout, tlsCfg)))
 	}
 
-	headers := cfg.ResolvedAuthHeaders()
+	headers := exp.Headers
 	if len(headers) > 0 {
 		opts = append(opts, otlploghttp.WithHeaders(headers))
 	}
@@ -104,7 +236,7 @@ func createHTTPLogExporter(ctx context.Context, cfg *config.Config, lgr logger.L
 	}
 
 	lgr.Info(ctx, "OTLP log exporter initialized", logger.Fields{
-		"protocol": "http", "endpoint": cfg.Endpoint,
+		"protocol": "http", "endpoint": exp.Endpoint,
 	})
 
 	return exporter, nil
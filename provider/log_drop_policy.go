@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/log"
+)
+
+// LogsDroppedMetric counts log records a DropPolicyLogProcessor discarded,
+// labelled with the drop policy.
+const LogsDroppedMetric = "otel_agent_logs_dropped_total"
+
+// WithLogDropMeter counts the records dropped under LogsConfig.DropPolicy in
+// the LogsDroppedMetric counter of meter.
+func WithLogDropMeter(meter otelmetric.Meter) LogProviderOption {
+	return func(o *logProviderOptions) {
+		o.dropMeter = meter
+	}
+}
+
+// DropPolicyLogProcessor batches log records like the SDK batch processor,
+// but applies a configurable policy when its queue is full: evict the oldest
+// record, discard the new one, or block the caller for a bounded time. It
+// lets a service choose between logging backpressure slowing requests down
+// and shedding records, and counts what it sheds.
+type DropPolicyLogProcessor struct {
+	exporter      log.Exporter
+	policy        string
+	blockTimeout  time.Duration
+	batchSize     int
+	interval      time.Duration
+	exportTimeout time.Duration
+
+	queue   chan log.Record
+	dropped atomic.Uint64
+	counter otelmetric.Int64Counter
+
+	flush    chan chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewDropPolicyLogProcessor starts a DropPolicyLogProcessor exporting to
+// exporter with the queue, batch and policy settings of cfg. counter, when
+// not nil, is incremented for every dropped record.
+func NewDropPolicyLogProcessor(exporter log.Exporter, cfg config.LogsConfig, interval time.Duration, counter otelmetric.Int64Counter) *DropPolicyLogProcessor {
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	policy := cfg.DropPolicy
+	if policy == "" {
+		policy = config.DropOldest
+	}
+	exportTimeout := cfg.BatchTimeout
+	if exportTimeout <= 0 {
+		exportTimeout = 30 * time.Second
+	}
+
+	p := &DropPolicyLogProcessor{
+		exporter:      exporter,
+		policy:        policy,
+		blockTimeout:  cfg.BlockTimeout,
+		batchSize:     min(max(cfg.BatchSize, 1), queueSize),
+		interval:      interval,
+		exportTimeout: exportTimeout,
+		queue:         make(chan log.Record, queueSize),
+		counter:       counter,
+		flush:         make(chan chan struct{}),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Dropped returns the number of records dropped under the policy.
+func (p *DropPolicyLogProcessor) Dropped() uint64 {
+	return p.dropped.Load()
+}
+
+// Enabled reports whether the processor still accepts records.
+func (p *DropPolicyLogProcessor) Enabled(context.Context, log.EnabledParameters) bool {
+	select {
+	case <-p.stop:
+		return false
+	default:
+		return true
+	}
+}
+
+// OnEmit queues a copy of r, applying the drop policy when the queue is full.
+func (p *DropPolicyLogProcessor) OnEmit(ctx context.Context, r *log.Record) error {
+	select {
+	case <-p.stop:
+		return nil
+	default:
+	}
+
+	rec := r.Clone()
+	select {
+	case p.queue <- rec:
+		return nil
+	default:
+	}
+
+	switch p.policy {
+	case config.DropNewest:
+		p.drop(ctx)
+	case config.BlockWithTimeout:
+		timer := time.NewTimer(p.blockTimeout)
+		defer timer.Stop()
+		select {
+		case p.queue <- rec:
+		case <-timer.C:
+			p.drop(ctx)
+		case <-p.stop:
+		}
+	default:
+		for {
+			select {
+			case p.queue <- rec:
+				return nil
+			default:
+			}
+			select {
+			case <-p.queue:
+				p.drop(ctx)
+			default:
+			}
+		}
+	}
+	return nil
+}
+
+func (p *DropPolicyLogProcessor) drop(ctx context.Context) {
+	p.dropped.Add(1)
+	if p.counter != nil {
+		p.counter.Add(ctx, 1, otelmetric.WithAttributes(attribute.String("policy", p.policy)))
+	}
+}
+
+// ForceFlush exports every queued record and flushes the exporter.
+func (p *DropPolicyLogProcessor) ForceFlush(ctx context.Context) error {
+	done := make(chan struct{})
+	select {
+	case p.flush <- done:
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-done:
+		return p.exporter.ForceFlush(ctx)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown exports every queued record and shuts the exporter down.
+func (p *DropPolicyLogProcessor) Shutdown(ctx context.Context) error {
+	var err error
+	p.stopOnce.Do(func() {
+		close(p.stop)
+		select {
+		case <-p.done:
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		}
+		err = p.exporter.Shutdown(ctx)
+	})
+	return err
+}
+
+func (p *DropPolicyLogProcessor) run() {
+	defer close(p.done)
+
+	batch := make([]log.Record, 0, p.batchSize)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	export := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), p.exportTimeout)
+		_ = p.exporter.Export(ctx, batch)
+		cancel()
+		clear(batch)
+		batch = batch[:0]
+	}
+	drain := func() {
+		for {
+			select {
+			case r := <-p.queue:
+				if batch = append(batch, r); len(batch) >= p.batchSize {
+					export()
+				}
+			default:
+				export()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-p.stop:
+			drain()
+			return
+		case done := <-p.flush:
+			drain()
+			close(done)
+		case <-ticker.C:
+			export()
+		case r := <-p.queue:
+			if batch = append(batch, r); len(batch) >= p.batchSize {
+				export()
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/log"
+)
+
+// blockingLogExporter holds the first export until release is closed and
+// records the bodies of every exported record.
+type blockingLogExporter struct {
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	bodies  []string
+}
+
+func newBlockingLogExporter() *blockingLogExporter {
+	return &blockingLogExporter{started: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (e *blockingLogExporter) Export(_ context.Context, records []log.Record) error {
+	e.once.Do(func() { close(e.started) })
+	<-e.release
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.bodies = append(e.bodies, r.Body().AsString())
+	}
+	return nil
+}
+
+func (e *blockingLogExporter) Shutdown(context.Context) error   { return nil }
+func (e *blockingLogExporter) ForceFlush(context.Context) error { return nil }
+
+func (e *blockingLogExporter) exported() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.bodies...)
+}
+
+func emitLog(t *testing.T, p log.Processor, body string) {
+	t.Helper()
+	var r log.Record
+	r.SetBody(otellog.StringValue(body))
+	if err := p.OnEmit(context.Background(), &r); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// fillDropPolicyQueue starts a processor with a queue of two and a batch of
+// one, emits "first" so the exporter blocks on it, then fills the queue with
+// "a" and "b" and emits "c" into the full queue.
+func fillDropPolicyQueue(t *testing.T, policy string) (*DropPolicyLogProcessor, *blockingLogExporter) {
+	exp := newBlockingLogExporter()
+	p := NewDropPolicyLogProcessor(exp, config.LogsConfig{
+		QueueSize: 2, BatchSize: 1, DropPolicy: policy, BlockTimeout: 20 * time.Millisecond,
+	}, time.Hour, nil)
+
+	emitLog(t, p, "first")
+	<-exp.started
+	for _, body := range []string{"a", "b", "c"} {
+		emitLog(t, p, body)
+	}
+	return p, exp
+}
+
+func TestDropPolicyLogProcessor_Policies(t *testing.T) {
+	for _, tt := range []struct {
+		policy string
+		want   []string
+	}{
+		{config.DropOldest, []string{"first", "b", "c"}},
+		{config.DropNewest, []string{"first", "a", "b"}},
+		{config.BlockWithTimeout, []string{"first", "a", "b"}},
+	} {
+		t.Run(tt.policy, func(t *testing.T) {
+			p, exp := fillDropPolicyQueue(t, tt.policy)
+			close(exp.release)
+			if err := p.Shutdown(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := exp.exported(); !slices.Equal(got, tt.want) {
+				t.Errorf("exported %v, want %v", got, tt.want)
+			}
+			if got := p.Dropped(); got != 1 {
+				t.Errorf("Dropped() = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestDropPolicyLogProcessor_BlockWaitsForRoom(t *testing.T) {
+	exp := newBlockingLogExporter()
+	p := NewDropPolicyLogProcessor(exp, config.LogsConfig{
+		QueueSize: 1, BatchSize: 1, DropPolicy: config.BlockWithTimeout, BlockTimeout: 5 * time.Second,
+	}, time.Hour, nil)
+	defer p.Shutdown(context.Background())
+
+	emitLog(t, p, "first")
+	<-exp.started
+	emitLog(t, p, "queued")
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(exp.release)
+	}()
+	start := time.Now()
+	emitLog(t, p, "waited")
+	if time.Since(start) < 10*time.Millisecond {
+		t.Error("OnEmit returned before the queue had room")
+	}
+	if err := p.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := exp.exported(); !slices.Equal(got, []string{"first", "queued", "waited"}) {
+		t.Errorf("exported %v, want every record", got)
+	}
+	if got := p.Dropped(); got != 0 {
+		t.Errorf("Dropped() = %d, want 0", got)
+	}
+}
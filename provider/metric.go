@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"time"
 
@@ -11,57 +12,155 @@ import (
 	otlpmetrichttp "go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
+	"google.golang.org/grpc"
 )
 
+// MetricProviderOption customizes NewMetricProvider.
+type MetricProviderOption func(*metricProviderOptions)
+
+type metricProviderOptions struct {
+	exportStats *ExportStats
+	self        *SelfTelemetry
+	health      *ExporterHealth
+	reconnector *Reconnector
+	dialOptions []grpc.DialOption
+	temporality metric.TemporalitySelector
+	aggregation metric.AggregationSelector
+}
+
+// WithMetricGRPCDialOptions passes extra grpc.DialOptions (custom dialers,
+// keepalive, load balancing) to the gRPC metric exporter.
+func WithMetricGRPCDialOptions(opts ...grpc.DialOption) MetricProviderOption {
+	return func(o *metricProviderOptions) {
+		o.dialOptions = opts
+	}
+}
+
+// WithMetricTemporality sets the exporter's temporality selector, overriding
+// Metrics.TemporalityPreference.
+func WithMetricTemporality(selector metric.TemporalitySelector) MetricProviderOption {
+	return func(o *metricProviderOptions) {
+		o.temporality = selector
+	}
+}
+
+// WithMetricAggregation sets the exporter's default aggregation per
+// instrument kind. Views configured by the agent still take precedence.
+func WithMetricAggregation(selector metric.AggregationSelector) MetricProviderOption {
+	return func(o *metricProviderOptions) {
+		o.aggregation = selector
+	}
+}
+
 // NewMetricProvider creates a MeterProvider with OTLP exporter.
-func NewMetricProvider(cfg *config.Config, res *resource.Resource, log logger.Logger) (*metric.MeterProvider, error) {
+func NewMetricProvider(cfg *config.Config, res *resource.Resource, log logger.Logger, opts ...MetricProviderOption) (*metric.MeterProvider, error) {
 	ctx := context.Background()
 
-	exporter, err := createMetricExporter(ctx, cfg, log)
+	var o metricProviderOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.temporality == nil {
+		o.temporality = TemporalitySelector(cfg.Metrics.TemporalityPreference)
+	}
+
+	res, err := SignalResource(res, cfg, SignalMetrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build metrics resource: %w", err)
+	}
+
+	exporter, err := createMetricExporter(ctx, cfg, log, &o)
 	if err != nil {
 		return nil, err
 	}
+	if o.reconnector != nil {
+		swap := newSwappable(exporter, func() (metric.Exporter, error) {
+			return createMetricExporter(ctx, cfg, log, &o)
+		})
+		exporter = reconnectingMetricExporter{swap}
+		o.reconnector.register(SignalMetrics, swap.reconnect)
+	}
+	exporter = &cardinalityMetricExporter{Exporter: exporter, maxLength: cfg.Metrics.Cardinality.MaxAttributeLength, log: log}
+	if o.exportStats != nil {
+		exporter = statsMetricExporter{Exporter: exporter, stats: o.exportStats}
+	}
+	if o.health != nil {
+		exporter = healthMetricExporter{Exporter: exporter, health: o.health}
+	}
+	if o.self != nil {
+		exporter = selfTelemetryMetricExporter{Exporter: exporter, st: o.self}
+	}
 
-	opts := []metric.Option{
+	mpOpts := []metric.Option{
 		metric.WithReader(metric.NewPeriodicReader(exporter,
 			metric.WithInterval(cfg.Metrics.DefaultInterval),
 		)),
 		metric.WithResource(res),
 	}
+	if limit := cfg.Metrics.Cardinality.MaxSeriesPerInstrument; limit > 0 {
+		mpOpts = append(mpOpts, metric.WithCardinalityLimit(limit))
+	}
+	if views := metricViews(cfg); len(views) > 0 {
+		mpOpts = append(mpOpts, metric.WithView(views...))
+	}
 
-	return metric.NewMeterProvider(opts...), nil
+	return metric.NewMeterProvider(mpOpts...), nil
 }
 
-func createMetricExporter(ctx context.Context, cfg *config.Config, log logger.Logger) (metric.Exporter, error) {
-	protocol := cfg.ExporterProtocol
+func createMetricExporter(ctx context.Context, cfg *config.Config, log logger.Logger, o *metricProviderOptions) (metric.Exporter, error) {
+	if cfg.Features.DryRun {
+		return dryRunMetricExporter{log: log, temporality: o.temporality, aggregation: o.aggregation}, nil
+	}
+	protocol := cfg.SignalExporter(SignalMetrics).Protocol
 	if protocol == "" {
 		protocol = "grpc"
 	}
 
 	switch protocol {
 	case "grpc":
-		return createGRPCMetricExporter(ctx, cfg, log)
+		return createGRPCMetricExporter(ctx, cfg, log, o)
 	case "http", "http/protobuf":
-		return createHTTPMetricExporter(ctx, cfg, log)
+		return createHTTPMetricExporter(ctx, cfg, log, o)
+	case ProtocolStdout:
+		return createStdoutMetricExporter(ctx, cfg, log, o)
 	default:
-		return nil, fmt.Errorf("unsupported OTLP protocol: %s (use 'grpc' or 'http')", protocol)
+		if factory, ok := lookupFactory(exporterFactories.metrics, protocol); ok {
+			return factory(ctx, exporterSettings(cfg, SignalMetrics, log))
+		}
+		return nil, fmt.Errorf("unsupported OTLP protocol: %s (use 'grpc', 'http', 'stdout' or a registered exporter factory)", protocol)
 	}
 }
 
-func createGRPCMetricExporter(ctx context.Context, cfg *config.Config, log logger.Logger) (metric.Exporter, error) {
+func createGRPCMetricExporter(ctx context.Context, cfg *config.Config, log logger.Logger, o *metricProviderOptions) (metric.Exporter, error) {
+	exp := cfg.SignalExporter(SignalMetrics)
+	host, _ := splitEndpoint(exp.Endpoint)
 	opts := []otlpmetricgrpc.Option{
-		otlpmetricgrpc.WithEndpoint(cfg.Endpoint),
+		otlpmetricgrpc.WithEndpoint(host),
 		otlpmetricgrpc.WithTimeout(cfg.Timeout),
+		otlpmetricgrpc.WithTemporalitySelector(o.temporality),
+	}
+	if o.aggregation != nil {
+		opts = append(opts, otlpmetricgrpc.WithAggregationSelector(o.aggregation))
 	}
 
 	if cfg.Insecure {
 		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	} else {
+		creds, err := buildTLSCredentials(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(creds))
+	}
+	compression, err := NormalizeCompression("grpc", cfg.Compression)
+	if err != nil {
+		return nil, err
 	}
-	if cfg.Compression != "" && cfg.Compression != "none" {
-		opts = append(opts, otlpmetricgrpc.WithCompressor(cfg.Compression))
+	if compression != CompressionNone {
+		opts = append(opts, otlpmetricgrpc.WithCompressor(compression))
 	}
 
-	headers := cfg.ResolvedAuthHeaders()
+	headers := exp.Headers
 	if len(headers) > 0 {
 		opts = append(opts, otlpmetricgrpc.WithHeaders(headers))
 	}
@@ -75,32 +174,59 @@ func createGRPCMetricExporter(ctx context.Context, cfg *config.Config, log logge
 		}))
 	}
 
+	if len(o.dialOptions) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithDialOption(o.dialOptions...))
+	}
+
 	exporter, err := otlpmetricgrpc.New(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create OTLP gRPC metric exporter: %w", err)
 	}
 
 	log.Info(ctx, "OTLP metric exporter initialized", logger.Fields{
-		"protocol": "grpc", "endpoint": cfg.Endpoint,
+		"protocol": "grpc", "endpoint": exp.Endpoint,
 	})
 
 	return exporter, nil
 }
 
-func createHTTPMetricExporter(ctx context.Context, cfg *config.Config, log logger.Logger) (metric.Exporter, error) {
+func createHTTPMetricExporter(ctx context.Context, cfg *config.Config, log logger.Logger, o *metricProviderOptions) (metric.Exporter, error) {
+	exp := cfg.SignalExporter(SignalMetrics)
+	host, path := splitEndpoint(exp.Endpoint)
 	opts := []otlpmetrichttp.Option{
-		otlpmetrichttp.WithEndpoint(cfg.Endpoint),
+		otlpmetrichttp.WithEndpoint(host),
 		otlpmetrichttp.WithTimeout(cfg.Timeout),
+		otlpmetrichttp.WithTemporalitySelector(o.temporality),
+	}
+	if o.aggregation != nil {
+		opts = append(opts, otlpmetrichttp.WithAggregationSelector(o.aggregation))
+	}
+	if path != "" {
+		opts = append(opts, otlpmetrichttp.WithURLPath(path))
 	}
 
+	var tlsCfg *tls.Config
 	if cfg.Insecure {
 		opts = append(opts, otlpmetrichttp.WithInsecure())
+	} else {
+		var err error
+		if tlsCfg, err = BuildTLSConfig(cfg.TLS); err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsCfg))
+	}
+	compression, err := NormalizeCompression("http", cfg.Compression)
+	if err != nil {
+		return nil, err
 	}
-	if cfg.Compression != "" && cfg.Compression != "none" {
+	switch compression {
+	case CompressionGzip:
 		opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+	case CompressionZstd:
+		opts = append(opts, otlpmetrichttp.WithHTTPClient(newZstdHTTPClient(cfg.Timeout, tlsCfg)))
 	}
 
-	headers := cfg.ResolvedAuthHeaders()
+	headers := exp.Headers
 	if len(headers) > 0 {
 		opts = append(opts, otlpmetrichttp.WithHeaders(headers))
 	}
@@ -120,7 +246,7 @@ func createHTTPMetricExporter(ctx context.Context, cfg *config.Config, log logge
 	}
 
 	log.Info(ctx, "OTLP metric exporter initialized", logger.Fields{
-		"protocol": "http", "endpoint": cfg.Endpoint,
+		"protocol": "http", "endpoint": exp.Endpoint,
 	})
 
 	return exporter, nil
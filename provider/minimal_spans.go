@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MinimalSpanMetric is the histogram fed by minimal span mode.
+const MinimalSpanMetric = "otel_agent.request.duration"
+
+// NewMinimalSpanSampler wraps next so that entry spans (server and consumer
+// spans without a local parent) it would drop are still recorded, but not
+// sampled. They never reach the exporter, yet span processors see them end,
+// which lets NewMinimalSpanProcessor measure every request. Their children
+// keep following the parent decision, so unsampled requests stay cheap.
+func NewMinimalSpanSampler(next sdktrace.Sampler) sdktrace.Sampler {
+	return minimalSpanSampler{next: next}
+}
+
+type minimalSpanSampler struct {
+	next sdktrace.Sampler
+}
+
+func (s minimalSpanSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	res := s.next.ShouldSample(p)
+	if res.Decision == sdktrace.Drop && isEntrySpan(p.Kind, trace.SpanContextFromContext(p.ParentContext)) {
+		res.Decision = sdktrace.RecordOnly
+	}
+	return res
+}
+
+func (s minimalSpanSampler) Description() string {
+	return "MinimalSpans{" + s.next.Description() + "}"
+}
+
+// isEntrySpan reports whether a span of the given kind and parent is where a
+// request enters the service.
+func isEntrySpan(kind trace.SpanKind, parent trace.SpanContext) bool {
+	if kind != trace.SpanKindServer && kind != trace.SpanKindConsumer {
+		return false
+	}
+	return !parent.IsValid() || parent.IsRemote()
+}
+
+// NewMinimalSpanProcessor records the route, status and duration of every
+// entry span, sampled or not, in the MinimalSpanMetric histogram of meter.
+// Sampled spans become exemplars, so SLO dashboards get 100% coverage and
+// still link to the traces that were kept.
+func NewMinimalSpanProcessor(meter metric.Meter) sdktrace.SpanProcessor {
+	duration, err := meter.Float64Histogram(MinimalSpanMetric,
+		metric.WithDescription("Duration of every request entering the service, sampled or not"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		duration, _ = noop.NewMeterProvider().Meter("").Float64Histogram(MinimalSpanMetric)
+	}
+	return &minimalSpanProcessor{duration: duration}
+}
+
+type minimalSpanProcessor struct {
+	duration metric.Float64Histogram
+}
+
+func (p *minimalSpanProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (p *minimalSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !isEntrySpan(s.SpanKind(), s.Parent()) {
+		return
+	}
+
+	route := s.Name()
+	attrs := make([]attribute.KeyValue, 0, 4)
+	for _, kv := range s.Attributes() {
+		switch kv.Key {
+		case "http.route":
+			route = kv.Value.AsString()
+		case "http.response.status_code", "rpc.grpc.status_code":
+			attrs = append(attrs, kv)
+		}
+	}
+	attrs = append(attrs,
+		attribute.String("route", route),
+		attribute.String("span.kind", s.SpanKind().String()),
+		attribute.String("otel.status_code", s.Status().Code.String()),
+	)
+
+	// The span context lets the SDK attach sampled spans as exemplars.
+	ctx := trace.ContextWithSpanContext(context.Background(), s.SpanContext())
+	p.duration.Record(ctx, s.EndTime().Sub(s.StartTime()).Seconds(), metric.WithAttributes(attrs...))
+}
+
+func (p *minimalSpanProcessor) Shutdown(context.Context) error { return nil }
+
+func (p *minimalSpanProcessor) ForceFlush(context.Context) error { return nil }
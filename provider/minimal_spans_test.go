@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newMinimalSpanTracer(t *testing.T, sampler sdktrace.Sampler) (trace.Tracer, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(NewMinimalSpanSampler(sampler)),
+		sdktrace.WithSyncer(exp),
+		sdktrace.WithSpanProcessor(NewMinimalSpanProcessor(mp.Meter("test"))),
+	)
+	return tp.Tracer("test"), exp, reader
+}
+
+func collectMinimalSpans(t *testing.T, reader *sdkmetric.ManualReader) []metricdata.HistogramDataPoint[float64] {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == MinimalSpanMetric {
+				return m.Data.(metricdata.Histogram[float64]).DataPoints
+			}
+		}
+	}
+	return nil
+}
+
+func TestMinimalSpans_MeasuresUnsampledRequests(t *testing.T) {
+	tracer, exp, reader := newMinimalSpanTracer(t, sdktrace.ParentBased(sdktrace.NeverSample()))
+
+	for range 3 {
+		ctx, span := tracer.Start(context.Background(), "GET /users/42",
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.route", "/users/:id"),
+				attribute.Int("http.response.status_code", 200),
+			))
+		_, child := tracer.Start(ctx, "db.query")
+		if child.IsRecording() {
+			t.Error("child of an unsampled request should not be recorded")
+		}
+		child.End()
+		span.End()
+	}
+
+	if got := len(exp.GetSpans()); got != 0 {
+		t.Errorf("exported %d spans, want none at a zero sampling rate", got)
+	}
+	points := collectMinimalSpans(t, reader)
+	if len(points) != 1 || points[0].Count != 3 {
+		t.Fatalf("data points = %+v, want one series with 3 requests", points)
+	}
+	if route, _ := points[0].Attributes.Value("route"); route.AsString() != "/users/:id" {
+		t.Errorf("route = %q, want the http.route attribute", route.AsString())
+	}
+	if status, _ := points[0].Attributes.Value("http.response.status_code"); status.AsInt64() != 200 {
+		t.Errorf("status = %v, want 200", status)
+	}
+}
+
+func TestMinimalSpans_SampledRequestsAreExportedAndMeasured(t *testing.T) {
+	tracer, exp, reader := newMinimalSpanTracer(t, sdktrace.AlwaysSample())
+
+	_, span := tracer.Start(context.Background(), "orders.consume", trace.WithSpanKind(trace.SpanKindConsumer))
+	span.End()
+	// Internal spans are not requests and are not measured.
+	_, internal := tracer.Start(context.Background(), "cron")
+	internal.End()
+
+	if got := len(exp.GetSpans()); got != 2 {
+		t.Errorf("exported %d spans, want both sampled spans", got)
+	}
+	points := collectMinimalSpans(t, reader)
+	if len(points) != 1 || points[0].Count != 1 {
+		t.Fatalf("data points = %+v, want only the consumer span", points)
+	}
+	if route, _ := points[0].Attributes.Value("route"); route.AsString() != "orders.consume" {
+		t.Errorf("route = %q, want the span name", route.AsString())
+	}
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	xray "go.opentelemetry.io/contrib/propagators/aws/xray"
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	testTraceID, _ = trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	testSpanID, _  = trace.SpanIDFromHex("00f067aa0ba902b7")
+)
+
+func sampledContext() context.Context {
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    testTraceID,
+		SpanID:     testSpanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+}
+
+func TestPropagators_RoundTrip(t *testing.T) {
+	for name, header := range map[string]string{
+		"b3":      "b3",
+		"b3multi": "x-b3-traceid",
+		"jaeger":  "uber-trace-id",
+		"xray":    "X-Amzn-Trace-Id",
+	} {
+		p, err := NewPropagator([]string{name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		carrier := propagation.MapCarrier{}
+		p.Inject(sampledContext(), carrier)
+		if carrier.Get(header) == "" {
+			t.Errorf("%s: header %q not injected, got %v", name, header, carrier)
+		}
+
+		sc := trace.SpanContextFromContext(p.Extract(context.Background(), carrier))
+		if sc.TraceID() != testTraceID || sc.SpanID() != testSpanID || !sc.IsSampled() || !sc.IsRemote() {
+			t.Errorf("%s: extracted %+v from %v", name, sc, carrier)
+		}
+	}
+}
+
+func TestPropagators_ExtractLegacyHeaders(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		p       propagation.TextMapPropagator
+		headers map[string]string
+		traceID string
+		sampled bool
+	}{
+		{"b3 64-bit trace ID", b3.New(), map[string]string{
+			"b3": "a3ce929d0e0e4736-00f067aa0ba902b7-1",
+		}, "0000000000000000a3ce929d0e0e4736", true},
+		{"b3 debug", b3.New(), map[string]string{
+			"b3": "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-d-05e3ac9a4f6e3b90",
+		}, "4bf92f3577b34da6a3ce929d0e0e4736", true},
+		{"b3 multi read by single", b3.New(), map[string]string{
+			"x-b3-traceid": "4bf92f3577b34da6a3ce929d0e0e4736", "x-b3-spanid": "00f067aa0ba902b7", "x-b3-flags": "1",
+		}, "4bf92f3577b34da6a3ce929d0e0e4736", true},
+		{"jaeger unsampled short IDs", jaeger.Jaeger{}, map[string]string{
+			"uber-trace-id": "a3ce929d0e0e4736:f067aa0ba902b7:0:0",
+		}, "0000000000000000a3ce929d0e0e4736", false},
+		{"xray unsampled", xray.Propagator{}, map[string]string{
+			"X-Amzn-Trace-Id": "Root=1-4bf92f35-77b34da6a3ce929d0e0e4736;Parent=00f067aa0ba902b7;Sampled=0",
+		}, "4bf92f3577b34da6a3ce929d0e0e4736", false},
+	} {
+		sc := trace.SpanContextFromContext(tc.p.Extract(context.Background(), propagation.MapCarrier(tc.headers)))
+		if !sc.IsValid() || sc.TraceID().String() != tc.traceID || sc.IsSampled() != tc.sampled {
+			t.Errorf("%s: extracted %+v", tc.name, sc)
+		}
+	}
+}
+
+func TestPropagators_InvalidHeadersIgnored(t *testing.T) {
+	for name, tc := range map[string]struct {
+		p      propagation.TextMapPropagator
+		header string
+		value  string
+	}{
+		"b3 sampling only": {b3.New(), "b3", "1"},
+		"b3 bad sampled":   {b3.New(), "b3", "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-x"},
+		"jaeger parts":     {jaeger.Jaeger{}, "uber-trace-id", "4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7"},
+		"xray version":     {xray.Propagator{}, "X-Amzn-Trace-Id", "Root=2-4bf92f35-77b34da6a3ce929d0e0e4736;Parent=00f067aa0ba902b7"},
+		"xray no parent":   {xray.Propagator{}, "X-Amzn-Trace-Id", "Root=1-4bf92f35-77b34da6a3ce929d0e0e4736"},
+	} {
+		ctx := tc.p.Extract(context.Background(), propagation.MapCarrier{tc.header: tc.value})
+		if trace.SpanContextFromContext(ctx).IsValid() {
+			t.Errorf("%s: expected no span context", name)
+		}
+	}
+}
+
+func TestNewPropagator(t *testing.T) {
+	p, err := NewPropagator([]string{"tracecontext", "baggage", "b3", "b3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	carrier := propagation.MapCarrier{}
+	p.Inject(sampledContext(), carrier)
+	if carrier.Get("traceparent") == "" || carrier.Get("b3") == "" {
+		t.Errorf("injected %v, want traceparent and b3", carrier)
+	}
+
+	p, err = NewPropagator([]string{"none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields := p.Fields(); len(fields) != 0 {
+		t.Errorf("none: fields = %v, want none", fields)
+	}
+
+	if _, err := NewPropagator([]string{"ottrace"}); err == nil {
+		t.Error("expected an error for an unknown propagator")
+	}
+}
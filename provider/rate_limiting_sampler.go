@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RateLimitingSampler samples at most a fixed number of root spans per
+// second, whatever the traffic volume. It is a token bucket that refills
+// continuously and holds up to one second of budget, so short bursts after an
+// idle period are sampled fully but sustained load is capped.
+//
+// Use it as the root of a ParentBased sampler; createSampler does this for
+// the "rate_limited" sampler type.
+type RateLimitingSampler struct {
+	limit float64
+	now   func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimitingSampler creates a sampler that allows spansPerSecond
+// sampled roots per second. A non-positive limit samples nothing.
+func NewRateLimitingSampler(spansPerSecond float64) *RateLimitingSampler {
+	limit := math.Max(spansPerSecond, 0)
+	s := &RateLimitingSampler{
+		limit:  limit,
+		now:    time.Now,
+		tokens: math.Max(limit, 1),
+	}
+	s.last = s.now()
+	return s
+}
+
+// ShouldSample implements sdktrace.Sampler.
+func (s *RateLimitingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	decision := sdktrace.Drop
+	if s.take() {
+		decision = sdktrace.RecordAndSample
+	}
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+// Description implements sdktrace.Sampler.
+func (s *RateLimitingSampler) Description() string {
+	return fmt.Sprintf("RateLimitingSampler{%g}", s.limit)
+}
+
+func (s *RateLimitingSampler) take() bool {
+	if s.limit <= 0 {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if elapsed := now.Sub(s.last).Seconds(); elapsed > 0 {
+		s.tokens = math.Min(math.Max(s.limit, 1), s.tokens+elapsed*s.limit)
+	}
+	s.last = now
+
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+	return true
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestRateLimitingSampler(limit float64) (*RateLimitingSampler, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	s := NewRateLimitingSampler(limit)
+	s.now = clock.now
+	s.last = clock.t
+	return s, clock
+}
+
+func countSampled(s sdktrace.Sampler, n int) int {
+	sampled := 0
+	for i := 0; i < n; i++ {
+		res := s.ShouldSample(sdktrace.SamplingParameters{
+			ParentContext: context.Background(),
+			TraceID:       traceIDAt(0.5),
+			Name:          "GET /orders",
+		})
+		if res.Decision == sdktrace.RecordAndSample {
+			sampled++
+		}
+	}
+	return sampled
+}
+
+func TestRateLimitingSampler_CapsSampledRootsPerSecond(t *testing.T) {
+	s, clock := newTestRateLimitingSampler(100)
+
+	total := 0
+	for w := 0; w < 5; w++ {
+		total += countSampled(s, 10000)
+		clock.advance(time.Second)
+	}
+	if total != 500 {
+		t.Errorf("sampled %d roots over 5s, want 500 at 100/s", total)
+	}
+}
+
+func TestRateLimitingSampler_RefillsContinuously(t *testing.T) {
+	s, clock := newTestRateLimitingSampler(10)
+	countSampled(s, 100) // drain the bucket
+
+	clock.advance(250 * time.Millisecond)
+	if got := countSampled(s, 100); got != 2 {
+		t.Errorf("sampled %d after 250ms at 10/s, want 2", got)
+	}
+}
+
+func TestRateLimitingSampler_FractionalLimit(t *testing.T) {
+	s, clock := newTestRateLimitingSampler(0.5)
+
+	if got := countSampled(s, 10); got != 1 {
+		t.Errorf("sampled %d initially, want 1", got)
+	}
+	clock.advance(time.Second)
+	if got := countSampled(s, 10); got != 0 {
+		t.Errorf("sampled %d after 1s at 0.5/s, want 0", got)
+	}
+	clock.advance(time.Second)
+	if got := countSampled(s, 10); got != 1 {
+		t.Errorf("sampled %d after 2s at 0.5/s, want 1", got)
+	}
+}
+
+func TestCreateSampler_RateLimitedHonorsParent(t *testing.T) {
+	sampler := createSampler(config.SamplingConfig{Type: "rate_limited", Rate: 1})
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceIDAt(0.5),
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), parent)
+	for i := 0; i < 10; i++ {
+		res := sampler.ShouldSample(sdktrace.SamplingParameters{ParentContext: ctx, TraceID: parent.TraceID()})
+		if res.Decision != sdktrace.RecordAndSample {
+			t.Fatalf("child %d of a sampled parent was dropped", i)
+		}
+	}
+	if got := countSampled(sampler, 10); got != 1 {
+		t.Errorf("sampled %d roots, want 1 at 1/s", got)
+	}
+}
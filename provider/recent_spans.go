@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// RecentSpan is a snapshot of a finished span, shaped for JSON.
+type RecentSpan struct {
+	TraceID       string            `json:"trace_id"`
+	SpanID        string            `json:"span_id"`
+	ParentSpanID  string            `json:"parent_span_id,omitempty"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind"`
+	Scope         string            `json:"scope"`
+	Start         time.Time         `json:"start"`
+	End           time.Time         `json:"end"`
+	DurationMS    float64           `json:"duration_ms"`
+	Status        string            `json:"status"`
+	StatusMessage string            `json:"status_message,omitempty"`
+	Sampled       bool              `json:"sampled"`
+	Attributes    map[string]any    `json:"attributes,omitempty"`
+	Events        []RecentSpanEvent `json:"events,omitempty"`
+}
+
+// RecentSpanEvent is a span event of a RecentSpan.
+type RecentSpanEvent struct {
+	Name       string         `json:"name"`
+	Time       time.Time      `json:"time"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// RecentSpans is a SpanProcessor that keeps the last finished spans in a
+// ring buffer, so instrumentation can be checked locally without a backend.
+// Spans that are recorded but not sampled are kept too, flagged as such.
+type RecentSpans struct {
+	mu    sync.Mutex
+	spans []RecentSpan
+	next  int
+	full  bool
+
+	// scrub applies the export-time PII rules, so the buffer never holds
+	// what the exporter would not send
+	scrub *ScrubProcessor
+}
+
+// NewRecentSpans returns a buffer of the last size finished spans.
+func NewRecentSpans(size int) *RecentSpans {
+	return &RecentSpans{spans: make([]RecentSpan, max(size, 1))}
+}
+
+// WithRecentSpans records every finished span in r.
+func WithRecentSpans(r *RecentSpans) TraceProviderOption {
+	return func(o *traceProviderOptions) {
+		o.recent = r
+	}
+}
+
+// Spans returns the buffered spans, most recently finished first.
+func (r *RecentSpans) Spans() []RecentSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := r.next
+	if r.full {
+		n = len(r.spans)
+	}
+	out := make([]RecentSpan, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, r.spans[(r.next-i+len(r.spans))%len(r.spans)])
+	}
+	return out
+}
+
+// OnStart implements sdktrace.SpanProcessor.
+func (r *RecentSpans) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+// OnEnd implements sdktrace.SpanProcessor.
+func (r *RecentSpans) OnEnd(s sdktrace.ReadOnlySpan) {
+	if r.scrub != nil {
+		s = r.scrub.scrubSpan(s)
+	}
+	snapshot := newRecentSpan(s)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans[r.next] = snapshot
+	r.next = (r.next + 1) % len(r.spans)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Shutdown implements sdktrace.SpanProcessor.
+func (r *RecentSpans) Shutdown(context.Context) error { return nil }
+
+// ForceFlush implements sdktrace.SpanProcessor.
+func (r *RecentSpans) ForceFlush(context.Context) error { return nil }
+
+func newRecentSpan(s sdktrace.ReadOnlySpan) RecentSpan {
+	sc := s.SpanContext()
+	span := RecentSpan{
+		TraceID:       sc.TraceID().String(),
+		SpanID:        sc.SpanID().String(),
+		Name:          s.Name(),
+		Kind:          s.SpanKind().String(),
+		Scope:         s.InstrumentationScope().Name,
+		Start:         s.StartTime(),
+		End:           s.EndTime(),
+		DurationMS:    float64(s.EndTime().Sub(s.StartTime())) / float64(time.Millisecond),
+		Status:        s.Status().Code.String(),
+		StatusMessage: s.Status().Description,
+		Sampled:       sc.IsSampled(),
+		Attributes:    attributeMap(s.Attributes()),
+	}
+	if parent := s.Parent(); parent.IsValid() {
+		span.ParentSpanID = parent.SpanID().String()
+	}
+	for _, ev := range s.Events() {
+		span.Events = append(span.Events, RecentSpanEvent{
+			Name:       ev.Name,
+			Time:       ev.Time,
+			Attributes: attributeMap(ev.Attributes),
+		})
+	}
+	return span
+}
+
+func attributeMap(attrs []attribute.KeyValue) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(attrs))
+	for _, kv := range attrs {
+		m[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return m
+}
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRecentSpans_KeepsLastSpansNewestFirst(t *testing.T) {
+	recent := NewRecentSpans(2)
+	if got := recent.Spans(); len(got) != 0 {
+		t.Fatalf("Spans() = %v, want none", got)
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recent))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	for _, name := range []string{"a", "b", "c"} {
+		_, span := tp.Tracer("test").Start(ctx, name, trace.WithAttributes(attribute.String("step", name)))
+		span.AddEvent("done")
+		span.End()
+	}
+
+	got := recent.Spans()
+	if len(got) != 2 || got[0].Name != "c" || got[1].Name != "b" {
+		t.Fatalf("Spans() = %+v, want c then b", got)
+	}
+	c := got[0]
+	if c.ParentSpanID != parent.SpanContext().SpanID().String() || c.TraceID != parent.SpanContext().TraceID().String() {
+		t.Errorf("c parent/trace = %s/%s, want the parent span", c.ParentSpanID, c.TraceID)
+	}
+	if c.Attributes["step"] != "c" || len(c.Events) != 1 || c.Events[0].Name != "done" {
+		t.Errorf("c attributes/events = %v/%v", c.Attributes, c.Events)
+	}
+	if c.Kind != "internal" || c.Status != "Unset" || !c.Sampled {
+		t.Errorf("c kind/status/sampled = %s/%s/%v", c.Kind, c.Status, c.Sampled)
+	}
+	parent.End()
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Reconnector replaces the exporters of the providers it was passed to with
+// freshly created ones, which opens new collector connections. It recovers an
+// exporter whose connection is wedged without restarting the process.
+type Reconnector struct {
+	mu      sync.Mutex
+	signals map[string]func(context.Context) error
+}
+
+// NewReconnector creates a Reconnector with no exporters registered.
+func NewReconnector() *Reconnector {
+	return &Reconnector{signals: make(map[string]func(context.Context) error)}
+}
+
+func (r *Reconnector) register(signal string, reconnect func(context.Context) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.signals[signal] = reconnect
+}
+
+// Reconnect recreates the exporter of every registered signal and shuts the
+// old one down. Exports in flight on an old exporter may fail. A signal whose
+// new exporter cannot be created keeps its current one.
+func (r *Reconnector) Reconnect(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	signals := make([]string, 0, len(r.signals))
+	for signal := range r.signals {
+		signals = append(signals, signal)
+	}
+	sort.Strings(signals)
+
+	var errs []error
+	for _, signal := range signals {
+		if err := r.signals[signal](ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", signal, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithTraceReconnector lets r recreate the trace exporter.
+func WithTraceReconnector(r *Reconnector) TraceProviderOption {
+	return func(o *traceProviderOptions) {
+		o.reconnector = r
+	}
+}
+
+// WithMetricReconnector lets r recreate the metric exporter.
+func WithMetricReconnector(r *Reconnector) MetricProviderOption {
+	return func(o *metricProviderOptions) {
+		o.reconnector = r
+	}
+}
+
+// WithLogReconnector lets r recreate the log exporter.
+func WithLogReconnector(r *Reconnector) LogProviderOption {
+	return func(o *logProviderOptions) {
+		o.reconnector = r
+	}
+}
+
+// swappable holds the current exporter and swaps in a new one on reconnect.
+type swappable[E interface{ Shutdown(context.Context) error }] struct {
+	current atomic.Pointer[E]
+	create  func() (E, error)
+}
+
+func newSwappable[E interface{ Shutdown(context.Context) error }](exp E, create func() (E, error)) *swappable[E] {
+	s := &swappable[E]{create: create}
+	s.current.Store(&exp)
+	return s
+}
+
+func (s *swappable[E]) get() E {
+	return *s.current.Load()
+}
+
+func (s *swappable[E]) reconnect(ctx context.Context) error {
+	exp, err := s.create()
+	if err != nil {
+		return err
+	}
+	old := s.current.Swap(&exp)
+	return (*old).Shutdown(ctx)
+}
+
+type reconnectingSpanExporter struct {
+	*swappable[sdktrace.SpanExporter]
+}
+
+func (e reconnectingSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	return e.get().ExportSpans(ctx, spans)
+}
+
+func (e reconnectingSpanExporter) Shutdown(ctx context.Context) error {
+	return e.get().Shutdown(ctx)
+}
+
+type reconnectingMetricExporter struct {
+	*swappable[metric.Exporter]
+}
+
+func (e reconnectingMetricExporter) Temporality(k metric.InstrumentKind) metricdata.Temporality {
+	return e.get().Temporality(k)
+}
+
+func (e reconnectingMetricExporter) Aggregation(k metric.InstrumentKind) metric.Aggregation {
+	return e.get().Aggregation(k)
+}
+
+func (e reconnectingMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	return e.get().Export(ctx, rm)
+}
+
+func (e reconnectingMetricExporter) ForceFlush(ctx context.Context) error {
+	return e.get().ForceFlush(ctx)
+}
+
+func (e reconnectingMetricExporter) Shutdown(ctx context.Context) error {
+	return e.get().Shutdown(ctx)
+}
+
+type reconnectingLogExporter struct {
+	*swappable[log.Exporter]
+}
+
+func (e reconnectingLogExporter) Export(ctx context.Context, records []log.Record) error {
+	return e.get().Export(ctx, records)
+}
+
+func (e reconnectingLogExporter) ForceFlush(ctx context.Context) error {
+	return e.get().ForceFlush(ctx)
+}
+
+func (e reconnectingLogExporter) Shutdown(ctx context.Context) error {
+	return e.get().Shutdown(ctx)
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestReconnector_SwapsExporters(t *testing.T) {
+	first, second := tracetest.NewInMemoryExporter(), tracetest.NewInMemoryExporter()
+	next := []sdktrace.SpanExporter{second}
+	swap := newSwappable[sdktrace.SpanExporter](first, func() (sdktrace.SpanExporter, error) {
+		if len(next) == 0 {
+			return nil, errors.New("collector unreachable")
+		}
+		e := next[0]
+		next = next[1:]
+		return e, nil
+	})
+	exp := reconnectingSpanExporter{swap}
+	r := NewReconnector()
+	r.register(SignalTraces, swap.reconnect)
+
+	spans := tracetest.SpanStubs{{Name: "a"}}.Snapshots()
+	_ = exp.ExportSpans(context.Background(), spans)
+	if len(first.GetSpans()) != 1 {
+		t.Fatalf("first exporter got %d spans, want 1", len(first.GetSpans()))
+	}
+	if err := r.Reconnect(context.Background()); err != nil {
+		t.Fatalf("Reconnect: %v", err)
+	}
+	_ = exp.ExportSpans(context.Background(), spans)
+
+	// Shutting the old exporter down clears the in-memory exporter.
+	if len(first.GetSpans()) != 0 || len(second.GetSpans()) != 1 {
+		t.Errorf("first has %d spans, second %d; want the old one shut down and the new one used",
+			len(first.GetSpans()), len(second.GetSpans()))
+	}
+
+	if err := r.Reconnect(context.Background()); err == nil {
+		t.Error("expected the creation error")
+	}
+	_ = exp.ExportSpans(context.Background(), spans)
+	if len(second.GetSpans()) != 2 {
+		t.Error("a failed reconnect should keep the current exporter")
+	}
+}
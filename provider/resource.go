@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"runtime"
 
 	"github.com/RodolfoBonis/go-otel-agent/config"
@@ -10,14 +11,59 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
+// ResourceOption customizes BuildResource.
+type ResourceOption func(*resourceOptions)
+
+type resourceOptions struct {
+	attrs     []attribute.KeyValue
+	detectors []resource.Detector
+}
+
+// WithResourceAttributes adds attributes known only at runtime, such as the
+// build SHA or region, to the resource. Calls accumulate.
+func WithResourceAttributes(attrs ...attribute.KeyValue) ResourceOption {
+	return func(o *resourceOptions) {
+		o.attrs = append(o.attrs, attrs...)
+	}
+}
+
+// WithResourceDetectors runs detectors, e.g. the EC2, ECS or GCP detectors
+// from opentelemetry-go-contrib, and adds what they find to the resource.
+// Calls accumulate; later detectors win on conflicting keys.
+func WithResourceDetectors(detectors ...resource.Detector) ResourceOption {
+	return func(o *resourceOptions) {
+		o.detectors = append(o.detectors, detectors...)
+	}
+}
+
 // BuildResource creates an OTel Resource from the agent config.
-func BuildResource(cfg *config.Config) (*resource.Resource, error) {
+//
+// Attributes from the config (service identity, OTEL_RESOURCE_ATTRIBUTES,
+// Kubernetes and container attributes) win over WithResourceAttributes,
+// which win over WithResourceDetectors and, before those, the cloud
+// detectors of OTEL_RESOURCE_DETECTORS. A failing detector does not fail the
+// build: the resource is returned with what was detected, along with the
+// error.
+func BuildResource(cfg *config.Config, opts ...ResourceOption) (*resource.Resource, error) {
+	var o resourceOptions
+	if cloud := newCloudDetector(cfg); cloud != nil {
+		o.detectors = append(o.detectors, cloud)
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	instanceID, err := ResolveInstanceID(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	attrs := []attribute.KeyValue{
 		semconv.ServiceName(cfg.ServiceName),
 		semconv.ServiceVersion(cfg.Version),
 		attribute.String("service.namespace", cfg.Namespace),
 		attribute.String("environment", cfg.Environment),
-		attribute.String("service.instance.id", cfg.Resource.ServiceInstance),
+		attribute.String("service.instance.id", instanceID),
 	}
 
 	if cfg.Resource.DeploymentEnvironment != "" {
@@ -28,6 +74,9 @@ func BuildResource(cfg *config.Config) (*resource.Resource, error) {
 	if cfg.Resource.K8sPodName != "" {
 		attrs = append(attrs, semconv.K8SPodName(cfg.Resource.K8sPodName))
 	}
+	if cfg.Resource.K8sPodUID != "" {
+		attrs = append(attrs, semconv.K8SPodUID(cfg.Resource.K8sPodUID))
+	}
 	if cfg.Resource.K8sPodIP != "" {
 		attrs = append(attrs, attribute.String("k8s.pod.ip", cfg.Resource.K8sPodIP))
 	}
@@ -61,11 +110,48 @@ func BuildResource(cfg *config.Config) (*resource.Resource, error) {
 		semconv.ProcessRuntimeDescription("Go runtime"),
 	)
 
-	return resource.New(context.Background(),
+	ctx := context.Background()
+	res, err := resource.New(ctx,
 		resource.WithAttributes(attrs...),
 		resource.WithTelemetrySDK(),
 		resource.WithHost(),
 		resource.WithProcess(),
 		resource.WithOS(),
 	)
+	if err != nil || (len(o.detectors) == 0 && len(o.attrs) == 0) {
+		return res, err
+	}
+
+	// Detected resources carry their own semconv schema URL; a conflicting
+	// one only drops the schema URL, so the merge error is reported too.
+	extra, detectErr := resource.New(ctx,
+		resource.WithDetectors(o.detectors...),
+		resource.WithAttributes(o.attrs...),
+	)
+	merged, mergeErr := resource.Merge(extra, res)
+	return merged, errors.Join(detectErr, mergeErr)
+}
+
+// SignalResource returns base extended with the resource attributes
+// configured for signal alone (see config.PerSignalAttributes). It returns
+// base unchanged when there are none.
+func SignalResource(base *resource.Resource, cfg *config.Config, signal string) (*resource.Resource, error) {
+	var extra map[string]string
+	switch signal {
+	case SignalTraces:
+		extra = cfg.Resource.PerSignalAttributes.Traces
+	case SignalMetrics:
+		extra = cfg.Resource.PerSignalAttributes.Metrics
+	case SignalLogs:
+		extra = cfg.Resource.PerSignalAttributes.Logs
+	}
+	if len(extra) == 0 {
+		return base, nil
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(extra))
+	for key, value := range extra {
+		attrs = append(attrs, attribute.String(key, value))
+	}
+	return resource.Merge(base, resource.NewSchemaless(attrs...))
 }
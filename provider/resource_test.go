@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestSignalResource_AddsAttributesToOneSignal(t *testing.T) {
+	base := resource.NewSchemaless(
+		attribute.String("service.name", "api"),
+		attribute.String("team", "shared"),
+	)
+	cfg := &config.Config{Resource: config.ResourceConfig{
+		PerSignalAttributes: config.PerSignalAttributes{
+			Logs: map[string]string{"log.source": "app", "team": "logs"},
+		},
+	}}
+
+	logs, err := SignalResource(base, cfg, SignalLogs)
+	if err != nil {
+		t.Fatalf("SignalResource: %v", err)
+	}
+	set := logs.Set()
+	if v, _ := set.Value("log.source"); v.AsString() != "app" {
+		t.Errorf("log.source = %q, want app", v.AsString())
+	}
+	if v, _ := set.Value("team"); v.AsString() != "logs" {
+		t.Errorf("team = %q, want the per-signal value to win", v.AsString())
+	}
+	if v, _ := set.Value("service.name"); v.AsString() != "api" {
+		t.Errorf("service.name = %q, want the shared attribute kept", v.AsString())
+	}
+
+	metrics, err := SignalResource(base, cfg, SignalMetrics)
+	if err != nil {
+		t.Fatalf("SignalResource: %v", err)
+	}
+	if metrics != base {
+		t.Error("expected the shared resource for a signal without extra attributes")
+	}
+}
+
+func TestBuildResource_AttributesAndDetectors(t *testing.T) {
+	cfg := &config.Config{
+		ServiceName: "api",
+		Resource: config.ResourceConfig{
+			ServiceInstance:  "api-1",
+			CustomAttributes: map[string]string{"region": "from-env"},
+		},
+	}
+	cloud := resource.StringDetector("", "cloud.provider", func() (string, error) { return "aws", nil })
+	failing := resource.StringDetector("", "cloud.region", func() (string, error) { return "", errors.New("no metadata endpoint") })
+	clash := resource.StringDetector("", "service.name", func() (string, error) { return "detected", nil })
+
+	res, err := BuildResource(cfg,
+		WithResourceDetectors(cloud, failing, clash),
+		WithResourceAttributes(attribute.String("build.sha", "abc123"), attribute.String("region", "from-code")),
+	)
+	if res == nil {
+		t.Fatalf("BuildResource: %v", err)
+	}
+	if err == nil {
+		t.Error("expected the failing detector to be reported")
+	}
+
+	set := res.Set()
+	for key, want := range map[attribute.Key]string{
+		"cloud.provider": "aws",
+		"build.sha":      "abc123",
+		"region":         "from-env",
+		"service.name":   "api",
+	} {
+		if v, _ := set.Value(key); v.AsString() != want {
+			t.Errorf("%s = %q, want %q", key, v.AsString(), want)
+		}
+	}
+	if _, ok := set.Value("cloud.region"); ok {
+		t.Error("cloud.region set although its detector failed")
+	}
+}
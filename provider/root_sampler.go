@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type rootSamplerKey struct{}
+
+// ContextWithRootSampler returns a copy of ctx in which root spans are
+// sampled by s instead of the configured sampler, so an instrumentation can
+// sample a noisy route at a lower rate (or a critical one at a higher rate)
+// than the rest of the service. Spans with a parent keep following it, and
+// the samplers layered on top of the configured one (tenant quotas, minimal
+// spans) still apply.
+func ContextWithRootSampler(ctx context.Context, s sdktrace.Sampler) context.Context {
+	return context.WithValue(ctx, rootSamplerKey{}, s)
+}
+
+// rootSamplerOverride samples root spans with the sampler of their context,
+// when there is one, and everything else with next.
+type rootSamplerOverride struct {
+	next sdktrace.Sampler
+}
+
+func (s rootSamplerOverride) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if override, ok := p.ParentContext.Value(rootSamplerKey{}).(sdktrace.Sampler); ok &&
+		!trace.SpanContextFromContext(p.ParentContext).IsValid() {
+		return override.ShouldSample(p)
+	}
+	return s.next.ShouldSample(p)
+}
+
+func (s rootSamplerOverride) Description() string {
+	return s.next.Description()
+}
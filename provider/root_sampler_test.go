@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRootSamplerOverride(t *testing.T) {
+	s := rootSamplerOverride{next: sdktrace.ParentBased(sdktrace.AlwaysSample())}
+	root := ContextWithRootSampler(context.Background(), sdktrace.NeverSample())
+
+	if d := s.ShouldSample(sdktrace.SamplingParameters{ParentContext: root, TraceID: trace.TraceID{1}}).Decision; d != sdktrace.Drop {
+		t.Errorf("root span with an override: decision %v, want Drop", d)
+	}
+	if d := s.ShouldSample(sdktrace.SamplingParameters{ParentContext: context.Background(), TraceID: trace.TraceID{1}}).Decision; d != sdktrace.RecordAndSample {
+		t.Errorf("root span without an override: decision %v, want RecordAndSample", d)
+	}
+
+	parent := trace.ContextWithSpanContext(root, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}, TraceFlags: trace.FlagsSampled,
+	}))
+	if d := s.ShouldSample(sdktrace.SamplingParameters{ParentContext: parent, TraceID: trace.TraceID{1}}).Decision; d != sdktrace.RecordAndSample {
+		t.Errorf("child of a sampled span: decision %v, want RecordAndSample", d)
+	}
+}
@@ -2,104 +2,172 @@ package provider
 
 import (
 	"context"
-	"regexp"
-	"strings"
-	"sync"
+	"slices"
 
 	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/RodolfoBonis/go-otel-agent/scrub"
 	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/log"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
-// ScrubProcessor is a SpanProcessor that redacts PII from span attributes
-// before they are exported. Applied at attribute-setting level since
-// ReadOnlySpan is immutable after span end.
+// ScrubProcessor redacts PII from span attributes. As a SpanProcessor it
+// only scrubs the attributes a span starts with; the agent instead wraps its
+// exporter with NewScrubSpanExporter, which scrubs finished spans and their
+// events as they are exported. Keys and values are matched by a
+// scrub.Scrubber, so spans follow the same rules as explicit scrub calls.
 type ScrubProcessor struct {
-	config           config.ScrubConfig
-	sensitiveKeys    map[string]struct{}
-	compiledPatterns []*regexp.Regexp
-	once             sync.Once
+	config   config.ScrubConfig
+	scrubber *scrub.Scrubber
+	self     *SelfTelemetry
 }
 
 // NewScrubProcessor creates a new PII scrubbing span processor.
 func NewScrubProcessor(cfg config.ScrubConfig) *ScrubProcessor {
-	sp := &ScrubProcessor{
-		config:        cfg,
-		sensitiveKeys: make(map[string]struct{}),
-	}
-	sp.init()
-	return sp
+	return &ScrubProcessor{config: cfg, scrubber: scrub.New(cfg)}
 }
 
-func (sp *ScrubProcessor) init() {
-	sp.once.Do(func() {
-		for _, key := range sp.config.SensitiveKeys {
-			sp.sensitiveKeys[key] = struct{}{}
-		}
-
-		for _, pattern := range sp.config.SensitivePatterns {
-			if re, err := regexp.Compile(pattern); err == nil {
-				sp.compiledPatterns = append(sp.compiledPatterns, re)
-			}
-		}
-	})
+// InvalidPatterns returns one error per sensitive pattern that failed to
+// compile and is therefore not applied.
+func (sp *ScrubProcessor) InvalidPatterns() []error {
+	return sp.scrubber.InvalidPatterns()
 }
 
-// OnStart is called when a span starts. We scrub attributes here since
-// we can still modify the span.
+// OnStart is called when a span starts. It scrubs the attributes the span
+// starts with; attributes set later are only covered by
+// NewScrubSpanExporter, which the agent uses instead of this processor.
 func (sp *ScrubProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
 	if !sp.config.Enabled {
 		return
 	}
 
-	redacted := sp.config.RedactedValue
-	if redacted == "" {
-		redacted = "[REDACTED]"
+	if scrubbed, redactions := sp.scrubAttributes(s.Attributes()); scrubbed != nil {
+		s.SetAttributes(scrubbed...)
+		sp.self.redacted(redactions)
 	}
+}
 
-	attrs := s.Attributes()
-	var scrubbed []attribute.KeyValue
-
-	for _, attr := range attrs {
-		key := string(attr.Key)
+// scrubSpan returns s with its attributes and event attributes scrubbed, or
+// s itself when nothing needed scrubbing.
+func (sp *ScrubProcessor) scrubSpan(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	attrs, redactions := sp.scrubAttributes(s.Attributes())
 
-		if sp.isSensitive(key) {
-			scrubbed = append(scrubbed, attribute.String(key, redacted))
+	var events []sdktrace.Event
+	for i, ev := range s.Events() {
+		evAttrs, n := sp.scrubAttributes(ev.Attributes)
+		if evAttrs == nil {
+			continue
 		}
+		if events == nil {
+			events = slices.Clone(s.Events())
+		}
+		events[i].Attributes = evAttrs
+		redactions += n
 	}
 
-	if len(scrubbed) > 0 {
-		s.SetAttributes(scrubbed...)
+	if attrs == nil && events == nil {
+		return s
 	}
+	sp.self.redacted(redactions)
+	if attrs == nil {
+		attrs = s.Attributes()
+	}
+	if events == nil {
+		events = s.Events()
+	}
+	return scrubbedSpan{ReadOnlySpan: s, attrs: attrs, events: events}
+}
 
-	// DB statement truncation (separate concern from PII redaction)
-	if sp.config.DBStatementMaxLength > 0 {
-		sp.truncateDBStatements(s)
+// scrubAttributes returns a copy of attrs with every attribute scrubbed by
+// scrubAttribute, or nil when none changed, and the number of redactions.
+func (sp *ScrubProcessor) scrubAttributes(attrs []attribute.KeyValue) ([]attribute.KeyValue, int) {
+	var (
+		out        []attribute.KeyValue
+		redactions int
+	)
+	for i, attr := range attrs {
+		scrubbed, changed, redacted := sp.scrubAttribute(attr)
+		if !changed {
+			continue
+		}
+		if out == nil {
+			out = slices.Clone(attrs)
+		}
+		out[i] = scrubbed
+		if redacted {
+			redactions++
+		}
 	}
+	return out, redactions
 }
 
-// truncateDBStatements applies length truncation to DB query attributes.
-// Handles both db.statement (legacy semconv) and db.query.text (new semconv).
-func (sp *ScrubProcessor) truncateDBStatements(s sdktrace.ReadWriteSpan) {
-	dbKeys := []string{"db.statement", "db.query.text"}
-	attrs := s.Attributes()
-	var truncated []attribute.KeyValue
+// scrubAttribute redacts the value of a sensitive key, sanitizes and
+// truncates DB statements, and redacts the values the detectors find. It
+// reports whether attr changed and whether anything was redacted, as
+// opposed to only normalized.
+func (sp *ScrubProcessor) scrubAttribute(attr attribute.KeyValue) (attribute.KeyValue, bool, bool) {
+	key := string(attr.Key)
+	if sp.scrubber.IsSensitiveKey(key) {
+		return attribute.String(key, sp.scrubber.RedactedValue()), true, true
+	}
+
+	changed := false
+	// DB statement sanitization and truncation (separate concern from PII
+	// redaction). Handles both db.statement (legacy semconv) and
+	// db.query.text (new semconv).
+	if key == "db.statement" || key == "db.query.text" {
+		val := attr.Value.AsString()
+		if stmt := DBStatement(val, sp.config); stmt != val {
+			attr, changed = attribute.String(key, stmt), true
+		}
+	}
 
-	for _, attr := range attrs {
-		key := string(attr.Key)
-		for _, dbKey := range dbKeys {
-			if key == dbKey {
-				val := attr.Value.AsString()
-				if len(val) > sp.config.DBStatementMaxLength {
-					truncated = append(truncated, attribute.String(key, val[:sp.config.DBStatementMaxLength]+"..."))
-				}
+	if detected, ok := sp.redactDetected(attr); ok {
+		return detected, true, true
+	}
+	return attr, changed, false
+}
+
+// redactDetected redacts the values the detectors find in a string or
+// string slice attribute, reporting whether anything was redacted.
+func (sp *ScrubProcessor) redactDetected(attr attribute.KeyValue) (attribute.KeyValue, bool) {
+	if !sp.scrubber.HasDetectors() {
+		return attr, false
+	}
+	switch attr.Value.Type() {
+	case attribute.STRING:
+		v := attr.Value.AsString()
+		if r := sp.scrubber.RedactDetected(v); r != v {
+			return attribute.String(string(attr.Key), r), true
+		}
+	case attribute.STRINGSLICE:
+		values := attr.Value.AsStringSlice()
+		changed := false
+		for i, v := range values {
+			if r := sp.scrubber.RedactDetected(v); r != v {
+				values[i], changed = r, true
 			}
 		}
+		if changed {
+			return attribute.StringSlice(string(attr.Key), values), true
+		}
 	}
+	return attr, false
+}
 
-	if len(truncated) > 0 {
-		s.SetAttributes(truncated...)
+// DBStatement returns query as it may be recorded under cfg: with its
+// literals replaced when SQLSanitize is set, and truncated to
+// DBStatementMaxLength. Database integrations use it so statements look the
+// same whichever path records them.
+func DBStatement(query string, cfg config.ScrubConfig) string {
+	if cfg.SQLSanitize {
+		query = scrub.SQL(query, scrub.SQLDialect(cfg.SQLDialect))
+	}
+	if cfg.DBStatementMaxLength > 0 && len(query) > cfg.DBStatementMaxLength {
+		query = query[:cfg.DBStatementMaxLength] + "..."
 	}
+	return query
 }
 
 // OnEnd is called when a span ends.
@@ -111,17 +179,124 @@ func (sp *ScrubProcessor) Shutdown(_ context.Context) error { return nil }
 // ForceFlush forces a flush of the processor.
 func (sp *ScrubProcessor) ForceFlush(_ context.Context) error { return nil }
 
-func (sp *ScrubProcessor) isSensitive(key string) bool {
-	if _, ok := sp.sensitiveKeys[key]; ok {
+// NewScrubSpanExporter wraps next so that spans are scrubbed with the
+// rules of cfg as they are exported: sensitive keys and detected values are
+// redacted and DB statements sanitized and truncated, in span and event
+// attributes. Working on the finished span, it also covers attributes set
+// after the span started, such as request bodies captured by middleware.
+// The spans given to ExportSpans are not modified; scrubbed copies are
+// passed to next.
+func NewScrubSpanExporter(next sdktrace.SpanExporter, cfg config.ScrubConfig) sdktrace.SpanExporter {
+	return &scrubSpanExporter{SpanExporter: next, sp: NewScrubProcessor(cfg)}
+}
+
+type scrubSpanExporter struct {
+	sdktrace.SpanExporter
+	sp *ScrubProcessor
+}
+
+func (e *scrubSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	scrubbed := make([]sdktrace.ReadOnlySpan, len(spans))
+	for i, s := range spans {
+		scrubbed[i] = e.sp.scrubSpan(s)
+	}
+	return e.SpanExporter.ExportSpans(ctx, scrubbed)
+}
+
+// scrubbedSpan is a finished span with scrubbed attributes and events.
+type scrubbedSpan struct {
+	sdktrace.ReadOnlySpan
+	attrs  []attribute.KeyValue
+	events []sdktrace.Event
+}
+
+func (s scrubbedSpan) Attributes() []attribute.KeyValue { return s.attrs }
+func (s scrubbedSpan) Events() []sdktrace.Event         { return s.events }
+
+// NewScrubLogProcessor wraps next, typically the batch log processor, so
+// that the values cfg.Detectors find in a record's body and attributes are
+// redacted before it is exported.
+func NewScrubLogProcessor(next log.Processor, cfg config.ScrubConfig) log.Processor {
+	detectors, _ := scrub.Detectors(cfg.Detectors)
+	redacted := cfg.RedactedValue
+	if redacted == "" {
+		redacted = config.RedactedValue
+	}
+	return &scrubLogProcessor{next: next, detectors: detectors, redacted: redacted}
+}
+
+type scrubLogProcessor struct {
+	next      log.Processor
+	detectors []*scrub.Detector
+	redacted  string
+}
+
+func (p *scrubLogProcessor) OnEmit(ctx context.Context, r *log.Record) error {
+	if body, ok := p.redactValue(r.Body()); ok {
+		r.SetBody(body)
+	}
+
+	attrs := make([]otellog.KeyValue, 0, r.AttributesLen())
+	changed := false
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		if v, ok := p.redactValue(kv.Value); ok {
+			kv.Value, changed = v, true
+		}
+		attrs = append(attrs, kv)
 		return true
+	})
+	if changed {
+		r.SetAttributes(attrs...)
 	}
 
-	lowerKey := strings.ToLower(key)
-	for _, re := range sp.compiledPatterns {
-		if re.MatchString(lowerKey) {
-			return true
+	return p.next.OnEmit(ctx, r)
+}
+
+// redactValue redacts detected values in strings, at any depth of slices
+// and maps, reporting whether anything was redacted.
+func (p *scrubLogProcessor) redactValue(v otellog.Value) (otellog.Value, bool) {
+	switch v.Kind() {
+	case otellog.KindString:
+		s := v.AsString()
+		if r := scrub.RedactDetected(s, p.detectors, p.redacted); r != s {
+			return otellog.StringValue(r), true
+		}
+	case otellog.KindSlice:
+		items := v.AsSlice()
+		out := make([]otellog.Value, len(items))
+		changed := false
+		for i, item := range items {
+			var ok bool
+			out[i], ok = p.redactValue(item)
+			changed = changed || ok
+		}
+		if changed {
+			return otellog.SliceValue(out...), true
+		}
+	case otellog.KindMap:
+		kvs := v.AsMap()
+		out := make([]otellog.KeyValue, len(kvs))
+		changed := false
+		for i, kv := range kvs {
+			value, ok := p.redactValue(kv.Value)
+			out[i] = otellog.KeyValue{Key: kv.Key, Value: value}
+			changed = changed || ok
+		}
+		if changed {
+			return otellog.MapValue(out...), true
 		}
 	}
+	return v, false
+}
+
+func (p *scrubLogProcessor) Enabled(ctx context.Context, param log.EnabledParameters) bool {
+	return p.next.Enabled(ctx, param)
+}
+
+func (p *scrubLogProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
 
-	return false
+func (p *scrubLogProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
 }
@@ -5,6 +5,12 @@ import (
 	"testing"
 
 	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestNewScrubProcessor_CreatesValidProcessor(t *testing.T) {
@@ -23,17 +29,30 @@ func TestNewScrubProcessor_CreatesValidProcessor(t *testing.T) {
 	if sp == nil {
 		t.Fatal("NewScrubProcessor returned nil")
 	}
-	if len(sp.sensitiveKeys) != 2 {
-		t.Errorf("sensitiveKeys count = %d, want 2", len(sp.sensitiveKeys))
+	for _, key := range []string{"password", "secret", "auth_token", "api_key"} {
+		if !sp.scrubber.IsSensitiveKey(key) {
+			t.Errorf("%q is not sensitive", key)
+		}
+	}
+	if got := sp.scrubber.RedactedValue(); got != "[SCRUBBED]" {
+		t.Errorf("RedactedValue() = %q, want [SCRUBBED]", got)
+	}
+	if len(sp.InvalidPatterns()) != 0 {
+		t.Errorf("invalid patterns = %v, want none", sp.InvalidPatterns())
 	}
-	if _, ok := sp.sensitiveKeys["password"]; !ok {
-		t.Error("sensitiveKeys missing 'password'")
+}
+
+func TestScrubbers_ReportInvalidPatterns(t *testing.T) {
+	cfg := config.ScrubConfig{
+		Enabled:           true,
+		SensitivePatterns: []string{"(?i)token", "[unclosed", "(?P<bad"},
 	}
-	if _, ok := sp.sensitiveKeys["secret"]; !ok {
-		t.Error("sensitiveKeys missing 'secret'")
+
+	if got := len(NewScrubProcessor(cfg).InvalidPatterns()); got != 2 {
+		t.Errorf("ScrubProcessor invalid patterns = %d, want 2", got)
 	}
-	if len(sp.compiledPatterns) != 2 {
-		t.Errorf("compiledPatterns count = %d, want 2", len(sp.compiledPatterns))
+	if got := len(NewHTTPScrubber(config.HTTPConfig{}, cfg).InvalidPatterns()); got != 2 {
+		t.Errorf("HTTPScrubber invalid patterns = %d, want 2", got)
 	}
 }
 
@@ -55,9 +74,9 @@ func TestIsSensitive_ExactKeyMatch(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		got := sp.isSensitive(tt.key)
+		got := sp.scrubber.IsSensitiveKey(tt.key)
 		if got != tt.want {
-			t.Errorf("isSensitive(%q) = %v, want %v", tt.key, got, tt.want)
+			t.Errorf("IsSensitiveKey(%q) = %v, want %v", tt.key, got, tt.want)
 		}
 	}
 }
@@ -85,9 +104,9 @@ func TestIsSensitive_PatternMatch(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		got := sp.isSensitive(tt.key)
+		got := sp.scrubber.IsSensitiveKey(tt.key)
 		if got != tt.want {
-			t.Errorf("isSensitive(%q) = %v, want %v", tt.key, got, tt.want)
+			t.Errorf("IsSensitiveKey(%q) = %v, want %v", tt.key, got, tt.want)
 		}
 	}
 }
@@ -135,7 +154,165 @@ func TestNewScrubProcessor_InvalidPatternSkipped(t *testing.T) {
 	}
 	sp := NewScrubProcessor(cfg)
 
-	if len(sp.compiledPatterns) != 1 {
-		t.Errorf("compiledPatterns count = %d, want 1 (invalid pattern should be skipped)", len(sp.compiledPatterns))
+	if len(sp.InvalidPatterns()) != 1 {
+		t.Errorf("invalid patterns = %d, want 1 (invalid pattern should be skipped)", len(sp.InvalidPatterns()))
+	}
+	if !sp.scrubber.IsSensitiveKey("auth_token") {
+		t.Error("the valid pattern is not applied")
+	}
+}
+
+func TestScrubProcessor_SanitizesDBStatements(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(NewScrubProcessor(config.ScrubConfig{
+			Enabled:              true,
+			SQLSanitize:          true,
+			SQLDialect:           "postgres",
+			DBStatementMaxLength: 40,
+		})),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "SELECT", trace.WithAttributes(
+		attribute.String("db.query.text", "SELECT id FROM users WHERE email = 'a@b.com' AND token = $$abc$$"),
+		attribute.String("db.statement", "SELECT 1"),
+	))
+	span.End()
+
+	attrs := map[attribute.Key]string{}
+	for _, kv := range recorder.Ended()[0].Attributes() {
+		attrs[kv.Key] = kv.Value.AsString()
+	}
+	if got, want := attrs["db.query.text"], "SELECT id FROM users WHERE email = ? AND..."; got != want {
+		t.Errorf("db.query.text = %q, want %q", got, want)
+	}
+	if got := attrs["db.statement"]; got != "SELECT ?" {
+		t.Errorf("db.statement = %q, want SELECT ?", got)
+	}
+}
+
+func TestDBStatement_SanitizeOff(t *testing.T) {
+	query := "SELECT * FROM t WHERE id = 42"
+	if got := DBStatement(query, config.ScrubConfig{}); got != query {
+		t.Errorf("DBStatement without sanitization = %q, want the query unchanged", got)
+	}
+}
+
+func TestScrubProcessor_RedactsDetectedValues(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(NewScrubProcessor(config.ScrubConfig{
+			Enabled:   true,
+			Detectors: []string{"email", "credit_card"},
+		})),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "op", trace.WithAttributes(
+		attribute.String("customer", "jane@example.com"),
+		attribute.StringSlice("cards", []string{"4111111111111111", "n/a"}),
+		attribute.Int("amount", 42),
+	))
+	span.End()
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range recorder.Ended()[0].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if got := attrs["customer"].AsString(); got != "[REDACTED]" {
+		t.Errorf("customer = %q, want [REDACTED]", got)
+	}
+	if got := attrs["cards"].AsStringSlice(); got[0] != "[REDACTED]" || got[1] != "n/a" {
+		t.Errorf("cards = %v, want the card number redacted", got)
+	}
+	if got := attrs["amount"].AsInt64(); got != 42 {
+		t.Errorf("amount = %d, want 42", got)
+	}
+}
+
+func TestScrubSpanExporter_RedactsAttributesSetAfterStart(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(NewScrubSpanExporter(exporter, config.ScrubConfig{
+		Enabled:       true,
+		SensitiveKeys: []string{"password"},
+		Detectors:     []string{"email"},
+	})))
+
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	span.SetAttributes(
+		attribute.String("password", "hunter2"),
+		attribute.String("http.request.body", `{"note":"mail jane@example.com"}`),
+		attribute.String("route", "/login"),
+	)
+	span.AddEvent("retry", trace.WithAttributes(attribute.String("password", "hunter2")))
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(spans))
+	}
+	attrs := map[attribute.Key]string{}
+	for _, kv := range spans[0].Attributes {
+		attrs[kv.Key] = kv.Value.Emit()
+	}
+	if attrs["password"] != "[REDACTED]" {
+		t.Errorf("password = %q, want [REDACTED]", attrs["password"])
+	}
+	if want := `{"note":"mail [REDACTED]"}`; attrs["http.request.body"] != want {
+		t.Errorf("body = %q, want %q", attrs["http.request.body"], want)
+	}
+	if attrs["route"] != "/login" {
+		t.Errorf("route = %q, want /login", attrs["route"])
+	}
+	if got := spans[0].Events[0].Attributes[0].Value.AsString(); got != "[REDACTED]" {
+		t.Errorf("event password = %q, want [REDACTED]", got)
+	}
+}
+
+type capturingLogProcessor struct{ records []log.Record }
+
+func (p *capturingLogProcessor) OnEmit(_ context.Context, r *log.Record) error {
+	p.records = append(p.records, r.Clone())
+	return nil
+}
+func (p *capturingLogProcessor) Enabled(context.Context, log.EnabledParameters) bool { return true }
+func (p *capturingLogProcessor) Shutdown(context.Context) error                      { return nil }
+func (p *capturingLogProcessor) ForceFlush(context.Context) error                    { return nil }
+
+func TestScrubLogProcessor_RedactsDetectedValues(t *testing.T) {
+	next := &capturingLogProcessor{}
+	lp := log.NewLoggerProvider(log.WithProcessor(NewScrubLogProcessor(
+		next,
+		config.ScrubConfig{Enabled: true, Detectors: []string{"email", "jwt"}, RedactedValue: "***"},
+	)))
+
+	var r otellog.Record
+	r.SetBody(otellog.StringValue("login by jane@example.com"))
+	r.AddAttributes(
+		otellog.String("user", "bob"),
+		otellog.Map("auth", otellog.String("token", "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig")),
+	)
+	lp.Logger("test").Emit(context.Background(), r)
+
+	if len(next.records) != 1 {
+		t.Fatalf("forwarded %d records, want 1", len(next.records))
+	}
+	got := next.records[0]
+	if body := got.Body().AsString(); body != "login by ***" {
+		t.Errorf("body = %q, want the email redacted", body)
 	}
+	got.WalkAttributes(func(kv otellog.KeyValue) bool {
+		switch kv.Key {
+		case "user":
+			if kv.Value.AsString() != "bob" {
+				t.Errorf("user = %q, want bob", kv.Value.AsString())
+			}
+		case "auth":
+			if token := kv.Value.AsMap()[0].Value.AsString(); token != "***" {
+				t.Errorf("auth.token = %q, want ***", token)
+			}
+		}
+		return true
+	})
 }
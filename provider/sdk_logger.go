@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"github.com/go-logr/logr"
+)
+
+// Verbosity levels of the OpenTelemetry SDK's internal logger. The SDK logs
+// warnings at V(1), informational messages at V(4) and debug messages at
+// V(8); errors are always logged.
+const (
+	SDKLogErrors   = 0
+	SDKLogWarnings = 1
+	SDKLogInfo     = 4
+	SDKLogDebug    = 8
+)
+
+// NewSDKLogger returns a logr.Logger, for otel.SetLogger, that writes the
+// OpenTelemetry SDK's internal messages (exporter retries, reasons data was
+// dropped) through log, so they appear in the service's normal logs.
+// Messages above verbosity are discarded; a negative verbosity discards
+// everything. Warnings are logged at Warning level, info at Info and debug
+// at Debug.
+func NewSDKLogger(log logger.Logger, verbosity int) logr.Logger {
+	if log == nil || verbosity < 0 {
+		return logr.Discard()
+	}
+	return logr.New(&sdkLogSink{logger: log, verbosity: verbosity})
+}
+
+// sdkLogSink is a logr.LogSink writing to a logger.Logger.
+type sdkLogSink struct {
+	logger    logger.Logger
+	verbosity int
+	name      string
+	values    []any
+}
+
+func (s *sdkLogSink) Init(logr.RuntimeInfo) {}
+
+func (s *sdkLogSink) Enabled(level int) bool {
+	return level <= s.verbosity
+}
+
+func (s *sdkLogSink) Info(level int, msg string, keysAndValues ...any) {
+	fields := s.fields(keysAndValues)
+	ctx := context.Background()
+	switch {
+	case level <= SDKLogWarnings:
+		s.logger.Warning(ctx, msg, fields)
+	case level <= SDKLogInfo:
+		s.logger.Info(ctx, msg, fields)
+	default:
+		s.logger.Debug(ctx, msg, fields)
+	}
+}
+
+func (s *sdkLogSink) Error(err error, msg string, keysAndValues ...any) {
+	fields := s.fields(keysAndValues)
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	s.logger.Error(context.Background(), msg, fields)
+}
+
+func (s *sdkLogSink) WithValues(keysAndValues ...any) logr.LogSink {
+	c := *s
+	c.values = append(s.values[:len(s.values):len(s.values)], keysAndValues...)
+	return &c
+}
+
+func (s *sdkLogSink) WithName(name string) logr.LogSink {
+	c := *s
+	if c.name != "" {
+		name = c.name + "/" + name
+	}
+	c.name = name
+	return &c
+}
+
+// fields turns logr key/value pairs into logger fields, after those added
+// with WithValues. A trailing key without a value is kept with a nil value.
+func (s *sdkLogSink) fields(keysAndValues []any) logger.Fields {
+	fields := logger.Fields{"source": "otel-sdk"}
+	if s.name != "" {
+		fields["logger"] = s.name
+	}
+	for _, kvs := range [][]any{s.values, keysAndValues} {
+		for i := 0; i < len(kvs); i += 2 {
+			var v any
+			if i+1 < len(kvs) {
+				v = kvs[i+1]
+			}
+			fields[fmt.Sprint(kvs[i])] = v
+		}
+	}
+	return fields
+}
+
+var _ logr.LogSink = (*sdkLogSink)(nil)
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+)
+
+type leveledEntry struct {
+	level   string
+	message string
+	fields  logger.Fields
+}
+
+// leveledLogger captures entries of every level.
+type leveledLogger struct {
+	logger.NoopLogger
+	entries []leveledEntry
+}
+
+func (l *leveledLogger) record(level, msg string, fields []logger.Fields) {
+	merged := logger.Fields{}
+	for _, f := range fields {
+		for k, v := range f {
+			merged[k] = v
+		}
+	}
+	l.entries = append(l.entries, leveledEntry{level, msg, merged})
+}
+
+func (l *leveledLogger) Debug(_ context.Context, msg string, f ...logger.Fields) {
+	l.record("debug", msg, f)
+}
+func (l *leveledLogger) Info(_ context.Context, msg string, f ...logger.Fields) {
+	l.record("info", msg, f)
+}
+func (l *leveledLogger) Warning(_ context.Context, msg string, f ...logger.Fields) {
+	l.record("warn", msg, f)
+}
+func (l *leveledLogger) Error(_ context.Context, msg string, f ...logger.Fields) {
+	l.record("error", msg, f)
+}
+
+func TestSDKLogger_MapsVerbosityToLevels(t *testing.T) {
+	log := &leveledLogger{}
+	l := NewSDKLogger(log, SDKLogDebug)
+
+	l.V(SDKLogWarnings).Info("retrying export", "attempt", 2)
+	l.V(SDKLogInfo).Info("exporter started")
+	l.V(SDKLogDebug).Info("batch flushed")
+	l.Error(errors.New("deadline exceeded"), "export failed")
+
+	want := []string{"warn", "info", "debug", "error"}
+	if len(log.entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(log.entries), len(want))
+	}
+	for i, level := range want {
+		if log.entries[i].level != level {
+			t.Errorf("entry %d (%q) level = %s, want %s", i, log.entries[i].message, log.entries[i].level, level)
+		}
+	}
+	if got := log.entries[0].fields["attempt"]; got != 2 {
+		t.Errorf("attempt = %v, want 2", got)
+	}
+	if got := log.entries[3].fields["error"]; got != "deadline exceeded" {
+		t.Errorf("error = %v, want %q", got, "deadline exceeded")
+	}
+	if got := log.entries[1].fields["source"]; got != "otel-sdk" {
+		t.Errorf("source = %v, want otel-sdk", got)
+	}
+}
+
+func TestSDKLogger_DiscardsAboveVerbosity(t *testing.T) {
+	log := &leveledLogger{}
+	l := NewSDKLogger(log, SDKLogWarnings)
+
+	l.V(SDKLogWarnings).Info("kept")
+	l.V(SDKLogInfo).Info("dropped")
+	l.V(SDKLogDebug).Info("dropped")
+
+	if len(log.entries) != 1 || log.entries[0].message != "kept" {
+		t.Errorf("entries = %+v, want only the warning", log.entries)
+	}
+
+	log = &leveledLogger{}
+	NewSDKLogger(log, -1).Error(errors.New("boom"), "discarded")
+	if len(log.entries) != 0 {
+		t.Errorf("negative verbosity logged %d entries", len(log.entries))
+	}
+}
+
+func TestSDKLogger_KeepsNameAndValues(t *testing.T) {
+	log := &leveledLogger{}
+	l := NewSDKLogger(log, SDKLogInfo).WithName("otlp").WithName("grpc").WithValues("endpoint", "collector:4317")
+
+	l.V(SDKLogInfo).Info("connected", "dangling")
+
+	f := log.entries[0].fields
+	if f["logger"] != "otlp/grpc" {
+		t.Errorf("logger = %v, want otlp/grpc", f["logger"])
+	}
+	if f["endpoint"] != "collector:4317" {
+		t.Errorf("endpoint = %v, want collector:4317", f["endpoint"])
+	}
+	if v, ok := f["dangling"]; !ok || v != nil {
+		t.Errorf("dangling key = %v (present %v), want nil", v, ok)
+	}
+}
@@ -0,0 +1,304 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// defaultQueueSize is the SDK batch processors' queue size when none is
+// configured.
+const defaultQueueSize = 2048
+
+// SelfTelemetry reports what the agent itself does as metrics: sampling
+// decisions, batch queue utilization, export latency, scrubber redactions,
+// route-exclusion hits and the agent's worker pool queue. Export failures are counted by ExporterHealth.
+// All methods are no-ops on a nil *SelfTelemetry.
+type SelfTelemetry struct {
+	spans      otelmetric.Int64Counter
+	exports    otelmetric.Float64Histogram
+	redactions otelmetric.Int64Counter
+	exclusions otelmetric.Int64Counter
+	rejected   otelmetric.Int64Counter
+
+	traceQueue  atomic.Pointer[queueUsage]
+	logQueue    atomic.Pointer[queueUsage]
+	workerQueue atomic.Pointer[func() int]
+	blocklist   atomic.Pointer[Blocklist]
+}
+
+// NewSelfTelemetry creates the self-telemetry instruments on meter.
+func NewSelfTelemetry(meter otelmetric.Meter) (*SelfTelemetry, error) {
+	st := &SelfTelemetry{}
+	var err, e error
+
+	st.spans, e = meter.Int64Counter("otel_agent_spans_started_total",
+		otelmetric.WithDescription("Spans started, by sampling decision (sampled, recorded, dropped)"))
+	err = errors.Join(err, e)
+	st.exports, e = meter.Float64Histogram("otel_agent_export_duration_seconds",
+		otelmetric.WithDescription("Duration of export calls by signal and outcome"),
+		otelmetric.WithUnit("s"))
+	err = errors.Join(err, e)
+	st.redactions, e = meter.Int64Counter("otel_agent_scrub_redactions_total",
+		otelmetric.WithDescription("Span attributes redacted by the PII scrubber"))
+	err = errors.Join(err, e)
+	st.exclusions, e = meter.Int64Counter("otel_agent_route_exclusions_total",
+		otelmetric.WithDescription("Requests skipped by route exclusion, by signal"))
+	err = errors.Join(err, e)
+	st.rejected, e = meter.Int64Counter("otel_agent_worker_tasks_rejected_total",
+		otelmetric.WithDescription("Tasks rejected by the agent's worker pool because its queue was full"))
+	err = errors.Join(err, e)
+
+	queue, e := meter.Float64ObservableGauge("otel_agent_queue_utilization",
+		otelmetric.WithDescription("Approximate fill ratio (0-1) of the batch processor queue, by signal"))
+	err = errors.Join(err, e)
+	workerQueue, e := meter.Int64ObservableGauge("otel_agent_worker_queue_depth",
+		otelmetric.WithDescription("Tasks waiting for a worker of the agent's worker pool"))
+	err = errors.Join(err, e)
+	evictions, e := meter.Int64ObservableCounter("otel_agent_blocklist_evictions_total",
+		otelmetric.WithDescription("Blocked traces evicted from the full blocked-trace set before they expired"))
+	err = errors.Join(err, e)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o otelmetric.Observer) error {
+		for signal, q := range map[string]*queueUsage{SignalTraces: st.traceQueue.Load(), SignalLogs: st.logQueue.Load()} {
+			if q != nil {
+				o.ObserveFloat64(queue, q.utilization(), otelmetric.WithAttributes(attribute.String("signal", signal)))
+			}
+		}
+		if depth := st.workerQueue.Load(); depth != nil {
+			o.ObserveInt64(workerQueue, int64((*depth)()))
+		}
+		if b := st.blocklist.Load(); b != nil {
+			o.ObserveInt64(evictions, b.Evicted())
+		}
+		return nil
+	}, queue, workerQueue, evictions)
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// RouteExcluded counts a request that route exclusion kept out of signal.
+func (st *SelfTelemetry) RouteExcluded(signal string) {
+	if st == nil {
+		return
+	}
+	st.exclusions.Add(context.Background(), 1, otelmetric.WithAttributes(attribute.String("signal", signal)))
+}
+
+// TrackWorkerQueue reports depth, the number of tasks queued on the agent's
+// worker pool, as otel_agent_worker_queue_depth.
+func (st *SelfTelemetry) TrackWorkerQueue(depth func() int) {
+	if st == nil {
+		return
+	}
+	st.workerQueue.Store(&depth)
+}
+
+// TrackBlocklist reports the blocked traces b evicted before they expired as
+// otel_agent_blocklist_evictions_total.
+func (st *SelfTelemetry) TrackBlocklist(b *Blocklist) {
+	if st == nil || b == nil {
+		return
+	}
+	st.blocklist.Store(b)
+}
+
+// WorkerTaskRejected counts a task the worker pool rejected.
+func (st *SelfTelemetry) WorkerTaskRejected() {
+	if st == nil {
+		return
+	}
+	st.rejected.Add(context.Background(), 1)
+}
+
+func (st *SelfTelemetry) spanStarted(decision sdktrace.SamplingDecision) {
+	if st == nil {
+		return
+	}
+	name := "dropped"
+	switch decision {
+	case sdktrace.RecordAndSample:
+		name = "sampled"
+	case sdktrace.RecordOnly:
+		name = "recorded"
+	}
+	st.spans.Add(context.Background(), 1, otelmetric.WithAttributes(attribute.String("decision", name)))
+}
+
+func (st *SelfTelemetry) redacted(n int) {
+	if st == nil || n == 0 {
+		return
+	}
+	st.redactions.Add(context.Background(), int64(n))
+}
+
+func (st *SelfTelemetry) exported(ctx context.Context, signal string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	st.exports.Record(ctx, time.Since(start).Seconds(), otelmetric.WithAttributes(
+		attribute.String("signal", signal),
+		attribute.String("outcome", outcome),
+	))
+}
+
+// trackQueue starts reporting the utilization of signal's batch queue.
+func (st *SelfTelemetry) trackQueue(signal string, capacity int) *queueUsage {
+	if capacity <= 0 {
+		capacity = defaultQueueSize
+	}
+	q := &queueUsage{capacity: int64(capacity)}
+	if signal == SignalLogs {
+		st.logQueue.Store(q)
+	} else {
+		st.traceQueue.Store(q)
+	}
+	return q
+}
+
+// queueUsage estimates how many items sit in a batch processor between being
+// queued and leaving it in an export. The SDK does not expose its queue, so
+// items are counted on the way in and out; like the processor, the estimate
+// stops growing at capacity.
+type queueUsage struct {
+	capacity int64
+	pending  atomic.Int64
+}
+
+func (q *queueUsage) enqueue() {
+	for {
+		n := q.pending.Load()
+		if n >= q.capacity || q.pending.CompareAndSwap(n, n+1) {
+			return
+		}
+	}
+}
+
+func (q *queueUsage) dequeue(n int) {
+	for {
+		cur := q.pending.Load()
+		if q.pending.CompareAndSwap(cur, max(cur-int64(n), 0)) {
+			return
+		}
+	}
+}
+
+func (q *queueUsage) utilization() float64 {
+	return float64(q.pending.Load()) / float64(q.capacity)
+}
+
+// WithTraceSelfTelemetry reports sampling decisions, queue utilization,
+// export latency and redactions of the trace pipeline to st.
+func WithTraceSelfTelemetry(st *SelfTelemetry) TraceProviderOption {
+	return func(o *traceProviderOptions) {
+		o.self = st
+	}
+}
+
+// WithMetricSelfTelemetry reports metric export latency to st.
+func WithMetricSelfTelemetry(st *SelfTelemetry) MetricProviderOption {
+	return func(o *metricProviderOptions) {
+		o.self = st
+	}
+}
+
+// WithLogSelfTelemetry reports queue utilization and export latency of the
+// log pipeline to st.
+func WithLogSelfTelemetry(st *SelfTelemetry) LogProviderOption {
+	return func(o *logProviderOptions) {
+		o.self = st
+	}
+}
+
+type selfTelemetrySampler struct {
+	next sdktrace.Sampler
+	st   *SelfTelemetry
+}
+
+func (s selfTelemetrySampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	res := s.next.ShouldSample(p)
+	s.st.spanStarted(res.Decision)
+	return res
+}
+
+func (s selfTelemetrySampler) Description() string {
+	return s.next.Description()
+}
+
+// queueSpanProcessor counts the spans next, the batch span processor, queues.
+type queueSpanProcessor struct {
+	sdktrace.SpanProcessor
+	queue *queueUsage
+}
+
+func (p queueSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		p.queue.enqueue()
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// queueLogProcessor counts the records next, the batch log processor, queues.
+type queueLogProcessor struct {
+	log.Processor
+	queue *queueUsage
+}
+
+func (p queueLogProcessor) OnEmit(ctx context.Context, r *log.Record) error {
+	p.queue.enqueue()
+	return p.Processor.OnEmit(ctx, r)
+}
+
+type selfTelemetrySpanExporter struct {
+	sdktrace.SpanExporter
+	st    *SelfTelemetry
+	queue *queueUsage
+}
+
+func (e selfTelemetrySpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	start := time.Now()
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.queue.dequeue(len(spans))
+	e.st.exported(ctx, SignalTraces, start, err)
+	return err
+}
+
+type selfTelemetryMetricExporter struct {
+	metric.Exporter
+	st *SelfTelemetry
+}
+
+func (e selfTelemetryMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	start := time.Now()
+	err := e.Exporter.Export(ctx, rm)
+	e.st.exported(ctx, SignalMetrics, start, err)
+	return err
+}
+
+type selfTelemetryLogExporter struct {
+	log.Exporter
+	st    *SelfTelemetry
+	queue *queueUsage
+}
+
+func (e selfTelemetryLogExporter) Export(ctx context.Context, records []log.Record) error {
+	start := time.Now()
+	err := e.Exporter.Export(ctx, records)
+	e.queue.dequeue(len(records))
+	e.st.exported(ctx, SignalLogs, start, err)
+	return err
+}
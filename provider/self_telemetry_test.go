@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// selfMetrics collects reader and indexes each data point by metric name and
+// its attribute values, e.g. "otel_agent_spans_started_total{sampled}".
+func selfMetrics(t *testing.T, reader *sdkmetric.ManualReader) map[string]float64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]float64{}
+	key := func(name string, attrs attribute.Set) string {
+		k := name + "{"
+		for i, kv := range attrs.ToSlice() {
+			if i > 0 {
+				k += ","
+			}
+			k += kv.Value.Emit()
+		}
+		return k + "}"
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch d := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range d.DataPoints {
+					got[key(m.Name, dp.Attributes)] = float64(dp.Value)
+				}
+			case metricdata.Gauge[int64]:
+				for _, dp := range d.DataPoints {
+					got[key(m.Name, dp.Attributes)] = float64(dp.Value)
+				}
+			case metricdata.Gauge[float64]:
+				for _, dp := range d.DataPoints {
+					got[key(m.Name, dp.Attributes)] = dp.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range d.DataPoints {
+					got[key(m.Name, dp.Attributes)] = float64(dp.Count)
+				}
+			}
+		}
+	}
+	return got
+}
+
+func TestSelfTelemetry_ReportsTracePipeline(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	st, err := NewSelfTelemetry(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"))
+	if err != nil {
+		t.Fatalf("NewSelfTelemetry: %v", err)
+	}
+
+	cfg := &config.Config{
+		ExporterProtocol: ProtocolStdout,
+		Stdout:           config.StdoutConfig{Path: filepath.Join(t.TempDir(), "traces.json"), MaxSizeMB: 1},
+		Traces: config.TracesConfig{
+			Sampling:       config.SamplingConfig{Type: "always_on"},
+			BatchTimeout:   time.Hour,
+			QueueSize:      8,
+			MaxExportBatch: 8,
+		},
+		Scrub: config.ScrubConfig{Enabled: true, SensitiveKeys: []string{"password"}},
+	}
+	tp, err := NewTraceProvider(cfg, resource.Empty(), &logger.NoopLogger{}, WithTraceSelfTelemetry(st))
+	if err != nil {
+		t.Fatalf("NewTraceProvider: %v", err)
+	}
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	for range 4 {
+		_, span := tp.Tracer("test").Start(context.Background(), "login",
+			trace.WithAttributes(attribute.String("password", "hunter2")))
+		span.End()
+	}
+
+	got := selfMetrics(t, reader)
+	if got["otel_agent_spans_started_total{sampled}"] != 4 {
+		t.Errorf("spans started = %v, want 4 sampled", got)
+	}
+	if got["otel_agent_queue_utilization{traces}"] != 0.5 {
+		t.Errorf("queue utilization = %v, want 4 of 8 queued spans", got["otel_agent_queue_utilization{traces}"])
+	}
+
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	got = selfMetrics(t, reader)
+	if got["otel_agent_queue_utilization{traces}"] != 0 {
+		t.Errorf("queue utilization after flush = %v, want 0", got["otel_agent_queue_utilization{traces}"])
+	}
+	if got["otel_agent_export_duration_seconds{success,traces}"] != 1 {
+		t.Errorf("export durations = %v, want one successful trace export", got)
+	}
+	if got["otel_agent_scrub_redactions_total{}"] != 4 {
+		t.Errorf("redactions = %v, want one per exported span", got["otel_agent_scrub_redactions_total{}"])
+	}
+}
+
+func TestSelfTelemetry_ReportsWorkerQueue(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	st, err := NewSelfTelemetry(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"))
+	if err != nil {
+		t.Fatalf("NewSelfTelemetry: %v", err)
+	}
+
+	st.TrackWorkerQueue(func() int { return 7 })
+	st.WorkerTaskRejected()
+	st.WorkerTaskRejected()
+
+	got := selfMetrics(t, reader)
+	if got["otel_agent_worker_queue_depth{}"] != 7 || got["otel_agent_worker_tasks_rejected_total{}"] != 2 {
+		t.Errorf("worker metrics = %v, want depth 7 and 2 rejections", got)
+	}
+}
+
+func TestSelfTelemetry_NilIsNoop(t *testing.T) {
+	var st *SelfTelemetry
+	st.RouteExcluded(SignalTraces)
+	st.TrackWorkerQueue(func() int { return 1 })
+	st.WorkerTaskRejected()
+	st.redacted(3)
+}
+
+func TestQueueUsage_StaysWithinCapacity(t *testing.T) {
+	q := &queueUsage{capacity: 2}
+	for range 5 {
+		q.enqueue()
+	}
+	if got := q.utilization(); got != 1 {
+		t.Errorf("utilization = %v, want 1 when the queue is full", got)
+	}
+	q.dequeue(10)
+	if got := q.utilization(); got != 0 {
+		t.Errorf("utilization = %v, want 0 after draining", got)
+	}
+}
@@ -0,0 +1,284 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// CompressedCountAttribute is set on a compressed span to the number of
+	// sibling spans it stands for.
+	CompressedCountAttribute = "otel.span.compressed.count"
+
+	// CompressedDurationSumAttribute, CompressedDurationMinAttribute and
+	// CompressedDurationMaxAttribute are set on a compressed span to the
+	// total, shortest and longest duration of the spans it stands for, in
+	// milliseconds.
+	CompressedDurationSumAttribute = "otel.span.compressed.duration_sum_ms"
+	CompressedDurationMinAttribute = "otel.span.compressed.duration_min_ms"
+	CompressedDurationMaxAttribute = "otel.span.compressed.duration_max_ms"
+
+	// maxPendingParents bounds the parents whose children are tracked;
+	// children of further parents are passed through uncompressed.
+	maxPendingParents = 4096
+)
+
+// NewSpanCompressionProcessor wraps next, typically the batch span
+// processor, so that runs of consecutive sibling spans with the same name
+// and kind, such as the 500 cache lookups of a loop, reach it as one span.
+//
+// A span is compressible when it is sampled, has a local parent, never
+// started a child (even one still running when it ends), lasts at most
+// cfg.MaxDuration and does not have an error status. A run ends when its
+// parent ends or when a sibling that does not extend it ends. A run of at
+// least cfg.MinSpans spans is exported as its first span, ending where the
+// last one ended, with otel.span.compressed.count and the total, min and max
+// duration of the run; shorter runs are exported unchanged.
+//
+// Only children started while their parent was still running can be
+// compressed: the children of a parent that already ended, as in
+// fire-and-forget goroutines, are passed through.
+//
+// ForceFlush ends the runs in progress, so a run that straddles a flush is
+// exported as two spans (or uncompressed, if either part is too short).
+func NewSpanCompressionProcessor(next sdktrace.SpanProcessor, cfg config.SpanCompressionConfig) sdktrace.SpanProcessor {
+	return &spanCompressionProcessor{
+		next:        next,
+		minSpans:    cfg.MinSpans,
+		maxDuration: cfg.MaxDuration,
+		parents:     make(map[spanKey]*pendingParent),
+	}
+}
+
+type spanKey struct {
+	trace trace.TraceID
+	span  trace.SpanID
+}
+
+// pendingParent is a running parent that started children, and the run of
+// its children in progress, if any. span is nil when the parent was not in
+// the context its children started from.
+type pendingParent struct {
+	span sdktrace.ReadOnlySpan
+	run  *spanRun
+}
+
+// spanRun collects consecutive compressible children of one parent. Until
+// the run is long enough to be compressed its spans are held; after that only
+// the first one and the statistics are kept.
+type spanRun struct {
+	name  string
+	kind  trace.SpanKind
+	first sdktrace.ReadOnlySpan
+	held  []sdktrace.ReadOnlySpan
+
+	count         int
+	end           time.Time
+	sum, min, max time.Duration
+}
+
+type spanCompressionProcessor struct {
+	next        sdktrace.SpanProcessor
+	minSpans    int
+	maxDuration time.Duration
+
+	mu sync.Mutex
+	// parents has an entry for every unended local parent that started a
+	// child.
+	parents map[spanKey]*pendingParent
+	// untracked is set when a child started while parents was full: its
+	// parent cannot be told from a childless span, so nothing is compressed
+	// until parents has drained.
+	untracked bool
+}
+
+func (p *spanCompressionProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	if parent := s.Parent(); s.SpanContext().IsSampled() && parent.IsValid() && !parent.IsRemote() {
+		key := spanKey{trace: parent.TraceID(), span: parent.SpanID()}
+		// ctx is the parent's context. The parent's end time is set before
+		// its OnEnd takes p.mu, so a parent seen running here has not yet
+		// been through OnEnd and will remove its entry. A parent known only
+		// by its span context is tracked until the next flush.
+		parentSpan, _ := trace.SpanFromContext(ctx).(sdktrace.ReadOnlySpan)
+		if parentSpan != nil && !parentSpan.SpanContext().Equal(parent) {
+			parentSpan = nil
+		}
+		p.mu.Lock()
+		if _, tracked := p.parents[key]; !tracked && (parentSpan == nil || parentSpan.EndTime().IsZero()) {
+			if len(p.parents) < maxPendingParents {
+				p.parents[key] = &pendingParent{span: parentSpan}
+			} else {
+				p.untracked = true
+			}
+		}
+		p.mu.Unlock()
+	}
+	p.next.OnStart(ctx, s)
+}
+
+func (p *spanCompressionProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	sc := s.SpanContext()
+	if !sc.IsSampled() {
+		p.next.OnEnd(s)
+		return
+	}
+
+	var flush []sdktrace.ReadOnlySpan
+	forward := true
+
+	p.mu.Lock()
+	// The run of s's children is over, and a span that started children
+	// must keep its ID for them to point at, even if some are still running.
+	self := spanKey{trace: sc.TraceID(), span: sc.SpanID()}
+	children, hasChildren := p.parents[self]
+	if hasChildren {
+		delete(p.parents, self)
+		if children.run != nil {
+			flush = children.run.flush(flush, p.minSpans)
+		}
+	}
+	if len(p.parents) == 0 {
+		p.untracked = false
+	}
+	hasChildren = hasChildren || p.untracked
+
+	// Only the children of tracked parents are held: the parent's OnEnd
+	// then exports the run, which it could not do once it has ended.
+	if parent := s.Parent(); parent.IsValid() && !parent.IsRemote() {
+		if pending, tracked := p.parents[spanKey{trace: parent.TraceID(), span: parent.SpanID()}]; tracked {
+			run := pending.run
+			if run != nil && !(run.extendedBy(s) && p.compressible(s, hasChildren)) {
+				flush = run.flush(flush, p.minSpans)
+				run = nil
+			}
+			if run == nil && p.compressible(s, hasChildren) {
+				run = &spanRun{name: s.Name(), kind: s.SpanKind(), first: s}
+			}
+			if run != nil {
+				run.add(s, p.minSpans)
+				forward = false
+			}
+			pending.run = run
+		}
+	}
+	p.mu.Unlock()
+
+	for _, held := range flush {
+		p.next.OnEnd(held)
+	}
+	if forward {
+		p.next.OnEnd(s)
+	}
+}
+
+func (p *spanCompressionProcessor) compressible(s sdktrace.ReadOnlySpan, hasChildren bool) bool {
+	return !hasChildren &&
+		s.Status().Code != codes.Error &&
+		s.EndTime().Sub(s.StartTime()) <= p.maxDuration
+}
+
+// flushAll ends every run in progress, for spans whose parent never ended
+// by the time of a flush or shutdown. The running parents stay tracked
+// unless forget is set, so they are still known to have children when they
+// end; parents that ended without going through OnEnd, such as spans of
+// another provider, and parents that cannot be checked are dropped.
+func (p *spanCompressionProcessor) flushAll(forget bool) {
+	var flush []sdktrace.ReadOnlySpan
+	p.mu.Lock()
+	for key, pending := range p.parents {
+		if pending.run != nil {
+			flush = pending.run.flush(flush, p.minSpans)
+			pending.run = nil
+		}
+		if forget || pending.span == nil || !pending.span.EndTime().IsZero() {
+			delete(p.parents, key)
+		}
+	}
+	if len(p.parents) == 0 {
+		p.untracked = false
+	}
+	p.mu.Unlock()
+
+	for _, s := range flush {
+		p.next.OnEnd(s)
+	}
+}
+
+func (p *spanCompressionProcessor) Shutdown(ctx context.Context) error {
+	p.flushAll(true)
+	return p.next.Shutdown(ctx)
+}
+
+func (p *spanCompressionProcessor) ForceFlush(ctx context.Context) error {
+	p.flushAll(false)
+	return p.next.ForceFlush(ctx)
+}
+
+func (r *spanRun) extendedBy(s sdktrace.ReadOnlySpan) bool {
+	return s.Name() == r.name && s.SpanKind() == r.kind
+}
+
+func (r *spanRun) add(s sdktrace.ReadOnlySpan, minSpans int) {
+	d := s.EndTime().Sub(s.StartTime())
+	if r.count == 0 || d < r.min {
+		r.min = d
+	}
+	if d > r.max {
+		r.max = d
+	}
+	if end := s.EndTime(); end.After(r.end) {
+		r.end = end
+	}
+	r.sum += d
+	r.count++
+
+	if r.count < minSpans {
+		r.held = append(r.held, s)
+	} else {
+		r.held = nil
+	}
+}
+
+// flush appends the spans the run is exported as to spans.
+func (r *spanRun) flush(spans []sdktrace.ReadOnlySpan, minSpans int) []sdktrace.ReadOnlySpan {
+	if r.count < minSpans {
+		return append(spans, r.held...)
+	}
+	return append(spans, compressedSpan{
+		ReadOnlySpan: r.first,
+		end:          r.end,
+		attrs: []attribute.KeyValue{
+			attribute.Int(CompressedCountAttribute, r.count),
+			attribute.Float64(CompressedDurationSumAttribute, milliseconds(r.sum)),
+			attribute.Float64(CompressedDurationMinAttribute, milliseconds(r.min)),
+			attribute.Float64(CompressedDurationMaxAttribute, milliseconds(r.max)),
+		},
+	})
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// compressedSpan is the first span of a run, spanning the whole run.
+type compressedSpan struct {
+	sdktrace.ReadOnlySpan
+	end   time.Time
+	attrs []attribute.KeyValue
+}
+
+func (s compressedSpan) EndTime() time.Time {
+	return s.end
+}
+
+func (s compressedSpan) Attributes() []attribute.KeyValue {
+	attrs := s.ReadOnlySpan.Attributes()
+	return append(attrs[:len(attrs):len(attrs)], s.attrs...)
+}
@@ -0,0 +1,202 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newCompressionTracer() (trace.Tracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	processor := NewSpanCompressionProcessor(recorder, config.SpanCompressionConfig{
+		Enabled: true, MinSpans: 3, MaxDuration: 50 * time.Millisecond,
+	})
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+	return tp.Tracer("test"), recorder
+}
+
+// endChild starts and ends a span of the given duration under ctx.
+func endChild(ctx context.Context, tracer trace.Tracer, name string, d time.Duration) trace.Span {
+	start := time.Now()
+	_, s := tracer.Start(ctx, name, trace.WithTimestamp(start))
+	s.End(trace.WithTimestamp(start.Add(d)))
+	return s
+}
+
+func attributeValue(s sdktrace.ReadOnlySpan, key string) (attribute.Value, bool) {
+	for _, kv := range s.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestSpanCompressionProcessor_CollapsesRunOfSiblings(t *testing.T) {
+	tracer, recorder := newCompressionTracer()
+
+	ctx, root := tracer.Start(context.Background(), "handler")
+	for i := range 500 {
+		endChild(ctx, tracer, "cache.get", time.Duration(1+i%5)*time.Millisecond)
+	}
+	root.End()
+
+	if got := endedNames(recorder); len(got) != 2 || got[0] != "cache.get" || got[1] != "handler" {
+		t.Fatalf("exported spans = %v, want one cache.get and handler", got)
+	}
+	compressed := recorder.Ended()[0]
+	if v, _ := attributeValue(compressed, CompressedCountAttribute); v.AsInt64() != 500 {
+		t.Errorf("%s = %d, want 500", CompressedCountAttribute, v.AsInt64())
+	}
+	if v, _ := attributeValue(compressed, CompressedDurationSumAttribute); v.AsFloat64() != 1500 {
+		t.Errorf("%s = %v, want 1500", CompressedDurationSumAttribute, v.AsFloat64())
+	}
+	if v, _ := attributeValue(compressed, CompressedDurationMinAttribute); v.AsFloat64() != 1 {
+		t.Errorf("%s = %v, want 1", CompressedDurationMinAttribute, v.AsFloat64())
+	}
+	if v, _ := attributeValue(compressed, CompressedDurationMaxAttribute); v.AsFloat64() != 5 {
+		t.Errorf("%s = %v, want 5", CompressedDurationMaxAttribute, v.AsFloat64())
+	}
+	if compressed.EndTime().Before(compressed.StartTime()) {
+		t.Error("compressed span ends before it starts")
+	}
+}
+
+func TestSpanCompressionProcessor_KeepsShortRunsAndBreaks(t *testing.T) {
+	tracer, recorder := newCompressionTracer()
+
+	ctx, root := tracer.Start(context.Background(), "handler")
+	endChild(ctx, tracer, "cache.get", time.Millisecond)
+	endChild(ctx, tracer, "cache.get", time.Millisecond)
+	endChild(ctx, tracer, "db.query", time.Millisecond)
+	endChild(ctx, tracer, "cache.get", time.Millisecond)
+	endChild(ctx, tracer, "cache.get", time.Second) // too slow to join a run
+	root.End()
+
+	want := []string{"cache.get", "cache.get", "db.query", "cache.get", "cache.get", "handler"}
+	got := endedNames(recorder)
+	if len(got) != len(want) {
+		t.Fatalf("exported spans = %v, want %v", got, want)
+	}
+	for _, s := range recorder.Ended() {
+		if _, ok := attributeValue(s, CompressedCountAttribute); ok {
+			t.Errorf("span %s was compressed, want runs shorter than MinSpans left alone", s.Name())
+		}
+	}
+}
+
+func TestSpanCompressionProcessor_NeverCompressesErrorsOrParents(t *testing.T) {
+	tracer, recorder := newCompressionTracer()
+
+	ctx, root := tracer.Start(context.Background(), "handler")
+	for range 3 {
+		endChild(ctx, tracer, "cache.get", time.Millisecond)
+	}
+	_, failed := tracer.Start(ctx, "cache.get")
+	failed.SetStatus(codes.Error, "timeout")
+	failed.End()
+	for range 3 {
+		groupCtx, group := tracer.Start(ctx, "batch")
+		endChild(groupCtx, tracer, "item", time.Millisecond)
+		group.End()
+	}
+	root.End()
+
+	var compressed, batches int
+	for _, s := range recorder.Ended() {
+		if _, ok := attributeValue(s, CompressedCountAttribute); ok {
+			compressed++
+		}
+		if s.Name() == "batch" {
+			batches++
+		}
+	}
+	if compressed != 1 {
+		t.Errorf("exported %d compressed spans, want only the run before the error", compressed)
+	}
+	if batches != 3 {
+		t.Errorf("exported %d batch spans, want all 3 since they have children", batches)
+	}
+}
+
+func TestSpanCompressionProcessor_ForceFlushEndsOpenRuns(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	processor := NewSpanCompressionProcessor(recorder, config.SpanCompressionConfig{
+		Enabled: true, MinSpans: 3, MaxDuration: 50 * time.Millisecond,
+	})
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor)).Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "long-running")
+	for range 4 {
+		endChild(ctx, tracer, "poll", time.Millisecond)
+	}
+	if err := processor.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush() error = %v", err)
+	}
+	if got := endedNames(recorder); len(got) != 1 || got[0] != "poll" {
+		t.Errorf("exported spans = %v, want the compressed poll run", got)
+	}
+	root.End()
+}
+
+func TestSpanCompressionProcessor_NeverCompressesSpansWithRunningChildren(t *testing.T) {
+	tracer, recorder := newCompressionTracer()
+
+	ctx, root := tracer.Start(context.Background(), "handler")
+	var orphans []trace.Span
+	for range 3 {
+		start := time.Now()
+		groupCtx, group := tracer.Start(ctx, "dispatch", trace.WithTimestamp(start))
+		_, async := tracer.Start(groupCtx, "async.job")
+		group.End(trace.WithTimestamp(start.Add(time.Millisecond)))
+		orphans = append(orphans, async)
+	}
+	for _, s := range orphans {
+		s.End()
+	}
+	root.End()
+
+	var dispatches int
+	for _, s := range recorder.Ended() {
+		if s.Name() != "dispatch" {
+			continue
+		}
+		dispatches++
+		if _, ok := attributeValue(s, CompressedCountAttribute); ok {
+			t.Errorf("dispatch span was compressed although its child was still running")
+		}
+	}
+	if dispatches != 3 {
+		t.Errorf("exported %d dispatch spans, want all 3 since they started children", dispatches)
+	}
+}
+
+func TestSpanCompressionProcessor_PassesThroughChildrenThatOutliveTheirParent(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	processor := NewSpanCompressionProcessor(recorder, config.SpanCompressionConfig{
+		Enabled: true, MinSpans: 3, MaxDuration: 50 * time.Millisecond,
+	}).(*spanCompressionProcessor)
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor)).Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "handler")
+	_, early := tracer.Start(ctx, "audit.write") // started before the parent ends
+	root.End()
+	early.End()
+	for range 4 {
+		endChild(ctx, tracer, "audit.write", time.Millisecond) // started after
+	}
+
+	if got := endedNames(recorder); len(got) != 6 {
+		t.Errorf("exported spans = %v, want all 6 without waiting for a flush", got)
+	}
+	if n := len(processor.parents); n != 0 {
+		t.Errorf("tracking %d parents after the parent ended, want 0", n)
+	}
+}
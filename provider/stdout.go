@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ProtocolStdout selects the stdout exporters. See config.ProtocolStdout.
+const ProtocolStdout = config.ProtocolStdout
+
+// stdoutFiles shares one rotating file per path between the trace, metric
+// and log exporters, so their writes never interleave mid-record. The file
+// is closed when the last exporter using it shuts down.
+var (
+	stdoutFilesMu sync.Mutex
+	stdoutFiles   = make(map[string]*sharedFile)
+)
+
+type sharedFile struct {
+	*rotatingFile
+	refs int
+}
+
+// stdoutWriter returns os.Stdout, or the rotating file at cfg.Path, and the
+// function the exporter must call on shutdown to release it.
+func stdoutWriter(cfg config.StdoutConfig) (io.Writer, func() error, error) {
+	if cfg.Path == "" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+
+	stdoutFilesMu.Lock()
+	defer stdoutFilesMu.Unlock()
+	f, ok := stdoutFiles[cfg.Path]
+	if !ok {
+		file, err := newRotatingFile(cfg.Path, int64(cfg.MaxSizeMB)<<20, cfg.MaxBackups)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open stdout exporter file: %w", err)
+		}
+		f = &sharedFile{rotatingFile: file}
+		stdoutFiles[cfg.Path] = f
+	}
+	f.refs++
+
+	var once sync.Once
+	release := func() (err error) {
+		once.Do(func() {
+			stdoutFilesMu.Lock()
+			defer stdoutFilesMu.Unlock()
+			if f.refs--; f.refs == 0 {
+				delete(stdoutFiles, cfg.Path)
+				err = f.Close()
+			}
+		})
+		return err
+	}
+	return f, release, nil
+}
+
+func stdoutTarget(cfg config.StdoutConfig) string {
+	if cfg.Path == "" {
+		return "stdout"
+	}
+	return cfg.Path
+}
+
+// stdoutSpanExporter releases its file once the exporter is shut down.
+type stdoutSpanExporter struct {
+	sdktrace.SpanExporter
+	release func() error
+}
+
+func (e *stdoutSpanExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.SpanExporter.Shutdown(ctx), e.release())
+}
+
+// stdoutMetricExporter releases its file once the exporter is shut down.
+type stdoutMetricExporter struct {
+	metric.Exporter
+	release func() error
+}
+
+func (e *stdoutMetricExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.Exporter.Shutdown(ctx), e.release())
+}
+
+// stdoutLogExporter releases its file once the exporter is shut down.
+type stdoutLogExporter struct {
+	log.Exporter
+	release func() error
+}
+
+func (e *stdoutLogExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.Exporter.Shutdown(ctx), e.release())
+}
+
+func createStdoutTraceExporter(ctx context.Context, cfg *config.Config, log logger.Logger) (sdktrace.SpanExporter, error) {
+	w, release, err := stdoutWriter(cfg.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	opts := []stdouttrace.Option{stdouttrace.WithWriter(w)}
+	if cfg.Stdout.PrettyPrint {
+		opts = append(opts, stdouttrace.WithPrettyPrint())
+	}
+
+	exporter, err := stdouttrace.New(opts...)
+	if err != nil {
+		_ = release()
+		return nil, fmt.Errorf("failed to create stdout trace exporter: %w", err)
+	}
+
+	log.Info(ctx, "Stdout trace exporter initialized", logger.Fields{"output": stdoutTarget(cfg.Stdout)})
+	return &stdoutSpanExporter{SpanExporter: exporter, release: release}, nil
+}
+
+func createStdoutMetricExporter(ctx context.Context, cfg *config.Config, log logger.Logger, o *metricProviderOptions) (metric.Exporter, error) {
+	w, release, err := stdoutWriter(cfg.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	opts := []stdoutmetric.Option{stdoutmetric.WithWriter(w), stdoutmetric.WithTemporalitySelector(o.temporality)}
+	if o.aggregation != nil {
+		opts = append(opts, stdoutmetric.WithAggregationSelector(o.aggregation))
+	}
+	if cfg.Stdout.PrettyPrint {
+		opts = append(opts, stdoutmetric.WithPrettyPrint())
+	}
+
+	exporter, err := stdoutmetric.New(opts...)
+	if err != nil {
+		_ = release()
+		return nil, fmt.Errorf("failed to create stdout metric exporter: %w", err)
+	}
+
+	log.Info(ctx, "Stdout metric exporter initialized", logger.Fields{"output": stdoutTarget(cfg.Stdout)})
+	return &stdoutMetricExporter{Exporter: exporter, release: release}, nil
+}
+
+func createStdoutLogExporter(ctx context.Context, cfg *config.Config, lgr logger.Logger) (log.Exporter, error) {
+	w, release, err := stdoutWriter(cfg.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	opts := []stdoutlog.Option{stdoutlog.WithWriter(w)}
+	if cfg.Stdout.PrettyPrint {
+		opts = append(opts, stdoutlog.WithPrettyPrint())
+	}
+
+	exporter, err := stdoutlog.New(opts...)
+	if err != nil {
+		_ = release()
+		return nil, fmt.Errorf("failed to create stdout log exporter: %w", err)
+	}
+
+	lgr.Info(ctx, "Stdout log exporter initialized", logger.Fields{"output": stdoutTarget(cfg.Stdout)})
+	return &stdoutLogExporter{Exporter: exporter, release: release}, nil
+}
+
+// rotatingFile is an append-only file that is renamed to path.1 (shifting
+// older backups up to path.<maxBackups>) once a write would take it past
+// maxBytes. A non-positive maxBytes disables rotation.
+type rotatingFile struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newRotatingFile(path string, maxBytes int64, maxBackups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	r := &rotatingFile{path: path, maxBytes: maxBytes, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	r.file, r.size = f, info.Size()
+	return nil
+}
+
+// Write implements io.Writer. A single write is never split across files.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.maxBytes > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate moves the current file out of the way and opens a new one. If the
+// file cannot be moved, it is reopened and kept past maxBytes, so writes go
+// on and rotation is retried on the next write.
+func (r *rotatingFile) rotate() error {
+	_ = r.file.Close()
+	if r.maxBackups > 0 {
+		for i := r.maxBackups - 1; i > 0; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+		_ = os.Rename(r.path, r.path+".1")
+	} else {
+		_ = os.Remove(r.path)
+	}
+	return r.open()
+}
+
+// Close closes the underlying file.
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
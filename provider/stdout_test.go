@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestRotatingFile_RotatesAndKeepsBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "otel", "telemetry.json")
+	f, err := newRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("newRotatingFile: %v", err)
+	}
+	defer f.Close()
+
+	for _, rec := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		if _, err := f.Write([]byte(rec)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	for name, want := range map[string]string{
+		path:        "dddddddd\n",
+		path + ".1": "cccccccc\n",
+		path + ".2": "bbbbbbbb\n",
+	} {
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("ReadFile(%s): %v", name, err)
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", filepath.Base(name), got, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("expected at most 2 backups")
+	}
+}
+
+func TestRotatingFile_NeverSplitsAWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.json")
+	f, err := newRotatingFile(path, 4, 1)
+	if err != nil {
+		t.Fatalf("newRotatingFile: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write([]byte("larger than the limit\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	if string(got) != "larger than the limit\n" {
+		t.Errorf("file = %q, want the whole record", got)
+	}
+}
+
+func TestNewTraceProvider_StdoutProtocolWritesToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.json")
+	cfg := &config.Config{
+		ExporterProtocol: ProtocolStdout,
+		Stdout:           config.StdoutConfig{Path: path, MaxSizeMB: 1},
+		Traces: config.TracesConfig{
+			Sampling:       config.SamplingConfig{Type: "always_on"},
+			BatchSize:      512,
+			QueueSize:      2048,
+			MaxExportBatch: 512,
+		},
+	}
+
+	tp, err := NewTraceProvider(cfg, resource.Empty(), &logger.NoopLogger{})
+	if err != nil {
+		t.Fatalf("NewTraceProvider: %v", err)
+	}
+	_, span := tp.Tracer("test").Start(context.Background(), "checkout")
+	span.End()
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !strings.Contains(string(got), `"Name":"checkout"`) {
+		t.Errorf("expected the span in %s, got:\n%s", path, got)
+	}
+}
+
+func TestRotatingFile_KeepsWritingWhenRotationFails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "telemetry.json")
+	f, err := newRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("newRotatingFile: %v", err)
+	}
+	defer f.Close()
+
+	// A directory in the way of the backup makes the rename fail.
+	if err := os.MkdirAll(filepath.Join(path+".1", "busy"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range []string{"aaaaaaaa\n", "bbbbbbbb\n"} {
+		if _, err := f.Write([]byte(rec)); err != nil {
+			t.Fatalf("Write(%q): %v", rec, err)
+		}
+	}
+	got, _ := os.ReadFile(path)
+	if string(got) != "aaaaaaaa\nbbbbbbbb\n" {
+		t.Errorf("file = %q, want both records in the unrotated file", got)
+	}
+}
+
+func TestStdoutWriter_ClosesTheFileWithTheLastExporter(t *testing.T) {
+	cfg := config.StdoutConfig{Path: filepath.Join(t.TempDir(), "telemetry.json")}
+	first, releaseFirst, err := stdoutWriter(cfg)
+	if err != nil {
+		t.Fatalf("stdoutWriter: %v", err)
+	}
+	second, releaseSecond, err := stdoutWriter(cfg)
+	if err != nil {
+		t.Fatalf("stdoutWriter: %v", err)
+	}
+	if first != second {
+		t.Fatal("exporters of one path must share its file")
+	}
+
+	if err := releaseFirst(); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	_ = releaseFirst() // releasing twice must not close the file under the other exporter
+	if _, err := second.Write([]byte("still open\n")); err != nil {
+		t.Fatalf("Write after the first release: %v", err)
+	}
+	if err := releaseSecond(); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if _, err := second.Write([]byte("closed\n")); err == nil {
+		t.Error("Write after the last release succeeded, want the file closed")
+	}
+	if _, ok := stdoutFiles[cfg.Path]; ok {
+		t.Error("the closed file is still registered")
+	}
+}
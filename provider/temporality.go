@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// TemporalitySelector returns the selector for a temporality preference, as
+// defined for OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE:
+//
+//   - cumulative: every instrument is cumulative (the SDK default)
+//   - delta: counters, observable counters and histograms are delta;
+//     up-down counters stay cumulative
+//   - lowmemory: only synchronous counters and histograms are delta
+//
+// Any other value selects cumulative.
+func TemporalitySelector(preference string) metric.TemporalitySelector {
+	switch preference {
+	case config.TemporalityDelta:
+		return deltaTemporality
+	case config.TemporalityLowMemory:
+		return lowMemoryTemporality
+	default:
+		return metric.DefaultTemporalitySelector
+	}
+}
+
+func deltaTemporality(kind metric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case metric.InstrumentKindCounter, metric.InstrumentKindObservableCounter, metric.InstrumentKindHistogram:
+		return metricdata.DeltaTemporality
+	default:
+		return metricdata.CumulativeTemporality
+	}
+}
+
+func lowMemoryTemporality(kind metric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case metric.InstrumentKindCounter, metric.InstrumentKindHistogram:
+		return metricdata.DeltaTemporality
+	default:
+		return metricdata.CumulativeTemporality
+	}
+}
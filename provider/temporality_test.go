@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestTemporalitySelector(t *testing.T) {
+	const (
+		cumulative = metricdata.CumulativeTemporality
+		delta      = metricdata.DeltaTemporality
+	)
+	kinds := []metric.InstrumentKind{
+		metric.InstrumentKindCounter,
+		metric.InstrumentKindObservableCounter,
+		metric.InstrumentKindHistogram,
+		metric.InstrumentKindUpDownCounter,
+		metric.InstrumentKindObservableUpDownCounter,
+	}
+	for _, tc := range []struct {
+		preference string
+		want       []metricdata.Temporality
+	}{
+		{config.TemporalityCumulative, []metricdata.Temporality{cumulative, cumulative, cumulative, cumulative, cumulative}},
+		{config.TemporalityDelta, []metricdata.Temporality{delta, delta, delta, cumulative, cumulative}},
+		{config.TemporalityLowMemory, []metricdata.Temporality{delta, cumulative, delta, cumulative, cumulative}},
+		{"", []metricdata.Temporality{cumulative, cumulative, cumulative, cumulative, cumulative}},
+	} {
+		selector := TemporalitySelector(tc.preference)
+		for i, kind := range kinds {
+			if got := selector(kind); got != tc.want[i] {
+				t.Errorf("%q: %v = %v, want %v", tc.preference, kind, got, tc.want[i])
+			}
+		}
+	}
+}
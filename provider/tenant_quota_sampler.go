@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DefaultTenantBaggageKey is the baggage member holding the tenant ID.
+	DefaultTenantBaggageKey = "tenant.id"
+
+	// maxTrackedTenants bounds the per-tenant buckets; tenants seen after
+	// the limit share one bucket with the default quota.
+	maxTrackedTenants = 1024
+
+	// tenantIdleTimeout is how long a bucket is kept after its last use. A
+	// bucket refills in a minute, so an idle one is as good as a new one.
+	tenantIdleTimeout = time.Minute
+)
+
+// TenantQuotaSampler caps the sampled traces per minute of each tenant, so
+// one noisy customer cannot use up the sampling budget and hide the issues of
+// the others. The tenant is read from a baggage member of the parent context.
+//
+// It wraps the configured sampler and only applies to root spans: a sampled
+// root of a tenant that is over quota is dropped, every other decision is
+// kept. Spans without the baggage member, and tenants without a quota, are
+// not limited. Each tenant has a token bucket holding one minute of budget,
+// so a tenant may burst up to its quota after an idle period.
+//
+// Baggage is set by whoever calls the service, so the tenant IDs are not
+// trusted: buckets idle for a minute are dropped, and at most
+// maxTrackedTenants tenants without an override are tracked at a time.
+type TenantQuotaSampler struct {
+	next      sdktrace.Sampler
+	key       string
+	quota     int
+	overrides map[string]int
+	now       func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tenantBucket
+	overflow  *tenantBucket
+	lastSweep time.Time
+}
+
+type tenantBucket struct {
+	perMinute float64
+	tokens    float64
+	last      time.Time
+}
+
+// NewTenantQuotaSampler wraps next with the tenant quotas of sampling:
+// TenantQuota traces per minute for every tenant, overridden per tenant by
+// TenantQuotas. An empty TenantKey reads DefaultTenantBaggageKey.
+func NewTenantQuotaSampler(next sdktrace.Sampler, sampling config.SamplingConfig) *TenantQuotaSampler {
+	key := sampling.TenantKey
+	if key == "" {
+		key = DefaultTenantBaggageKey
+	}
+	return &TenantQuotaSampler{
+		next:      next,
+		key:       key,
+		quota:     sampling.TenantQuota,
+		overrides: sampling.TenantQuotas,
+		now:       time.Now,
+		buckets:   make(map[string]*tenantBucket),
+	}
+}
+
+// ShouldSample implements sdktrace.Sampler.
+func (s *TenantQuotaSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.next.ShouldSample(p)
+	if result.Decision != sdktrace.RecordAndSample || trace.SpanContextFromContext(p.ParentContext).IsValid() {
+		return result
+	}
+	tenant := baggage.FromContext(p.ParentContext).Member(s.key).Value()
+	if tenant == "" || s.take(tenant) {
+		return result
+	}
+	return sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: result.Tracestate}
+}
+
+// Description implements sdktrace.Sampler.
+func (s *TenantQuotaSampler) Description() string {
+	return fmt.Sprintf("TenantQuotaSampler{%s,%d/min,%s}", s.key, s.quota, s.next.Description())
+}
+
+// take reports whether tenant has budget left, consuming one trace of it.
+func (s *TenantQuotaSampler) take(tenant string) bool {
+	quota, override := s.overrides[tenant]
+	if !override {
+		if s.quota <= 0 {
+			return true
+		}
+		quota = s.quota
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= tenantIdleTimeout {
+		s.sweep(now)
+	}
+	b, ok := s.buckets[tenant]
+	switch {
+	case ok:
+	case override || len(s.buckets) < maxTrackedTenants:
+		b = newTenantBucket(quota, now)
+		s.buckets[tenant] = b
+	default:
+		if s.overflow == nil {
+			s.overflow = newTenantBucket(quota, now)
+		}
+		b = s.overflow
+	}
+	return b.take(now)
+}
+
+// sweep drops the buckets unused for tenantIdleTimeout, which have refilled
+// to their quota anyway. s.mu must be held.
+func (s *TenantQuotaSampler) sweep(now time.Time) {
+	s.lastSweep = now
+	for tenant, b := range s.buckets {
+		if now.Sub(b.last) >= tenantIdleTimeout {
+			delete(s.buckets, tenant)
+		}
+	}
+	if s.overflow != nil && now.Sub(s.overflow.last) >= tenantIdleTimeout {
+		s.overflow = nil
+	}
+}
+
+func newTenantBucket(perMinute int, now time.Time) *tenantBucket {
+	return &tenantBucket{perMinute: float64(perMinute), tokens: float64(perMinute), last: now}
+}
+
+func (b *tenantBucket) take(now time.Time) bool {
+	if elapsed := now.Sub(b.last).Minutes(); elapsed > 0 {
+		b.tokens = math.Min(b.perMinute, b.tokens+elapsed*b.perMinute)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
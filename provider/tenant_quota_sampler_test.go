@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestTenantQuotaSampler(quota int, overrides map[string]int) (*TenantQuotaSampler, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	s := NewTenantQuotaSampler(sdktrace.AlwaysSample(), config.SamplingConfig{TenantQuota: quota, TenantQuotas: overrides})
+	s.now = clock.now
+	return s, clock
+}
+
+func tenantContext(t *testing.T, tenant string) context.Context {
+	t.Helper()
+	m, err := baggage.NewMember(DefaultTenantBaggageKey, tenant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := baggage.New(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return baggage.ContextWithBaggage(context.Background(), b)
+}
+
+func countTenantSampled(s sdktrace.Sampler, ctx context.Context, n int) int {
+	sampled := 0
+	for i := 0; i < n; i++ {
+		res := s.ShouldSample(sdktrace.SamplingParameters{ParentContext: ctx, TraceID: traceIDAt(0.5), Name: "GET /orders"})
+		if res.Decision == sdktrace.RecordAndSample {
+			sampled++
+		}
+	}
+	return sampled
+}
+
+func TestTenantQuotaSampler_NoisyTenantDoesNotStarveOthers(t *testing.T) {
+	s, _ := newTestTenantQuotaSampler(10, nil)
+
+	if got := countTenantSampled(s, tenantContext(t, "noisy"), 1000); got != 10 {
+		t.Errorf("noisy tenant sampled %d traces, want its quota of 10", got)
+	}
+	if got := countTenantSampled(s, tenantContext(t, "quiet"), 5); got != 5 {
+		t.Errorf("quiet tenant sampled %d traces, want all 5", got)
+	}
+}
+
+func TestTenantQuotaSampler_RefillsPerMinute(t *testing.T) {
+	s, clock := newTestTenantQuotaSampler(60, nil)
+	ctx := tenantContext(t, "acme")
+
+	countTenantSampled(s, ctx, 100)
+	clock.advance(30 * time.Second)
+	if got := countTenantSampled(s, ctx, 100); got != 30 {
+		t.Errorf("after half a minute sampled %d, want 30", got)
+	}
+	clock.advance(10 * time.Minute)
+	if got := countTenantSampled(s, ctx, 100); got != 60 {
+		t.Errorf("after an idle period sampled %d, want the 60 burst", got)
+	}
+}
+
+func TestTenantQuotaSampler_Overrides(t *testing.T) {
+	s, _ := newTestTenantQuotaSampler(0, map[string]int{"enterprise": 100, "blocked": 0})
+
+	if got := countTenantSampled(s, tenantContext(t, "enterprise"), 200); got != 100 {
+		t.Errorf("enterprise sampled %d, want 100", got)
+	}
+	if got := countTenantSampled(s, tenantContext(t, "blocked"), 10); got != 0 {
+		t.Errorf("blocked sampled %d, want 0", got)
+	}
+	// No default quota: tenants without an override are not limited.
+	if got := countTenantSampled(s, tenantContext(t, "other"), 200); got != 200 {
+		t.Errorf("other sampled %d, want 200", got)
+	}
+}
+
+func TestTenantQuotaSampler_IgnoresUntaggedAndChildSpans(t *testing.T) {
+	s, _ := newTestTenantQuotaSampler(1, nil)
+
+	if got := countTenantSampled(s, context.Background(), 50); got != 50 {
+		t.Errorf("spans without a tenant sampled %d, want 50", got)
+	}
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceIDAt(0.5),
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(tenantContext(t, "acme"), parent)
+	if got := countTenantSampled(s, ctx, 50); got != 50 {
+		t.Errorf("child spans sampled %d, want 50: quotas apply to roots only", got)
+	}
+}
+
+func TestTenantQuotaSampler_BoundsTrackedTenants(t *testing.T) {
+	s, _ := newTestTenantQuotaSampler(1, nil)
+	for i := 0; i < maxTrackedTenants+10; i++ {
+		countTenantSampled(s, tenantContext(t, "tenant-"+strconv.Itoa(i)), 1)
+	}
+	if len(s.buckets) != maxTrackedTenants {
+		t.Errorf("tracked %d tenants, want %d", len(s.buckets), maxTrackedTenants)
+	}
+}
+
+func TestTenantQuotaSampler_ForgetsIdleTenants(t *testing.T) {
+	s, clock := newTestTenantQuotaSampler(1, nil)
+	for i := 0; i < maxTrackedTenants+10; i++ {
+		countTenantSampled(s, tenantContext(t, "tenant-"+strconv.Itoa(i)), 1)
+	}
+
+	clock.advance(tenantIdleTimeout)
+	ctx := tenantContext(t, "newcomer")
+	if got := countTenantSampled(s, ctx, 3); got != 1 {
+		t.Errorf("newcomer sampled %d, want its own quota of 1", got)
+	}
+	if len(s.buckets) != 1 || s.overflow != nil {
+		t.Errorf("tracked %d tenants (overflow %v), want only the newcomer", len(s.buckets), s.overflow != nil)
+	}
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"google.golang.org/grpc/credentials"
+)
+
+var tlsVersions = map[string]uint16{
+	"":    tls.VersionTLS12,
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// BuildTLSConfig returns the client TLS configuration used by the OTLP
+// exporters when insecure mode is off: a custom CA pool, an optional client
+// certificate for mTLS, and the minimum protocol version. Without a CA file
+// the system roots are used.
+func BuildTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	minVersion, ok := tlsVersions[cfg.MinVersion]
+	if !ok {
+		return nil, fmt.Errorf("unsupported TLS min version %q", cfg.MinVersion)
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion:         minVersion,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // explicit opt-in via OTEL_EXPORTER_OTLP_TLS_SKIP_VERIFY
+	}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		tlsCfg.RootCAs = x509.NewCertPool()
+		if !tlsCfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", cfg.CAFile)
+		}
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsCfg, nil
+}
+
+// buildTLSCredentials wraps BuildTLSConfig for the gRPC exporters.
+func buildTLSCredentials(cfg config.TLSConfig) (credentials.TransportCredentials, error) {
+	tlsCfg, err := BuildTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// testPKI is a throwaway CA with a localhost server certificate and a client
+// certificate, written as PEM files.
+type testPKI struct {
+	caFile, certFile, keyFile string
+	pool                      *x509.CertPool
+	server                    tls.Certificate
+}
+
+func newTestPKI(t *testing.T) testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+
+	issue := func(serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+		key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "localhost"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, _ := x509.MarshalPKCS8PrivateKey(key)
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	}
+
+	pki := testPKI{
+		caFile:   filepath.Join(dir, "ca.pem"),
+		certFile: filepath.Join(dir, "client.pem"),
+		keyFile:  filepath.Join(dir, "client-key.pem"),
+		pool:     x509.NewCertPool(),
+	}
+	pki.pool.AddCert(caCert)
+
+	serverCert, serverKey := issue(2, x509.ExtKeyUsageServerAuth)
+	if pki.server, err = tls.X509KeyPair(serverCert, serverKey); err != nil {
+		t.Fatal(err)
+	}
+	clientCert, clientKey := issue(3, x509.ExtKeyUsageClientAuth)
+
+	for path, data := range map[string][]byte{
+		pki.caFile:   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		pki.certFile: clientCert,
+		pki.keyFile:  clientKey,
+	} {
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return pki
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	pki := newTestPKI(t)
+
+	tlsCfg, err := BuildTLSConfig(config.TLSConfig{
+		CAFile:     pki.caFile,
+		CertFile:   pki.certFile,
+		KeyFile:    pki.keyFile,
+		MinVersion: "1.3",
+	})
+	if err != nil {
+		t.Fatalf("BuildTLSConfig: %v", err)
+	}
+	if tlsCfg.MinVersion != tls.VersionTLS13 {
+		t.Errorf("MinVersion = %x, want TLS 1.3", tlsCfg.MinVersion)
+	}
+	if tlsCfg.RootCAs == nil || len(tlsCfg.Certificates) != 1 {
+		t.Errorf("RootCAs = %v, Certificates = %d, want custom pool and one client cert", tlsCfg.RootCAs, len(tlsCfg.Certificates))
+	}
+
+	defaults, err := BuildTLSConfig(config.TLSConfig{})
+	if err != nil {
+		t.Fatalf("BuildTLSConfig(defaults): %v", err)
+	}
+	if defaults.MinVersion != tls.VersionTLS12 || defaults.RootCAs != nil {
+		t.Errorf("defaults = %+v, want TLS 1.2 with system roots", defaults)
+	}
+}
+
+func TestBuildTLSConfig_Errors(t *testing.T) {
+	pki := newTestPKI(t)
+	notPEM := filepath.Join(t.TempDir(), "empty.pem")
+	_ = os.WriteFile(notPEM, []byte("not a certificate"), 0o600)
+
+	for name, cfg := range map[string]config.TLSConfig{
+		"missing CA file":     {CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"CA file without PEM": {CAFile: notPEM},
+		"cert without key":    {CertFile: pki.certFile},
+		"unknown version":     {MinVersion: "2.0"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := BuildTLSConfig(cfg); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestHTTPTraceExporter_UsesMutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+
+	var verified atomic.Bool
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified.Store(len(r.TLS.VerifiedChains) > 0)
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{pki.server},
+		ClientCAs:    pki.pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	for _, compression := range []string{CompressionNone, CompressionZstd} {
+		t.Run("compression="+compression, func(t *testing.T) {
+			verified.Store(false)
+			cfg := &config.Config{
+				Endpoint:    srv.Listener.Addr().String(),
+				Timeout:     5 * time.Second,
+				Compression: compression,
+				TLS: config.TLSConfig{
+					CAFile:   pki.caFile,
+					CertFile: pki.certFile,
+					KeyFile:  pki.keyFile,
+				},
+			}
+			exp, err := createHTTPTraceExporter(context.Background(), cfg, &logger.NoopLogger{})
+			if err != nil {
+				t.Fatalf("createHTTPTraceExporter: %v", err)
+			}
+			defer func() { _ = exp.Shutdown(context.Background()) }()
+
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+			_, span := tp.Tracer("test").Start(context.Background(), "op")
+			span.End()
+
+			if !verified.Load() {
+				t.Error("collector did not receive a request with a verified client certificate")
+			}
+		})
+	}
+}
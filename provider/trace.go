@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"time"
 
@@ -9,28 +10,174 @@ import (
 	"github.com/RodolfoBonis/go-otel-agent/logger"
 	otlptracegrpc "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	otlptracehttp "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
 )
 
+// TraceProviderOption customizes NewTraceProvider.
+type TraceProviderOption func(*traceProviderOptions)
+
+type traceProviderOptions struct {
+	adaptive    *AdaptiveSampler
+	exportStats *ExportStats
+	health      *ExporterHealth
+	blocklist   *Blocklist
+	minimal     metric.Meter
+	self        *SelfTelemetry
+	reconnector *Reconnector
+	recent      *RecentSpans
+	budget      *ResourceBudget
+	dialOptions []grpc.DialOption
+}
+
+// WithTraceGRPCDialOptions passes extra grpc.DialOptions (custom dialers,
+// keepalive, load balancing) to the gRPC trace exporter.
+func WithTraceGRPCDialOptions(opts ...grpc.DialOption) TraceProviderOption {
+	return func(o *traceProviderOptions) {
+		o.dialOptions = opts
+	}
+}
+
+// WithAdaptiveSampler uses s as the root sampler (wrapped in ParentBased)
+// instead of the configured ratio, and registers it as a span processor so it
+// can observe errors.
+func WithAdaptiveSampler(s *AdaptiveSampler) TraceProviderOption {
+	return func(o *traceProviderOptions) {
+		o.adaptive = s
+	}
+}
+
+// WithBlocklist drops spans of traces blocked by b before they are batched.
+func WithBlocklist(b *Blocklist) TraceProviderOption {
+	return func(o *traceProviderOptions) {
+		o.blocklist = b
+	}
+}
+
+// WithMinimalSpans keeps recording entry spans that the sampler drops, without
+// exporting them, and records the duration of every request in the
+// MinimalSpanMetric histogram of meter.
+func WithMinimalSpans(meter metric.Meter) TraceProviderOption {
+	return func(o *traceProviderOptions) {
+		o.minimal = meter
+	}
+}
+
 // NewTraceProvider creates a TracerProvider with OTLP exporter.
 // Fixes: always wraps sampler in ParentBased, wires span limits and retry config.
-func NewTraceProvider(cfg *config.Config, res *resource.Resource, log logger.Logger) (*sdktrace.TracerProvider, error) {
+func NewTraceProvider(cfg *config.Config, res *resource.Resource, log logger.Logger, opts ...TraceProviderOption) (*sdktrace.TracerProvider, error) {
 	ctx := context.Background()
 
-	exporter, err := createTraceExporter(ctx, cfg, log)
+	var o traceProviderOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	res, err := SignalResource(res, cfg, SignalTraces)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build traces resource: %w", err)
+	}
+
+	exporter, err := createTraceExporter(ctx, cfg, log, o.dialOptions...)
 	if err != nil {
 		return nil, err
 	}
+	if o.reconnector != nil {
+		swap := newSwappable(exporter, func() (sdktrace.SpanExporter, error) {
+			return createTraceExporter(ctx, cfg, log, o.dialOptions...)
+		})
+		exporter = reconnectingSpanExporter{swap}
+		o.reconnector.register(SignalTraces, swap.reconnect)
+	}
+	if cfg.Features.DebugMode && cfg.SignalExporter(SignalTraces).Protocol != ProtocolStdout {
+		exporter = NewInspectingSpanExporter(exporter, log)
+	}
+	if o.exportStats != nil {
+		exporter = statsSpanExporter{SpanExporter: exporter, stats: o.exportStats}
+	}
+	if o.health != nil {
+		exporter = healthSpanExporter{SpanExporter: exporter, health: o.health}
+	}
+	var queue *queueUsage
+	if o.self != nil {
+		queue = o.self.trackQueue(SignalTraces, cfg.Traces.QueueSize)
+		exporter = selfTelemetrySpanExporter{SpanExporter: exporter, st: o.self, queue: queue}
+	}
+	// PII scrubbing runs on the finished span, outermost, so attributes set
+	// after Start are covered and the debug exporters only see scrubbed data.
+	if cfg.Scrub.Enabled {
+		processor := NewScrubProcessor(cfg.Scrub)
+		processor.self = o.self
+		exporter = &scrubSpanExporter{SpanExporter: exporter, sp: processor}
+	}
+
+	sampler := createSampler(cfg.Traces.Sampling)
+	if o.adaptive != nil {
+		sampler = sdktrace.ParentBased(o.adaptive)
+	}
+	sampler = rootSamplerOverride{next: sampler}
+	if o.budget != nil {
+		sampler = newBudgetSampler(sampler, o.budget)
+	}
+	if s := cfg.Traces.Sampling; s.TenantQuota > 0 || len(s.TenantQuotas) > 0 {
+		sampler = NewTenantQuotaSampler(sampler, s)
+	}
+	if o.minimal != nil {
+		sampler = NewMinimalSpanSampler(sampler)
+	}
+	if o.self != nil {
+		sampler = selfTelemetrySampler{next: sampler, st: o.self}
+	}
 
-	opts := []sdktrace.TracerProviderOption{
-		sdktrace.WithBatcher(exporter,
+	var batcher sdktrace.SpanProcessor
+	if cfg.Traces.DynamicBatching.Enabled {
+		batcher = NewDynamicBatchProcessor(exporter, cfg.Traces.DynamicBatching,
+			cfg.Traces.QueueSize, cfg.Traces.MaxExportBatch, cfg.Traces.BatchTimeout)
+	} else {
+		batcher = sdktrace.NewBatchSpanProcessor(exporter,
 			sdktrace.WithBatchTimeout(cfg.Traces.BatchTimeout),
 			sdktrace.WithMaxExportBatchSize(cfg.Traces.MaxExportBatch),
 			sdktrace.WithMaxQueueSize(cfg.Traces.QueueSize),
-		),
+		)
+	}
+	if o.exportStats != nil {
+		batcher = statsSpanProcessor{SpanProcessor: batcher, stats: o.exportStats}
+	}
+	if queue != nil {
+		batcher = queueSpanProcessor{SpanProcessor: batcher, queue: queue}
+	}
+	if cfg.Traces.Compression.Enabled {
+		batcher = NewSpanCompressionProcessor(batcher, cfg.Traces.Compression)
+	}
+	if o.blocklist != nil {
+		batcher = NewBlocklistSpanProcessor(batcher, o.blocklist)
+	}
+
+	var tpOpts []sdktrace.TracerProviderOption
+	// Processors run OnStart in registration order: baggage attributes must
+	// be in place before the blocklist looks at them.
+	if len(cfg.Traces.BaggageKeys) > 0 {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(NewBaggageSpanProcessor(cfg.Traces.BaggageKeys)))
+	}
+	tpOpts = append(tpOpts,
+		sdktrace.WithSpanProcessor(batcher),
 		sdktrace.WithResource(res),
-		sdktrace.WithSampler(createSampler(cfg.Traces.Sampling)),
+		sdktrace.WithSampler(sampler),
+	)
+
+	if o.adaptive != nil {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(o.adaptive))
+	}
+	if o.minimal != nil {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(NewMinimalSpanProcessor(o.minimal)))
+	}
+	if o.recent != nil {
+		if cfg.Scrub.Enabled {
+			o.recent.scrub = NewScrubProcessor(cfg.Scrub)
+		}
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(o.recent))
 	}
 
 	// Wire span limits using NewSpanLimits() as base to preserve safe defaults
@@ -43,50 +190,64 @@ func NewTraceProvider(cfg *config.Config, res *resource.Resource, log logger.Log
 		limits.LinkCountLimit = cfg.Traces.MaxLinksPerSpan
 		limits.AttributePerEventCountLimit = cfg.Traces.MaxAttributesPerSpan
 		limits.AttributePerLinkCountLimit = cfg.Traces.MaxAttributesPerSpan
-		opts = append(opts, sdktrace.WithRawSpanLimits(limits))
+		tpOpts = append(tpOpts, sdktrace.WithRawSpanLimits(limits))
 	}
 
-	// Add PII scrubbing processor if enabled
-	if cfg.Scrub.Enabled {
-		processor := NewScrubProcessor(cfg.Scrub)
-		opts = append(opts, sdktrace.WithSpanProcessor(processor))
-	}
-
-	return sdktrace.NewTracerProvider(opts...), nil
+	return sdktrace.NewTracerProvider(tpOpts...), nil
 }
 
-func createTraceExporter(ctx context.Context, cfg *config.Config, log logger.Logger) (sdktrace.SpanExporter, error) {
-	protocol := cfg.ExporterProtocol
+func createTraceExporter(ctx context.Context, cfg *config.Config, log logger.Logger, dialOpts ...grpc.DialOption) (sdktrace.SpanExporter, error) {
+	if cfg.Features.DryRun {
+		return dryRunSpanExporter{log: log}, nil
+	}
+	protocol := cfg.SignalExporter(SignalTraces).Protocol
 	if protocol == "" {
 		protocol = "grpc"
 	}
 
 	switch protocol {
 	case "grpc":
-		return createGRPCTraceExporter(ctx, cfg, log)
+		return createGRPCTraceExporter(ctx, cfg, log, dialOpts...)
 	case "http", "http/protobuf":
 		return createHTTPTraceExporter(ctx, cfg, log)
+	case ProtocolStdout:
+		return createStdoutTraceExporter(ctx, cfg, log)
 	default:
-		return nil, fmt.Errorf("unsupported OTLP protocol: %s (use 'grpc' or 'http')", protocol)
+		if factory, ok := lookupFactory(exporterFactories.traces, protocol); ok {
+			return factory(ctx, exporterSettings(cfg, SignalTraces, log))
+		}
+		return nil, fmt.Errorf("unsupported OTLP protocol: %s (use 'grpc', 'http', 'stdout' or a registered exporter factory)", protocol)
 	}
 }
 
-func createGRPCTraceExporter(ctx context.Context, cfg *config.Config, log logger.Logger) (sdktrace.SpanExporter, error) {
+func createGRPCTraceExporter(ctx context.Context, cfg *config.Config, log logger.Logger, dialOpts ...grpc.DialOption) (sdktrace.SpanExporter, error) {
+	exp := cfg.SignalExporter(SignalTraces)
+	host, _ := splitEndpoint(exp.Endpoint)
 	opts := []otlptracegrpc.Option{
-		otlptracegrpc.WithEndpoint(cfg.Endpoint),
+		otlptracegrpc.WithEndpoint(host),
 		otlptracegrpc.WithTimeout(cfg.Timeout),
 	}
 
 	if cfg.Insecure {
 		opts = append(opts, otlptracegrpc.WithInsecure())
+	} else {
+		creds, err := buildTLSCredentials(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(creds))
 	}
 
-	if cfg.Compression != "" && cfg.Compression != "none" {
-		opts = append(opts, otlptracegrpc.WithCompressor(cfg.Compression))
+	compression, err := NormalizeCompression("grpc", cfg.Compression)
+	if err != nil {
+		return nil, err
+	}
+	if compression != CompressionNone {
+		opts = append(opts, otlptracegrpc.WithCompressor(compression))
 	}
 
 	// Wire auth headers
-	headers := cfg.ResolvedAuthHeaders()
+	headers := exp.Headers
 	if len(headers) > 0 {
 		opts = append(opts, otlptracegrpc.WithHeaders(headers))
 	}
@@ -101,33 +262,56 @@ func createGRPCTraceExporter(ctx context.Context, cfg *config.Config, log logger
 		}))
 	}
 
+	if len(dialOpts) > 0 {
+		opts = append(opts, otlptracegrpc.WithDialOption(dialOpts...))
+	}
+
 	exporter, err := otlptracegrpc.New(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create OTLP gRPC trace exporter: %w", err)
 	}
 
 	log.Info(ctx, "OTLP trace exporter initialized", logger.Fields{
-		"protocol": "grpc", "endpoint": cfg.Endpoint,
+		"protocol": "grpc", "endpoint": exp.Endpoint,
 	})
 
 	return exporter, nil
 }
 
 func createHTTPTraceExporter(ctx context.Context, cfg *config.Config, log logger.Logger) (sdktrace.SpanExporter, error) {
+	exp := cfg.SignalExporter(SignalTraces)
+	host, path := splitEndpoint(exp.Endpoint)
 	opts := []otlptracehttp.Option{
-		otlptracehttp.WithEndpoint(cfg.Endpoint),
+		otlptracehttp.WithEndpoint(host),
 		otlptracehttp.WithTimeout(cfg.Timeout),
 	}
+	if path != "" {
+		opts = append(opts, otlptracehttp.WithURLPath(path))
+	}
 
+	var tlsCfg *tls.Config
 	if cfg.Insecure {
 		opts = append(opts, otlptracehttp.WithInsecure())
+	} else {
+		var err error
+		if tlsCfg, err = BuildTLSConfig(cfg.TLS); err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsCfg))
 	}
 
-	if cfg.Compression != "" && cfg.Compression != "none" {
+	compression, err := NormalizeCompression("http", cfg.Compression)
+	if err != nil {
+		return nil, err
+	}
+	switch compression {
+	case CompressionGzip:
 		opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	case CompressionZstd:
+		opts = append(opts, otlptracehttp.WithHTTPClient(newZstdHTTPClient(cfg.Timeout, tlsCfg)))
 	}
 
-	headers := cfg.ResolvedAuthHeaders()
+	headers := exp.Headers
 	if len(headers) > 0 {
 		opts = append(opts, otlptracehttp.WithHeaders(headers))
 	}
@@ -147,7 +331,7 @@ func createHTTPTraceExporter(ctx context.Context, cfg *config.Config, log logger
 	}
 
 	log.Info(ctx, "OTLP trace exporter initialized", logger.Fields{
-		"protocol": "http", "endpoint": cfg.Endpoint,
+		"protocol": "http", "endpoint": exp.Endpoint,
 	})
 
 	return exporter, nil
@@ -165,6 +349,9 @@ func createSampler(sampling config.SamplingConfig) sdktrace.Sampler {
 		return sdktrace.NeverSample()
 	case "ratio", "traceidratio":
 		rootSampler = sdktrace.TraceIDRatioBased(sampling.Rate)
+	case "rate_limited":
+		// The sampler argument is a spans-per-second budget, not a ratio.
+		rootSampler = NewRateLimitingSampler(sampling.Rate)
 	default:
 		// Default: parent_based with ratio
 		rootSampler = sdktrace.TraceIDRatioBased(sampling.Rate)
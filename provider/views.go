@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+)
+
+// exponentialHistogram is the SDK's default base-2 exponential aggregation.
+var exponentialHistogram = metric.AggregationBase2ExponentialHistogram{MaxSize: 160, MaxScale: 20}
+
+// httpLatencyHistograms and dbLatencyHistograms are the well-known duration
+// instruments that get the configured HTTP or DB bucket boundaries instead of
+// the SDK defaults. The DB names follow the semantic conventions, so any
+// database instrumentation that records them shares one bucket layout.
+var (
+	httpLatencyHistograms = []string{
+		"http.server.request.duration",
+		"http.server.request.queue.duration",
+		"http.server.request.stage.duration",
+		"http.client.request.duration",
+	}
+	dbLatencyHistograms = []string{
+		"db.client.operation.duration",
+		"db.client.duration",
+	}
+)
+
+// metricViews returns the views NewMetricProvider installs for cfg.
+//
+// Everything is decided by a single view: the SDK creates one stream per
+// matching view, so two views matching the same instrument would export it
+// twice.
+func metricViews(cfg *config.Config) []metric.View {
+	card := cfg.Metrics.Cardinality
+	boundaries := make(map[string][]float64)
+	if b := cfg.Metrics.HTTPLatencyBoundaries; len(b) > 0 {
+		for _, name := range httpLatencyHistograms {
+			boundaries[name] = b
+		}
+	}
+	if b := cfg.Metrics.DBLatencyBoundaries; len(b) > 0 {
+		for _, name := range dbLatencyHistograms {
+			boundaries[name] = b
+		}
+	}
+	if !card.UseExponentialHist && len(card.HistogramAggregation) == 0 && len(card.DropAttributes) == 0 && len(boundaries) == 0 {
+		return nil
+	}
+
+	// Exact names win over globs; globs are tried in a stable order.
+	patterns := make([]string, 0, len(card.HistogramAggregation))
+	for p := range card.HistogramAggregation {
+		patterns = append(patterns, p)
+	}
+	slices.Sort(patterns)
+
+	var filter attribute.Filter
+	if len(card.DropAttributes) > 0 {
+		filter = attribute.NewDenyKeysFilter(toKeys(card.DropAttributes)...)
+	}
+
+	return []metric.View{func(inst metric.Instrument) (metric.Stream, bool) {
+		var aggregation metric.Aggregation
+		if inst.Kind == metric.InstrumentKindHistogram {
+			exponential := card.UseExponentialHist && isDurationHistogram(inst)
+			if agg, ok := histogramOverride(card.HistogramAggregation, patterns, inst.Name); ok {
+				exponential = agg == config.HistogramExponential
+			}
+			if exponential {
+				aggregation = exponentialHistogram
+			} else if b, ok := boundaries[inst.Name]; ok {
+				aggregation = metric.AggregationExplicitBucketHistogram{Boundaries: b}
+			}
+		}
+		if aggregation == nil && filter == nil {
+			return metric.Stream{}, false
+		}
+		return metric.Stream{
+			Name:            inst.Name,
+			Description:     inst.Description,
+			Unit:            inst.Unit,
+			Aggregation:     aggregation,
+			AttributeFilter: filter,
+		}, true
+	}}
+}
+
+func toKeys(names []string) []attribute.Key {
+	keys := make([]attribute.Key, len(names))
+	for i, name := range names {
+		keys[i] = attribute.Key(name)
+	}
+	return keys
+}
+
+func histogramOverride(overrides map[string]string, patterns []string, name string) (string, bool) {
+	if agg, ok := overrides[name]; ok {
+		return agg, true
+	}
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return overrides[p], true
+		}
+	}
+	return "", false
+}
+
+// isDurationHistogram reports whether inst measures time, by its unit or,
+// for instruments without one, by its name.
+func isDurationHistogram(inst metric.Instrument) bool {
+	switch inst.Unit {
+	case "s", "ms", "us", "ns":
+		return true
+	}
+	name := strings.ToLower(inst.Name)
+	return strings.Contains(name, "duration") || strings.Contains(name, "latency")
+}
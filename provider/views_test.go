@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// histogramKinds records one value in each named histogram and returns
+// whether each was exported as an exponential histogram.
+func histogramKinds(t *testing.T, cfg *config.Config, histograms map[string]string) map[string]bool {
+	t.Helper()
+	reader := metric.NewManualReader()
+	mp := metric.NewMeterProvider(metric.WithReader(reader), metric.WithView(metricViews(cfg)...))
+	meter := mp.Meter("test")
+	for name, unit := range histograms {
+		h, err := meter.Float64Histogram(name, otelmetric.WithUnit(unit))
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.Record(context.Background(), 0.25)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	kinds := make(map[string]bool)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if _, dup := kinds[m.Name]; dup {
+			t.Errorf("%s exported more than once", m.Name)
+		}
+		_, kinds[m.Name] = m.Data.(metricdata.ExponentialHistogram[float64])
+	}
+	return kinds
+}
+
+func TestMetricViews_ExponentialDurationHistograms(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Metrics.Cardinality.UseExponentialHist = true
+
+	kinds := histogramKinds(t, cfg, map[string]string{
+		"db.client.operation.duration": "s",
+		"queue.wait_latency":           "",
+		"batch.size":                   "{item}",
+	})
+	if !kinds["db.client.operation.duration"] || !kinds["queue.wait_latency"] {
+		t.Errorf("duration histograms = %v, want exponential", kinds)
+	}
+	if kinds["batch.size"] {
+		t.Error("batch.size is not a duration and should keep explicit buckets")
+	}
+}
+
+func TestMetricViews_PerInstrumentOverrides(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Metrics.Cardinality.UseExponentialHist = true
+	cfg.Metrics.Cardinality.HistogramAggregation = map[string]string{
+		"http.server.request.duration": config.HistogramExplicit,
+		"batch.*":                      config.HistogramExponential,
+	}
+
+	kinds := histogramKinds(t, cfg, map[string]string{
+		"http.server.request.duration": "s",
+		"db.client.operation.duration": "s",
+		"batch.size":                   "{item}",
+	})
+	if kinds["http.server.request.duration"] {
+		t.Error("http.server.request.duration should keep explicit buckets")
+	}
+	if !kinds["db.client.operation.duration"] || !kinds["batch.size"] {
+		t.Errorf("kinds = %v, want DB latency and batch.size exponential", kinds)
+	}
+}
+
+func TestMetricViews_NoneByDefault(t *testing.T) {
+	if views := metricViews(&config.Config{}); len(views) != 0 {
+		t.Errorf("got %d views, want none", len(views))
+	}
+}
+
+func TestMetricViews_LatencyBoundaries(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Metrics.HTTPLatencyBoundaries = []float64{0.1, 1}
+	cfg.Metrics.DBLatencyBoundaries = []float64{0.01, 0.1, 1}
+
+	reader := metric.NewManualReader()
+	meter := metric.NewMeterProvider(metric.WithReader(reader), metric.WithView(metricViews(cfg)...)).Meter("test")
+	for _, name := range []string{"http.server.request.duration", "db.client.operation.duration", "batch.size"} {
+		h, err := meter.Float64Histogram(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.Record(context.Background(), 0.25)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	bounds := make(map[string]int)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		bounds[m.Name] = len(m.Data.(metricdata.Histogram[float64]).DataPoints[0].Bounds)
+	}
+	if bounds["http.server.request.duration"] != 2 {
+		t.Errorf("HTTP histogram has %d bounds, want 2", bounds["http.server.request.duration"])
+	}
+	if bounds["db.client.operation.duration"] != 3 {
+		t.Errorf("DB histogram has %d bounds, want 3", bounds["db.client.operation.duration"])
+	}
+	if bounds["batch.size"] != 15 {
+		t.Errorf("batch.size has %d bounds, want the 15 SDK defaults", bounds["batch.size"])
+	}
+}
+
+func TestMetricViews_ExponentialWinsOverBoundaries(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Metrics.HTTPLatencyBoundaries = []float64{0.1, 1}
+	cfg.Metrics.Cardinality.UseExponentialHist = true
+
+	kinds := histogramKinds(t, cfg, map[string]string{"http.server.request.duration": "s"})
+	if !kinds["http.server.request.duration"] {
+		t.Error("exponential aggregation should take precedence over configured boundaries")
+	}
+}
@@ -0,0 +1,34 @@
+package otelagent
+
+import (
+	"runtime"
+	"strings"
+)
+
+// defaultScope is used when the caller's package cannot be determined.
+const defaultScope = "github.com/RodolfoBonis/go-otel-agent"
+
+// callerScope returns the import path of the package of the function skip
+// frames above callerScope (1 = the direct caller).
+func callerScope(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return defaultScope
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return defaultScope
+	}
+	return packageFromFuncName(fn.Name())
+}
+
+// packageFromFuncName extracts the package path from a fully-qualified
+// function name such as "github.com/org/repo/pkg.(*T).Method" or
+// "main.main.func1".
+func packageFromFuncName(name string) string {
+	lastSlash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[lastSlash+1:], "."); dot >= 0 {
+		return name[:lastSlash+1+dot]
+	}
+	return name
+}
@@ -0,0 +1,35 @@
+package otelagent
+
+import "testing"
+
+func TestPackageFromFuncName(t *testing.T) {
+	tests := map[string]string{
+		"github.com/org/repo/pkg.(*Service).Handle": "github.com/org/repo/pkg",
+		"github.com/org/repo/pkg.Func.func1":        "github.com/org/repo/pkg",
+		"github.com/org/repo.v2/pkg.Func":           "github.com/org/repo.v2/pkg",
+		"main.main":                                 "main",
+	}
+
+	for in, want := range tests {
+		if got := packageFromFuncName(in); got != want {
+			t.Errorf("packageFromFuncName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCallerScope_ReturnsCallingPackage(t *testing.T) {
+	if got := callerScope(1); got != "github.com/RodolfoBonis/go-otel-agent" {
+		t.Errorf("callerScope(1) = %q, want this package", got)
+	}
+}
+
+func TestAgent_TracerAndMeter_NeverNil(t *testing.T) {
+	agent := newTestAgent("test-scope")
+
+	if agent.Tracer() == nil || agent.Tracer("explicit") == nil {
+		t.Error("Tracer returned nil")
+	}
+	if agent.Meter() == nil || agent.Meter("explicit") == nil {
+		t.Error("Meter returned nil")
+	}
+}
@@ -0,0 +1,156 @@
+package scrub
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+)
+
+// Names of the built-in value detectors, as listed in ScrubConfig.Detectors
+// and OTEL_PII_DETECTORS.
+const (
+	DetectorJWT        = "jwt"
+	DetectorBearer     = "bearer"
+	DetectorEmail      = "email"
+	DetectorCreditCard = "credit_card"
+	DetectorSSN        = "ssn"
+	DetectorCPF        = "cpf"
+	DetectorPrivateIP  = "private_ip"
+)
+
+// Detector finds one kind of sensitive value in text, whatever key or
+// attribute the text is under.
+type Detector struct {
+	name  string
+	re    *regexp.Regexp
+	valid func(match string) bool // nil accepts every match
+}
+
+// builtinDetectors are applied in this order, tokens first, so that a
+// broader detector does not consume part of a narrower one's match.
+var builtinDetectors = []*Detector{
+	{name: DetectorJWT, re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)},
+	{name: DetectorBearer, re: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]+=*`)},
+	{name: DetectorEmail, re: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)},
+	{name: DetectorCreditCard, re: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), valid: validCardNumber},
+	{name: DetectorSSN, re: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`), valid: validSSN},
+	{name: DetectorCPF, re: regexp.MustCompile(`\b\d{3}\.?\d{3}\.?\d{3}-?\d{2}\b`), valid: validCPF},
+	{name: DetectorPrivateIP, re: regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`), valid: privateIPv4},
+}
+
+// Name returns the detector's name.
+func (d *Detector) Name() string {
+	return d.name
+}
+
+// Redact replaces every value d finds in v with redacted.
+func (d *Detector) Redact(v, redacted string) string {
+	if d.valid == nil {
+		return d.re.ReplaceAllLiteralString(v, redacted)
+	}
+	return d.re.ReplaceAllStringFunc(v, func(match string) string {
+		if d.valid(match) {
+			return redacted
+		}
+		return match
+	})
+}
+
+// Detectors returns the built-in detectors named in names, in the order
+// they are applied, and one error per unknown name.
+func Detectors(names []string) ([]*Detector, []error) {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var detectors []*Detector
+	for _, d := range builtinDetectors {
+		if wanted[d.name] {
+			detectors = append(detectors, d)
+			delete(wanted, d.name)
+		}
+	}
+
+	var unknown []error
+	for _, name := range names {
+		if wanted[name] {
+			unknown = append(unknown, fmt.Errorf("unknown PII detector %q", name))
+			delete(wanted, name)
+		}
+	}
+	return detectors, unknown
+}
+
+// RedactDetected replaces every value found by detectors in v with
+// redacted.
+func RedactDetected(v string, detectors []*Detector, redacted string) string {
+	for _, d := range detectors {
+		v = d.Redact(v, redacted)
+	}
+	return v
+}
+
+// validCardNumber reports whether the digits of s pass the Luhn check.
+func validCardNumber(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
+
+// validSSN rejects the area, group and serial numbers never issued.
+func validSSN(s string) bool {
+	area, group, serial := s[0:3], s[4:6], s[7:11]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
+
+// validCPF checks the two verification digits of a Brazilian CPF.
+func validCPF(s string) bool {
+	digits := make([]int, 0, 11)
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			digits = append(digits, int(s[i]-'0'))
+		}
+	}
+	if len(digits) != 11 {
+		return false
+	}
+	same := true
+	for _, d := range digits[1:] {
+		same = same && d == digits[0]
+	}
+	if same {
+		return false
+	}
+	for check := 9; check <= 10; check++ {
+		sum := 0
+		for i := 0; i < check; i++ {
+			sum += digits[i] * (check + 1 - i)
+		}
+		if d := sum * 10 % 11 % 10; d != digits[check] {
+			return false
+		}
+	}
+	return true
+}
+
+// privateIPv4 reports whether s is an IPv4 address in a private range
+// (10/8, 172.16/12, 192.168/16).
+func privateIPv4(s string) bool {
+	ip := net.ParseIP(s)
+	return ip != nil && ip.IsPrivate()
+}
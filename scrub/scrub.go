@@ -0,0 +1,202 @@
+// Package scrub exposes the agent's PII scrubbing rules as a public utility so
+// application code can redact arbitrary payloads (log fields, event data,
+// span attributes) with the exact same sensitive-key and pattern rules used
+// by the span and HTTP pipelines. The span pipeline matches keys and values
+// with a Scrubber, so the rules are defined here once.
+package scrub
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+)
+
+// Scrubber redacts sensitive keys and values using a ScrubConfig.
+// Unlike the automatic pipelines, explicit calls always apply the configured
+// rules, regardless of ScrubConfig.Enabled.
+type Scrubber struct {
+	sensitiveKeys    map[string]struct{}
+	compiledPatterns []*regexp.Regexp
+	invalidPatterns  []error
+	detectors        []*Detector
+	redacted         string
+}
+
+// New creates a Scrubber from the given scrub configuration. Invalid
+// patterns are skipped, matching the span processor behavior, and reported
+// by InvalidPatterns.
+func New(cfg config.ScrubConfig) *Scrubber {
+	s := &Scrubber{
+		sensitiveKeys: make(map[string]struct{}, len(cfg.SensitiveKeys)),
+		redacted:      cfg.RedactedValue,
+	}
+	if s.redacted == "" {
+		s.redacted = config.RedactedValue
+	}
+
+	for _, key := range cfg.SensitiveKeys {
+		s.sensitiveKeys[key] = struct{}{}
+	}
+	s.compiledPatterns, s.invalidPatterns = config.CompilePatterns(cfg.SensitivePatterns)
+	s.detectors, _ = Detectors(cfg.Detectors)
+
+	return s
+}
+
+// InvalidPatterns returns one error per configured pattern that failed to
+// compile and is therefore not applied.
+func (s *Scrubber) InvalidPatterns() []error {
+	return s.invalidPatterns
+}
+
+// IsSensitiveKey reports whether key matches a sensitive key exactly or
+// any sensitive pattern (matched against the lowercased key).
+func (s *Scrubber) IsSensitiveKey(key string) bool {
+	if _, ok := s.sensitiveKeys[key]; ok {
+		return true
+	}
+
+	lowerKey := strings.ToLower(key)
+	for _, re := range s.compiledPatterns {
+		if re.MatchString(lowerKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RedactedValue returns the value that replaces redacted data.
+func (s *Scrubber) RedactedValue() string {
+	return s.redacted
+}
+
+// HasDetectors reports whether any value detector is configured.
+func (s *Scrubber) HasDetectors() bool {
+	return len(s.detectors) > 0
+}
+
+// RedactDetected redacts the values found by the configured detectors in v,
+// leaving pattern matches alone: patterns apply to keys, and to free-form
+// text only through String.
+func (s *Scrubber) RedactDetected(v string) string {
+	return RedactDetected(v, s.detectors, s.redacted)
+}
+
+// String redacts every sensitive pattern match and every value found by the
+// configured detectors in free-form text, the same way captured HTTP bodies
+// are scrubbed.
+func (s *Scrubber) String(v string) string {
+	for _, re := range s.compiledPatterns {
+		v = re.ReplaceAllString(v, s.redacted)
+	}
+	return s.RedactDetected(v)
+}
+
+// Map returns a copy of m with values of sensitive keys replaced by the
+// redacted value, and values found by the configured detectors redacted in
+// the other strings. Nested maps and slices are scrubbed recursively; the
+// input map is never modified.
+func (s *Scrubber) Map(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[string]any, len(m))
+	for k, v := range m {
+		if s.IsSensitiveKey(k) {
+			result[k] = s.redacted
+			continue
+		}
+		result[k] = s.value(v)
+	}
+	return result
+}
+
+// Struct converts v to a map using its JSON representation (honoring json
+// tags) and scrubs it with Map. v must encode to a JSON object.
+func (s *Scrubber) Struct(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("scrub: failed to encode value: %w", err)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("scrub: value of type %T is not a JSON object: %w", v, err)
+	}
+
+	return s.Map(m), nil
+}
+
+func (s *Scrubber) value(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return s.Map(val)
+	case map[string]string:
+		result := make(map[string]any, len(val))
+		for k, sv := range val {
+			result[k] = sv
+		}
+		return s.Map(result)
+	case []any:
+		result := make([]any, len(val))
+		for i, item := range val {
+			result[i] = s.value(item)
+		}
+		return result
+	case string:
+		return s.RedactDetected(val)
+	default:
+		return v
+	}
+}
+
+var (
+	// defaultScrubber applies the default ScrubConfig until the agent
+	// replaces it during Init.
+	defaultScrubber = New(config.Defaults().Scrub)
+	defaultMu       sync.RWMutex
+)
+
+// SetDefault replaces the Scrubber used by the package-level functions.
+// The agent calls this during Init with its ScrubConfig.
+func SetDefault(s *Scrubber) {
+	if s == nil {
+		return
+	}
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultScrubber = s
+}
+
+// Default returns the Scrubber used by the package-level functions.
+func Default() *Scrubber {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultScrubber
+}
+
+// Map scrubs m with the default Scrubber.
+func Map(m map[string]any) map[string]any {
+	return Default().Map(m)
+}
+
+// Struct scrubs v with the default Scrubber.
+func Struct(v any) (map[string]any, error) {
+	return Default().Struct(v)
+}
+
+// String scrubs free-form text with the default Scrubber.
+func String(v string) string {
+	return Default().String(v)
+}
+
+// IsSensitiveKey reports whether key is sensitive under the default Scrubber.
+func IsSensitiveKey(key string) bool {
+	return Default().IsSensitiveKey(key)
+}
@@ -0,0 +1,157 @@
+package scrub
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RodolfoBonis/go-otel-agent/config"
+)
+
+func testConfig() config.ScrubConfig {
+	return config.ScrubConfig{
+		SensitiveKeys:     []string{"password", "ssn"},
+		SensitivePatterns: []string{".*token.*"},
+		RedactedValue:     "***",
+	}
+}
+
+func TestScrubber_IsSensitiveKey(t *testing.T) {
+	s := New(testConfig())
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"password", true},
+		{"ssn", true},
+		{"access_token", true},
+		{"Refresh-Token", true}, // patterns match the lowercased key
+		{"username", false},
+	}
+
+	for _, tt := range tests {
+		if got := s.IsSensitiveKey(tt.key); got != tt.want {
+			t.Errorf("IsSensitiveKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestScrubber_InvalidPatterns(t *testing.T) {
+	cfg := testConfig()
+	cfg.SensitivePatterns = append(cfg.SensitivePatterns, "(unclosed")
+	s := New(cfg)
+
+	invalid := s.InvalidPatterns()
+	if len(invalid) != 1 || !strings.Contains(invalid[0].Error(), "(unclosed") {
+		t.Errorf("InvalidPatterns = %v, want one error naming the pattern", invalid)
+	}
+	if !s.IsSensitiveKey("access_token") {
+		t.Error("valid patterns should still apply")
+	}
+}
+
+func TestScrubber_Map_RedactsNestedValues(t *testing.T) {
+	s := New(testConfig())
+	in := map[string]any{
+		"user":     "alice",
+		"password": "hunter2",
+		"auth": map[string]any{
+			"access_token": "abc",
+			"scope":        "read",
+		},
+		"items": []any{
+			map[string]any{"ssn": "123-45-6789", "id": 1},
+		},
+	}
+
+	out := s.Map(in)
+
+	if out["user"] != "alice" {
+		t.Errorf("user = %v, want alice", out["user"])
+	}
+	if out["password"] != "***" {
+		t.Errorf("password = %v, want redacted", out["password"])
+	}
+	auth := out["auth"].(map[string]any)
+	if auth["access_token"] != "***" || auth["scope"] != "read" {
+		t.Errorf("nested map not scrubbed correctly: %v", auth)
+	}
+	item := out["items"].([]any)[0].(map[string]any)
+	if item["ssn"] != "***" || item["id"] != 1 {
+		t.Errorf("slice item not scrubbed correctly: %v", item)
+	}
+
+	// Input must not be mutated.
+	if in["password"] != "hunter2" {
+		t.Error("Map mutated the input map")
+	}
+}
+
+func TestScrubber_Struct_UsesJSONTags(t *testing.T) {
+	type payload struct {
+		Name     string `json:"name"`
+		Password string `json:"password"`
+		APIToken string `json:"api_token"`
+		Internal string `json:"-"`
+	}
+	s := New(testConfig())
+
+	out, err := s.Struct(payload{Name: "bob", Password: "x", APIToken: "y", Internal: "z"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out["name"] != "bob" {
+		t.Errorf("name = %v, want bob", out["name"])
+	}
+	if out["password"] != "***" || out["api_token"] != "***" {
+		t.Errorf("sensitive fields not redacted: %v", out)
+	}
+	if _, ok := out["Internal"]; ok {
+		t.Error("json:\"-\" field should be omitted")
+	}
+}
+
+func TestScrubber_Struct_NonObjectReturnsError(t *testing.T) {
+	s := New(testConfig())
+
+	if _, err := s.Struct([]string{"a"}); err == nil {
+		t.Error("expected error for non-object value")
+	}
+}
+
+func TestScrubber_String_RedactsPatterns(t *testing.T) {
+	s := New(config.ScrubConfig{SensitivePatterns: []string{`\d{3}-\d{2}-\d{4}`}})
+
+	got := s.String("ssn is 123-45-6789")
+	if got != "ssn is [REDACTED]" {
+		t.Errorf("String = %q, want %q", got, "ssn is [REDACTED]")
+	}
+}
+
+func TestSetDefault_ReplacesPackageScrubber(t *testing.T) {
+	prev := Default()
+	defer SetDefault(prev)
+
+	SetDefault(New(config.ScrubConfig{SensitiveKeys: []string{"card"}}))
+
+	out := Map(map[string]any{"card": "4111", "password": "x"})
+	if out["card"] != "[REDACTED]" {
+		t.Errorf("card = %v, want redacted", out["card"])
+	}
+	if out["password"] != "x" {
+		t.Errorf("password = %v, want untouched by custom default", out["password"])
+	}
+}
+
+func TestDefault_FollowsTheDefaultScrubConfig(t *testing.T) {
+	cfg := config.Defaults().Scrub
+	for _, key := range cfg.SensitiveKeys {
+		if !Default().IsSensitiveKey(key) {
+			t.Errorf("default key %q is not sensitive", key)
+		}
+	}
+	if got := Default().RedactedValue(); got != cfg.RedactedValue {
+		t.Errorf("RedactedValue() = %q, want %q", got, cfg.RedactedValue)
+	}
+}
@@ -0,0 +1,201 @@
+package scrub
+
+import (
+	"strings"
+)
+
+// SQLDialect selects the quoting rules SQL uses to tell literals from
+// identifiers.
+type SQLDialect string
+
+// Dialects understood by SQL. The zero value is SQLGeneric.
+const (
+	// SQLGeneric follows ANSI SQL: 'single quotes' are strings and
+	// "double quotes" identifiers.
+	SQLGeneric SQLDialect = "generic"
+	// SQLPostgres also replaces $tag$dollar-quoted$tag$ strings and
+	// keeps $1-style placeholders.
+	SQLPostgres SQLDialect = "postgres"
+	// SQLMySQL treats "double quotes" as strings, `backticks` as
+	// identifiers, backslash as an escape inside strings and # as a
+	// comment.
+	SQLMySQL SQLDialect = "mysql"
+	// SQLSQLite is SQLGeneric with `backticks` and [brackets] as
+	// identifiers.
+	SQLSQLite SQLDialect = "sqlite"
+	// SQLMSSQL is SQLGeneric with [brackets] as identifiers.
+	SQLMSSQL SQLDialect = "mssql"
+)
+
+// SQL normalizes query by replacing its string and numeric literals with ?
+// and removing comments, so statements can be recorded without the values
+// they carry. Identifiers, keywords and placeholders ($1, :name, @p1, ?) are
+// kept:
+//
+//	SELECT * FROM users WHERE email = 'a@b.com' AND age > 30
+//	SELECT * FROM users WHERE email = ? AND age > ?
+//
+// Unterminated strings and comments are replaced up to the end of query.
+func SQL(query string, dialect SQLDialect) string {
+	var b strings.Builder
+	b.Grow(len(query))
+
+	n := len(query)
+	for i := 0; i < n; {
+		c := query[i]
+		switch {
+		case c == '\'':
+			i = skipQuoted(query, i, '\'', dialect == SQLMySQL)
+			b.WriteByte('?')
+		case c == '"' && dialect == SQLMySQL:
+			i = skipQuoted(query, i, '"', true)
+			b.WriteByte('?')
+		case c == '"' || c == '`' && dialect != SQLPostgres:
+			end := skipQuoted(query, i, c, false)
+			b.WriteString(query[i:end])
+			i = end
+		case c == '[' && (dialect == SQLMSSQL || dialect == SQLSQLite):
+			end := strings.IndexByte(query[i:], ']')
+			if end < 0 {
+				end = n - i - 1
+			}
+			b.WriteString(query[i : i+end+1])
+			i += end + 1
+		case c == '-' && i+1 < n && query[i+1] == '-',
+			c == '#' && dialect == SQLMySQL:
+			i = skipLine(query, i)
+		case c == '/' && i+1 < n && query[i+1] == '*':
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				i = n
+			} else {
+				i += end + 4
+			}
+		case c == '$' && dialect == SQLPostgres && i+1 < n && !isDigit(query[i+1]):
+			if end, ok := skipDollarQuoted(query, i); ok {
+				b.WriteByte('?')
+				i = end
+			} else {
+				b.WriteByte(c)
+				i++
+			}
+		case isDigit(c) || c == '.' && i+1 < n && isDigit(query[i+1]):
+			i = skipNumber(query, i)
+			b.WriteByte('?')
+		case isIdentStart(c):
+			start := i
+			for i < n && isIdentPart(query[i]) {
+				i++
+			}
+			// String prefixes: E'...', N'...', X'...', B'...'.
+			if i < n && query[i] == '\'' && i-start == 1 && strings.ContainsRune("eEnNxXbB", rune(query[start])) {
+				continue
+			}
+			b.WriteString(query[start:i])
+		default:
+			b.WriteByte(c)
+			i++
+			// Placeholders such as $1, :1 and @p1 keep their digits.
+			if c == '$' || c == ':' || c == '@' {
+				for i < n && isIdentPart(query[i]) {
+					b.WriteByte(query[i])
+					i++
+				}
+			}
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// skipQuoted returns the index after the quoted section starting at
+// query[start], where a doubled quote is an escaped one and, with
+// backslash, so is a backslash-escaped one.
+func skipQuoted(query string, start int, quote byte, backslash bool) int {
+	for i := start + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+		case quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+// skipDollarQuoted returns the index after the $tag$...$tag$ string starting
+// at query[start], reporting false when no valid opening tag starts there.
+func skipDollarQuoted(query string, start int) (int, bool) {
+	end := start + 1
+	for end < len(query) && query[end] != '$' {
+		if !isIdentPart(query[end]) {
+			return 0, false
+		}
+		end++
+	}
+	if end >= len(query) {
+		return 0, false
+	}
+	tag := query[start : end+1]
+	closing := strings.Index(query[end+1:], tag)
+	if closing < 0 {
+		return len(query), true
+	}
+	return end + 1 + closing + len(tag), true
+}
+
+func skipLine(query string, start int) int {
+	end := strings.IndexByte(query[start:], '\n')
+	if end < 0 {
+		return len(query)
+	}
+	return start + end
+}
+
+// skipNumber returns the index after the number starting at query[start]:
+// decimals, exponents and 0x hexadecimals.
+func skipNumber(query string, start int) int {
+	i := start
+	if i+1 < len(query) && query[i] == '0' && (query[i+1] == 'x' || query[i+1] == 'X') {
+		i += 2
+		for i < len(query) && isHexDigit(query[i]) {
+			i++
+		}
+		return i
+	}
+	for i < len(query) && (isDigit(query[i]) || query[i] == '.') {
+		i++
+	}
+	if i < len(query) && (query[i] == 'e' || query[i] == 'E') {
+		j := i + 1
+		if j < len(query) && (query[j] == '+' || query[j] == '-') {
+			j++
+		}
+		if j < len(query) && isDigit(query[j]) {
+			i = j
+			for i < len(query) && isDigit(query[i]) {
+				i++
+			}
+		}
+	}
+	return i
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c >= 0x80
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '$'
+}
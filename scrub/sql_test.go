@@ -0,0 +1,50 @@
+package scrub
+
+import "testing"
+
+func TestSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect SQLDialect
+		query   string
+		want    string
+	}{
+		{"strings and numbers", "",
+			"SELECT * FROM users WHERE email = 'a@b.com' AND age > 30 AND score < 4.5e2",
+			"SELECT * FROM users WHERE email = ? AND age > ? AND score < ?"},
+		{"escaped quote", SQLGeneric,
+			"UPDATE t SET note = 'it''s secret' WHERE id = 0x1F",
+			"UPDATE t SET note = ? WHERE id = ?"},
+		{"identifiers with digits kept", SQLGeneric,
+			`SELECT "col1", t2.c3 FROM table1 t2 WHERE t2.c3 IN (1, 2, 3)`,
+			`SELECT "col1", t2.c3 FROM table1 t2 WHERE t2.c3 IN (?, ?, ?)`},
+		{"comments removed", SQLGeneric,
+			"SELECT id -- token=abc\nFROM t /* user 42 */ WHERE x = 1",
+			"SELECT id \nFROM t  WHERE x = ?"},
+		{"string prefixes", SQLGeneric,
+			"INSERT INTO t VALUES (N'José', E'a\\nb', X'DEAD')",
+			"INSERT INTO t VALUES (?, ?, ?)"},
+		{"placeholders kept", SQLGeneric,
+			"SELECT * FROM t WHERE a = ? AND b = :name AND c = @p1 AND d = $2",
+			"SELECT * FROM t WHERE a = ? AND b = :name AND c = @p1 AND d = $2"},
+		{"postgres dollar quotes and casts", SQLPostgres,
+			"SELECT $$secret$$, $tag$it's$tag$::text, $1::int FROM t",
+			"SELECT ?, ?::text, $1::int FROM t"},
+		{"mysql double quotes and backslashes", SQLMySQL,
+			"SELECT `name` FROM t WHERE a = \"x\\\"y\" AND b = 'o\\'k' # trailing",
+			"SELECT `name` FROM t WHERE a = ? AND b = ?"},
+		{"mssql brackets", SQLMSSQL,
+			"SELECT [order id] FROM [dbo].[orders] WHERE total > 100",
+			"SELECT [order id] FROM [dbo].[orders] WHERE total > ?"},
+		{"unterminated string", SQLGeneric,
+			"SELECT 'never closed",
+			"SELECT ?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SQL(tt.query, tt.dialect); got != tt.want {
+				t.Errorf("SQL(%q) =\n%q\nwant\n%q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
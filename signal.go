@@ -0,0 +1,135 @@
+package otelagent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/RodolfoBonis/go-otel-agent/logger"
+)
+
+// shutdownSignals are the signals that end a process by default and that
+// RunUntilSignal and WithShutdownOnSignal flush telemetry on.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// RunUntilSignal blocks until the process receives SIGTERM or SIGINT, or ctx
+// is done, then flushes and shuts the agent down within
+// Performance.ShutdownTimeout. Call it at the end of main, after Init and
+// after starting servers in their own goroutines:
+//
+//	go srv.ListenAndServe()
+//	if err := agent.RunUntilSignal(ctx); err != nil {
+//		log.Printf("telemetry shutdown: %v", err)
+//	}
+func (a *Agent) RunUntilSignal(ctx context.Context) error {
+	a.mu.RLock()
+	initialized := a.initialized
+	a.mu.RUnlock()
+	if !initialized {
+		return ErrNotInitialized
+	}
+
+	sigCtx, stop := signal.NotifyContext(ctx, shutdownSignals...)
+	defer stop()
+	<-sigCtx.Done()
+
+	if ctx.Err() == nil {
+		a.logger.Info(ctx, "Shutdown signal received, flushing telemetry")
+	}
+	return a.flushAndShutdown(ctx)
+}
+
+// flushAndShutdown flushes every signal and shuts the agent down within
+// Performance.ShutdownTimeout, even when ctx is already cancelled.
+func (a *Agent) flushAndShutdown(ctx context.Context) error {
+	ctx, cancel := a.shutdownContext(context.WithoutCancel(ctx))
+	defer cancel()
+
+	// Shutdown flushes as well; flushing first gets the tail out before
+	// stopping the collectors and the worker pool eats into the deadline.
+	flushErr := a.ForceFlush(ctx)
+	return errors.Join(flushErr, a.Shutdown(ctx))
+}
+
+// signalHookMode is what the signal hook installed at Init does.
+type signalHookMode int
+
+const (
+	signalHookNone     signalHookMode = iota
+	signalHookShutdown                // WithShutdownOnSignal
+	signalHookFlush                   // WithFlushOnSignal
+)
+
+// setSignalHook selects mode, or turns the hook off when mode is the one
+// currently selected and enabled is false.
+func (a *Agent) setSignalHook(mode signalHookMode, enabled bool) {
+	switch {
+	case enabled:
+		a.signalHook = mode
+	case a.signalHook == mode:
+		a.signalHook = signalHookNone
+	}
+}
+
+// installSignalHook handles SIGTERM and SIGINT according to mode. With
+// signalHookShutdown it flushes and shuts the agent down on the first signal,
+// then raises it again so the process ends as it would have without the hook.
+// With signalHookFlush it flushes on every signal and leaves the rest to the
+// application's own handler, which receives the signal as well. Shutdown
+// removes the hook.
+func (a *Agent) installSignalHook(mode signalHookMode) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, shutdownSignals...)
+	done := make(chan struct{})
+	a.signalStop = func() {
+		signal.Stop(ch)
+		close(done)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-ch:
+				ctx := context.Background()
+				a.logger.Info(ctx, "Shutdown signal received, flushing telemetry", logger.Fields{"signal": sig.String()})
+				if mode == signalHookFlush {
+					if err := a.flushOnSignal(ctx); err != nil {
+						a.logger.Error(ctx, "Failed to flush telemetry on signal", logger.Fields{"error": err.Error()})
+					}
+					continue
+				}
+
+				signal.Stop(ch)
+				if err := a.flushAndShutdown(ctx); err != nil {
+					a.logger.Error(ctx, "Failed to flush telemetry on signal", logger.Fields{"error": err.Error()})
+				}
+				raise(sig)
+				return
+			}
+		}
+	}()
+}
+
+// flushOnSignal flushes every signal within Performance.ShutdownTimeout.
+func (a *Agent) flushOnSignal(ctx context.Context) error {
+	ctx, cancel := a.shutdownContext(ctx)
+	defer cancel()
+	return a.ForceFlush(ctx)
+}
+
+// raise delivers sig to the current process. Where that is not supported
+// (os.Interrupt on Windows) the process exits, as the signal would have made
+// it.
+func raise(sig os.Signal) {
+	p, err := os.FindProcess(os.Getpid())
+	if err == nil {
+		err = p.Signal(sig)
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,120 @@
+package otelagent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func newSignalTestAgent(t *testing.T, opts ...Option) *Agent {
+	t.Helper()
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", filepath.Join(t.TempDir(), "telemetry.json"))
+
+	agent := NewAgent(append([]Option{
+		WithServiceName("signal-test"),
+		WithStdoutExporter(),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+	}, opts...)...)
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	return agent
+}
+
+func TestRunUntilSignal_FlushesAndShutsDownWhenContextEnds(t *testing.T) {
+	if err := NewAgent().RunUntilSignal(context.Background()); !errors.Is(err, ErrNotInitialized) {
+		t.Fatalf("RunUntilSignal before Init = %v, want ErrNotInitialized", err)
+	}
+
+	agent := newSignalTestAgent(t)
+	_, span := agent.GetTracer("test").Start(context.Background(), "op")
+	span.End()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := agent.RunUntilSignal(ctx); err != nil {
+		t.Fatalf("RunUntilSignal: %v", err)
+	}
+	if agent.IsRunning() {
+		t.Error("agent still running after RunUntilSignal returned")
+	}
+	if got := agent.Diagnostics().Exports["traces"].TotalExported; got != 1 {
+		t.Errorf("TotalExported = %d, want the span ended before the signal", got)
+	}
+}
+
+func TestWithShutdownOnSignal_FlushesThenRaisesTheSignalAgain(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals cannot be sent to the current process on Windows")
+	}
+
+	// Stands in for the application's own handler and keeps the re-raised
+	// signal from ending the test binary
+	received := make(chan os.Signal, 2)
+	signal.Notify(received, syscall.SIGTERM)
+	defer signal.Stop(received)
+
+	agent := newSignalTestAgent(t, WithShutdownOnSignal(true))
+	_, span := agent.GetTracer("test").Start(context.Background(), "op")
+	span.End()
+
+	raise(syscall.SIGTERM)
+	for i := range 2 {
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d SIGTERMs, want the original and the re-raised one", i)
+		}
+	}
+
+	if agent.IsRunning() {
+		t.Error("agent still running after the signal")
+	}
+	if got := agent.Diagnostics().Exports["traces"].TotalExported; got != 1 {
+		t.Errorf("TotalExported = %d, want the span ended before the signal", got)
+	}
+}
+
+func TestWithFlushOnSignal_FlushesAndLeavesTheSignalToTheApplication(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals cannot be sent to the current process on Windows")
+	}
+
+	received := make(chan os.Signal, 2)
+	signal.Notify(received, syscall.SIGTERM)
+	defer signal.Stop(received)
+
+	agent := newSignalTestAgent(t, WithFlushOnSignal(true))
+	t.Cleanup(func() { _ = agent.Shutdown(context.Background()) })
+	_, span := agent.GetTracer("test").Start(context.Background(), "op")
+	span.End()
+
+	raise(syscall.SIGTERM)
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the application did not receive the signal")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for agent.Diagnostics().Exports["traces"].TotalExported != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("span ended before the signal was not flushed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case <-received:
+		t.Error("signal raised again, want it delivered to the application once")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if !agent.IsRunning() {
+		t.Error("agent shut down by the signal, want shutdown left to the application")
+	}
+}
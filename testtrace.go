@@ -0,0 +1,98 @@
+package otelagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestTraceAttribute marks every span, measurement and log record produced
+// by EmitTestTrace, so they can be found (or filtered out) in the backend.
+const TestTraceAttribute = "otel_agent.test"
+
+// TestTraceSpanName is the name of the root span produced by EmitTestTrace.
+const TestTraceSpanName = "otel-agent.test-trace"
+
+// TestTraceMetric counts the calls to EmitTestTrace.
+const TestTraceMetric = "otel_agent_test_traces_total"
+
+// EmitTestTrace sends a small, recognizable trace through the pipeline and
+// flushes it, so smoke tests and runbooks can check that telemetry reaches
+// the backend after a deploy or collector change:
+//
+//	otel-agent.test-trace                (server)
+//	├── otel-agent.test-trace.query      (client)
+//	└── otel-agent.test-trace.process    (internal)
+//	    └── otel-agent.test-trace.render (internal)
+//
+// Every span carries otel_agent.test=true. It also adds one to the
+// otel_agent_test_traces_total counter and emits an INFO log record inside
+// the root span, when those signals are enabled. Search the backend for the
+// returned trace ID to confirm all three arrived.
+//
+// The spans go through the configured sampler like any other trace; an
+// error is returned when the root span was not sampled.
+func (a *Agent) EmitTestTrace(ctx context.Context) (trace.TraceID, error) {
+	a.mu.RLock()
+	initialized := a.initialized
+	a.mu.RUnlock()
+	if !initialized {
+		return trace.TraceID{}, ErrNotInitialized
+	}
+	if a.tracerProvider == nil {
+		return trace.TraceID{}, errors.New("go-otel-agent: traces are disabled, no test trace to emit")
+	}
+
+	marker := attribute.Bool(TestTraceAttribute, true)
+	tracer := a.GetTracer(agentScopeName)
+
+	ctx, root := tracer.Start(ctx, TestTraceSpanName,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(marker),
+	)
+	traceID := root.SpanContext().TraceID()
+
+	_, query := tracer.Start(ctx, TestTraceSpanName+".query",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(marker, attribute.String("db.system.name", "otel-agent-test")),
+	)
+	query.End()
+
+	processCtx, process := tracer.Start(ctx, TestTraceSpanName+".process", trace.WithAttributes(marker))
+	_, render := tracer.Start(processCtx, TestTraceSpanName+".render", trace.WithAttributes(marker))
+	render.AddEvent("rendered", trace.WithAttributes(marker))
+	render.End()
+	process.End()
+
+	if counter, err := a.GetMeter(agentScopeName).Int64Counter(TestTraceMetric,
+		metric.WithDescription("Test traces emitted by EmitTestTrace"),
+	); err == nil {
+		counter.Add(ctx, 1, metric.WithAttributes(marker))
+	}
+
+	if a.loggerProvider != nil {
+		var record otellog.Record
+		record.SetTimestamp(time.Now())
+		record.SetSeverity(otellog.SeverityInfo)
+		record.SetSeverityText("INFO")
+		record.SetBody(otellog.StringValue("otel-agent test trace " + traceID.String()))
+		record.AddAttributes(otellog.Bool(TestTraceAttribute, true))
+		a.loggerProvider.Logger(agentScopeName).Emit(ctx, record)
+	}
+
+	root.End()
+
+	if err := a.ForceFlush(ctx); err != nil {
+		return traceID, fmt.Errorf("flush test trace: %w", err)
+	}
+	if !root.SpanContext().IsSampled() {
+		return traceID, fmt.Errorf("test trace %s was not sampled; check the trace sampling rate", traceID)
+	}
+	return traceID, nil
+}
@@ -0,0 +1,48 @@
+package otelagent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmitTestTrace(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "telemetry.json")
+	t.Setenv("OTEL_EXPORTER_STDOUT_PATH", out)
+	agent := NewAgent(
+		WithServiceName("test-trace"),
+		WithStdoutExporter(),
+		WithDisabledSignals(SignalMetrics, SignalLogs),
+	)
+	if _, err := agent.EmitTestTrace(context.Background()); !errors.Is(err, ErrNotInitialized) {
+		t.Fatalf("before Init: err = %v, want ErrNotInitialized", err)
+	}
+	if err := agent.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	defer func() { _ = agent.Shutdown(context.Background()) }()
+
+	traceID, err := agent.EmitTestTrace(context.Background())
+	if err != nil {
+		t.Fatalf("EmitTestTrace: %v", err)
+	}
+	if !traceID.IsValid() {
+		t.Fatal("expected a valid trace ID")
+	}
+	if got := agent.Diagnostics().Exports["traces"].TotalExported; got != 4 {
+		t.Errorf("exported %d spans, want 4", got)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{TestTraceSpanName, TestTraceSpanName + ".render", TestTraceAttribute, traceID.String()} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("exported telemetry does not contain %q", want)
+		}
+	}
+}
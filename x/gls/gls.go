@@ -0,0 +1,69 @@
+// Package gls is an opt-in bridge for legacy code that does not pass
+// context.Context through its call stack. A context (and therefore the active
+// span) is bound to the current goroutine at an instrumented boundary and
+// can be retrieved anywhere deeper in the same goroutine.
+//
+// Prefer passing ctx explicitly; this package is meant to ease migration of
+// old codebases. Every Bind must be paired with its restore function,
+// otherwise the binding outlives the request on pooled goroutines.
+//
+// The package is experimental and may change in a minor release.
+package gls
+
+import (
+	"context"
+	"sync"
+
+	"github.com/petermattis/goid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// bindings maps goroutine ID (int64) -> context.Context.
+var bindings sync.Map
+
+// Bind stores ctx as the current goroutine's context and returns a function
+// that restores the previous binding. Bindings nest:
+//
+//	restore := gls.Bind(ctx)
+//	defer restore()
+func Bind(ctx context.Context) (restore func()) {
+	id := goid.Get()
+	prev, hadPrev := bindings.Load(id)
+	bindings.Store(id, ctx)
+
+	return func() {
+		if hadPrev {
+			bindings.Store(id, prev)
+		} else {
+			bindings.Delete(id)
+		}
+	}
+}
+
+// Context returns the context bound to the current goroutine, or
+// context.Background() when none is bound.
+func Context() context.Context {
+	if ctx, ok := bindings.Load(goid.Get()); ok {
+		return ctx.(context.Context)
+	}
+	return context.Background()
+}
+
+// Span returns the span of the context bound to the current goroutine.
+// It is a non-recording span when nothing is bound.
+func Span() trace.Span {
+	return trace.SpanFromContext(Context())
+}
+
+// Do runs fn with ctx bound to the current goroutine.
+func Do(ctx context.Context, fn func()) {
+	restore := Bind(ctx)
+	defer restore()
+	fn()
+}
+
+// Go runs fn in a new goroutine with ctx bound to it. Bindings are not
+// inherited by goroutines started any other way.
+func Go(ctx context.Context, fn func()) {
+	go Do(ctx, fn)
+}
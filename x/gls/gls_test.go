@@ -0,0 +1,62 @@
+package gls
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSpan_UnboundIsNonRecording(t *testing.T) {
+	if Span().SpanContext().IsValid() {
+		t.Error("expected invalid span when nothing is bound")
+	}
+}
+
+func TestBind_NestsAndRestores(t *testing.T) {
+	tracer := sdktrace.NewTracerProvider().Tracer("test")
+	outerCtx, outer := tracer.Start(context.Background(), "outer")
+	innerCtx, inner := tracer.Start(outerCtx, "inner")
+
+	restoreOuter := Bind(outerCtx)
+	if Span().SpanContext().SpanID() != outer.SpanContext().SpanID() {
+		t.Error("expected outer span after first bind")
+	}
+
+	restoreInner := Bind(innerCtx)
+	if Span().SpanContext().SpanID() != inner.SpanContext().SpanID() {
+		t.Error("expected inner span after nested bind")
+	}
+
+	restoreInner()
+	if Span().SpanContext().SpanID() != outer.SpanContext().SpanID() {
+		t.Error("expected outer span after restoring inner bind")
+	}
+
+	restoreOuter()
+	if Span().SpanContext().IsValid() {
+		t.Error("expected no span after restoring all binds")
+	}
+}
+
+func TestGo_BindsOnlyInNewGoroutine(t *testing.T) {
+	tracer := sdktrace.NewTracerProvider().Tracer("test")
+	ctx, span := tracer.Start(context.Background(), "job")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var got bool
+	Go(ctx, func() {
+		defer wg.Done()
+		got = Span().SpanContext().SpanID() == span.SpanContext().SpanID()
+	})
+	wg.Wait()
+
+	if !got {
+		t.Error("expected span to be bound inside the goroutine")
+	}
+	if Span().SpanContext().IsValid() {
+		t.Error("binding leaked into the calling goroutine")
+	}
+}
@@ -0,0 +1,225 @@
+// Package resilience wraps circuit breakers so their behavior shows up in the
+// traces and metrics of the calls they protect: state transitions and
+// short-circuited requests become span events on the active span, and each
+// breaker reports its state as a gauge.
+//
+// The package is experimental and may change in a minor release.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	otelagent "github.com/RodolfoBonis/go-otel-agent"
+	"github.com/sony/gobreaker/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const scopeName = "github.com/RodolfoBonis/go-otel-agent/x/resilience"
+
+// Breaker state values reported by the circuit_breaker.state gauge.
+const (
+	StateClosed   = int64(gobreaker.StateClosed)
+	StateHalfOpen = int64(gobreaker.StateHalfOpen)
+	StateOpen     = int64(gobreaker.StateOpen)
+)
+
+// CircuitBreaker is an instrumented gobreaker.CircuitBreaker.
+type CircuitBreaker[T any] struct {
+	cb    *gobreaker.TwoStepCircuitBreaker[T]
+	name  string
+	agent *otelagent.Agent
+
+	// mu serializes the breaker's bookkeeping (not the protected calls), so
+	// the transitions OnStateChange appends to pending are the ones the
+	// current step caused
+	mu      sync.Mutex
+	pending []transition
+
+	initOnce     sync.Once
+	rejected     metric.Int64Counter
+	stateChanges metric.Int64Counter
+	registration metric.Registration
+}
+
+// transition is a breaker state change.
+type transition struct {
+	from, to gobreaker.State
+}
+
+// NewCircuitBreaker creates a breaker from gobreaker settings. A user
+// OnStateChange callback is preserved and called after the transition is
+// recorded. A nil or disabled agent yields a working breaker without telemetry.
+func NewCircuitBreaker[T any](agent *otelagent.Agent, st gobreaker.Settings) *CircuitBreaker[T] {
+	b := &CircuitBreaker[T]{name: st.Name}
+	if agent != nil && agent.IsEnabled() {
+		b.agent = agent
+	}
+
+	userHook := st.OnStateChange
+	st.OnStateChange = func(name string, from, to gobreaker.State) {
+		// Called by gobreaker within a step, with b.mu held.
+		b.pending = append(b.pending, transition{from: from, to: to})
+		if b.stateChanges != nil {
+			b.stateChanges.Add(context.Background(), 1, metric.WithAttributes(
+				attribute.String("circuit_breaker.name", name),
+				attribute.String("circuit_breaker.from", from.String()),
+				attribute.String("circuit_breaker.to", to.String()),
+			))
+		}
+		if userHook != nil {
+			userHook(name, from, to)
+		}
+	}
+
+	b.cb = gobreaker.NewTwoStepCircuitBreaker[T](st)
+	return b
+}
+
+// Name returns the breaker name.
+func (b *CircuitBreaker[T]) Name() string { return b.name }
+
+// State returns the current breaker state.
+func (b *CircuitBreaker[T]) State() gobreaker.State {
+	var state gobreaker.State
+	// Reading the state moves an expired open breaker to half-open; no
+	// request caused that transition.
+	b.step(func() { state = b.cb.State() })
+	return state
+}
+
+// Counts returns the breaker's internal request counts.
+func (b *CircuitBreaker[T]) Counts() gobreaker.Counts { return b.cb.Counts() }
+
+// Execute runs fn through the breaker. The active span in ctx receives the
+// breaker name and state, a "circuit_breaker.state_change" event for each
+// transition the call caused, and a "circuit_breaker.rejected" event when
+// the request is short-circuited. A panic in fn counts as a failure and is
+// propagated.
+func (b *CircuitBreaker[T]) Execute(ctx context.Context, fn func(context.Context) (T, error)) (result T, err error) {
+	b.lazyInit()
+	span := trace.SpanFromContext(ctx)
+
+	var done func(error)
+	transitions := b.step(func() { done, err = b.cb.Allow() })
+	if err == nil {
+		defer func() {
+			if e := recover(); e != nil {
+				b.step(func() { done(fmt.Errorf("%v", e)) })
+				panic(e)
+			}
+		}()
+		result, err = fn(ctx)
+		transitions = append(transitions, b.step(func() { done(err) })...)
+	}
+
+	if span.IsRecording() {
+		span.SetAttributes(
+			attribute.String("circuit_breaker.name", b.name),
+			attribute.String("circuit_breaker.state", b.State().String()),
+		)
+		for _, t := range transitions {
+			span.AddEvent("circuit_breaker.state_change", trace.WithAttributes(
+				attribute.String("circuit_breaker.name", b.name),
+				attribute.String("circuit_breaker.from", t.from.String()),
+				attribute.String("circuit_breaker.to", t.to.String()),
+			))
+		}
+	}
+
+	if IsRejected(err) {
+		reason := "open"
+		if errors.Is(err, gobreaker.ErrTooManyRequests) {
+			reason = "half_open_limit"
+		}
+		if b.rejected != nil {
+			b.rejected.Add(ctx, 1, metric.WithAttributes(
+				attribute.String("circuit_breaker.name", b.name),
+				attribute.String("reason", reason),
+			))
+		}
+		span.AddEvent("circuit_breaker.rejected", trace.WithAttributes(
+			attribute.String("circuit_breaker.name", b.name),
+			attribute.String("reason", reason),
+		))
+	}
+
+	return result, err
+}
+
+// step runs f, a call into the breaker, and returns the transitions it
+// caused.
+func (b *CircuitBreaker[T]) step(f func()) []transition {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	f()
+	transitions := b.pending
+	b.pending = nil
+	return transitions
+}
+
+// Close unregisters the circuit_breaker.state gauge, for breakers created
+// per tenant or per host that are dropped before the agent shuts down. The
+// breaker keeps protecting calls, without the gauge.
+func (b *CircuitBreaker[T]) Close() error {
+	// A breaker closed before its first instrumented call never registers.
+	b.initOnce.Do(func() {})
+	b.mu.Lock()
+	registration := b.registration
+	b.registration = nil
+	b.mu.Unlock()
+	if registration == nil {
+		return nil
+	}
+	return registration.Unregister()
+}
+
+// IsRejected reports whether err means the breaker short-circuited the
+// request without calling the protected function.
+func IsRejected(err error) bool {
+	return errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests)
+}
+
+// lazyInit creates the instruments from the agent's meter on the first call
+// after agent.Init() (FX lifecycle ordering); calls made before it are not
+// measured rather than pinned to a no-op meter.
+func (b *CircuitBreaker[T]) lazyInit() {
+	if b.agent == nil || !b.agent.IsRunning() {
+		return
+	}
+	b.initOnce.Do(func() {
+		meter := b.agent.GetMeter(scopeName)
+
+		b.rejected, _ = meter.Int64Counter(
+			"circuit_breaker.requests.rejected",
+			metric.WithDescription("Requests short-circuited by a circuit breaker"),
+		)
+		stateChanges, _ := meter.Int64Counter(
+			"circuit_breaker.state_changes",
+			metric.WithDescription("Circuit breaker state transitions"),
+		)
+
+		state, err := meter.Int64ObservableGauge(
+			"circuit_breaker.state",
+			metric.WithDescription("Circuit breaker state (0=closed, 1=half-open, 2=open)"),
+		)
+		var registration metric.Registration
+		if err == nil {
+			nameAttr := metric.WithAttributes(attribute.String("circuit_breaker.name", b.name))
+			registration, _ = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+				o.ObserveInt64(state, int64(b.State()), nameAttr)
+				return nil
+			}, state)
+		}
+
+		// OnStateChange reads stateChanges with b.mu held.
+		b.mu.Lock()
+		b.stateChanges = stateChanges
+		b.registration = registration
+		b.mu.Unlock()
+	})
+}
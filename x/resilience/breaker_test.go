@@ -0,0 +1,121 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sony/gobreaker/v2"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func tripAfterOne(counts gobreaker.Counts) bool { return counts.ConsecutiveFailures >= 1 }
+
+func eventNames(span sdktrace.ReadOnlySpan) []string {
+	var names []string
+	for _, e := range span.Events() {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+func contains(names []string, want string) bool {
+	for _, n := range names {
+		if n == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCircuitBreaker_NilAgentStillProtects(t *testing.T) {
+	var hookCalls int
+	b := NewCircuitBreaker[int](nil, gobreaker.Settings{
+		Name:          "payments",
+		ReadyToTrip:   tripAfterOne,
+		OnStateChange: func(string, gobreaker.State, gobreaker.State) { hookCalls++ },
+	})
+
+	_, err := b.Execute(context.Background(), func(context.Context) (int, error) { return 0, errors.New("down") })
+	if err == nil || IsRejected(err) {
+		t.Fatalf("expected underlying error, got %v", err)
+	}
+	if b.State() != gobreaker.StateOpen {
+		t.Errorf("state = %v, want open", b.State())
+	}
+	if hookCalls != 1 {
+		t.Errorf("user OnStateChange called %d times, want 1", hookCalls)
+	}
+
+	if _, err := b.Execute(context.Background(), func(context.Context) (int, error) { return 1, nil }); !IsRejected(err) {
+		t.Errorf("expected rejection while open, got %v", err)
+	}
+}
+
+func TestCircuitBreaker_RecordsSpanEvents(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	b := NewCircuitBreaker[string](nil, gobreaker.Settings{Name: "inventory", ReadyToTrip: tripAfterOne})
+
+	ctx, span := tracer.Start(context.Background(), "trip")
+	_, _ = b.Execute(ctx, func(context.Context) (string, error) { return "", errors.New("down") })
+	span.End()
+
+	ctx, span = tracer.Start(context.Background(), "rejected")
+	_, _ = b.Execute(ctx, func(context.Context) (string, error) { return "ok", nil })
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if names := eventNames(spans[0]); !contains(names, "circuit_breaker.state_change") {
+		t.Errorf("trip span events = %v, want state_change", names)
+	}
+	if names := eventNames(spans[1]); !contains(names, "circuit_breaker.rejected") {
+		t.Errorf("rejected span events = %v, want rejected", names)
+	}
+	for _, kv := range spans[1].Attributes() {
+		if kv.Key == "circuit_breaker.state" && kv.Value.AsString() != "open" {
+			t.Errorf("circuit_breaker.state = %q, want open", kv.Value.AsString())
+		}
+	}
+}
+
+func TestCircuitBreaker_StateChangeEventOnlyOnTheCallThatCausedIt(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	b := NewCircuitBreaker[string](nil, gobreaker.Settings{Name: "inventory", ReadyToTrip: tripAfterOne})
+
+	// The slow call is admitted while the breaker is closed and ends after
+	// the failing call has opened it.
+	admitted, release := make(chan struct{}), make(chan struct{})
+	slowDone := make(chan struct{})
+	go func() {
+		defer close(slowDone)
+		ctx, span := tracer.Start(context.Background(), "slow")
+		defer span.End()
+		_, _ = b.Execute(ctx, func(context.Context) (string, error) {
+			close(admitted)
+			<-release
+			return "ok", nil
+		})
+	}()
+	<-admitted
+
+	ctx, span := tracer.Start(context.Background(), "trip")
+	_, _ = b.Execute(ctx, func(context.Context) (string, error) { return "", errors.New("down") })
+	span.End()
+	close(release)
+	<-slowDone
+
+	for _, s := range recorder.Ended() {
+		got := contains(eventNames(s), "circuit_breaker.state_change")
+		if want := s.Name() == "trip"; got != want {
+			t.Errorf("%s span has state_change = %v, want %v", s.Name(), got, want)
+		}
+	}
+}